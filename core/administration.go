@@ -23,6 +23,7 @@ import (
 	"health/core/model"
 	"health/utils"
 	"log"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -44,6 +45,20 @@ func (app *Application) getCovid19Configs() ([]model.COVID19Config, error) {
 }
 
 func (app *Application) updateCovid19Config(config *model.COVID19Config) error {
+	names := make(map[string]bool, len(config.RosterAttributes))
+	for _, attribute := range config.RosterAttributes {
+		if len(attribute.Name) == 0 || strings.Contains(attribute.Name, ".") || strings.HasPrefix(attribute.Name, "$") {
+			return fmt.Errorf("invalid roster attribute name %s", attribute.Name)
+		}
+		if names[attribute.Name] || model.IsRosterField(attribute.Name) {
+			return fmt.Errorf("roster attribute %s is duplicated", attribute.Name)
+		}
+		names[attribute.Name] = true
+		if _, err := regexp.Compile(attribute.Pattern); err != nil {
+			return fmt.Errorf("invalid roster attribute %s pattern - %s", attribute.Name, err)
+		}
+	}
+
 	err := app.storage.SaveCovid19Config(config)
 	if err != nil {
 		return err
@@ -101,7 +116,10 @@ func (app *Application) createAppVersion(current model.User, group string, audit
 	//audit
 	userIdentifier, userInfo := current.GetLogData()
 	lData := []AuditDataEntry{{Key: "version", Value: version}}
-	defer app.audit.LogCreateEvent(userIdentifier, userInfo, group, "app-version", version, lData, audit)
+	err = app.audit.LogCreateEvent(userIdentifier, userInfo, group, "app-version", version, lData, audit)
+	if err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -124,7 +142,10 @@ func (app *Application) createNews(current model.User, group string, audit *stri
 	userIdentifier, userInfo := current.GetLogData()
 	lData := []AuditDataEntry{{Key: "date", Value: fmt.Sprint(date)}, {Key: "title", Value: title}, {Key: "description", Value: description},
 		{Key: "htmlContent", Value: htmlContent}, {Key: "link", Value: utils.GetString(link)}}
-	defer app.audit.LogCreateEvent(userIdentifier, userInfo, group, "news", news.ID, lData, audit)
+	err = app.audit.LogCreateEvent(userIdentifier, userInfo, group, "news", news.ID, lData, audit)
+	if err != nil {
+		return nil, err
+	}
 
 	return news, nil
 }
@@ -154,7 +175,10 @@ func (app *Application) updateNews(current model.User, group string, audit *stri
 	userIdentifier, userInfo := current.GetLogData()
 	lData := []AuditDataEntry{{Key: "date", Value: fmt.Sprint(date)}, {Key: "title", Value: title}, {Key: "description", Value: description},
 		{Key: "htmlContent", Value: htmlContent}, {Key: "link", Value: utils.GetString(link)}}
-	defer app.audit.LogUpdateEvent(userIdentifier, userInfo, group, "news", ID, lData, audit)
+	err = app.audit.LogUpdateEvent(userIdentifier, userInfo, group, "news", ID, lData, audit)
+	if err != nil {
+		return nil, err
+	}
 
 	return news, nil
 }
@@ -167,7 +191,10 @@ func (app *Application) deleteNews(current model.User, group string, ID string)
 
 	//audit
 	userIdentifier, userInfo := current.GetLogData()
-	defer app.audit.LogDeleteEvent(userIdentifier, userInfo, group, "news", ID)
+	err = app.audit.LogDeleteEvent(userIdentifier, userInfo, group, "news", ID)
+	if err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -188,7 +215,10 @@ func (app *Application) createResource(current model.User, group string, audit *
 	//audit
 	userIdentifier, userInfo := current.GetLogData()
 	lData := []AuditDataEntry{{Key: "title", Value: title}, {Key: "link", Value: link}, {Key: "displayOrder", Value: fmt.Sprint(displayOrder)}}
-	defer app.audit.LogCreateEvent(userIdentifier, userInfo, group, "resource", resource.ID, lData, audit)
+	err = app.audit.LogCreateEvent(userIdentifier, userInfo, group, "resource", resource.ID, lData, audit)
+	if err != nil {
+		return nil, err
+	}
 
 	return resource, nil
 }
@@ -216,7 +246,10 @@ func (app *Application) updateResource(current model.User, group string, audit *
 	//audit
 	userIdentifier, userInfo := current.GetLogData()
 	lData := []AuditDataEntry{{Key: "title", Value: title}, {Key: "link", Value: link}, {Key: "displayOrder", Value: fmt.Sprint(displayOrder)}}
-	defer app.audit.LogUpdateEvent(userIdentifier, userInfo, group, "resource", ID, lData, audit)
+	err = app.audit.LogUpdateEvent(userIdentifier, userInfo, group, "resource", ID, lData, audit)
+	if err != nil {
+		return nil, err
+	}
 
 	return resource, nil
 }
@@ -229,7 +262,10 @@ func (app *Application) deleteResource(current model.User, group string, ID stri
 
 	//audit
 	userIdentifier, userInfo := current.GetLogData()
-	defer app.audit.LogDeleteEvent(userIdentifier, userInfo, group, "resource", ID)
+	err = app.audit.LogDeleteEvent(userIdentifier, userInfo, group, "resource", ID)
+	if err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -330,7 +366,10 @@ func (app *Application) createFAQ(current model.User, group string, audit *strin
 	userIdentifier, userInfo := current.GetLogData()
 	lData := []AuditDataEntry{{Key: "section", Value: section}, {Key: "sectionDisplayOrder", Value: fmt.Sprint(sectionDisplayOrder)}, {Key: "title", Value: title},
 		{Key: "description", Value: description}, {Key: "questionDisplayOrder", Value: fmt.Sprint(questionDisplayOrder)}}
-	defer app.audit.LogCreateEvent(userIdentifier, userInfo, group, "faq-question", question.ID, lData, audit)
+	err = app.audit.LogCreateEvent(userIdentifier, userInfo, group, "faq-question", question.ID, lData, audit)
+	if err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -386,7 +425,10 @@ func (app *Application) updateFAQ(current model.User, group string, audit *strin
 	//audit
 	userIdentifier, userInfo := current.GetLogData()
 	lData := []AuditDataEntry{{Key: "title", Value: title}, {Key: "description", Value: description}, {Key: "displayOrder", Value: fmt.Sprint(displayOrder)}}
-	defer app.audit.LogUpdateEvent(userIdentifier, userInfo, group, "faq-question", ID, lData, audit)
+	err = app.audit.LogUpdateEvent(userIdentifier, userInfo, group, "faq-question", ID, lData, audit)
+	if err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -433,7 +475,10 @@ func (app *Application) deleteFAQ(current model.User, group string, ID string) e
 
 	//audit
 	userIdentifier, userInfo := current.GetLogData()
-	defer app.audit.LogDeleteEvent(userIdentifier, userInfo, group, "faq-question", ID)
+	err = app.audit.LogDeleteEvent(userIdentifier, userInfo, group, "faq-question", ID)
+	if err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -446,7 +491,10 @@ func (app *Application) deleteFAQSection(current model.User, group string, ID st
 
 	//audit
 	userIdentifier, userInfo := current.GetLogData()
-	defer app.audit.LogDeleteEvent(userIdentifier, userInfo, group, "faq-section", ID)
+	err = app.audit.LogDeleteEvent(userIdentifier, userInfo, group, "faq-section", ID)
+	if err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -489,7 +537,10 @@ func (app *Application) updateFAQSection(current model.User, group string, audit
 	//audit
 	userIdentifier, userInfo := current.GetLogData()
 	lData := []AuditDataEntry{{Key: "title", Value: title}, {Key: "displayOrder", Value: fmt.Sprint(displayOrder)}}
-	defer app.audit.LogCreateEvent(userIdentifier, userInfo, group, "faq-section", ID, lData, audit)
+	err = app.audit.LogCreateEvent(userIdentifier, userInfo, group, "faq-section", ID, lData, audit)
+	if err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -502,7 +553,10 @@ func (app *Application) createProvider(current model.User, group string, audit *
 
 	userIdentifier, userInfo := current.GetLogData()
 	lData := []AuditDataEntry{{Key: "providerName", Value: providerName}, {Key: "manualTest", Value: fmt.Sprint(manualTest)}, {Key: "availableMechanisms", Value: fmt.Sprint(availableMechanisms)}}
-	defer app.audit.LogCreateEvent(userIdentifier, userInfo, group, "provider", provider.ID, lData, audit)
+	err = app.audit.LogCreateEvent(userIdentifier, userInfo, group, "provider", provider.ID, lData, audit)
+	if err != nil {
+		return nil, err
+	}
 
 	return provider, nil
 }
@@ -530,7 +584,10 @@ func (app *Application) updateProvider(current model.User, group string, audit *
 	//audit
 	userIdentifier, userInfo := current.GetLogData()
 	lData := []AuditDataEntry{{Key: "providerName", Value: providerName}, {Key: "manualTest", Value: fmt.Sprint(manualTest)}, {Key: "availableMechanisms", Value: fmt.Sprint(availableMechanisms)}}
-	defer app.audit.LogUpdateEvent(userIdentifier, userInfo, group, "provider", ID, lData, audit)
+	err = app.audit.LogUpdateEvent(userIdentifier, userInfo, group, "provider", ID, lData, audit)
+	if err != nil {
+		return nil, err
+	}
 
 	return provider, nil
 }
@@ -540,15 +597,28 @@ func (app *Application) deleteProvider(current model.User, group string, ID stri
 	if err != nil {
 		return err
 	}
+	err = app.storage.DeleteProviderCredentials(ID)
+	if err != nil {
+		return err
+	}
 
 	//audit
 	userIdentifier, userInfo := current.GetLogData()
-	defer app.audit.LogDeleteEvent(userIdentifier, userInfo, group, "provider", ID)
+	err = app.audit.LogDeleteEvent(userIdentifier, userInfo, group, "provider", ID)
+	if err != nil {
+		return err
+	}
 
 	return nil
 }
 
 func (app *Application) createCounty(current model.User, group string, audit *string, name string, stateProvince string, country string) (*model.County, error) {
+	//the new county is out of any counties scope
+	all, _ := app.countiesScope(group, model.PermissionCountiesWrite)
+	if !all {
+		return nil, ErrCountyForbidden
+	}
+
 	county, err := app.storage.CreateCounty(name, stateProvince, country)
 	if err != nil {
 		return nil, err
@@ -557,12 +627,20 @@ func (app *Application) createCounty(current model.User, group string, audit *st
 	//audit
 	userIdentifier, userInfo := current.GetLogData()
 	lData := []AuditDataEntry{{Key: "name", Value: name}, {Key: "stateProvince", Value: stateProvince}, {Key: "country", Value: country}}
-	defer app.audit.LogCreateEvent(userIdentifier, userInfo, group, "county", county.ID, lData, audit)
+	err = app.audit.LogCreateEvent(userIdentifier, userInfo, group, "county", county.ID, lData, audit)
+	if err != nil {
+		return nil, err
+	}
 
 	return county, nil
 }
 
 func (app *Application) updateCounty(current model.User, group string, audit *string, ID string, name string, stateProvince string, country string) (*model.County, error) {
+	err := app.checkCountyScope(group, model.PermissionCountiesWrite, ID)
+	if err != nil {
+		return nil, err
+	}
+
 	county, err := app.storage.FindCounty(ID)
 	if err != nil {
 		return nil, err
@@ -585,25 +663,41 @@ func (app *Application) updateCounty(current model.User, group string, audit *st
 	//audit
 	userIdentifier, userInfo := current.GetLogData()
 	lData := []AuditDataEntry{{Key: "name", Value: name}, {Key: "stateProvince", Value: stateProvince}, {Key: "country", Value: country}}
-	defer app.audit.LogUpdateEvent(userIdentifier, userInfo, group, "county", county.ID, lData, audit)
+	err = app.audit.LogUpdateEvent(userIdentifier, userInfo, group, "county", county.ID, lData, audit)
+	if err != nil {
+		return nil, err
+	}
 
 	return county, nil
 }
 
 func (app *Application) deleteCounty(current model.User, group string, ID string) error {
-	err := app.storage.DeleteCounty(ID)
+	err := app.checkCountyScope(group, model.PermissionCountiesWrite, ID)
+	if err != nil {
+		return err
+	}
+
+	err = app.storage.DeleteCounty(ID)
 	if err != nil {
 		return err
 	}
 
 	//audit
 	userIdentifier, userInfo := current.GetLogData()
-	defer app.audit.LogDeleteEvent(userIdentifier, userInfo, group, "county", ID)
+	err = app.audit.LogDeleteEvent(userIdentifier, userInfo, group, "county", ID)
+	if err != nil {
+		return err
+	}
 
 	return nil
 }
 
 func (app *Application) createGuideline(current model.User, group string, audit *string, countyID string, name string, description string, items []model.GuidelineItem) (*model.Guideline, error) {
+	err := app.checkCountyScope(group, model.PermissionGuidelinesWrite, countyID)
+	if err != nil {
+		return nil, err
+	}
+
 	//1. find if we have a county for the provided ID
 	county, err := app.storage.FindCounty(countyID)
 	if err != nil {
@@ -622,7 +716,10 @@ func (app *Application) createGuideline(current model.User, group string, audit
 	//audit
 	userIdentifier, userInfo := current.GetLogData()
 	lData := []AuditDataEntry{{Key: "name", Value: name}, {Key: "description", Value: description}, {Key: "items", Value: fmt.Sprint(items)}}
-	defer app.audit.LogCreateEvent(userIdentifier, userInfo, group, "guideline", guideline.ID, lData, audit)
+	err = app.audit.LogCreateEvent(userIdentifier, userInfo, group, "guideline", guideline.ID, lData, audit)
+	if err != nil {
+		return nil, err
+	}
 
 	return guideline, nil
 }
@@ -635,6 +732,10 @@ func (app *Application) updateGuideline(current model.User, group string, audit
 	if guideline == nil {
 		return nil, errors.New("guideline is nil for id " + ID)
 	}
+	err = app.checkCountyScope(group, model.PermissionGuidelinesWrite, guideline.County.ID)
+	if err != nil {
+		return nil, err
+	}
 
 	//add the new values
 	guideline.Name = name
@@ -650,25 +751,48 @@ func (app *Application) updateGuideline(current model.User, group string, audit
 	//audit
 	userIdentifier, userInfo := current.GetLogData()
 	lData := []AuditDataEntry{{Key: "name", Value: name}, {Key: "description", Value: description}, {Key: "items", Value: fmt.Sprint(items)}}
-	defer app.audit.LogUpdateEvent(userIdentifier, userInfo, group, "guideline", ID, lData, audit)
+	err = app.audit.LogUpdateEvent(userIdentifier, userInfo, group, "guideline", ID, lData, audit)
+	if err != nil {
+		return nil, err
+	}
 
 	return guideline, nil
 }
 
 func (app *Application) deleteGuideline(current model.User, group string, ID string) error {
-	err := app.storage.DeleteGuideline(ID)
+	guideline, err := app.storage.FindGuideline(ID)
+	if err != nil {
+		return err
+	}
+	if guideline == nil {
+		return errors.New("guideline is nil for id " + ID)
+	}
+	err = app.checkCountyScope(group, model.PermissionGuidelinesWrite, guideline.County.ID)
+	if err != nil {
+		return err
+	}
+
+	err = app.storage.DeleteGuideline(ID)
 	if err != nil {
 		return err
 	}
 
 	//audit
 	userIdentifier, userInfo := current.GetLogData()
-	defer app.audit.LogDeleteEvent(userIdentifier, userInfo, group, "guideline", ID)
+	err = app.audit.LogDeleteEvent(userIdentifier, userInfo, group, "guideline", ID)
+	if err != nil {
+		return err
+	}
 
 	return nil
 }
 
-func (app *Application) getGuidelinesByCountyID(countyID string) ([]*model.Guideline, error) {
+func (app *Application) getGuidelinesByCountyID(group string, countyID string) ([]*model.Guideline, error) {
+	err := app.checkCountyScope(group, model.PermissionGuidelinesRead, countyID)
+	if err != nil {
+		return nil, err
+	}
+
 	//1. first check if we have a county for the provided id
 	county, err := app.storage.FindCounty(countyID)
 	if err != nil {
@@ -687,6 +811,11 @@ func (app *Application) getGuidelinesByCountyID(countyID string) ([]*model.Guide
 }
 
 func (app *Application) createCountyStatus(current model.User, group string, audit *string, countyID string, name string, description string) (*model.CountyStatus, error) {
+	err := app.checkCountyScope(group, model.PermissionCountyStatusesWrite, countyID)
+	if err != nil {
+		return nil, err
+	}
+
 	//1. find if we have a county for the provided ID
 	county, err := app.storage.FindCounty(countyID)
 	if err != nil {
@@ -705,7 +834,10 @@ func (app *Application) createCountyStatus(current model.User, group string, aud
 	//audit
 	userIdentifier, userInfo := current.GetLogData()
 	lData := []AuditDataEntry{{Key: "countyID", Value: countyID}, {Key: "name", Value: name}, {Key: "description", Value: description}}
-	defer app.audit.LogCreateEvent(userIdentifier, userInfo, group, "county-status", countyStatus.ID, lData, audit)
+	err = app.audit.LogCreateEvent(userIdentifier, userInfo, group, "county-status", countyStatus.ID, lData, audit)
+	if err != nil {
+		return nil, err
+	}
 
 	return countyStatus, nil
 }
@@ -718,6 +850,10 @@ func (app *Application) updateCountyStatus(current model.User, group string, aud
 	if countyStatus == nil {
 		return nil, errors.New("county status is nil for id " + ID)
 	}
+	err = app.checkCountyScope(group, model.PermissionCountyStatusesWrite, countyStatus.County.ID)
+	if err != nil {
+		return nil, err
+	}
 
 	//add the new values
 	countyStatus.Name = name
@@ -732,25 +868,48 @@ func (app *Application) updateCountyStatus(current model.User, group string, aud
 	//audit
 	userIdentifier, userInfo := current.GetLogData()
 	lData := []AuditDataEntry{{Key: "name", Value: name}, {Key: "description", Value: description}}
-	defer app.audit.LogUpdateEvent(userIdentifier, userInfo, group, "county-status", ID, lData, audit)
+	err = app.audit.LogUpdateEvent(userIdentifier, userInfo, group, "county-status", ID, lData, audit)
+	if err != nil {
+		return nil, err
+	}
 
 	return countyStatus, nil
 }
 
 func (app *Application) deleteCountyStatus(current model.User, group string, ID string) error {
-	err := app.storage.DeleteCountyStatus(ID)
+	countyStatus, err := app.storage.FindCountyStatus(ID)
+	if err != nil {
+		return err
+	}
+	if countyStatus == nil {
+		return errors.New("county status is nil for id " + ID)
+	}
+	err = app.checkCountyScope(group, model.PermissionCountyStatusesWrite, countyStatus.County.ID)
+	if err != nil {
+		return err
+	}
+
+	err = app.storage.DeleteCountyStatus(ID)
 	if err != nil {
 		return err
 	}
 
 	//audit
 	userIdentifier, userInfo := current.GetLogData()
-	defer app.audit.LogDeleteEvent(userIdentifier, userInfo, group, "county-status", ID)
+	err = app.audit.LogDeleteEvent(userIdentifier, userInfo, group, "county-status", ID)
+	if err != nil {
+		return err
+	}
 
 	return nil
 }
 
-func (app *Application) getCountyStatusByCountyID(countyID string) ([]*model.CountyStatus, error) {
+func (app *Application) getCountyStatusByCountyID(group string, countyID string) ([]*model.CountyStatus, error) {
+	err := app.checkCountyScope(group, model.PermissionCountyStatusesRead, countyID)
+	if err != nil {
+		return nil, err
+	}
+
 	//1. first check if we have a county for the provided id
 	county, err := app.storage.FindCounty(countyID)
 	if err != nil {
@@ -788,7 +947,10 @@ func (app *Application) createTestType(current model.User, group string, audit *
 
 	userIdentifier, userInfo := current.GetLogData()
 	lData := []AuditDataEntry{{Key: "name", Value: name}, {Key: "priority", Value: fmt.Sprint(utils.GetInt(priority))}}
-	defer app.audit.LogCreateEvent(userIdentifier, userInfo, group, "test-type", testType.ID, lData, audit)
+	err = app.audit.LogCreateEvent(userIdentifier, userInfo, group, "test-type", testType.ID, lData, audit)
+	if err != nil {
+		return nil, err
+	}
 
 	return testType, nil
 }
@@ -815,7 +977,10 @@ func (app *Application) updateTestType(current model.User, group string, audit *
 	//audit
 	userIdentifier, userInfo := current.GetLogData()
 	lData := []AuditDataEntry{{Key: "name", Value: name}, {Key: "priority", Value: fmt.Sprint(utils.GetInt(priority))}}
-	defer app.audit.LogUpdateEvent(userIdentifier, userInfo, group, "test-type", ID, lData, audit)
+	err = app.audit.LogUpdateEvent(userIdentifier, userInfo, group, "test-type", ID, lData, audit)
+	if err != nil {
+		return nil, err
+	}
 
 	return testType, nil
 }
@@ -828,7 +993,10 @@ func (app *Application) deleteTestType(current model.User, group string, ID stri
 
 	//audit
 	userIdentifier, userInfo := current.GetLogData()
-	defer app.audit.LogDeleteEvent(userIdentifier, userInfo, group, "test-type", ID)
+	err = app.audit.LogDeleteEvent(userIdentifier, userInfo, group, "test-type", ID)
+	if err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -853,7 +1021,10 @@ func (app *Application) createTestTypeResult(current model.User, group string, a
 	userIdentifier, userInfo := current.GetLogData()
 	lData := []AuditDataEntry{{Key: "testTypeID", Value: testTypeID}, {Key: "name", Value: name}, {Key: "nextStep", Value: nextStep},
 		{Key: "nextStepOffset", Value: fmt.Sprint(utils.GetInt(nextStepOffset))}, {Key: "resultExpiresOffset", Value: fmt.Sprint(utils.GetInt(resultExpiresOffset))}}
-	defer app.audit.LogCreateEvent(userIdentifier, userInfo, group, "test-type-result", testTypeResult.ID, lData, audit)
+	err = app.audit.LogCreateEvent(userIdentifier, userInfo, group, "test-type-result", testTypeResult.ID, lData, audit)
+	if err != nil {
+		return nil, err
+	}
 
 	return testTypeResult, nil
 }
@@ -883,7 +1054,10 @@ func (app *Application) updateTestTypeResult(current model.User, group string, a
 	userIdentifier, userInfo := current.GetLogData()
 	lData := []AuditDataEntry{{Key: "name", Value: name}, {Key: "nextStep", Value: nextStep},
 		{Key: "nextStepOffset", Value: fmt.Sprint(utils.GetInt(nextStepOffset))}, {Key: "resultExpiresOffset", Value: fmt.Sprint(utils.GetInt(resultExpiresOffset))}}
-	defer app.audit.LogUpdateEvent(userIdentifier, userInfo, group, "test-type-result", ID, lData, audit)
+	err = app.audit.LogUpdateEvent(userIdentifier, userInfo, group, "test-type-result", ID, lData, audit)
+	if err != nil {
+		return nil, err
+	}
 
 	return testTypeResult, nil
 }
@@ -896,7 +1070,10 @@ func (app *Application) deleteTestTypeResult(current model.User, group string, I
 
 	//audit
 	userIdentifier, userInfo := current.GetLogData()
-	defer app.audit.LogDeleteEvent(userIdentifier, userInfo, group, "test-type-result", ID)
+	err = app.audit.LogDeleteEvent(userIdentifier, userInfo, group, "test-type-result", ID)
+	if err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -919,15 +1096,31 @@ func (app *Application) getTestTypeResultsByTestTypeID(testTypeID string) ([]*mo
 	return testTypeResults, nil
 }
 
-func (app *Application) getRules() ([]*model.Rule, error) {
+func (app *Application) getRules(group string) ([]*model.Rule, error) {
 	rules, err := app.storage.ReadAllRules()
 	if err != nil {
 		return nil, err
 	}
-	return rules, nil
+
+	all, counties := app.countiesScope(group, model.PermissionRulesRead)
+	if all {
+		return rules, nil
+	}
+	var result []*model.Rule
+	for _, rule := range rules {
+		if utils.Contains(counties, rule.County.ID) {
+			result = append(result, rule)
+		}
+	}
+	return result, nil
 }
 
-func (app *Application) getCRules(countyID string, appVersion string) (*model.CRules, error) {
+func (app *Application) getCRules(group string, countyID string, appVersion string) (*model.CRules, error) {
+	err := app.checkCountyScope(group, model.PermissionCRulesRead, countyID)
+	if err != nil {
+		return nil, err
+	}
+
 	supported, v := app.isVersionSupported(appVersion)
 	if !supported {
 		return nil, errors.New("app version is not supported")
@@ -941,6 +1134,11 @@ func (app *Application) getCRules(countyID string, appVersion string) (*model.CR
 }
 
 func (app *Application) createOrUpdateCRules(current model.User, group string, audit *string, countyID string, appVersion string, data string) error {
+	err := app.checkCountyScope(group, model.PermissionCRulesWrite, countyID)
+	if err != nil {
+		return err
+	}
+
 	supported, v := app.isVersionSupported(appVersion)
 	if !supported {
 		return errors.New("app version is not supported")
@@ -956,10 +1154,16 @@ func (app *Application) createOrUpdateCRules(current model.User, group string, a
 	lData := []AuditDataEntry{{Key: "countyID", Value: countyID}, {Key: "appVersion", Value: appVersion}, {Key: "data", Value: data}}
 	if *create {
 		//create
-		defer app.audit.LogCreateEvent(userIdentifier, userInfo, group, "crules", "", lData, audit)
+		err = app.audit.LogCreateEvent(userIdentifier, userInfo, group, "crules", "", lData, audit)
+		if err != nil {
+			return err
+		}
 	} else {
 		//update
-		defer app.audit.LogUpdateEvent(userIdentifier, userInfo, group, "crules", "", lData, audit)
+		err = app.audit.LogUpdateEvent(userIdentifier, userInfo, group, "crules", "", lData, audit)
+		if err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -994,21 +1198,23 @@ func (app *Application) createOrUpdateSymptoms(current model.User, group string,
 	lData := []AuditDataEntry{{Key: "appVersion", Value: appVersion}, {Key: "items", Value: items}}
 	if *create {
 		//create
-		defer app.audit.LogCreateEvent(userIdentifier, userInfo, group, "symptoms", "", lData, audit)
+		err = app.audit.LogCreateEvent(userIdentifier, userInfo, group, "symptoms", "", lData, audit)
+		if err != nil {
+			return err
+		}
 	} else {
 		//update
-		defer app.audit.LogUpdateEvent(userIdentifier, userInfo, group, "symptoms", "", lData, audit)
+		err = app.audit.LogUpdateEvent(userIdentifier, userInfo, group, "symptoms", "", lData, audit)
+		if err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
-func (app *Application) getUINOverrides(uin *string, sort *string) ([]*model.UINOverride, error) {
-	uinOverrides, err := app.storage.FindUINOverrides(uin, sort)
-	if err != nil {
-		return nil, err
-	}
-	return uinOverrides, nil
+func (app *Application) queryUINOverrides(q *utils.Query) ([]*model.UINOverride, string, error) {
+	return app.storage.QueryUINOverrides(q)
 }
 
 func (app *Application) createUINOverride(current model.User, group string, audit *string, uin string, exempt *bool, interval *int, category *string, activation *time.Time, expiration *time.Time) (*model.UINOverride, error) {
@@ -1021,7 +1227,10 @@ func (app *Application) createUINOverride(current model.User, group string, audi
 	userIdentifier, userInfo := current.GetLogData()
 	lData := []AuditDataEntry{{Key: "uin", Value: uin}, {Key: "interval", Value: fmt.Sprint(interval)}, {Key: "exempt", Value: utils.GetBoolString(exempt)},
 		{Key: "category", Value: utils.GetString(category)}, {Key: "expiration", Value: utils.GetTime(expiration)}}
-	defer app.audit.LogCreateEvent(userIdentifier, userInfo, group, "uin-override", uin, lData, audit)
+	err = app.audit.LogCreateEvent(userIdentifier, userInfo, group, "uin-override", uin, lData, audit)
+	if err != nil {
+		return nil, err
+	}
 
 	return uinOverride, nil
 }
@@ -1036,7 +1245,10 @@ func (app *Application) updateUINOverride(current model.User, group string, audi
 	userIdentifier, userInfo := current.GetLogData()
 	lData := []AuditDataEntry{{Key: "uin", Value: uin}, {Key: "interval", Value: fmt.Sprint(interval)}, {Key: "exempt", Value: utils.GetBoolString(exempt)},
 		{Key: "category", Value: utils.GetString(category)}, {Key: "expiration", Value: utils.GetTime(expiration)}}
-	defer app.audit.LogUpdateEvent(userIdentifier, userInfo, group, "uin-override", uin, lData, audit)
+	err = app.audit.LogUpdateEvent(userIdentifier, userInfo, group, "uin-override", uin, lData, audit)
+	if err != nil {
+		return nil, err
+	}
 
 	return result, nil
 }
@@ -1049,7 +1261,10 @@ func (app *Application) deleteUINOverride(current model.User, group string, uin
 
 	//audit
 	userIdentifier, userInfo := current.GetLogData()
-	defer app.audit.LogDeleteEvent(userIdentifier, userInfo, group, "uin-override", uin)
+	err = app.audit.LogDeleteEvent(userIdentifier, userInfo, group, "uin-override", uin)
+	if err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -1061,6 +1276,10 @@ func (app *Application) createRule(current model.User, group string, audit *stri
 
 	//First validate
 	//1. Check if we have a county with the provided ID
+	err := app.checkCountyScope(group, model.PermissionRulesWrite, countyID)
+	if err != nil {
+		return nil, err
+	}
 	county, err := app.storage.FindCounty(countyID)
 	if err != nil {
 		return nil, err
@@ -1106,7 +1325,10 @@ func (app *Application) createRule(current model.User, group string, audit *stri
 	userIdentifier, userInfo := current.GetLogData()
 	lData := []AuditDataEntry{{Key: "countyID", Value: countyID}, {Key: "testTypeID", Value: testTypeID},
 		{Key: "priority", Value: fmt.Sprint(utils.GetInt(priority))}, {Key: "resultsStatuses", Value: fmt.Sprint(resultsStatuses)}}
-	defer app.audit.LogCreateEvent(userIdentifier, userInfo, group, "rule", rule.ID, lData, audit)
+	err = app.audit.LogCreateEvent(userIdentifier, userInfo, group, "rule", rule.ID, lData, audit)
+	if err != nil {
+		return nil, err
+	}
 
 	return rule, nil
 }
@@ -1120,6 +1342,10 @@ func (app *Application) updateRule(current model.User, group string, audit *stri
 	if rule == nil {
 		return nil, errors.New("rule is nil for id " + ID)
 	}
+	err = app.checkCountyScope(group, model.PermissionRulesWrite, rule.County.ID)
+	if err != nil {
+		return nil, err
+	}
 
 	//2. Check if the rule data is valid
 	valid, err := app.isRuleDataValid(rule.County.ID, rule.TestType.ID, resultsStatuses)
@@ -1143,7 +1369,10 @@ func (app *Application) updateRule(current model.User, group string, audit *stri
 	//audit
 	userIdentifier, userInfo := current.GetLogData()
 	lData := []AuditDataEntry{{Key: "priority", Value: fmt.Sprint(utils.GetInt(priority))}, {Key: "resultsStatuses", Value: fmt.Sprint(resultsStatuses)}}
-	defer app.audit.LogUpdateEvent(userIdentifier, userInfo, group, "rule", ID, lData, audit)
+	err = app.audit.LogUpdateEvent(userIdentifier, userInfo, group, "rule", ID, lData, audit)
+	if err != nil {
+		return nil, err
+	}
 
 	return rule, nil
 }
@@ -1234,31 +1463,57 @@ func (app *Application) containsTestTypeResult(ID string, list []model.TestTypeR
 }
 
 func (app *Application) deleteRule(current model.User, group string, ID string) error {
-	err := app.storage.DeleteRule(ID)
+	rule, err := app.storage.FindRule(ID)
+	if err != nil {
+		return err
+	}
+	if rule == nil {
+		return errors.New("rule is nil for id " + ID)
+	}
+	err = app.checkCountyScope(group, model.PermissionRulesWrite, rule.County.ID)
+	if err != nil {
+		return err
+	}
+
+	err = app.storage.DeleteRule(ID)
 	if err != nil {
 		return err
 	}
 
 	//audit
 	userIdentifier, userInfo := current.GetLogData()
-	defer app.audit.LogDeleteEvent(userIdentifier, userInfo, group, "rule", ID)
+	err = app.audit.LogDeleteEvent(userIdentifier, userInfo, group, "rule", ID)
+	if err != nil {
+		return err
+	}
 
 	return nil
 }
 
-func (app *Application) getLocations() ([]*model.Location, error) {
-	locations, err := app.storage.ReadAllLocations()
-	if err != nil {
-		return nil, err
+func (app *Application) queryLocations(group string, q *utils.Query) ([]*model.Location, string, error) {
+	all, counties := app.countiesScope(group, model.PermissionLocationsRead)
+	if !all {
+		if q == nil {
+			q = &utils.Query{}
+		}
+		values := make([]interface{}, len(counties))
+		for i, county := range counties {
+			values[i] = county
+		}
+		q.AddCondition("county_id", utils.QueryOperatorIn, values...)
 	}
-	return locations, nil
+	return app.storage.QueryLocations(q)
 }
 
 func (app *Application) createLocation(current model.User, group string, audit *string, providerID string, countyID string, name string, address1 string, address2 string, city string,
 	state string, zip string, country string, latitude float64, longitude float64, contact string,
 	daysOfOperation []model.OperationDay, url string, notes string, waitTimeColor *string, availableTests []string) (*model.Location, error) {
 	//1. check if the location data is valid
-	err := app.isLocationDataValid(providerID, countyID, availableTests)
+	err := app.checkCountyScope(group, model.PermissionLocationsWrite, countyID)
+	if err != nil {
+		return nil, err
+	}
+	err = app.isLocationDataValid(providerID, countyID, availableTests)
 	if err != nil {
 		return nil, err
 	}
@@ -1277,7 +1532,10 @@ func (app *Application) createLocation(current model.User, group string, audit *
 		{Key: "latitude", Value: fmt.Sprint(latitude)}, {Key: "longitude", Value: fmt.Sprint(longitude)}, {Key: "contact", Value: contact},
 		{Key: "daysOfOperation", Value: fmt.Sprint(daysOfOperation)}, {Key: "url", Value: url}, {Key: "notes", Value: notes}, {Key: "waitTimeColor", Value: utils.GetString(waitTimeColor)},
 		{Key: "availableTests", Value: fmt.Sprint(availableTests)}}
-	defer app.audit.LogCreateEvent(userIdentifier, userInfo, group, "location", location.ID, lData, audit)
+	err = app.audit.LogCreateEvent(userIdentifier, userInfo, group, "location", location.ID, lData, audit)
+	if err != nil {
+		return nil, err
+	}
 
 	return location, nil
 }
@@ -1294,6 +1552,10 @@ func (app *Application) updateLocation(current model.User, group string, audit *
 	if location == nil {
 		return nil, errors.New("location is nil for id " + ID)
 	}
+	err = app.checkCountyScope(group, model.PermissionLocationsWrite, location.County.ID)
+	if err != nil {
+		return nil, err
+	}
 
 	// check if the provided test types ids are valid
 	areTestTypesValid, err := app.areTestTypesValid(availableTests)
@@ -1342,7 +1604,10 @@ func (app *Application) updateLocation(current model.User, group string, audit *
 		{Key: "longitude", Value: fmt.Sprint(longitude)}, {Key: "contact", Value: contact}, {Key: "daysOfOperation", Value: fmt.Sprint(daysOfOperation)},
 		{Key: "url", Value: url}, {Key: "notes", Value: notes}, {Key: "waitTimeColor", Value: utils.GetString(waitTimeColor)},
 		{Key: "availableTests", Value: fmt.Sprint(availableTests)}}
-	defer app.audit.LogUpdateEvent(userIdentifier, userInfo, group, "location", ID, lData, audit)
+	err = app.audit.LogUpdateEvent(userIdentifier, userInfo, group, "location", ID, lData, audit)
+	if err != nil {
+		return nil, err
+	}
 
 	return location, nil
 }
@@ -1410,14 +1675,29 @@ func (app *Application) containsTestType(ID string, list []*model.TestType) bool
 }
 
 func (app *Application) deleteLocation(current model.User, group string, ID string) error {
-	err := app.storage.DeleteLocation(ID)
+	location, err := app.storage.FindLocation(ID)
+	if err != nil {
+		return err
+	}
+	if location == nil {
+		return errors.New("location is nil for id " + ID)
+	}
+	err = app.checkCountyScope(group, model.PermissionLocationsWrite, location.County.ID)
+	if err != nil {
+		return err
+	}
+
+	err = app.storage.DeleteLocation(ID)
 	if err != nil {
 		return err
 	}
 
 	//audit
 	userIdentifier, userInfo := current.GetLogData()
-	defer app.audit.LogDeleteEvent(userIdentifier, userInfo, group, "location", ID)
+	err = app.audit.LogDeleteEvent(userIdentifier, userInfo, group, "location", ID)
+	if err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -1431,7 +1711,10 @@ func (app *Application) createSymptom(current model.User, group string, name str
 	//audit
 	userIdentifier, userInfo := current.GetLogData()
 	lData := []AuditDataEntry{{Key: "name", Value: name}, {Key: "symptomGroup", Value: symptomGroup}}
-	defer app.audit.LogCreateEvent(userIdentifier, userInfo, group, "symptom", symptom.ID, lData, nil)
+	err = app.audit.LogCreateEvent(userIdentifier, userInfo, group, "symptom", symptom.ID, lData, nil)
+	if err != nil {
+		return nil, err
+	}
 
 	return symptom, nil
 }
@@ -1457,7 +1740,10 @@ func (app *Application) updateSymptom(current model.User, group string, ID strin
 	//audit
 	userIdentifier, userInfo := current.GetLogData()
 	lData := []AuditDataEntry{{Key: "name", Value: name}}
-	defer app.audit.LogUpdateEvent(userIdentifier, userInfo, group, "symptom", ID, lData, nil)
+	err = app.audit.LogUpdateEvent(userIdentifier, userInfo, group, "symptom", ID, lData, nil)
+	if err != nil {
+		return nil, err
+	}
 
 	return symptom, nil
 }
@@ -1470,25 +1756,43 @@ func (app *Application) deleteSymptom(current model.User, group string, ID strin
 
 	//audit
 	userIdentifier, userInfo := current.GetLogData()
-	defer app.audit.LogDeleteEvent(userIdentifier, userInfo, group, "symptom", ID)
+	err = app.audit.LogDeleteEvent(userIdentifier, userInfo, group, "symptom", ID)
+	if err != nil {
+		return err
+	}
 	return nil
 }
 
-func (app *Application) getSymptomRules() ([]*model.SymptomRule, error) {
+func (app *Application) getSymptomRules(group string) ([]*model.SymptomRule, error) {
 	symptomRules, err := app.storage.ReadAllSymptomRules()
 	if err != nil {
 		return nil, err
 	}
-	return symptomRules, nil
+
+	all, counties := app.countiesScope(group, model.PermissionSymptomsRead)
+	if all {
+		return symptomRules, nil
+	}
+	var result []*model.SymptomRule
+	for _, symptomRule := range symptomRules {
+		if utils.Contains(counties, symptomRule.County.ID) {
+			result = append(result, symptomRule)
+		}
+	}
+	return result, nil
 }
 
 func (app *Application) createSymptomRule(current model.User, group string, countyID string, gr1Count int, gr2Count int, items []model.SymptomRuleItem) (*model.SymptomRule, error) {
 	// validate the data
-	err := app.validateSymptomRuleData(countyID, gr1Count, gr2Count, items)
+	err := app.checkCountyScope(group, model.PermissionSymptomsWrite, countyID)
 	if err != nil {
 		return nil, err
 	}
-
+	err = app.validateSymptomRuleData(countyID, gr1Count, gr2Count, items)
+	if err != nil {
+		return nil, err
+	}
+
 	//create it
 	symptomRule, err := app.storage.CreateSymptomRule(countyID, gr1Count, gr2Count, items)
 	if err != nil {
@@ -1499,7 +1803,10 @@ func (app *Application) createSymptomRule(current model.User, group string, coun
 	userIdentifier, userInfo := current.GetLogData()
 	lData := []AuditDataEntry{{Key: "countyID", Value: countyID}, {Key: "gr1Count", Value: fmt.Sprint(gr1Count)},
 		{Key: "gr2Count", Value: fmt.Sprint(gr2Count)}, {Key: "items", Value: fmt.Sprint(items)}}
-	defer app.audit.LogCreateEvent(userIdentifier, userInfo, group, "symptom-rule", symptomRule.ID, lData, nil)
+	err = app.audit.LogCreateEvent(userIdentifier, userInfo, group, "symptom-rule", symptomRule.ID, lData, nil)
+	if err != nil {
+		return nil, err
+	}
 
 	return symptomRule, nil
 }
@@ -1513,8 +1820,16 @@ func (app *Application) updateSymptomRule(current model.User, group string, ID s
 	if symptomRule == nil {
 		return nil, errors.New("symptom rule is nil for id " + ID)
 	}
+	err = app.checkCountyScope(group, model.PermissionSymptomsWrite, symptomRule.County.ID)
+	if err != nil {
+		return nil, err
+	}
 
 	// validate the data
+	err = app.checkCountyScope(group, model.PermissionSymptomsWrite, countyID)
+	if err != nil {
+		return nil, err
+	}
 	err = app.validateSymptomRuleData(countyID, gr1Count, gr2Count, items)
 	if err != nil {
 		return nil, err
@@ -1536,7 +1851,10 @@ func (app *Application) updateSymptomRule(current model.User, group string, ID s
 	userIdentifier, userInfo := current.GetLogData()
 	lData := []AuditDataEntry{{Key: "countyID", Value: countyID}, {Key: "gr1Count", Value: fmt.Sprint(gr1Count)},
 		{Key: "gr2Count", Value: fmt.Sprint(gr2Count)}, {Key: "items", Value: fmt.Sprint(items)}}
-	defer app.audit.LogUpdateEvent(userIdentifier, userInfo, group, "symptom-rule", ID, lData, nil)
+	err = app.audit.LogUpdateEvent(userIdentifier, userInfo, group, "symptom-rule", ID, lData, nil)
+	if err != nil {
+		return nil, err
+	}
 
 	return symptomRule, nil
 }
@@ -1591,35 +1909,70 @@ func (app *Application) areGr1Gr2Valid(gr1 bool, gr2 bool, items []model.Symptom
 }
 
 func (app *Application) deleteSymptomRule(current model.User, group string, ID string) error {
-	err := app.storage.DeleteSymptomRule(ID)
+	symptomRule, err := app.storage.FindSymptomRule(ID)
+	if err != nil {
+		return err
+	}
+	if symptomRule == nil {
+		return errors.New("symptom rule is nil for id " + ID)
+	}
+	err = app.checkCountyScope(group, model.PermissionSymptomsWrite, symptomRule.County.ID)
+	if err != nil {
+		return err
+	}
+
+	err = app.storage.DeleteSymptomRule(ID)
 	if err != nil {
 		return err
 	}
 
 	//audit
 	userIdentifier, userInfo := current.GetLogData()
-	defer app.audit.LogDeleteEvent(userIdentifier, userInfo, group, "symptom-rule", ID)
+	err = app.audit.LogDeleteEvent(userIdentifier, userInfo, group, "symptom-rule", ID)
+	if err != nil {
+		return err
+	}
 
 	return nil
 }
 
-func (app *Application) getManualTestByCountyID(countyID string, status *string) ([]*model.EManualTest, error) {
-	manualTests, err := app.storage.FindManualTestsByCountyIDDeep(countyID, status)
-	if err != nil {
-		return nil, err
+func (app *Application) queryManualTests(group string, countyID string, q *utils.Query) ([]*model.EManualTest, string, error) {
+	var countyIDs []string
+	if countyID == "all" {
+		//nil for all counties
+		all, counties := app.countiesScope(group, model.PermissionManualTestsRead)
+		if !all {
+			countyIDs = append([]string{}, counties...)
+		}
+	} else {
+		err := app.checkCountyScope(group, model.PermissionManualTestsRead, countyID)
+		if err != nil {
+			return nil, "", err
+		}
+		countyIDs = []string{countyID}
 	}
-	return manualTests, nil
+	return app.storage.QueryManualTests(countyIDs, q)
 }
 
-func (app *Application) processManualTest(ID string, status string, encryptedKey *string, encryptedBlob *string, date *time.Time) error {
-	err := app.storage.ProcessManualTest(ID, status, encryptedKey, encryptedBlob, date)
+func (app *Application) processManualTest(group string, ID string, status string, encryptedKey *string, encryptedBlob *string, date *time.Time) error {
+	err := app.checkManualTestCountyScope(group, model.PermissionManualTestsVerify, ID)
+	if err != nil {
+		return err
+	}
+
+	err = app.storage.ProcessManualTest(ID, status, encryptedKey, encryptedBlob, date)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (app *Application) getManualTestImage(ID string) (*string, *string, error) {
+func (app *Application) getManualTestImage(group string, ID string) (*string, *string, error) {
+	err := app.checkManualTestCountyScope(group, model.PermissionManualTestsRead, ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	encryptedImageKey, encryptedImageBlob, err := app.storage.FindManualTestImage(ID)
 	if err != nil {
 		return nil, nil, err
@@ -1627,15 +1980,48 @@ func (app *Application) getManualTestImage(ID string) (*string, *string, error)
 	return encryptedImageKey, encryptedImageBlob, nil
 }
 
-func (app *Application) getAccessRules() ([]*model.AccessRule, error) {
+//checkManualTestCountyScope checks the county scope for the manual test county. The manual tests without county are available
+//only for the admins which are not limited to counties.
+func (app *Application) checkManualTestCountyScope(group string, permission string, ID string) error {
+	all, counties := app.countiesScope(group, permission)
+	if all {
+		return nil
+	}
+	countyID, err := app.storage.FindManualTestCountyID(ID)
+	if err != nil {
+		return err
+	}
+	if countyID == nil || !utils.Contains(counties, *countyID) {
+		return ErrCountyForbidden
+	}
+	return nil
+}
+
+func (app *Application) getAccessRules(group string) ([]*model.AccessRule, error) {
 	accessRules, err := app.storage.ReadAllAccessRules()
 	if err != nil {
 		return nil, err
 	}
-	return accessRules, nil
+
+	all, counties := app.countiesScope(group, model.PermissionAccessRulesRead)
+	if all {
+		return accessRules, nil
+	}
+	var result []*model.AccessRule
+	for _, accessRule := range accessRules {
+		if utils.Contains(counties, accessRule.County.ID) {
+			result = append(result, accessRule)
+		}
+	}
+	return result, nil
 }
 
 func (app *Application) createAccessRule(current model.User, group string, audit *string, countyID string, rules []model.AccessRuleCountyStatus) (*model.AccessRule, error) {
+	err := app.checkCountyScope(group, model.PermissionAccessRulesWrite, countyID)
+	if err != nil {
+		return nil, err
+	}
+
 	accessRule, err := app.storage.CreateAccessRule(countyID, rules)
 	if err != nil {
 		return nil, err
@@ -1644,12 +2030,24 @@ func (app *Application) createAccessRule(current model.User, group string, audit
 	//audit
 	userIdentifier, userInfo := current.GetLogData()
 	lData := []AuditDataEntry{{Key: "countyID", Value: countyID}, {Key: "rules", Value: fmt.Sprint(rules)}}
-	defer app.audit.LogCreateEvent(userIdentifier, userInfo, group, "access-rule", accessRule.ID, lData, audit)
+	err = app.audit.LogCreateEvent(userIdentifier, userInfo, group, "access-rule", accessRule.ID, lData, audit)
+	if err != nil {
+		return nil, err
+	}
 
 	return accessRule, nil
 }
 
 func (app *Application) updateAccessRule(current model.User, group string, audit *string, ID string, countyID string, rules []model.AccessRuleCountyStatus) (*model.AccessRule, error) {
+	err := app.checkAccessRuleCountyScope(group, ID)
+	if err != nil {
+		return nil, err
+	}
+	err = app.checkCountyScope(group, model.PermissionAccessRulesWrite, countyID)
+	if err != nil {
+		return nil, err
+	}
+
 	accessRule, err := app.storage.UpdateAccessRule(ID, countyID, rules)
 	if err != nil {
 		return nil, err
@@ -1658,23 +2056,45 @@ func (app *Application) updateAccessRule(current model.User, group string, audit
 	//audit
 	userIdentifier, userInfo := current.GetLogData()
 	lData := []AuditDataEntry{{Key: "countyID", Value: countyID}, {Key: "rules", Value: fmt.Sprint(rules)}}
-	defer app.audit.LogUpdateEvent(userIdentifier, userInfo, group, "access-rule", ID, lData, audit)
+	err = app.audit.LogUpdateEvent(userIdentifier, userInfo, group, "access-rule", ID, lData, audit)
+	if err != nil {
+		return nil, err
+	}
 
 	return accessRule, nil
 }
 
 func (app *Application) deleteAccessRule(current model.User, group string, ID string) error {
-	err := app.storage.DeleteAccessRule(ID)
+	err := app.checkAccessRuleCountyScope(group, ID)
+	if err != nil {
+		return err
+	}
+
+	err = app.storage.DeleteAccessRule(ID)
 	if err != nil {
 		return err
 	}
 
 	//audit
 	userIdentifier, userInfo := current.GetLogData()
-	defer app.audit.LogDeleteEvent(userIdentifier, userInfo, group, "access-rule", ID)
+	err = app.audit.LogDeleteEvent(userIdentifier, userInfo, group, "access-rule", ID)
+	if err != nil {
+		return err
+	}
 	return nil
 }
 
+func (app *Application) checkAccessRuleCountyScope(group string, ID string) error {
+	accessRule, err := app.storage.FindAccessRule(ID)
+	if err != nil {
+		return err
+	}
+	if accessRule == nil {
+		return errors.New("access rule is nil for id " + ID)
+	}
+	return app.checkCountyScope(group, model.PermissionAccessRulesWrite, accessRule.County.ID)
+}
+
 func (app *Application) getUserByExternalID(externalID string) (*model.User, error) {
 	user, err := app.storage.FindUserAccountsByExternalID(externalID)
 	if err != nil {
@@ -1683,46 +2103,59 @@ func (app *Application) getUserByExternalID(externalID string) (*model.User, err
 	return user, nil
 }
 
-func (app *Application) createRoster(current model.User, group string, audit *string, phone string, uin string, firstName string,
-	middleName string, lastName string, birthDate string, gender string, address1 string, address2 string,
-	address3 string, city string, state string, zipCode string, email string, badgeType string) error {
-	err := app.storage.CreateRoster(phone, uin, firstName, middleName, lastName, birthDate, gender, address1,
-		address2, address3, city, state, zipCode, email, badgeType)
+func (app *Application) createRoster(current model.User, group string, audit *string, roster model.Roster) error {
+	err := app.validateRoster(&roster, true)
+	if err != nil {
+		return err
+	}
+
+	err = app.storage.CreateRoster(roster)
 	if err != nil {
 		return err
 	}
 
 	//audit
 	userIdentifier, userInfo := current.GetLogData()
-	lData := []AuditDataEntry{{Key: "phone", Value: phone}, {Key: "uin", Value: uin}, {Key: "firstName", Value: firstName}, {Key: "middleName", Value: middleName},
-		{Key: "lastName", Value: lastName}, {Key: "birthDate", Value: birthDate}, {Key: "gender", Value: gender}, {Key: "address1", Value: address1},
-		{Key: "address2", Value: address2}, {Key: "address3", Value: address3}, {Key: "city", Value: city}, {Key: "state", Value: state},
-		{Key: "zipCode", Value: zipCode}, {Key: "email", Value: email}, {Key: "badgeType", Value: badgeType}}
-	defer app.audit.LogCreateEvent(userIdentifier, userInfo, group, "roster", "", lData, audit)
+	lData := rosterAuditData(roster)
+	err = app.audit.LogCreateEvent(userIdentifier, userInfo, group, "roster", "", lData, audit)
+	if err != nil {
+		return err
+	}
 
 	return nil
 }
 
-func (app *Application) updateRoster(current model.User, group string, audit *string, uin string, firstName string, middleName string, lastName string, birthDate string, gender string,
-	address1 string, address2 string, address3 string, city string, state string, zipCode string, email string, badgeType string) error {
-	err := app.storage.UpdateRoster(uin, firstName, middleName, lastName, birthDate, gender, address1,
-		address2, address3, city, state, zipCode, email, badgeType)
+func (app *Application) updateRoster(current model.User, group string, audit *string, roster model.Roster) error {
+	//the phone and the uin are not updated
+	err := app.validateRoster(&roster, false)
+	if err != nil {
+		return err
+	}
+
+	err = app.storage.UpdateRoster(roster)
 	if err != nil {
 		return err
 	}
 
 	//audit
 	userIdentifier, userInfo := current.GetLogData()
-	lData := []AuditDataEntry{{Key: "uin", Value: uin}, {Key: "firstName", Value: firstName}, {Key: "middleName", Value: middleName},
-		{Key: "lastName", Value: lastName}, {Key: "birthDate", Value: birthDate}, {Key: "gender", Value: gender}, {Key: "address1", Value: address1},
-		{Key: "address2", Value: address2}, {Key: "address3", Value: address3}, {Key: "city", Value: city}, {Key: "state", Value: state},
-		{Key: "zipCode", Value: zipCode}, {Key: "email", Value: email}, {Key: "badgeType", Value: badgeType}}
-	defer app.audit.LogUpdateEvent(userIdentifier, userInfo, group, "roster", "", lData, audit)
+	lData := rosterAuditData(roster)[1:]
+	err = app.audit.LogUpdateEvent(userIdentifier, userInfo, group, "roster", "", lData, audit)
+	if err != nil {
+		return err
+	}
 
 	return nil
 }
 
-func (app *Application) createRosterItems(current model.User, group string, audit *string, items []map[string]string) error {
+func (app *Application) createRosterItems(current model.User, group string, audit *string, items []model.Roster) error {
+	for i := range items {
+		err := app.validateRoster(&items[i], true)
+		if err != nil {
+			return fmt.Errorf("item %d: %s", i, err)
+		}
+	}
+
 	err := app.storage.CreateRosterItems(items)
 	if err != nil {
 		return err
@@ -1730,13 +2163,16 @@ func (app *Application) createRosterItems(current model.User, group string, audi
 
 	//audit
 	userIdentifier, userInfo := current.GetLogData()
-	lData := []AuditDataEntry{{Key: "items", Value: fmt.Sprintf("%s", items)}}
-	defer app.audit.LogCreateEvent(userIdentifier, userInfo, group, "roster", "", lData, audit)
+	lData := []AuditDataEntry{{Key: "items", Value: fmt.Sprintf("%v", items)}}
+	err = app.audit.LogCreateEvent(userIdentifier, userInfo, group, "roster", "", lData, audit)
+	if err != nil {
+		return err
+	}
 
 	return nil
 }
 
-func (app *Application) getRosters(filter *utils.Filter, sortBy string, sortOrder int, limit int, offset int) ([]map[string]interface{}, error) {
+func (app *Application) getRosters(filter *utils.Filter, sortBy string, sortOrder int, limit int, offset int) ([]model.Roster, error) {
 	rosters, err := app.storage.FindRosters(filter, sortBy, sortOrder, limit, offset)
 	if err != nil {
 		return nil, err
@@ -1744,6 +2180,79 @@ func (app *Application) getRosters(filter *utils.Filter, sortBy string, sortOrde
 	return rosters, nil
 }
 
+func (app *Application) queryRosters(q *utils.Query) ([]model.Roster, string, error) {
+	return app.storage.QueryRosters(q)
+}
+
+//validateRoster normalizes the roster member phone and validates the fields and the extra attributes
+func (app *Application) validateRoster(roster *model.Roster, isNew bool) error {
+	rosterErrors := app.checkRoster(roster, isNew)
+	if len(rosterErrors) > 0 {
+		rosterError := rosterErrors[0]
+		return fmt.Errorf("%s %s - %s", rosterError.Field, rosterError.Value, rosterError.Message)
+	}
+	return nil
+}
+
+//checkRoster normalizes the roster member phone and gives all the fields and the extra attributes errors.
+//The phone and the uin are checked only for the new members as they cannot be updated.
+func (app *Application) checkRoster(roster *model.Roster, isNew bool) []model.RosterImportError {
+	var rosterErrors []model.RosterImportError
+	if isNew {
+		phone := phoneFormatReplacer.Replace(roster.Phone)
+		if !e164PhonePattern.MatchString(phone) {
+			rosterErrors = append(rosterErrors, model.RosterImportError{Field: "phone", Value: roster.Phone,
+				Message: "the phone must be in the E.164 format"})
+		}
+		roster.Phone = phone
+
+		if !uinPattern.MatchString(roster.UIN) {
+			rosterErrors = append(rosterErrors, model.RosterImportError{Field: "uin", Value: roster.UIN,
+				Message: "the uin must have 9 digits"})
+		}
+	}
+
+	var attributes []model.RosterAttribute
+	if config := app.getCachedCovid19Config(); config != nil {
+		attributes = config.RosterAttributes
+	}
+	configured := make(map[string]bool, len(attributes))
+	for _, attribute := range attributes {
+		configured[attribute.Name] = true
+
+		value, ok := roster.Attributes[attribute.Name]
+		if !ok || len(value) == 0 {
+			if attribute.Required {
+				rosterErrors = append(rosterErrors, model.RosterImportError{Field: attribute.Name, Message: "the attribute is required"})
+			}
+			continue
+		}
+		if len(attribute.Pattern) > 0 {
+			//the patterns are validated when the config is saved
+			pattern := regexp.MustCompile(attribute.Pattern)
+			if !pattern.MatchString(value) {
+				rosterErrors = append(rosterErrors, model.RosterImportError{Field: attribute.Name, Value: value,
+					Message: fmt.Sprintf("the attribute must match %s", attribute.Pattern)})
+			}
+		}
+	}
+	for name, value := range roster.Attributes {
+		if !configured[name] {
+			rosterErrors = append(rosterErrors, model.RosterImportError{Field: name, Value: value, Message: "unknown attribute"})
+		}
+	}
+	return rosterErrors
+}
+
+func rosterAuditData(roster model.Roster) []AuditDataEntry {
+	return []AuditDataEntry{{Key: "phone", Value: roster.Phone}, {Key: "uin", Value: roster.UIN}, {Key: "firstName", Value: roster.FirstName},
+		{Key: "middleName", Value: roster.MiddleName}, {Key: "lastName", Value: roster.LastName}, {Key: "birthDate", Value: roster.BirthDate},
+		{Key: "gender", Value: roster.Gender}, {Key: "address1", Value: roster.Address1}, {Key: "address2", Value: roster.Address2},
+		{Key: "address3", Value: roster.Address3}, {Key: "city", Value: roster.City}, {Key: "state", Value: roster.State},
+		{Key: "zipCode", Value: roster.ZipCode}, {Key: "email", Value: roster.Email}, {Key: "badgeType", Value: roster.BadgeType},
+		{Key: "attributes", Value: fmt.Sprintf("%v", roster.Attributes)}}
+}
+
 func (app *Application) deleteRosterByPhone(current model.User, group string, phone string) error {
 	err := app.storage.DeleteRosterByPhone(phone)
 	if err != nil {
@@ -1752,7 +2261,10 @@ func (app *Application) deleteRosterByPhone(current model.User, group string, ph
 
 	//audit
 	userIdentifier, userInfo := current.GetLogData()
-	defer app.audit.LogDeleteEvent(userIdentifier, userInfo, group, "roster", fmt.Sprintf("phone:%s", phone))
+	err = app.audit.LogDeleteEvent(userIdentifier, userInfo, group, "roster", fmt.Sprintf("phone:%s", phone))
+	if err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -1765,7 +2277,10 @@ func (app *Application) deleteRosterByUIN(current model.User, group string, uin
 
 	//audit
 	userIdentifier, userInfo := current.GetLogData()
-	defer app.audit.LogDeleteEvent(userIdentifier, userInfo, group, "roster", fmt.Sprintf("uin:%s", uin))
+	err = app.audit.LogDeleteEvent(userIdentifier, userInfo, group, "roster", fmt.Sprintf("uin:%s", uin))
+	if err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -1778,7 +2293,10 @@ func (app *Application) deleteAllRosters(current model.User, group string) error
 
 	//audit
 	userIdentifier, userInfo := current.GetLogData()
-	defer app.audit.LogDeleteEvent(userIdentifier, userInfo, group, "roster", "all")
+	err = app.audit.LogDeleteEvent(userIdentifier, userInfo, group, "roster", "all")
+	if err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -1792,7 +2310,10 @@ func (app *Application) createRawSubAccountItems(current model.User, group strin
 	//audit
 	userIdentifier, userInfo := current.GetLogData()
 	lData := []AuditDataEntry{{Key: "items", Value: fmt.Sprintf("%+v", items)}}
-	defer app.audit.LogCreateEvent(userIdentifier, userInfo, group, "raw-sub-account", "", lData, audit)
+	err = app.audit.LogCreateEvent(userIdentifier, userInfo, group, "raw-sub-account", "", lData, audit)
+	if err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -1819,7 +2340,27 @@ func (app *Application) updateRawSubAccount(current model.User, group string, au
 		{Key: "lastName", Value: lastName}, {Key: "birthDate", Value: birthDate}, {Key: "gender", Value: gender}, {Key: "address1", Value: address1},
 		{Key: "address2", Value: address2}, {Key: "address3", Value: address3}, {Key: "city", Value: city}, {Key: "state", Value: state},
 		{Key: "zipCode", Value: zipCode}, {Key: "phone", Value: phone}, {Key: "netID", Value: netID}, {Key: "email", Value: email}}
-	defer app.audit.LogUpdateEvent(userIdentifier, userInfo, group, "raw-sub-account", "", lData, audit)
+	err = app.audit.LogUpdateEvent(userIdentifier, userInfo, group, "raw-sub-account", "", lData, audit)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (app *Application) revokeRawSubAccount(current model.User, group string, audit *string, uin string) error {
+	err := app.storage.RevokeRawSubAccount(uin)
+	if err != nil {
+		return err
+	}
+
+	//audit
+	userIdentifier, userInfo := current.GetLogData()
+	lData := []AuditDataEntry{{Key: "uin", Value: uin}, {Key: "linkStatus", Value: model.SubAccountLinkRevoked}}
+	err = app.audit.LogUpdateEvent(userIdentifier, userInfo, group, "raw-sub-account", uin, lData, audit)
+	if err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -1832,7 +2373,10 @@ func (app *Application) deleteRawSubAccountByUIN(current model.User, group strin
 
 	//audit
 	userIdentifier, userInfo := current.GetLogData()
-	defer app.audit.LogDeleteEvent(userIdentifier, userInfo, group, "raw-sub-account", fmt.Sprintf("uin:%s", uin))
+	err = app.audit.LogDeleteEvent(userIdentifier, userInfo, group, "raw-sub-account", fmt.Sprintf("uin:%s", uin))
+	if err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -1845,65 +2389,266 @@ func (app *Application) deleteAllRawSubAccounts(current model.User, group string
 
 	//audit
 	userIdentifier, userInfo := current.GetLogData()
-	defer app.audit.LogDeleteEvent(userIdentifier, userInfo, group, "raw-sub-account", "all")
+	err = app.audit.LogDeleteEvent(userIdentifier, userInfo, group, "raw-sub-account", "all")
+	if err != nil {
+		return err
+	}
 
 	return nil
 }
 
-func (app *Application) createAction(current model.User, group string, audit *string, providerID string, accountID string, encryptedKey string, encryptedBlob string) (*model.CTest, error) {
+func (app *Application) createAction(current model.User, group string, audit *string, providerID string, accountID string, encryptedKey string, encryptedBlob string, positive bool) (*model.CTest, error) {
+	userIdentifier, userInfo := current.GetLogData()
+
 	//1. create a ctest
 	item, user, err := app.storage.CreateAdminCTest(providerID, accountID, encryptedKey, encryptedBlob, false, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	//2. send a firebase notification to the user.
-	go func(userUUID string) {
-		if len(userUUID) <= 0 {
-			log.Println("user uuid is empty")
-			return
-		}
-		//1. load the user data, we need the fcm tokens
-		userData, err := app.profileBB.LoadUserData(user.UUID)
-		if err != nil {
-			log.Printf("Error loading user data - %s\n", err)
-			return
-		}
+	//2. issue an exposure verification code for the user if enabled and the admin marked the result as positive
+	verificationCode, err := app.issueCTestExposureVerificationCode(user.ID, providerID, positive, userIdentifier)
+	if err != nil {
+		log.Printf("Error issuing an exposure verification code for an action - %s\n", err)
+	}
 
-		//2. send notification message
-		//2.1 prepare the data
-		data := make(map[string]string)
-		data["type"] = "health.covid19.notification"
-		data["health.covid19.notification.type"] = "process-pending-tests"
-		data["title"] = "COVID-19"
-		data["body"] = "You have received a COVID-19 update"
-		data["click_action"] = "FLUTTER_NOTIFICATION_CLICK"
-		app.messaging.SendNotificationMessage(userData.FCMTokens, "COVID-19", "You have received a COVID-19 update", data)
-	}(user.UUID)
+	//3. send a notification to the user.
+	go app.sendCTestNotification(user.UUID, model.NotificationEventProcessPendingTests, providerID, verificationCode)
 
 	//audit
-	userIdentifier, userInfo := current.GetLogData()
 	lData := []AuditDataEntry{{Key: "providerID", Value: providerID}, {Key: "accountID", Value: accountID},
-		{Key: "encryptedKey", Value: encryptedKey}, {Key: "encryptedBlob", Value: encryptedBlob}}
-	defer app.audit.LogCreateEvent(userIdentifier, userInfo, group, "action", item.ID, lData, audit)
+		{Key: "encryptedKey", Value: encryptedKey}, {Key: "encryptedBlob", Value: encryptedBlob}, {Key: "positive", Value: fmt.Sprint(positive)}}
+	err = app.audit.LogCreateEvent(userIdentifier, userInfo, group, "action", item.ID, lData, audit)
+	if err != nil {
+		return nil, err
+	}
 
 	return item, nil
 }
 
-func (app *Application) getAudit(current model.User, group string, userIdentifier *string, entity *string, entityID *string, operation *string,
-	clientData *string, createdAt *time.Time, sortBy *string, asc *bool, limit *int64) ([]*AuditEntity, error) {
-
-	//Admin can look all logs
+func (app *Application) getAudit(current model.User, group string, q *utils.Query) ([]*AuditEntity, string, error) {
+	//the groups with the permission can look all logs
 	var usedGroup *string
-	if current.IsAdmin() {
+	if app.HasPermission(group, model.PermissionAuditReadAll) {
 		usedGroup = nil
 	} else {
 		usedGroup = &group
 	}
 
-	items, err := app.audit.Find(userIdentifier, usedGroup, entity, entityID, operation, clientData, createdAt, sortBy, asc, limit)
+	items, cursor, err := app.audit.Query(usedGroup, q)
+	if err != nil {
+		return nil, "", err
+	}
+	return items, cursor, nil
+}
+
+func (app *Application) verifyAudit(fromSequence int64, toSequence int64) (*AuditVerification, error) {
+	verification, err := app.audit.Verify(fromSequence, toSequence)
+	if err != nil {
+		return nil, err
+	}
+	if !verification.Valid {
+		log.Printf("the audit chain verification failed for %d-%d", verification.FromSequence, verification.ToSequence)
+	}
+	return verification, nil
+}
+
+func (app *Application) getAuditMetrics() AuditMetrics {
+	return app.audit.Metrics()
+}
+
+func (app *Application) getNotificationTemplates() ([]*model.NotificationTemplate, error) {
+	templates, err := app.storage.ReadAllNotificationTemplates()
+	if err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+func (app *Application) createNotificationTemplate(current model.User, group string, audit *string, event string, variants []model.NotificationTemplateVariant) (*model.NotificationTemplate, error) {
+	err := app.validateNotificationTemplateVariants(variants)
+	if err != nil {
+		return nil, err
+	}
+
+	template, err := app.storage.CreateNotificationTemplate(event, variants)
+	if err != nil {
+		return nil, err
+	}
+
+	//audit
+	userIdentifier, userInfo := current.GetLogData()
+	lData := []AuditDataEntry{{Key: "event", Value: event}, {Key: "variants", Value: app.formatNotificationTemplateVariants(variants)}}
+	err = app.audit.LogCreateEvent(userIdentifier, userInfo, group, "notification-template", template.ID, lData, audit)
+	if err != nil {
+		return nil, err
+	}
+
+	return template, nil
+}
+
+func (app *Application) updateNotificationTemplate(current model.User, group string, audit *string, ID string, event string, variants []model.NotificationTemplateVariant) (*model.NotificationTemplate, error) {
+	err := app.validateNotificationTemplateVariants(variants)
+	if err != nil {
+		return nil, err
+	}
+
+	template, err := app.storage.UpdateNotificationTemplate(ID, event, variants)
+	if err != nil {
+		return nil, err
+	}
+
+	//audit
+	userIdentifier, userInfo := current.GetLogData()
+	lData := []AuditDataEntry{{Key: "event", Value: event}, {Key: "variants", Value: app.formatNotificationTemplateVariants(variants)}}
+	err = app.audit.LogUpdateEvent(userIdentifier, userInfo, group, "notification-template", ID, lData, audit)
+	if err != nil {
+		return nil, err
+	}
+
+	return template, nil
+}
+
+func (app *Application) deleteNotificationTemplate(current model.User, group string, ID string) error {
+	err := app.storage.DeleteNotificationTemplate(ID)
+	if err != nil {
+		return err
+	}
+
+	//audit
+	userIdentifier, userInfo := current.GetLogData()
+	err = app.audit.LogDeleteEvent(userIdentifier, userInfo, group, "notification-template", ID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (app *Application) validateNotificationTemplateVariants(variants []model.NotificationTemplateVariant) error {
+	if len(variants) == 0 {
+		return errors.New("at least one variant is required")
+	}
+	languages := map[string]bool{}
+	for _, variant := range variants {
+		language := strings.ToLower(variant.Language)
+		if languages[language] {
+			return fmt.Errorf("duplicated variant for language %s", variant.Language)
+		}
+		languages[language] = true
+	}
+	return nil
+}
+
+func (app *Application) formatNotificationTemplateVariants(variants []model.NotificationTemplateVariant) string {
+	items := make([]string, len(variants))
+	for i, variant := range variants {
+		items[i] = fmt.Sprintf("%s - %s - %s", variant.Language, variant.Title, variant.Body)
+	}
+	return strings.Join(items, "; ")
+}
+
+func (app *Application) getBroadcasts(group string, status *string) ([]*model.Broadcast, error) {
+	broadcasts, err := app.storage.FindBroadcasts(status)
 	if err != nil {
 		return nil, err
 	}
-	return items, nil
+
+	all, counties := app.countiesScope(group, model.PermissionBroadcastsRead)
+	if all {
+		return broadcasts, nil
+	}
+	var result []*model.Broadcast
+	for _, broadcast := range broadcasts {
+		if broadcast.Target.CountyID != nil && utils.Contains(counties, *broadcast.Target.CountyID) {
+			result = append(result, broadcast)
+		}
+	}
+	return result, nil
+}
+
+//checkBroadcastCountyScope checks the county scope for the broadcast target. The broadcasts which are not targeted to a county
+//are available only for the admins which are not limited to counties.
+func (app *Application) checkBroadcastCountyScope(group string, permission string, target model.BroadcastTarget) error {
+	all, counties := app.countiesScope(group, permission)
+	if all {
+		return nil
+	}
+	if target.CountyID == nil || !utils.Contains(counties, *target.CountyID) {
+		return ErrCountyForbidden
+	}
+	return nil
+}
+
+func (app *Application) getBroadcastAudienceSize(group string, target model.BroadcastTarget) (int, error) {
+	err := app.checkBroadcastCountyScope(group, model.PermissionBroadcastsWrite, target)
+	if err != nil {
+		return -1, err
+	}
+
+	uuids, err := app.storage.FindBroadcastAudience(target)
+	if err != nil {
+		return -1, err
+	}
+	return len(uuids), nil
+}
+
+func (app *Application) createBroadcast(current model.User, group string, audit *string, target model.BroadcastTarget, title string, body string, scheduledAt *time.Time) (*model.Broadcast, error) {
+	//1. validate the target, it also gives the audience size for the audit
+	audienceSize, err := app.getBroadcastAudienceSize(group, target)
+	if err != nil {
+		return nil, err
+	}
+
+	//2. create it, the broadcasts timer sends it when the time comes
+	sendAt := time.Now()
+	if scheduledAt != nil {
+		sendAt = *scheduledAt
+	}
+	userIdentifier, userInfo := current.GetLogData()
+	broadcast, err := app.storage.CreateBroadcast(target, title, body, sendAt, userIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	//audit
+	lData := []AuditDataEntry{{Key: "countyID", Value: utils.GetString(target.CountyID)}, {Key: "roster", Value: utils.GetBoolString(target.Roster)},
+		{Key: "rawSubAccountStatus", Value: utils.GetString(target.RawSubAccountStatus)}, {Key: "uinOverrideCategory", Value: utils.GetString(target.UINOverrideCategory)},
+		{Key: "appVersion", Value: utils.GetString(target.AppVersion)}, {Key: "title", Value: title}, {Key: "body", Value: body},
+		{Key: "scheduledAt", Value: utils.GetTime(&sendAt)}, {Key: "audienceSize", Value: strconv.Itoa(audienceSize)}}
+	err = app.audit.LogCreateEvent(userIdentifier, userInfo, group, "broadcast", broadcast.ID, lData, audit)
+	if err != nil {
+		return nil, err
+	}
+
+	return broadcast, nil
+}
+
+func (app *Application) cancelBroadcast(current model.User, group string, ID string) error {
+	broadcast, err := app.storage.FindBroadcast(ID)
+	if err != nil {
+		return err
+	}
+	if broadcast == nil {
+		return errors.New("there is no a broadcast for id " + ID)
+	}
+	err = app.checkBroadcastCountyScope(group, model.PermissionBroadcastsWrite, broadcast.Target)
+	if err != nil {
+		return err
+	}
+
+	err = app.storage.CancelBroadcast(ID)
+	if err != nil {
+		return err
+	}
+
+	//audit
+	userIdentifier, userInfo := current.GetLogData()
+	lData := []AuditDataEntry{{Key: "status", Value: model.BroadcastStatusCancelled}}
+	err = app.audit.LogUpdateEvent(userIdentifier, userInfo, group, "broadcast", ID, lData, nil)
+	if err != nil {
+		return err
+	}
+
+	return nil
 }
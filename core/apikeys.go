@@ -0,0 +1,238 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+
+package core
+
+import (
+	"errors"
+	"fmt"
+	"health/core/model"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	//how often the API keys last usage is saved
+	apiKeysUsageFlushPeriod = time.Minute
+)
+
+//FindAPIKey finds the active API key for the key value and the scope. The key usage is recorded.
+func (app *Application) FindAPIKey(key string, scope string) *model.APIKey {
+	app.akLock.RLock()
+	apiKey, ok := app.cachedAPIKeys[hashAPISecret(key)]
+	app.akLock.RUnlock()
+
+	now := time.Now().UTC()
+	if !ok || !apiKey.IsActive(now) || !apiKey.HasScope(scope) {
+		return nil
+	}
+
+	app.akUsageLock.Lock()
+	app.apiKeysUsage[apiKey.ID] = now
+	app.akUsageLock.Unlock()
+
+	return &apiKey
+}
+
+func (app *Application) loadAPIKeys() {
+	log.Println("Load API keys")
+
+	apiKeys, err := app.storage.ReadActiveAPIKeys()
+	if err != nil {
+		log.Printf("Error reading the API keys %s", err)
+		return
+	}
+
+	cached := make(map[string]model.APIKey, len(apiKeys))
+	for _, apiKey := range apiKeys {
+		cached[apiKey.KeyHash] = apiKey
+	}
+
+	app.akLock.Lock()
+	app.cachedAPIKeys = cached
+	app.akLock.Unlock()
+}
+
+func (app *Application) setupAPIKeysUsageTimer() {
+	log.Println("Application -> setupAPIKeysUsageTimer")
+
+	ticker := time.NewTicker(apiKeysUsageFlushPeriod)
+	for range ticker.C {
+		app.flushAPIKeysUsage()
+	}
+}
+
+//flushAPIKeysUsage saves the last usage of the keys used since the previous flush
+func (app *Application) flushAPIKeysUsage() {
+	app.akUsageLock.Lock()
+	usage := app.apiKeysUsage
+	app.apiKeysUsage = map[string]time.Time{}
+	app.akUsageLock.Unlock()
+
+	if len(usage) == 0 {
+		return
+	}
+	err := app.storage.UpdateAPIKeysLastUsed(usage)
+	if err != nil {
+		log.Printf("flushAPIKeysUsage -> error saving the API keys usage - %s", err)
+	}
+}
+
+func (app *Application) getAPIKeys() ([]model.APIKey, error) {
+	return app.storage.ReadAllAPIKeys()
+}
+
+//createAPIKey creates an API key and gives it with the key value. The value is not kept.
+func (app *Application) createAPIKey(current model.User, group string, audit *string, name string, owner string, scopes []string,
+	expires *time.Time) (*model.APIKey, string, error) {
+	err := validateAPIKey(name, scopes)
+	if err != nil {
+		return nil, "", err
+	}
+
+	apiKey, key, err := app.newAPIKey(name, owner, scopes, expires)
+	if err != nil {
+		return nil, "", err
+	}
+	err = app.storage.CreateAPIKey(*apiKey)
+	if err != nil {
+		return nil, "", err
+	}
+
+	//audit
+	userIdentifier, userInfo := current.GetLogData()
+	lData := []AuditDataEntry{{Key: "name", Value: name}, {Key: "owner", Value: owner}, {Key: "scopes", Value: strings.Join(scopes, ",")},
+		{Key: "keyPrefix", Value: apiKey.KeyPrefix}}
+	err = app.audit.LogCreateEvent(userIdentifier, userInfo, group, "api-key", apiKey.ID, lData, audit)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return apiKey, key, nil
+}
+
+//rotateAPIKey creates a new key with the same name, owner and scopes. The rotated key is valid during the grace period,
+//so that the owner can move to the new key.
+func (app *Application) rotateAPIKey(current model.User, group string, audit *string, ID string,
+	gracePeriodHours *int) (*model.APIKey, string, error) {
+	rotated, err := app.storage.FindAPIKey(ID)
+	if err != nil {
+		return nil, "", err
+	}
+	if rotated == nil {
+		return nil, "", fmt.Errorf("there is no an API key with id %s", ID)
+	}
+	now := time.Now().UTC()
+	if !rotated.IsActive(now) {
+		return nil, "", errors.New("the API key is not active")
+	}
+	hours := defaultGracePeriodHours
+	if gracePeriodHours != nil {
+		hours = *gracePeriodHours
+	}
+	if hours < 0 {
+		return nil, "", errors.New("the grace period cannot be negative")
+	}
+
+	apiKey, key, err := app.newAPIKey(rotated.Name, rotated.Owner, rotated.Scopes, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	err = app.storage.CreateAPIKey(*apiKey)
+	if err != nil {
+		return nil, "", err
+	}
+
+	expires := now.Add(time.Duration(hours) * time.Hour)
+	if rotated.DateExpires == nil || expires.Before(*rotated.DateExpires) {
+		rotated.DateExpires = &expires
+		err = app.storage.UpdateAPIKey(*rotated)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+
+	//audit
+	userIdentifier, userInfo := current.GetLogData()
+	lData := []AuditDataEntry{{Key: "name", Value: rotated.Name}, {Key: "rotatedID", Value: ID},
+		{Key: "keyPrefix", Value: apiKey.KeyPrefix}, {Key: "rotatedExpires", Value: rotated.DateExpires.Format(time.RFC3339)}}
+	err = app.audit.LogCreateEvent(userIdentifier, userInfo, group, "api-key", apiKey.ID, lData, audit)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return apiKey, key, nil
+}
+
+func (app *Application) revokeAPIKey(current model.User, group string, audit *string, ID string) error {
+	apiKey, err := app.storage.FindAPIKey(ID)
+	if err != nil {
+		return err
+	}
+	if apiKey == nil {
+		return fmt.Errorf("there is no an API key with id %s", ID)
+	}
+	if apiKey.DateRevoked != nil {
+		return errors.New("the API key is already revoked")
+	}
+
+	now := time.Now().UTC()
+	apiKey.DateRevoked = &now
+	err = app.storage.UpdateAPIKey(*apiKey)
+	if err != nil {
+		return err
+	}
+
+	//audit
+	userIdentifier, userInfo := current.GetLogData()
+	lData := []AuditDataEntry{{Key: "name", Value: apiKey.Name}, {Key: "revoked", Value: "true"}}
+	err = app.audit.LogUpdateEvent(userIdentifier, userInfo, group, "api-key", ID, lData, audit)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (app *Application) newAPIKey(name string, owner string, scopes []string, expires *time.Time) (*model.APIKey, string, error) {
+	key, err := generateAPISecret()
+	if err != nil {
+		return nil, "", err
+	}
+
+	apiKey := model.APIKey{ID: uuid.New().String(), Name: name, Owner: owner, Scopes: scopes, KeyHash: hashAPISecret(key),
+		KeyPrefix: key[:apiKeyPrefixLength], DateExpires: expires, DateCreated: time.Now().UTC()}
+	return &apiKey, key, nil
+}
+
+func validateAPIKey(name string, scopes []string) error {
+	if len(strings.TrimSpace(name)) == 0 {
+		return errors.New("the API key name is required")
+	}
+	if len(scopes) == 0 {
+		return errors.New("the API key needs at least one scope")
+	}
+	for _, scope := range scopes {
+		if !model.IsAPIKeyScope(scope) {
+			return fmt.Errorf("unknown API key scope %s", scope)
+		}
+	}
+	return nil
+}
@@ -28,6 +28,14 @@ import (
 	"time"
 )
 
+//defaultNotificationVariants is the notification content used when there is no template for the event
+var defaultNotificationVariants = map[string]model.NotificationTemplateVariant{
+	model.NotificationEventProcessPendingTests: {Language: model.DefaultNotificationLanguage, Title: "COVID-19", Body: "You have received a COVID-19 update"},
+	model.NotificationEventNewResult:           {Language: model.DefaultNotificationLanguage, Title: "COVID-19", Body: "You have received a new COVID-19 test result{{provider_from}}"},
+	model.NotificationEventTestingReminder:     {Language: model.DefaultNotificationLanguage, Title: "COVID-19 Testing", Body: "Your next COVID-19 test is due on {{due_date}}"},
+	model.NotificationEventTestingDue:          {Language: model.DefaultNotificationLanguage, Title: "COVID-19 Testing", Body: "Your COVID-19 test is due"},
+}
+
 //Application represents the core application code based on hexagonal architecture
 type Application struct {
 	version string
@@ -43,6 +51,8 @@ type Application struct {
 	rokmetro     Rokmetro
 	audit        Audit
 
+	exposureNotification ExposureNotification
+
 	storage Storage
 
 	//cache config data
@@ -53,6 +63,27 @@ type Application struct {
 	avLock            *sync.RWMutex
 	cachedAppVersions []string
 
+	//cache roles
+	rolesLock   *sync.RWMutex
+	cachedRoles []model.Role
+
+	//cache provider credentials by key hash
+	pcLock                    *sync.RWMutex
+	cachedProviderCredentials map[string]model.ProviderCredential
+
+	//cache API keys by key hash and their usage since the last flush
+	akLock        *sync.RWMutex
+	cachedAPIKeys map[string]model.APIKey
+	akUsageLock   *sync.Mutex
+	apiKeysUsage  map[string]time.Time
+
+	//cache the recently used users
+	usersCache *usersCache
+
+	//failed exposure code verifications by user
+	ecLock                          *sync.Mutex
+	failedExposureCodeVerifications map[string][]time.Time
+
 	listeners []ApplicationListener
 }
 
@@ -68,11 +99,28 @@ func (app *Application) Start() {
 	//cache the app versions
 	app.loadAppVersions()
 
+	//cache the roles
+	app.loadRoles()
+
+	//cache the provider credentials
+	app.loadProviderCredentials()
+
+	//cache the API keys
+	app.loadAPIKeys()
+
 	go app.loadNewsData()
 	//Disable the resource data loading as we cannot map the new created data
 	//go app.loadResourcesData()
 
 	go app.setupLocationWaitTimeColorTimer()
+
+	go app.setupBroadcastsTimer()
+
+	go app.setupTestingRemindersTimer()
+
+	go app.setupExposuresPurgeTimer()
+
+	go app.setupAPIKeysUsageTimer()
 }
 
 //AddListener adds application listener
@@ -250,6 +298,94 @@ func (app *Application) notifyListeners(message string, data interface{}) {
 	}()
 }
 
+//sendUserNotification sends a notification to the user devices. The title and the body come from the event template in the user language.
+func (app *Application) sendUserNotification(userUUID string, event string, params map[string]string) {
+	app.sendUserNotificationWithData(userUUID, event, event, params, nil)
+}
+
+//sendUserNotificationWithData sends a notification with the notification type the app expects and the content from the event template.
+//The notification type and the event differ when an event only chooses another content for a type which the app already handles.
+func (app *Application) sendUserNotificationWithData(userUUID string, notificationType string, event string, params map[string]string, extraData map[string]string) {
+	if len(userUUID) <= 0 {
+		log.Println("user uuid is empty")
+		return
+	}
+
+	//1. load the user data, we need the fcm tokens and the language
+	userData, err := app.profileBB.LoadUserData(userUUID)
+	if err != nil {
+		log.Printf("Error loading user data - %s\n", err)
+		return
+	}
+
+	//2. prepare the content
+	title, body, err := app.getNotificationContent(event, userData.Language, params)
+	if err != nil {
+		log.Printf("Error preparing the %s notification content - %s\n", event, err)
+		return
+	}
+
+	//3. send notification message
+	data := app.prepareNotificationData(notificationType, title, body)
+	for key, value := range extraData {
+		data[key] = value
+	}
+	app.messaging.SendNotificationMessage(userData.FCMTokens, title, body, data)
+}
+
+func (app *Application) prepareNotificationData(notificationType string, title string, body string) map[string]string {
+	data := make(map[string]string)
+	data["type"] = "health.covid19.notification"
+	data["health.covid19.notification.type"] = notificationType
+	data["title"] = title
+	data["body"] = body
+	data["click_action"] = "FLUTTER_NOTIFICATION_CLICK"
+	return data
+}
+
+func (app *Application) getNotificationContent(event string, language string, params map[string]string) (string, string, error) {
+	template, err := app.storage.FindNotificationTemplateByEvent(event)
+	if err != nil {
+		return "", "", err
+	}
+
+	var variant *model.NotificationTemplateVariant
+	if template != nil {
+		variant = template.GetVariant(language)
+	}
+	if variant == nil {
+		//not configured by the admins, use the built in content
+		defaultVariant, ok := defaultNotificationVariants[event]
+		if !ok {
+			return "", "", fmt.Errorf("there is no notification template for %s", event)
+		}
+		variant = &defaultVariant
+	}
+
+	title, body := variant.Render(params)
+	return title, body, nil
+}
+
+//sendCTestNotification lets the user know that there is a new ctest to be processed.
+//The notification type is always process-pending-tests as the apps process the pending tests on it, the event chooses the content
+//and it is passed to the app with the notification data. The exposure verification code is passed too if issued.
+func (app *Application) sendCTestNotification(userUUID string, event string, providerID string, verificationCode *string) {
+	params := map[string]string{"provider": "", "provider_from": ""}
+	provider, err := app.storage.FindProvider(providerID)
+	if err != nil {
+		log.Printf("Error finding the provider for the notification - %s\n", err)
+	} else if provider != nil {
+		params["provider"] = provider.Name
+		params["provider_from"] = " from " + provider.Name
+	}
+
+	data := map[string]string{"health.covid19.notification.event": event}
+	if verificationCode != nil {
+		data["exposure_verification_code"] = *verificationCode
+	}
+	app.sendUserNotificationWithData(userUUID, model.NotificationEventProcessPendingTests, event, params, data)
+}
+
 func (app *Application) checkAppVersion(v *string) (*string, error) {
 	//use the latest version if not provided
 	if v == nil {
@@ -534,7 +670,9 @@ func (app *Application) findNewNewsItems(list []ProviderNews, latestDate *time.T
 
 //FindUserByShibbolethID finds an user for the provided shibboleth id
 func (app *Application) FindUserByShibbolethID(shibbolethID string) (*model.User, error) {
-	user, err := app.storage.FindUserByShibbolethID(shibbolethID)
+	user, err := app.usersCache.find("shibboleth:"+shibbolethID, func() (*model.User, error) {
+		return app.storage.FindUserByShibbolethID(shibbolethID)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -543,7 +681,9 @@ func (app *Application) FindUserByShibbolethID(shibbolethID string) (*model.User
 
 //FindUserByExternalID finds an user for the provided external id
 func (app *Application) FindUserByExternalID(externalID string) (*model.User, error) {
-	user, err := app.storage.FindUserByExternalID(externalID)
+	user, err := app.usersCache.find("external:"+externalID, func() (*model.User, error) {
+		return app.storage.FindUserByExternalID(externalID)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -568,6 +708,7 @@ func (app *Application) CreateDefaultAccount(userID string) (*model.User, error)
 	if err != nil {
 		return nil, err
 	}
+	app.usersCache.invalidate(userID)
 
 	return user, nil
 }
@@ -588,16 +729,18 @@ func (app *Application) UpdateUser(user *model.User) error {
 	if err != nil {
 		return err
 	}
+	//do not wait for the storage notification as the next request could be for the same user
+	app.usersCache.invalidate(user.ID)
 	return nil
 }
 
-//LoadAllRosters loads all rosters
-func (app *Application) LoadAllRosters() ([]map[string]string, error) {
-	rosters, err := app.storage.ReadAllRosters()
+//FindRosterByPhone finds the roster member with the given phone, nil if there is no such member
+func (app *Application) FindRosterByPhone(phone string) (*model.Roster, error) {
+	roster, err := app.storage.FindRosterByPhone(phone)
 	if err != nil {
 		return nil, err
 	}
-	return rosters, nil
+	return roster, nil
 }
 
 func (app *Application) getEHistoriesByAccountID(accountID string) ([]*model.EHistory, error) {
@@ -633,6 +776,10 @@ func (app *Application) getProviders() ([]*model.Provider, error) {
 	return providers, nil
 }
 
+func (app *Application) queryProviders(q *utils.Query) ([]*model.Provider, string, error) {
+	return app.storage.QueryProviders(q)
+}
+
 func (app *Application) findCounties(f *utils.Filter) ([]*model.County, error) {
 	counties, err := app.storage.FindCounties(f)
 	if err != nil {
@@ -641,6 +788,21 @@ func (app *Application) findCounties(f *utils.Filter) ([]*model.County, error) {
 	return counties, nil
 }
 
+func (app *Application) queryCounties(group string, q *utils.Query) ([]*model.County, string, error) {
+	all, counties := app.countiesScope(group, model.PermissionCountiesRead)
+	if !all {
+		if q == nil {
+			q = &utils.Query{}
+		}
+		values := make([]interface{}, len(counties))
+		for i, county := range counties {
+			values[i] = county
+		}
+		q.AddCondition("_id", utils.QueryOperatorIn, values...)
+	}
+	return app.storage.QueryCounties(q)
+}
+
 func (app *Application) getCounty(ID string) (*model.County, error) {
 	county, err := app.storage.FindCounty(ID)
 	if err != nil {
@@ -683,13 +845,22 @@ func (app *Application) getSymptomGroups() ([]*model.SymptomGroup, error) {
 
 //NewApplication creates new Application
 func NewApplication(version string, build string, dataProvider DataProvider, sender Sender, messaging Messaging,
-	profileBB ProfileBuildingBlock, rokmetro Rokmetro, storage Storage, audit Audit) *Application {
+	profileBB ProfileBuildingBlock, rokmetro Rokmetro, exposureNotification ExposureNotification, storage Storage, audit Audit,
+	sharedUsersCache UsersCache, usersCacheSize int, usersCacheTTL time.Duration) *Application {
 	cvLock := &sync.RWMutex{}
 	avLock := &sync.RWMutex{}
+	rolesLock := &sync.RWMutex{}
+	pcLock := &sync.RWMutex{}
+	akLock := &sync.RWMutex{}
+	akUsageLock := &sync.Mutex{}
+	ecLock := &sync.Mutex{}
+	usersCache := newUsersCache(sharedUsersCache, usersCacheSize, usersCacheTTL)
 	listeners := []ApplicationListener{}
 
 	application := Application{version: version, build: build, dataProvider: dataProvider, sender: sender, messaging: messaging,
-		profileBB: profileBB, rokmetro: rokmetro, storage: storage, audit: audit, cvLock: cvLock, avLock: avLock, listeners: listeners}
+		profileBB: profileBB, rokmetro: rokmetro, exposureNotification: exposureNotification, storage: storage, audit: audit, cvLock: cvLock, avLock: avLock,
+		rolesLock: rolesLock, pcLock: pcLock, akLock: akLock,
+		akUsageLock: akUsageLock, apiKeysUsage: map[string]time.Time{}, usersCache: usersCache, ecLock: ecLock, failedExposureCodeVerifications: map[string][]time.Time{}, listeners: listeners}
 
 	//add the drivers ports/interfaces
 	application.Services = &servicesImpl{app: &application}
@@ -0,0 +1,133 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+
+package core
+
+import (
+	"health/core/model"
+	"log"
+	"time"
+)
+
+const (
+	//used when the broadcast rate is not set in the config
+	defaultBroadcastRate int = 10
+
+	broadcastNotificationType string = "broadcast"
+
+	//the sending instance renews its claim periodically, a broadcast with an older claim is not sent by anyone
+	broadcastClaimRenewPeriod = time.Minute
+	broadcastClaimTimeout     = 10 * time.Minute
+)
+
+func (app *Application) setupBroadcastsTimer() {
+	log.Println("Application -> setupBroadcastsTimer")
+
+	//send the broadcasts which were due while the service was not running
+	app.sendDueBroadcasts()
+
+	//check it every minute
+	ticker := time.NewTicker(time.Minute)
+	for range ticker.C {
+		app.sendDueBroadcasts()
+	}
+}
+
+func (app *Application) sendDueBroadcasts() {
+	//the broadcasts of a stopped instance stay in sending, they are marked as failed as it is not known who received them
+	failedCount, err := app.storage.FailStaleBroadcasts(time.Now().Add(-broadcastClaimTimeout))
+	if err != nil {
+		log.Printf("error failing the stale broadcasts - %s", err)
+	} else if failedCount > 0 {
+		log.Printf("%d stale broadcasts marked as failed", failedCount)
+	}
+
+	for {
+		//the storage marks the broadcast as sending, so no other instance takes it
+		broadcast, err := app.storage.StartDueBroadcast(time.Now())
+		if err != nil {
+			log.Printf("error starting a due broadcast - %s", err)
+			return
+		}
+		if broadcast == nil {
+			//no more due broadcasts
+			return
+		}
+
+		app.sendBroadcast(*broadcast)
+	}
+}
+
+func (app *Application) sendBroadcast(broadcast model.Broadcast) {
+	log.Printf("sendBroadcast -> start sending %s", broadcast.ID)
+
+	//1. find the audience
+	uuids, err := app.storage.FindBroadcastAudience(broadcast.Target)
+	if err != nil {
+		log.Printf("sendBroadcast -> error finding the audience for %s - %s", broadcast.ID, err)
+
+		err = app.storage.UpdateBroadcastStatus(broadcast.ID, model.BroadcastStatusFailed, nil, nil, nil)
+		if err != nil {
+			log.Printf("sendBroadcast -> error updating the status for %s - %s", broadcast.ID, err)
+		}
+		return
+	}
+	audienceCount := len(uuids)
+
+	//2. fan out with the configured rate - every user needs a profile building block call
+	rate := defaultBroadcastRate
+	config := app.getCachedCovid19Config()
+	if config != nil && config.BroadcastRate > 0 {
+		rate = config.BroadcastRate
+	}
+	limiter := time.NewTicker(time.Second / time.Duration(rate))
+	defer limiter.Stop()
+
+	data := app.prepareNotificationData(broadcastNotificationType, broadcast.Title, broadcast.Body)
+	sentCount := 0
+	claimRenewed := time.Now()
+	for _, uuid := range uuids {
+		<-limiter.C
+
+		if time.Since(claimRenewed) >= broadcastClaimRenewPeriod {
+			claimRenewed = time.Now()
+			err = app.storage.RenewBroadcastClaim(broadcast.ID, sentCount, claimRenewed)
+			if err != nil {
+				log.Printf("sendBroadcast -> error renewing the claim for %s - %s", broadcast.ID, err)
+			}
+		}
+
+		userData, err := app.profileBB.LoadUserData(uuid)
+		if err != nil {
+			log.Printf("sendBroadcast -> error loading user data - %s", err)
+			continue
+		}
+		if len(userData.FCMTokens) == 0 {
+			continue
+		}
+		app.messaging.SendNotificationMessage(userData.FCMTokens, broadcast.Title, broadcast.Body, data)
+		sentCount++
+	}
+
+	//3. mark it as sent
+	dateSent := time.Now()
+	err = app.storage.UpdateBroadcastStatus(broadcast.ID, model.BroadcastStatusSent, &audienceCount, &sentCount, &dateSent)
+	if err != nil {
+		log.Printf("sendBroadcast -> error updating the status for %s - %s", broadcast.ID, err)
+	}
+	log.Printf("sendBroadcast -> %s sent to %d of %d users", broadcast.ID, sentCount, audienceCount)
+}
@@ -0,0 +1,183 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+
+package core
+
+import (
+	"health/core/model"
+	"health/utils"
+	"sort"
+	"time"
+)
+
+//used when the testing reminder days are not set in the config
+const defaultDueSoonDays int = 2
+
+//getComplianceItems gives the compliance items in the group counties scope. The items without county are given only to the admins
+//which are not limited to counties.
+func (app *Application) getComplianceItems(group string, now time.Time) ([]model.ComplianceItem, error) {
+	subjects, err := app.storage.FindTestingSubjects()
+	if err != nil {
+		return nil, err
+	}
+	all, counties := app.countiesScope(group, model.PermissionComplianceRead)
+
+	defaultInterval := 0
+	dueSoonDays := defaultDueSoonDays
+	config := app.getCachedCovid19Config()
+	if config != nil {
+		defaultInterval = config.TestingInterval
+		if config.TestingReminderDaysBefore > 0 {
+			dueSoonDays = config.TestingReminderDaysBefore
+		}
+	}
+
+	items := make([]model.ComplianceItem, 0, len(subjects))
+	for _, subject := range subjects {
+		if !all && (subject.CountyID == nil || !utils.Contains(counties, *subject.CountyID)) {
+			continue
+		}
+
+		item := model.ComplianceItem{UIN: subject.UIN, FirstName: subject.FirstName, LastName: subject.LastName,
+			CountyID: subject.CountyID, LatestTestDate: subject.LatestTestDate}
+		if subject.Override != nil {
+			item.Category = subject.Override.Category
+		}
+
+		dueDate := subject.GetDueDate(defaultInterval, now)
+		switch {
+		case dueDate == nil:
+			item.Status = model.ComplianceStatusExempt
+		case !now.Before(*dueDate):
+			item.Status = model.ComplianceStatusOverdue
+		case !now.Before(dueDate.AddDate(0, 0, -dueSoonDays)):
+			item.Status = model.ComplianceStatusDueSoon
+		default:
+			item.Status = model.ComplianceStatusCompliant
+		}
+		if dueDate != nil && !dueDate.IsZero() {
+			item.DueDate = dueDate
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func (app *Application) getComplianceSummary(group string) (*model.ComplianceSummary, error) {
+	now := time.Now()
+	items, err := app.getComplianceItems(group, now)
+	if err != nil {
+		return nil, err
+	}
+
+	//we need the counties names
+	counties, err := app.storage.FindCounties(nil)
+	if err != nil {
+		return nil, err
+	}
+	countiesNames := make(map[string]string, len(counties))
+	for _, county := range counties {
+		countiesNames[county.ID] = county.Name
+	}
+
+	summary := model.ComplianceSummary{GeneratedAt: now}
+	byCategory := map[string]*model.ComplianceGroupCounts{}
+	byCounty := map[string]*model.ComplianceGroupCounts{}
+	for _, item := range items {
+		summary.Total.Add(item.Status)
+
+		category := ""
+		if item.Category != nil {
+			category = *item.Category
+		}
+		categoryCounts, ok := byCategory[category]
+		if !ok {
+			categoryCounts = &model.ComplianceGroupCounts{Key: category, Name: category}
+			byCategory[category] = categoryCounts
+		}
+		categoryCounts.Counts.Add(item.Status)
+
+		countyID := ""
+		if item.CountyID != nil {
+			countyID = *item.CountyID
+		}
+		countyCounts, ok := byCounty[countyID]
+		if !ok {
+			countyCounts = &model.ComplianceGroupCounts{Key: countyID, Name: countiesNames[countyID]}
+			byCounty[countyID] = countyCounts
+		}
+		countyCounts.Counts.Add(item.Status)
+	}
+
+	summary.ByCategory = app.sortComplianceGroups(byCategory)
+	summary.ByCounty = app.sortComplianceGroups(byCounty)
+	return &summary, nil
+}
+
+func (app *Application) sortComplianceGroups(groups map[string]*model.ComplianceGroupCounts) []model.ComplianceGroupCounts {
+	result := make([]model.ComplianceGroupCounts, 0, len(groups))
+	for _, group := range groups {
+		result = append(result, *group)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Key < result[j].Key
+	})
+	return result
+}
+
+func (app *Application) findComplianceItems(group string, status *string, category *string, countyID *string, limit int, offset int) ([]model.ComplianceItem, int, error) {
+	if countyID != nil {
+		err := app.checkCountyScope(group, model.PermissionComplianceRead, *countyID)
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+
+	items, err := app.getComplianceItems(group, time.Now())
+	if err != nil {
+		return nil, 0, err
+	}
+
+	//filter
+	filtered := make([]model.ComplianceItem, 0, len(items))
+	for _, item := range items {
+		if status != nil && item.Status != *status {
+			continue
+		}
+		if category != nil && (item.Category == nil || *item.Category != *category) {
+			continue
+		}
+		if countyID != nil && (item.CountyID == nil || *item.CountyID != *countyID) {
+			continue
+		}
+		filtered = append(filtered, item)
+	}
+	sort.Slice(filtered, func(i, j int) bool {
+		return filtered[i].UIN < filtered[j].UIN
+	})
+	total := len(filtered)
+
+	//paginate - 0 limit means all
+	if offset > total {
+		offset = total
+	}
+	end := total
+	if limit > 0 && offset+limit < total {
+		end = offset + limit
+	}
+	return filtered[offset:end], total, nil
+}
@@ -0,0 +1,234 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+
+package core
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"health/core/model"
+	"health/utils"
+	"log"
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	exposureCodeLength = 8
+	//used if not set in the config
+	exposureDefaultCodeLifetime     = 60 //minutes
+	exposureDefaultCodesHourlyLimit = 50
+
+	exposureUploadTokenLifetime = 24 * time.Hour
+
+	//failed code verifications per user per hour, it prevents guessing the codes
+	exposureMaxFailedVerifications = 10
+)
+
+//ErrUploadTokenNotValid is given when the exposures upload token is not valid, expired or already used
+var ErrUploadTokenNotValid = errors.New("the upload token is not valid or has expired")
+
+func (app *Application) issueExposureVerificationCode(current model.User, group string, audit *string,
+	reportType string, testDate time.Time, symptomOnsetDate *time.Time) (*model.ExposureVerificationCode, string, error) {
+	userIdentifier, userInfo := current.GetLogData()
+
+	//1. check the issuer limit
+	_, hourlyLimit, _ := app.getExposureCodesSettings()
+	count, err := app.storage.CountExposureVerificationCodes(userIdentifier, time.Now().Add(-time.Hour))
+	if err != nil {
+		return nil, "", err
+	}
+	if count >= int64(hourlyLimit) {
+		return nil, "", fmt.Errorf("the limit of %d exposure codes per hour is reached", hourlyLimit)
+	}
+
+	//2. issue it
+	item, code, err := app.createExposureVerificationCode(reportType, testDate, symptomOnsetDate, nil, model.ExposureCodeSourceAdmin, userIdentifier)
+	if err != nil {
+		return nil, "", err
+	}
+
+	//audit - the code itself is not logged
+	lData := []AuditDataEntry{{Key: "reportType", Value: reportType}, {Key: "testDate", Value: utils.GetTime(&testDate)},
+		{Key: "symptomOnsetDate", Value: utils.GetTime(symptomOnsetDate)}, {Key: "expiresAt", Value: utils.GetTime(&item.ExpiresAt)}}
+	err = app.audit.LogCreateEvent(userIdentifier, userInfo, group, "exposure-verification-code", item.ID, lData, audit)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return item, code, nil
+}
+
+func (app *Application) getExposureVerificationCodes(issuedBy *string, status *string, limit int64) ([]model.ExposureVerificationCode, error) {
+	return app.storage.FindExposureVerificationCodes(issuedBy, status, limit)
+}
+
+//issueCTestExposureVerificationCode issues a code which only the ctest user can claim.
+//The ctest result is encrypted for the user, so a code is issued only when the sender explicitly marked the result as positive.
+func (app *Application) issueCTestExposureVerificationCode(userID string, providerID string, positive bool, issuedBy string) (*string, error) {
+	_, _, autoIssue := app.getExposureCodesSettings()
+	if !autoIssue || !positive {
+		return nil, nil
+	}
+
+	_, code, err := app.createExposureVerificationCode(model.ExposureReportTypeConfirmed, time.Now(), nil, &userID,
+		model.ExposureCodeSourceCTest, issuedBy)
+	if err != nil {
+		return nil, err
+	}
+	return &code, nil
+}
+
+func (app *Application) createExposureVerificationCode(reportType string, testDate time.Time, symptomOnsetDate *time.Time,
+	userID *string, source string, issuedBy string) (*model.ExposureVerificationCode, string, error) {
+	if reportType != model.ExposureReportTypeConfirmed && reportType != model.ExposureReportTypeLikely {
+		return nil, "", fmt.Errorf("not supported report type %s", reportType)
+	}
+	now := time.Now()
+	if testDate.After(now) || testDate.Before(now.Add(-exposureKeysMaxAge)) {
+		return nil, "", errors.New("the test date must be within the last 14 days")
+	}
+	if symptomOnsetDate != nil && (symptomOnsetDate.After(now) || symptomOnsetDate.Before(now.Add(-exposureKeysMaxAge))) {
+		return nil, "", errors.New("the symptom onset date must be within the last 14 days")
+	}
+
+	lifetime, _, _ := app.getExposureCodesSettings()
+	item := model.ExposureVerificationCode{ReportType: reportType, TestDate: testDate, SymptomOnsetDate: symptomOnsetDate,
+		UserID: userID, Source: source, IssuedBy: issuedBy, Status: model.ExposureCodeStatusIssued,
+		ExpiresAt: now.Add(lifetime), DateCreated: now}
+
+	//the codes are short, so retry on a collision with an existing one
+	for i := 0; i < 5; i++ {
+		code, err := generateExposureCode()
+		if err != nil {
+			return nil, "", err
+		}
+		item.ID = uuid.New().String()
+		item.CodeHash = hashExposureSecret(code)
+
+		created, err := app.storage.CreateExposureVerificationCode(item)
+		if err != nil {
+			return nil, "", err
+		}
+		if created {
+			return &item, code, nil
+		}
+	}
+	return nil, "", errors.New("cannot generate a unique exposure code")
+}
+
+func (app *Application) verifyExposureCode(current model.User, code string) (*model.ExposureVerificationCode, string, error) {
+	if !app.allowExposureCodeVerification(current.ID) {
+		return nil, "", errors.New("too many failed exposure code verifications, try again later")
+	}
+
+	token, err := generateExposureUploadToken()
+	if err != nil {
+		return nil, "", err
+	}
+
+	item, err := app.storage.ClaimExposureVerificationCode(hashExposureSecret(code), current.ID,
+		hashExposureSecret(token), time.Now().Add(exposureUploadTokenLifetime))
+	if err != nil {
+		return nil, "", err
+	}
+	if item == nil {
+		app.recordFailedExposureCodeVerification(current.ID)
+		return nil, "", errors.New("the exposure code is not valid or has expired")
+	}
+
+	log.Printf("exposure code %s claimed by %s", item.ID, current.ID)
+	return item, token, nil
+}
+
+func (app *Application) allowExposureCodeVerification(userID string) bool {
+	app.ecLock.Lock()
+	defer app.ecLock.Unlock()
+
+	//keep only the last hour attempts
+	from := time.Now().Add(-time.Hour)
+	var attempts []time.Time
+	for _, attempt := range app.failedExposureCodeVerifications[userID] {
+		if attempt.After(from) {
+			attempts = append(attempts, attempt)
+		}
+	}
+	if len(attempts) == 0 {
+		delete(app.failedExposureCodeVerifications, userID)
+	} else {
+		app.failedExposureCodeVerifications[userID] = attempts
+	}
+	return len(attempts) < exposureMaxFailedVerifications
+}
+
+func (app *Application) recordFailedExposureCodeVerification(userID string) {
+	app.ecLock.Lock()
+	defer app.ecLock.Unlock()
+
+	app.failedExposureCodeVerifications[userID] = append(app.failedExposureCodeVerifications[userID], time.Now())
+}
+
+func (app *Application) getExposureCodesSettings() (time.Duration, int, bool) {
+	lifetime := exposureDefaultCodeLifetime
+	hourlyLimit := exposureDefaultCodesHourlyLimit
+	autoIssue := false
+
+	config := app.getCachedCovid19Config()
+	if config != nil {
+		if config.ExposureCodeLifetime > 0 {
+			lifetime = config.ExposureCodeLifetime
+		}
+		if config.ExposureCodesHourlyLimit > 0 {
+			hourlyLimit = config.ExposureCodesHourlyLimit
+		}
+		autoIssue = config.ExposureCodesAutoIssue
+	}
+	return time.Duration(lifetime) * time.Minute, hourlyLimit, autoIssue
+}
+
+func generateExposureCode() (string, error) {
+	max := big.NewInt(1)
+	for i := 0; i < exposureCodeLength; i++ {
+		max.Mul(max, big.NewInt(10))
+	}
+	n, err := rand.Int(rand.Reader, max)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%0"+strconv.Itoa(exposureCodeLength)+"d", n), nil
+}
+
+func generateExposureUploadToken() (string, error) {
+	b := make([]byte, 32)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashExposureSecret(secret string) string {
+	hash := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(hash[:])
+}
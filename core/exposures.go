@@ -0,0 +1,303 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+
+package core
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"health/core/model"
+	"log"
+	"time"
+)
+
+const (
+	//the key rolling interval is 10 minutes
+	exposureIntervalDuration = 10 * time.Minute
+	//a key is valid for one day at most
+	exposureMaxRollingPeriod = 144
+	//the keys older than 14 days are not accepted nor exported
+	exposureKeysMaxAge = 14 * 24 * time.Hour
+
+	exposureKeyLength           = 16
+	exposureMaxKeysPerUpload    = 30
+	exposureMaxTransmissionRisk = 8
+
+	//the maximum keys count in one export file
+	exposureExportBatchSize = 10000
+	//used if not set in the config
+	exposureDefaultExportWindow = 24 //hours
+	//used if not set in the config
+	exposureDefaultRetentionDays = 14
+
+	//the exposures pages do not give the last added exposures until they are older than the lag. It is longer than an insert transaction
+	//can take(60 seconds by default) with the clocks difference between the instances.
+	exposuresPageLag = 2 * time.Minute
+)
+
+//ErrExposureCertificateUsed is given when the exposure keys are published with a verification certificate which has been already used
+var ErrExposureCertificateUsed = errors.New("the verification certificate has been already used")
+
+func (app *Application) publishExposureKeys(keys []model.TraceExposure, certificate string, hmacKey string) (int, error) {
+	err := app.validateExposureKeys(keys)
+	if err != nil {
+		return 0, err
+	}
+
+	verification, err := app.exposureNotification.VerifyCertificate(certificate, hmacKey, keys)
+	if err != nil {
+		return 0, err
+	}
+
+	//fill the data given by the verification and the fields used by the legacy clients.
+	//There is no expirestamp, the published keys are kept for the whole retention period.
+	now := time.Now().UnixNano() / 1000000 //we need milliseconds
+	intervalMS := int64(exposureIntervalDuration / time.Millisecond)
+	for i := range keys {
+		key := &keys[i]
+		key.DateAdded = now
+		key.Timestamp = int64(*key.RollingStartNumber) * intervalMS
+		key.ReportType = &verification.ReportType
+		if verification.SymptomOnsetInterval != nil {
+			daysSinceOnset := (*key.RollingStartNumber - *verification.SymptomOnsetInterval) / exposureMaxRollingPeriod
+			key.DaysSinceOnset = &daysSinceOnset
+		}
+	}
+
+	//the certificate is used once, the keys which have been already published are skipped
+	created, insertedCount, err := app.storage.CreateGAENTraceReports(hashExposureSecret(certificate), verification.ExpiresAt, keys)
+	if err != nil {
+		return 0, err
+	}
+	if !created {
+		return 0, ErrExposureCertificateUsed
+	}
+	return insertedCount, nil
+}
+
+func (app *Application) validateExposureKeys(keys []model.TraceExposure) error {
+	if len(keys) == 0 {
+		return errors.New("no exposure keys")
+	}
+	if len(keys) > exposureMaxKeysPerUpload {
+		return fmt.Errorf("too many exposure keys, the maximum is %d", exposureMaxKeysPerUpload)
+	}
+
+	now := time.Now()
+	currentInterval := int32(now.Unix() / int64(exposureIntervalDuration/time.Second))
+	minInterval := int32(now.Add(-exposureKeysMaxAge).Unix() / int64(exposureIntervalDuration/time.Second))
+
+	keysData := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		keyData, err := base64.StdEncoding.DecodeString(key.TEK)
+		if err != nil || len(keyData) != exposureKeyLength {
+			return fmt.Errorf("the exposure key %s is not valid", key.TEK)
+		}
+		if keysData[key.TEK] {
+			return fmt.Errorf("the exposure key %s is duplicated", key.TEK)
+		}
+		keysData[key.TEK] = true
+
+		if key.RollingStartNumber == nil || key.RollingPeriod == nil || key.TransmissionRisk == nil {
+			return fmt.Errorf("missing data for the exposure key %s", key.TEK)
+		}
+		if *key.RollingPeriod < 1 || *key.RollingPeriod > exposureMaxRollingPeriod {
+			return fmt.Errorf("the rolling period for the exposure key %s must be between 1 and %d", key.TEK, exposureMaxRollingPeriod)
+		}
+		if *key.RollingStartNumber > currentInterval {
+			return fmt.Errorf("the exposure key %s is from the future", key.TEK)
+		}
+		if *key.RollingStartNumber+*key.RollingPeriod < minInterval {
+			return fmt.Errorf("the exposure key %s is too old", key.TEK)
+		}
+		if *key.TransmissionRisk < 0 || *key.TransmissionRisk > exposureMaxTransmissionRisk {
+			return fmt.Errorf("the transmission risk for the exposure key %s must be between 0 and %d", key.TEK, exposureMaxTransmissionRisk)
+		}
+	}
+	return nil
+}
+
+//getExposureExportIndex gives the names of the export files for the completed windows of the last 14 days
+func (app *Application) getExposureExportIndex() ([]string, error) {
+	window := app.getExposureExportWindow()
+	now := time.Now().UTC()
+
+	//the windows are aligned to the unix epoch
+	end := truncateExposureWindow(now, window)
+	start := truncateExposureWindow(now.Add(-exposureKeysMaxAge), window)
+
+	var names []string
+	for windowStart := start; windowStart.Before(end); windowStart = windowStart.Add(window) {
+		windowEnd := windowStart.Add(window)
+		count, err := app.storage.CountGAENTraceExposures(toMilliseconds(windowStart), toMilliseconds(windowEnd))
+		if err != nil {
+			return nil, err
+		}
+		if count == 0 {
+			continue
+		}
+
+		batchSize := int((count + exposureExportBatchSize - 1) / exposureExportBatchSize)
+		for batchNum := 1; batchNum <= batchSize; batchNum++ {
+			names = append(names, formatExposureExportName(windowStart, windowEnd, batchNum))
+		}
+	}
+	return names, nil
+}
+
+//getExposureExport gives the signed export archive for the provided window and batch
+func (app *Application) getExposureExport(start int64, end int64, batchNum int) ([]byte, error) {
+	window := app.getExposureExportWindow()
+	windowStart := time.Unix(start, 0).UTC()
+	windowEnd := time.Unix(end, 0).UTC()
+
+	//only the completed windows from the index are served
+	if !windowStart.Equal(truncateExposureWindow(windowStart, window)) || !windowEnd.Equal(windowStart.Add(window)) ||
+		windowEnd.After(time.Now()) || batchNum < 1 {
+		return nil, nil
+	}
+
+	count, err := app.storage.CountGAENTraceExposures(toMilliseconds(windowStart), toMilliseconds(windowEnd))
+	if err != nil {
+		return nil, err
+	}
+	batchSize := int((count + exposureExportBatchSize - 1) / exposureExportBatchSize)
+	if batchNum > batchSize {
+		return nil, nil
+	}
+
+	keys, err := app.storage.ReadGAENTraceExposures(toMilliseconds(windowStart), toMilliseconds(windowEnd),
+		int64((batchNum-1)*exposureExportBatchSize), exposureExportBatchSize)
+	if err != nil {
+		return nil, err
+	}
+
+	return app.exposureNotification.CreateExport(keys, windowStart, windowEnd, batchNum, batchSize)
+}
+
+func (app *Application) getExposureExportWindow() time.Duration {
+	hours := exposureDefaultExportWindow
+	config := app.getCachedCovid19Config()
+	if config != nil && config.ExposureExportWindow > 0 {
+		hours = config.ExposureExportWindow
+	}
+	return time.Duration(hours) * time.Hour
+}
+
+func (app *Application) setupExposuresPurgeTimer() {
+	log.Println("Application -> setupExposuresPurgeTimer")
+
+	//purge for first time
+	app.purgeExposures()
+
+	//purge every hour
+	ticker := time.NewTicker(time.Hour)
+	for range ticker.C {
+		app.purgeExposures()
+	}
+}
+
+func (app *Application) purgeExposures() {
+	now := time.Now()
+	retentionFrom := now.Add(-app.getExposureRetention())
+
+	expiredCount, retentionCount, err := app.storage.PurgeTraceExposures(toMilliseconds(now), toMilliseconds(retentionFrom))
+	if err != nil {
+		log.Printf("purgeExposures -> error purging the exposures - %s", err)
+		return
+	}
+	log.Printf("purgeExposures -> %d expired and %d out of retention exposures purged", expiredCount, retentionCount)
+
+	purge := model.ExposurePurge{Date: now, ExpiredCount: expiredCount, RetentionCount: retentionCount}
+	err = app.storage.CreateExposurePurge(purge)
+	if err != nil {
+		log.Printf("purgeExposures -> error recording the purge - %s", err)
+	}
+}
+
+//getExposureMetrics gives the exposures counts and the purges for the last days
+func (app *Application) getExposureMetrics(days int) (*model.ExposureMetrics, error) {
+	totalCount, err := app.storage.CountTraceExposures(false)
+	if err != nil {
+		return nil, err
+	}
+	gaenCount, err := app.storage.CountTraceExposures(true)
+	if err != nil {
+		return nil, err
+	}
+	purges, err := app.storage.FindExposurePurges(time.Now().AddDate(0, 0, -days))
+	if err != nil {
+		return nil, err
+	}
+
+	var purgedCount int64
+	for _, purge := range purges {
+		purgedCount += purge.ExpiredCount + purge.RetentionCount
+	}
+	if purges == nil {
+		purges = make([]model.ExposurePurge, 0)
+	}
+
+	retentionDays := int(app.getExposureRetention() / (24 * time.Hour))
+	return &model.ExposureMetrics{TotalCount: totalCount, GAENCount: gaenCount, RetentionDays: retentionDays,
+		PurgedCount: purgedCount, Purges: purges}, nil
+}
+
+func (app *Application) getExposureRetention() time.Duration {
+	days := exposureDefaultRetentionDays
+	config := app.getCachedCovid19Config()
+	if config != nil && config.ExposureRetentionDays > 0 {
+		days = config.ExposureRetentionDays
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+func encodeExposuresCursor(cursor model.TraceExposureCursor) (string, error) {
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodeExposuresCursor(value string) (*model.TraceExposureCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, errors.New("the cursor is not valid")
+	}
+	var cursor model.TraceExposureCursor
+	err = json.Unmarshal(data, &cursor)
+	if err != nil || len(cursor.ID) != 24 {
+		return nil, errors.New("the cursor is not valid")
+	}
+	return &cursor, nil
+}
+
+func truncateExposureWindow(t time.Time, window time.Duration) time.Time {
+	seconds := int64(window / time.Second)
+	return time.Unix(t.Unix()-t.Unix()%seconds, 0).UTC()
+}
+
+func formatExposureExportName(start time.Time, end time.Time, batchNum int) string {
+	return fmt.Sprintf("%d-%d-%05d.zip", start.Unix(), end.Unix(), batchNum)
+}
+
+func toMilliseconds(t time.Time) int64 {
+	return t.UnixNano() / 1000000
+}
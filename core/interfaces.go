@@ -53,7 +53,7 @@ type Services interface {
 	UpdateEHistory(accountID string, ID string, date *time.Time, encryptedKey *string, encryptedBlob *string) (*model.EHistory, error)
 
 	GetCTests(account model.Account, processed bool) ([]*model.CTest, []*model.Provider, error)
-	CreateExternalCTest(providerID string, uin string, encryptedKey string, encryptedBlob string, orderNumber *string) error
+	CreateExternalCTest(credential *model.ProviderCredential, providerID string, uin string, encryptedKey string, encryptedBlob string, orderNumber *string, positive bool) error
 	DeleteCTests(accountID string) (int64, error)
 	UpdateCTest(account model.Account, ID string, processed bool) (*model.CTest, error)
 
@@ -79,8 +79,13 @@ type Services interface {
 	GetCRulesByCounty(appVersion *string, countyID string) (*model.CRules, error)
 	GetAccessRuleByCounty(countyID string) (*model.AccessRule, []*model.CountyStatus, error)
 
-	AddTraceReport(items []model.TraceExposure) (int, error)
+	AddTraceReport(items []model.TraceExposure, uploadToken string) (int, error)
 	GetExposures(timestamp *int64, dateAdded *int64) ([]model.TraceExposure, error)
+	GetExposuresPage(timestamp *int64, cursor *string, limit int) ([]model.TraceExposure, string, error)
+	PublishExposureKeys(keys []model.TraceExposure, certificate string, hmacKey string) (int, error)
+	GetExposureExportIndex() ([]string, error)
+	GetExposureExport(start int64, end int64, batchNum int) ([]byte, error)
+	VerifyExposureCode(current model.User, code string) (*model.ExposureVerificationCode, string, error)
 
 	GetUINOverride(account model.Account, v2 bool) (*model.UINOverride, error)
 	CreateOrUpdateUINOverride(account model.Account, interval int, category *string, activation *time.Time, expiration *time.Time) error
@@ -93,7 +98,11 @@ type Services interface {
 	SetUINBuildingAccess(account model.Account, date time.Time, access string) error
 	GetExtUINBuildingAccess(uin string) (*model.UINBuildingAccess, error)
 
-	GetRosterByPhone(phone string) (map[string]string, error)
+	GetRosterByPhone(phone string) (*model.Roster, error)
+
+	GetPendingSubAccounts(current model.User) ([]model.RawSubAccount, error)
+	AcceptSubAccount(current model.User, uin string) (*model.Account, error)
+	DeclineSubAccount(current model.User, uin string) error
 
 	GetExtJoinExternalApproval(account model.Account) ([]RokmetroJoinGroupExtApprovement, error)
 	UpdateExtJoinExternalApprovement(jeaID string, status string) error
@@ -180,8 +189,8 @@ func (s *servicesImpl) GetCTests(account model.Account, processed bool) ([]*mode
 	return s.app.getCTests(account, processed)
 }
 
-func (s *servicesImpl) CreateExternalCTest(providerID string, uin string, encryptedKey string, encryptedBlob string, orderNumber *string) error {
-	return s.app.createExternalCTest(providerID, uin, encryptedKey, encryptedBlob, orderNumber)
+func (s *servicesImpl) CreateExternalCTest(credential *model.ProviderCredential, providerID string, uin string, encryptedKey string, encryptedBlob string, orderNumber *string, positive bool) error {
+	return s.app.createExternalCTest(credential, providerID, uin, encryptedKey, encryptedBlob, orderNumber, positive)
 }
 
 func (s *servicesImpl) DeleteCTests(accountID string) (int64, error) {
@@ -252,14 +261,34 @@ func (s *servicesImpl) GetAccessRuleByCounty(countyID string) (*model.AccessRule
 	return s.app.getAccessRuleByCounty(countyID)
 }
 
-func (s *servicesImpl) AddTraceReport(items []model.TraceExposure) (int, error) {
-	return s.app.аddTraceReport(items)
+func (s *servicesImpl) AddTraceReport(items []model.TraceExposure, uploadToken string) (int, error) {
+	return s.app.аddTraceReport(items, uploadToken)
 }
 
 func (s *servicesImpl) GetExposures(timestamp *int64, dateAdded *int64) ([]model.TraceExposure, error) {
 	return s.app.getExposures(timestamp, dateAdded)
 }
 
+func (s *servicesImpl) GetExposuresPage(timestamp *int64, cursor *string, limit int) ([]model.TraceExposure, string, error) {
+	return s.app.getExposuresPage(timestamp, cursor, limit)
+}
+
+func (s *servicesImpl) PublishExposureKeys(keys []model.TraceExposure, certificate string, hmacKey string) (int, error) {
+	return s.app.publishExposureKeys(keys, certificate, hmacKey)
+}
+
+func (s *servicesImpl) GetExposureExportIndex() ([]string, error) {
+	return s.app.getExposureExportIndex()
+}
+
+func (s *servicesImpl) GetExposureExport(start int64, end int64, batchNum int) ([]byte, error) {
+	return s.app.getExposureExport(start, end, batchNum)
+}
+
+func (s *servicesImpl) VerifyExposureCode(current model.User, code string) (*model.ExposureVerificationCode, string, error) {
+	return s.app.verifyExposureCode(current, code)
+}
+
 func (s *servicesImpl) GetUINOverride(account model.Account, v2 bool) (*model.UINOverride, error) {
 	return s.app.getUINOverride(account, v2)
 }
@@ -292,10 +321,22 @@ func (s *servicesImpl) GetExtUINBuildingAccess(uin string) (*model.UINBuildingAc
 	return s.app.getExtUINBuildingAccess(uin)
 }
 
-func (s *servicesImpl) GetRosterByPhone(phone string) (map[string]string, error) {
+func (s *servicesImpl) GetRosterByPhone(phone string) (*model.Roster, error) {
 	return s.app.getRosterByPhone(phone)
 }
 
+func (s *servicesImpl) GetPendingSubAccounts(current model.User) ([]model.RawSubAccount, error) {
+	return s.app.getPendingSubAccounts(current)
+}
+
+func (s *servicesImpl) AcceptSubAccount(current model.User, uin string) (*model.Account, error) {
+	return s.app.acceptSubAccount(current, uin)
+}
+
+func (s *servicesImpl) DeclineSubAccount(current model.User, uin string) error {
+	return s.app.declineSubAccount(current, uin)
+}
+
 func (s *servicesImpl) GetExtJoinExternalApproval(account model.Account) ([]RokmetroJoinGroupExtApprovement, error) {
 	return s.app.getExtJoinExternalApproval(account)
 }
@@ -340,12 +381,22 @@ type Administration interface {
 	DeleteFAQSection(current model.User, group string, ID string) error
 	UpdateFAQSection(current model.User, group string, audit *string, ID string, title string, displayOrder int) error
 
-	GetProviders() ([]*model.Provider, error)
+	QueryProviders(q *utils.Query) ([]*model.Provider, string, error)
 	CreateProvider(current model.User, group string, audit *string, providerName string, manualTest bool, availableMechanisms []string) (*model.Provider, error)
 	UpdateProvider(current model.User, group string, audit *string, ID string, providerName string, manualTest bool, availableMechanisms []string) (*model.Provider, error)
 	DeleteProvider(current model.User, group string, ID string) error
 
-	FindCounties(f *utils.Filter) ([]*model.County, error)
+	GetProviderCredentials(providerID string) ([]model.ProviderCredential, error)
+	CreateProviderCredential(current model.User, group string, audit *string, providerID string, requireSignature bool, rateLimit int, scopes []string, expires *time.Time) (*model.ProviderCredential, string, error)
+	RotateProviderCredential(current model.User, group string, audit *string, ID string, gracePeriodHours *int) (*model.ProviderCredential, string, error)
+	RevokeProviderCredential(current model.User, group string, audit *string, ID string) error
+
+	GetAPIKeys() ([]model.APIKey, error)
+	CreateAPIKey(current model.User, group string, audit *string, name string, owner string, scopes []string, expires *time.Time) (*model.APIKey, string, error)
+	RotateAPIKey(current model.User, group string, audit *string, ID string, gracePeriodHours *int) (*model.APIKey, string, error)
+	RevokeAPIKey(current model.User, group string, audit *string, ID string) error
+
+	QueryCounties(group string, q *utils.Query) ([]*model.County, string, error)
 	CreateCounty(current model.User, group string, audit *string, name string, stateProvince string, country string) (*model.County, error)
 	UpdateCounty(current model.User, group string, audit *string, ID string, name string, stateProvince string, country string) (*model.County, error)
 	DeleteCounty(current model.User, group string, ID string) error
@@ -353,12 +404,12 @@ type Administration interface {
 	CreateGuideline(current model.User, group string, audit *string, countyID string, name string, description string, items []model.GuidelineItem) (*model.Guideline, error)
 	UpdateGuideline(current model.User, group string, audit *string, ID string, name string, description string, items []model.GuidelineItem) (*model.Guideline, error)
 	DeleteGuideline(current model.User, group string, ID string) error
-	GetGuidelinesByCountyID(countyID string) ([]*model.Guideline, error)
+	GetGuidelinesByCountyID(group string, countyID string) ([]*model.Guideline, error)
 
 	CreateCountyStatus(current model.User, group string, audit *string, countyID string, name string, description string) (*model.CountyStatus, error)
 	UpdateCountyStatus(current model.User, group string, audit *string, ID string, name string, description string) (*model.CountyStatus, error)
 	DeleteCountyStatus(current model.User, group string, ID string) error
-	GetCountyStatusByCountyID(countyID string) ([]*model.CountyStatus, error)
+	GetCountyStatusByCountyID(group string, countyID string) ([]*model.CountyStatus, error)
 
 	GetTestTypes() ([]*model.TestType, error)
 	CreateTestType(current model.User, group string, audit *string, name string, priority *int) (*model.TestType, error)
@@ -370,12 +421,12 @@ type Administration interface {
 	DeleteTestTypeResult(current model.User, group string, ID string) error
 	GetTestTypeResultsByTestTypeID(testTypeID string) ([]*model.TestTypeResult, error)
 
-	GetRules() ([]*model.Rule, error)
+	GetRules(group string) ([]*model.Rule, error)
 	CreateRule(current model.User, group string, audit *string, countyID string, testTypeID string, priority *int, resultsStates []model.TestTypeResultCountyStatus) (*model.Rule, error)
 	UpdateRule(current model.User, group string, audit *string, ID string, priority *int, resultsStates []model.TestTypeResultCountyStatus) (*model.Rule, error)
 	DeleteRule(current model.User, group string, ID string) error
 
-	GetLocations() ([]*model.Location, error)
+	QueryLocations(group string, q *utils.Query) ([]*model.Location, string, error)
 	CreateLocation(current model.User, group string, audit *string, providerID string, countyID string, name string, address1 string, address2 string, city string,
 		state string, zip string, country string, latitude float64, longitude float64, contact string,
 		daysOfOperation []model.OperationDay, url string, notes string, waitTimeColor *string, availableTests []string) (*model.Location, error)
@@ -390,38 +441,39 @@ type Administration interface {
 
 	GetSymptomGroups() ([]*model.SymptomGroup, error)
 
-	GetSymptomRules() ([]*model.SymptomRule, error)
+	GetSymptomRules(group string) ([]*model.SymptomRule, error)
 	CreateSymptomRule(current model.User, group string, countyID string, gr1Count int, gr2Count int, items []model.SymptomRuleItem) (*model.SymptomRule, error)
 	UpdateSymptomRule(current model.User, group string, ID string, countyID string, gr1Count int, gr2Count int, items []model.SymptomRuleItem) (*model.SymptomRule, error)
 	DeleteSymptomRule(current model.User, group string, ID string) error
 
-	GetManualTestByCountyID(countyID string, status *string) ([]*model.EManualTest, error)
-	ProcessManualTest(ID string, status string, encryptedKey *string, encryptedBlob *string, date *time.Time) error
-	GetManualTestImage(ID string) (*string, *string, error)
+	QueryManualTests(group string, countyID string, q *utils.Query) ([]*model.EManualTest, string, error)
+	ProcessManualTest(group string, ID string, status string, encryptedKey *string, encryptedBlob *string, date *time.Time) error
+	GetManualTestImage(group string, ID string) (*string, *string, error)
 
-	GetAccessRules() ([]*model.AccessRule, error)
+	GetAccessRules(group string) ([]*model.AccessRule, error)
 	CreateAccessRule(current model.User, group string, audit *string, countyID string, rules []model.AccessRuleCountyStatus) (*model.AccessRule, error)
 	UpdateAccessRule(current model.User, group string, audit *string, ID string, countyID string, rules []model.AccessRuleCountyStatus) (*model.AccessRule, error)
 	DeleteAccessRule(current model.User, group string, ID string) error
 
-	GetCRules(countyID string, appVersion string) (*model.CRules, error)
+	GetCRules(group string, countyID string, appVersion string) (*model.CRules, error)
 	CreateOrUpdateCRules(current model.User, group string, audit *string, countyID string, appVersion string, data string) error
 
 	GetSymptoms(appVersion string) (*model.Symptoms, error)
 	CreateOrUpdateSymptoms(current model.User, group string, audit *string, appVersion string, items string) error
 
-	GetUINOverrides(uin *string, sort *string) ([]*model.UINOverride, error)
+	QueryUINOverrides(q *utils.Query) ([]*model.UINOverride, string, error)
 	CreateUINOverride(current model.User, group string, audit *string, uin string, exempt *bool, interval *int, category *string, activation *time.Time, expiration *time.Time) (*model.UINOverride, error)
 	UpdateUINOverride(current model.User, group string, audit *string, uin string, exempt *bool, interval *int, category *string, activation *time.Time, expiration *time.Time) (*string, error)
 	DeleteUINOverride(current model.User, group string, uin string) error
 
-	CreateRoster(current model.User, group string, audit *string, phone string, uin string, firstName string,
-		middleName string, lastName string, birthDate string, gender string, address1 string, address2 string,
-		address3 string, city string, state string, zipCode string, email string, badgeType string) error
-	UpdateRoster(current model.User, group string, audit *string, uin string, firstName string, middleName string, lastName string, birthDate string, gender string,
-		address1 string, address2 string, address3 string, city string, state string, zipCode string, email string, badgeType string) error
-	CreateRosterItems(current model.User, group string, audit *string, items []map[string]string) error
-	GetRosters(filter *utils.Filter, sortBy string, sortOrder int, limit int, offset int) ([]map[string]interface{}, error)
+	CreateRoster(current model.User, group string, audit *string, roster model.Roster) error
+	UpdateRoster(current model.User, group string, audit *string, roster model.Roster) error
+	CreateRosterItems(current model.User, group string, audit *string, items []model.Roster) error
+	GetRosters(filter *utils.Filter, sortBy string, sortOrder int, limit int, offset int) ([]model.Roster, error)
+	QueryRosters(q *utils.Query) ([]model.Roster, string, error)
+	ImportRoster(current model.User, group string, audit *string, fileName string, rows [][]string, mapping map[string]string, dryRun bool) (*model.RosterImport, error)
+	GetRosterImport(ID string) (*model.RosterImport, error)
+	SyncRosters(current model.User, group string, audit *string, items []model.Roster, dryRun bool) (*model.RosterSync, error)
 	DeleteRosterByPhone(current model.User, group string, phone string) error
 	DeleteRosterByUIN(current model.User, group string, uin string) error
 	DeleteAllRosters(current model.User, group string) error
@@ -430,15 +482,41 @@ type Administration interface {
 	GetRawSubAccounts(filter *utils.Filter, sortBy string, sortOrder int, limit int, offset int) ([]model.RawSubAccount, error)
 	UpdateRawSubAccount(current model.User, group string, audit *string, uin string, firstName string, middleName string, lastName string, birthDate string, gender string,
 		address1 string, address2 string, address3 string, city string, state string, zipCode string, phone string, netID string, email string) error
+	RevokeRawSubAccount(current model.User, group string, audit *string, uin string) error
 	DeleteRawSubAccountByUIN(current model.User, group string, uin string) error
 	DeleteAllRawSubAccounts(current model.User, group string) error
 
 	GetUserByExternalID(externalID string) (*model.User, error)
 
-	CreateAction(current model.User, group string, audit *string, providerID string, accountID string, encryptedKey string, encryptedBlob string) (*model.CTest, error)
+	CreateAction(current model.User, group string, audit *string, providerID string, accountID string, encryptedKey string, encryptedBlob string, positive bool) (*model.CTest, error)
 
-	GetAudit(current model.User, group string, userIdentifier *string, entity *string, entityID *string, operation *string, clientData *string,
-		createdAt *time.Time, sortBy *string, asc *bool, limit *int64) ([]*AuditEntity, error)
+	GetAudit(current model.User, group string, q *utils.Query) ([]*AuditEntity, string, error)
+	VerifyAudit(fromSequence int64, toSequence int64) (*AuditVerification, error)
+	GetAuditMetrics() AuditMetrics
+
+	GetNotificationTemplates() ([]*model.NotificationTemplate, error)
+	CreateNotificationTemplate(current model.User, group string, audit *string, event string, variants []model.NotificationTemplateVariant) (*model.NotificationTemplate, error)
+	UpdateNotificationTemplate(current model.User, group string, audit *string, ID string, event string, variants []model.NotificationTemplateVariant) (*model.NotificationTemplate, error)
+	DeleteNotificationTemplate(current model.User, group string, ID string) error
+
+	GetBroadcasts(group string, status *string) ([]*model.Broadcast, error)
+	GetBroadcastAudienceSize(group string, target model.BroadcastTarget) (int, error)
+	CreateBroadcast(current model.User, group string, audit *string, target model.BroadcastTarget, title string, body string, scheduledAt *time.Time) (*model.Broadcast, error)
+	CancelBroadcast(current model.User, group string, ID string) error
+
+	GetComplianceSummary(group string) (*model.ComplianceSummary, error)
+	GetComplianceItems(group string, status *string, category *string, countyID *string, limit int, offset int) ([]model.ComplianceItem, int, error)
+
+	GetExposureVerificationCodes(issuedBy *string, status *string, limit int64) ([]model.ExposureVerificationCode, error)
+	IssueExposureVerificationCode(current model.User, group string, audit *string, reportType string, testDate time.Time,
+		symptomOnsetDate *time.Time) (*model.ExposureVerificationCode, string, error)
+	GetExposureMetrics(days int) (*model.ExposureMetrics, error)
+	GetUsersCacheMetrics() model.UsersCacheMetrics
+
+	GetRoles() ([]model.Role, error)
+	CreateRole(current model.User, group string, audit *string, name string, description string, groups []string, permissions []string, counties []string) (*model.Role, error)
+	UpdateRole(current model.User, group string, audit *string, ID string, name string, description string, groups []string, permissions []string, counties []string) (*model.Role, error)
+	DeleteRole(current model.User, group string, ID string) error
 }
 
 type administrationImpl struct {
@@ -525,8 +603,8 @@ func (s *administrationImpl) UpdateFAQSection(current model.User, group string,
 	return s.app.updateFAQSection(current, group, audit, ID, title, displayOrder)
 }
 
-func (s *administrationImpl) GetProviders() ([]*model.Provider, error) {
-	return s.app.getProviders()
+func (s *administrationImpl) QueryProviders(q *utils.Query) ([]*model.Provider, string, error) {
+	return s.app.queryProviders(q)
 }
 
 func (s *administrationImpl) CreateProvider(current model.User, group string, audit *string, providerName string, manualTest bool, availableMechanisms []string) (*model.Provider, error) {
@@ -541,8 +619,40 @@ func (s *administrationImpl) DeleteProvider(current model.User, group string, ID
 	return s.app.deleteProvider(current, group, ID)
 }
 
-func (s *administrationImpl) FindCounties(f *utils.Filter) ([]*model.County, error) {
-	return s.app.findCounties(f)
+func (s *administrationImpl) GetProviderCredentials(providerID string) ([]model.ProviderCredential, error) {
+	return s.app.getProviderCredentials(providerID)
+}
+
+func (s *administrationImpl) CreateProviderCredential(current model.User, group string, audit *string, providerID string, requireSignature bool, rateLimit int, scopes []string, expires *time.Time) (*model.ProviderCredential, string, error) {
+	return s.app.createProviderCredential(current, group, audit, providerID, requireSignature, rateLimit, scopes, expires)
+}
+
+func (s *administrationImpl) RotateProviderCredential(current model.User, group string, audit *string, ID string, gracePeriodHours *int) (*model.ProviderCredential, string, error) {
+	return s.app.rotateProviderCredential(current, group, audit, ID, gracePeriodHours)
+}
+
+func (s *administrationImpl) RevokeProviderCredential(current model.User, group string, audit *string, ID string) error {
+	return s.app.revokeProviderCredential(current, group, audit, ID)
+}
+
+func (s *administrationImpl) GetAPIKeys() ([]model.APIKey, error) {
+	return s.app.getAPIKeys()
+}
+
+func (s *administrationImpl) CreateAPIKey(current model.User, group string, audit *string, name string, owner string, scopes []string, expires *time.Time) (*model.APIKey, string, error) {
+	return s.app.createAPIKey(current, group, audit, name, owner, scopes, expires)
+}
+
+func (s *administrationImpl) RotateAPIKey(current model.User, group string, audit *string, ID string, gracePeriodHours *int) (*model.APIKey, string, error) {
+	return s.app.rotateAPIKey(current, group, audit, ID, gracePeriodHours)
+}
+
+func (s *administrationImpl) RevokeAPIKey(current model.User, group string, audit *string, ID string) error {
+	return s.app.revokeAPIKey(current, group, audit, ID)
+}
+
+func (s *administrationImpl) QueryCounties(group string, q *utils.Query) ([]*model.County, string, error) {
+	return s.app.queryCounties(group, q)
 }
 
 func (s *administrationImpl) CreateCounty(current model.User, group string, audit *string, name string, stateProvince string, country string) (*model.County, error) {
@@ -569,8 +679,8 @@ func (s *administrationImpl) DeleteGuideline(current model.User, group string, I
 	return s.app.deleteGuideline(current, group, ID)
 }
 
-func (s *administrationImpl) GetGuidelinesByCountyID(countyID string) ([]*model.Guideline, error) {
-	return s.app.getGuidelinesByCountyID(countyID)
+func (s *administrationImpl) GetGuidelinesByCountyID(group string, countyID string) ([]*model.Guideline, error) {
+	return s.app.getGuidelinesByCountyID(group, countyID)
 }
 
 func (s *administrationImpl) CreateCountyStatus(current model.User, group string, audit *string, countyID string, name string, description string) (*model.CountyStatus, error) {
@@ -585,8 +695,8 @@ func (s *administrationImpl) DeleteCountyStatus(current model.User, group string
 	return s.app.deleteCountyStatus(current, group, ID)
 }
 
-func (s *administrationImpl) GetCountyStatusByCountyID(countyID string) ([]*model.CountyStatus, error) {
-	return s.app.getCountyStatusByCountyID(countyID)
+func (s *administrationImpl) GetCountyStatusByCountyID(group string, countyID string) ([]*model.CountyStatus, error) {
+	return s.app.getCountyStatusByCountyID(group, countyID)
 }
 
 func (s *administrationImpl) GetTestTypes() ([]*model.TestType, error) {
@@ -621,8 +731,8 @@ func (s *administrationImpl) GetTestTypeResultsByTestTypeID(testTypeID string) (
 	return s.app.getTestTypeResultsByTestTypeID(testTypeID)
 }
 
-func (s *administrationImpl) GetRules() ([]*model.Rule, error) {
-	return s.app.getRules()
+func (s *administrationImpl) GetRules(group string) ([]*model.Rule, error) {
+	return s.app.getRules(group)
 }
 
 func (s *administrationImpl) CreateRule(current model.User, group string, audit *string, countyID string, testTypeID string, priority *int, resultsStatuses []model.TestTypeResultCountyStatus) (*model.Rule, error) {
@@ -637,8 +747,8 @@ func (s *administrationImpl) DeleteRule(current model.User, group string, ID str
 	return s.app.deleteRule(current, group, ID)
 }
 
-func (s *administrationImpl) GetLocations() ([]*model.Location, error) {
-	return s.app.getLocations()
+func (s *administrationImpl) QueryLocations(group string, q *utils.Query) ([]*model.Location, string, error) {
+	return s.app.queryLocations(group, q)
 }
 
 func (s *administrationImpl) CreateLocation(current model.User, group string, audit *string, providerID string, countyID string, name string, address1 string, address2 string, city string,
@@ -675,8 +785,8 @@ func (s *administrationImpl) GetSymptomGroups() ([]*model.SymptomGroup, error) {
 	return s.app.getSymptomGroups()
 }
 
-func (s *administrationImpl) GetSymptomRules() ([]*model.SymptomRule, error) {
-	return s.app.getSymptomRules()
+func (s *administrationImpl) GetSymptomRules(group string) ([]*model.SymptomRule, error) {
+	return s.app.getSymptomRules(group)
 }
 
 func (s *administrationImpl) CreateSymptomRule(current model.User, group string, countyID string, gr1Count int, gr2Count int, items []model.SymptomRuleItem) (*model.SymptomRule, error) {
@@ -691,20 +801,20 @@ func (s *administrationImpl) DeleteSymptomRule(current model.User, group string,
 	return s.app.deleteSymptomRule(current, group, ID)
 }
 
-func (s *administrationImpl) GetManualTestByCountyID(countyID string, status *string) ([]*model.EManualTest, error) {
-	return s.app.getManualTestByCountyID(countyID, status)
+func (s *administrationImpl) QueryManualTests(group string, countyID string, q *utils.Query) ([]*model.EManualTest, string, error) {
+	return s.app.queryManualTests(group, countyID, q)
 }
 
-func (s *administrationImpl) ProcessManualTest(ID string, status string, encryptedKey *string, encryptedBlob *string, date *time.Time) error {
-	return s.app.processManualTest(ID, status, encryptedKey, encryptedBlob, date)
+func (s *administrationImpl) ProcessManualTest(group string, ID string, status string, encryptedKey *string, encryptedBlob *string, date *time.Time) error {
+	return s.app.processManualTest(group, ID, status, encryptedKey, encryptedBlob, date)
 }
 
-func (s *administrationImpl) GetManualTestImage(ID string) (*string, *string, error) {
-	return s.app.getManualTestImage(ID)
+func (s *administrationImpl) GetManualTestImage(group string, ID string) (*string, *string, error) {
+	return s.app.getManualTestImage(group, ID)
 }
 
-func (s *administrationImpl) GetAccessRules() ([]*model.AccessRule, error) {
-	return s.app.getAccessRules()
+func (s *administrationImpl) GetAccessRules(group string) ([]*model.AccessRule, error) {
+	return s.app.getAccessRules(group)
 }
 
 func (s *administrationImpl) CreateAccessRule(current model.User, group string, audit *string, countyID string, rules []model.AccessRuleCountyStatus) (*model.AccessRule, error) {
@@ -719,8 +829,8 @@ func (s *administrationImpl) DeleteAccessRule(current model.User, group string,
 	return s.app.deleteAccessRule(current, group, ID)
 }
 
-func (s *administrationImpl) GetCRules(countyID string, appVersion string) (*model.CRules, error) {
-	return s.app.getCRules(countyID, appVersion)
+func (s *administrationImpl) GetCRules(group string, countyID string, appVersion string) (*model.CRules, error) {
+	return s.app.getCRules(group, countyID, appVersion)
 }
 
 func (s *administrationImpl) CreateOrUpdateCRules(current model.User, group string, audit *string, countyID string, appVersion string, data string) error {
@@ -735,8 +845,8 @@ func (s *administrationImpl) CreateOrUpdateSymptoms(current model.User, group st
 	return s.app.createOrUpdateSymptoms(current, group, audit, appVersion, items)
 }
 
-func (s *administrationImpl) GetUINOverrides(uin *string, sort *string) ([]*model.UINOverride, error) {
-	return s.app.getUINOverrides(uin, sort)
+func (s *administrationImpl) QueryUINOverrides(q *utils.Query) ([]*model.UINOverride, string, error) {
+	return s.app.queryUINOverrides(q)
 }
 
 func (s *administrationImpl) CreateUINOverride(current model.User, group string, audit *string, uin string, exempt *bool, interval *int, category *string, activation *time.Time, expiration *time.Time) (*model.UINOverride, error) {
@@ -755,27 +865,38 @@ func (s *administrationImpl) GetUserByExternalID(externalID string) (*model.User
 	return s.app.getUserByExternalID(externalID)
 }
 
-func (s *administrationImpl) CreateRoster(current model.User, group string, audit *string, phone string, uin string, firstName string,
-	middleName string, lastName string, birthDate string, gender string, address1 string, address2 string,
-	address3 string, city string, state string, zipCode string, email string, badgeType string) error {
-	return s.app.createRoster(current, group, audit, phone, uin, firstName, middleName, lastName, birthDate, gender,
-		address1, address2, address3, city, state, zipCode, email, badgeType)
+func (s *administrationImpl) CreateRoster(current model.User, group string, audit *string, roster model.Roster) error {
+	return s.app.createRoster(current, group, audit, roster)
 }
 
-func (s *administrationImpl) UpdateRoster(current model.User, group string, audit *string, uin string, firstName string, middleName string, lastName string, birthDate string, gender string,
-	address1 string, address2 string, address3 string, city string, state string, zipCode string, email string, badgeType string) error {
-	return s.app.updateRoster(current, group, audit, uin, firstName, middleName, lastName, birthDate, gender,
-		address1, address2, address3, city, state, zipCode, email, badgeType)
+func (s *administrationImpl) UpdateRoster(current model.User, group string, audit *string, roster model.Roster) error {
+	return s.app.updateRoster(current, group, audit, roster)
 }
 
-func (s *administrationImpl) CreateRosterItems(current model.User, group string, audit *string, items []map[string]string) error {
+func (s *administrationImpl) CreateRosterItems(current model.User, group string, audit *string, items []model.Roster) error {
 	return s.app.createRosterItems(current, group, audit, items)
 }
 
-func (s *administrationImpl) GetRosters(filter *utils.Filter, sortBy string, sortOrder int, limit int, offset int) ([]map[string]interface{}, error) {
+func (s *administrationImpl) GetRosters(filter *utils.Filter, sortBy string, sortOrder int, limit int, offset int) ([]model.Roster, error) {
 	return s.app.getRosters(filter, sortBy, sortOrder, limit, offset)
 }
 
+func (s *administrationImpl) QueryRosters(q *utils.Query) ([]model.Roster, string, error) {
+	return s.app.queryRosters(q)
+}
+
+func (s *administrationImpl) ImportRoster(current model.User, group string, audit *string, fileName string, rows [][]string, mapping map[string]string, dryRun bool) (*model.RosterImport, error) {
+	return s.app.importRoster(current, group, audit, fileName, rows, mapping, dryRun)
+}
+
+func (s *administrationImpl) GetRosterImport(ID string) (*model.RosterImport, error) {
+	return s.app.getRosterImport(ID)
+}
+
+func (s *administrationImpl) SyncRosters(current model.User, group string, audit *string, items []model.Roster, dryRun bool) (*model.RosterSync, error) {
+	return s.app.syncRosters(current, group, audit, items, dryRun)
+}
+
 func (s *administrationImpl) DeleteRosterByPhone(current model.User, group string, phone string) error {
 	return s.app.deleteRosterByPhone(current, group, phone)
 }
@@ -802,6 +923,10 @@ func (s *administrationImpl) UpdateRawSubAccount(current model.User, group strin
 		address3, city, state, zipCode, phone, netID, email)
 }
 
+func (s *administrationImpl) RevokeRawSubAccount(current model.User, group string, audit *string, uin string) error {
+	return s.app.revokeRawSubAccount(current, group, audit, uin)
+}
+
 func (s *administrationImpl) DeleteRawSubAccountByUIN(current model.User, group string, uin string) error {
 	return s.app.deleteRawSubAccountByUIN(current, group, uin)
 }
@@ -810,13 +935,93 @@ func (s *administrationImpl) DeleteAllRawSubAccounts(current model.User, group s
 	return s.app.deleteAllRawSubAccounts(current, group)
 }
 
-func (s *administrationImpl) CreateAction(current model.User, group string, audit *string, providerID string, accountID string, encryptedKey string, encryptedBlob string) (*model.CTest, error) {
-	return s.app.createAction(current, group, audit, providerID, accountID, encryptedKey, encryptedBlob)
+func (s *administrationImpl) CreateAction(current model.User, group string, audit *string, providerID string, accountID string, encryptedKey string, encryptedBlob string, positive bool) (*model.CTest, error) {
+	return s.app.createAction(current, group, audit, providerID, accountID, encryptedKey, encryptedBlob, positive)
+}
+
+func (s *administrationImpl) GetAudit(current model.User, group string, q *utils.Query) ([]*AuditEntity, string, error) {
+	return s.app.getAudit(current, group, q)
 }
 
-func (s *administrationImpl) GetAudit(current model.User, group string, userIdentifier *string, entity *string, entityID *string, operation *string,
-	clientData *string, createdAt *time.Time, sortBy *string, asc *bool, limit *int64) ([]*AuditEntity, error) {
-	return s.app.getAudit(current, group, userIdentifier, entity, entityID, operation, clientData, createdAt, sortBy, asc, limit)
+func (s *administrationImpl) VerifyAudit(fromSequence int64, toSequence int64) (*AuditVerification, error) {
+	return s.app.verifyAudit(fromSequence, toSequence)
+}
+
+func (s *administrationImpl) GetAuditMetrics() AuditMetrics {
+	return s.app.getAuditMetrics()
+}
+
+func (s *administrationImpl) GetNotificationTemplates() ([]*model.NotificationTemplate, error) {
+	return s.app.getNotificationTemplates()
+}
+
+func (s *administrationImpl) CreateNotificationTemplate(current model.User, group string, audit *string, event string, variants []model.NotificationTemplateVariant) (*model.NotificationTemplate, error) {
+	return s.app.createNotificationTemplate(current, group, audit, event, variants)
+}
+
+func (s *administrationImpl) UpdateNotificationTemplate(current model.User, group string, audit *string, ID string, event string, variants []model.NotificationTemplateVariant) (*model.NotificationTemplate, error) {
+	return s.app.updateNotificationTemplate(current, group, audit, ID, event, variants)
+}
+
+func (s *administrationImpl) DeleteNotificationTemplate(current model.User, group string, ID string) error {
+	return s.app.deleteNotificationTemplate(current, group, ID)
+}
+
+func (s *administrationImpl) GetBroadcasts(group string, status *string) ([]*model.Broadcast, error) {
+	return s.app.getBroadcasts(group, status)
+}
+
+func (s *administrationImpl) GetBroadcastAudienceSize(group string, target model.BroadcastTarget) (int, error) {
+	return s.app.getBroadcastAudienceSize(group, target)
+}
+
+func (s *administrationImpl) CreateBroadcast(current model.User, group string, audit *string, target model.BroadcastTarget, title string, body string, scheduledAt *time.Time) (*model.Broadcast, error) {
+	return s.app.createBroadcast(current, group, audit, target, title, body, scheduledAt)
+}
+
+func (s *administrationImpl) CancelBroadcast(current model.User, group string, ID string) error {
+	return s.app.cancelBroadcast(current, group, ID)
+}
+
+func (s *administrationImpl) GetComplianceSummary(group string) (*model.ComplianceSummary, error) {
+	return s.app.getComplianceSummary(group)
+}
+
+func (s *administrationImpl) GetComplianceItems(group string, status *string, category *string, countyID *string, limit int, offset int) ([]model.ComplianceItem, int, error) {
+	return s.app.findComplianceItems(group, status, category, countyID, limit, offset)
+}
+
+func (s *administrationImpl) GetExposureVerificationCodes(issuedBy *string, status *string, limit int64) ([]model.ExposureVerificationCode, error) {
+	return s.app.getExposureVerificationCodes(issuedBy, status, limit)
+}
+
+func (s *administrationImpl) IssueExposureVerificationCode(current model.User, group string, audit *string, reportType string, testDate time.Time,
+	symptomOnsetDate *time.Time) (*model.ExposureVerificationCode, string, error) {
+	return s.app.issueExposureVerificationCode(current, group, audit, reportType, testDate, symptomOnsetDate)
+}
+
+func (s *administrationImpl) GetUsersCacheMetrics() model.UsersCacheMetrics {
+	return s.app.getUsersCacheMetrics()
+}
+
+func (s *administrationImpl) GetExposureMetrics(days int) (*model.ExposureMetrics, error) {
+	return s.app.getExposureMetrics(days)
+}
+
+func (s *administrationImpl) GetRoles() ([]model.Role, error) {
+	return s.app.getRoles()
+}
+
+func (s *administrationImpl) CreateRole(current model.User, group string, audit *string, name string, description string, groups []string, permissions []string, counties []string) (*model.Role, error) {
+	return s.app.createRole(current, group, audit, name, description, groups, permissions, counties)
+}
+
+func (s *administrationImpl) UpdateRole(current model.User, group string, audit *string, ID string, name string, description string, groups []string, permissions []string, counties []string) (*model.Role, error) {
+	return s.app.updateRole(current, group, audit, ID, name, description, groups, permissions, counties)
+}
+
+func (s *administrationImpl) DeleteRole(current model.User, group string, ID string) error {
+	return s.app.deleteRole(current, group, ID)
 }
 
 //Storage is used by core to storage data - DB storage adapter, file storage adapter etc
@@ -876,6 +1081,7 @@ type Storage interface {
 	SaveEHistory(history *model.EHistory) error
 
 	ReadAllProviders() ([]*model.Provider, error)
+	QueryProviders(q *utils.Query) ([]*model.Provider, string, error)
 	CreateProvider(providerName string, manualTest bool, availableMechanisms []string) (*model.Provider, error)
 	FindProvider(ID string) (*model.Provider, error)
 	SaveProvider(provider *model.Provider) error
@@ -890,6 +1096,7 @@ type Storage interface {
 	SaveCTest(ctest *model.CTest) error
 
 	FindCounties(f *utils.Filter) ([]*model.County, error)
+	QueryCounties(q *utils.Query) ([]*model.County, string, error)
 	CreateCounty(name string, stateProvince string, country string) (*model.County, error)
 	FindCounty(ID string) (*model.County, error)
 	SaveCounty(county *model.County) error
@@ -929,6 +1136,7 @@ type Storage interface {
 	DeleteRule(ID string) error
 
 	ReadAllLocations() ([]*model.Location, error)
+	QueryLocations(q *utils.Query) ([]*model.Location, string, error)
 	CreateLocation(providerID string, countyID string, name string, address1 string, address2 string, city string,
 		state string, zip string, country string, latitude float64, longitude float64, contact string,
 		daysOfOperation []model.OperationDay, url string, notes string, waitTimeColor *string, availableTests []string) (*model.Location, error)
@@ -960,15 +1168,31 @@ type Storage interface {
 	CreateOrUpdateCRules(appVersion string, countyID string, data string) (*bool, error)
 
 	CreateTraceReports(items []model.TraceExposure) (int, error)
-	ReadTraceExposures(timestamp *int64, dateAdded *int64) ([]model.TraceExposure, error)
-
-	FindManualTestsByCountyIDDeep(countyID string, status *string) ([]*model.EManualTest, error)
+	ReadTraceExposures(timestamp *int64, dateAdded *int64, now int64) ([]model.TraceExposure, error)
+	ReadTraceExposuresPage(timestamp *int64, after *model.TraceExposureCursor, addedBefore int64, now int64, limit int64) ([]model.TraceExposure, *model.TraceExposureCursor, error)
+	PurgeTraceExposures(now int64, retentionFrom int64) (int64, int64, error)
+	CountTraceExposures(gaenOnly bool) (int64, error)
+	CreateExposurePurge(purge model.ExposurePurge) error
+	FindExposurePurges(from time.Time) ([]model.ExposurePurge, error)
+	CountGAENTraceExposures(dateAddedFrom int64, dateAddedTo int64) (int64, error)
+	ReadGAENTraceExposures(dateAddedFrom int64, dateAddedTo int64, offset int64, limit int64) ([]model.TraceExposure, error)
+
+	CreateExposureVerificationCode(code model.ExposureVerificationCode) (bool, error)
+	CountExposureVerificationCodes(issuedBy string, from time.Time) (int64, error)
+	FindExposureVerificationCodes(issuedBy *string, status *string, limit int64) ([]model.ExposureVerificationCode, error)
+	ClaimExposureVerificationCode(codeHash string, userID string, tokenHash string, tokenExpiresAt time.Time) (*model.ExposureVerificationCode, error)
+	CreateTraceReportsWithUploadToken(tokenHash string, items []model.TraceExposure) (*model.ExposureVerificationCode, int, error)
+	CreateGAENTraceReports(certificateHash string, certificateExpiresAt time.Time, items []model.TraceExposure) (bool, int, error)
+
+	QueryManualTests(countyIDs []string, q *utils.Query) ([]*model.EManualTest, string, error)
 	FindManualTestImage(ID string) (*string, *string, error)
+	FindManualTestCountyID(ID string) (*string, error)
 	ProcessManualTest(ID string, status string, encryptedKey *string, encryptedBlob *string, date *time.Time) error
 
 	ReadAllAccessRules() ([]*model.AccessRule, error)
 	CreateAccessRule(countyID string, rules []model.AccessRuleCountyStatus) (*model.AccessRule, error)
 	UpdateAccessRule(ID string, countyID string, rules []model.AccessRuleCountyStatus) (*model.AccessRule, error)
+	FindAccessRule(ID string) (*model.AccessRule, error)
 	FindAccessRuleByCountyID(countyID string) (*model.AccessRule, error)
 	DeleteAccessRule(ID string) error
 
@@ -981,6 +1205,7 @@ type Storage interface {
 
 	//finds the uin override for the provided uin. If uin is nil then it gives all
 	FindUINOverrides(uin *string, sort *string) ([]*model.UINOverride, error)
+	QueryUINOverrides(q *utils.Query) ([]*model.UINOverride, string, error)
 	CreateUINOverride(uin string, exempt *bool, interval *int, category *string, activation *time.Time, expiration *time.Time) (*model.UINOverride, error)
 	UpdateUINOverride(uin string, exempt *bool, interval *int, category *string, activation *time.Time, expiration *time.Time) (*string, error)
 	DeleteUINOverride(uin string) error
@@ -988,24 +1213,72 @@ type Storage interface {
 	FindUINBuildingAccess(uin string) (*model.UINBuildingAccess, error)
 	CreateOrUpdateUINBuildingAccess(uin string, date time.Time, access string) error
 
-	ReadAllRosters() ([]map[string]string, error)
-	FindRosterByPhone(phone string) (map[string]string, error)
-	FindRosters(filter *utils.Filter, sortBy string, sortOrder int, limit int, offset int) ([]map[string]interface{}, error)
-	CreateRoster(phone string, uin string, firstName string, middleName string, lastName string, birthDate string, gender string,
-		address1 string, address2 string, address3 string, city string, state string, zipCode string, email string, badgeType string) error
-	UpdateRoster(uin string, firstName string, middleName string, lastName string, birthDate string, gender string,
-		address1 string, address2 string, address3 string, city string, state string, zipCode string, email string, badgeType string) error
-	CreateRosterItems(items []map[string]string) error
+	ReadAllRosters() ([]model.Roster, error)
+	FindRosterByPhone(phone string) (*model.Roster, error)
+	FindRosters(filter *utils.Filter, sortBy string, sortOrder int, limit int, offset int) ([]model.Roster, error)
+	QueryRosters(q *utils.Query) ([]model.Roster, string, error)
+	FindRostersByPhonesOrUINs(phones []string, uins []string) ([]model.Roster, error)
+	CreateRoster(roster model.Roster) error
+	UpdateRoster(roster model.Roster) error
+	CreateRosterItems(items []model.Roster) error
+	SyncRosters(added []model.Roster, updated []model.Roster, removedUINs []string) error
 	DeleteRosterByPhone(phone string) error
 	DeleteRosterByUIN(uin string) error
 	DeleteAllRosters() error
 
+	CreateRosterImport(item model.RosterImport, items []model.Roster) error
+	FindRosterImport(ID string) (*model.RosterImport, error)
+
 	CreateRawSubAccountItems(items []model.RawSubAccount) error
 	FindRawSubAccounts(filter *utils.Filter, sortBy string, sortOrder int, limit int, offset int) ([]model.RawSubAccount, error)
 	UpdateRawSubAcccount(uin string, firstName string, middleName string, lastName string, birthDate string, gender string,
 		address1 string, address2 string, address3 string, city string, state string, zipCode string, phone string, netID string, email string) error
 	DeleteRawSubAccountByUIN(uin string) error
 	DeleteAllSubAccounts() error
+	FindRawSubAccountsByPrimaryAccount(primaryAccount string, linkStatus string) ([]model.RawSubAccount, error)
+	AcceptRawSubAccount(userID string, uin string) (*model.Account, error)
+	DeclineRawSubAccount(primaryAccount string, uin string) error
+	RevokeRawSubAccount(uin string) error
+
+	ReadAllNotificationTemplates() ([]*model.NotificationTemplate, error)
+	FindNotificationTemplateByEvent(event string) (*model.NotificationTemplate, error)
+	CreateNotificationTemplate(event string, variants []model.NotificationTemplateVariant) (*model.NotificationTemplate, error)
+	UpdateNotificationTemplate(ID string, event string, variants []model.NotificationTemplateVariant) (*model.NotificationTemplate, error)
+	DeleteNotificationTemplate(ID string) error
+
+	FindBroadcastAudience(target model.BroadcastTarget) ([]string, error)
+	CreateBroadcast(target model.BroadcastTarget, title string, body string, scheduledAt time.Time, createdBy string) (*model.Broadcast, error)
+	FindBroadcasts(status *string) ([]*model.Broadcast, error)
+	FindBroadcast(ID string) (*model.Broadcast, error)
+	StartDueBroadcast(now time.Time) (*model.Broadcast, error)
+	RenewBroadcastClaim(ID string, sentCount int, now time.Time) error
+	FailStaleBroadcasts(claimedBefore time.Time) (int64, error)
+	UpdateBroadcastStatus(ID string, status string, audienceCount *int, sentCount *int, dateSent *time.Time) error
+	CancelBroadcast(ID string) error
+
+	FindTestingSubjects() ([]model.TestingSubject, error)
+	CreateTestingReminder(uin string, dueDate time.Time, reminderType string) (bool, error)
+
+	ReadAllRoles() ([]model.Role, error)
+	FindRole(ID string) (*model.Role, error)
+	CreateRoles(roles []model.Role) error
+	CreateRole(role model.Role) error
+	UpdateRole(role model.Role) error
+	DeleteRole(ID string) error
+
+	ReadActiveProviderCredentials() ([]model.ProviderCredential, error)
+	FindProviderCredentials(providerID string) ([]model.ProviderCredential, error)
+	FindProviderCredential(ID string) (*model.ProviderCredential, error)
+	CreateProviderCredential(credential model.ProviderCredential) error
+	UpdateProviderCredential(credential model.ProviderCredential) error
+	DeleteProviderCredentials(providerID string) error
+
+	ReadActiveAPIKeys() ([]model.APIKey, error)
+	ReadAllAPIKeys() ([]model.APIKey, error)
+	FindAPIKey(ID string) (*model.APIKey, error)
+	CreateAPIKey(apiKey model.APIKey) error
+	UpdateAPIKey(apiKey model.APIKey) error
+	UpdateAPIKeysLastUsed(usage map[string]time.Time) error
 }
 
 //StorageListener listenes for change data storage events
@@ -1014,6 +1287,9 @@ type StorageListener interface {
 	OnAppVersionsChanged()
 	OnRostersChanged()
 	OnRawSubAccountsChanged()
+	OnRolesChanged()
+	OnProviderCredentialsChanged()
+	OnAPIKeysChanged()
 
 	OnUserCreated(user model.User)
 	OnUserUpdated(user model.User)
@@ -1035,11 +1311,32 @@ func (a *storageListenerImpl) OnAppVersionsChanged() {
 }
 
 func (a *storageListenerImpl) OnRostersChanged() {
+	//the cached users could be affected by the roster change
+	a.app.usersCache.purge()
+
 	//notify that th rosters has been changed
 	a.app.notifyListeners("onRostersUpdated", nil)
 }
 
+func (a *storageListenerImpl) OnRolesChanged() {
+	//reload the roles
+	a.app.loadRoles()
+}
+
+func (a *storageListenerImpl) OnProviderCredentialsChanged() {
+	//reload the provider credentials
+	a.app.loadProviderCredentials()
+}
+
+func (a *storageListenerImpl) OnAPIKeysChanged() {
+	//reload the API keys
+	a.app.loadAPIKeys()
+}
+
 func (a *storageListenerImpl) OnRawSubAccountsChanged() {
+	//the cached users could be affected by the sub accounts change
+	a.app.usersCache.purge()
+
 	//notify that the raw sub accounts have been changed
 	a.app.notifyListeners("onRawSubAccountsUpdated", nil)
 }
@@ -1052,12 +1349,18 @@ func (a *storageListenerImpl) OnUserCreated(user model.User) {
 
 func (a *storageListenerImpl) OnUserUpdated(user model.User) {
 	log.Printf("storageListenerImpl -> OnUserUpdated - %s", user.ID)
+	//take out the updated user from the cached users
+	a.app.usersCache.invalidate(user.ID)
+
 	//notify that a user has been updated
 	a.app.notifyListeners("onUserUpdated", user)
 }
 
 func (a *storageListenerImpl) OnUserDeleted(userID string) {
 	log.Printf("storageListenerImpl -> OnUserDeleted - %s", userID)
+	//take out the deleted user from the cached users
+	a.app.usersCache.invalidate(userID)
+
 	//notify that a user has been deleted
 	a.app.notifyListeners("onUserDeleted", userID)
 }
@@ -1086,6 +1389,7 @@ type ProviderResource struct {
 type Sender interface {
 	SendForNews(newsList []*model.News)
 	SendForResources(resourcesList []*model.Resource)
+	SendForTestingOverdue(subjects []model.TestingSubject)
 }
 
 //Messaging is used by core to send user messages
@@ -1093,6 +1397,21 @@ type Messaging interface {
 	SendNotificationMessage(tokens []string, title string, body string, data map[string]string)
 }
 
+//UsersCache is used by core to share the cached users between the service instances
+type UsersCache interface {
+	//Get gives the cached value, nil if the key is not cached
+	Get(key string) ([]byte, error)
+	Set(key string, value []byte, ttl time.Duration) error
+	Delete(keys []string) error
+	DeletePrefix(prefix string) error
+}
+
+//ExposureNotification is used by core to verify the exposure keys uploads and to create the exposure keys exports
+type ExposureNotification interface {
+	VerifyCertificate(certificate string, hmacKey string, keys []model.TraceExposure) (*model.ExposureVerification, error)
+	CreateExport(keys []model.TraceExposure, start time.Time, end time.Time, batchNum int, batchSize int) ([]byte, error)
+}
+
 //ProfileBuildingBlock is used by core to communicate with the profile building block.
 type ProfileBuildingBlock interface {
 	LoadUserData(uuid string) (*ProfileUserData, error)
@@ -1101,6 +1420,7 @@ type ProfileBuildingBlock interface {
 //ProfileUserData represents the profile building block user data entity
 type ProfileUserData struct {
 	FCMTokens []string `json:"fcmTokens"`
+	Language  string   `json:"language"`
 }
 
 //Rokmetro is used by core to communicate with the rokmetro ecosystem
@@ -1125,12 +1445,13 @@ type RokmetroJoinGroupExtApprovement struct {
 
 //Audit is used by core to log history
 type Audit interface {
-	LogCreateEvent(userIdentifier string, userInfo string, usedGroup string, entity string, entityID string, data []AuditDataEntry, clientData *string)
-	LogUpdateEvent(userIdentifier string, userInfo string, usedGroup string, entity string, entityID string, data []AuditDataEntry, clientData *string)
-	LogDeleteEvent(userIdentifier string, userInfo string, usedGroup string, entity string, entityID string)
+	LogCreateEvent(userIdentifier string, userInfo string, usedGroup string, entity string, entityID string, data []AuditDataEntry, clientData *string) error
+	LogUpdateEvent(userIdentifier string, userInfo string, usedGroup string, entity string, entityID string, data []AuditDataEntry, clientData *string) error
+	LogDeleteEvent(userIdentifier string, userInfo string, usedGroup string, entity string, entityID string) error
 
-	Find(userIdentifier *string, usedGroup *string, entity *string, entityID *string, operation *string,
-		clientData *string, createdAt *time.Time, sortBy *string, asc *bool, limit *int64) ([]*AuditEntity, error)
+	Query(usedGroup *string, q *utils.Query) ([]*AuditEntity, string, error)
+	Verify(fromSequence int64, toSequence int64) (*AuditVerification, error)
+	Metrics() AuditMetrics
 }
 
 //AuditEntity represents audit module entity
@@ -1141,11 +1462,63 @@ type AuditEntity struct {
 	Entity         string    `json:"entity" bson:"entity"`
 	EntityID       string    `json:"entity_id" bson:"entity_id"`
 	Operation      string    `json:"operation" bson:"operation"`
-	Data           *string   `json:"data" bson:"data"`
+	Data           *string   `json:"data" bson:"data"` //the flattened data of the items logged before the snapshots were introduced
 	ClientData     *string   `json:"client_data" bson:"client_data"`
 	CreatedAt      time.Time `json:"created_at" bson:"created_at"`
+
+	Before  map[string]string `json:"before" bson:"before"` //the previous values of the updated fields or the deleted entity data, nil if not known
+	After   map[string]string `json:"after" bson:"after"`   //nil for delete
+	Changes []AuditChange     `json:"changes" bson:"changes"`
+
+	//the items are chained by hash in the order of their sequence, the items logged before the chain do not have sequence
+	Sequence int64  `json:"sequence" bson:"sequence,omitempty"`
+	PrevHash string `json:"prev_hash" bson:"prev_hash,omitempty"`
+	Hash     string `json:"hash" bson:"hash,omitempty"`
 } // @name AuditEntity
 
+//AuditChange represents a field change between the before and the after audit snapshots
+type AuditChange struct {
+	Field  string  `json:"field" bson:"field"`
+	Before *string `json:"before" bson:"before"`
+	After  *string `json:"after" bson:"after"`
+} // @name AuditChange
+
+//AuditVerification represents the result of the audit chain verification
+type AuditVerification struct {
+	FromSequence int64 `json:"from_sequence"`
+	ToSequence   int64 `json:"to_sequence"` //the last checked item
+	Checked      int64 `json:"checked"`
+	Checkpoints  int   `json:"checkpoints"`
+	Valid        bool  `json:"valid"`
+
+	Gaps               []AuditSequenceRange `json:"gaps"`                //the missing items
+	Modified           []int64              `json:"modified"`            //the items which content does not match their hash
+	Unlinked           []int64              `json:"unlinked"`            //the items which previous hash does not match the previous item
+	InvalidCheckpoints []int64              `json:"invalid_checkpoints"` //the checkpoints with wrong signature or not matching their item
+
+	UnverifiedCheckpoints []int64 `json:"unverified_checkpoints"` //the checkpoints which signature cannot be checked as there is no signing key
+} // @name AuditVerification
+
+//AuditSequenceRange represents audit items sequence range
+type AuditSequenceRange struct {
+	From int64 `json:"from"`
+	To   int64 `json:"to"`
+} // @name AuditSequenceRange
+
+//AuditMetrics represents the audit writer metrics
+type AuditMetrics struct {
+	QueueDepth   int        `json:"queue_depth"` //the items waiting to be written
+	QueueSize    int        `json:"queue_size"`
+	Written      int64      `json:"written"`
+	Dropped      int64      `json:"dropped"` //the lost items
+	Retries      int64      `json:"retries"`
+	Spilled      int64      `json:"spilled"` //the items kept on disk while they cannot be written
+	SpillEnabled bool       `json:"spill_enabled"`
+	Strict       bool       `json:"strict"`
+	LastError    *string    `json:"last_error"`
+	LastErrorAt  *time.Time `json:"last_error_at"`
+} // @name AuditMetrics
+
 //AuditDataEntry represents audit data entry
 type AuditDataEntry struct {
 	Key   string
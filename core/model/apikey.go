@@ -0,0 +1,73 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+
+package model
+
+import "time"
+
+//the scopes of the API keys - which API keys headers they are accepted for
+const (
+	APIKeyScopeApp       = "app"       //ROKWIRE-API-KEY
+	APIKeyScopeExternal  = "external"  //ROKWIRE-EXT-HS-API-KEY
+	APIKeyScopeProviders = "providers" //ROKWIRE-HS-API-KEY, not bound to a provider
+)
+
+//APIKeyScopes are all the scopes which the API keys can have
+var APIKeyScopes = []string{APIKeyScopeApp, APIKeyScopeExternal, APIKeyScopeProviders}
+
+//APIKey represents an API key managed by the admins. Only the key hash is kept, the key is given once on creating.
+type APIKey struct {
+	ID        string   `json:"id" bson:"_id"`
+	Name      string   `json:"name" bson:"name"`
+	Owner     string   `json:"owner" bson:"owner"` //who uses the key
+	Scopes    []string `json:"scopes" bson:"scopes"`
+	KeyHash   string   `json:"-" bson:"key_hash"`
+	KeyPrefix string   `json:"key_prefix" bson:"key_prefix"` //the first key characters, so that the owner can recognize the key
+
+	DateExpires  *time.Time `json:"date_expires" bson:"date_expires"` //set when the key is rotated or it is given for a period
+	DateRevoked  *time.Time `json:"date_revoked" bson:"date_revoked"`
+	DateLastUsed *time.Time `json:"date_last_used" bson:"date_last_used"`
+	DateCreated  time.Time  `json:"date_created" bson:"date_created"`
+} // @name APIKey
+
+//IsActive checks if the key can be used at the moment
+func (k APIKey) IsActive(now time.Time) bool {
+	if k.DateRevoked != nil {
+		return false
+	}
+	return k.DateExpires == nil || now.Before(*k.DateExpires)
+}
+
+//HasScope checks if the key is accepted for the scope
+func (k APIKey) HasScope(scope string) bool {
+	for _, current := range k.Scopes {
+		if current == scope {
+			return true
+		}
+	}
+	return false
+}
+
+//IsAPIKeyScope checks if the name is of a known API key scope
+func IsAPIKeyScope(name string) bool {
+	for _, scope := range APIKeyScopes {
+		if scope == name {
+			return true
+		}
+	}
+	return false
+}
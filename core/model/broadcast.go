@@ -0,0 +1,72 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+
+package model
+
+import "time"
+
+const (
+	//BroadcastStatusScheduled is the status of a broadcast waiting for its send time
+	BroadcastStatusScheduled string = "scheduled"
+	//BroadcastStatusSending is the status of a broadcast which is being sent at the moment
+	BroadcastStatusSending string = "sending"
+	//BroadcastStatusSent is the status of a sent broadcast
+	BroadcastStatusSent string = "sent"
+	//BroadcastStatusCancelled is the status of a broadcast cancelled before its send time
+	BroadcastStatusCancelled string = "cancelled"
+	//BroadcastStatusFailed is the status of a broadcast which could not be sent
+	BroadcastStatusFailed string = "failed"
+
+	//RawSubAccountStatusPending is the status of a raw sub account which has not been accepted as an account yet
+	RawSubAccountStatusPending string = "pending"
+	//RawSubAccountStatusCreated is the status of a raw sub account which has been accepted as an account
+	RawSubAccountStatusCreated string = "created"
+)
+
+//BroadcastTarget represents the audience of a broadcast. All the provided criteria must be satisfied.
+type BroadcastTarget struct {
+	CountyID            *string `json:"county_id" bson:"county_id"`                           //users with manual tests in the county
+	Roster              *bool   `json:"roster" bson:"roster"`                                 //users in (true) or not in (false) the roster
+	RawSubAccountStatus *string `json:"raw_sub_account_status" bson:"raw_sub_account_status"` //pending - the primary accounts, created - the sub accounts
+	UINOverrideCategory *string `json:"uin_override_category" bson:"uin_override_category"`
+	AppVersion          *string `json:"app_version" bson:"app_version"`
+} // @name BroadcastTarget
+
+//IsEmpty says if there is no any criteria - the broadcast is for all users
+func (bt BroadcastTarget) IsEmpty() bool {
+	return bt.CountyID == nil && bt.Roster == nil && bt.RawSubAccountStatus == nil &&
+		bt.UINOverrideCategory == nil && bt.AppVersion == nil
+}
+
+//Broadcast represents a notification sent to many users
+type Broadcast struct {
+	ID     string          `json:"id" bson:"_id"`
+	Target BroadcastTarget `json:"target" bson:"target"`
+	Title  string          `json:"title" bson:"title"`
+	Body   string          `json:"body" bson:"body"`
+
+	ScheduledAt time.Time  `json:"scheduled_at" bson:"scheduled_at"`
+	Status      string     `json:"status" bson:"status"`
+	DateClaimed *time.Time `json:"date_claimed" bson:"date_claimed"` //renewed by the instance while it is sending the broadcast
+
+	AudienceCount *int `json:"audience_count" bson:"audience_count"`
+	SentCount     *int `json:"sent_count" bson:"sent_count"`
+
+	CreatedBy   string     `json:"created_by" bson:"created_by"`
+	DateCreated time.Time  `json:"date_created" bson:"date_created"`
+	DateSent    *time.Time `json:"date_sent" bson:"date_sent"`
+} // @name Broadcast
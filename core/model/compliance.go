@@ -0,0 +1,80 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+
+package model
+
+import "time"
+
+const (
+	//ComplianceStatusCompliant is for the roster members which are tested within their interval
+	ComplianceStatusCompliant string = "compliant"
+	//ComplianceStatusDueSoon is for the roster members which next test is due in the next few days
+	ComplianceStatusDueSoon string = "due-soon"
+	//ComplianceStatusOverdue is for the roster members which missed their due date or have never been tested
+	ComplianceStatusOverdue string = "overdue"
+	//ComplianceStatusExempt is for the roster members which do not need to be tested
+	ComplianceStatusExempt string = "exempt"
+)
+
+//ComplianceItem represents the testing compliance of a roster member
+type ComplianceItem struct {
+	UIN            string     `json:"uin"`
+	FirstName      string     `json:"first_name"`
+	LastName       string     `json:"last_name"`
+	Category       *string    `json:"category"`
+	CountyID       *string    `json:"county_id"`
+	LatestTestDate *time.Time `json:"latest_test_date"`
+	DueDate        *time.Time `json:"due_date"`
+	Status         string     `json:"status"`
+} // @name ComplianceItem
+
+//ComplianceCounts represents how many roster members are in every compliance status
+type ComplianceCounts struct {
+	Compliant int `json:"compliant"`
+	DueSoon   int `json:"due_soon"`
+	Overdue   int `json:"overdue"`
+	Exempt    int `json:"exempt"`
+} // @name ComplianceCounts
+
+//Add counts one more roster member with the provided status
+func (cc *ComplianceCounts) Add(status string) {
+	switch status {
+	case ComplianceStatusCompliant:
+		cc.Compliant++
+	case ComplianceStatusDueSoon:
+		cc.DueSoon++
+	case ComplianceStatusOverdue:
+		cc.Overdue++
+	case ComplianceStatusExempt:
+		cc.Exempt++
+	}
+}
+
+//ComplianceGroupCounts represents the compliance counts for a category or a county. Empty key means without category or county.
+type ComplianceGroupCounts struct {
+	Key    string           `json:"key"`
+	Name   string           `json:"name"`
+	Counts ComplianceCounts `json:"counts"`
+} // @name ComplianceGroupCounts
+
+//ComplianceSummary represents the compliance dashboard data
+type ComplianceSummary struct {
+	GeneratedAt time.Time               `json:"generated_at"`
+	Total       ComplianceCounts        `json:"total"`
+	ByCategory  []ComplianceGroupCounts `json:"by_category"`
+	ByCounty    []ComplianceGroupCounts `json:"by_county"`
+} // @name ComplianceSummary
@@ -21,4 +21,18 @@ package model
 type COVID19Config struct {
 	Name             string `json:"name" bson:"name"`
 	NewsUpdatePeriod int    `json:"news_update_period" bson:"news_update_period"` //in minutes
+	BroadcastRate    int    `json:"broadcast_rate" bson:"broadcast_rate"`         //users per second, default if not set
+
+	TestingInterval           int `json:"testing_interval" bson:"testing_interval"`                         //in days, for the roster members without uin override interval. 0 - no interval
+	TestingReminderDaysBefore int `json:"testing_reminder_days_before" bson:"testing_reminder_days_before"` //0 - no reminder before the due date
+	TestingOverdueEmailDays   int `json:"testing_overdue_email_days" bson:"testing_overdue_email_days"`     //days after the due date for email escalation. 0 - no escalation
+
+	ExposureExportWindow  int `json:"exposure_export_window" bson:"exposure_export_window"`   //in hours, the exposure keys export files period. default if not set
+	ExposureRetentionDays int `json:"exposure_retention_days" bson:"exposure_retention_days"` //the exposures older than this are purged. default if not set
+
+	ExposureCodeLifetime     int  `json:"exposure_code_lifetime" bson:"exposure_code_lifetime"`           //in minutes, default if not set
+	ExposureCodesHourlyLimit int  `json:"exposure_codes_hourly_limit" bson:"exposure_codes_hourly_limit"` //codes per issuer, default if not set
+	ExposureCodesAutoIssue   bool `json:"exposure_codes_auto_issue" bson:"exposure_codes_auto_issue"`     //issue a code for the ctests marked as positive
+
+	RosterAttributes []RosterAttribute `json:"roster_attributes" bson:"roster_attributes"` //the extra attributes the roster members can have
 }
@@ -0,0 +1,56 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+
+package model
+
+import "time"
+
+const (
+	//ExposureCodeStatusIssued is the status of a code waiting to be exchanged for an upload token
+	ExposureCodeStatusIssued string = "issued"
+	//ExposureCodeStatusClaimed is the status of a code exchanged for an upload token
+	ExposureCodeStatusClaimed string = "claimed"
+	//ExposureCodeStatusUsed is the status of a code which upload token has been used for uploading the keys
+	ExposureCodeStatusUsed string = "used"
+
+	//ExposureCodeSourceAdmin is for the codes issued by the public health staff
+	ExposureCodeSourceAdmin string = "admin"
+	//ExposureCodeSourceCTest is for the codes issued automatically on receiving a test result
+	ExposureCodeSourceCTest string = "ctest"
+)
+
+//ExposureVerificationCode represents a one-time code which authorizes the user to upload exposure keys.
+//Only the hashes of the code and of the upload token are stored.
+type ExposureVerificationCode struct {
+	ID               string     `json:"id" bson:"_id"`
+	CodeHash         string     `json:"-" bson:"code_hash"`
+	ReportType       string     `json:"report_type" bson:"report_type"`
+	TestDate         time.Time  `json:"test_date" bson:"test_date"`
+	SymptomOnsetDate *time.Time `json:"symptom_onset_date" bson:"symptom_onset_date"`
+	UserID           *string    `json:"user_id" bson:"user_id"` //set if the code can be claimed only by a specific user
+	Source           string     `json:"source" bson:"source"`
+	IssuedBy         string     `json:"issued_by" bson:"issued_by"`
+	Status           string     `json:"status" bson:"status"`
+	ExpiresAt        time.Time  `json:"expires_at" bson:"expires_at"`
+
+	TokenHash      *string    `json:"-" bson:"token_hash"`
+	TokenExpiresAt *time.Time `json:"token_expires_at" bson:"token_expires_at"`
+
+	DateCreated time.Time  `json:"date_created" bson:"date_created"`
+	DateClaimed *time.Time `json:"date_claimed" bson:"date_claimed"`
+	DateUsed    *time.Time `json:"date_used" bson:"date_used"`
+} // @name ExposureVerificationCode
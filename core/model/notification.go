@@ -0,0 +1,89 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+
+package model
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	//DefaultNotificationLanguage is used when there is no variant for the user language
+	DefaultNotificationLanguage string = "en"
+
+	//NotificationEventProcessPendingTests is the event for a new test result which the user has to process
+	NotificationEventProcessPendingTests string = "process-pending-tests"
+	//NotificationEventNewResult is the event for a new test result sent by a provider
+	NotificationEventNewResult string = "new-result"
+	//NotificationEventTestingReminder is the event for a test which will be due soon
+	NotificationEventTestingReminder string = "testing-reminder"
+	//NotificationEventTestingDue is the event for a test which is due
+	NotificationEventTestingDue string = "testing-due"
+)
+
+//NotificationTemplate represents the notification content for an event type
+type NotificationTemplate struct {
+	ID          string                        `json:"id" bson:"_id"`
+	Event       string                        `json:"event" bson:"event"`
+	Variants    []NotificationTemplateVariant `json:"variants" bson:"variants"`
+	DateCreated time.Time                     `json:"date_created" bson:"date_created"`
+	DateUpdated *time.Time                    `json:"date_updated" bson:"date_updated"`
+} // @name NotificationTemplate
+
+//GetVariant gives the variant for the language. It falls back to the default language and then to the first variant.
+func (nt NotificationTemplate) GetVariant(language string) *NotificationTemplateVariant {
+	if len(nt.Variants) == 0 {
+		return nil
+	}
+
+	var defaultVariant *NotificationTemplateVariant
+	for i, variant := range nt.Variants {
+		if strings.EqualFold(variant.Language, language) {
+			return &nt.Variants[i]
+		}
+		if strings.EqualFold(variant.Language, DefaultNotificationLanguage) {
+			defaultVariant = &nt.Variants[i]
+		}
+	}
+	if defaultVariant != nil {
+		return defaultVariant
+	}
+	return &nt.Variants[0]
+}
+
+//NotificationTemplateVariant represents the notification content for a language.
+//The title and the body may contain {{name}} placeholders.
+type NotificationTemplateVariant struct {
+	Language string `json:"language" bson:"language" validate:"required"`
+	Title    string `json:"title" bson:"title" validate:"required"`
+	Body     string `json:"body" bson:"body" validate:"required"`
+} // @name NotificationTemplateVariant
+
+//Render gives the title and the body with the placeholders replaced by the params values
+func (ntv NotificationTemplateVariant) Render(params map[string]string) (string, string) {
+	if len(params) == 0 {
+		return ntv.Title, ntv.Body
+	}
+
+	oldNew := make([]string, 0, len(params)*2)
+	for key, value := range params {
+		oldNew = append(oldNew, "{{"+key+"}}", value)
+	}
+	replacer := strings.NewReplacer(oldNew...)
+	return replacer.Replace(ntv.Title), replacer.Replace(ntv.Body)
+}
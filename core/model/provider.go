@@ -17,6 +17,21 @@
 
 package model
 
+import "time"
+
+//the scopes of the provider credentials - which providers APIs besides the own ctests the credentials are accepted for
+const (
+	ProviderScopeUsersRead          = "users.read"           //the users by uin and for re-post
+	ProviderScopeTrackRead          = "track.read"           //the uins by order numbers and the items lists by uins
+	ProviderScopeUINOverridesRead   = "uin-overrides.read"   //the ext uin overrides
+	ProviderScopeUINOverridesWrite  = "uin-overrides.write"  //create, update and delete the ext uin overrides
+	ProviderScopeBuildingAccessRead = "building-access.read" //the ext building access
+)
+
+//ProviderScopes are all the scopes which the provider credentials can have
+var ProviderScopes = []string{ProviderScopeUsersRead, ProviderScopeTrackRead, ProviderScopeUINOverridesRead,
+	ProviderScopeUINOverridesWrite, ProviderScopeBuildingAccessRead}
+
 //Provider represents provider entity
 type Provider struct {
 	ID         string
@@ -59,3 +74,49 @@ type OperationDay struct {
 	OpenTime  string
 	CloseTime string
 }
+
+//ProviderCredential represents an API key given to a provider. Only the key hash is kept, the key is given once on creating.
+type ProviderCredential struct {
+	ID         string `json:"id" bson:"_id"`
+	ProviderID string `json:"provider_id" bson:"provider_id"`
+	KeyHash    string `json:"-" bson:"key_hash"`
+	KeyPrefix  string `json:"key_prefix" bson:"key_prefix"` //the first key characters, so that the provider can recognize the key
+
+	SigningSecret    string `json:"-" bson:"signing_secret"`
+	RequireSignature bool   `json:"require_signature" bson:"require_signature"` //the requests must be signed with the signing secret
+	RateLimit        int    `json:"rate_limit" bson:"rate_limit"`               //requests per minute, 0 for the default limit
+
+	Scopes []string `json:"scopes" bson:"scopes"` //the other providers APIs which the credential can use, the own ctests are always allowed
+
+	DateExpires *time.Time `json:"date_expires" bson:"date_expires"` //set when the credential is rotated or it is given for a period
+	DateRevoked *time.Time `json:"date_revoked" bson:"date_revoked"`
+	DateCreated time.Time  `json:"date_created" bson:"date_created"`
+} // @name ProviderCredential
+
+//IsActive checks if the credential can be used at the moment
+func (c ProviderCredential) IsActive(now time.Time) bool {
+	if c.DateRevoked != nil {
+		return false
+	}
+	return c.DateExpires == nil || now.Before(*c.DateExpires)
+}
+
+//HasScope checks if the credential is accepted for the scope
+func (c ProviderCredential) HasScope(scope string) bool {
+	for _, current := range c.Scopes {
+		if current == scope {
+			return true
+		}
+	}
+	return false
+}
+
+//IsProviderScope checks if the name is of a known provider credential scope
+func IsProviderScope(name string) bool {
+	for _, scope := range ProviderScopes {
+		if scope == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,136 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+
+package model
+
+import "time"
+
+//the permissions which the admin APIs require
+const (
+	PermissionConfigRead                 = "config.read"
+	PermissionConfigWrite                = "config.write"
+	PermissionAppVersionsRead            = "app-versions.read"
+	PermissionAppVersionsWrite           = "app-versions.write"
+	PermissionNewsRead                   = "news.read"
+	PermissionNewsWrite                  = "news.write"
+	PermissionResourcesRead              = "resources.read"
+	PermissionResourcesWrite             = "resources.write"
+	PermissionFAQRead                    = "faq.read"
+	PermissionFAQWrite                   = "faq.write"
+	PermissionProvidersRead              = "providers.read"
+	PermissionProvidersWrite             = "providers.write"
+	PermissionTestTypesRead              = "test-types.read"
+	PermissionTestTypesWrite             = "test-types.write"
+	PermissionCountiesRead               = "counties.read"
+	PermissionCountiesWrite              = "counties.write"
+	PermissionGuidelinesRead             = "guidelines.read"
+	PermissionGuidelinesWrite            = "guidelines.write"
+	PermissionCountyStatusesRead         = "county-statuses.read"
+	PermissionCountyStatusesWrite        = "county-statuses.write"
+	PermissionRulesRead                  = "rules.read"
+	PermissionRulesWrite                 = "rules.write"
+	PermissionCRulesRead                 = "crules.read"
+	PermissionCRulesWrite                = "crules.write"
+	PermissionAccessRulesRead            = "access-rules.read"
+	PermissionAccessRulesWrite           = "access-rules.write"
+	PermissionLocationsRead              = "locations.read"
+	PermissionLocationsWrite             = "locations.write"
+	PermissionSymptomsRead               = "symptoms.read"
+	PermissionSymptomsWrite              = "symptoms.write"
+	PermissionManualTestsRead            = "manual-tests.read"
+	PermissionManualTestsVerify          = "manual-tests.verify"
+	PermissionUINOverridesRead           = "uin-overrides.read"
+	PermissionUINOverridesWrite          = "uin-overrides.write"
+	PermissionRostersRead                = "rosters.read"
+	PermissionRostersWrite               = "rosters.write"
+	PermissionSubAccountsRead            = "sub-accounts.read"
+	PermissionSubAccountsWrite           = "sub-accounts.write"
+	PermissionUsersRead                  = "users.read"
+	PermissionActionsCreate              = "actions.create"
+	PermissionAuditRead                  = "audit.read"
+	PermissionAuditReadAll               = "audit.read-all"
+	PermissionNotificationTemplatesRead  = "notification-templates.read"
+	PermissionNotificationTemplatesWrite = "notification-templates.write"
+	PermissionBroadcastsRead             = "broadcasts.read"
+	PermissionBroadcastsWrite            = "broadcasts.write"
+	PermissionComplianceRead             = "compliance.read"
+	PermissionExposureCodesRead          = "exposure-codes.read"
+	PermissionExposureCodesWrite         = "exposure-codes.write"
+	PermissionExposureMetricsRead        = "exposure-metrics.read"
+	PermissionRolesRead                  = "roles.read"
+	PermissionRolesWrite                 = "roles.write"
+	PermissionAPIKeysRead                = "api-keys.read"
+	PermissionAPIKeysWrite               = "api-keys.write"
+	PermissionTokenMetricsRead           = "token-metrics.read"
+)
+
+//Permissions are all the permissions which the roles can have
+var Permissions = []string{PermissionConfigRead, PermissionConfigWrite, PermissionAppVersionsRead, PermissionAppVersionsWrite,
+	PermissionNewsRead, PermissionNewsWrite, PermissionResourcesRead, PermissionResourcesWrite, PermissionFAQRead, PermissionFAQWrite,
+	PermissionProvidersRead, PermissionProvidersWrite, PermissionTestTypesRead, PermissionTestTypesWrite, PermissionCountiesRead,
+	PermissionCountiesWrite, PermissionGuidelinesRead, PermissionGuidelinesWrite, PermissionCountyStatusesRead, PermissionCountyStatusesWrite,
+	PermissionRulesRead, PermissionRulesWrite, PermissionCRulesRead, PermissionCRulesWrite, PermissionAccessRulesRead, PermissionAccessRulesWrite,
+	PermissionLocationsRead, PermissionLocationsWrite, PermissionSymptomsRead, PermissionSymptomsWrite, PermissionManualTestsRead,
+	PermissionManualTestsVerify, PermissionUINOverridesRead, PermissionUINOverridesWrite, PermissionRostersRead, PermissionRostersWrite,
+	PermissionSubAccountsRead, PermissionSubAccountsWrite, PermissionUsersRead, PermissionActionsCreate, PermissionAuditRead,
+	PermissionAuditReadAll, PermissionNotificationTemplatesRead, PermissionNotificationTemplatesWrite, PermissionBroadcastsRead,
+	PermissionBroadcastsWrite, PermissionComplianceRead, PermissionExposureCodesRead, PermissionExposureCodesWrite,
+	PermissionExposureMetricsRead, PermissionRolesRead, PermissionRolesWrite, PermissionAPIKeysRead, PermissionAPIKeysWrite,
+	PermissionTokenMetricsRead}
+
+//IsPermission checks if the name is of a known permission
+func IsPermission(name string) bool {
+	for _, permission := range Permissions {
+		if permission == name {
+			return true
+		}
+	}
+	return false
+}
+
+//Role represents a set of permissions given to the members of identity provider groups
+type Role struct {
+	ID          string   `json:"id" bson:"_id"`
+	Name        string   `json:"name" bson:"name"`
+	Description string   `json:"description" bson:"description"`
+	Groups      []string `json:"groups" bson:"groups"` //the identity provider groups which members have the role
+	Permissions []string `json:"permissions" bson:"permissions"`
+	Counties    []string `json:"counties" bson:"counties"` //the counties ids to which the county bound permissions are limited, empty for all counties
+
+	DateCreated time.Time  `json:"date_created" bson:"date_created"`
+	DateUpdated *time.Time `json:"date_updated" bson:"date_updated"`
+} // @name Role
+
+//HasGroup checks if the role is given to the group members
+func (r Role) HasGroup(group string) bool {
+	for _, current := range r.Groups {
+		if current == group {
+			return true
+		}
+	}
+	return false
+}
+
+//HasPermission checks if the role gives the permission
+func (r Role) HasPermission(permission string) bool {
+	for _, current := range r.Permissions {
+		if current == permission {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,209 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+
+package model
+
+import "time"
+
+const (
+	//RosterImportMaxErrors is the count of the errors kept in a roster import report
+	RosterImportMaxErrors int = 10000
+	//the longer values are shortened in the errors
+	rosterImportMaxErrorValue int = 100
+)
+
+//Roster represents a roster member. The phone and the uin are unique.
+type Roster struct {
+	Phone      string `json:"phone" bson:"phone"`
+	UIN        string `json:"uin" bson:"uin"`
+	FirstName  string `json:"first_name" bson:"first_name"`
+	MiddleName string `json:"middle_name" bson:"middle_name"`
+	LastName   string `json:"last_name" bson:"last_name"`
+	BirthDate  string `json:"birth_date" bson:"birth_date"`
+	Gender     string `json:"gender" bson:"gender"`
+	Address1   string `json:"address1" bson:"address1"`
+	Address2   string `json:"address2" bson:"address2"`
+	Address3   string `json:"address3" bson:"address3"`
+	City       string `json:"city" bson:"city"`
+	State      string `json:"state" bson:"state"`
+	ZipCode    string `json:"zip_code" bson:"zip_code"`
+	Email      string `json:"email" bson:"email"`
+	BadgeType  string `json:"badge_type" bson:"badge_type"`
+
+	Attributes map[string]string `json:"attributes,omitempty" bson:"attributes,omitempty"` //the extra attributes configured by the admins
+} // @name Roster
+
+//RosterFields are the roster members fields names
+var RosterFields = []string{"phone", "uin", "first_name", "middle_name", "last_name", "birth_date", "gender",
+	"address1", "address2", "address3", "city", "state", "zip_code", "email", "badge_type"}
+
+//IsRosterField checks if the name is of a roster field and not of an extra attribute
+func IsRosterField(name string) bool {
+	for _, field := range RosterFields {
+		if field == name {
+			return true
+		}
+	}
+	return false
+}
+
+//Field gives the value of a field by its name. The names which are not roster fields are for the extra attributes.
+func (r Roster) Field(name string) string {
+	if field := r.field(name); field != nil {
+		return *field
+	}
+	return r.Attributes[name]
+}
+
+//SetField sets the value of a field by its name. The names which are not roster fields are for the extra attributes.
+func (r *Roster) SetField(name string, value string) {
+	if field := r.field(name); field != nil {
+		*field = value
+		return
+	}
+	if r.Attributes == nil {
+		r.Attributes = map[string]string{}
+	}
+	r.Attributes[name] = value
+}
+
+func (r *Roster) field(name string) *string {
+	switch name {
+	case "phone":
+		return &r.Phone
+	case "uin":
+		return &r.UIN
+	case "first_name":
+		return &r.FirstName
+	case "middle_name":
+		return &r.MiddleName
+	case "last_name":
+		return &r.LastName
+	case "birth_date":
+		return &r.BirthDate
+	case "gender":
+		return &r.Gender
+	case "address1":
+		return &r.Address1
+	case "address2":
+		return &r.Address2
+	case "address3":
+		return &r.Address3
+	case "city":
+		return &r.City
+	case "state":
+		return &r.State
+	case "zip_code":
+		return &r.ZipCode
+	case "email":
+		return &r.Email
+	case "badge_type":
+		return &r.BadgeType
+	default:
+		return nil
+	}
+}
+
+//Equal checks if the roster members have the same fields and attributes
+func (r Roster) Equal(other Roster) bool {
+	for _, name := range RosterFields {
+		if r.Field(name) != other.Field(name) {
+			return false
+		}
+	}
+	if len(r.Attributes) != len(other.Attributes) {
+		return false
+	}
+	for name, value := range r.Attributes {
+		if otherValue, ok := other.Attributes[name]; !ok || otherValue != value {
+			return false
+		}
+	}
+	return true
+}
+
+//RosterAttribute represents an extra roster attribute configured by the admins
+type RosterAttribute struct {
+	Name     string `json:"name" bson:"name"`
+	Required bool   `json:"required" bson:"required"`
+	Pattern  string `json:"pattern" bson:"pattern"` //regular expression for the values, any value if empty
+} // @name RosterAttribute
+
+//RosterImport represents the report of a roster file import. The dry run imports only validate the file.
+type RosterImport struct {
+	ID       string `json:"id" bson:"_id"`
+	FileName string `json:"file_name" bson:"file_name"`
+	DryRun   bool   `json:"dry_run" bson:"dry_run"`
+
+	Total      int `json:"total" bson:"total"`
+	Valid      int `json:"valid" bson:"valid"`
+	Invalid    int `json:"invalid" bson:"invalid"`
+	Duplicates int `json:"duplicates" bson:"duplicates"`
+	Created    int `json:"created" bson:"created"`
+
+	Errors      []RosterImportError `json:"errors" bson:"errors"`             //the first errors only, so that the report is not too large
+	ErrorsCount int                 `json:"errors_count" bson:"errors_count"` //all the errors
+	Preview     []Roster            `json:"preview,omitempty" bson:"-"`       //the first valid items in the dry run imports
+
+	CreatedBy   string    `json:"created_by" bson:"created_by"`
+	DateCreated time.Time `json:"date_created" bson:"date_created"`
+} // @name RosterImport
+
+//AddErrors adds the errors to the report. Only the first RosterImportMaxErrors are kept and their values are shortened.
+func (ri *RosterImport) AddErrors(items ...RosterImportError) {
+	for _, item := range items {
+		ri.ErrorsCount++
+		if len(ri.Errors) >= RosterImportMaxErrors {
+			continue
+		}
+		if len(item.Value) > rosterImportMaxErrorValue {
+			item.Value = item.Value[:rosterImportMaxErrorValue] + "..."
+		}
+		ri.Errors = append(ri.Errors, item)
+	}
+}
+
+//RosterImportError represents an invalid or duplicated row in a roster file import
+type RosterImportError struct {
+	Row     int    `json:"row" bson:"row"` //the row number in the file, the header is row 1
+	Field   string `json:"field" bson:"field"`
+	Value   string `json:"value" bson:"value"`
+	Message string `json:"message" bson:"message"`
+} // @name RosterImportError
+
+//RosterSync represents the differences between the roster and the desired roster items. They are applied if it is not a dry run.
+type RosterSync struct {
+	DryRun bool `json:"dry_run"`
+
+	Added     int `json:"added"`
+	Updated   int `json:"updated"`
+	Removed   int `json:"removed"`
+	Unchanged int `json:"unchanged"`
+
+	AddedUINs   []string `json:"added_uins"`
+	UpdatedUINs []string `json:"updated_uins"`
+	RemovedUINs []string `json:"removed_uins"`
+
+	PhoneConflicts []RosterPhoneConflict `json:"phone_conflicts"` //the phones taken from other roster items, the sync applies them together
+} // @name RosterSync
+
+//RosterPhoneConflict represents a roster item phone which is currently held by another roster item
+type RosterPhoneConflict struct {
+	UIN       string `json:"uin"`
+	Phone     string `json:"phone"`
+	HeldByUIN string `json:"held_by_uin"`
+} // @name RosterPhoneConflict
@@ -83,3 +83,14 @@ type UINOverride struct {
 	Activation *time.Time `json:"activation" bson:"activation"`
 	Expiration *time.Time `json:"expiration" bson:"expiration"`
 } // @name UINOverride
+
+//IsActive says if the override is applied at the provided moment
+func (uo UINOverride) IsActive(now time.Time) bool {
+	if uo.Activation != nil && now.Before(*uo.Activation) {
+		return false
+	}
+	if uo.Expiration != nil && !now.Before(*uo.Expiration) {
+		return false
+	}
+	return true
+}
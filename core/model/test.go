@@ -58,3 +58,51 @@ type EManualTest struct {
 	Date        *time.Time
 	DateCreated time.Time
 }
+
+//TestingSubject represents a roster member with the data needed for finding when the next test is due
+type TestingSubject struct {
+	UIN       string
+	FirstName string
+	LastName  string
+	Email     string
+	Phone     string
+
+	AccountID *string //nil if the roster member has not created an account
+	UserUUID  *string
+	CountyID  *string //the county of the latest manual test
+
+	LatestTestDate *time.Time
+	Override       *UINOverride
+}
+
+//GetEffectiveInterval gives the testing interval in days for the provided moment. The uin override interval
+//is used if the override is active, the default interval otherwise. It gives nil when the subject is exempt
+//or there is no interval at all.
+func (ts TestingSubject) GetEffectiveInterval(defaultInterval int, now time.Time) *int {
+	if ts.Override != nil && ts.Override.IsActive(now) {
+		if ts.Override.Exempt != nil && *ts.Override.Exempt {
+			return nil
+		}
+		if ts.Override.Interval != nil && *ts.Override.Interval > 0 {
+			return ts.Override.Interval
+		}
+	}
+	if defaultInterval <= 0 {
+		return nil
+	}
+	return &defaultInterval
+}
+
+//GetDueDate gives when the next test is due. It gives a zero time when the subject has never been tested
+//and nil when the subject does not need to be tested.
+func (ts TestingSubject) GetDueDate(defaultInterval int, now time.Time) *time.Time {
+	interval := ts.GetEffectiveInterval(defaultInterval, now)
+	if interval == nil {
+		return nil
+	}
+	if ts.LatestTestDate == nil {
+		return &time.Time{}
+	}
+	dueDate := ts.LatestTestDate.AddDate(0, 0, *interval)
+	return &dueDate
+}
@@ -17,10 +17,57 @@
 
 package model
 
-//TraceExposure represents contact tracing exposure entity
+import "time"
+
+const (
+	//ExposureReportTypeConfirmed is for keys uploaded after a confirmed test
+	ExposureReportTypeConfirmed string = "confirmed"
+	//ExposureReportTypeLikely is for keys uploaded after a clinical diagnosis
+	ExposureReportTypeLikely string = "likely"
+)
+
+//TraceExposure represents contact tracing exposure entity.
+//The rolling start number, rolling period, transmission risk, report type and days since onset of symptoms
+//are set only for the keys published through the exposure notification (GAEN) publish API.
 type TraceExposure struct {
 	DateAdded   int64  `json:"date_added" bson:"date_added"`
 	Timestamp   int64  `json:"timestamp" bson:"timestamp"`
 	TEK         string `json:"tek" bson:"tek"`
 	Expirestamp *int64 `json:"expirestamp" bson:"expirestamp"`
+
+	RollingStartNumber *int32  `json:"rolling_start_number,omitempty" bson:"rolling_start_number,omitempty"`
+	RollingPeriod      *int32  `json:"rolling_period,omitempty" bson:"rolling_period,omitempty"`
+	TransmissionRisk   *int32  `json:"transmission_risk,omitempty" bson:"transmission_risk,omitempty"`
+	ReportType         *string `json:"report_type,omitempty" bson:"report_type,omitempty"`
+	DaysSinceOnset     *int32  `json:"days_since_onset,omitempty" bson:"days_since_onset,omitempty"`
 } // @name TraceExposure
+
+//TraceExposureCursor represents the position after which the next exposures page starts
+type TraceExposureCursor struct {
+	DateAdded int64  `json:"d"`
+	ID        string `json:"i"`
+	Timestamp *int64 `json:"t,omitempty"` //the timestamp of the first page, the next pages use it too
+}
+
+//ExposureVerification represents the verified data from an exposure keys upload verification certificate
+type ExposureVerification struct {
+	ReportType           string
+	SymptomOnsetInterval *int32
+	ExpiresAt            time.Time //the certificate cannot be used after it
+}
+
+//ExposurePurge represents a trace exposures purge run
+type ExposurePurge struct {
+	Date           time.Time `json:"date" bson:"date"`
+	ExpiredCount   int64     `json:"expired_count" bson:"expired_count"`     //deleted because of their expirestamp
+	RetentionCount int64     `json:"retention_count" bson:"retention_count"` //deleted because they are older than the retention period
+} // @name ExposurePurge
+
+//ExposureMetrics represents the trace exposures storage metrics
+type ExposureMetrics struct {
+	TotalCount    int64           `json:"total_count"`
+	GAENCount     int64           `json:"gaen_count"`
+	RetentionDays int             `json:"retention_days"`
+	PurgedCount   int64           `json:"purged_count"` //for the given purges
+	Purges        []ExposurePurge `json:"purges"`
+} // @name ExposureMetrics
@@ -45,6 +45,20 @@ type User struct {
 	DateUpdated *time.Time `json:"date_updated" bson:"date_updated"`
 }
 
+//UsersCacheMetrics represents the users cache usage since the service start
+type UsersCacheMetrics struct {
+	Size          int   `json:"size"`     //the cached entries - two per user
+	Capacity      int   `json:"capacity"` //the max cached entries
+	TTL           int   `json:"ttl"`      //in seconds
+	Shared        bool  `json:"shared"`   //if the replicas share the cache
+	Hits          int64 `json:"hits"`
+	SharedHits    int64 `json:"shared_hits"`
+	Misses        int64 `json:"misses"`
+	Invalidations int64 `json:"invalidations"`
+	Purges        int64 `json:"purges"`
+	SharedErrors  int64 `json:"shared_errors"`
+} // @name UsersCacheMetrics
+
 //GetAccount gives the user account for the provided account id
 func (user User) GetAccount(accountID string) *Account {
 	if len(user.Accounts) == 0 {
@@ -96,40 +110,6 @@ func (user User) HasDefaultAccount() bool {
 	return false
 }
 
-//IsAdmin says if the user is admin
-func (user User) IsAdmin() bool {
-	if user.ShibbolethAuth == nil {
-		return false
-	}
-	isMemberOfList := user.ShibbolethAuth.IsMemberOf
-	if isMemberOfList == nil {
-		return false
-	}
-	for _, group := range *isMemberOfList {
-		if group == "urn:mace:uiuc.edu:urbana:authman:app-rokwire-service-policy-rokwire admin app" {
-			return true
-		}
-	}
-	return false
-}
-
-//IsPublicHealth says if the user is public health
-func (user User) IsPublicHealth() bool {
-	if user.ShibbolethAuth == nil {
-		return false
-	}
-	isMemberOfList := user.ShibbolethAuth.IsMemberOf
-	if isMemberOfList == nil {
-		return false
-	}
-	for _, group := range *isMemberOfList {
-		if group == "urn:mace:uiuc.edu:urbana:authman:app-rokwire-service-policy-rokwire public health" {
-			return true
-		}
-	}
-	return false
-}
-
 //IsMemberOf says if the user is member of a group
 func (user User) IsMemberOf(group string) bool {
 	if user.ShibbolethAuth == nil {
@@ -209,4 +189,18 @@ type RawSubAccount struct {
 	PrimaryAccount string `json:"primary_account" bson:"primary_account"`
 
 	AccountID *string `json:"account_id" bson:"account_id"`
+
+	LinkStatus      string     `json:"link_status" bson:"link_status"` //pending, accepted, declined or revoked
+	DateLinkUpdated *time.Time `json:"date_link_updated" bson:"date_link_updated"`
 } // @name RawSubAccount
+
+const (
+	//SubAccountLinkPending is the status of a sub account which waits for the primary user to accept or decline it
+	SubAccountLinkPending string = "pending"
+	//SubAccountLinkAccepted is the status of a sub account which has been accepted and linked as an account of the primary user
+	SubAccountLinkAccepted string = "accepted"
+	//SubAccountLinkDeclined is the status of a sub account which has been declined by the primary user
+	SubAccountLinkDeclined string = "declined"
+	//SubAccountLinkRevoked is the status of a sub account which link has been revoked by the admins
+	SubAccountLinkRevoked string = "revoked"
+)
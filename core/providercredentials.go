@@ -0,0 +1,233 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+
+package core
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"health/core/model"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	//the first characters of the generated keys which are kept, so that the keys can be recognized
+	apiKeyPrefixLength = 6
+	//how long the rotated credentials and API keys are still valid if the grace period is not provided
+	defaultGracePeriodHours = 24
+)
+
+//ErrProviderForbidden is given when a provider works with the data of another provider
+var ErrProviderForbidden = errors.New("the provider credential does not allow this provider")
+
+//FindProviderCredential finds the active provider credential for the API key
+func (app *Application) FindProviderCredential(apiKey string) *model.ProviderCredential {
+	app.pcLock.RLock()
+	credential, ok := app.cachedProviderCredentials[hashAPISecret(apiKey)]
+	app.pcLock.RUnlock()
+
+	if !ok || !credential.IsActive(time.Now().UTC()) {
+		return nil
+	}
+	return &credential
+}
+
+func (app *Application) loadProviderCredentials() {
+	log.Println("Load provider credentials")
+
+	credentials, err := app.storage.ReadActiveProviderCredentials()
+	if err != nil {
+		log.Printf("Error reading the provider credentials %s", err)
+		return
+	}
+
+	cached := make(map[string]model.ProviderCredential, len(credentials))
+	for _, credential := range credentials {
+		cached[credential.KeyHash] = credential
+	}
+
+	app.pcLock.Lock()
+	app.cachedProviderCredentials = cached
+	app.pcLock.Unlock()
+}
+
+func (app *Application) getProviderCredentials(providerID string) ([]model.ProviderCredential, error) {
+	return app.storage.FindProviderCredentials(providerID)
+}
+
+//createProviderCredential creates a credential for the provider and gives it with its API key. The key is not kept.
+func (app *Application) createProviderCredential(current model.User, group string, audit *string, providerID string,
+	requireSignature bool, rateLimit int, scopes []string, expires *time.Time) (*model.ProviderCredential, string, error) {
+	provider, err := app.storage.FindProvider(providerID)
+	if err != nil {
+		return nil, "", err
+	}
+	if provider == nil {
+		return nil, "", fmt.Errorf("there is no a provider with id %s", providerID)
+	}
+	if rateLimit < 0 {
+		return nil, "", errors.New("the rate limit cannot be negative")
+	}
+	for _, scope := range scopes {
+		if !model.IsProviderScope(scope) {
+			return nil, "", fmt.Errorf("unknown provider credential scope %s", scope)
+		}
+	}
+
+	credential, key, err := app.newProviderCredential(providerID, requireSignature, rateLimit, scopes, expires)
+	if err != nil {
+		return nil, "", err
+	}
+	err = app.storage.CreateProviderCredential(*credential)
+	if err != nil {
+		return nil, "", err
+	}
+
+	//audit
+	userIdentifier, userInfo := current.GetLogData()
+	lData := []AuditDataEntry{{Key: "providerID", Value: providerID}, {Key: "keyPrefix", Value: credential.KeyPrefix},
+		{Key: "requireSignature", Value: fmt.Sprint(requireSignature)}, {Key: "rateLimit", Value: fmt.Sprint(rateLimit)},
+		{Key: "scopes", Value: strings.Join(scopes, ",")}}
+	err = app.audit.LogCreateEvent(userIdentifier, userInfo, group, "provider-credential", credential.ID, lData, audit)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return credential, key, nil
+}
+
+//rotateProviderCredential creates a new credential with the same settings. The rotated credential is valid during the grace period,
+//so that the provider can move to the new key.
+func (app *Application) rotateProviderCredential(current model.User, group string, audit *string, ID string,
+	gracePeriodHours *int) (*model.ProviderCredential, string, error) {
+	rotated, err := app.storage.FindProviderCredential(ID)
+	if err != nil {
+		return nil, "", err
+	}
+	if rotated == nil {
+		return nil, "", fmt.Errorf("there is no a provider credential with id %s", ID)
+	}
+	now := time.Now().UTC()
+	if !rotated.IsActive(now) {
+		return nil, "", errors.New("the provider credential is not active")
+	}
+	hours := defaultGracePeriodHours
+	if gracePeriodHours != nil {
+		hours = *gracePeriodHours
+	}
+	if hours < 0 {
+		return nil, "", errors.New("the grace period cannot be negative")
+	}
+
+	credential, key, err := app.newProviderCredential(rotated.ProviderID, rotated.RequireSignature, rotated.RateLimit, rotated.Scopes, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	err = app.storage.CreateProviderCredential(*credential)
+	if err != nil {
+		return nil, "", err
+	}
+
+	expires := now.Add(time.Duration(hours) * time.Hour)
+	if rotated.DateExpires == nil || expires.Before(*rotated.DateExpires) {
+		rotated.DateExpires = &expires
+		err = app.storage.UpdateProviderCredential(*rotated)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+
+	//audit
+	userIdentifier, userInfo := current.GetLogData()
+	lData := []AuditDataEntry{{Key: "providerID", Value: rotated.ProviderID}, {Key: "rotatedID", Value: ID},
+		{Key: "keyPrefix", Value: credential.KeyPrefix}, {Key: "rotatedExpires", Value: rotated.DateExpires.Format(time.RFC3339)}}
+	err = app.audit.LogCreateEvent(userIdentifier, userInfo, group, "provider-credential", credential.ID, lData, audit)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return credential, key, nil
+}
+
+func (app *Application) revokeProviderCredential(current model.User, group string, audit *string, ID string) error {
+	credential, err := app.storage.FindProviderCredential(ID)
+	if err != nil {
+		return err
+	}
+	if credential == nil {
+		return fmt.Errorf("there is no a provider credential with id %s", ID)
+	}
+	if credential.DateRevoked != nil {
+		return errors.New("the provider credential is already revoked")
+	}
+
+	now := time.Now().UTC()
+	credential.DateRevoked = &now
+	err = app.storage.UpdateProviderCredential(*credential)
+	if err != nil {
+		return err
+	}
+
+	//audit
+	userIdentifier, userInfo := current.GetLogData()
+	lData := []AuditDataEntry{{Key: "providerID", Value: credential.ProviderID}, {Key: "revoked", Value: "true"}}
+	err = app.audit.LogUpdateEvent(userIdentifier, userInfo, group, "provider-credential", ID, lData, audit)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (app *Application) newProviderCredential(providerID string, requireSignature bool, rateLimit int, scopes []string,
+	expires *time.Time) (*model.ProviderCredential, string, error) {
+	key, err := generateAPISecret()
+	if err != nil {
+		return nil, "", err
+	}
+	signingSecret, err := generateAPISecret()
+	if err != nil {
+		return nil, "", err
+	}
+
+	credential := model.ProviderCredential{ID: uuid.New().String(), ProviderID: providerID, KeyHash: hashAPISecret(key),
+		KeyPrefix: key[:apiKeyPrefixLength], SigningSecret: signingSecret, RequireSignature: requireSignature,
+		RateLimit: rateLimit, Scopes: scopes, DateExpires: expires, DateCreated: time.Now().UTC()}
+	return &credential, key, nil
+}
+
+func generateAPISecret() (string, error) {
+	b := make([]byte, 32)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashAPISecret(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(hash[:])
+}
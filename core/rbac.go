@@ -0,0 +1,288 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+
+package core
+
+import (
+	"errors"
+	"fmt"
+	"health/core/model"
+	"health/utils"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	groupAdminApp       = "urn:mace:uiuc.edu:urbana:authman:app-rokwire-service-policy-rokwire admin app"
+	groupHealthProvider = "urn:mace:uiuc.edu:urbana:authman:app-rokwire-service-policy-rokwire health provider"
+	groupHealthMedia    = "urn:mace:uiuc.edu:urbana:authman:app-rokwire-service-policy-rokwire health media"
+	groupTestVerify     = "urn:mace:uiuc.edu:urbana:authman:app-rokwire-service-policy-rokwire health test verify"
+	groupPublicHealth   = "urn:mace:uiuc.edu:urbana:authman:app-rokwire-service-policy-rokwire public health"
+	groupLocationAdmin  = "urn:mace:uiuc.edu:urbana:authman:app-rokwire-service-policy-rokwire location admin"
+)
+
+//defaultRoles are created when there are no roles. They give the same access as the groups had before the roles.
+var defaultRoles = []model.Role{
+	{Name: "admin", Description: "Full access", Groups: []string{groupAdminApp}, Permissions: model.Permissions},
+	{Name: "health provider", Description: "Manages the test types and the locations", Groups: []string{groupHealthProvider},
+		Permissions: []string{model.PermissionProvidersRead, model.PermissionCountiesRead, model.PermissionCountyStatusesRead,
+			model.PermissionTestTypesRead, model.PermissionTestTypesWrite, model.PermissionRulesRead, model.PermissionAccessRulesRead,
+			model.PermissionLocationsRead, model.PermissionLocationsWrite}},
+	{Name: "health media", Description: "Manages the news, the FAQ and the resources", Groups: []string{groupHealthMedia},
+		Permissions: []string{model.PermissionNewsRead, model.PermissionNewsWrite, model.PermissionFAQRead, model.PermissionFAQWrite,
+			model.PermissionResourcesRead, model.PermissionResourcesWrite}},
+	{Name: "test verify", Description: "Verifies the manual tests", Groups: []string{groupTestVerify},
+		Permissions: []string{model.PermissionManualTestsRead, model.PermissionManualTestsVerify}},
+	{Name: "public health", Description: "Manages the counties, the rules and the users notifications", Groups: []string{groupPublicHealth},
+		Permissions: []string{model.PermissionProvidersRead, model.PermissionUsersRead, model.PermissionActionsCreate,
+			model.PermissionCountiesRead, model.PermissionCountiesWrite, model.PermissionCountyStatusesRead, model.PermissionCountyStatusesWrite,
+			model.PermissionTestTypesRead, model.PermissionTestTypesWrite, model.PermissionRulesRead, model.PermissionRulesWrite,
+			model.PermissionGuidelinesRead, model.PermissionGuidelinesWrite, model.PermissionAccessRulesRead, model.PermissionAccessRulesWrite,
+			model.PermissionManualTestsRead, model.PermissionManualTestsVerify, model.PermissionSymptomsRead, model.PermissionSymptomsWrite,
+			model.PermissionNotificationTemplatesRead, model.PermissionNotificationTemplatesWrite, model.PermissionBroadcastsRead,
+			model.PermissionBroadcastsWrite, model.PermissionComplianceRead, model.PermissionExposureCodesRead, model.PermissionExposureCodesWrite,
+			model.PermissionExposureMetricsRead, model.PermissionAuditRead}},
+	{Name: "location admin", Description: "Manages the locations", Groups: []string{groupLocationAdmin},
+		Permissions: []string{model.PermissionLocationsRead, model.PermissionLocationsWrite, model.PermissionProvidersRead,
+			model.PermissionCountiesRead, model.PermissionTestTypesRead}},
+}
+
+//ErrCountyForbidden is given when the admin works with a county which is out of the counties the admin roles are limited to
+var ErrCountyForbidden = errors.New("the county is out of the admin counties")
+
+//HasPermission checks if the members of the group have the permission by any of their roles
+func (app *Application) HasPermission(group string, permission string) bool {
+	for _, role := range app.getCachedRoles() {
+		if role.HasGroup(group) && role.HasPermission(permission) {
+			return true
+		}
+	}
+	return false
+}
+
+//countiesScope gives the counties for which the group members have the permission. It gives all as true when
+//any of the group roles with the permission is not limited to counties.
+func (app *Application) countiesScope(group string, permission string) (all bool, counties []string) {
+	for _, role := range app.getCachedRoles() {
+		if !role.HasGroup(group) || !role.HasPermission(permission) {
+			continue
+		}
+		if len(role.Counties) == 0 {
+			return true, nil
+		}
+		counties = append(counties, role.Counties...)
+	}
+	return false, counties
+}
+
+//checkCountyScope checks if the group members have the permission for the county
+func (app *Application) checkCountyScope(group string, permission string, countyID string) error {
+	all, counties := app.countiesScope(group, permission)
+	if all || utils.Contains(counties, countyID) {
+		return nil
+	}
+	return ErrCountyForbidden
+}
+
+func (app *Application) loadRoles() {
+	log.Println("Load roles")
+
+	roles, err := app.storage.ReadAllRoles()
+	if err != nil {
+		log.Printf("Error reading the roles %s", err)
+		return
+	}
+	if len(roles) == 0 {
+		roles, err = app.createDefaultRoles()
+		if err != nil {
+			log.Printf("Error creating the default roles %s", err)
+			return
+		}
+	}
+	app.setCachedRoles(roles)
+}
+
+func (app *Application) createDefaultRoles() ([]model.Role, error) {
+	log.Println("Create the default roles")
+
+	now := time.Now().UTC()
+	roles := make([]model.Role, len(defaultRoles))
+	for i, role := range defaultRoles {
+		role.ID = uuid.New().String()
+		role.DateCreated = now
+		roles[i] = role
+	}
+	err := app.storage.CreateRoles(roles)
+	if err != nil {
+		//another instance could have created them
+		return app.storage.ReadAllRoles()
+	}
+	return roles, nil
+}
+
+func (app *Application) setCachedRoles(roles []model.Role) {
+	app.rolesLock.Lock()
+	app.cachedRoles = roles
+	app.rolesLock.Unlock()
+}
+
+func (app *Application) getCachedRoles() []model.Role {
+	app.rolesLock.RLock()
+	defer app.rolesLock.RUnlock()
+
+	return app.cachedRoles
+}
+
+func (app *Application) getRoles() ([]model.Role, error) {
+	return app.storage.ReadAllRoles()
+}
+
+func (app *Application) createRole(current model.User, group string, audit *string, name string, description string,
+	groups []string, permissions []string, counties []string) (*model.Role, error) {
+	err := validateRole(name, permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	role := model.Role{ID: uuid.New().String(), Name: name, Description: description, Groups: groups, Permissions: permissions,
+		Counties: counties, DateCreated: time.Now().UTC()}
+	err = app.storage.CreateRole(role)
+	if err != nil {
+		return nil, err
+	}
+
+	//audit
+	userIdentifier, userInfo := current.GetLogData()
+	lData := []AuditDataEntry{{Key: "name", Value: name}, {Key: "description", Value: description},
+		{Key: "groups", Value: strings.Join(groups, ",")}, {Key: "permissions", Value: strings.Join(permissions, ",")},
+		{Key: "counties", Value: strings.Join(counties, ",")}}
+	err = app.audit.LogCreateEvent(userIdentifier, userInfo, group, "role", role.ID, lData, audit)
+	if err != nil {
+		return nil, err
+	}
+
+	return &role, nil
+}
+
+func (app *Application) updateRole(current model.User, group string, audit *string, ID string, name string, description string,
+	groups []string, permissions []string, counties []string) (*model.Role, error) {
+	err := validateRole(name, permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	role, err := app.storage.FindRole(ID)
+	if err != nil {
+		return nil, err
+	}
+	if role == nil {
+		return nil, fmt.Errorf("there is no a role with id %s", ID)
+	}
+
+	now := time.Now().UTC()
+	role.Name = name
+	role.Description = description
+	role.Groups = groups
+	role.Permissions = permissions
+	role.Counties = counties
+	role.DateUpdated = &now
+	err = app.checkRolesManagement(*role, false)
+	if err != nil {
+		return nil, err
+	}
+
+	err = app.storage.UpdateRole(*role)
+	if err != nil {
+		return nil, err
+	}
+
+	//audit
+	userIdentifier, userInfo := current.GetLogData()
+	lData := []AuditDataEntry{{Key: "name", Value: name}, {Key: "description", Value: description},
+		{Key: "groups", Value: strings.Join(groups, ",")}, {Key: "permissions", Value: strings.Join(permissions, ",")},
+		{Key: "counties", Value: strings.Join(counties, ",")}}
+	err = app.audit.LogUpdateEvent(userIdentifier, userInfo, group, "role", ID, lData, audit)
+	if err != nil {
+		return nil, err
+	}
+
+	return role, nil
+}
+
+func (app *Application) deleteRole(current model.User, group string, ID string) error {
+	role, err := app.storage.FindRole(ID)
+	if err != nil {
+		return err
+	}
+	if role == nil {
+		return fmt.Errorf("there is no a role with id %s", ID)
+	}
+	err = app.checkRolesManagement(*role, true)
+	if err != nil {
+		return err
+	}
+
+	err = app.storage.DeleteRole(ID)
+	if err != nil {
+		return err
+	}
+
+	//audit
+	userIdentifier, userInfo := current.GetLogData()
+	err = app.audit.LogDeleteEvent(userIdentifier, userInfo, group, "role", ID)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+//checkRolesManagement checks that a group can still manage the roles after the role change. The roles limited to counties
+//do not count as they could not give access out of their counties.
+func (app *Application) checkRolesManagement(changed model.Role, deleted bool) error {
+	roles, err := app.storage.ReadAllRoles()
+	if err != nil {
+		return err
+	}
+	for _, role := range roles {
+		if role.ID == changed.ID {
+			if deleted {
+				continue
+			}
+			role = changed
+		}
+		if len(role.Groups) > 0 && len(role.Counties) == 0 && role.HasPermission(model.PermissionRolesWrite) {
+			return nil
+		}
+	}
+	return errors.New("at least one role with groups and not limited to counties must have the roles.write permission")
+}
+
+func validateRole(name string, permissions []string) error {
+	if len(strings.TrimSpace(name)) == 0 {
+		return errors.New("the role name is required")
+	}
+	for _, permission := range permissions {
+		if !model.IsPermission(permission) {
+			return fmt.Errorf("unknown permission %s", permission)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+
+package core
+
+import (
+	"health/core/model"
+	"log"
+	"time"
+)
+
+const (
+	testingReminderTypeUpcoming string = "upcoming"
+	testingReminderTypeDue      string = "due"
+	testingReminderTypeOverdue  string = "overdue"
+)
+
+func (app *Application) setupTestingRemindersTimer() {
+	log.Println("Application -> setupTestingRemindersTimer")
+
+	//check it for first time
+	app.checkTestingReminders()
+
+	//check it every hour, every reminder is recorded so it is sent only once
+	ticker := time.NewTicker(time.Hour)
+	for range ticker.C {
+		app.checkTestingReminders()
+	}
+}
+
+func (app *Application) checkTestingReminders() {
+	log.Println("Application -> checkTestingReminders")
+
+	config := app.getCachedCovid19Config()
+	if config == nil {
+		log.Println("checkTestingReminders -> there is no config")
+		return
+	}
+
+	subjects, err := app.storage.FindTestingSubjects()
+	if err != nil {
+		log.Printf("checkTestingReminders -> error loading the testing subjects - %s", err)
+		return
+	}
+
+	now := time.Now()
+	var overdue []model.TestingSubject
+	for _, subject := range subjects {
+		dueDate := subject.GetDueDate(config.TestingInterval, now)
+		if dueDate == nil {
+			//exempt or without interval
+			continue
+		}
+		reminderType := app.getTestingReminderType(*dueDate, now, config)
+		if reminderType == nil {
+			continue
+		}
+
+		//record it first, this guarantees that it is sent only once
+		created, err := app.storage.CreateTestingReminder(subject.UIN, *dueDate, *reminderType)
+		if err != nil {
+			log.Printf("checkTestingReminders -> error recording a reminder - %s", err)
+			continue
+		}
+		if !created {
+			//already sent
+			continue
+		}
+
+		switch *reminderType {
+		case testingReminderTypeOverdue:
+			overdue = append(overdue, subject)
+		case testingReminderTypeUpcoming:
+			app.sendTestingReminder(subject, model.NotificationEventTestingReminder, *dueDate)
+		case testingReminderTypeDue:
+			app.sendTestingReminder(subject, model.NotificationEventTestingDue, *dueDate)
+		}
+	}
+
+	if len(overdue) > 0 {
+		app.sender.SendForTestingOverdue(overdue)
+	}
+}
+
+func (app *Application) getTestingReminderType(dueDate time.Time, now time.Time, config *model.COVID19Config) *string {
+	var reminderType string
+	if dueDate.IsZero() {
+		//never tested - it is due but we do not escalate as the roster member could be just added
+		reminderType = testingReminderTypeDue
+		return &reminderType
+	}
+
+	if config.TestingOverdueEmailDays > 0 && !now.Before(dueDate.AddDate(0, 0, config.TestingOverdueEmailDays)) {
+		reminderType = testingReminderTypeOverdue
+	} else if !now.Before(dueDate) {
+		reminderType = testingReminderTypeDue
+	} else if config.TestingReminderDaysBefore > 0 && !now.Before(dueDate.AddDate(0, 0, -config.TestingReminderDaysBefore)) {
+		reminderType = testingReminderTypeUpcoming
+	} else {
+		return nil
+	}
+	return &reminderType
+}
+
+func (app *Application) sendTestingReminder(subject model.TestingSubject, event string, dueDate time.Time) {
+	if subject.UserUUID == nil {
+		//the roster member has not an account, there is no where to send it
+		return
+	}
+
+	params := map[string]string{"first_name": subject.FirstName, "last_name": subject.LastName, "due_date": ""}
+	if !dueDate.IsZero() {
+		params["due_date"] = dueDate.Format("January 2")
+	}
+	app.sendUserNotification(*subject.UserUUID, event, params)
+}
@@ -0,0 +1,246 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+
+package core
+
+import (
+	"errors"
+	"fmt"
+	"health/core/model"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	rosterImportPreviewSize = 20
+)
+
+var (
+	e164PhonePattern = regexp.MustCompile(`^\+[1-9][0-9]{1,14}$`)
+	uinPattern       = regexp.MustCompile(`^[0-9]{9}$`)
+
+	//the formatting characters which are allowed in the phones in the files
+	phoneFormatReplacer = strings.NewReplacer(" ", "", "-", "", "(", "", ")", "", ".", "")
+)
+
+func (app *Application) importRoster(current model.User, group string, audit *string, fileName string, rows [][]string,
+	mapping map[string]string, dryRun bool) (*model.RosterImport, error) {
+	if len(rows) < 2 {
+		return nil, errors.New("the file must have a header row and at least one roster row")
+	}
+
+	//1. find the roster field or attribute for every column
+	columns, err := app.mapRosterColumns(rows[0], mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	//2. validate the rows
+	userIdentifier, userInfo := current.GetLogData()
+	report := model.RosterImport{ID: uuid.New().String(), FileName: fileName, DryRun: dryRun, Total: len(rows) - 1,
+		Errors: []model.RosterImportError{}, CreatedBy: userIdentifier, DateCreated: time.Now().UTC()}
+	var items []model.Roster
+	var itemsRows []int
+	phones := map[string]int{}
+	uins := map[string]int{}
+	for i, row := range rows[1:] {
+		rowNumber := i + 2
+		if len(strings.TrimSpace(strings.Join(row, ""))) == 0 {
+			//skip the empty rows
+			report.Total--
+			continue
+		}
+		item, rowErrors := app.constructRosterItem(rowNumber, row, columns)
+		if len(rowErrors) > 0 {
+			report.Invalid++
+			report.AddErrors(rowErrors...)
+			continue
+		}
+
+		//duplicates in the file
+		if firstRow, ok := phones[item.Phone]; ok {
+			report.Duplicates++
+			report.AddErrors(model.RosterImportError{Row: rowNumber, Field: "phone", Value: item.Phone,
+				Message: fmt.Sprintf("duplicates row %d", firstRow)})
+			continue
+		}
+		if firstRow, ok := uins[item.UIN]; ok {
+			report.Duplicates++
+			report.AddErrors(model.RosterImportError{Row: rowNumber, Field: "uin", Value: item.UIN,
+				Message: fmt.Sprintf("duplicates row %d", firstRow)})
+			continue
+		}
+		phones[item.Phone] = rowNumber
+		uins[item.UIN] = rowNumber
+
+		items = append(items, item)
+		itemsRows = append(itemsRows, rowNumber)
+	}
+
+	//3. check for duplicates in the roster
+	items, err = app.removeExistingRosterItems(items, itemsRows, &report)
+	if err != nil {
+		return nil, err
+	}
+	report.Valid = len(items)
+
+	//4. create the items with the report for downloading the errors
+	var created []model.Roster
+	if dryRun {
+		if len(items) > rosterImportPreviewSize {
+			report.Preview = items[:rosterImportPreviewSize]
+		} else {
+			report.Preview = items
+		}
+	} else {
+		created = items
+		report.Created = len(items)
+	}
+	err = app.storage.CreateRosterImport(report, created)
+	if err != nil {
+		return nil, err
+	}
+
+	//audit
+	if !dryRun {
+		lData := []AuditDataEntry{{Key: "fileName", Value: fileName}, {Key: "total", Value: strconv.Itoa(report.Total)},
+			{Key: "created", Value: strconv.Itoa(report.Created)}, {Key: "errors", Value: strconv.Itoa(report.ErrorsCount)}}
+		err = app.audit.LogCreateEvent(userIdentifier, userInfo, group, "roster-import", report.ID, lData, audit)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &report, nil
+}
+
+func (app *Application) getRosterImport(ID string) (*model.RosterImport, error) {
+	return app.storage.FindRosterImport(ID)
+}
+
+//removeExistingRosterItems removes the items which phones or uins are already in the roster and reports them as duplicates
+func (app *Application) removeExistingRosterItems(items []model.Roster, itemsRows []int, report *model.RosterImport) ([]model.Roster, error) {
+	if len(items) == 0 {
+		return items, nil
+	}
+
+	phones := make([]string, len(items))
+	uins := make([]string, len(items))
+	for i, item := range items {
+		phones[i] = item.Phone
+		uins[i] = item.UIN
+	}
+	existing, err := app.storage.FindRostersByPhonesOrUINs(phones, uins)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) == 0 {
+		return items, nil
+	}
+
+	existingPhones := map[string]bool{}
+	existingUINs := map[string]bool{}
+	for _, item := range existing {
+		existingPhones[item.Phone] = true
+		existingUINs[item.UIN] = true
+	}
+
+	var result []model.Roster
+	for i, item := range items {
+		if existingPhones[item.Phone] {
+			report.Duplicates++
+			report.AddErrors(model.RosterImportError{Row: itemsRows[i], Field: "phone", Value: item.Phone,
+				Message: "already in the roster"})
+			continue
+		}
+		if existingUINs[item.UIN] {
+			report.Duplicates++
+			report.AddErrors(model.RosterImportError{Row: itemsRows[i], Field: "uin", Value: item.UIN,
+				Message: "already in the roster"})
+			continue
+		}
+		result = append(result, item)
+	}
+	return result, nil
+}
+
+//mapRosterColumns gives the roster field or attribute for every file column, empty for the columns which are not imported.
+//The headers are used as fields names if there is no mapping.
+func (app *Application) mapRosterColumns(header []string, mapping map[string]string) ([]string, error) {
+	attributes := map[string]bool{}
+	if config := app.getCachedCovid19Config(); config != nil {
+		for _, attribute := range config.RosterAttributes {
+			attributes[attribute.Name] = true
+		}
+	}
+
+	columns := make([]string, len(header))
+	mapped := map[string]bool{}
+	for i, name := range header {
+		name = strings.TrimSpace(name)
+
+		var field string
+		if len(mapping) > 0 {
+			field = mapping[name]
+		} else {
+			field = strings.ReplaceAll(strings.ToLower(name), " ", "_")
+		}
+		if len(field) == 0 {
+			continue
+		}
+
+		if !model.IsRosterField(field) && !attributes[field] {
+			return nil, fmt.Errorf("column %s is mapped to unknown roster field %s", name, field)
+		}
+		if mapped[field] {
+			return nil, fmt.Errorf("more than one column is mapped to roster field %s", field)
+		}
+		mapped[field] = true
+		columns[i] = field
+	}
+
+	if !mapped["phone"] || !mapped["uin"] {
+		return nil, errors.New("phone and uin columns are required")
+	}
+	return columns, nil
+}
+
+//constructRosterItem gives the roster item for a file row and the row validation errors
+func (app *Application) constructRosterItem(rowNumber int, row []string, columns []string) (model.Roster, []model.RosterImportError) {
+	var item model.Roster
+	for i, field := range columns {
+		if len(field) == 0 || i >= len(row) {
+			continue
+		}
+		value := strings.TrimSpace(row[i])
+		if len(value) == 0 && !model.IsRosterField(field) {
+			//the empty attributes are missing
+			continue
+		}
+		item.SetField(field, value)
+	}
+
+	rowErrors := app.checkRoster(&item, true)
+	for i := range rowErrors {
+		rowErrors[i].Row = rowNumber
+	}
+	return item, rowErrors
+}
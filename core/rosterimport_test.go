@@ -0,0 +1,262 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+
+package core
+
+import (
+	"health/core/model"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+//fakeRosterStorage keeps the roster in memory, the other storage methods are not implemented
+type fakeRosterStorage struct {
+	Storage
+
+	rosters []model.Roster
+
+	rosterImport *model.RosterImport
+	created      []model.Roster
+
+	synced  bool
+	added   []model.Roster
+	updated []model.Roster
+	removed []string
+}
+
+func (s *fakeRosterStorage) ReadAllRosters() ([]model.Roster, error) {
+	return s.rosters, nil
+}
+
+func (s *fakeRosterStorage) FindRostersByPhonesOrUINs(phones []string, uins []string) ([]model.Roster, error) {
+	var result []model.Roster
+	for _, roster := range s.rosters {
+		for _, phone := range phones {
+			if roster.Phone == phone {
+				result = append(result, roster)
+			}
+		}
+		for _, uin := range uins {
+			if roster.UIN == uin {
+				result = append(result, roster)
+			}
+		}
+	}
+	return result, nil
+}
+
+func (s *fakeRosterStorage) CreateRosterImport(item model.RosterImport, items []model.Roster) error {
+	s.rosterImport = &item
+	s.created = items
+	return nil
+}
+
+func (s *fakeRosterStorage) SyncRosters(added []model.Roster, updated []model.Roster, removedUINs []string) error {
+	s.synced = true
+	s.added = added
+	s.updated = updated
+	s.removed = removedUINs
+	return nil
+}
+
+//fakeAudit counts the logged events, the other audit methods are not implemented
+type fakeAudit struct {
+	Audit
+
+	events int
+}
+
+func (a *fakeAudit) LogCreateEvent(userIdentifier string, userInfo string, usedGroup string, entity string, entityID string, data []AuditDataEntry, clientData *string) error {
+	a.events++
+	return nil
+}
+
+func (a *fakeAudit) LogUpdateEvent(userIdentifier string, userInfo string, usedGroup string, entity string, entityID string, data []AuditDataEntry, clientData *string) error {
+	a.events++
+	return nil
+}
+
+func newRosterTestApplication(rosters []model.Roster, attributes []model.RosterAttribute) (*Application, *fakeRosterStorage, *fakeAudit) {
+	storage := &fakeRosterStorage{rosters: rosters}
+	audit := &fakeAudit{}
+	app := NewApplication("1.0.0", "1", nil, nil, nil, nil, nil, nil, storage, audit, nil, 0, time.Minute)
+	app.cachedCovid19Config = &model.COVID19Config{RosterAttributes: attributes}
+	return app, storage, audit
+}
+
+func TestImportRoster(t *testing.T) {
+	existing := []model.Roster{{Phone: "+12175550000", UIN: "000000000"}}
+	attributes := []model.RosterAttribute{{Name: "department", Required: true}, {Name: "building", Pattern: "^[A-Z]{3}$"}}
+
+	tests := []struct {
+		name    string
+		rows    [][]string
+		mapping map[string]string
+		dryRun  bool
+		wantErr string
+
+		total, valid, invalid, duplicates, created int
+		errors                                     []model.RosterImportError
+		createdUINs                                []string
+	}{
+		{
+			name:    "no rows",
+			rows:    [][]string{{"phone", "uin"}},
+			wantErr: "at least one roster row",
+		},
+		{
+			name:    "no uin column",
+			rows:    [][]string{{"phone", "department"}, {"+12175551111", "IT"}},
+			wantErr: "phone and uin columns are required",
+		},
+		{
+			name:    "unknown column",
+			rows:    [][]string{{"phone", "uin", "department", "favorite color"}, {"+12175551111", "111111111", "IT", "red"}},
+			wantErr: "unknown roster field favorite_color",
+		},
+		{
+			name:    "two columns mapped to the same field",
+			rows:    [][]string{{"Mobile", "Cell", "NetID"}, {"+12175551111", "+12175551112", "111111111"}},
+			mapping: map[string]string{"Mobile": "phone", "Cell": "phone", "NetID": "uin"},
+			wantErr: "more than one column",
+		},
+		{
+			name: "valid rows with formatted phones and skipped empty rows",
+			rows: [][]string{{"Phone", "UIN", "Department", "Building"},
+				{"+1 (217) 555-1111", "111111111", "IT", "SIB"},
+				{"", " ", "", ""},
+				{"+1.217.555.2222", "222222222", "HR", ""}},
+			total: 2, valid: 2, created: 2, createdUINs: []string{"111111111", "222222222"},
+		},
+		{
+			name:    "mapping ignores the not mapped columns",
+			rows:    [][]string{{"Mobile", "NetID", "Dept", "Notes"}, {"+12175551111", "111111111", "IT", "anything"}},
+			mapping: map[string]string{"Mobile": "phone", "NetID": "uin", "Dept": "department"},
+			total:   1, valid: 1, created: 1, createdUINs: []string{"111111111"},
+		},
+		{
+			name: "invalid rows",
+			rows: [][]string{{"phone", "uin", "department", "building"},
+				{"2175551111", "111111111", "IT", ""},
+				{"+12175552222", "2222", "IT", ""},
+				{"+12175553333", "333333333", "", ""},
+				{"+12175554444", "444444444", "IT", "sib"}},
+			total: 4, invalid: 4,
+			errors: []model.RosterImportError{
+				{Row: 2, Field: "phone", Value: "2175551111", Message: "the phone must be in the E.164 format"},
+				{Row: 3, Field: "uin", Value: "2222", Message: "the uin must have 9 digits"},
+				{Row: 4, Field: "department", Message: "the attribute is required"},
+				{Row: 5, Field: "building", Value: "sib", Message: "the attribute must match ^[A-Z]{3}$"}},
+		},
+		{
+			name: "duplicates in the file and in the roster",
+			rows: [][]string{{"phone", "uin", "department"},
+				{"+12175551111", "111111111", "IT"},
+				{"+12175551111", "222222222", "IT"},
+				{"+12175553333", "111111111", "IT"},
+				{"+12175550000", "444444444", "IT"},
+				{"+12175555555", "000000000", "IT"}},
+			total: 5, valid: 1, duplicates: 4, created: 1, createdUINs: []string{"111111111"},
+			errors: []model.RosterImportError{
+				{Row: 3, Field: "phone", Value: "+12175551111", Message: "duplicates row 2"},
+				{Row: 4, Field: "uin", Value: "111111111", Message: "duplicates row 2"},
+				{Row: 5, Field: "phone", Value: "+12175550000", Message: "already in the roster"},
+				{Row: 6, Field: "uin", Value: "000000000", Message: "already in the roster"}},
+		},
+		{
+			name:   "dry run",
+			rows:   [][]string{{"phone", "uin", "department"}, {"+12175551111", "111111111", "IT"}},
+			dryRun: true,
+			total:  1, valid: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app, storage, audit := newRosterTestApplication(existing, attributes)
+
+			report, err := app.importRoster(model.User{}, "group", nil, "roster.csv", tt.rows, tt.mapping, tt.dryRun)
+			if len(tt.wantErr) > 0 {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("importRoster() error = %v, expected %s", err, tt.wantErr)
+				}
+				if storage.rosterImport != nil {
+					t.Errorf("importRoster() created a report on error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("importRoster() error = %v", err)
+			}
+
+			if report.Total != tt.total || report.Valid != tt.valid || report.Invalid != tt.invalid ||
+				report.Duplicates != tt.duplicates || report.Created != tt.created {
+				t.Errorf("importRoster() counts - total %d, valid %d, invalid %d, duplicates %d, created %d",
+					report.Total, report.Valid, report.Invalid, report.Duplicates, report.Created)
+			}
+			expectedErrors := tt.errors
+			if expectedErrors == nil {
+				expectedErrors = []model.RosterImportError{}
+			}
+			if !reflect.DeepEqual(report.Errors, expectedErrors) || report.ErrorsCount != len(expectedErrors) {
+				t.Errorf("importRoster() errors = %v, expected %v", report.Errors, expectedErrors)
+			}
+
+			//the report is created with the items
+			if storage.rosterImport == nil || storage.rosterImport.ID != report.ID {
+				t.Fatalf("importRoster() did not create the report")
+			}
+			var createdUINs []string
+			for _, item := range storage.created {
+				createdUINs = append(createdUINs, item.UIN)
+			}
+			if !reflect.DeepEqual(createdUINs, tt.createdUINs) {
+				t.Errorf("importRoster() created %v, expected %v", createdUINs, tt.createdUINs)
+			}
+			if tt.dryRun && (len(report.Preview) != tt.valid || audit.events != 0) {
+				t.Errorf("importRoster() dry run - preview %d, audit events %d", len(report.Preview), audit.events)
+			}
+			if !tt.dryRun && audit.events != 1 {
+				t.Errorf("importRoster() audit events %d", audit.events)
+			}
+		})
+	}
+}
+
+func TestImportRosterErrorsLimit(t *testing.T) {
+	app, storage, _ := newRosterTestApplication(nil, nil)
+
+	rows := [][]string{{"phone", "uin"}}
+	for i := 0; i < model.RosterImportMaxErrors+10; i++ {
+		rows = append(rows, []string{"bad phone " + strings.Repeat("1", 200), "111111111"})
+	}
+
+	report, err := app.importRoster(model.User{}, "group", nil, "roster.csv", rows, nil, false)
+	if err != nil {
+		t.Fatalf("importRoster() error = %v", err)
+	}
+	if len(report.Errors) != model.RosterImportMaxErrors || report.ErrorsCount != model.RosterImportMaxErrors+10 {
+		t.Errorf("importRoster() errors - kept %d, count %d", len(report.Errors), report.ErrorsCount)
+	}
+	if len(report.Errors[0].Value) > 110 {
+		t.Errorf("importRoster() error value is not shortened - %d", len(report.Errors[0].Value))
+	}
+	if storage.rosterImport == nil || len(storage.created) != 0 {
+		t.Errorf("importRoster() created %d items", len(storage.created))
+	}
+}
@@ -0,0 +1,121 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+
+package core
+
+import (
+	"fmt"
+	"health/core/model"
+	"strconv"
+)
+
+func (app *Application) syncRosters(current model.User, group string, audit *string, items []model.Roster, dryRun bool) (*model.RosterSync, error) {
+	//1. validate the desired items
+	desired, err := app.validateRosterSyncItems(items)
+	if err != nil {
+		return nil, err
+	}
+
+	//2. find the differences
+	rosters, err := app.storage.ReadAllRosters()
+	if err != nil {
+		return nil, err
+	}
+	sync := model.RosterSync{DryRun: dryRun, AddedUINs: []string{}, UpdatedUINs: []string{}, RemovedUINs: []string{},
+		PhoneConflicts: []model.RosterPhoneConflict{}}
+	phoneHolders := make(map[string]string, len(rosters))
+	for _, roster := range rosters {
+		phoneHolders[roster.Phone] = roster.UIN
+	}
+	var added []model.Roster
+	var updated []model.Roster
+	for _, roster := range rosters {
+		uin := roster.UIN
+		item, ok := desired[uin]
+		if !ok {
+			sync.RemovedUINs = append(sync.RemovedUINs, uin)
+			continue
+		}
+		if roster.Equal(item) {
+			sync.Unchanged++
+		} else {
+			updated = append(updated, item)
+			sync.UpdatedUINs = append(sync.UpdatedUINs, uin)
+		}
+		delete(desired, uin)
+	}
+	for _, item := range items {
+		if _, ok := desired[item.UIN]; ok {
+			added = append(added, item)
+			sync.AddedUINs = append(sync.AddedUINs, item.UIN)
+		}
+	}
+	for _, changed := range [][]model.Roster{added, updated} {
+		for _, item := range changed {
+			if holder, ok := phoneHolders[item.Phone]; ok && holder != item.UIN {
+				sync.PhoneConflicts = append(sync.PhoneConflicts, model.RosterPhoneConflict{UIN: item.UIN, Phone: item.Phone, HeldByUIN: holder})
+			}
+		}
+	}
+	sync.Added = len(added)
+	sync.Updated = len(updated)
+	sync.Removed = len(sync.RemovedUINs)
+
+	if dryRun || (sync.Added == 0 && sync.Updated == 0 && sync.Removed == 0) {
+		return &sync, nil
+	}
+
+	//3. apply them
+	err = app.storage.SyncRosters(added, updated, sync.RemovedUINs)
+	if err != nil {
+		return nil, err
+	}
+
+	//audit
+	userIdentifier, userInfo := current.GetLogData()
+	lData := []AuditDataEntry{{Key: "added", Value: strconv.Itoa(sync.Added)}, {Key: "updated", Value: strconv.Itoa(sync.Updated)},
+		{Key: "removed", Value: strconv.Itoa(sync.Removed)}, {Key: "removedUINs", Value: fmt.Sprintf("%s", sync.RemovedUINs)}}
+	err = app.audit.LogUpdateEvent(userIdentifier, userInfo, group, "roster", "sync", lData, audit)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sync, nil
+}
+
+//validateRosterSyncItems normalizes and validates the items and gives them by uin
+func (app *Application) validateRosterSyncItems(items []model.Roster) (map[string]model.Roster, error) {
+	result := make(map[string]model.Roster, len(items))
+	phones := make(map[string]bool, len(items))
+	for i := range items {
+		err := app.validateRoster(&items[i], true)
+		if err != nil {
+			return nil, fmt.Errorf("item %d: %s", i, err)
+		}
+
+		item := items[i]
+		if _, ok := result[item.UIN]; ok {
+			return nil, fmt.Errorf("item %d uin %s is duplicated", i, item.UIN)
+		}
+		if phones[item.Phone] {
+			return nil, fmt.Errorf("item %d phone %s is duplicated", i, item.Phone)
+		}
+		result[item.UIN] = item
+		phones[item.Phone] = true
+	}
+	return result, nil
+}
@@ -0,0 +1,148 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+
+package core
+
+import (
+	"health/core/model"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSyncRosters(t *testing.T) {
+	rosters := []model.Roster{
+		{Phone: "+12175551111", UIN: "111111111", FirstName: "John"},
+		{Phone: "+12175552222", UIN: "222222222", FirstName: "Jane", Attributes: map[string]string{"department": "IT"}},
+		{Phone: "+12175553333", UIN: "333333333"},
+	}
+
+	tests := []struct {
+		name    string
+		items   []model.Roster
+		dryRun  bool
+		wantErr string
+
+		added, updated, removed []string
+		unchanged               int
+		conflicts               []model.RosterPhoneConflict
+		applied                 bool
+	}{
+		{
+			name: "no changes",
+			items: []model.Roster{
+				{Phone: "+1 217 555 3333", UIN: "333333333"},
+				{Phone: "+12175551111", UIN: "111111111", FirstName: "John"},
+				{Phone: "+12175552222", UIN: "222222222", FirstName: "Jane", Attributes: map[string]string{"department": "IT"}},
+			},
+			added: []string{}, updated: []string{}, removed: []string{}, unchanged: 3,
+		},
+		{
+			name: "added, updated and removed",
+			items: []model.Roster{
+				{Phone: "+12175551111", UIN: "111111111", FirstName: "Johnny"},
+				{Phone: "+12175552222", UIN: "222222222", FirstName: "Jane", Attributes: map[string]string{"department": "HR"}},
+				{Phone: "+12175554444", UIN: "444444444"},
+			},
+			added: []string{"444444444"}, updated: []string{"111111111", "222222222"}, removed: []string{"333333333"}, applied: true,
+		},
+		{
+			name: "dry run",
+			items: []model.Roster{
+				{Phone: "+12175551111", UIN: "111111111", FirstName: "John"},
+				{Phone: "+12175554444", UIN: "444444444"},
+			},
+			dryRun: true,
+			added:  []string{"444444444"}, updated: []string{}, removed: []string{"222222222", "333333333"}, unchanged: 1,
+		},
+		{
+			name: "phone taken from another item",
+			items: []model.Roster{
+				{Phone: "+12175553333", UIN: "111111111", FirstName: "John"},
+				{Phone: "+12175551111", UIN: "444444444"},
+			},
+			added: []string{"444444444"}, updated: []string{"111111111"}, removed: []string{"222222222", "333333333"},
+			conflicts: []model.RosterPhoneConflict{{UIN: "444444444", Phone: "+12175551111", HeldByUIN: "111111111"},
+				{UIN: "111111111", Phone: "+12175553333", HeldByUIN: "333333333"}},
+			applied: true,
+		},
+		{
+			name:    "duplicated uin",
+			items:   []model.Roster{{Phone: "+12175551111", UIN: "111111111"}, {Phone: "+12175554444", UIN: "111111111"}},
+			wantErr: "uin 111111111 is duplicated",
+		},
+		{
+			name:    "duplicated phone",
+			items:   []model.Roster{{Phone: "+12175551111", UIN: "111111111"}, {Phone: "+1 217 555 1111", UIN: "444444444"}},
+			wantErr: "phone +12175551111 is duplicated",
+		},
+		{
+			name:    "invalid item",
+			items:   []model.Roster{{Phone: "+12175551111", UIN: "1111"}},
+			wantErr: "item 0: uin 1111",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app, storage, audit := newRosterTestApplication(rosters, []model.RosterAttribute{{Name: "department"}})
+
+			sync, err := app.syncRosters(model.User{}, "group", nil, tt.items, tt.dryRun)
+			if len(tt.wantErr) > 0 {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("syncRosters() error = %v, expected %s", err, tt.wantErr)
+				}
+				if storage.synced {
+					t.Errorf("syncRosters() applied the changes on error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("syncRosters() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(sync.AddedUINs, tt.added) || !reflect.DeepEqual(sync.UpdatedUINs, tt.updated) ||
+				!reflect.DeepEqual(sync.RemovedUINs, tt.removed) {
+				t.Errorf("syncRosters() added %v, updated %v, removed %v", sync.AddedUINs, sync.UpdatedUINs, sync.RemovedUINs)
+			}
+			if sync.Added != len(tt.added) || sync.Updated != len(tt.updated) || sync.Removed != len(tt.removed) || sync.Unchanged != tt.unchanged {
+				t.Errorf("syncRosters() counts - added %d, updated %d, removed %d, unchanged %d", sync.Added, sync.Updated, sync.Removed, sync.Unchanged)
+			}
+			expectedConflicts := tt.conflicts
+			if expectedConflicts == nil {
+				expectedConflicts = []model.RosterPhoneConflict{}
+			}
+			if !reflect.DeepEqual(sync.PhoneConflicts, expectedConflicts) {
+				t.Errorf("syncRosters() phone conflicts %v, expected %v", sync.PhoneConflicts, expectedConflicts)
+			}
+
+			//the changes are applied at once
+			if storage.synced != tt.applied {
+				t.Fatalf("syncRosters() applied %t, expected %t", storage.synced, tt.applied)
+			}
+			if tt.applied {
+				if len(storage.added) != len(tt.added) || len(storage.updated) != len(tt.updated) || !reflect.DeepEqual(storage.removed, tt.removed) {
+					t.Errorf("syncRosters() applied - added %d, updated %d, removed %v", len(storage.added), len(storage.updated), storage.removed)
+				}
+				if audit.events != 1 {
+					t.Errorf("syncRosters() audit events %d", audit.events)
+				}
+			} else if audit.events != 0 {
+				t.Errorf("syncRosters() audit events %d", audit.events)
+			}
+		})
+	}
+}
@@ -114,36 +114,27 @@ func (app *Application) getCTests(account model.Account, processed bool) ([]*mod
 	return ctests, providers, nil
 }
 
-func (app *Application) createExternalCTest(providerID string, uin string, encryptedKey string, encryptedBlob string, orderNumber *string) error {
-	//1. create a ctest
+func (app *Application) createExternalCTest(credential *model.ProviderCredential, providerID string, uin string, encryptedKey string, encryptedBlob string, orderNumber *string, positive bool) error {
+	//1. the provider credentials can create ctests only for their provider. The shared keys are not bound to a provider,
+	//they are accepted until their sunset
+	if credential != nil && credential.ProviderID != providerID {
+		return ErrProviderForbidden
+	}
+
+	//2. create a ctest
 	_, user, err := app.storage.CreateExternalCTest(providerID, uin, encryptedKey, encryptedBlob, false, orderNumber)
 	if err != nil {
 		return err
 	}
 
-	//2. send a firebase notification to the user that the ctest is arrived.
-	go func(userUUID string) {
-		if len(userUUID) <= 0 {
-			log.Println("user uuid is empty")
-			return
-		}
-		//1. load the user data, we need the fcm tokens
-		userData, err := app.profileBB.LoadUserData(user.UUID)
-		if err != nil {
-			log.Printf("Error loading user data - %s\n", err)
-			return
-		}
+	//3. issue an exposure verification code for the user if enabled and the provider marked the result as positive
+	verificationCode, err := app.issueCTestExposureVerificationCode(user.ID, providerID, positive, "provider:"+providerID)
+	if err != nil {
+		log.Printf("Error issuing an exposure verification code for a ctest - %s\n", err)
+	}
 
-		//2. send notification message
-		//2.1 prepare the data
-		data := make(map[string]string)
-		data["type"] = "health.covid19.notification"
-		data["health.covid19.notification.type"] = "process-pending-tests"
-		data["title"] = "COVID-19"
-		data["body"] = "You have received a COVID-19 update"
-		data["click_action"] = "FLUTTER_NOTIFICATION_CLICK"
-		app.messaging.SendNotificationMessage(userData.FCMTokens, "COVID-19", "You have received a COVID-19 update", data)
-	}(user.UUID)
+	//4. send a notification to the user that the ctest is arrived.
+	go app.sendCTestNotification(user.UUID, model.NotificationEventNewResult, providerID, verificationCode)
 
 	return nil
 }
@@ -360,22 +351,74 @@ func (app *Application) getAccessRuleByCounty(countyID string) (*model.AccessRul
 	return accessRule, countyStatuses, nil
 }
 
-func (app *Application) аddTraceReport(items []model.TraceExposure) (int, error) {
-	insertedCount, err := app.storage.CreateTraceReports(items)
+func (app *Application) аddTraceReport(items []model.TraceExposure, uploadToken string) (int, error) {
+	//the upload must be authorized by a token given for a verification code, the token is used only if the reports are created
+	code, insertedCount, err := app.storage.CreateTraceReportsWithUploadToken(hashExposureSecret(uploadToken), items)
 	if err != nil {
 		return 0, err
 	}
+	if code == nil {
+		return 0, ErrUploadTokenNotValid
+	}
 	return insertedCount, nil
 }
 
 func (app *Application) getExposures(timestamp *int64, dateAdded *int64) ([]model.TraceExposure, error) {
-	items, err := app.storage.ReadTraceExposures(timestamp, dateAdded)
+	//do not give the exposures which are going to be purged
+	retentionFrom := toMilliseconds(time.Now().Add(-app.getExposureRetention()))
+	if timestamp == nil || *timestamp < retentionFrom {
+		timestamp = &retentionFrom
+	}
+
+	items, err := app.storage.ReadTraceExposures(timestamp, dateAdded, toMilliseconds(time.Now()))
 	if err != nil {
 		return nil, err
 	}
 	return items, nil
 }
 
+func (app *Application) getExposuresPage(timestamp *int64, cursor *string, limit int) ([]model.TraceExposure, string, error) {
+	var after *model.TraceExposureCursor
+	if cursor != nil {
+		if timestamp != nil {
+			return nil, "", errors.New("the timestamp cannot be used with a cursor, the cursor keeps the first page timestamp")
+		}
+		decoded, err := decodeExposuresCursor(*cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		after = decoded
+		timestamp = decoded.Timestamp
+	}
+	requestedTimestamp := timestamp
+
+	//do not give the exposures which are going to be purged
+	now := time.Now()
+	retentionFrom := toMilliseconds(now.Add(-app.getExposureRetention()))
+	if timestamp == nil || *timestamp < retentionFrom {
+		timestamp = &retentionFrom
+	}
+
+	//the exposures added in the last moments could be still inserted with an earlier date added, so they are given
+	//after the lag. Otherwise the cursor could move after them and they would be never downloaded.
+	addedBefore := toMilliseconds(now.Add(-exposuresPageLag))
+	items, last, err := app.storage.ReadTraceExposuresPage(timestamp, after, addedBefore, toMilliseconds(now), int64(limit))
+	if err != nil {
+		return nil, "", err
+	}
+
+	//the client keeps the last cursor for downloading only the new exposures next time
+	nextCursor := ""
+	if last != nil {
+		last.Timestamp = requestedTimestamp
+		nextCursor, err = encodeExposuresCursor(*last)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+	return items, nextCursor, nil
+}
+
 func (app *Application) getUINOverride(account model.Account, v2 bool) (*model.UINOverride, error) {
 	//supported only for Shibboleth users - uin
 	uin := account.ExternalID
@@ -504,7 +547,7 @@ func (app *Application) getSymptoms(appVersion *string) (*model.Symptoms, error)
 	return symptoms, nil
 }
 
-func (app *Application) getRosterByPhone(phone string) (map[string]string, error) {
+func (app *Application) getRosterByPhone(phone string) (*model.Roster, error) {
 	roster, err := app.storage.FindRosterByPhone(phone)
 	if err != nil {
 		return nil, err
@@ -512,6 +555,42 @@ func (app *Application) getRosterByPhone(phone string) (map[string]string, error
 	return roster, nil
 }
 
+func (app *Application) getPendingSubAccounts(current model.User) ([]model.RawSubAccount, error) {
+	return app.storage.FindRawSubAccountsByPrimaryAccount(current.ExternalID, model.SubAccountLinkPending)
+}
+
+func (app *Application) acceptSubAccount(current model.User, uin string) (*model.Account, error) {
+	account, err := app.storage.AcceptRawSubAccount(current.ID, uin)
+	if err != nil {
+		return nil, err
+	}
+
+	//audit
+	lData := []AuditDataEntry{{Key: "uin", Value: uin}, {Key: "accountID", Value: account.ID}, {Key: "linkStatus", Value: model.SubAccountLinkAccepted}}
+	err = app.audit.LogUpdateEvent(current.ID, current.ExternalID, "", "raw-sub-account", uin, lData, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return account, nil
+}
+
+func (app *Application) declineSubAccount(current model.User, uin string) error {
+	err := app.storage.DeclineRawSubAccount(current.ExternalID, uin)
+	if err != nil {
+		return err
+	}
+
+	//audit
+	lData := []AuditDataEntry{{Key: "uin", Value: uin}, {Key: "linkStatus", Value: model.SubAccountLinkDeclined}}
+	err = app.audit.LogUpdateEvent(current.ID, current.ExternalID, "", "raw-sub-account", uin, lData, nil)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func (app *Application) getExtJoinExternalApproval(account model.Account) ([]RokmetroJoinGroupExtApprovement, error) {
 	//ask rokmetro for the data
 	data, err := app.rokmetro.GetExtJoinExternalApproval(account.ExternalID)
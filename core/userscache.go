@@ -0,0 +1,218 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+
+package core
+
+import (
+	"encoding/json"
+	"health/core/model"
+	"health/utils"
+	"log"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	//the users are cached under "user:" + id, the "external:" and "shibboleth:" keys give the user id,
+	//so the user is invalidated only by its id
+	usersCacheKeyPrefix = "health:users:"
+)
+
+//getUsersCacheMetrics gives the users cache usage
+func (app *Application) getUsersCacheMetrics() model.UsersCacheMetrics {
+	return app.usersCache.metrics()
+}
+
+type usersCacheEntry struct {
+	value   interface{} //the user id for the lookup keys, model.User for the user keys
+	expires time.Time
+}
+
+//usersCache keeps the recently used users in the process memory and in the shared cache if there is one
+type usersCache struct {
+	local    *utils.LRUCache
+	capacity int
+	ttl      time.Duration
+	shared   UsersCache //nil if the replicas do not share a cache
+
+	version int64 //increased on every invalidation, the users found while it changes are not cached
+
+	hits          int64
+	sharedHits    int64
+	misses        int64
+	invalidations int64
+	purges        int64
+	sharedErrors  int64
+}
+
+//find gives the cached user for the lookup key or loads it
+func (c *usersCache) find(lookupKey string, load func() (*model.User, error)) (*model.User, error) {
+	lookupKey = usersCacheKeyPrefix + lookupKey
+
+	user := c.get(lookupKey)
+	if user != nil {
+		return user, nil
+	}
+
+	version := atomic.LoadInt64(&c.version)
+	user, err := load()
+	if err != nil || user == nil {
+		return user, err
+	}
+	if atomic.LoadInt64(&c.version) == version {
+		c.put(lookupKey, *user)
+	}
+	return user, nil
+}
+
+func (c *usersCache) get(lookupKey string) *model.User {
+	if userID, ok := c.getLocal(lookupKey).(string); ok {
+		if user, ok := c.getLocal(c.userKey(userID)).(model.User); ok {
+			atomic.AddInt64(&c.hits, 1)
+			return &user
+		}
+	}
+
+	if c.shared != nil {
+		version := atomic.LoadInt64(&c.version)
+		user := c.getShared(lookupKey)
+		if user != nil {
+			atomic.AddInt64(&c.sharedHits, 1)
+			if atomic.LoadInt64(&c.version) == version {
+				c.putLocal(lookupKey, *user)
+			}
+			return user
+		}
+	}
+
+	atomic.AddInt64(&c.misses, 1)
+	return nil
+}
+
+func (c *usersCache) getLocal(key string) interface{} {
+	value, ok := c.local.Get(key)
+	if !ok {
+		return nil
+	}
+	entry := value.(usersCacheEntry)
+	if time.Now().After(entry.expires) {
+		c.local.Remove(key)
+		return nil
+	}
+	return entry.value
+}
+
+func (c *usersCache) getShared(lookupKey string) *model.User {
+	userID, err := c.shared.Get(lookupKey)
+	if err != nil {
+		c.sharedError("get", err)
+		return nil
+	}
+	if userID == nil {
+		return nil
+	}
+	data, err := c.shared.Get(c.userKey(string(userID)))
+	if err != nil {
+		c.sharedError("get", err)
+		return nil
+	}
+	if data == nil {
+		return nil
+	}
+	var user model.User
+	err = json.Unmarshal(data, &user)
+	if err != nil {
+		c.sharedError("unmarshal", err)
+		return nil
+	}
+	return &user
+}
+
+func (c *usersCache) put(lookupKey string, user model.User) {
+	c.putLocal(lookupKey, user)
+
+	if c.shared != nil {
+		data, err := json.Marshal(user)
+		if err != nil {
+			c.sharedError("marshal", err)
+			return
+		}
+		err = c.shared.Set(c.userKey(user.ID), data, c.ttl)
+		if err == nil {
+			err = c.shared.Set(lookupKey, []byte(user.ID), c.ttl)
+		}
+		if err != nil {
+			c.sharedError("set", err)
+		}
+	}
+}
+
+func (c *usersCache) putLocal(lookupKey string, user model.User) {
+	expires := time.Now().Add(c.ttl)
+	c.local.Put(c.userKey(user.ID), usersCacheEntry{value: user, expires: expires})
+	c.local.Put(lookupKey, usersCacheEntry{value: user.ID, expires: expires})
+}
+
+//invalidate removes the user, its lookup keys stay but do not find it anymore
+func (c *usersCache) invalidate(userID string) {
+	atomic.AddInt64(&c.version, 1)
+	atomic.AddInt64(&c.invalidations, 1)
+
+	key := c.userKey(userID)
+	c.local.Remove(key)
+	if c.shared != nil {
+		err := c.shared.Delete([]string{key})
+		if err != nil {
+			c.sharedError("delete", err)
+		}
+	}
+}
+
+//purge removes all cached users
+func (c *usersCache) purge() {
+	log.Println("usersCache -> purge")
+
+	atomic.AddInt64(&c.version, 1)
+	atomic.AddInt64(&c.purges, 1)
+
+	c.local.Purge()
+	if c.shared != nil {
+		err := c.shared.DeletePrefix(usersCacheKeyPrefix)
+		if err != nil {
+			c.sharedError("purge", err)
+		}
+	}
+}
+
+func (c *usersCache) userKey(userID string) string {
+	return usersCacheKeyPrefix + "user:" + userID
+}
+
+func (c *usersCache) sharedError(operation string, err error) {
+	atomic.AddInt64(&c.sharedErrors, 1)
+	log.Printf("usersCache -> shared cache %s error - %s", operation, err)
+}
+
+func (c *usersCache) metrics() model.UsersCacheMetrics {
+	return model.UsersCacheMetrics{Size: c.local.Len(), Capacity: c.capacity, TTL: int(c.ttl.Seconds()), Shared: c.shared != nil,
+		Hits: atomic.LoadInt64(&c.hits), SharedHits: atomic.LoadInt64(&c.sharedHits), Misses: atomic.LoadInt64(&c.misses),
+		Invalidations: atomic.LoadInt64(&c.invalidations), Purges: atomic.LoadInt64(&c.purges), SharedErrors: atomic.LoadInt64(&c.sharedErrors)}
+}
+
+func newUsersCache(shared UsersCache, capacity int, ttl time.Duration) *usersCache {
+	return &usersCache{local: utils.NewLRUCache(capacity), capacity: capacity, ttl: ttl, shared: shared}
+}
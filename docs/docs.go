@@ -62,6 +62,12 @@ var doc = `{
                             "type": "string"
                         }
                     },
+                    "403": {
+                        "description": "The county is out of the admin counties",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -113,6 +119,12 @@ var doc = `{
                         "schema": {
                             "$ref": "#/definitions/AccessRule"
                         }
+                    },
+                    "403": {
+                        "description": "The county is out of the admin counties",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 }
             }
@@ -162,6 +174,12 @@ var doc = `{
                         "schema": {
                             "$ref": "#/definitions/AccessRule"
                         }
+                    },
+                    "403": {
+                        "description": "The county is out of the admin counties",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 }
             },
@@ -197,6 +215,12 @@ var doc = `{
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "403": {
+                        "description": "The county is out of the admin counties",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 }
             }
@@ -243,6 +267,170 @@ var doc = `{
                 }
             }
         },
+        "/admin/api-keys": {
+            "get": {
+                "security": [
+                    {
+                        "AdminUserAuth": []
+                    },
+                    {
+                        "AdminGroupAuth": []
+                    }
+                ],
+                "description": "Gives the API keys managed by the admins. The keys values are not given.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "operationId": "GetAPIKeys",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/APIKey"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "AdminUserAuth": []
+                    },
+                    {
+                        "AdminGroupAuth": []
+                    }
+                ],
+                "description": "Creates an API key. The key value is given only in this response. The scopes are \"app\", \"external\" and \"providers\".",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "operationId": "CreateAPIKey",
+                "parameters": [
+                    {
+                        "description": "body data",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/createAPIKeyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/apiKeyResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/api-keys/{id}/revoke": {
+            "put": {
+                "security": [
+                    {
+                        "AdminUserAuth": []
+                    },
+                    {
+                        "AdminGroupAuth": []
+                    }
+                ],
+                "description": "Revokes an API key. It cannot be used anymore.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "operationId": "RevokeAPIKey",
+                "parameters": [
+                    {
+                        "description": "body data",
+                        "name": "data",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/revokeAPIKeyRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully revoked",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/api-keys/{id}/rotate": {
+            "put": {
+                "security": [
+                    {
+                        "AdminUserAuth": []
+                    },
+                    {
+                        "AdminGroupAuth": []
+                    }
+                ],
+                "description": "Creates a new API key with the name, the owner and the scopes of the rotated one. The rotated key stays valid for the grace period - 24 hours if not provided.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "operationId": "RotateAPIKey",
+                "parameters": [
+                    {
+                        "description": "body data",
+                        "name": "data",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/rotateAPIKeyRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/apiKeyResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/admin/app-versions": {
             "get": {
                 "security": [
@@ -324,7 +512,7 @@ var doc = `{
                         "AdminGroupAuth": []
                     }
                 ],
-                "description": "Gives the audilt/log history",
+                "description": "Gives the audilt/log history. The items have the entity data before and after the change and the changed fields.\nThe items can be filtered by the listed fields as field=value or field=operator:value where the operator is eq, ne, in, range, prefix or exists.\nThe items are given in pages if \"limit\" or \"cursor\" is provided. The next page cursor is given in the ROKWIRE-CONTINUATION-TOKEN header.\nThe old user-identifier, entity-id, client-data, created-at and asc params are still supported.",
                 "consumes": [
                     "application/json"
                 ],
@@ -336,7 +524,13 @@ var doc = `{
                     {
                         "type": "string",
                         "description": "User identifier",
-                        "name": "user-identifier",
+                        "name": "user_identifier",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Used group",
+                        "name": "used_group",
                         "in": "query"
                     },
                     {
@@ -348,7 +542,7 @@ var doc = `{
                     {
                         "type": "string",
                         "description": "Entity ID",
-                        "name": "entity-id",
+                        "name": "entity_id",
                         "in": "query"
                     },
                     {
@@ -360,25 +554,25 @@ var doc = `{
                     {
                         "type": "string",
                         "description": "Client data",
-                        "name": "client-data",
+                        "name": "client_data",
                         "in": "query"
                     },
                     {
                         "type": "string",
                         "description": "Created At",
-                        "name": "created-at",
+                        "name": "created_at",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Sort By",
-                        "name": "sort",
+                        "description": "Changed field - the items which changed the field",
+                        "name": "changed_field",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Ascending",
-                        "name": "asc",
+                        "description": "Sort fields - field1,-field2",
+                        "name": "sort",
                         "in": "query"
                     },
                     {
@@ -386,6 +580,12 @@ var doc = `{
                         "description": "Limit",
                         "name": "limit",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor",
+                        "name": "cursor",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -401,7 +601,7 @@ var doc = `{
                 }
             }
         },
-        "/admin/counties": {
+        "/admin/audit/metrics": {
             "get": {
                 "security": [
                     {
@@ -411,45 +611,26 @@ var doc = `{
                         "AdminGroupAuth": []
                     }
                 ],
-                "description": "Gives the counties list",
+                "description": "Gives the audit queue depth, the written, dropped and spilled items and the last write error since the service start.",
                 "consumes": [
                     "application/json"
                 ],
                 "tags": [
                     "Admin"
                 ],
-                "operationId": "getCounties",
+                "operationId": "GetAuditMetrics",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/ACounty"
-                            }
-                        }
-                    },
-                    "400": {
-                        "description": "Authentication error",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server error",
-                        "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/AuditMetrics"
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/admin/audit/verify": {
+            "get": {
                 "security": [
                     {
                         "AdminUserAuth": []
@@ -458,40 +639,40 @@ var doc = `{
                         "AdminGroupAuth": []
                     }
                 ],
-                "description": "Creates a county",
+                "description": "Verifies that the audit items are not modified or removed - every item matches its hash and is chained to the previous one and the signed checkpoints match their items. The checkpoints cannot be verified and the result is not valid if there is no signing key.",
                 "consumes": [
                     "application/json"
                 ],
-                "produces": [
-                    "application/json"
-                ],
                 "tags": [
                     "Admin"
                 ],
-                "operationId": "CreateCounty",
+                "operationId": "VerifyAudit",
                 "parameters": [
                     {
-                        "description": "body data",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/createCountyRequest"
-                        }
+                        "type": "integer",
+                        "description": "From sequence - from the first item if not provided",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "To sequence - to the last item if not provided",
+                        "name": "to",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/ACounty"
+                            "$ref": "#/definitions/AuditVerification"
                         }
                     }
                 }
             }
         },
-        "/admin/counties/{id}": {
-            "put": {
+        "/admin/broadcasts": {
+            "get": {
                 "security": [
                     {
                         "AdminUserAuth": []
@@ -500,45 +681,35 @@ var doc = `{
                         "AdminGroupAuth": []
                     }
                 ],
-                "description": "Updates a county",
+                "description": "Gives the broadcasts sorted by the scheduled time, the latest first",
                 "consumes": [
                     "application/json"
                 ],
-                "produces": [
-                    "application/json"
-                ],
                 "tags": [
                     "Admin"
                 ],
-                "operationId": "UpdateCounty",
+                "operationId": "GetBroadcasts",
                 "parameters": [
-                    {
-                        "description": "body data",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/updateCountyRequest"
-                        }
-                    },
                     {
                         "type": "string",
-                        "description": "ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "scheduled, sending, sent, cancelled or failed",
+                        "name": "status",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/ACounty"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/Broadcast"
+                            }
                         }
                     }
                 }
             },
-            "delete": {
+            "post": {
                 "security": [
                     {
                         "AdminUserAuth": []
@@ -547,110 +718,17 @@ var doc = `{
                         "AdminGroupAuth": []
                     }
                 ],
-                "description": "Deletes a county",
+                "description": "Creates a broadcast. It is sent at the scheduled time or immediately if there is no scheduled time. An empty target means all users.",
                 "consumes": [
-                    "text/plain"
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
                 ],
                 "tags": [
                     "Admin"
                 ],
-                "operationId": "deleteCounty",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "Successfuly deleted",
-                        "schema": {
-                            "type": "string"
-                        }
-                    }
-                }
-            }
-        },
-        "/admin/county-statuses": {
-            "get": {
-                "security": [
-                    {
-                        "AdminUserAuth": []
-                    },
-                    {
-                        "AdminGroupAuth": []
-                    }
-                ],
-                "description": "Gets all county statuses for a county.",
-                "consumes": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Admin"
-                ],
-                "operationId": "getCountyStatusesByCountyID",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "County ID",
-                        "name": "county-id",
-                        "in": "query",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/CountyStatus"
-                            }
-                        }
-                    },
-                    "400": {
-                        "description": "Authentication error",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server error",
-                        "schema": {
-                            "type": "string"
-                        }
-                    }
-                }
-            },
-            "post": {
-                "security": [
-                    {
-                        "AdminUserAuth": []
-                    },
-                    {
-                        "AdminGroupAuth": []
-                    }
-                ],
-                "description": "Creates a county status.",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Admin"
-                ],
-                "operationId": "CreateCountyStatus",
+                "operationId": "CreateBroadcast",
                 "parameters": [
                     {
                         "description": "body data",
@@ -658,7 +736,7 @@ var doc = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/createCountyStatusRequest"
+                            "$ref": "#/definitions/createBroadcastRequest"
                         }
                     }
                 ],
@@ -666,14 +744,14 @@ var doc = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/CountyStatus"
+                            "$ref": "#/definitions/Broadcast"
                         }
                     }
                 }
             }
         },
-        "/admin/county-statuses/{id}": {
-            "put": {
+        "/admin/broadcasts/preview": {
+            "post": {
                 "security": [
                     {
                         "AdminUserAuth": []
@@ -682,7 +760,7 @@ var doc = `{
                         "AdminGroupAuth": []
                     }
                 ],
-                "description": "Updates a county status.",
+                "description": "Gives how many users a broadcast with the provided target will reach.",
                 "consumes": [
                     "application/json"
                 ],
@@ -692,7 +770,7 @@ var doc = `{
                 "tags": [
                     "Admin"
                 ],
-                "operationId": "UpdateCountyStatus",
+                "operationId": "PreviewBroadcast",
                 "parameters": [
                     {
                         "description": "body data",
@@ -700,27 +778,22 @@ var doc = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/updateCountyStatusRequest"
+                            "$ref": "#/definitions/previewBroadcastRequest"
                         }
-                    },
-                    {
-                        "type": "string",
-                        "description": "ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/CountyStatus"
+                            "$ref": "#/definitions/previewBroadcastResponse"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/admin/broadcasts/{id}/cancel": {
+            "put": {
                 "security": [
                     {
                         "AdminUserAuth": []
@@ -729,14 +802,14 @@ var doc = `{
                         "AdminGroupAuth": []
                     }
                 ],
-                "description": "Deletes a county status",
+                "description": "Cancels a broadcast which has not been sent yet",
                 "consumes": [
                     "text/plain"
                 ],
                 "tags": [
                     "Admin"
                 ],
-                "operationId": "deleteCountyStatus",
+                "operationId": "CancelBroadcast",
                 "parameters": [
                     {
                         "type": "string",
@@ -748,7 +821,7 @@ var doc = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfuly deleted",
+                        "description": "Successfully cancelled",
                         "schema": {
                             "type": "string"
                         }
@@ -756,7 +829,7 @@ var doc = `{
                 }
             }
         },
-        "/admin/covid19-configs": {
+        "/admin/compliance": {
             "get": {
                 "security": [
                     {
@@ -766,25 +839,25 @@ var doc = `{
                         "AdminGroupAuth": []
                     }
                 ],
-                "description": "Gives all the covid19 configurations",
+                "description": "Gives the roster members testing compliance counts - total, per uin override category and per county.",
+                "consumes": [
+                    "application/json"
+                ],
                 "tags": [
                     "Admin"
                 ],
-                "operationId": "GetCovid19Configs",
+                "operationId": "GetComplianceSummary",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/model.COVID19Config"
-                            }
+                            "$ref": "#/definitions/ComplianceSummary"
                         }
                     }
                 }
             }
         },
-        "/admin/crules": {
+        "/admin/compliance/items": {
             "get": {
                 "security": [
                     {
@@ -794,15 +867,31 @@ var doc = `{
                         "AdminGroupAuth": []
                     }
                 ],
-                "description": "Gives the rules",
+                "description": "Gives the roster members testing compliance matching the filters and paginated. The csv format gives all the matching items if there is no limit.",
                 "consumes": [
                     "application/json"
                 ],
+                "produces": [
+                    "application/json",
+                    "text/csv"
+                ],
                 "tags": [
                     "Admin"
                 ],
-                "operationId": "GetCRules",
+                "operationId": "GetComplianceItems",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "compliant, due-soon, overdue or exempt",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "UIN override category",
+                        "name": "category",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
                         "description": "County ID",
@@ -811,8 +900,20 @@ var doc = `{
                     },
                     {
                         "type": "string",
-                        "description": "App version",
-                        "name": "app-version",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Offset",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "json or csv",
+                        "name": "format",
                         "in": "query"
                     }
                 ],
@@ -820,12 +921,14 @@ var doc = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/getComplianceItemsResponse"
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/admin/counties": {
+            "get": {
                 "security": [
                     {
                         "AdminUserAuth": []
@@ -834,63 +937,71 @@ var doc = `{
                         "AdminGroupAuth": []
                     }
                 ],
-                "description": "Creates rules, updates them if already created.",
+                "description": "Gives the counties list\nThe items can be filtered by the listed fields as field=value or field=operator:value where the operator is eq, ne, in, range, prefix or exists.\nThe items are given in pages if \"limit\" or \"cursor\" is provided. The next page cursor is given in the ROKWIRE-CONTINUATION-TOKEN header.",
                 "consumes": [
                     "application/json"
                 ],
-                "produces": [
-                    "application/json"
-                ],
                 "tags": [
                     "Admin"
                 ],
-                "operationId": "CreateOrUpdateCRules",
+                "operationId": "getCounties",
                 "parameters": [
                     {
-                        "description": "body data",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/createOrUpdateCRulesRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "string"
-                        }
-                    }
-                }
-            }
-        },
-        "/admin/faq": {
-            "get": {
-                "security": [
+                        "type": "string",
+                        "description": "ID",
+                        "name": "id",
+                        "in": "query"
+                    },
                     {
-                        "AdminUserAuth": []
+                        "type": "string",
+                        "description": "Name",
+                        "name": "name",
+                        "in": "query"
                     },
                     {
-                        "AdminGroupAuth": []
+                        "type": "string",
+                        "description": "State province",
+                        "name": "state_province",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Country",
+                        "name": "country",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date created",
+                        "name": "date_created",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields - field1,-field2",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor",
+                        "name": "cursor",
+                        "in": "query"
                     }
                 ],
-                "description": "Gives FAQs list",
-                "consumes": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Admin"
-                ],
-                "operationId": "getFAQs",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/FAQ"
+                                "$ref": "#/definitions/ACounty"
                             }
                         }
                     },
@@ -923,7 +1034,7 @@ var doc = `{
                         "AdminGroupAuth": []
                     }
                 ],
-                "description": "Creates a faq item",
+                "description": "Creates a county",
                 "consumes": [
                     "application/json"
                 ],
@@ -933,7 +1044,7 @@ var doc = `{
                 "tags": [
                     "Admin"
                 ],
-                "operationId": "createFAQItem",
+                "operationId": "CreateCounty",
                 "parameters": [
                     {
                         "description": "body data",
@@ -941,7 +1052,7 @@ var doc = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/createFAQRequest"
+                            "$ref": "#/definitions/createCountyRequest"
                         }
                     }
                 ],
@@ -949,13 +1060,13 @@ var doc = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/ACounty"
                         }
                     }
                 }
             }
         },
-        "/admin/faq/section/{id}": {
+        "/admin/counties/{id}": {
             "put": {
                 "security": [
                     {
@@ -965,7 +1076,7 @@ var doc = `{
                         "AdminGroupAuth": []
                     }
                 ],
-                "description": "Updates a faq section.",
+                "description": "Updates a county",
                 "consumes": [
                     "application/json"
                 ],
@@ -975,7 +1086,7 @@ var doc = `{
                 "tags": [
                     "Admin"
                 ],
-                "operationId": "UpdateFAQSection",
+                "operationId": "UpdateCounty",
                 "parameters": [
                     {
                         "description": "body data",
@@ -983,7 +1094,7 @@ var doc = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/updateFAQSection"
+                            "$ref": "#/definitions/updateCountyRequest"
                         }
                     },
                     {
@@ -998,7 +1109,7 @@ var doc = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/ACounty"
                         }
                     }
                 }
@@ -1012,14 +1123,14 @@ var doc = `{
                         "AdminGroupAuth": []
                     }
                 ],
-                "description": "Deletes a faq section",
+                "description": "Deletes a county",
                 "consumes": [
                     "text/plain"
                 ],
                 "tags": [
                     "Admin"
                 ],
-                "operationId": "deleteFAQSection",
+                "operationId": "deleteCounty",
                 "parameters": [
                     {
                         "type": "string",
@@ -1039,91 +1150,7 @@ var doc = `{
                 }
             }
         },
-        "/admin/faq/{id}": {
-            "put": {
-                "security": [
-                    {
-                        "AdminUserAuth": []
-                    },
-                    {
-                        "AdminGroupAuth": []
-                    }
-                ],
-                "description": "Updates a faq item.",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Admin"
-                ],
-                "operationId": "UpdateFAQItem",
-                "parameters": [
-                    {
-                        "description": "body data",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/updateFAQRequest"
-                        }
-                    },
-                    {
-                        "type": "string",
-                        "description": "ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "string"
-                        }
-                    }
-                }
-            },
-            "delete": {
-                "security": [
-                    {
-                        "AdminUserAuth": []
-                    },
-                    {
-                        "AdminGroupAuth": []
-                    }
-                ],
-                "description": "Deletes a faq item",
-                "consumes": [
-                    "text/plain"
-                ],
-                "tags": [
-                    "Admin"
-                ],
-                "operationId": "deleteFAQItem",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "Successfuly deleted FAQ item",
-                        "schema": {
-                            "type": "string"
-                        }
-                    }
-                }
-            }
-        },
-        "/admin/guidelines": {
+        "/admin/county-statuses": {
             "get": {
                 "security": [
                     {
@@ -1133,14 +1160,14 @@ var doc = `{
                         "AdminGroupAuth": []
                     }
                 ],
-                "description": "Gets all guidelines for a county",
+                "description": "Gets all county statuses for a county.",
                 "consumes": [
                     "application/json"
                 ],
                 "tags": [
                     "Admin"
                 ],
-                "operationId": "getGuidelinesByCountyID",
+                "operationId": "getCountyStatusesByCountyID",
                 "parameters": [
                     {
                         "type": "string",
@@ -1156,7 +1183,7 @@ var doc = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/Guideline"
+                                "$ref": "#/definitions/CountyStatus"
                             }
                         }
                     },
@@ -1166,6 +1193,12 @@ var doc = `{
                             "type": "string"
                         }
                     },
+                    "403": {
+                        "description": "The county is out of the admin counties",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -1189,7 +1222,7 @@ var doc = `{
                         "AdminGroupAuth": []
                     }
                 ],
-                "description": "Creates a guideline",
+                "description": "Creates a county status.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1199,7 +1232,7 @@ var doc = `{
                 "tags": [
                     "Admin"
                 ],
-                "operationId": "CreateGuideline",
+                "operationId": "CreateCountyStatus",
                 "parameters": [
                     {
                         "description": "body data",
@@ -1207,7 +1240,7 @@ var doc = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/createGuidelineRequest"
+                            "$ref": "#/definitions/createCountyStatusRequest"
                         }
                     }
                 ],
@@ -1215,13 +1248,19 @@ var doc = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/Guideline"
+                            "$ref": "#/definitions/CountyStatus"
+                        }
+                    },
+                    "403": {
+                        "description": "The county is out of the admin counties",
+                        "schema": {
+                            "type": "string"
                         }
                     }
                 }
             }
         },
-        "/admin/guidelines/{id}": {
+        "/admin/county-statuses/{id}": {
             "put": {
                 "security": [
                     {
@@ -1231,7 +1270,7 @@ var doc = `{
                         "AdminGroupAuth": []
                     }
                 ],
-                "description": "Updates a guideline.",
+                "description": "Updates a county status.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1241,7 +1280,7 @@ var doc = `{
                 "tags": [
                     "Admin"
                 ],
-                "operationId": "UpdateGuideline",
+                "operationId": "UpdateCountyStatus",
                 "parameters": [
                     {
                         "description": "body data",
@@ -1249,7 +1288,7 @@ var doc = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/updateGuidelineRequest"
+                            "$ref": "#/definitions/updateCountyStatusRequest"
                         }
                     },
                     {
@@ -1264,7 +1303,13 @@ var doc = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/Guideline"
+                            "$ref": "#/definitions/CountyStatus"
+                        }
+                    },
+                    "403": {
+                        "description": "The county is out of the admin counties",
+                        "schema": {
+                            "type": "string"
                         }
                     }
                 }
@@ -1278,14 +1323,14 @@ var doc = `{
                         "AdminGroupAuth": []
                     }
                 ],
-                "description": "Deletes a guideline.",
+                "description": "Deletes a county status",
                 "consumes": [
                     "text/plain"
                 ],
                 "tags": [
                     "Admin"
                 ],
-                "operationId": "deleteGuideline",
+                "operationId": "deleteCountyStatus",
                 "parameters": [
                     {
                         "type": "string",
@@ -1301,11 +1346,17 @@ var doc = `{
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "403": {
+                        "description": "The county is out of the admin counties",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 }
             }
         },
-        "/admin/locations": {
+        "/admin/covid19-configs": {
             "get": {
                 "security": [
                     {
@@ -1315,45 +1366,26 @@ var doc = `{
                         "AdminGroupAuth": []
                     }
                 ],
-                "description": "Gives the locations list",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Gives all the covid19 configurations",
                 "tags": [
                     "Admin"
                 ],
-                "operationId": "getLocations",
+                "operationId": "GetCovid19Configs",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/Location"
+                                "$ref": "#/definitions/model.COVID19Config"
                             }
                         }
-                    },
-                    "400": {
-                        "description": "Authentication error",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server error",
-                        "schema": {
-                            "type": "string"
-                        }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/admin/crules": {
+            "get": {
                 "security": [
                     {
                         "AdminUserAuth": []
@@ -1362,39 +1394,43 @@ var doc = `{
                         "AdminGroupAuth": []
                     }
                 ],
-                "description": "Creates a location.",
+                "description": "Gives the rules",
                 "consumes": [
                     "application/json"
                 ],
-                "produces": [
-                    "application/json"
-                ],
                 "tags": [
                     "Admin"
                 ],
-                "operationId": "CreateLocation",
+                "operationId": "GetCRules",
                 "parameters": [
                     {
-                        "description": "body data",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/createLocationRequest"
-                        }
+                        "type": "string",
+                        "description": "County ID",
+                        "name": "county-id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "App version",
+                        "name": "app-version",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/Location"
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "The county is out of the admin counties",
+                        "schema": {
+                            "type": "string"
                         }
                     }
                 }
-            }
-        },
-        "/admin/locations/{id}": {
+            },
             "put": {
                 "security": [
                     {
@@ -1404,7 +1440,7 @@ var doc = `{
                         "AdminGroupAuth": []
                     }
                 ],
-                "description": "Updates a location.",
+                "description": "Creates rules, updates them if already created.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1414,7 +1450,7 @@ var doc = `{
                 "tags": [
                     "Admin"
                 ],
-                "operationId": "UpdateLocation",
+                "operationId": "CreateOrUpdateCRules",
                 "parameters": [
                     {
                         "description": "body data",
@@ -1422,27 +1458,28 @@ var doc = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/updateLocationRequest"
+                            "$ref": "#/definitions/createOrUpdateCRulesRequest"
                         }
-                    },
-                    {
-                        "type": "string",
-                        "description": "ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/Location"
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "The county is out of the admin counties",
+                        "schema": {
+                            "type": "string"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/admin/exposure-metrics": {
+            "get": {
                 "security": [
                     {
                         "AdminUserAuth": []
@@ -1451,34 +1488,33 @@ var doc = `{
                         "AdminGroupAuth": []
                     }
                 ],
-                "description": "Deletes a location",
+                "description": "Gives the trace exposures counts, the retention period and the purges for the last days.",
                 "consumes": [
-                    "text/plain"
+                    "application/json"
                 ],
                 "tags": [
                     "Admin"
                 ],
-                "operationId": "deleteLocation",
+                "operationId": "GetExposureMetrics",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "type": "integer",
+                        "description": "Days - the purges period, 30 if not provided",
+                        "name": "days",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfuly deleted",
+                        "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/ExposureMetrics"
                         }
                     }
                 }
             }
         },
-        "/admin/manual-tests": {
+        "/admin/exposure-verification-codes": {
             "get": {
                 "security": [
                     {
@@ -1488,46 +1524,88 @@ var doc = `{
                         "AdminGroupAuth": []
                     }
                 ],
-                "description": "Gives the manual tests for a county",
+                "description": "Gives the exposure verification codes, the newest first. The codes themselves are not stored so they are not given.",
                 "consumes": [
                     "application/json"
                 ],
                 "tags": [
                     "Admin"
                 ],
-                "operationId": "getManualTestsByCountyID",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/ManualTest"
-                            }
-                        }
-                    },
-                    "400": {
-                        "description": "Authentication error",
+                "operationId": "GetExposureVerificationCodes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Issued by - user identifier or provider:{provider id}",
+                        "name": "issued-by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Status - issued, claimed or used",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Limit - 100 if not provided",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/ExposureVerificationCode"
+                            }
                         }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "AdminUserAuth": []
                     },
-                    "404": {
-                        "description": "Not Found",
+                    {
+                        "AdminGroupAuth": []
+                    }
+                ],
+                "description": "Issues a one-time exposure verification code for a positive test. The user enters the code in the app in order to upload the exposure keys. The code is given only in this response.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "operationId": "IssueExposureVerificationCode",
+                "parameters": [
+                    {
+                        "description": "body data",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/issueExposureVerificationCodeRequest"
                         }
-                    },
-                    "500": {
-                        "description": "Internal Server error",
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/issueExposureVerificationCodeResponse"
                         }
                     }
                 }
             }
         },
-        "/admin/manual-tests/{id}/image": {
+        "/admin/faq": {
             "get": {
                 "security": [
                     {
@@ -1537,28 +1615,22 @@ var doc = `{
                         "AdminGroupAuth": []
                     }
                 ],
-                "description": "Gives the image for a manual test",
+                "description": "Gives FAQs list",
                 "consumes": [
                     "application/json"
                 ],
                 "tags": [
                     "Admin"
                 ],
-                "operationId": "getManualTestImage",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Manual Test ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
+                "operationId": "getFAQs",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/ManualTestImage"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/FAQ"
+                            }
                         }
                     },
                     "400": {
@@ -1580,10 +1652,8 @@ var doc = `{
                         }
                     }
                 }
-            }
-        },
-        "/admin/manual-tests/{id}/process": {
-            "put": {
+            },
+            "post": {
                 "security": [
                     {
                         "AdminUserAuth": []
@@ -1592,7 +1662,7 @@ var doc = `{
                         "AdminGroupAuth": []
                     }
                 ],
-                "description": "Processes manual test.",
+                "description": "Creates a faq item",
                 "consumes": [
                     "application/json"
                 ],
@@ -1602,7 +1672,7 @@ var doc = `{
                 "tags": [
                     "Admin"
                 ],
-                "operationId": "ProcessManualTest",
+                "operationId": "createFAQItem",
                 "parameters": [
                     {
                         "description": "body data",
@@ -1610,15 +1680,8 @@ var doc = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/processManualTestRequest"
+                            "$ref": "#/definitions/createFAQRequest"
                         }
-                    },
-                    {
-                        "type": "string",
-                        "description": "ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
@@ -1631,8 +1694,8 @@ var doc = `{
                 }
             }
         },
-        "/admin/news": {
-            "get": {
+        "/admin/faq/section/{id}": {
+            "put": {
                 "security": [
                     {
                         "AdminUserAuth": []
@@ -1641,45 +1704,45 @@ var doc = `{
                         "AdminGroupAuth": []
                     }
                 ],
-                "description": "Gives news.",
+                "description": "Updates a faq section.",
                 "consumes": [
                     "application/json"
                 ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
                     "Admin"
                 ],
-                "operationId": "getNews",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/News"
-                            }
-                        }
-                    },
-                    "400": {
-                        "description": "Authentication error",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
+                "operationId": "UpdateFAQSection",
+                "parameters": [
+                    {
+                        "description": "body data",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/updateFAQSection"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server error",
+                    {
+                        "type": "string",
+                        "description": "ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
                             "type": "string"
                         }
                     }
                 }
             },
-            "post": {
+            "delete": {
                 "security": [
                     {
                         "AdminUserAuth": []
@@ -1688,39 +1751,34 @@ var doc = `{
                         "AdminGroupAuth": []
                     }
                 ],
-                "description": "Creates news",
+                "description": "Deletes a faq section",
                 "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
+                    "text/plain"
                 ],
                 "tags": [
                     "Admin"
                 ],
-                "operationId": "CreateNews",
+                "operationId": "deleteFAQSection",
                 "parameters": [
                     {
-                        "description": "body data",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/createNewsRequest"
-                        }
+                        "type": "string",
+                        "description": "ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Successfuly deleted",
                         "schema": {
-                            "$ref": "#/definitions/News"
+                            "type": "string"
                         }
                     }
                 }
             }
         },
-        "/admin/news/{id}": {
+        "/admin/faq/{id}": {
             "put": {
                 "security": [
                     {
@@ -1730,7 +1788,7 @@ var doc = `{
                         "AdminGroupAuth": []
                     }
                 ],
-                "description": "Updates news.",
+                "description": "Updates a faq item.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1740,7 +1798,7 @@ var doc = `{
                 "tags": [
                     "Admin"
                 ],
-                "operationId": "UpdateNews",
+                "operationId": "UpdateFAQItem",
                 "parameters": [
                     {
                         "description": "body data",
@@ -1748,7 +1806,7 @@ var doc = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/updateNewsRequest"
+                            "$ref": "#/definitions/updateFAQRequest"
                         }
                     },
                     {
@@ -1763,7 +1821,7 @@ var doc = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/News"
+                            "type": "string"
                         }
                     }
                 }
@@ -1777,14 +1835,14 @@ var doc = `{
                         "AdminGroupAuth": []
                     }
                 ],
-                "description": "Deletes news",
+                "description": "Deletes a faq item",
                 "consumes": [
                     "text/plain"
                 ],
                 "tags": [
                     "Admin"
                 ],
-                "operationId": "deleteNews",
+                "operationId": "deleteFAQItem",
                 "parameters": [
                     {
                         "type": "string",
@@ -1796,7 +1854,7 @@ var doc = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfuly deleted new items",
+                        "description": "Successfuly deleted FAQ item",
                         "schema": {
                             "type": "string"
                         }
@@ -1804,7 +1862,7 @@ var doc = `{
                 }
             }
         },
-        "/admin/providers": {
+        "/admin/guidelines": {
             "get": {
                 "security": [
                     {
@@ -1814,21 +1872,30 @@ var doc = `{
                         "AdminGroupAuth": []
                     }
                 ],
-                "description": "Gives the providers list",
+                "description": "Gets all guidelines for a county",
                 "consumes": [
                     "application/json"
                 ],
                 "tags": [
                     "Admin"
                 ],
-                "operationId": "getProviders",
+                "operationId": "getGuidelinesByCountyID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "County ID",
+                        "name": "county-id",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/Provider"
+                                "$ref": "#/definitions/Guideline"
                             }
                         }
                     },
@@ -1838,6 +1905,12 @@ var doc = `{
                             "type": "string"
                         }
                     },
+                    "403": {
+                        "description": "The county is out of the admin counties",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -1861,7 +1934,7 @@ var doc = `{
                         "AdminGroupAuth": []
                     }
                 ],
-                "description": "Creates a provider",
+                "description": "Creates a guideline",
                 "consumes": [
                     "application/json"
                 ],
@@ -1871,7 +1944,7 @@ var doc = `{
                 "tags": [
                     "Admin"
                 ],
-                "operationId": "createProvider",
+                "operationId": "CreateGuideline",
                 "parameters": [
                     {
                         "description": "body data",
@@ -1879,7 +1952,7 @@ var doc = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/createProviderRequest"
+                            "$ref": "#/definitions/createGuidelineRequest"
                         }
                     }
                 ],
@@ -1887,13 +1960,19 @@ var doc = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/Provider"
+                            "$ref": "#/definitions/Guideline"
+                        }
+                    },
+                    "403": {
+                        "description": "The county is out of the admin counties",
+                        "schema": {
+                            "type": "string"
                         }
                     }
                 }
             }
         },
-        "/admin/providers/{id}": {
+        "/admin/guidelines/{id}": {
             "put": {
                 "security": [
                     {
@@ -1903,7 +1982,7 @@ var doc = `{
                         "AdminGroupAuth": []
                     }
                 ],
-                "description": "Updates a provider.",
+                "description": "Updates a guideline.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1913,7 +1992,7 @@ var doc = `{
                 "tags": [
                     "Admin"
                 ],
-                "operationId": "UpdateProvider",
+                "operationId": "UpdateGuideline",
                 "parameters": [
                     {
                         "description": "body data",
@@ -1921,7 +2000,7 @@ var doc = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/updateProviderRequest"
+                            "$ref": "#/definitions/updateGuidelineRequest"
                         }
                     },
                     {
@@ -1936,7 +2015,13 @@ var doc = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/Provider"
+                            "$ref": "#/definitions/Guideline"
+                        }
+                    },
+                    "403": {
+                        "description": "The county is out of the admin counties",
+                        "schema": {
+                            "type": "string"
                         }
                     }
                 }
@@ -1950,14 +2035,14 @@ var doc = `{
                         "AdminGroupAuth": []
                     }
                 ],
-                "description": "Deletes a provider",
+                "description": "Deletes a guideline.",
                 "consumes": [
                     "text/plain"
                 ],
                 "tags": [
                     "Admin"
                 ],
-                "operationId": "deleteProvider",
+                "operationId": "deleteGuideline",
                 "parameters": [
                     {
                         "type": "string",
@@ -1973,12 +2058,18 @@ var doc = `{
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "403": {
+                        "description": "The county is out of the admin counties",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 }
             }
         },
-        "/admin/raw-sub-account-items": {
-            "post": {
+        "/admin/locations": {
+            "get": {
                 "security": [
                     {
                         "AdminUserAuth": []
@@ -1987,79 +2078,73 @@ var doc = `{
                         "AdminGroupAuth": []
                     }
                 ],
-                "description": "Creates sub account items",
+                "description": "Gives the locations list\nThe items can be filtered by the listed fields as field=value or field=operator:value where the operator is eq, ne, in, range, prefix or exists.\nThe items are given in pages if \"limit\" or \"cursor\" is provided. The next page cursor is given in the ROKWIRE-CONTINUATION-TOKEN header.",
                 "consumes": [
                     "application/json"
                 ],
-                "produces": [
-                    "application/json"
-                ],
                 "tags": [
                     "Admin"
                 ],
-                "operationId": "CreateSubAccountItems",
+                "operationId": "getLocations",
                 "parameters": [
                     {
-                        "description": "body data",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/createRawSubAccountItemsRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "string"
-                        }
-                    }
-                }
-            }
-        },
-        "/admin/raw-sub-accounts": {
-            "get": {
-                "security": [
+                        "type": "string",
+                        "description": "ID",
+                        "name": "id",
+                        "in": "query"
+                    },
                     {
-                        "AdminUserAuth": []
+                        "type": "string",
+                        "description": "Name",
+                        "name": "name",
+                        "in": "query"
                     },
                     {
-                        "AdminGroupAuth": []
-                    }
-                ],
-                "description": "Gives the sub accounts matching filters, sorted, and paginated",
-                "consumes": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Admin"
-                ],
-                "operationId": "GetSubAccounts",
-                "parameters": [
+                        "type": "string",
+                        "description": "City",
+                        "name": "city",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
-                        "description": "Phone",
-                        "name": "phone",
+                        "description": "State",
+                        "name": "state",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "UIN",
-                        "name": "uin",
+                        "description": "ZIP",
+                        "name": "zip",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Sort By",
-                        "name": "sortBy",
+                        "description": "Country",
+                        "name": "country",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Order By",
-                        "name": "orderBy",
+                        "description": "Provider ID",
+                        "name": "provider_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "County ID",
+                        "name": "county_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date created",
+                        "name": "date_created",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields - field1,-field2",
+                        "name": "sort",
                         "in": "query"
                     },
                     {
@@ -2070,8 +2155,8 @@ var doc = `{
                     },
                     {
                         "type": "string",
-                        "description": "Offset",
-                        "name": "offset",
+                        "description": "Cursor",
+                        "name": "cursor",
                         "in": "query"
                     }
                 ],
@@ -2081,13 +2166,37 @@ var doc = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/RawSubAccount"
+                                "$ref": "#/definitions/Location"
                             }
                         }
+                    },
+                    "400": {
+                        "description": "Authentication error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "The county is out of the admin counties",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server error",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 }
             },
-            "delete": {
+            "post": {
                 "security": [
                     {
                         "AdminUserAuth": []
@@ -2096,17 +2205,37 @@ var doc = `{
                         "AdminGroupAuth": []
                     }
                 ],
-                "description": "Deletes all sub accounts",
+                "description": "Creates a location.",
                 "consumes": [
-                    "text/plain"
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
                 ],
                 "tags": [
                     "Admin"
                 ],
-                "operationId": "DeleteAllSubAccounts",
+                "operationId": "CreateLocation",
+                "parameters": [
+                    {
+                        "description": "body data",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/createLocationRequest"
+                        }
+                    }
+                ],
                 "responses": {
                     "200": {
-                        "description": "Successfuly deleted",
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Location"
+                        }
+                    },
+                    "403": {
+                        "description": "The county is out of the admin counties",
                         "schema": {
                             "type": "string"
                         }
@@ -2114,7 +2243,7 @@ var doc = `{
                 }
             }
         },
-        "/admin/raw-sub-accounts/uin/{id}": {
+        "/admin/locations/{id}": {
             "put": {
                 "security": [
                     {
@@ -2124,7 +2253,7 @@ var doc = `{
                         "AdminGroupAuth": []
                     }
                 ],
-                "description": "Updates a sub account.",
+                "description": "Updates a location.",
                 "consumes": [
                     "application/json"
                 ],
@@ -2134,7 +2263,7 @@ var doc = `{
                 "tags": [
                     "Admin"
                 ],
-                "operationId": "UpdateSubAccount",
+                "operationId": "UpdateLocation",
                 "parameters": [
                     {
                         "description": "body data",
@@ -2142,13 +2271,13 @@ var doc = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/updateRawSubAccountRequest"
+                            "$ref": "#/definitions/updateLocationRequest"
                         }
                     },
                     {
                         "type": "string",
-                        "description": "UIN",
-                        "name": "uin",
+                        "description": "ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
@@ -2156,14 +2285,18 @@ var doc = `{
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Location"
+                        }
+                    },
+                    "403": {
+                        "description": "The county is out of the admin counties",
                         "schema": {
                             "type": "string"
                         }
                     }
                 }
-            }
-        },
-        "/admin/raw-sub-accounts/uin/{uin}": {
+            },
             "delete": {
                 "security": [
                     {
@@ -2173,19 +2306,19 @@ var doc = `{
                         "AdminGroupAuth": []
                     }
                 ],
-                "description": "Deletes a sub account by uin",
+                "description": "Deletes a location",
                 "consumes": [
                     "text/plain"
                 ],
                 "tags": [
                     "Admin"
                 ],
-                "operationId": "DeleteSubAccountByUIN",
+                "operationId": "deleteLocation",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "UIN",
-                        "name": "uin",
+                        "description": "ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
@@ -2196,11 +2329,17 @@ var doc = `{
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "403": {
+                        "description": "The county is out of the admin counties",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 }
             }
         },
-        "/admin/resources": {
+        "/admin/manual-tests": {
             "get": {
                 "security": [
                     {
@@ -2210,21 +2349,78 @@ var doc = `{
                         "AdminGroupAuth": []
                     }
                 ],
-                "description": "Gives the resources.",
+                "description": "Gives the manual tests for a county\nThe items can be filtered by the listed fields as field=value or field=operator:value where the operator is eq, ne, in, range, prefix or exists.\nThe items are given in pages if \"limit\" or \"cursor\" is provided. The next page cursor is given in the ROKWIRE-CONTINUATION-TOKEN header.",
                 "consumes": [
                     "application/json"
                 ],
                 "tags": [
                     "Admin"
                 ],
-                "operationId": "getResources",
+                "operationId": "getManualTestsByCountyID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "County ID or all",
+                        "name": "county-id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID",
+                        "name": "id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Location ID",
+                        "name": "location_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date",
+                        "name": "date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Date created",
+                        "name": "date_created",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields - field1,-field2",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/Resource"
+                                "$ref": "#/definitions/ManualTest"
                             }
                         }
                     },
@@ -2234,6 +2430,12 @@ var doc = `{
                             "type": "string"
                         }
                     },
+                    "403": {
+                        "description": "The county is out of the admin counties",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -2247,8 +2449,10 @@ var doc = `{
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/admin/manual-tests/{id}/image": {
+            "get": {
                 "security": [
                     {
                         "AdminUserAuth": []
@@ -2257,73 +2461,50 @@ var doc = `{
                         "AdminGroupAuth": []
                     }
                 ],
-                "description": "Creates a resource",
+                "description": "Gives the image for a manual test",
                 "consumes": [
                     "application/json"
                 ],
-                "produces": [
-                    "application/json"
-                ],
                 "tags": [
                     "Admin"
                 ],
-                "operationId": "createResources",
+                "operationId": "getManualTestImage",
                 "parameters": [
                     {
-                        "description": "body data",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/createResourceRequest"
-                        }
+                        "type": "string",
+                        "description": "Manual Test ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/Resource"
+                            "$ref": "#/definitions/ManualTestImage"
                         }
-                    }
-                }
-            }
-        },
-        "/admin/resources/display-order": {
-            "post": {
-                "security": [
-                    {
-                        "AdminUserAuth": []
                     },
-                    {
-                        "AdminGroupAuth": []
-                    }
-                ],
-                "description": "Updates the display order for all resources.",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Admin"
-                ],
-                "operationId": "updateDisplaOrderResources",
-                "parameters": [
-                    {
-                        "description": "body data",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
+                    "400": {
+                        "description": "Authentication error",
                         "schema": {
-                            "$ref": "#/definitions/updateDisplayOrderResourceRequest"
+                            "type": "string"
                         }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "Successfully updated resource items",
+                    },
+                    "403": {
+                        "description": "The county is out of the admin counties",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server error",
                         "schema": {
                             "type": "string"
                         }
@@ -2331,7 +2512,7 @@ var doc = `{
                 }
             }
         },
-        "/admin/resources/{id}": {
+        "/admin/manual-tests/{id}/process": {
             "put": {
                 "security": [
                     {
@@ -2341,7 +2522,7 @@ var doc = `{
                         "AdminGroupAuth": []
                     }
                 ],
-                "description": "Updates a resource.",
+                "description": "Processes manual test.",
                 "consumes": [
                     "application/json"
                 ],
@@ -2351,7 +2532,7 @@ var doc = `{
                 "tags": [
                     "Admin"
                 ],
-                "operationId": "UpdateResource",
+                "operationId": "ProcessManualTest",
                 "parameters": [
                     {
                         "description": "body data",
@@ -2359,7 +2540,7 @@ var doc = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/updateResourceRequest"
+                            "$ref": "#/definitions/processManualTestRequest"
                         }
                     },
                     {
@@ -2374,12 +2555,20 @@ var doc = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/Resource"
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "The county is out of the admin counties",
+                        "schema": {
+                            "type": "string"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/admin/news": {
+            "get": {
                 "security": [
                     {
                         "AdminUserAuth": []
@@ -2388,34 +2577,44 @@ var doc = `{
                         "AdminGroupAuth": []
                     }
                 ],
-                "description": "Deletes a resource.",
+                "description": "Gives news.",
                 "consumes": [
-                    "text/plain"
+                    "application/json"
                 ],
                 "tags": [
                     "Admin"
                 ],
-                "operationId": "deleteResource",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
+                "operationId": "getNews",
                 "responses": {
                     "200": {
-                        "description": "Successfuly deleted resource item",
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/News"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Authentication error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server error",
                         "schema": {
                             "type": "string"
                         }
                     }
                 }
-            }
-        },
-        "/admin/roster-items": {
+            },
             "post": {
                 "security": [
                     {
@@ -2425,7 +2624,7 @@ var doc = `{
                         "AdminGroupAuth": []
                     }
                 ],
-                "description": "Creates many roster items",
+                "description": "Creates news",
                 "consumes": [
                     "application/json"
                 ],
@@ -2435,7 +2634,7 @@ var doc = `{
                 "tags": [
                     "Admin"
                 ],
-                "operationId": "CreateRosterItems",
+                "operationId": "CreateNews",
                 "parameters": [
                     {
                         "description": "body data",
@@ -2443,7 +2642,7 @@ var doc = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/createRosterItemsRequest"
+                            "$ref": "#/definitions/createNewsRequest"
                         }
                     }
                 ],
@@ -2451,14 +2650,14 @@ var doc = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/News"
                         }
                     }
                 }
             }
         },
-        "/admin/rosters": {
-            "get": {
+        "/admin/news/{id}": {
+            "put": {
                 "security": [
                     {
                         "AdminUserAuth": []
@@ -2467,54 +2666,45 @@ var doc = `{
                         "AdminGroupAuth": []
                     }
                 ],
-                "description": "Gives the roster members matching filters, sorted, and paginated",
+                "description": "Updates news.",
                 "consumes": [
                     "application/json"
                 ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
                     "Admin"
                 ],
-                "operationId": "GetRosters",
+                "operationId": "UpdateNews",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Phone",
-                        "name": "phone",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "UIN",
-                        "name": "uin",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Sort By",
-                        "name": "sortBy",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Order By",
-                        "name": "orderBy",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Limit",
-                        "name": "limit",
-                        "in": "query"
+                        "description": "body data",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/updateNewsRequest"
+                        }
                     },
                     {
                         "type": "string",
-                        "description": "Offset",
-                        "name": "offset",
-                        "in": "query"
+                        "description": "ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
-                ]
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/News"
+                        }
+                    }
+                }
             },
-            "post": {
+            "delete": {
                 "security": [
                     {
                         "AdminUserAuth": []
@@ -2523,38 +2713,35 @@ var doc = `{
                         "AdminGroupAuth": []
                     }
                 ],
-                "description": "Creates a roster",
+                "description": "Deletes news",
                 "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
+                    "text/plain"
                 ],
                 "tags": [
                     "Admin"
                 ],
-                "operationId": "CreateRoster",
+                "operationId": "deleteNews",
                 "parameters": [
                     {
-                        "description": "body data",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/createRosterRequest"
-                        }
+                        "type": "string",
+                        "description": "ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Successfuly deleted new items",
                         "schema": {
                             "type": "string"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/admin/notification-templates": {
+            "get": {
                 "security": [
                     {
                         "AdminUserAuth": []
@@ -2563,26 +2750,27 @@ var doc = `{
                         "AdminGroupAuth": []
                     }
                 ],
-                "description": "Deletes all rosters",
+                "description": "Gives all the notification templates",
                 "consumes": [
-                    "text/plain"
+                    "application/json"
                 ],
                 "tags": [
                     "Admin"
                 ],
-                "operationId": "DeleteAllRosters",
+                "operationId": "GetNotificationTemplates",
                 "responses": {
                     "200": {
-                        "description": "Successfuly deleted",
+                        "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/NotificationTemplate"
+                            }
                         }
                     }
                 }
-            }
-        },
-        "/admin/rosters/phone/{phone}": {
-            "delete": {
+            },
+            "post": {
                 "security": [
                     {
                         "AdminUserAuth": []
@@ -2591,34 +2779,39 @@ var doc = `{
                         "AdminGroupAuth": []
                     }
                 ],
-                "description": "Deletes a roster by phone",
+                "description": "Creates a notification template. The title and the body of the variants may contain {{name}} placeholders.",
                 "consumes": [
-                    "text/plain"
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
                 ],
                 "tags": [
                     "Admin"
                 ],
-                "operationId": "DeleteRosterByPhone",
+                "operationId": "CreateNotificationTemplate",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Phone",
-                        "name": "phone",
-                        "in": "path",
-                        "required": true
+                        "description": "body data",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/createNotificationTemplateRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfuly deleted",
+                        "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/NotificationTemplate"
                         }
                     }
                 }
             }
         },
-        "/admin/rosters/uin/{id}": {
+        "/admin/notification-templates/{id}": {
             "put": {
                 "security": [
                     {
@@ -2628,7 +2821,7 @@ var doc = `{
                         "AdminGroupAuth": []
                     }
                 ],
-                "description": "Updates a roster.",
+                "description": "Updates a notification template.",
                 "consumes": [
                     "application/json"
                 ],
@@ -2638,7 +2831,7 @@ var doc = `{
                 "tags": [
                     "Admin"
                 ],
-                "operationId": "UpdateRoster",
+                "operationId": "UpdateNotificationTemplate",
                 "parameters": [
                     {
                         "description": "body data",
@@ -2646,13 +2839,13 @@ var doc = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/updateRosterRequest"
+                            "$ref": "#/definitions/updateNotificationTemplateRequest"
                         }
                     },
                     {
                         "type": "string",
-                        "description": "UIN",
-                        "name": "uin",
+                        "description": "ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
@@ -2661,13 +2854,11 @@ var doc = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/NotificationTemplate"
                         }
                     }
                 }
-            }
-        },
-        "/admin/rosters/uin/{uin}": {
+            },
             "delete": {
                 "security": [
                     {
@@ -2677,19 +2868,19 @@ var doc = `{
                         "AdminGroupAuth": []
                     }
                 ],
-                "description": "Deletes a roster by uin",
+                "description": "Deletes a notification template",
                 "consumes": [
                     "text/plain"
                 ],
                 "tags": [
                     "Admin"
                 ],
-                "operationId": "DeleteRosterByUIN",
+                "operationId": "DeleteNotificationTemplate",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "UIN",
-                        "name": "uin",
+                        "description": "ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
@@ -2704,7 +2895,7 @@ var doc = `{
                 }
             }
         },
-        "/admin/rules": {
+        "/admin/permissions": {
             "get": {
                 "security": [
                     {
@@ -2714,45 +2905,29 @@ var doc = `{
                         "AdminGroupAuth": []
                     }
                 ],
-                "description": "Gives the rules list",
+                "description": "Gives the permissions which the roles can have.",
                 "consumes": [
                     "application/json"
                 ],
                 "tags": [
                     "Admin"
                 ],
-                "operationId": "getRules",
+                "operationId": "GetPermissions",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/ARule"
+                                "type": "string"
                             }
                         }
-                    },
-                    "400": {
-                        "description": "Authentication error",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server error",
-                        "schema": {
-                            "type": "string"
-                        }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/admin/provider-credentials/{id}/revoke": {
+            "put": {
                 "security": [
                     {
                         "AdminUserAuth": []
@@ -2761,39 +2936,42 @@ var doc = `{
                         "AdminGroupAuth": []
                     }
                 ],
-                "description": "Creates a rule",
+                "description": "Revokes a provider credential. It cannot be used anymore.",
                 "consumes": [
                     "application/json"
                 ],
-                "produces": [
-                    "application/json"
-                ],
                 "tags": [
                     "Admin"
                 ],
-                "operationId": "CreateRule",
+                "operationId": "RevokeProviderCredential",
                 "parameters": [
                     {
                         "description": "body data",
                         "name": "data",
                         "in": "body",
-                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/createRuleRequest"
+                            "$ref": "#/definitions/revokeProviderCredentialRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Credential ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Successfully revoked",
                         "schema": {
-                            "$ref": "#/definitions/ARule"
+                            "type": "string"
                         }
                     }
                 }
             }
         },
-        "/admin/rules/{id}": {
+        "/admin/provider-credentials/{id}/rotate": {
             "put": {
                 "security": [
                     {
@@ -2803,7 +2981,7 @@ var doc = `{
                         "AdminGroupAuth": []
                     }
                 ],
-                "description": "Updates a rule.",
+                "description": "Creates a new credential with the settings of the rotated one. The rotated credential stays valid for the grace period - 24 hours if not provided.",
                 "consumes": [
                     "application/json"
                 ],
@@ -2813,20 +2991,19 @@ var doc = `{
                 "tags": [
                     "Admin"
                 ],
-                "operationId": "UpdateRule",
+                "operationId": "RotateProviderCredential",
                 "parameters": [
                     {
                         "description": "body data",
                         "name": "data",
                         "in": "body",
-                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/updateRuleRequest"
+                            "$ref": "#/definitions/rotateProviderCredentialRequest"
                         }
                     },
                     {
                         "type": "string",
-                        "description": "ID",
+                        "description": "Credential ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -2836,12 +3013,14 @@ var doc = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/ARule"
+                            "$ref": "#/definitions/providerCredentialResponse"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/admin/providers": {
+            "get": {
                 "security": [
                     {
                         "AdminUserAuth": []
@@ -2850,109 +3029,65 @@ var doc = `{
                         "AdminGroupAuth": []
                     }
                 ],
-                "description": "Deletes a rule",
+                "description": "Gives the providers list\nThe items can be filtered by the listed fields as field=value or field=operator:value where the operator is eq, ne, in, range, prefix or exists.\nThe items are given in pages if \"limit\" or \"cursor\" is provided. The next page cursor is given in the ROKWIRE-CONTINUATION-TOKEN header.",
                 "consumes": [
-                    "text/plain"
+                    "application/json"
                 ],
                 "tags": [
                     "Admin"
                 ],
-                "operationId": "deleteRule",
+                "operationId": "getProviders",
                 "parameters": [
                     {
                         "type": "string",
                         "description": "ID",
                         "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "Successfuly deleted",
-                        "schema": {
-                            "type": "string"
-                        }
-                    }
-                }
-            }
-        },
-        "/admin/symptom-groups": {
-            "get": {
-                "security": [
+                        "in": "query"
+                    },
                     {
-                        "AdminUserAuth": []
+                        "type": "string",
+                        "description": "Provider name",
+                        "name": "provider_name",
+                        "in": "query"
                     },
                     {
-                        "AdminGroupAuth": []
-                    }
-                ],
-                "description": "Gives the symptom groups",
-                "consumes": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Admin"
-                ],
-                "operationId": "getSymptomGroups",
-                "deprecated": true,
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/SymptomGroup"
-                            }
-                        }
+                        "type": "string",
+                        "description": "Manual test",
+                        "name": "manual_test",
+                        "in": "query"
                     },
-                    "400": {
-                        "description": "Authentication error",
-                        "schema": {
-                            "type": "string"
-                        }
+                    {
+                        "type": "string",
+                        "description": "Date created",
+                        "name": "date_created",
+                        "in": "query"
                     },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "type": "string"
-                        }
+                    {
+                        "type": "string",
+                        "description": "Sort fields - field1,-field2",
+                        "name": "sort",
+                        "in": "query"
                     },
-                    "500": {
-                        "description": "Internal Server error",
-                        "schema": {
-                            "type": "string"
-                        }
-                    }
-                }
-            }
-        },
-        "/admin/symptom-rules": {
-            "get": {
-                "security": [
                     {
-                        "AdminUserAuth": []
+                        "type": "string",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
                     },
                     {
-                        "AdminGroupAuth": []
+                        "type": "string",
+                        "description": "Cursor",
+                        "name": "cursor",
+                        "in": "query"
                     }
                 ],
-                "description": "Gives the symptom rules",
-                "consumes": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Admin"
-                ],
-                "operationId": "getSymptomRules",
-                "deprecated": true,
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/SymptomRule"
+                                "$ref": "#/definitions/Provider"
                             }
                         }
                     },
@@ -2985,7 +3120,7 @@ var doc = `{
                         "AdminGroupAuth": []
                     }
                 ],
-                "description": "Creates a symptom rule.",
+                "description": "Creates a provider",
                 "consumes": [
                     "application/json"
                 ],
@@ -2995,8 +3130,7 @@ var doc = `{
                 "tags": [
                     "Admin"
                 ],
-                "operationId": "CreateSymptomRule",
-                "deprecated": true,
+                "operationId": "createProvider",
                 "parameters": [
                     {
                         "description": "body data",
@@ -3004,7 +3138,7 @@ var doc = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/createSymptomRuleRequest"
+                            "$ref": "#/definitions/createProviderRequest"
                         }
                     }
                 ],
@@ -3012,13 +3146,13 @@ var doc = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/SymptomRule"
+                            "$ref": "#/definitions/Provider"
                         }
                     }
                 }
             }
         },
-        "/admin/symptom-rules/{id}": {
+        "/admin/providers/{id}": {
             "put": {
                 "security": [
                     {
@@ -3028,7 +3162,7 @@ var doc = `{
                         "AdminGroupAuth": []
                     }
                 ],
-                "description": "Updates a symptom rule.",
+                "description": "Updates a provider.",
                 "consumes": [
                     "application/json"
                 ],
@@ -3038,8 +3172,7 @@ var doc = `{
                 "tags": [
                     "Admin"
                 ],
-                "operationId": "UpdateSymptomRule",
-                "deprecated": true,
+                "operationId": "UpdateProvider",
                 "parameters": [
                     {
                         "description": "body data",
@@ -3047,7 +3180,7 @@ var doc = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/updateSymptomRuleRequest"
+                            "$ref": "#/definitions/updateProviderRequest"
                         }
                     },
                     {
@@ -3062,7 +3195,7 @@ var doc = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/SymptomRule"
+                            "$ref": "#/definitions/Provider"
                         }
                     }
                 }
@@ -3076,15 +3209,14 @@ var doc = `{
                         "AdminGroupAuth": []
                     }
                 ],
-                "description": "Deletes a symptom rule.",
+                "description": "Deletes a provider",
                 "consumes": [
                     "text/plain"
                 ],
                 "tags": [
                     "Admin"
                 ],
-                "operationId": "deleteSymptomRule",
-                "deprecated": true,
+                "operationId": "deleteProvider",
                 "parameters": [
                     {
                         "type": "string",
@@ -3104,7 +3236,7 @@ var doc = `{
                 }
             }
         },
-        "/admin/symptoms": {
+        "/admin/providers/{id}/credentials": {
             "get": {
                 "security": [
                     {
@@ -3114,32 +3246,36 @@ var doc = `{
                         "AdminGroupAuth": []
                     }
                 ],
-                "description": "Gives the symptoms",
+                "description": "Gives the credentials of a provider. The keys and the signing secrets are not given.",
                 "consumes": [
                     "application/json"
                 ],
                 "tags": [
                     "Admin"
                 ],
-                "operationId": "GetASymptoms",
+                "operationId": "GetProviderCredentials",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "App version",
-                        "name": "app-version",
-                        "in": "query"
+                        "description": "Provider ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/ProviderCredential"
+                            }
                         }
                     }
                 }
             },
-            "put": {
+            "post": {
                 "security": [
                     {
                         "AdminUserAuth": []
@@ -3148,7 +3284,7 @@ var doc = `{
                         "AdminGroupAuth": []
                     }
                 ],
-                "description": "Creates symptoms or update them if already created.",
+                "description": "Creates a credential for a provider. The API key and the signing secret are given only in this response.\nThe requests made with the credential are bound to the provider and they must be signed if \"require_signature\" is set.\nThe credential can create ctests for its provider, the other providers APIs need the scopes - users.read, track.read,\nuin-overrides.read, uin-overrides.write and building-access.read.",
                 "consumes": [
                     "application/json"
                 ],
@@ -3158,7 +3294,7 @@ var doc = `{
                 "tags": [
                     "Admin"
                 ],
-                "operationId": "CreateorUpdateSymptoms",
+                "operationId": "CreateProviderCredential",
                 "parameters": [
                     {
                         "description": "body data",
@@ -3166,19 +3302,28 @@ var doc = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/createOrUpdateSymptomsRequest"
+                            "$ref": "#/definitions/createProviderCredentialRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Provider ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/providerCredentialResponse"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/admin/raw-sub-account-items": {
             "post": {
                 "security": [
                     {
@@ -3188,7 +3333,7 @@ var doc = `{
                         "AdminGroupAuth": []
                     }
                 ],
-                "description": "Creates a symptom",
+                "description": "Creates sub account items",
                 "consumes": [
                     "application/json"
                 ],
@@ -3198,8 +3343,7 @@ var doc = `{
                 "tags": [
                     "Admin"
                 ],
-                "operationId": "CreateSymptom",
-                "deprecated": true,
+                "operationId": "CreateSubAccountItems",
                 "parameters": [
                     {
                         "description": "body data",
@@ -3207,7 +3351,7 @@ var doc = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/createSymptomRequest"
+                            "$ref": "#/definitions/createRawSubAccountItemsRequest"
                         }
                     }
                 ],
@@ -3215,14 +3359,14 @@ var doc = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/Symptom"
+                            "type": "string"
                         }
                     }
                 }
             }
         },
-        "/admin/symptoms/{id}": {
-            "put": {
+        "/admin/raw-sub-accounts": {
+            "get": {
                 "security": [
                     {
                         "AdminUserAuth": []
@@ -3231,84 +3375,65 @@ var doc = `{
                         "AdminGroupAuth": []
                     }
                 ],
-                "description": "Updates a symptom.",
+                "description": "Gives the sub accounts matching filters, sorted, and paginated",
                 "consumes": [
                     "application/json"
                 ],
-                "produces": [
-                    "application/json"
-                ],
                 "tags": [
                     "Admin"
                 ],
-                "operationId": "UpdateSymptom",
-                "deprecated": true,
+                "operationId": "GetSubAccounts",
                 "parameters": [
                     {
-                        "description": "body data",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/updateSymptomRequest"
-                        }
+                        "type": "string",
+                        "description": "Phone",
+                        "name": "phone",
+                        "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/Symptom"
-                        }
-                    }
-                }
-            },
-            "delete": {
-                "security": [
+                        "description": "UIN",
+                        "name": "uin",
+                        "in": "query"
+                    },
                     {
-                        "AdminUserAuth": []
+                        "type": "string",
+                        "description": "Sort By",
+                        "name": "sortBy",
+                        "in": "query"
                     },
                     {
-                        "AdminGroupAuth": []
-                    }
-                ],
-                "description": "Deletes a symptom",
-                "consumes": [
-                    "text/plain"
-                ],
-                "tags": [
-                    "Admin"
-                ],
-                "operationId": "deleteSymptom",
-                "deprecated": true,
-                "parameters": [
+                        "type": "string",
+                        "description": "Order By",
+                        "name": "orderBy",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
-                        "description": "ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Offset",
+                        "name": "offset",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfuly deleted",
+                        "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/RawSubAccount"
+                            }
                         }
                     }
                 }
-            }
-        },
-        "/admin/test-type-results": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "AdminUserAuth": []
@@ -3317,54 +3442,26 @@ var doc = `{
                         "AdminGroupAuth": []
                     }
                 ],
-                "description": "Gets all test type results for a test type.",
+                "description": "Deletes all sub accounts",
                 "consumes": [
-                    "application/json"
+                    "text/plain"
                 ],
                 "tags": [
                     "Admin"
                 ],
-                "operationId": "getTestTypeResultsByTestTypeID",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Test Type ID",
-                        "name": "test-type-id",
-                        "in": "query",
-                        "required": true
-                    }
-                ],
+                "operationId": "DeleteAllSubAccounts",
                 "responses": {
                     "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/TestTypeResult"
-                            }
-                        }
-                    },
-                    "400": {
-                        "description": "Authentication error",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server error",
+                        "description": "Successfuly deleted",
                         "schema": {
                             "type": "string"
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/admin/raw-sub-accounts/uin/{id}": {
+            "put": {
                 "security": [
                     {
                         "AdminUserAuth": []
@@ -3373,7 +3470,7 @@ var doc = `{
                         "AdminGroupAuth": []
                     }
                 ],
-                "description": "Creates a test type result for a specific test type.",
+                "description": "Updates a sub account.",
                 "consumes": [
                     "application/json"
                 ],
@@ -3383,7 +3480,7 @@ var doc = `{
                 "tags": [
                     "Admin"
                 ],
-                "operationId": "CreateTestTypeResult",
+                "operationId": "UpdateSubAccount",
                 "parameters": [
                     {
                         "description": "body data",
@@ -3391,22 +3488,29 @@ var doc = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/createTestTypeResultRequest"
+                            "$ref": "#/definitions/updateRawSubAccountRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "UIN",
+                        "name": "uin",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/TestTypeResult"
+                            "type": "string"
                         }
                     }
                 }
             }
         },
-        "/admin/test-type-results/{id}": {
-            "put": {
+        "/admin/raw-sub-accounts/uin/{uin}": {
+            "delete": {
                 "security": [
                     {
                         "AdminUserAuth": []
@@ -3415,45 +3519,35 @@ var doc = `{
                         "AdminGroupAuth": []
                     }
                 ],
-                "description": "Updates test type result.",
+                "description": "Deletes a sub account by uin",
                 "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
+                    "text/plain"
                 ],
                 "tags": [
                     "Admin"
                 ],
-                "operationId": "UpdateTestTypeResult",
+                "operationId": "DeleteSubAccountByUIN",
                 "parameters": [
-                    {
-                        "description": "body data",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/updateTestTypeResultRequest"
-                        }
-                    },
                     {
                         "type": "string",
-                        "description": "ID",
-                        "name": "id",
+                        "description": "UIN",
+                        "name": "uin",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Successfuly deleted",
                         "schema": {
-                            "$ref": "#/definitions/TestTypeResult"
+                            "type": "string"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/admin/raw-sub-accounts/uin/{uin}/revoke": {
+            "put": {
                 "security": [
                     {
                         "AdminUserAuth": []
@@ -3462,26 +3556,34 @@ var doc = `{
                         "AdminGroupAuth": []
                     }
                 ],
-                "description": "Deletes a test type result",
+                "description": "Revokes the link of an accepted sub account. The account of the primary user becomes not active.",
                 "consumes": [
-                    "text/plain"
+                    "application/json"
                 ],
                 "tags": [
                     "Admin"
                 ],
-                "operationId": "deleteTestTypeResult",
+                "operationId": "RevokeSubAccount",
                 "parameters": [
+                    {
+                        "description": "body data",
+                        "name": "data",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/revokeSubAccountRequest"
+                        }
+                    },
                     {
                         "type": "string",
-                        "description": "ID",
-                        "name": "id",
+                        "description": "UIN",
+                        "name": "uin",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfuly deleted",
+                        "description": "Successfully revoked",
                         "schema": {
                             "type": "string"
                         }
@@ -3489,7 +3591,7 @@ var doc = `{
                 }
             }
         },
-        "/admin/test-types": {
+        "/admin/resources": {
             "get": {
                 "security": [
                     {
@@ -3499,21 +3601,21 @@ var doc = `{
                         "AdminGroupAuth": []
                     }
                 ],
-                "description": "Gives the test types",
+                "description": "Gives the resources.",
                 "consumes": [
                     "application/json"
                 ],
                 "tags": [
                     "Admin"
                 ],
-                "operationId": "getTestTypes",
+                "operationId": "getResources",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/ATestType"
+                                "$ref": "#/definitions/Resource"
                             }
                         }
                     },
@@ -3546,7 +3648,7 @@ var doc = `{
                         "AdminGroupAuth": []
                     }
                 ],
-                "description": "Creates a test type.",
+                "description": "Creates a resource",
                 "consumes": [
                     "application/json"
                 ],
@@ -3556,7 +3658,7 @@ var doc = `{
                 "tags": [
                     "Admin"
                 ],
-                "operationId": "createTestType",
+                "operationId": "createResources",
                 "parameters": [
                     {
                         "description": "body data",
@@ -3564,7 +3666,7 @@ var doc = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/createTestTypeRequest"
+                            "$ref": "#/definitions/createResourceRequest"
                         }
                     }
                 ],
@@ -3572,13 +3674,55 @@ var doc = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/ATestType"
+                            "$ref": "#/definitions/Resource"
                         }
                     }
                 }
             }
         },
-        "/admin/test-types/{id}": {
+        "/admin/resources/display-order": {
+            "post": {
+                "security": [
+                    {
+                        "AdminUserAuth": []
+                    },
+                    {
+                        "AdminGroupAuth": []
+                    }
+                ],
+                "description": "Updates the display order for all resources.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "operationId": "updateDisplaOrderResources",
+                "parameters": [
+                    {
+                        "description": "body data",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/updateDisplayOrderResourceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully updated resource items",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/resources/{id}": {
             "put": {
                 "security": [
                     {
@@ -3588,7 +3732,7 @@ var doc = `{
                         "AdminGroupAuth": []
                     }
                 ],
-                "description": "Updates a test type.",
+                "description": "Updates a resource.",
                 "consumes": [
                     "application/json"
                 ],
@@ -3598,7 +3742,7 @@ var doc = `{
                 "tags": [
                     "Admin"
                 ],
-                "operationId": "UpdateTestType",
+                "operationId": "UpdateResource",
                 "parameters": [
                     {
                         "description": "body data",
@@ -3606,7 +3750,7 @@ var doc = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/updateTestTypeRequest"
+                            "$ref": "#/definitions/updateResourceRequest"
                         }
                     },
                     {
@@ -3621,7 +3765,7 @@ var doc = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/ATestType"
+                            "$ref": "#/definitions/Resource"
                         }
                     }
                 }
@@ -3635,14 +3779,14 @@ var doc = `{
                         "AdminGroupAuth": []
                     }
                 ],
-                "description": "Deletes a test type",
+                "description": "Deletes a resource.",
                 "consumes": [
                     "text/plain"
                 ],
                 "tags": [
                     "Admin"
                 ],
-                "operationId": "deleteTestType",
+                "operationId": "deleteResource",
                 "parameters": [
                     {
                         "type": "string",
@@ -3654,7 +3798,7 @@ var doc = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfuly deleted",
+                        "description": "Successfuly deleted resource item",
                         "schema": {
                             "type": "string"
                         }
@@ -3662,7 +3806,7 @@ var doc = `{
                 }
             }
         },
-        "/admin/uin-overrides": {
+        "/admin/roles": {
             "get": {
                 "security": [
                     {
@@ -3672,35 +3816,21 @@ var doc = `{
                         "AdminGroupAuth": []
                     }
                 ],
-                "description": "Gives uin override items",
+                "description": "Gives the roles with their groups and permissions.",
                 "consumes": [
                     "application/json"
                 ],
                 "tags": [
                     "Admin"
                 ],
-                "operationId": "GetUINOverrides",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "UIN",
-                        "name": "uin",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Sort by uin or category",
-                        "name": "sort",
-                        "in": "query"
-                    }
-                ],
+                "operationId": "GetRoles",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/UINOverride"
+                                "$ref": "#/definitions/Role"
                             }
                         }
                     }
@@ -3715,7 +3845,7 @@ var doc = `{
                         "AdminGroupAuth": []
                     }
                 ],
-                "description": "Creates an uin override.",
+                "description": "Creates a role. The members of the role groups get the role permissions. The permissions for the county bound entities are limited to the role counties when they are provided.",
                 "consumes": [
                     "application/json"
                 ],
@@ -3725,7 +3855,7 @@ var doc = `{
                 "tags": [
                     "Admin"
                 ],
-                "operationId": "CreateUINOverride",
+                "operationId": "CreateRole",
                 "parameters": [
                     {
                         "description": "body data",
@@ -3733,7 +3863,7 @@ var doc = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/createUINOverrideRequest"
+                            "$ref": "#/definitions/roleRequest"
                         }
                     }
                 ],
@@ -3741,13 +3871,13 @@ var doc = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/UINOverride"
+                            "$ref": "#/definitions/Role"
                         }
                     }
                 }
             }
         },
-        "/admin/uin-overrides/uin/{uin}": {
+        "/admin/roles/{id}": {
             "put": {
                 "security": [
                     {
@@ -3757,7 +3887,7 @@ var doc = `{
                         "AdminGroupAuth": []
                     }
                 ],
-                "description": "Updates uin override.",
+                "description": "Updates a role. At least one role with groups must keep the roles.write permission.",
                 "consumes": [
                     "application/json"
                 ],
@@ -3767,7 +3897,7 @@ var doc = `{
                 "tags": [
                     "Admin"
                 ],
-                "operationId": "UpdateUINOverride",
+                "operationId": "UpdateRole",
                 "parameters": [
                     {
                         "description": "body data",
@@ -3775,13 +3905,13 @@ var doc = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/updateUINOverrideRequest"
+                            "$ref": "#/definitions/roleRequest"
                         }
                     },
                     {
                         "type": "string",
-                        "description": "UIN",
-                        "name": "uin",
+                        "description": "ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
@@ -3790,7 +3920,7 @@ var doc = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/Role"
                         }
                     }
                 }
@@ -3804,26 +3934,26 @@ var doc = `{
                         "AdminGroupAuth": []
                     }
                 ],
-                "description": "Deletes an uin override",
+                "description": "Deletes a role. At least one role with groups must keep the roles.write permission.",
                 "consumes": [
                     "text/plain"
                 ],
                 "tags": [
                     "Admin"
                 ],
-                "operationId": "DeleteUINOverride",
+                "operationId": "DeleteRole",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "UIN",
-                        "name": "uin",
+                        "description": "ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfuly deleted",
+                        "description": "Successfully deleted",
                         "schema": {
                             "type": "string"
                         }
@@ -3831,8 +3961,8 @@ var doc = `{
                 }
             }
         },
-        "/admin/user": {
-            "get": {
+        "/admin/roster-items": {
+            "post": {
                 "security": [
                     {
                         "AdminUserAuth": []
@@ -3841,44 +3971,31 @@ var doc = `{
                         "AdminGroupAuth": []
                     }
                 ],
-                "description": "Gets the user by external id.",
+                "description": "Creates many roster items",
                 "consumes": [
                     "application/json"
                 ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
                     "Admin"
                 ],
-                "operationId": "GetUserByExternalID",
+                "operationId": "CreateRosterItems",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "External ID",
-                        "name": "external-id",
-                        "in": "query",
-                        "required": true
+                        "description": "body data",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/createRosterItemsRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/User"
-                        }
-                    },
-                    "400": {
-                        "description": "Authentication error",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server error",
                         "schema": {
                             "type": "string"
                         }
@@ -3886,55 +4003,119 @@ var doc = `{
                 }
             }
         },
-        "/covid19/access-rules/county/{id}": {
+        "/admin/rosters": {
             "get": {
                 "security": [
                     {
-                        "RokwireAuth": []
+                        "AdminUserAuth": []
+                    },
+                    {
+                        "AdminGroupAuth": []
                     }
                 ],
-                "description": "Gives the access rule for a county.",
+                "description": "Gives the roster members matching filters, sorted, and paginated\nThe items can be filtered by the listed fields as field=value or field=operator:value where the operator is eq, ne, in, range, prefix or exists.\nThe items are given in pages if \"limit\" or \"cursor\" is provided. The next page cursor is given in the ROKWIRE-CONTINUATION-TOKEN header.\nThe \"offset\" param keeps the old behaviour where the filters match parts of the values.",
                 "consumes": [
                     "application/json"
                 ],
                 "tags": [
-                    "Covid19"
+                    "Admin"
                 ],
-                "operationId": "GetAccessRuleByCounty",
+                "operationId": "GetRosters",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "County ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "TODO",
-                        "schema": {
-                            "type": "string"
-                        }
+                        "description": "Phone",
+                        "name": "phone",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "UIN",
+                        "name": "uin",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "First name",
+                        "name": "first_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last name",
+                        "name": "last_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Email",
+                        "name": "email",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Badge type",
+                        "name": "badge_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields - field1,-field2",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort By",
+                        "name": "sortBy",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort order - 1 or -1",
+                        "name": "sortOrder",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Offset",
+                        "name": "offset",
+                        "in": "query"
                     }
-                }
-            }
-        },
-        "/covid19/building-access": {
-            "put": {
+                ]
+            },
+            "post": {
                 "security": [
                     {
-                        "AppUserAuth": []
+                        "AdminUserAuth": []
                     },
                     {
-                        "AppUserAccountAuth": []
+                        "AdminGroupAuth": []
                     }
                 ],
-                "description": "grant/deny building access",
+                "description": "Creates a roster",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Covid19"
+                    "Admin"
                 ],
-                "operationId": "SetUINBuildingAccess",
+                "operationId": "CreateRoster",
                 "parameters": [
                     {
                         "description": "body data",
@@ -3942,7 +4123,7 @@ var doc = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/setBuildingAccessRequest"
+                            "$ref": "#/definitions/createRosterRequest"
                         }
                     }
                 ],
@@ -3954,40 +4135,79 @@ var doc = `{
                         }
                     }
                 }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "AdminUserAuth": []
+                    },
+                    {
+                        "AdminGroupAuth": []
+                    }
+                ],
+                "description": "Deletes all rosters",
+                "consumes": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "operationId": "DeleteAllRosters",
+                "responses": {
+                    "200": {
+                        "description": "Successfuly deleted",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
             }
         },
-        "/covid19/counties": {
-            "get": {
+        "/admin/rosters/import": {
+            "post": {
                 "security": [
                     {
-                        "RokwireAuth": []
+                        "AdminUserAuth": []
+                    },
+                    {
+                        "AdminGroupAuth": []
                     }
                 ],
-                "description": "Gives the counties. Optionally you can filter the results by one or many of the following three fields - name, state_province and country",
+                "description": "Imports roster members from a csv or xlsx file. The first row must be a header. The columns are mapped to the roster fields\nby the \"mapping\" json object - {\"column header\":\"roster field\"}, the headers are used as roster fields if there is no mapping.\nThe phones must be in the E.164 format and the uins must have 9 digits. The invalid rows and the rows which phones or uins\nare already in the roster are not imported. The dry run only validates the file and gives a preview of the valid items.\nThe xlsx files can have up to 16384 columns(XFD) and 5000000 cells.",
                 "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Covid19"
+                    "Admin"
                 ],
-                "operationId": "GetCounties",
+                "operationId": "ImportRoster",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "name",
-                        "name": "name",
-                        "in": "query"
+                        "type": "file",
+                        "description": "The csv or xlsx file",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
                     },
                     {
                         "type": "string",
-                        "description": "State province",
-                        "name": "state_province",
-                        "in": "query"
+                        "description": "Columns mapping",
+                        "name": "mapping",
+                        "in": "formData"
                     },
                     {
                         "type": "string",
-                        "description": "Country",
-                        "name": "country",
+                        "description": "Audit",
+                        "name": "audit",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Dry run",
+                        "name": "dry-run",
                         "in": "query"
                     }
                 ],
@@ -3995,34 +4215,34 @@ var doc = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/County"
-                            }
+                            "$ref": "#/definitions/RosterImport"
                         }
                     }
                 }
             }
         },
-        "/covid19/counties/{id}": {
+        "/admin/rosters/imports/{id}/errors": {
             "get": {
                 "security": [
                     {
-                        "RokwireAuth": []
+                        "AdminUserAuth": []
+                    },
+                    {
+                        "AdminGroupAuth": []
                     }
                 ],
-                "description": "Gets a county",
-                "consumes": [
-                    "application/json"
+                "description": "Gives the invalid and the duplicated rows of a roster import as a csv file. The reports are kept for 30 days.\nOnly the first 10000 errors are kept, the report \"errors_count\" has the count of all errors.",
+                "produces": [
+                    "text/csv"
                 ],
                 "tags": [
-                    "Covid19"
+                    "Admin"
                 ],
-                "operationId": "getCounty",
+                "operationId": "GetRosterImportErrors",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID",
+                        "description": "Import ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -4030,41 +4250,44 @@ var doc = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "The errors csv",
                         "schema": {
-                            "$ref": "#/definitions/County"
+                            "type": "string"
                         }
                     }
                 }
             }
         },
-        "/covid19/crules/county/{id}": {
-            "get": {
+        "/admin/rosters/phone/{phone}": {
+            "delete": {
                 "security": [
                     {
-                        "RokwireAuth": []
+                        "AdminUserAuth": []
+                    },
+                    {
+                        "AdminGroupAuth": []
                     }
                 ],
-                "description": "Gives the rules for a county.",
+                "description": "Deletes a roster by phone",
                 "consumes": [
-                    "application/json"
+                    "text/plain"
                 ],
                 "tags": [
-                    "Covid19"
+                    "Admin"
                 ],
-                "operationId": "GetCRulesByCounty",
+                "operationId": "DeleteRosterByPhone",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "County ID",
-                        "name": "id",
+                        "description": "Phone",
+                        "name": "phone",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Successfuly deleted",
                         "schema": {
                             "type": "string"
                         }
@@ -4072,51 +4295,17 @@ var doc = `{
                 }
             }
         },
-        "/covid19/ctests": {
-            "get": {
+        "/admin/rosters/sync": {
+            "put": {
                 "security": [
                     {
-                        "AppUserAuth": []
+                        "AdminUserAuth": []
                     },
                     {
-                        "AppUserAccountAuth": []
-                    }
-                ],
-                "description": "Gets not processed ctests for a user.",
-                "consumes": [
-                    "application/json"
-                ],
-                "tags": [
-                    "Covid19"
-                ],
-                "operationId": "getCTests",
-                "parameters": [
-                    {
-                        "type": "boolean",
-                        "description": "select false value",
-                        "name": "processed",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/model.CTest"
-                            }
-                        }
-                    }
-                }
-            },
-            "post": {
-                "security": [
-                    {
-                        "ProvidersAuth": []
+                        "AdminGroupAuth": []
                     }
                 ],
-                "description": "Creates CTest.",
+                "description": "Makes the roster the same as the provided items - adds the new uins, updates the changed items and removes the uins which are not provided.\nAll the changes are applied in one transaction. The dry run only gives the differences.\nThe phone conflicts are the items which take a phone held by another roster item - the phones are swapped or moved by the sync.",
                 "consumes": [
                     "application/json"
                 ],
@@ -4124,9 +4313,9 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Providers"
+                    "Admin"
                 ],
-                "operationId": "createCTest",
+                "operationId": "SyncRosters",
                 "parameters": [
                     {
                         "description": "body data",
@@ -4134,57 +4323,37 @@ var doc = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/createCTestRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "Successfully created",
-                        "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/syncRostersRequest"
                         }
-                    }
-                }
-            },
-            "delete": {
-                "security": [
-                    {
-                        "AppUserAuth": []
                     },
                     {
-                        "AppUserAccountAuth": []
+                        "type": "boolean",
+                        "description": "Dry run",
+                        "name": "dry-run",
+                        "in": "query"
                     }
                 ],
-                "description": "Deletes all ctests for a user",
-                "consumes": [
-                    "text/plain"
-                ],
-                "tags": [
-                    "Covid19"
-                ],
-                "operationId": "deleteCTests",
                 "responses": {
                     "200": {
-                        "description": "Successfuly deleted [n] items",
+                        "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/RosterSync"
                         }
                     }
                 }
             }
         },
-        "/covid19/ctests/{id}": {
+        "/admin/rosters/uin/{id}": {
             "put": {
                 "security": [
                     {
-                        "AppUserAuth": []
+                        "AdminUserAuth": []
                     },
                     {
-                        "AppUserAccountAuth": []
+                        "AdminGroupAuth": []
                     }
                 ],
-                "description": "Mark ctest as processed.",
+                "description": "Updates a roster.",
                 "consumes": [
                     "application/json"
                 ],
@@ -4192,9 +4361,9 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Covid19"
+                    "Admin"
                 ],
-                "operationId": "updateCTest",
+                "operationId": "UpdateRoster",
                 "parameters": [
                     {
                         "description": "body data",
@@ -4202,13 +4371,13 @@ var doc = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/updateCTestRequest"
+                            "$ref": "#/definitions/updateRosterRequest"
                         }
                     },
                     {
                         "type": "string",
-                        "description": "ID",
-                        "name": "id",
+                        "description": "UIN",
+                        "name": "uin",
                         "in": "path",
                         "required": true
                     }
@@ -4217,94 +4386,113 @@ var doc = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.CTest"
+                            "type": "string"
                         }
                     }
                 }
             }
         },
-        "/covid19/ext/building-access": {
-            "get": {
+        "/admin/rosters/uin/{uin}": {
+            "delete": {
                 "security": [
                     {
-                        "ProvidersAuth": []
+                        "AdminUserAuth": []
+                    },
+                    {
+                        "AdminGroupAuth": []
                     }
                 ],
-                "description": "Gives the building access for the provided UIN",
+                "description": "Deletes a roster by uin",
                 "consumes": [
-                    "application/json"
+                    "text/plain"
                 ],
                 "tags": [
-                    "Providers"
+                    "Admin"
                 ],
-                "operationId": "GetExtBuildingAccess",
+                "operationId": "DeleteRosterByUIN",
                 "parameters": [
                     {
                         "type": "string",
                         "description": "UIN",
                         "name": "uin",
-                        "in": "query",
+                        "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Successfuly deleted",
                         "schema": {
-                            "$ref": "#/definitions/UINBuildingAccess"
+                            "type": "string"
                         }
                     }
                 }
             }
         },
-        "/covid19/ext/uin-overrides": {
+        "/admin/rules": {
             "get": {
                 "security": [
                     {
-                        "ProvidersAuth": []
+                        "AdminUserAuth": []
+                    },
+                    {
+                        "AdminGroupAuth": []
                     }
                 ],
-                "description": "Gives the UIN overrides elements. The list can be filtered by UIN and sorted by UIN or Category",
+                "description": "Gives the rules list",
                 "consumes": [
                     "application/json"
                 ],
                 "tags": [
-                    "Providers"
-                ],
-                "operationId": "GetExtUINOverrides",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "UIN",
-                        "name": "uin",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Sort by uin or category",
-                        "name": "sort",
-                        "in": "query"
-                    }
+                    "Admin"
                 ],
+                "operationId": "getRules",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/UINOverride"
+                                "$ref": "#/definitions/ARule"
                             }
                         }
+                    },
+                    "400": {
+                        "description": "Authentication error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "The county is out of the admin counties",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server error",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 }
             },
             "post": {
                 "security": [
                     {
-                        "ProvidersAuth": []
+                        "AdminUserAuth": []
+                    },
+                    {
+                        "AdminGroupAuth": []
                     }
                 ],
-                "description": "Creates an uin override. The date format of the expiration field is \"2021-12-09T08:09:49.259Z\"",
+                "description": "Creates a rule",
                 "consumes": [
                     "application/json"
                 ],
@@ -4312,9 +4500,9 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Providers"
+                    "Admin"
                 ],
-                "operationId": "CreateExtUINOverrides",
+                "operationId": "CreateRule",
                 "parameters": [
                     {
                         "description": "body data",
@@ -4322,7 +4510,7 @@ var doc = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/createExtUINOverrideRequest"
+                            "$ref": "#/definitions/createRuleRequest"
                         }
                     }
                 ],
@@ -4330,20 +4518,29 @@ var doc = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/UINOverride"
+                            "$ref": "#/definitions/ARule"
+                        }
+                    },
+                    "403": {
+                        "description": "The county is out of the admin counties",
+                        "schema": {
+                            "type": "string"
                         }
                     }
                 }
             }
         },
-        "/covid19/ext/uin-overrides/uin/{uin}": {
+        "/admin/rules/{id}": {
             "put": {
                 "security": [
                     {
-                        "ProvidersAuth": []
+                        "AdminUserAuth": []
+                    },
+                    {
+                        "AdminGroupAuth": []
                     }
                 ],
-                "description": "Updates uin override. The date format of the expiration field is \"2021-12-09T08:09:49.259Z\"",
+                "description": "Updates a rule.",
                 "consumes": [
                     "application/json"
                 ],
@@ -4351,9 +4548,9 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Providers"
+                    "Admin"
                 ],
-                "operationId": "UpdateExtUINOverride",
+                "operationId": "UpdateRule",
                 "parameters": [
                     {
                         "description": "body data",
@@ -4361,13 +4558,13 @@ var doc = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/updateExtUINOverrideRequest"
+                            "$ref": "#/definitions/updateRuleRequest"
                         }
                     },
                     {
                         "type": "string",
-                        "description": "UIN",
-                        "name": "uin",
+                        "description": "ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
@@ -4375,6 +4572,12 @@ var doc = `{
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ARule"
+                        }
+                    },
+                    "403": {
+                        "description": "The county is out of the admin counties",
                         "schema": {
                             "type": "string"
                         }
@@ -4384,22 +4587,25 @@ var doc = `{
             "delete": {
                 "security": [
                     {
-                        "ProvidersAuth": []
+                        "AdminUserAuth": []
+                    },
+                    {
+                        "AdminGroupAuth": []
                     }
                 ],
-                "description": "Deletes an uin override",
+                "description": "Deletes a rule",
                 "consumes": [
                     "text/plain"
                 ],
                 "tags": [
-                    "Providers"
+                    "Admin"
                 ],
-                "operationId": "DeleteExtUINOverride",
+                "operationId": "deleteRule",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "UIN",
-                        "name": "uin",
+                        "description": "ID",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
@@ -4410,106 +4616,180 @@ var doc = `{
                         "schema": {
                             "type": "string"
                         }
+                    },
+                    "403": {
+                        "description": "The county is out of the admin counties",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 }
             }
         },
-        "/covid19/external/user": {
+        "/admin/symptom-groups": {
             "get": {
                 "security": [
                     {
-                        "ExternalAuth": []
+                        "AdminUserAuth": []
+                    },
+                    {
+                        "AdminGroupAuth": []
                     }
                 ],
-                "description": "Gets an user by identifier",
+                "description": "Gives the symptom groups",
+                "consumes": [
+                    "application/json"
+                ],
                 "tags": [
-                    "External"
-                ],
-                "operationId": "GetUserByIdentifier",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Identifier",
-                        "name": "identifier",
-                        "in": "query",
-                        "required": true
-                    }
+                    "Admin"
                 ],
+                "operationId": "getSymptomGroups",
+                "deprecated": true,
                 "responses": {
-                    "200": {}
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/SymptomGroup"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Authentication error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
                 }
             }
         },
-        "/covid19/faq": {
+        "/admin/symptom-rules": {
             "get": {
                 "security": [
                     {
-                        "RokwireAuth": []
+                        "AdminUserAuth": []
+                    },
+                    {
+                        "AdminGroupAuth": []
                     }
                 ],
-                "description": "Gives the covid19 FAQs. The sections are sorted by the display order field. The questions within a section are sorted by the display order field.",
+                "description": "Gives the symptom rules",
                 "consumes": [
                     "application/json"
                 ],
                 "tags": [
-                    "Covid19"
+                    "Admin"
                 ],
-                "operationId": "getFAQ",
+                "operationId": "getSymptomRules",
+                "deprecated": true,
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/FAQ"
+                                "$ref": "#/definitions/SymptomRule"
                             }
                         }
+                    },
+                    "400": {
+                        "description": "Authentication error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "The county is out of the admin counties",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server error",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 }
-            }
-        },
-        "/covid19/join-external-approvements": {
-            "get": {
+            },
+            "post": {
                 "security": [
                     {
-                        "AppUserAuth": []
+                        "AdminUserAuth": []
                     },
                     {
-                        "AppUserAccountAuth": []
+                        "AdminGroupAuth": []
                     }
                 ],
-                "description": "Gives the join groups external approvals for approving",
+                "description": "Creates a symptom rule.",
                 "consumes": [
                     "application/json"
                 ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Covid19"
+                    "Admin"
+                ],
+                "operationId": "CreateSymptomRule",
+                "deprecated": true,
+                "parameters": [
+                    {
+                        "description": "body data",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/createSymptomRuleRequest"
+                        }
+                    }
                 ],
-                "operationId": "GetExtJoinExternalApproval",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/JoinGroupExtApprovement"
-                            }
+                            "$ref": "#/definitions/SymptomRule"
+                        }
+                    },
+                    "403": {
+                        "description": "The county is out of the admin counties",
+                        "schema": {
+                            "type": "string"
                         }
                     }
                 }
             }
         },
-        "/covid19/join-external-approvements/{id}": {
+        "/admin/symptom-rules/{id}": {
             "put": {
                 "security": [
                     {
-                        "AppUserAuth": []
+                        "AdminUserAuth": []
                     },
                     {
-                        "AppUserAccountAuth": []
+                        "AdminGroupAuth": []
                     }
                 ],
-                "description": "Accept/Reject external group joining request",
+                "description": "Updates a symptom rule.",
                 "consumes": [
                     "application/json"
                 ],
@@ -4517,9 +4797,10 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Covid19"
+                    "Admin"
                 ],
-                "operationId": "UpdateExtJoinExternalApproval",
+                "operationId": "UpdateSymptomRule",
+                "deprecated": true,
                 "parameters": [
                     {
                         "description": "body data",
@@ -4527,7 +4808,7 @@ var doc = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/updateExtJoinExternalApprovementRequest"
+                            "$ref": "#/definitions/updateSymptomRuleRequest"
                         }
                     },
                     {
@@ -4540,98 +4821,107 @@ var doc = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully processed",
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/SymptomRule"
+                        }
+                    },
+                    "403": {
+                        "description": "The county is out of the admin counties",
                         "schema": {
                             "type": "string"
                         }
                     }
                 }
-            }
-        },
-        "/covid19/locations": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
-                        "RokwireAuth": []
+                        "AdminUserAuth": []
+                    },
+                    {
+                        "AdminGroupAuth": []
                     }
                 ],
-                "description": "Gets locations for county and provider - pass county-id and provider-id params. Get locations for county - pass county-id param.",
+                "description": "Deletes a symptom rule.",
                 "consumes": [
-                    "application/json"
+                    "text/plain"
                 ],
                 "tags": [
-                    "Covid19"
+                    "Admin"
                 ],
-                "operationId": "GetLocations",
+                "operationId": "deleteSymptomRule",
+                "deprecated": true,
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "County ID",
-                        "name": "county-id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Provider ID",
-                        "name": "provider-id",
-                        "in": "query"
+                        "description": "ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Successfuly deleted",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/Location"
-                            }
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "The county is out of the admin counties",
+                        "schema": {
+                            "type": "string"
                         }
                     }
                 }
             }
         },
-        "/covid19/locations/{id}": {
+        "/admin/symptoms": {
             "get": {
                 "security": [
                     {
-                        "RokwireAuth": []
+                        "AdminUserAuth": []
+                    },
+                    {
+                        "AdminGroupAuth": []
                     }
                 ],
-                "description": "Gets a location",
+                "description": "Gives the symptoms",
                 "consumes": [
                     "application/json"
                 ],
                 "tags": [
-                    "Covid19"
+                    "Admin"
                 ],
-                "operationId": "getLocation",
+                "operationId": "GetASymptoms",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "App version",
+                        "name": "app-version",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/Location"
+                            "type": "string"
                         }
                     }
                 }
-            }
-        },
-        "/covid19/login": {
-            "post": {
+            },
+            "put": {
                 "security": [
                     {
-                        "AppUserAuth": []
+                        "AdminUserAuth": []
+                    },
+                    {
+                        "AdminGroupAuth": []
                     }
                 ],
-                "description": "Creates a user, updates it if already created",
+                "description": "Creates symptoms or update them if already created.",
                 "consumes": [
                     "application/json"
                 ],
@@ -4639,9 +4929,9 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Covid19"
+                    "Admin"
                 ],
-                "operationId": "loginUser",
+                "operationId": "CreateorUpdateSymptoms",
                 "parameters": [
                     {
                         "description": "body data",
@@ -4649,113 +4939,132 @@ var doc = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/loginUserRequest"
+                            "$ref": "#/definitions/createOrUpdateSymptomsRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully created or Successfully updated",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "400": {
-                        "description": "Authentication error",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server error",
+                        "description": "OK",
                         "schema": {
                             "type": "string"
                         }
                     }
                 }
-            }
-        },
-        "/covid19/news": {
-            "get": {
+            },
+            "post": {
                 "security": [
                     {
-                        "RokwireAuth": []
+                        "AdminUserAuth": []
+                    },
+                    {
+                        "AdminGroupAuth": []
                     }
                 ],
-                "description": "Gives the covid19 news.",
+                "description": "Creates a symptom",
                 "consumes": [
                     "application/json"
                 ],
-                "tags": [
-                    "Covid19"
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "operationId": "CreateSymptom",
+                "deprecated": true,
+                "parameters": [
+                    {
+                        "description": "body data",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/createSymptomRequest"
+                        }
+                    }
                 ],
-                "operationId": "GetNews",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/News"
-                            }
+                            "$ref": "#/definitions/Symptom"
                         }
                     }
                 }
             }
         },
-        "/covid19/providers": {
-            "get": {
+        "/admin/symptoms/{id}": {
+            "put": {
                 "security": [
                     {
-                        "RokwireAuth": []
+                        "AdminUserAuth": []
+                    },
+                    {
+                        "AdminGroupAuth": []
                     }
                 ],
-                "description": "Gives all the providers",
+                "description": "Updates a symptom.",
                 "consumes": [
                     "application/json"
                 ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Covid19"
+                    "Admin"
+                ],
+                "operationId": "UpdateSymptom",
+                "deprecated": true,
+                "parameters": [
+                    {
+                        "description": "body data",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/updateSymptomRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "operationId": "GetProviders",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/Provider"
-                            }
+                            "$ref": "#/definitions/Symptom"
                         }
                     }
                 }
-            }
-        },
-        "/covid19/providers/county/{id}": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
-                        "RokwireAuth": []
+                        "AdminUserAuth": []
+                    },
+                    {
+                        "AdminGroupAuth": []
                     }
                 ],
-                "description": "Gives the providers which have locations in a specific county",
+                "description": "Deletes a symptom",
                 "consumes": [
-                    "application/json"
+                    "text/plain"
                 ],
                 "tags": [
-                    "Covid19"
+                    "Admin"
                 ],
-                "operationId": "getProvidersByCounty",
+                "operationId": "deleteSymptom",
+                "deprecated": true,
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "County ID",
+                        "description": "ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -4763,98 +5072,146 @@ var doc = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Successfuly deleted",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/Provider"
-                            }
+                            "type": "string"
                         }
                     }
                 }
             }
         },
-        "/covid19/resources": {
+        "/admin/test-type-results": {
             "get": {
                 "security": [
                     {
-                        "RokwireAuth": []
+                        "AdminUserAuth": []
+                    },
+                    {
+                        "AdminGroupAuth": []
                     }
                 ],
-                "description": "Gives the covid19 resources",
+                "description": "Gets all test type results for a test type.",
                 "consumes": [
                     "application/json"
                 ],
                 "tags": [
-                    "Covid19"
+                    "Admin"
+                ],
+                "operationId": "getTestTypeResultsByTestTypeID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Test Type ID",
+                        "name": "test-type-id",
+                        "in": "query",
+                        "required": true
+                    }
                 ],
-                "operationId": "GetResources",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/Resource"
+                                "$ref": "#/definitions/TestTypeResult"
                             }
                         }
+                    },
+                    "400": {
+                        "description": "Authentication error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server error",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 }
-            }
-        },
-        "/covid19/rosters/phone/{phone}": {
-            "get": {
+            },
+            "post": {
                 "security": [
                     {
-                        "RokwireAuth": []
+                        "AdminUserAuth": []
+                    },
+                    {
+                        "AdminGroupAuth": []
                     }
                 ],
-                "description": "Gives uin of the roster member with a given phone number.",
+                "description": "Creates a test type result for a specific test type.",
                 "consumes": [
                     "application/json"
                 ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Covid19"
+                    "Admin"
                 ],
-                "operationId": "GetRosterIDByPhone",
+                "operationId": "CreateTestTypeResult",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Phone",
-                        "name": "phone",
-                        "in": "path",
-                        "required": true
+                        "description": "body data",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/createTestTypeResultRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/getRosterByPhoneResponse"
+                            "$ref": "#/definitions/TestTypeResult"
                         }
                     }
                 }
             }
         },
-        "/covid19/rules/county/{id}": {
-            "get": {
+        "/admin/test-type-results/{id}": {
+            "put": {
                 "security": [
                     {
-                        "RokwireAuth": []
+                        "AdminUserAuth": []
+                    },
+                    {
+                        "AdminGroupAuth": []
                     }
                 ],
-                "description": "Gets the rules for a county",
+                "description": "Updates test type result.",
                 "consumes": [
                     "application/json"
                 ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Covid19"
+                    "Admin"
                 ],
-                "operationId": "GetRulesByCounty",
+                "operationId": "UpdateTestTypeResult",
                 "parameters": [
+                    {
+                        "description": "body data",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/updateTestTypeResultRequest"
+                        }
+                    },
                     {
                         "type": "string",
-                        "description": "County ID",
+                        "description": "ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -4864,194 +5221,319 @@ var doc = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/Rule"
-                            }
+                            "$ref": "#/definitions/TestTypeResult"
                         }
                     }
                 }
-            }
-        },
-        "/covid19/symptom-groups": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
-                        "RokwireAuth": []
+                        "AdminUserAuth": []
+                    },
+                    {
+                        "AdminGroupAuth": []
                     }
                 ],
-                "description": "Gives the symptom groups",
+                "description": "Deletes a test type result",
                 "consumes": [
-                    "application/json"
+                    "text/plain"
                 ],
                 "tags": [
-                    "Covid19"
+                    "Admin"
+                ],
+                "operationId": "deleteTestTypeResult",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "operationId": "GetSymptomGroups",
-                "deprecated": true,
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Successfuly deleted",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/SymptomGroup"
-                            }
+                            "type": "string"
                         }
                     }
                 }
             }
         },
-        "/covid19/symptom-rules/county/{id}": {
+        "/admin/test-types": {
             "get": {
                 "security": [
                     {
-                        "RokwireAuth": []
+                        "AdminUserAuth": []
+                    },
+                    {
+                        "AdminGroupAuth": []
                     }
                 ],
-                "description": "Gives the symptom rule for a county.",
+                "description": "Gives the test types",
                 "consumes": [
                     "application/json"
                 ],
                 "tags": [
-                    "Covid19"
-                ],
-                "operationId": "getSymptomRuleByCounty",
-                "deprecated": true,
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "County ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "Admin"
                 ],
+                "operationId": "getTestTypes",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/SymptomRule"
+                                "$ref": "#/definitions/ATestType"
                             }
                         }
+                    },
+                    "400": {
+                        "description": "Authentication error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server error",
+                        "schema": {
+                            "type": "string"
+                        }
                     }
                 }
-            }
-        },
-        "/covid19/symptoms": {
-            "get": {
+            },
+            "post": {
                 "security": [
                     {
-                        "RokwireAuth": []
+                        "AdminUserAuth": []
+                    },
+                    {
+                        "AdminGroupAuth": []
                     }
                 ],
-                "description": "Gives the symptoms",
+                "description": "Creates a test type.",
                 "consumes": [
                     "application/json"
                 ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Covid19"
+                    "Admin"
+                ],
+                "operationId": "createTestType",
+                "parameters": [
+                    {
+                        "description": "body data",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/createTestTypeRequest"
+                        }
+                    }
                 ],
-                "operationId": "GetSymptoms",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/ATestType"
                         }
                     }
                 }
             }
         },
-        "/covid19/test-types": {
-            "get": {
+        "/admin/test-types/{id}": {
+            "put": {
                 "security": [
                     {
-                        "RokwireAuth": []
+                        "AdminUserAuth": []
+                    },
+                    {
+                        "AdminGroupAuth": []
                     }
                 ],
-                "description": "Gets test types. You can filter by id. The ids have to be comma separated.",
+                "description": "Updates a test type.",
                 "consumes": [
                     "application/json"
                 ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Covid19"
+                    "Admin"
                 ],
-                "operationId": "GetTestTypes",
+                "operationId": "UpdateTestType",
                 "parameters": [
+                    {
+                        "description": "body data",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/updateTestTypeRequest"
+                        }
+                    },
                     {
                         "type": "string",
-                        "description": "Test Type IDs",
-                        "name": "ids",
-                        "in": "query"
+                        "description": "ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/TestType"
-                            }
+                            "$ref": "#/definitions/ATestType"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "AdminUserAuth": []
+                    },
+                    {
+                        "AdminGroupAuth": []
+                    }
+                ],
+                "description": "Deletes a test type",
+                "consumes": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "operationId": "deleteTestType",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfuly deleted",
+                        "schema": {
+                            "type": "string"
                         }
                     }
                 }
             }
         },
-        "/covid19/time": {
+        "/admin/token-metrics": {
             "get": {
                 "security": [
                     {
-                        "RokwireAuth": []
+                        "AdminUserAuth": []
+                    },
+                    {
+                        "AdminGroupAuth": []
                     }
                 ],
-                "description": "Gives the current time in UTC.",
+                "description": "Gives the usage of the user token types, the app versions they are enabled for and the deprecated token types sunset date.",
                 "consumes": [
                     "application/json"
                 ],
                 "tags": [
-                    "Covid19"
+                    "Admin"
                 ],
-                "operationId": "GetTime",
+                "operationId": "GetTokenMetrics",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/Time"
+                            "$ref": "#/definitions/tokenMetrics"
                         }
                     }
                 }
             }
         },
-        "/covid19/trace/exposures": {
+        "/admin/uin-overrides": {
             "get": {
                 "security": [
                     {
-                        "RokwireAuth": []
+                        "AdminUserAuth": []
+                    },
+                    {
+                        "AdminGroupAuth": []
                     }
                 ],
-                "description": "Gives the exposures records. \"timestamp\" and \"date-added\" params are optional. It is the time in milliseconds.",
+                "description": "Gives uin override items\nThe items can be filtered by the listed fields as field=value or field=operator:value where the operator is eq, ne, in, range, prefix or exists.\nThe items are given in pages if \"limit\" or \"cursor\" is provided. The next page cursor is given in the ROKWIRE-CONTINUATION-TOKEN header.",
                 "consumes": [
                     "application/json"
                 ],
                 "tags": [
-                    "Covid19"
+                    "Admin"
                 ],
-                "operationId": "GetExposures",
+                "operationId": "GetUINOverrides",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "timestamp",
-                        "name": "timestamp",
+                        "type": "string",
+                        "description": "UIN",
+                        "name": "uin",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "date-added",
-                        "name": "date-added",
+                        "description": "Exempt",
+                        "name": "exempt",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Interval",
+                        "name": "interval",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Category",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Activation",
+                        "name": "activation",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Expiration",
+                        "name": "expiration",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields - field1,-field2",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor",
+                        "name": "cursor",
                         "in": "query"
                     }
                 ],
@@ -5061,31 +5543,32 @@ var doc = `{
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/TraceExposure"
+                                "$ref": "#/definitions/UINOverride"
                             }
                         }
                     }
                 }
-            }
-        },
-        "/covid19/trace/report": {
+            },
             "post": {
                 "security": [
                     {
-                        "RokwireAuth": []
+                        "AdminUserAuth": []
+                    },
+                    {
+                        "AdminGroupAuth": []
                     }
                 ],
-                "description": "Adds contact tracing report. \"timestamp\" - Unix time, the number of milliseconds elapsed since January 1, 1970 UTC",
+                "description": "Creates an uin override.",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "text/plain"
+                    "application/json"
                 ],
                 "tags": [
-                    "Covid19"
+                    "Admin"
                 ],
-                "operationId": "AddTraceReport",
+                "operationId": "CreateUINOverride",
                 "parameters": [
                     {
                         "description": "body data",
@@ -5093,41 +5576,56 @@ var doc = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/addTraceReportRequest"
+                            "$ref": "#/definitions/createUINOverrideRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully added [n] items",
+                        "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/UINOverride"
                         }
                     }
                 }
             }
         },
-        "/covid19/track/items": {
-            "get": {
+        "/admin/uin-overrides/uin/{uin}": {
+            "put": {
                 "security": [
                     {
-                        "ProvidersAuth": []
+                        "AdminUserAuth": []
+                    },
+                    {
+                        "AdminGroupAuth": []
                     }
                 ],
-                "description": "Gives the items list for the provided UINs. The list must be comma separated. The response looks like {\"”777778\":[{\"order_number\":null,\"date_created\":\"2020-08-12T05:52:47.467Z”},…],”777777\":[{\"order_number\":\"9\",\"date_created\":\"2020-09-10T05:02:14.716Z\"}]}",
+                "description": "Updates uin override.",
                 "consumes": [
                     "application/json"
                 ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Providers"
+                    "Admin"
                 ],
-                "operationId": "GetItemsListsByUINs",
+                "operationId": "UpdateUINOverride",
                 "parameters": [
+                    {
+                        "description": "body data",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/updateUINOverrideRequest"
+                        }
+                    },
                     {
                         "type": "string",
-                        "description": "Comma separated - uin1,uin2",
-                        "name": "uins",
-                        "in": "query",
+                        "description": "UIN",
+                        "name": "uin",
+                        "in": "path",
                         "required": true
                     }
                 ],
@@ -5135,154 +5633,193 @@ var doc = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/ilbuResponse"
+                            "type": "string"
                         }
                     }
                 }
-            }
-        },
-        "/covid19/track/uins": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
-                        "ProvidersAuth": []
+                        "AdminUserAuth": []
+                    },
+                    {
+                        "AdminGroupAuth": []
                     }
                 ],
-                "description": "Gives the corresponding UINs for the provided order numbers list. The list must be comma separated. The response looks like {\"ordernumber1\":\"uin 1\",\"ordernumber2\":\"uin 2\"}",
+                "description": "Deletes an uin override",
                 "consumes": [
-                    "application/json"
+                    "text/plain"
                 ],
                 "tags": [
-                    "Providers"
+                    "Admin"
                 ],
-                "operationId": "GetUINsByOrderNumbers",
+                "operationId": "DeleteUINOverride",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Comma separated - ordernumber1,ordernumber2",
-                        "name": "order-numbers",
-                        "in": "query",
+                        "description": "UIN",
+                        "name": "uin",
+                        "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Successfuly deleted",
                         "schema": {
-                            "$ref": "#/definitions/gubonResponse"
+                            "type": "string"
                         }
                     }
                 }
             }
         },
-        "/covid19/uin-override": {
+        "/admin/user": {
             "get": {
                 "security": [
                     {
-                        "AppUserAuth": []
+                        "AdminUserAuth": []
                     },
                     {
-                        "AppUserAccountAuth": []
+                        "AdminGroupAuth": []
                     }
                 ],
-                "description": "Gives the uin override for the user",
+                "description": "Gets the user by external id.",
                 "consumes": [
                     "application/json"
                 ],
                 "tags": [
-                    "Covid19"
+                    "Admin"
+                ],
+                "operationId": "GetUserByExternalID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "External ID",
+                        "name": "external-id",
+                        "in": "query",
+                        "required": true
+                    }
                 ],
-                "operationId": "GetUINOverride",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/UINOverride"
+                            "$ref": "#/definitions/User"
+                        }
+                    },
+                    "400": {
+                        "description": "Authentication error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server error",
+                        "schema": {
+                            "type": "string"
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/admin/users-cache-metrics": {
+            "get": {
                 "security": [
                     {
-                        "AppUserAuth": []
+                        "AdminUserAuth": []
                     },
                     {
-                        "AppUserAccountAuth": []
+                        "AdminGroupAuth": []
                     }
                 ],
-                "description": "Creates an uin override or updates it if already created",
-                "produces": [
+                "description": "Gives the users cache size, limits and hits, misses and invalidations since the service start.",
+                "consumes": [
                     "application/json"
                 ],
                 "tags": [
-                    "Covid19"
-                ],
-                "operationId": "CreateOrUpdateUINOverride",
-                "parameters": [
-                    {
-                        "description": "body data",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/createOrUpdateUINOverride"
-                        }
-                    }
+                    "Admin"
                 ],
+                "operationId": "GetUsersCacheMetrics",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/UsersCacheMetrics"
                         }
                     }
                 }
             }
         },
-        "/covid19/user": {
+        "/covid19/access-rules/county/{id}": {
             "get": {
                 "security": [
                     {
-                        "AppUserAuth": []
+                        "RokwireAuth": []
                     }
                 ],
-                "description": "Gives the current user.",
+                "description": "Gives the access rule for a county.",
                 "consumes": [
                     "application/json"
                 ],
                 "tags": [
                     "Covid19"
                 ],
-                "operationId": "getUser",
+                "operationId": "GetAccessRuleByCounty",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "County ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "TODO",
                         "schema": {
-                            "$ref": "#/definitions/User"
+                            "type": "string"
                         }
                     }
                 }
             }
         },
-        "/covid19/user/clear": {
-            "get": {
+        "/covid19/building-access": {
+            "put": {
                 "security": [
                     {
                         "AppUserAuth": []
+                    },
+                    {
+                        "AppUserAccountAuth": []
                     }
                 ],
-                "description": "Clears everything for that user as if the user has never seen the service before.",
-                "consumes": [
-                    "text/plain"
-                ],
+                "description": "grant/deny building access",
                 "tags": [
                     "Covid19"
                 ],
-                "operationId": "clearUserData",
+                "operationId": "SetUINBuildingAccess",
+                "parameters": [
+                    {
+                        "description": "body data",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/setBuildingAccessRequest"
+                        }
+                    }
+                ],
                 "responses": {
                     "200": {
-                        "description": "Successfully cleared",
+                        "description": "OK",
                         "schema": {
                             "type": "string"
                         }
@@ -5290,53 +5827,73 @@ var doc = `{
                 }
             }
         },
-        "/covid19/users/re-post": {
+        "/covid19/counties": {
             "get": {
                 "security": [
                     {
-                        "ProvidersAuth": []
+                        "RokwireAuth": []
                     }
                 ],
-                "description": "Gives the users for re-posting the test results",
+                "description": "Gives the counties. Optionally you can filter the results by one or many of the following three fields - name, state_province and country",
                 "consumes": [
                     "application/json"
                 ],
                 "tags": [
-                    "Providers"
+                    "Covid19"
+                ],
+                "operationId": "GetCounties",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "name",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "State province",
+                        "name": "state_province",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Country",
+                        "name": "country",
+                        "in": "query"
+                    }
                 ],
-                "operationId": "GetUsersForRePost",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/PUser"
+                                "$ref": "#/definitions/County"
                             }
                         }
                     }
                 }
             }
         },
-        "/covid19/users/uin/{id}": {
+        "/covid19/counties/{id}": {
             "get": {
                 "security": [
                     {
-                        "ProvidersAuth": []
+                        "RokwireAuth": []
                     }
                 ],
-                "description": "Gives the user info needed for the providers",
+                "description": "Gets a county",
                 "consumes": [
                     "application/json"
                 ],
                 "tags": [
-                    "Providers"
+                    "Covid19"
                 ],
-                "operationId": "getUserByShibbolethUIN",
+                "operationId": "getCounty",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
+                        "description": "ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -5346,54 +5903,48 @@ var doc = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/GetUserByShibbolethUINResponse"
+                            "$ref": "#/definitions/County"
                         }
                     }
                 }
             }
         },
-        "/covid19/v2/app-version/{app-version}/statuses": {
+        "/covid19/crules/county/{id}": {
             "get": {
                 "security": [
                     {
-                        "AppUserAuth": []
-                    },
-                    {
-                        "AppUserAccountAuth": []
+                        "RokwireAuth": []
                     }
                 ],
-                "description": "Gets the status for the current user for a specific app version",
+                "description": "Gives the rules for a county.",
                 "consumes": [
                     "application/json"
                 ],
                 "tags": [
                     "Covid19"
                 ],
-                "operationId": "GetStatusV2",
+                "operationId": "GetCRulesByCounty",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "App version",
-                        "name": "app-version",
-                        "in": "path"
+                        "description": "County ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/Status"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
                         "schema": {
                             "type": "string"
                         }
                     }
                 }
-            },
-            "put": {
+            }
+        },
+        "/covid19/ctests": {
+            "get": {
                 "security": [
                     {
                         "AppUserAuth": []
@@ -5402,80 +5953,78 @@ var doc = `{
                         "AppUserAccountAuth": []
                     }
                 ],
-                "description": "Updates the status for the user for a specific app version. it creates it if not already created.",
+                "description": "Gets not processed ctests for a user.",
                 "consumes": [
                     "application/json"
                 ],
-                "produces": [
-                    "application/json"
-                ],
                 "tags": [
                     "Covid19"
                 ],
-                "operationId": "CreateOrUpdateStatusV2",
+                "operationId": "getCTests",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "App version",
-                        "name": "app-version",
-                        "in": "path"
-                    },
-                    {
-                        "description": "body data",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/createOrUpdateStatusRequest"
-                        }
+                        "type": "boolean",
+                        "description": "select false value",
+                        "name": "processed",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/Status"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.CTest"
+                            }
                         }
                     }
                 }
             },
-            "delete": {
+            "post": {
                 "security": [
                     {
-                        "AppUserAuth": []
-                    },
-                    {
-                        "AppUserAccountAuth": []
+                        "ProvidersAuth": []
                     }
                 ],
-                "description": "Deletes the status for the user for a specific app version.",
+                "description": "Creates CTest. The provider credentials create ctests only for their provider, the shared keys are accepted until their sunset.",
                 "consumes": [
-                    "text/plain"
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
                 ],
                 "tags": [
-                    "Covid19"
+                    "Providers"
                 ],
-                "operationId": "DeleteStatusV2",
+                "operationId": "createCTest",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "App version",
-                        "name": "app-version",
-                        "in": "path"
+                        "description": "body data",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/createCTestRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Successfully deleted",
+                        "description": "Successfully created",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "403": {
+                        "description": "The provider credential does not allow this provider",
                         "schema": {
                             "type": "string"
                         }
                     }
                 }
-            }
-        },
-        "/covid19/v2/histories": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "AppUserAuth": []
@@ -5484,27 +6033,26 @@ var doc = `{
                         "AppUserAccountAuth": []
                     }
                 ],
-                "description": "Gets all histories for the current user user",
+                "description": "Deletes all ctests for a user",
                 "consumes": [
-                    "application/json"
+                    "text/plain"
                 ],
                 "tags": [
                     "Covid19"
                 ],
-                "operationId": "getHistoriesV2",
+                "operationId": "deleteCTests",
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Successfuly deleted [n] items",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/model.EHistory"
-                            }
+                            "type": "string"
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/covid19/ctests/{id}": {
+            "put": {
                 "security": [
                     {
                         "AppUserAuth": []
@@ -5513,7 +6061,7 @@ var doc = `{
                         "AppUserAccountAuth": []
                     }
                 ],
-                "description": "\"date\", \"type\", \"encrypted_key\" and \"encrypted_blob\" are mandatory fields. When the type is \"unverified_manual_test\" then the client must pass also \"encrypted_image_key\", \"encrypted_image_blob\" and (\"location_id\" or \"county_id\").",
+                "description": "Mark ctest as processed.",
                 "consumes": [
                     "application/json"
                 ],
@@ -5523,7 +6071,7 @@ var doc = `{
                 "tags": [
                     "Covid19"
                 ],
-                "operationId": "createHistoryV2",
+                "operationId": "updateCTest",
                 "parameters": [
                     {
                         "description": "body data",
@@ -5531,139 +6079,148 @@ var doc = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/createHistoryRequest"
+                            "$ref": "#/definitions/updateCTestRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.EHistory"
+                            "$ref": "#/definitions/model.CTest"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/covid19/ext/building-access": {
+            "get": {
                 "security": [
                     {
-                        "AppUserAuth": []
-                    },
-                    {
-                        "AppUserAccountAuth": []
+                        "ProvidersAuth": []
                     }
                 ],
-                "description": "Deletes the history items for an user.",
+                "description": "Gives the building access for the provided UIN",
                 "consumes": [
-                    "text/plain"
+                    "application/json"
                 ],
                 "tags": [
-                    "Covid19"
+                    "Providers"
+                ],
+                "operationId": "GetExtBuildingAccess",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "UIN",
+                        "name": "uin",
+                        "in": "query",
+                        "required": true
+                    }
                 ],
-                "operationId": "deleteHistoriesV2",
                 "responses": {
                     "200": {
-                        "description": "Successfully deleted [n] items",
+                        "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/UINBuildingAccess"
                         }
                     }
                 }
             }
         },
-        "/covid19/v2/histories/{id}": {
-            "put": {
+        "/covid19/ext/uin-overrides": {
+            "get": {
                 "security": [
                     {
-                        "AppUserAuth": []
-                    },
-                    {
-                        "AppUserAccountAuth": []
+                        "ProvidersAuth": []
                     }
                 ],
-                "description": "\"date\", \"encrypted_key\" and \"encrypted_blob\" are optional. If a field is omitted then it will not be updated.",
+                "description": "Gives the UIN overrides elements. The list can be filtered by UIN and sorted by UIN or Category",
                 "consumes": [
                     "application/json"
                 ],
-                "produces": [
-                    "application/json"
-                ],
                 "tags": [
-                    "Covid19"
+                    "Providers"
                 ],
-                "operationId": "updateHistoryV2",
+                "operationId": "GetExtUINOverrides",
                 "parameters": [
                     {
-                        "description": "body data",
-                        "name": "data",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/updateHistoryRequest"
-                        }
+                        "type": "string",
+                        "description": "UIN",
+                        "name": "uin",
+                        "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Sort by uin or category",
+                        "name": "sort",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.EHistory"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/UINOverride"
+                            }
                         }
                     }
                 }
-            }
-        },
-        "/covid19/v2/statuses": {
-            "get": {
+            },
+            "post": {
                 "security": [
                     {
-                        "AppUserAuth": []
-                    },
-                    {
-                        "AppUserAccountAuth": []
+                        "ProvidersAuth": []
                     }
                 ],
-                "description": "Gets the status for the current user",
+                "description": "Creates an uin override. The date format of the expiration field is \"2021-12-09T08:09:49.259Z\"",
                 "consumes": [
                     "application/json"
                 ],
+                "produces": [
+                    "application/json"
+                ],
                 "tags": [
-                    "Covid19"
+                    "Providers"
+                ],
+                "operationId": "CreateExtUINOverrides",
+                "parameters": [
+                    {
+                        "description": "body data",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/createExtUINOverrideRequest"
+                        }
+                    }
                 ],
-                "operationId": "GetStatusV2Deprecated",
-                "deprecated": true,
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/Status"
-                        }
-                    },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/UINOverride"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/covid19/ext/uin-overrides/uin/{uin}": {
             "put": {
                 "security": [
                     {
-                        "AppUserAuth": []
-                    },
-                    {
-                        "AppUserAccountAuth": []
+                        "ProvidersAuth": []
                     }
                 ],
-                "description": "Updates the status for the user. it creates it if not already created.",
+                "description": "Updates uin override. The date format of the expiration field is \"2021-12-09T08:09:49.259Z\"",
                 "consumes": [
                     "application/json"
                 ],
@@ -5671,10 +6228,9 @@ var doc = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Covid19"
+                    "Providers"
                 ],
-                "operationId": "CreateOrUpdateStatusV2Deprecated",
-                "deprecated": true,
+                "operationId": "UpdateExtUINOverride",
                 "parameters": [
                     {
                         "description": "body data",
@@ -5682,15 +6238,22 @@ var doc = `{
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/createOrUpdateStatusRequest"
+                            "$ref": "#/definitions/updateExtUINOverrideRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "UIN",
+                        "name": "uin",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/Status"
+                            "type": "string"
                         }
                     }
                 }
@@ -5698,24 +6261,29 @@ var doc = `{
             "delete": {
                 "security": [
                     {
-                        "AppUserAuth": []
-                    },
-                    {
-                        "AppUserAccountAuth": []
+                        "ProvidersAuth": []
                     }
                 ],
-                "description": "Deletes the status for the user.",
+                "description": "Deletes an uin override",
                 "consumes": [
                     "text/plain"
                 ],
                 "tags": [
-                    "Covid19"
+                    "Providers"
+                ],
+                "operationId": "DeleteExtUINOverride",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "UIN",
+                        "name": "uin",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "operationId": "DeleteStatusV2Deprecated",
-                "deprecated": true,
                 "responses": {
                     "200": {
-                        "description": "Successfully deleted",
+                        "description": "Successfuly deleted",
                         "schema": {
                             "type": "string"
                         }
@@ -5723,515 +6291,3461 @@ var doc = `{
                 }
             }
         },
-        "/covid19/v2/uin-override": {
+        "/covid19/external/user": {
             "get": {
                 "security": [
                     {
-                        "AppUserAuth": []
-                    },
-                    {
-                        "AppUserAccountAuth": []
+                        "ExternalAuth": []
                     }
                 ],
-                "description": "Gives the uin override for the user",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Gets an user by identifier",
                 "tags": [
-                    "Covid19"
+                    "External"
+                ],
+                "operationId": "GetUserByIdentifier",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Identifier",
+                        "name": "identifier",
+                        "in": "query",
+                        "required": true
+                    }
                 ],
-                "operationId": "GetUINOverrideV2",
                 "responses": {
-                    "200": {
+                    "200": {}
+                }
+            }
+        },
+        "/covid19/faq": {
+            "get": {
+                "security": [
+                    {
+                        "RokwireAuth": []
+                    }
+                ],
+                "description": "Gives the covid19 FAQs. The sections are sorted by the display order field. The questions within a section are sorted by the display order field.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Covid19"
+                ],
+                "operationId": "getFAQ",
+                "responses": {
+                    "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/UINOverride"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/FAQ"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/covid19/join-external-approvements": {
+            "get": {
+                "security": [
+                    {
+                        "AppUserAuth": []
+                    },
+                    {
+                        "AppUserAccountAuth": []
+                    }
+                ],
+                "description": "Gives the join groups external approvals for approving",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Covid19"
+                ],
+                "operationId": "GetExtJoinExternalApproval",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/JoinGroupExtApprovement"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/covid19/join-external-approvements/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "AppUserAuth": []
+                    },
+                    {
+                        "AppUserAccountAuth": []
+                    }
+                ],
+                "description": "Accept/Reject external group joining request",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Covid19"
+                ],
+                "operationId": "UpdateExtJoinExternalApproval",
+                "parameters": [
+                    {
+                        "description": "body data",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/updateExtJoinExternalApprovementRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully processed",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/covid19/locations": {
+            "get": {
+                "security": [
+                    {
+                        "RokwireAuth": []
+                    }
+                ],
+                "description": "Gets locations for county and provider - pass county-id and provider-id params. Get locations for county - pass county-id param.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Covid19"
+                ],
+                "operationId": "GetLocations",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "County ID",
+                        "name": "county-id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Provider ID",
+                        "name": "provider-id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/Location"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/covid19/locations/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "RokwireAuth": []
+                    }
+                ],
+                "description": "Gets a location",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Covid19"
+                ],
+                "operationId": "getLocation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Location"
+                        }
+                    }
+                }
+            }
+        },
+        "/covid19/login": {
+            "post": {
+                "security": [
+                    {
+                        "AppUserAuth": []
+                    }
+                ],
+                "description": "Creates a user, updates it if already created",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Covid19"
+                ],
+                "operationId": "loginUser",
+                "parameters": [
+                    {
+                        "description": "body data",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/loginUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully created or Successfully updated",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Authentication error",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server error",
+                        "schema": {
+                            "type": "string"
                         }
                     }
                 }
             }
         },
-        "/version": {
-            "get": {
-                "description": "Gives the service version.",
-                "produces": [
-                    "text/plain"
-                ],
-                "operationId": "Version",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "string"
-                        }
+        "/covid19/news": {
+            "get": {
+                "security": [
+                    {
+                        "RokwireAuth": []
+                    }
+                ],
+                "description": "Gives the covid19 news.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Covid19"
+                ],
+                "operationId": "GetNews",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/News"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/covid19/providers": {
+            "get": {
+                "security": [
+                    {
+                        "RokwireAuth": []
+                    }
+                ],
+                "description": "Gives all the providers",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Covid19"
+                ],
+                "operationId": "GetProviders",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/Provider"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/covid19/providers/county/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "RokwireAuth": []
+                    }
+                ],
+                "description": "Gives the providers which have locations in a specific county",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Covid19"
+                ],
+                "operationId": "getProvidersByCounty",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "County ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/Provider"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/covid19/resources": {
+            "get": {
+                "security": [
+                    {
+                        "RokwireAuth": []
+                    }
+                ],
+                "description": "Gives the covid19 resources",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Covid19"
+                ],
+                "operationId": "GetResources",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/Resource"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/covid19/rosters/phone/{phone}": {
+            "get": {
+                "security": [
+                    {
+                        "RokwireAuth": []
+                    }
+                ],
+                "description": "Gives uin of the roster member with a given phone number.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Covid19"
+                ],
+                "operationId": "GetRosterIDByPhone",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Phone",
+                        "name": "phone",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/getRosterByPhoneResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/covid19/rules/county/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "RokwireAuth": []
+                    }
+                ],
+                "description": "Gets the rules for a county",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Covid19"
+                ],
+                "operationId": "GetRulesByCounty",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "County ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/Rule"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/covid19/symptom-groups": {
+            "get": {
+                "security": [
+                    {
+                        "RokwireAuth": []
+                    }
+                ],
+                "description": "Gives the symptom groups",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Covid19"
+                ],
+                "operationId": "GetSymptomGroups",
+                "deprecated": true,
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/SymptomGroup"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/covid19/symptom-rules/county/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "RokwireAuth": []
+                    }
+                ],
+                "description": "Gives the symptom rule for a county.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Covid19"
+                ],
+                "operationId": "getSymptomRuleByCounty",
+                "deprecated": true,
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "County ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/SymptomRule"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/covid19/symptoms": {
+            "get": {
+                "security": [
+                    {
+                        "RokwireAuth": []
+                    }
+                ],
+                "description": "Gives the symptoms",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Covid19"
+                ],
+                "operationId": "GetSymptoms",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/covid19/test-types": {
+            "get": {
+                "security": [
+                    {
+                        "RokwireAuth": []
+                    }
+                ],
+                "description": "Gets test types. You can filter by id. The ids have to be comma separated.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Covid19"
+                ],
+                "operationId": "GetTestTypes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Test Type IDs",
+                        "name": "ids",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/TestType"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/covid19/time": {
+            "get": {
+                "security": [
+                    {
+                        "RokwireAuth": []
+                    }
+                ],
+                "description": "Gives the current time in UTC.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Covid19"
+                ],
+                "operationId": "GetTime",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Time"
+                        }
+                    }
+                }
+            }
+        },
+        "/covid19/trace/export/index.txt": {
+            "get": {
+                "security": [
+                    {
+                        "RokwireAuth": []
+                    }
+                ],
+                "description": "Gives the exposure keys export files names, one per line. The files are available for the last 14 days and are given by the /covid19/trace/export/{name} API.",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "Covid19"
+                ],
+                "operationId": "GetExposureExportIndex",
+                "responses": {
+                    "200": {
+                        "description": "the files names",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/covid19/trace/export/{name}": {
+            "get": {
+                "security": [
+                    {
+                        "RokwireAuth": []
+                    }
+                ],
+                "description": "Gives a signed exposure keys export file in the GAEN format. The file names are given by the /covid19/trace/export/index.txt API.",
+                "produces": [
+                    "application/zip"
+                ],
+                "tags": [
+                    "Covid19"
+                ],
+                "operationId": "GetExposureExport",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "the file name - {start}-{end}-{batch}.zip",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "the export archive",
+                        "schema": {
+                            "type": "file"
+                        }
+                    }
+                }
+            }
+        },
+        "/covid19/trace/exposures": {
+            "get": {
+                "security": [
+                    {
+                        "RokwireAuth": []
+                    }
+                ],
+                "description": "Gives the exposures records. \"timestamp\" and \"date-added\" params are optional. It is the time in milliseconds. The expired exposures and the exposures older than the retention period are not given.\n\nThe records are given in pages sorted by the date added if \"limit\" or \"cursor\" is provided. The next page cursor is given in the ROKWIRE-CONTINUATION-TOKEN header.\nThe cursor of the last page gives only the exposures added after it, so the clients download only the new exposures incrementally. The pages have an ETag and If-None-Match is supported.\nThe pages give the exposures added more than 2 minutes ago, so that the cursor does not move after the exposures which are still being added. The cursor keeps the first page \"timestamp\", it cannot be used with \"timestamp\".\nThe \"binary\" format gives for every record - timestamp (int64), expirestamp (int64, 0 if not set), tek length (uint16) and tek bytes, big endian. The response is gzip encoded if the client accepts it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Covid19"
+                ],
+                "operationId": "GetExposures",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "timestamp",
+                        "name": "timestamp",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "date-added",
+                        "name": "date-added",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "page size - 1000 if not provided, 5000 max",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "the cursor given by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "json or binary - json if not provided, only for pages",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/TraceExposure"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/covid19/trace/publish": {
+            "post": {
+                "security": [
+                    {
+                        "RokwireAuth": []
+                    }
+                ],
+                "description": "Publishes exposure notification (GAEN) temporary exposure keys. The upload must be verified by a verification certificate issued for the keys - \"verificationPayload\" and \"hmacKey\". Each certificate can be used once, the keys which have been already published are skipped.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Covid19"
+                ],
+                "operationId": "PublishExposureKeys",
+                "parameters": [
+                    {
+                        "description": "body data",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/publishExposureKeysRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/publishExposureKeysResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/covid19/trace/report": {
+            "post": {
+                "security": [
+                    {
+                        "RokwireAuth": []
+                    }
+                ],
+                "description": "Adds contact tracing report. \"timestamp\" - Unix time, the number of milliseconds elapsed since January 1, 1970 UTC. The upload must be authorized by an upload token given by the /covid19/trace/verify API.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "Covid19"
+                ],
+                "operationId": "AddTraceReport",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "the upload token",
+                        "name": "ROKWIRE-UPLOAD-TOKEN",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "body data",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/addTraceReportRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully added [n] items",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/covid19/trace/verify": {
+            "post": {
+                "security": [
+                    {
+                        "AppUserAuth": []
+                    }
+                ],
+                "description": "Exchanges a one-time exposure verification code issued by the public health for an upload token. The token authorizes one /covid19/trace/report upload.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Covid19"
+                ],
+                "operationId": "VerifyExposureCode",
+                "parameters": [
+                    {
+                        "description": "body data",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/verifyExposureCodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/verifyExposureCodeResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/covid19/track/items": {
+            "get": {
+                "security": [
+                    {
+                        "ProvidersAuth": []
+                    }
+                ],
+                "description": "Gives the items list for the provided UINs. The list must be comma separated. The response looks like {\"”777778\":[{\"order_number\":null,\"date_created\":\"2020-08-12T05:52:47.467Z”},…],”777777\":[{\"order_number\":\"9\",\"date_created\":\"2020-09-10T05:02:14.716Z\"}]}",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Providers"
+                ],
+                "operationId": "GetItemsListsByUINs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comma separated - uin1,uin2",
+                        "name": "uins",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/ilbuResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/covid19/track/uins": {
+            "get": {
+                "security": [
+                    {
+                        "ProvidersAuth": []
+                    }
+                ],
+                "description": "Gives the corresponding UINs for the provided order numbers list. The list must be comma separated. The response looks like {\"ordernumber1\":\"uin 1\",\"ordernumber2\":\"uin 2\"}",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Providers"
+                ],
+                "operationId": "GetUINsByOrderNumbers",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comma separated - ordernumber1,ordernumber2",
+                        "name": "order-numbers",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/gubonResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/covid19/uin-override": {
+            "get": {
+                "security": [
+                    {
+                        "AppUserAuth": []
+                    },
+                    {
+                        "AppUserAccountAuth": []
+                    }
+                ],
+                "description": "Gives the uin override for the user",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Covid19"
+                ],
+                "operationId": "GetUINOverride",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/UINOverride"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "AppUserAuth": []
+                    },
+                    {
+                        "AppUserAccountAuth": []
+                    }
+                ],
+                "description": "Creates an uin override or updates it if already created",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Covid19"
+                ],
+                "operationId": "CreateOrUpdateUINOverride",
+                "parameters": [
+                    {
+                        "description": "body data",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/createOrUpdateUINOverride"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/covid19/user": {
+            "get": {
+                "security": [
+                    {
+                        "AppUserAuth": []
+                    }
+                ],
+                "description": "Gives the current user.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Covid19"
+                ],
+                "operationId": "getUser",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/User"
+                        }
+                    }
+                }
+            }
+        },
+        "/covid19/user/clear": {
+            "get": {
+                "security": [
+                    {
+                        "AppUserAuth": []
+                    }
+                ],
+                "description": "Clears everything for that user as if the user has never seen the service before.",
+                "consumes": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "Covid19"
+                ],
+                "operationId": "clearUserData",
+                "responses": {
+                    "200": {
+                        "description": "Successfully cleared",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/covid19/user/sub-accounts": {
+            "get": {
+                "security": [
+                    {
+                        "AppUserAuth": []
+                    }
+                ],
+                "description": "Gives the sub accounts which have been added for the user and wait to be accepted or declined.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Covid19"
+                ],
+                "operationId": "GetPendingSubAccounts",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/pendingSubAccountResponse"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/covid19/user/sub-accounts/{uin}/accept": {
+            "put": {
+                "security": [
+                    {
+                        "AppUserAuth": []
+                    }
+                ],
+                "description": "Accepts a pending sub account. It becomes an active account of the user.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Covid19"
+                ],
+                "operationId": "AcceptSubAccount",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "UIN",
+                        "name": "uin",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Account"
+                        }
+                    }
+                }
+            }
+        },
+        "/covid19/user/sub-accounts/{uin}/decline": {
+            "put": {
+                "security": [
+                    {
+                        "AppUserAuth": []
+                    }
+                ],
+                "description": "Declines a pending sub account.",
+                "consumes": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "Covid19"
+                ],
+                "operationId": "DeclineSubAccount",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "UIN",
+                        "name": "uin",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully declined",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/covid19/users/re-post": {
+            "get": {
+                "security": [
+                    {
+                        "ProvidersAuth": []
+                    }
+                ],
+                "description": "Gives the users for re-posting the test results",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Providers"
+                ],
+                "operationId": "GetUsersForRePost",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/PUser"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/covid19/users/uin/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "ProvidersAuth": []
+                    }
+                ],
+                "description": "Gives the user info needed for the providers",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Providers"
+                ],
+                "operationId": "getUserByShibbolethUIN",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/GetUserByShibbolethUINResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/covid19/v2/app-version/{app-version}/statuses": {
+            "get": {
+                "security": [
+                    {
+                        "AppUserAuth": []
+                    },
+                    {
+                        "AppUserAccountAuth": []
+                    }
+                ],
+                "description": "Gets the status for the current user for a specific app version",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Covid19"
+                ],
+                "operationId": "GetStatusV2",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "App version",
+                        "name": "app-version",
+                        "in": "path"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Status"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "AppUserAuth": []
+                    },
+                    {
+                        "AppUserAccountAuth": []
+                    }
+                ],
+                "description": "Updates the status for the user for a specific app version. it creates it if not already created.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Covid19"
+                ],
+                "operationId": "CreateOrUpdateStatusV2",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "App version",
+                        "name": "app-version",
+                        "in": "path"
+                    },
+                    {
+                        "description": "body data",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/createOrUpdateStatusRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Status"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "AppUserAuth": []
+                    },
+                    {
+                        "AppUserAccountAuth": []
+                    }
+                ],
+                "description": "Deletes the status for the user for a specific app version.",
+                "consumes": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "Covid19"
+                ],
+                "operationId": "DeleteStatusV2",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "App version",
+                        "name": "app-version",
+                        "in": "path"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Successfully deleted",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/covid19/v2/histories": {
+            "get": {
+                "security": [
+                    {
+                        "AppUserAuth": []
+                    },
+                    {
+                        "AppUserAccountAuth": []
+                    }
+                ],
+                "description": "Gets all histories for the current user user",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Covid19"
+                ],
+                "operationId": "getHistoriesV2",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.EHistory"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "AppUserAuth": []
+                    },
+                    {
+                        "AppUserAccountAuth": []
+                    }
+                ],
+                "description": "\"date\", \"type\", \"encrypted_key\" and \"encrypted_blob\" are mandatory fields. When the type is \"unverified_manual_test\" then the client must pass also \"encrypted_image_key\", \"encrypted_image_blob\" and (\"location_id\" or \"county_id\").",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Covid19"
+                ],
+                "operationId": "createHistoryV2",
+                "parameters": [
+                    {
+                        "description": "body data",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/createHistoryRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.EHistory"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "AppUserAuth": []
+                    },
+                    {
+                        "AppUserAccountAuth": []
+                    }
+                ],
+                "description": "Deletes the history items for an user.",
+                "consumes": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "Covid19"
+                ],
+                "operationId": "deleteHistoriesV2",
+                "responses": {
+                    "200": {
+                        "description": "Successfully deleted [n] items",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/covid19/v2/histories/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "AppUserAuth": []
+                    },
+                    {
+                        "AppUserAccountAuth": []
+                    }
+                ],
+                "description": "\"date\", \"encrypted_key\" and \"encrypted_blob\" are optional. If a field is omitted then it will not be updated.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Covid19"
+                ],
+                "operationId": "updateHistoryV2",
+                "parameters": [
+                    {
+                        "description": "body data",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/updateHistoryRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.EHistory"
+                        }
+                    }
+                }
+            }
+        },
+        "/covid19/v2/statuses": {
+            "get": {
+                "security": [
+                    {
+                        "AppUserAuth": []
+                    },
+                    {
+                        "AppUserAccountAuth": []
+                    }
+                ],
+                "description": "Gets the status for the current user",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Covid19"
+                ],
+                "operationId": "GetStatusV2Deprecated",
+                "deprecated": true,
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Status"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "AppUserAuth": []
+                    },
+                    {
+                        "AppUserAccountAuth": []
+                    }
+                ],
+                "description": "Updates the status for the user. it creates it if not already created.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Covid19"
+                ],
+                "operationId": "CreateOrUpdateStatusV2Deprecated",
+                "deprecated": true,
+                "parameters": [
+                    {
+                        "description": "body data",
+                        "name": "data",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/createOrUpdateStatusRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Status"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "AppUserAuth": []
+                    },
+                    {
+                        "AppUserAccountAuth": []
+                    }
+                ],
+                "description": "Deletes the status for the user.",
+                "consumes": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "Covid19"
+                ],
+                "operationId": "DeleteStatusV2Deprecated",
+                "deprecated": true,
+                "responses": {
+                    "200": {
+                        "description": "Successfully deleted",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/covid19/v2/uin-override": {
+            "get": {
+                "security": [
+                    {
+                        "AppUserAuth": []
+                    },
+                    {
+                        "AppUserAccountAuth": []
+                    }
+                ],
+                "description": "Gives the uin override for the user",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Covid19"
+                ],
+                "operationId": "GetUINOverrideV2",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/UINOverride"
+                        }
+                    }
+                }
+            }
+        },
+        "/version": {
+            "get": {
+                "description": "Gives the service version.",
+                "produces": [
+                    "text/plain"
+                ],
+                "operationId": "Version",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "ACounty": {
+            "type": "object",
+            "properties": {
+                "country": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "state_province": {
+                    "type": "string"
+                }
+            }
+        },
+        "APIKey": {
+            "type": "object",
+            "properties": {
+                "date_created": {
+                    "type": "string"
+                },
+                "date_expires": {
+                    "description": "set when the key is rotated or it is given for a period",
+                    "type": "string"
+                },
+                "date_last_used": {
+                    "type": "string"
+                },
+                "date_revoked": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "key_prefix": {
+                    "description": "the first key characters, so that the owner can recognize the key",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "owner": {
+                    "description": "who uses the key",
+                    "type": "string"
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "ARule": {
+            "type": "object",
+            "properties": {
+                "county_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "priority": {
+                    "type": "integer"
+                },
+                "results_statuses": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ATestTypeResultCountyStatus"
+                    }
+                },
+                "test_type_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "ATestType": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "priority": {
+                    "type": "integer"
+                }
+            }
+        },
+        "ATestTypeResultCountyStatus": {
+            "type": "object",
+            "properties": {
+                "county_status_id": {
+                    "type": "string"
+                },
+                "test_type_result_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "AccessRule": {
+            "type": "object",
+            "properties": {
+                "county_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "rules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/AccessRuleCountyStatus"
+                    }
+                }
+            }
+        },
+        "AccessRuleCountyStatus": {
+            "type": "object",
+            "properties": {
+                "county_status_id": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "Account": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "address1": {
+                    "type": "string"
+                },
+                "address2": {
+                    "type": "string"
+                },
+                "address3": {
+                    "type": "string"
+                },
+                "birth_date": {
+                    "type": "string"
+                },
+                "city": {
+                    "type": "string"
+                },
+                "default": {
+                    "type": "boolean"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "external_id": {
+                    "type": "string"
+                },
+                "first_name": {
+                    "type": "string"
+                },
+                "gender": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_name": {
+                    "type": "string"
+                },
+                "middle_name": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string"
+                },
+                "zip_code": {
+                    "type": "string"
+                }
+            }
+        },
+        "AuditChange": {
+            "type": "object",
+            "properties": {
+                "after": {
+                    "type": "string"
+                },
+                "before": {
+                    "type": "string"
+                },
+                "field": {
+                    "type": "string"
+                }
+            }
+        },
+        "AuditEntity": {
+            "type": "object",
+            "properties": {
+                "after": {
+                    "description": "nil for delete",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "before": {
+                    "description": "the previous values of the updated fields or the deleted entity data, nil if not known",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/AuditChange"
+                    }
+                },
+                "client_data": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "data": {
+                    "description": "the flattened data of the items logged before the snapshots were introduced",
+                    "type": "string"
+                },
+                "entity": {
+                    "type": "string"
+                },
+                "entity_id": {
+                    "type": "string"
+                },
+                "hash": {
+                    "type": "string"
+                },
+                "operation": {
+                    "type": "string"
+                },
+                "prev_hash": {
+                    "type": "string"
+                },
+                "sequence": {
+                    "description": "the items are chained by hash in the order of their sequence, the items logged before the chain do not have sequence",
+                    "type": "integer"
+                },
+                "used_group": {
+                    "type": "string"
+                },
+                "user_identifier": {
+                    "type": "string"
+                },
+                "user_info": {
+                    "type": "string"
+                }
+            }
+        },
+        "AuditMetrics": {
+            "type": "object",
+            "properties": {
+                "dropped": {
+                    "description": "the lost items",
+                    "type": "integer"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "last_error_at": {
+                    "type": "string"
+                },
+                "queue_depth": {
+                    "description": "the items waiting to be written",
+                    "type": "integer"
+                },
+                "queue_size": {
+                    "type": "integer"
+                },
+                "retries": {
+                    "type": "integer"
+                },
+                "spill_enabled": {
+                    "type": "boolean"
+                },
+                "spilled": {
+                    "description": "the items kept on disk while they cannot be written",
+                    "type": "integer"
+                },
+                "strict": {
+                    "type": "boolean"
+                },
+                "written": {
+                    "type": "integer"
+                }
+            }
+        },
+        "AuditSequenceRange": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "type": "integer"
+                },
+                "to": {
+                    "type": "integer"
+                }
+            }
+        },
+        "AuditVerification": {
+            "type": "object",
+            "properties": {
+                "checked": {
+                    "type": "integer"
+                },
+                "checkpoints": {
+                    "type": "integer"
+                },
+                "from_sequence": {
+                    "type": "integer"
+                },
+                "gaps": {
+                    "description": "the missing items",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/AuditSequenceRange"
+                    }
+                },
+                "invalid_checkpoints": {
+                    "description": "the checkpoints with wrong signature or not matching their item",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "modified": {
+                    "description": "the items which content does not match their hash",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "to_sequence": {
+                    "description": "the last checked item",
+                    "type": "integer"
+                },
+                "unlinked": {
+                    "description": "the items which previous hash does not match the previous item",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "unverified_checkpoints": {
+                    "description": "the checkpoints which signature cannot be checked as there is no signing key",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "valid": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "Broadcast": {
+            "type": "object",
+            "properties": {
+                "audience_count": {
+                    "type": "integer"
+                },
+                "body": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "date_claimed": {
+                    "description": "renewed by the instance while it is sending the broadcast",
+                    "type": "string"
+                },
+                "date_created": {
+                    "type": "string"
+                },
+                "date_sent": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "scheduled_at": {
+                    "type": "string"
+                },
+                "sent_count": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "target": {
+                    "type": "object",
+                    "$ref": "#/definitions/BroadcastTarget"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "BroadcastTarget": {
+            "type": "object",
+            "properties": {
+                "app_version": {
+                    "type": "string"
+                },
+                "county_id": {
+                    "description": "users with manual tests in the county",
+                    "type": "string"
+                },
+                "raw_sub_account_status": {
+                    "description": "pending - the primary accounts, created - the sub accounts",
+                    "type": "string"
+                },
+                "roster": {
+                    "description": "users in (true) or not in (false) the roster",
+                    "type": "boolean"
+                },
+                "uin_override_category": {
+                    "type": "string"
+                }
+            }
+        },
+        "ComplianceCounts": {
+            "type": "object",
+            "properties": {
+                "compliant": {
+                    "type": "integer"
+                },
+                "due_soon": {
+                    "type": "integer"
+                },
+                "exempt": {
+                    "type": "integer"
+                },
+                "overdue": {
+                    "type": "integer"
+                }
+            }
+        },
+        "ComplianceGroupCounts": {
+            "type": "object",
+            "properties": {
+                "counts": {
+                    "type": "object",
+                    "$ref": "#/definitions/ComplianceCounts"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "ComplianceItem": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string"
+                },
+                "county_id": {
+                    "type": "string"
+                },
+                "due_date": {
+                    "type": "string"
+                },
+                "first_name": {
+                    "type": "string"
+                },
+                "last_name": {
+                    "type": "string"
+                },
+                "latest_test_date": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "uin": {
+                    "type": "string"
+                }
+            }
+        },
+        "ComplianceSummary": {
+            "type": "object",
+            "properties": {
+                "by_category": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ComplianceGroupCounts"
+                    }
+                },
+                "by_county": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ComplianceGroupCounts"
+                    }
+                },
+                "generated_at": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "object",
+                    "$ref": "#/definitions/ComplianceCounts"
+                }
+            }
+        },
+        "County": {
+            "type": "object",
+            "properties": {
+                "country": {
+                    "type": "string"
+                },
+                "county_statuses": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/CountyStatus"
+                    }
+                },
+                "guidelines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/Guideline"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "state_province": {
+                    "type": "string"
+                }
+            }
+        },
+        "CountyStatus": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "ExposureMetrics": {
+            "type": "object",
+            "properties": {
+                "gaen_count": {
+                    "type": "integer"
+                },
+                "purged_count": {
+                    "description": "for the given purges",
+                    "type": "integer"
+                },
+                "purges": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/ExposurePurge"
+                    }
+                },
+                "retention_days": {
+                    "type": "integer"
+                },
+                "total_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "ExposurePurge": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "type": "string"
+                },
+                "expired_count": {
+                    "description": "deleted because of their expirestamp",
+                    "type": "integer"
+                },
+                "retention_count": {
+                    "description": "deleted because they are older than the retention period",
+                    "type": "integer"
+                }
+            }
+        },
+        "ExposureVerificationCode": {
+            "type": "object",
+            "properties": {
+                "date_claimed": {
+                    "type": "string"
+                },
+                "date_created": {
+                    "type": "string"
+                },
+                "date_used": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "issued_by": {
+                    "type": "string"
+                },
+                "report_type": {
+                    "type": "string"
+                },
+                "source": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "symptom_onset_date": {
+                    "type": "string"
+                },
+                "test_date": {
+                    "type": "string"
+                },
+                "token_expires_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "description": "set if the code can be claimed only by a specific user",
+                    "type": "string"
+                }
+            }
+        },
+        "FAQ": {
+            "type": "object",
+            "properties": {
+                "dateUpdated": {
+                    "type": "string"
+                },
+                "general": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/FAQGeneral"
+                    }
+                },
+                "sections": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/FAQSection"
+                    }
+                }
+            }
+        },
+        "FAQGeneral": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "link": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "FAQQuestion": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "display_order": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "link": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "FAQSection": {
+            "type": "object",
+            "properties": {
+                "display_order": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "questions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/FAQQuestion"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "GetUserByShibbolethUINResponse": {
+            "type": "object",
+            "properties": {
+                "consent": {
+                    "type": "boolean"
+                },
+                "consent_vaccine": {
+                    "type": "boolean"
+                },
+                "public_key": {
+                    "type": "string"
+                }
+            }
+        },
+        "Guideline": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/GuidelineItem"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "GuidelineItem": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "icon": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "JoinGroupExtApprovement": {
+            "type": "object",
+            "properties": {
+                "date_created": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "external_approver_id": {
+                    "type": "string"
+                },
+                "external_approver_last_name": {
+                    "type": "string"
+                },
+                "first_name": {
+                    "type": "string"
+                },
+                "group_name": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_name": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "Location": {
+            "type": "object",
+            "properties": {
+                "address_1": {
+                    "type": "string"
+                },
+                "address_2": {
+                    "type": "string"
+                },
+                "available_tests": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "city": {
+                    "type": "string"
+                },
+                "contact": {
+                    "type": "string"
+                },
+                "country": {
+                    "type": "string"
+                },
+                "county_id": {
+                    "type": "string"
+                },
+                "days_of_operation": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/OperationDay"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "latitude": {
+                    "type": "number"
+                },
+                "longitude": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "notes": {
+                    "type": "string"
+                },
+                "provider_id": {
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string"
+                },
+                "timezone": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "wait_time_color": {
+                    "type": "string"
+                },
+                "zip": {
+                    "type": "string"
+                }
+            }
+        },
+        "ManualTest": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "string"
+                },
+                "county_id": {
+                    "type": "string"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "date_created": {
+                    "type": "string"
+                },
+                "encrypted_blob": {
+                    "type": "string"
+                },
+                "encrypted_key": {
+                    "type": "string"
+                },
+                "history_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "location_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "user": {
+                    "type": "object",
+                    "$ref": "#/definitions/User"
+                }
+            }
+        },
+        "ManualTestImage": {
+            "type": "object",
+            "properties": {
+                "encrypted_image_blob": {
+                    "type": "string"
+                },
+                "encrypted_image_key": {
+                    "type": "string"
+                }
+            }
+        },
+        "News": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "htmlContent": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "link": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "NotificationTemplate": {
+            "type": "object",
+            "properties": {
+                "date_created": {
+                    "type": "string"
+                },
+                "date_updated": {
+                    "type": "string"
+                },
+                "event": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "variants": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/NotificationTemplateVariant"
+                    }
+                }
+            }
+        },
+        "NotificationTemplateVariant": {
+            "type": "object",
+            "required": [
+                "body",
+                "language",
+                "title"
+            ],
+            "properties": {
+                "body": {
+                    "type": "string"
+                },
+                "language": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "OperationDay": {
+            "type": "object",
+            "properties": {
+                "close_time": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "open_time": {
+                    "type": "string"
+                }
+            }
+        },
+        "PUser": {
+            "type": "object",
+            "properties": {
+                "consent": {
+                    "type": "boolean"
+                },
+                "consent_vaccine": {
+                    "type": "boolean"
+                },
+                "public_key": {
+                    "type": "string"
+                },
+                "uin": {
+                    "type": "string"
+                }
+            }
+        },
+        "Provider": {
+            "type": "object",
+            "properties": {
+                "available_mechanisms": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "manual_test": {
+                    "type": "boolean"
+                },
+                "provider_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "ProviderCredential": {
+            "type": "object",
+            "properties": {
+                "date_created": {
+                    "type": "string"
+                },
+                "date_expires": {
+                    "description": "set when the credential is rotated or it is given for a period",
+                    "type": "string"
+                },
+                "date_revoked": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "key_prefix": {
+                    "description": "the first key characters, so that the provider can recognize the key",
+                    "type": "string"
+                },
+                "provider_id": {
+                    "type": "string"
+                },
+                "rate_limit": {
+                    "description": "requests per minute, 0 for the default limit",
+                    "type": "integer"
+                },
+                "require_signature": {
+                    "description": "the requests must be signed with the signing secret",
+                    "type": "boolean"
+                },
+                "scopes": {
+                    "description": "the other providers APIs which the credential can use, the own ctests are always allowed",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "ACounty": {
+        },
+        "RawSubAccount": {
             "type": "object",
             "properties": {
-                "country": {
+                "account_id": {
                     "type": "string"
                 },
-                "id": {
+                "address1": {
                     "type": "string"
                 },
-                "name": {
+                "address2": {
                     "type": "string"
                 },
-                "state_province": {
+                "address3": {
+                    "type": "string"
+                },
+                "birth_date": {
+                    "type": "string"
+                },
+                "city": {
+                    "type": "string"
+                },
+                "date_link_updated": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "first_name": {
+                    "type": "string"
+                },
+                "gender": {
+                    "type": "string"
+                },
+                "last_name": {
+                    "type": "string"
+                },
+                "link_status": {
+                    "description": "pending, accepted, declined or revoked",
+                    "type": "string"
+                },
+                "middle_name": {
+                    "type": "string"
+                },
+                "net_id": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "primary_account": {
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string"
+                },
+                "uin": {
+                    "type": "string"
+                },
+                "zip_code": {
                     "type": "string"
                 }
             }
         },
-        "ARule": {
+        "Resource": {
             "type": "object",
             "properties": {
-                "county_id": {
+                "display_order": {
+                    "type": "integer"
+                },
+                "icon": {
                     "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
-                "priority": {
-                    "type": "integer"
+                "link": {
+                    "type": "string"
                 },
-                "results_statuses": {
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "Role": {
+            "type": "object",
+            "properties": {
+                "counties": {
+                    "description": "the counties ids to which the county bound permissions are limited, empty for all counties",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/ATestTypeResultCountyStatus"
+                        "type": "string"
                     }
                 },
-                "test_type_id": {
+                "date_created": {
+                    "type": "string"
+                },
+                "date_updated": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "groups": {
+                    "description": "the identity provider groups which members have the role",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
                     "type": "string"
+                },
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
-        "ATestType": {
+        "Roster": {
             "type": "object",
             "properties": {
-                "id": {
+                "address1": {
                     "type": "string"
                 },
-                "name": {
+                "address2": {
                     "type": "string"
                 },
-                "priority": {
-                    "type": "integer"
+                "address3": {
+                    "type": "string"
+                },
+                "attributes": {
+                    "description": "the extra attributes configured by the admins",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "badge_type": {
+                    "type": "string"
+                },
+                "birth_date": {
+                    "type": "string"
+                },
+                "city": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "first_name": {
+                    "type": "string"
+                },
+                "gender": {
+                    "type": "string"
+                },
+                "last_name": {
+                    "type": "string"
+                },
+                "middle_name": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string"
+                },
+                "uin": {
+                    "type": "string"
+                },
+                "zip_code": {
+                    "type": "string"
                 }
             }
         },
-        "ATestTypeResultCountyStatus": {
+        "RosterAttribute": {
             "type": "object",
             "properties": {
-                "county_status_id": {
+                "name": {
                     "type": "string"
                 },
-                "test_type_result_id": {
+                "pattern": {
+                    "description": "regular expression for the values, any value if empty",
                     "type": "string"
+                },
+                "required": {
+                    "type": "boolean"
                 }
             }
         },
-        "AccessRule": {
+        "RosterImport": {
             "type": "object",
             "properties": {
-                "county_id": {
+                "created": {
+                    "type": "integer"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "date_created": {
+                    "type": "string"
+                },
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "duplicates": {
+                    "type": "integer"
+                },
+                "errors": {
+                    "description": "the first errors only, so that the report is not too large",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/RosterImportError"
+                    }
+                },
+                "errors_count": {
+                    "description": "all the errors",
+                    "type": "integer"
+                },
+                "file_name": {
                     "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
-                "rules": {
+                "invalid": {
+                    "type": "integer"
+                },
+                "preview": {
+                    "description": "the first valid items in the dry run imports",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/AccessRuleCountyStatus"
+                        "$ref": "#/definitions/Roster"
                     }
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "valid": {
+                    "type": "integer"
                 }
             }
         },
-        "AccessRuleCountyStatus": {
+        "RosterImportError": {
             "type": "object",
             "properties": {
-                "county_status_id": {
+                "field": {
+                    "type": "string"
+                },
+                "message": {
                     "type": "string"
                 },
+                "row": {
+                    "description": "the row number in the file, the header is row 1",
+                    "type": "integer"
+                },
                 "value": {
                     "type": "string"
                 }
             }
         },
-        "Account": {
+        "RosterPhoneConflict": {
             "type": "object",
             "properties": {
-                "active": {
-                    "type": "boolean"
-                },
-                "address1": {
-                    "type": "string"
-                },
-                "address2": {
-                    "type": "string"
-                },
-                "address3": {
+                "held_by_uin": {
                     "type": "string"
                 },
-                "birth_date": {
+                "phone": {
                     "type": "string"
                 },
-                "city": {
+                "uin": {
                     "type": "string"
+                }
+            }
+        },
+        "RosterSync": {
+            "type": "object",
+            "properties": {
+                "added": {
+                    "type": "integer"
                 },
-                "default": {
-                    "type": "boolean"
+                "added_uins": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "email": {
-                    "type": "string"
+                "dry_run": {
+                    "type": "boolean"
                 },
-                "external_id": {
-                    "type": "string"
+                "phone_conflicts": {
+                    "description": "the phones taken from other roster items, the sync applies them together",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/RosterPhoneConflict"
+                    }
                 },
-                "first_name": {
-                    "type": "string"
+                "removed": {
+                    "type": "integer"
                 },
-                "gender": {
-                    "type": "string"
+                "removed_uins": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "id": {
-                    "type": "string"
+                "unchanged": {
+                    "type": "integer"
                 },
-                "last_name": {
-                    "type": "string"
+                "updated": {
+                    "type": "integer"
                 },
-                "middle_name": {
-                    "type": "string"
+                "updated_uins": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "Rule": {
+            "type": "object",
+            "properties": {
+                "priority": {
+                    "type": "integer"
                 },
-                "phone": {
-                    "type": "string"
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/TestTypeResultCountyStatus"
+                    }
                 },
-                "state": {
+                "test_type": {
                     "type": "string"
                 },
-                "zip_code": {
+                "test_type_id": {
                     "type": "string"
                 }
             }
         },
-        "AuditEntity": {
+        "Status": {
             "type": "object",
             "properties": {
-                "client_data": {
+                "account_id": {
                     "type": "string"
                 },
-                "created_at": {
+                "app_version": {
                     "type": "string"
                 },
-                "data": {
+                "date": {
                     "type": "string"
                 },
-                "entity": {
+                "date_updated": {
                     "type": "string"
                 },
-                "entity_id": {
+                "encrypted_blob": {
                     "type": "string"
                 },
-                "operation": {
+                "encrypted_key": {
                     "type": "string"
                 },
-                "used_group": {
+                "id": {
                     "type": "string"
-                },
-                "user_identifier": {
+                }
+            }
+        },
+        "Symptom": {
+            "type": "object",
+            "properties": {
+                "id": {
                     "type": "string"
                 },
-                "user_info": {
+                "name": {
                     "type": "string"
                 }
             }
         },
-        "County": {
+        "SymptomGroup": {
             "type": "object",
             "properties": {
-                "country": {
+                "id": {
                     "type": "string"
                 },
-                "county_statuses": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/CountyStatus"
-                    }
+                "name": {
+                    "type": "string"
                 },
-                "guidelines": {
+                "symptoms": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/Guideline"
+                        "$ref": "#/definitions/Symptom"
                     }
+                }
+            }
+        },
+        "SymptomRule": {
+            "type": "object",
+            "properties": {
+                "gr1_count": {
+                    "type": "integer"
                 },
-                "id": {
-                    "type": "string"
+                "gr2_count": {
+                    "type": "integer"
                 },
-                "name": {
+                "id": {
                     "type": "string"
                 },
-                "state_province": {
-                    "type": "string"
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/SymptomRuleItem"
+                    }
                 }
             }
         },
-        "CountyStatus": {
+        "SymptomRuleItem": {
             "type": "object",
             "properties": {
-                "description": {
+                "county_status_id": {
                     "type": "string"
                 },
-                "id": {
+                "gr1": {
+                    "type": "boolean"
+                },
+                "gr2": {
+                    "type": "boolean"
+                },
+                "health_status": {
                     "type": "string"
                 },
-                "name": {
+                "next_step": {
                     "type": "string"
                 }
             }
         },
-        "FAQ": {
+        "TestType": {
             "type": "object",
             "properties": {
-                "dateUpdated": {
+                "id": {
                     "type": "string"
                 },
-                "general": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/FAQGeneral"
-                    }
+                "name": {
+                    "type": "string"
                 },
-                "sections": {
+                "priority": {
+                    "type": "integer"
+                },
+                "results": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/FAQSection"
+                        "$ref": "#/definitions/TestTypeResult"
                     }
                 }
             }
         },
-        "FAQGeneral": {
+        "TestTypeResult": {
             "type": "object",
             "properties": {
-                "description": {
-                    "type": "string"
-                },
-                "link": {
+                "id": {
                     "type": "string"
                 },
-                "title": {
+                "name": {
                     "type": "string"
                 }
             }
         },
-        "FAQQuestion": {
+        "TestTypeResultCountyStatus": {
             "type": "object",
             "properties": {
-                "description": {
+                "health_status": {
                     "type": "string"
                 },
-                "display_order": {
-                    "type": "integer"
+                "health_status_id": {
+                    "type": "string"
                 },
-                "id": {
+                "result": {
                     "type": "string"
                 },
-                "link": {
+                "result_id": {
                     "type": "string"
                 },
-                "title": {
+                "result_next_step": {
                     "type": "string"
+                },
+                "result_next_step_time_interval": {
+                    "type": "integer"
                 }
             }
         },
-        "FAQSection": {
+        "Time": {
             "type": "object",
             "properties": {
-                "display_order": {
-                    "type": "integer"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "questions": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/FAQQuestion"
-                    }
-                },
-                "title": {
+                "time": {
                     "type": "string"
                 }
             }
         },
-        "GetUserByShibbolethUINResponse": {
+        "TraceExposure": {
             "type": "object",
             "properties": {
-                "consent": {
-                    "type": "boolean"
+                "date_added": {
+                    "type": "integer"
                 },
-                "consent_vaccine": {
-                    "type": "boolean"
+                "days_since_onset": {
+                    "type": "integer"
                 },
-                "public_key": {
+                "expirestamp": {
+                    "type": "integer"
+                },
+                "report_type": {
+                    "type": "string"
+                },
+                "rolling_period": {
+                    "type": "integer"
+                },
+                "rolling_start_number": {
+                    "type": "integer"
+                },
+                "tek": {
                     "type": "string"
+                },
+                "timestamp": {
+                    "type": "integer"
+                },
+                "transmission_risk": {
+                    "type": "integer"
                 }
             }
         },
-        "Guideline": {
+        "UINBuildingAccess": {
             "type": "object",
             "properties": {
-                "description": {
+                "access": {
                     "type": "string"
                 },
-                "id": {
+                "date": {
                     "type": "string"
                 },
-                "items": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/GuidelineItem"
-                    }
-                },
-                "name": {
+                "uin": {
                     "type": "string"
                 }
             }
         },
-        "GuidelineItem": {
+        "UINOverride": {
             "type": "object",
             "properties": {
-                "description": {
+                "activation": {
                     "type": "string"
                 },
-                "icon": {
+                "category": {
                     "type": "string"
                 },
-                "type": {
+                "exempt": {
+                    "type": "boolean"
+                },
+                "expiration": {
+                    "type": "string"
+                },
+                "interval": {
+                    "type": "integer"
+                },
+                "uin": {
                     "type": "string"
                 }
             }
         },
-        "JoinGroupExtApprovement": {
+        "User": {
             "type": "object",
             "properties": {
-                "date_created": {
-                    "type": "string"
+                "accounts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/Account"
+                    }
+                },
+                "consent": {
+                    "type": "boolean"
+                },
+                "consent_vaccine": {
+                    "type": "boolean"
                 },
-                "email": {
+                "encrypted_blob": {
                     "type": "string"
                 },
-                "external_approver_id": {
+                "encrypted_key": {
                     "type": "string"
                 },
-                "external_approver_last_name": {
+                "encrypted_pk": {
                     "type": "string"
                 },
-                "first_name": {
-                    "type": "string"
+                "exposure_notification": {
+                    "type": "boolean"
                 },
-                "group_name": {
+                "external_id": {
                     "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
-                "last_name": {
+                "public_key": {
                     "type": "string"
                 },
-                "phone": {
-                    "type": "string"
+                "re_post": {
+                    "type": "boolean"
                 },
-                "status": {
+                "uuid": {
                     "type": "string"
                 }
             }
         },
-        "Location": {
+        "UsersCacheMetrics": {
             "type": "object",
             "properties": {
-                "address_1": {
-                    "type": "string"
-                },
-                "address_2": {
-                    "type": "string"
-                },
-                "available_tests": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "capacity": {
+                    "description": "the max cached entries",
+                    "type": "integer"
                 },
-                "city": {
-                    "type": "string"
+                "hits": {
+                    "type": "integer"
                 },
-                "contact": {
-                    "type": "string"
+                "invalidations": {
+                    "type": "integer"
                 },
-                "country": {
-                    "type": "string"
+                "misses": {
+                    "type": "integer"
                 },
-                "county_id": {
-                    "type": "string"
+                "purges": {
+                    "type": "integer"
                 },
-                "days_of_operation": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/OperationDay"
-                    }
+                "shared": {
+                    "description": "if the replicas share the cache",
+                    "type": "boolean"
                 },
-                "id": {
-                    "type": "string"
+                "shared_errors": {
+                    "type": "integer"
                 },
-                "latitude": {
-                    "type": "number"
+                "shared_hits": {
+                    "type": "integer"
                 },
-                "longitude": {
-                    "type": "number"
+                "size": {
+                    "description": "the cached entries - two per user",
+                    "type": "integer"
                 },
-                "name": {
-                    "type": "string"
+                "ttl": {
+                    "description": "in seconds",
+                    "type": "integer"
+                }
+            }
+        },
+        "addTraceReportRequest": {
+            "type": "array",
+            "items": {
+                "type": "object",
+                "required": [
+                    "tek",
+                    "timestamp"
+                ],
+                "properties": {
+                    "expirestamp": {
+                        "type": "integer"
+                    },
+                    "tek": {
+                        "type": "string"
+                    },
+                    "timestamp": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "apiKeyResponse": {
+            "type": "object",
+            "properties": {
+                "api_key": {
+                    "type": "object",
+                    "$ref": "#/definitions/APIKey"
                 },
-                "notes": {
+                "key": {
                     "type": "string"
-                },
-                "provider_id": {
+                }
+            }
+        },
+        "createAPIKeyRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "scopes"
+            ],
+            "properties": {
+                "audit": {
                     "type": "string"
                 },
-                "state": {
+                "date_expires": {
                     "type": "string"
                 },
-                "timezone": {
+                "name": {
                     "type": "string"
                 },
-                "url": {
+                "owner": {
                     "type": "string"
                 },
-                "wait_time_color": {
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "createAccessRuleItemRequest": {
+            "type": "object",
+            "required": [
+                "county_status_id",
+                "value"
+            ],
+            "properties": {
+                "county_status_id": {
                     "type": "string"
                 },
-                "zip": {
+                "value": {
                     "type": "string"
                 }
             }
         },
-        "ManualTest": {
+        "createAccessRuleRequest": {
             "type": "object",
+            "required": [
+                "county_id",
+                "rules"
+            ],
             "properties": {
-                "account_id": {
+                "audit": {
                     "type": "string"
                 },
                 "county_id": {
                     "type": "string"
                 },
-                "date": {
+                "rules": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/createAccessRuleItemRequest"
+                    }
+                }
+            }
+        },
+        "createActionRequest": {
+            "type": "object",
+            "required": [
+                "account_id",
+                "encrypted_blob",
+                "encrypted_key",
+                "provider_id"
+            ],
+            "properties": {
+                "account_id": {
                     "type": "string"
                 },
-                "date_created": {
+                "audit": {
                     "type": "string"
                 },
                 "encrypted_blob": {
@@ -6240,82 +9754,77 @@ var doc = `{
                 "encrypted_key": {
                     "type": "string"
                 },
-                "history_id": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "location_id": {
-                    "type": "string"
+                "positive": {
+                    "description": "an exposure verification code is issued for the user only for positive results",
+                    "type": "boolean"
                 },
-                "status": {
+                "provider_id": {
                     "type": "string"
-                },
-                "user": {
-                    "type": "object",
-                    "$ref": "#/definitions/User"
                 }
             }
         },
-        "ManualTestImage": {
+        "createAppVersionRequest": {
             "type": "object",
+            "required": [
+                "version"
+            ],
             "properties": {
-                "encrypted_image_blob": {
+                "audit": {
                     "type": "string"
                 },
-                "encrypted_image_key": {
+                "version": {
                     "type": "string"
                 }
             }
         },
-        "News": {
+        "createBroadcastRequest": {
             "type": "object",
+            "required": [
+                "body",
+                "title"
+            ],
             "properties": {
-                "date": {
-                    "type": "string"
-                },
-                "description": {
+                "audit": {
                     "type": "string"
                 },
-                "htmlContent": {
+                "body": {
                     "type": "string"
                 },
-                "id": {
+                "scheduled_at": {
                     "type": "string"
                 },
-                "link": {
-                    "type": "string"
+                "target": {
+                    "type": "object",
+                    "$ref": "#/definitions/BroadcastTarget"
                 },
                 "title": {
                     "type": "string"
                 }
             }
         },
-        "OperationDay": {
+        "createCTestRequest": {
             "type": "object",
+            "required": [
+                "encrypted_blob",
+                "encrypted_key",
+                "provider_id",
+                "uin"
+            ],
             "properties": {
-                "close_time": {
+                "encrypted_blob": {
                     "type": "string"
                 },
-                "name": {
+                "encrypted_key": {
                     "type": "string"
                 },
-                "open_time": {
+                "order_number": {
                     "type": "string"
-                }
-            }
-        },
-        "PUser": {
-            "type": "object",
-            "properties": {
-                "consent": {
-                    "type": "boolean"
                 },
-                "consent_vaccine": {
+                "positive": {
+                    "description": "an exposure verification code is issued for the user only for positive results",
                     "type": "boolean"
                 },
-                "public_key": {
+                "provider_id": {
                     "type": "string"
                 },
                 "uin": {
@@ -6323,311 +9832,362 @@ var doc = `{
                 }
             }
         },
-        "Provider": {
-            "type": "object",
-            "properties": {
-                "available_mechanisms": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
-                },
-                "id": {
-                    "type": "string"
-                },
-                "manual_test": {
-                    "type": "boolean"
-                },
-                "provider_name": {
-                    "type": "string"
-                }
-            }
-        },
-        "RawSubAccount": {
+        "createCountyRequest": {
             "type": "object",
+            "required": [
+                "country",
+                "name",
+                "state_province"
+            ],
             "properties": {
-                "account_id": {
-                    "type": "string"
-                },
-                "address1": {
-                    "type": "string"
-                },
-                "address2": {
-                    "type": "string"
-                },
-                "address3": {
-                    "type": "string"
-                },
-                "birth_date": {
+                "audit": {
                     "type": "string"
                 },
-                "city": {
+                "country": {
                     "type": "string"
                 },
-                "email": {
+                "name": {
                     "type": "string"
                 },
-                "first_name": {
+                "state_province": {
                     "type": "string"
-                },
-                "gender": {
+                }
+            }
+        },
+        "createCountyStatusRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "audit": {
                     "type": "string"
                 },
-                "last_name": {
+                "county_id": {
                     "type": "string"
                 },
-                "middle_name": {
+                "description": {
                     "type": "string"
                 },
-                "net_id": {
+                "name": {
                     "type": "string"
-                },
-                "phone": {
+                }
+            }
+        },
+        "createExtUINOverrideRequest": {
+            "type": "object",
+            "required": [
+                "interval",
+                "uin"
+            ],
+            "properties": {
+                "activation": {
                     "type": "string"
                 },
-                "primary_account": {
+                "category": {
                     "type": "string"
                 },
-                "state": {
+                "expiration": {
                     "type": "string"
                 },
+                "interval": {
+                    "type": "integer"
+                },
                 "uin": {
                     "type": "string"
+                }
+            }
+        },
+        "createFAQQuestionRequest": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
                 },
-                "zip_code": {
+                "display_order": {
+                    "type": "integer"
+                },
+                "title": {
                     "type": "string"
                 }
             }
         },
-        "Resource": {
+        "createFAQRequest": {
             "type": "object",
             "properties": {
+                "audit": {
+                    "type": "string"
+                },
                 "display_order": {
                     "type": "integer"
                 },
-                "icon": {
-                    "type": "string"
+                "question": {
+                    "type": "object",
+                    "$ref": "#/definitions/createFAQQuestionRequest"
                 },
-                "id": {
+                "section": {
+                    "type": "string"
+                }
+            }
+        },
+        "createGuidelineItemsRequest": {
+            "type": "object",
+            "required": [
+                "description",
+                "icon",
+                "type"
+            ],
+            "properties": {
+                "description": {
                     "type": "string"
                 },
-                "link": {
+                "icon": {
                     "type": "string"
                 },
-                "title": {
+                "type": {
                     "type": "string"
                 }
             }
         },
-        "Rule": {
+        "createGuidelineRequest": {
             "type": "object",
+            "required": [
+                "items",
+                "name"
+            ],
             "properties": {
-                "priority": {
-                    "type": "integer"
+                "audit": {
+                    "type": "string"
                 },
-                "results": {
+                "county_id": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "items": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/TestTypeResultCountyStatus"
+                        "$ref": "#/definitions/createGuidelineItemsRequest"
                     }
                 },
-                "test_type": {
-                    "type": "string"
-                },
-                "test_type_id": {
+                "name": {
                     "type": "string"
                 }
             }
         },
-        "Status": {
+        "createHistoryRequest": {
             "type": "object",
+            "required": [
+                "date",
+                "encrypted_blob",
+                "encrypted_key",
+                "type"
+            ],
             "properties": {
-                "account_id": {
+                "county_id": {
                     "type": "string"
                 },
-                "app_version": {
+                "date": {
                     "type": "string"
                 },
-                "date": {
+                "encrypted_blob": {
                     "type": "string"
                 },
-                "date_updated": {
+                "encrypted_image_blob": {
                     "type": "string"
                 },
-                "encrypted_blob": {
+                "encrypted_image_key": {
                     "type": "string"
                 },
                 "encrypted_key": {
                     "type": "string"
                 },
-                "id": {
-                    "type": "string"
-                }
-            }
-        },
-        "Symptom": {
-            "type": "object",
-            "properties": {
-                "id": {
+                "location_id": {
                     "type": "string"
                 },
-                "name": {
+                "type": {
                     "type": "string"
                 }
             }
         },
-        "SymptomGroup": {
+        "createLocationRequest": {
             "type": "object",
+            "required": [
+                "available_tests",
+                "county_id",
+                "latitude",
+                "longitude",
+                "name",
+                "provider_id"
+            ],
             "properties": {
-                "id": {
+                "address_1": {
                     "type": "string"
                 },
-                "name": {
+                "address_2": {
                     "type": "string"
                 },
-                "symptoms": {
+                "audit": {
+                    "type": "string"
+                },
+                "available_tests": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/Symptom"
+                        "type": "string"
                     }
-                }
-            }
-        },
-        "SymptomRule": {
-            "type": "object",
-            "properties": {
-                "gr1_count": {
-                    "type": "integer"
                 },
-                "gr2_count": {
-                    "type": "integer"
+                "city": {
+                    "type": "string"
                 },
-                "id": {
+                "contact": {
                     "type": "string"
                 },
-                "items": {
+                "country": {
+                    "type": "string"
+                },
+                "county_id": {
+                    "type": "string"
+                },
+                "days_of_operation": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/SymptomRuleItem"
+                        "$ref": "#/definitions/locationOperationDayRequest"
                     }
-                }
-            }
-        },
-        "SymptomRuleItem": {
-            "type": "object",
-            "properties": {
-                "county_status_id": {
+                },
+                "latitude": {
+                    "type": "number"
+                },
+                "longitude": {
+                    "type": "number"
+                },
+                "name": {
                     "type": "string"
                 },
-                "gr1": {
-                    "type": "boolean"
+                "notes": {
+                    "type": "string"
                 },
-                "gr2": {
-                    "type": "boolean"
+                "provider_id": {
+                    "type": "string"
                 },
-                "health_status": {
+                "state": {
                     "type": "string"
                 },
-                "next_step": {
+                "url": {
+                    "type": "string"
+                },
+                "wait_time_color": {
+                    "type": "string"
+                },
+                "zip": {
                     "type": "string"
                 }
             }
         },
-        "TestType": {
+        "createNewsRequest": {
             "type": "object",
             "properties": {
-                "id": {
+                "audit": {
                     "type": "string"
                 },
-                "name": {
+                "date": {
                     "type": "string"
                 },
-                "priority": {
-                    "type": "integer"
+                "description": {
+                    "type": "string"
                 },
-                "results": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/TestTypeResult"
-                    }
+                "htmlContent": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
                 }
             }
         },
-        "TestTypeResult": {
+        "createNotificationTemplateRequest": {
             "type": "object",
+            "required": [
+                "event",
+                "variants"
+            ],
             "properties": {
-                "id": {
+                "audit": {
                     "type": "string"
                 },
-                "name": {
+                "event": {
                     "type": "string"
+                },
+                "variants": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/NotificationTemplateVariant"
+                    }
                 }
             }
         },
-        "TestTypeResultCountyStatus": {
+        "createOrUpdateCRulesRequest": {
             "type": "object",
+            "required": [
+                "app_version",
+                "county_id",
+                "data"
+            ],
             "properties": {
-                "health_status": {
-                    "type": "string"
-                },
-                "health_status_id": {
+                "app_version": {
                     "type": "string"
                 },
-                "result": {
+                "audit": {
                     "type": "string"
                 },
-                "result_id": {
+                "county_id": {
                     "type": "string"
                 },
-                "result_next_step": {
+                "data": {
                     "type": "string"
-                },
-                "result_next_step_time_interval": {
-                    "type": "integer"
                 }
             }
         },
-        "Time": {
+        "createOrUpdateStatusRequest": {
             "type": "object",
+            "required": [
+                "encrypted_blob",
+                "encrypted_key"
+            ],
             "properties": {
-                "time": {
+                "date": {
                     "type": "string"
-                }
-            }
-        },
-        "TraceExposure": {
-            "type": "object",
-            "properties": {
-                "date_added": {
-                    "type": "integer"
-                },
-                "expirestamp": {
-                    "type": "integer"
                 },
-                "tek": {
+                "encrypted_blob": {
                     "type": "string"
                 },
-                "timestamp": {
-                    "type": "integer"
+                "encrypted_key": {
+                    "type": "string"
                 }
             }
         },
-        "UINBuildingAccess": {
+        "createOrUpdateSymptomsRequest": {
             "type": "object",
+            "required": [
+                "app_version",
+                "items"
+            ],
             "properties": {
-                "access": {
+                "app_version": {
                     "type": "string"
                 },
-                "date": {
+                "audit": {
                     "type": "string"
                 },
-                "uin": {
+                "items": {
                     "type": "string"
                 }
             }
         },
-        "UINOverride": {
+        "createOrUpdateUINOverride": {
             "type": "object",
+            "required": [
+                "interval"
+            ],
             "properties": {
                 "activation": {
                     "type": "string"
@@ -6635,741 +10195,628 @@ var doc = `{
                 "category": {
                     "type": "string"
                 },
-                "exempt": {
-                    "type": "boolean"
-                },
                 "expiration": {
                     "type": "string"
                 },
                 "interval": {
                     "type": "integer"
-                },
-                "uin": {
-                    "type": "string"
                 }
             }
         },
-        "User": {
+        "createProviderCredentialRequest": {
             "type": "object",
             "properties": {
-                "accounts": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/Account"
-                    }
-                },
-                "consent": {
-                    "type": "boolean"
-                },
-                "consent_vaccine": {
-                    "type": "boolean"
-                },
-                "encrypted_blob": {
+                "audit": {
                     "type": "string"
                 },
-                "encrypted_key": {
+                "date_expires": {
                     "type": "string"
                 },
-                "encrypted_pk": {
-                    "type": "string"
+                "rate_limit": {
+                    "type": "integer"
                 },
-                "exposure_notification": {
+                "require_signature": {
                     "type": "boolean"
                 },
-                "external_id": {
-                    "type": "string"
-                },
-                "id": {
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "createProviderRequest": {
+            "type": "object",
+            "required": [
+                "manual_test",
+                "provider_name"
+            ],
+            "properties": {
+                "audit": {
                     "type": "string"
                 },
-                "public_key": {
-                    "type": "string"
+                "available_mechanisms": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "re_post": {
+                "manual_test": {
                     "type": "boolean"
                 },
-                "uuid": {
+                "provider_name": {
                     "type": "string"
                 }
             }
         },
-        "addTraceReportRequest": {
-            "type": "array",
-            "items": {
-                "type": "object",
-                "required": [
-                    "tek",
-                    "timestamp"
-                ],
-                "properties": {
-                    "expirestamp": {
-                        "type": "integer"
-                    },
-                    "tek": {
-                        "type": "string"
-                    },
-                    "timestamp": {
-                        "type": "integer"
+        "createRawSubAccountItemsRequest": {
+            "type": "object",
+            "properties": {
+                "audit": {
+                    "type": "string"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "type": "object",
+                        "properties": {
+                            "address1": {
+                                "type": "string"
+                            },
+                            "address2": {
+                                "type": "string"
+                            },
+                            "address3": {
+                                "type": "string"
+                            },
+                            "birth_date": {
+                                "type": "string"
+                            },
+                            "city": {
+                                "type": "string"
+                            },
+                            "email": {
+                                "type": "string"
+                            },
+                            "first_name": {
+                                "type": "string",
+                                "required": [
+                                    "first_name"
+                                ]
+                            },
+                            "gender": {
+                                "type": "string"
+                            },
+                            "last_name": {
+                                "type": "string",
+                                "required": [
+                                    "last_name"
+                                ]
+                            },
+                            "middle_name": {
+                                "type": "string"
+                            },
+                            "net_id": {
+                                "type": "string"
+                            },
+                            "phone": {
+                                "type": "string"
+                            },
+                            "primary_account": {
+                                "type": "string",
+                                "required": [
+                                    "primary_account"
+                                ]
+                            },
+                            "state": {
+                                "type": "string"
+                            },
+                            "uin": {
+                                "type": "string",
+                                "required": [
+                                    "uin"
+                                ]
+                            },
+                            "zip_code": {
+                                "type": "string"
+                            }
+                        }
                     }
                 }
             }
         },
-        "createAccessRuleItemRequest": {
+        "createResourceRequest": {
             "type": "object",
-            "required": [
-                "county_status_id",
-                "value"
-            ],
             "properties": {
-                "county_status_id": {
+                "audit": {
                     "type": "string"
                 },
-                "value": {
+                "display_order": {
+                    "type": "integer"
+                },
+                "link": {
+                    "type": "string"
+                },
+                "title": {
                     "type": "string"
                 }
             }
         },
-        "createAccessRuleRequest": {
+        "createRosterItemsRequest": {
             "type": "object",
             "required": [
-                "county_id",
-                "rules"
+                "items"
             ],
             "properties": {
                 "audit": {
                     "type": "string"
                 },
-                "county_id": {
-                    "type": "string"
-                },
-                "rules": {
+                "items": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/createAccessRuleItemRequest"
+                        "$ref": "#/definitions/rosterItemRequest"
                     }
                 }
             }
         },
-        "createActionRequest": {
+        "createRosterRequest": {
             "type": "object",
             "required": [
-                "account_id",
-                "encrypted_blob",
-                "encrypted_key",
-                "provider_id"
+                "phone",
+                "uin"
             ],
             "properties": {
-                "account_id": {
+                "address1": {
+                    "type": "string"
+                },
+                "address2": {
+                    "type": "string"
+                },
+                "address3": {
                     "type": "string"
                 },
+                "attributes": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
                 "audit": {
                     "type": "string"
                 },
-                "encrypted_blob": {
+                "badge_type": {
+                    "type": "string"
+                },
+                "birth_date": {
+                    "type": "string"
+                },
+                "city": {
                     "type": "string"
                 },
-                "encrypted_key": {
+                "email": {
                     "type": "string"
                 },
-                "provider_id": {
-                    "type": "string"
-                }
-            }
-        },
-        "createAppVersionRequest": {
-            "type": "object",
-            "required": [
-                "version"
-            ],
-            "properties": {
-                "audit": {
+                "first_name": {
                     "type": "string"
                 },
-                "version": {
+                "gender": {
                     "type": "string"
-                }
-            }
-        },
-        "createCTestRequest": {
-            "type": "object",
-            "required": [
-                "encrypted_blob",
-                "encrypted_key",
-                "provider_id",
-                "uin"
-            ],
-            "properties": {
-                "encrypted_blob": {
+                },
+                "last_name": {
                     "type": "string"
                 },
-                "encrypted_key": {
+                "middle_name": {
                     "type": "string"
                 },
-                "order_number": {
+                "phone": {
                     "type": "string"
                 },
-                "provider_id": {
+                "state": {
                     "type": "string"
                 },
                 "uin": {
                     "type": "string"
+                },
+                "zip_code": {
+                    "type": "string"
                 }
             }
         },
-        "createCountyRequest": {
+        "createRuleRequest": {
             "type": "object",
             "required": [
-                "country",
-                "name",
-                "state_province"
+                "county_id",
+                "results_statuses",
+                "test_type_id"
             ],
             "properties": {
                 "audit": {
                     "type": "string"
                 },
-                "country": {
+                "county_id": {
                     "type": "string"
                 },
-                "name": {
-                    "type": "string"
+                "priority": {
+                    "type": "integer"
                 },
-                "state_province": {
+                "results_statuses": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/createRuleResultsStatusesTypeRequest"
+                    }
+                },
+                "test_type_id": {
                     "type": "string"
                 }
             }
         },
-        "createCountyStatusRequest": {
+        "createRuleResultsStatusesTypeRequest": {
             "type": "object",
             "required": [
-                "name"
+                "county_status_id",
+                "test_type_result_id"
             ],
             "properties": {
-                "audit": {
-                    "type": "string"
-                },
-                "county_id": {
-                    "type": "string"
-                },
-                "description": {
+                "county_status_id": {
                     "type": "string"
                 },
-                "name": {
+                "test_type_result_id": {
                     "type": "string"
                 }
             }
         },
-        "createExtUINOverrideRequest": {
+        "createSymptomRequest": {
             "type": "object",
             "required": [
-                "interval",
-                "uin"
+                "name",
+                "symptom_group"
             ],
             "properties": {
-                "activation": {
-                    "type": "string"
-                },
-                "category": {
-                    "type": "string"
-                },
-                "expiration": {
+                "name": {
                     "type": "string"
                 },
-                "interval": {
-                    "type": "integer"
-                },
-                "uin": {
+                "symptom_group": {
                     "type": "string"
                 }
             }
         },
-        "createFAQQuestionRequest": {
+        "createSymptomRuleItemRequest": {
             "type": "object",
+            "required": [
+                "county_status_id",
+                "gr1",
+                "gr2",
+                "next_step"
+            ],
             "properties": {
-                "description": {
+                "county_status_id": {
                     "type": "string"
                 },
-                "display_order": {
-                    "type": "integer"
+                "gr1": {
+                    "type": "boolean"
                 },
-                "title": {
+                "gr2": {
+                    "type": "boolean"
+                },
+                "next_step": {
                     "type": "string"
                 }
             }
         },
-        "createFAQRequest": {
+        "createSymptomRuleRequest": {
             "type": "object",
+            "required": [
+                "county_id",
+                "gr1_count",
+                "gr2_count",
+                "items"
+            ],
             "properties": {
-                "audit": {
+                "county_id": {
                     "type": "string"
                 },
-                "display_order": {
+                "gr1_count": {
                     "type": "integer"
                 },
-                "question": {
-                    "type": "object",
-                    "$ref": "#/definitions/createFAQQuestionRequest"
+                "gr2_count": {
+                    "type": "integer"
                 },
-                "section": {
-                    "type": "string"
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/createSymptomRuleItemRequest"
+                    }
                 }
             }
         },
-        "createGuidelineItemsRequest": {
+        "createTestTypeRequest": {
             "type": "object",
             "required": [
-                "description",
-                "icon",
-                "type"
+                "name"
             ],
             "properties": {
-                "description": {
+                "audit": {
                     "type": "string"
                 },
-                "icon": {
+                "name": {
                     "type": "string"
                 },
-                "type": {
-                    "type": "string"
+                "priority": {
+                    "type": "integer"
                 }
             }
         },
-        "createGuidelineRequest": {
+        "createTestTypeResultRequest": {
             "type": "object",
             "required": [
-                "items",
                 "name"
             ],
             "properties": {
                 "audit": {
                     "type": "string"
                 },
-                "county_id": {
-                    "type": "string"
-                },
-                "description": {
+                "name": {
                     "type": "string"
                 },
-                "items": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/createGuidelineItemsRequest"
-                    }
-                },
-                "name": {
+                "test_type_id": {
                     "type": "string"
                 }
             }
         },
-        "createHistoryRequest": {
+        "createUINOverrideRequest": {
             "type": "object",
             "required": [
-                "date",
-                "encrypted_blob",
-                "encrypted_key",
-                "type"
+                "uin"
             ],
             "properties": {
-                "county_id": {
-                    "type": "string"
-                },
-                "date": {
+                "activation": {
                     "type": "string"
                 },
-                "encrypted_blob": {
+                "audit": {
                     "type": "string"
                 },
-                "encrypted_image_blob": {
+                "category": {
                     "type": "string"
                 },
-                "encrypted_image_key": {
-                    "type": "string"
+                "exempt": {
+                    "type": "boolean"
                 },
-                "encrypted_key": {
+                "expiration": {
                     "type": "string"
                 },
-                "location_id": {
-                    "type": "string"
+                "interval": {
+                    "type": "integer"
                 },
-                "type": {
+                "uin": {
                     "type": "string"
                 }
             }
         },
-        "createLocationRequest": {
+        "getComplianceItemsResponse": {
             "type": "object",
-            "required": [
-                "available_tests",
-                "county_id",
-                "latitude",
-                "longitude",
-                "name",
-                "provider_id"
-            ],
             "properties": {
-                "address_1": {
-                    "type": "string"
-                },
-                "address_2": {
-                    "type": "string"
-                },
-                "audit": {
-                    "type": "string"
-                },
-                "available_tests": {
+                "items": {
                     "type": "array",
                     "items": {
-                        "type": "string"
+                        "$ref": "#/definitions/ComplianceItem"
                     }
                 },
-                "city": {
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "getRosterByPhoneResponse": {
+            "type": "object",
+            "properties": {
+                "address1": {
                     "type": "string"
                 },
-                "contact": {
+                "address2": {
                     "type": "string"
                 },
-                "country": {
+                "address3": {
                     "type": "string"
                 },
-                "county_id": {
+                "badge_type": {
                     "type": "string"
                 },
-                "days_of_operation": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/locationOperationDayRequest"
-                    }
-                },
-                "latitude": {
-                    "type": "number"
-                },
-                "longitude": {
-                    "type": "number"
-                },
-                "name": {
+                "birth_date": {
                     "type": "string"
                 },
-                "notes": {
+                "city": {
                     "type": "string"
                 },
-                "provider_id": {
+                "email": {
                     "type": "string"
                 },
-                "state": {
+                "first_name": {
                     "type": "string"
                 },
-                "url": {
+                "gender": {
                     "type": "string"
                 },
-                "wait_time_color": {
+                "last_name": {
                     "type": "string"
                 },
-                "zip": {
-                    "type": "string"
-                }
-            }
-        },
-        "createNewsRequest": {
-            "type": "object",
-            "properties": {
-                "audit": {
+                "middle_name": {
                     "type": "string"
                 },
-                "date": {
+                "phone": {
                     "type": "string"
                 },
-                "description": {
+                "state": {
                     "type": "string"
                 },
-                "htmlContent": {
+                "uin": {
                     "type": "string"
                 },
-                "title": {
+                "zip_code": {
                     "type": "string"
                 }
             }
         },
-        "createOrUpdateCRulesRequest": {
+        "gubonResponse": {
             "type": "object",
-            "required": [
-                "app_version",
-                "county_id",
-                "data"
-            ],
-            "properties": {
-                "app_version": {
-                    "type": "string"
-                },
-                "audit": {
-                    "type": "string"
-                },
-                "county_id": {
-                    "type": "string"
-                },
-                "data": {
-                    "type": "string"
+            "additionalProperties": {
+                "type": "string"
+            }
+        },
+        "ilbuResponse": {
+            "type": "object",
+            "additionalProperties": {
+                "type": "array",
+                "items": {
+                    "$ref": "#/definitions/ilbuResponseItem"
                 }
             }
         },
-        "createOrUpdateStatusRequest": {
+        "ilbuResponseItem": {
             "type": "object",
-            "required": [
-                "encrypted_blob",
-                "encrypted_key"
-            ],
             "properties": {
-                "date": {
-                    "type": "string"
-                },
-                "encrypted_blob": {
+                "date_created": {
                     "type": "string"
                 },
-                "encrypted_key": {
+                "order_number": {
                     "type": "string"
                 }
             }
         },
-        "createOrUpdateSymptomsRequest": {
+        "issueExposureVerificationCodeRequest": {
             "type": "object",
             "required": [
-                "app_version",
-                "items"
+                "report_type",
+                "test_date"
             ],
             "properties": {
-                "app_version": {
+                "audit": {
                     "type": "string"
                 },
-                "audit": {
+                "report_type": {
                     "type": "string"
                 },
-                "items": {
+                "symptom_onset_date": {
+                    "type": "string"
+                },
+                "test_date": {
                     "type": "string"
                 }
             }
         },
-        "createOrUpdateUINOverride": {
+        "issueExposureVerificationCodeResponse": {
             "type": "object",
-            "required": [
-                "interval"
-            ],
             "properties": {
-                "activation": {
+                "code": {
                     "type": "string"
                 },
-                "category": {
+                "date_claimed": {
                     "type": "string"
                 },
-                "expiration": {
+                "date_created": {
                     "type": "string"
                 },
-                "interval": {
-                    "type": "integer"
-                }
-            }
-        },
-        "createProviderRequest": {
-            "type": "object",
-            "required": [
-                "manual_test",
-                "provider_name"
-            ],
-            "properties": {
-                "audit": {
+                "date_used": {
                     "type": "string"
                 },
-                "available_mechanisms": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                "expires_at": {
+                    "type": "string"
                 },
-                "manual_test": {
-                    "type": "boolean"
+                "id": {
+                    "type": "string"
                 },
-                "provider_name": {
+                "issued_by": {
                     "type": "string"
-                }
-            }
-        },
-        "createRawSubAccountItemsRequest": {
-            "type": "object",
-            "properties": {
-                "audit": {
+                },
+                "report_type": {
                     "type": "string"
                 },
-                "items": {
-                    "type": "array",
-                    "items": {
-                        "type": "object",
-                        "properties": {
-                            "address1": {
-                                "type": "string"
-                            },
-                            "address2": {
-                                "type": "string"
-                            },
-                            "address3": {
-                                "type": "string"
-                            },
-                            "birth_date": {
-                                "type": "string"
-                            },
-                            "city": {
-                                "type": "string"
-                            },
-                            "email": {
-                                "type": "string"
-                            },
-                            "first_name": {
-                                "type": "string",
-                                "required": [
-                                    "first_name"
-                                ]
-                            },
-                            "gender": {
-                                "type": "string"
-                            },
-                            "last_name": {
-                                "type": "string",
-                                "required": [
-                                    "last_name"
-                                ]
-                            },
-                            "middle_name": {
-                                "type": "string"
-                            },
-                            "net_id": {
-                                "type": "string"
-                            },
-                            "phone": {
-                                "type": "string"
-                            },
-                            "primary_account": {
-                                "type": "string",
-                                "required": [
-                                    "primary_account"
-                                ]
-                            },
-                            "state": {
-                                "type": "string"
-                            },
-                            "uin": {
-                                "type": "string",
-                                "required": [
-                                    "uin"
-                                ]
-                            },
-                            "zip_code": {
-                                "type": "string"
-                            }
-                        }
-                    }
-                }
-            }
-        },
-        "createResourceRequest": {
-            "type": "object",
-            "properties": {
-                "audit": {
+                "source": {
                     "type": "string"
                 },
-                "display_order": {
-                    "type": "integer"
+                "status": {
+                    "type": "string"
                 },
-                "link": {
+                "symptom_onset_date": {
                     "type": "string"
                 },
-                "title": {
+                "test_date": {
                     "type": "string"
-                }
-            }
-        },
-        "createRosterItemsRequest": {
-            "type": "object",
-            "properties": {
-                "audit": {
+                },
+                "token_expires_at": {
                     "type": "string"
                 },
-                "items": {
-                    "type": "array",
-                    "items": {
-                        "type": "object",
-                        "properties": {
-                            "address1": {
-                                "type": "string"
-                            },
-                            "address2": {
-                                "type": "string"
-                            },
-                            "address3": {
-                                "type": "string"
-                            },
-                            "badge_type": {
-                                "type": "string"
-                            },
-                            "birth_date": {
-                                "type": "string"
-                            },
-                            "city": {
-                                "type": "string"
-                            },
-                            "email": {
-                                "type": "string"
-                            },
-                            "first_name": {
-                                "type": "string"
-                            },
-                            "gender": {
-                                "type": "string"
-                            },
-                            "last_name": {
-                                "type": "string"
-                            },
-                            "middle_name": {
-                                "type": "string"
-                            },
-                            "phone": {
-                                "type": "string",
-                                "required": [
-                                    "phone"
-                                ]
-                            },
-                            "state": {
-                                "type": "string"
-                            },
-                            "uin": {
-                                "type": "string",
-                                "required": [
-                                    "uin"
-                                ]
-                            },
-                            "zip_code": {
-                                "type": "string"
-                            }
-                        }
-                    }
+                "user_id": {
+                    "description": "set if the code can be claimed only by a specific user",
+                    "type": "string"
                 }
             }
         },
-        "createRosterRequest": {
+        "locationOperationDayRequest": {
+            "type": "object",
+            "properties": {
+                "close_time": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "open_time": {
+                    "type": "string"
+                }
+            }
+        },
+        "loginUserRequest": {
             "type": "object",
             "required": [
-                "phone",
-                "uin"
+                "consent",
+                "exposure_notification",
+                "public_key",
+                "uuid"
             ],
             "properties": {
-                "address1": {
+                "consent": {
+                    "type": "boolean"
+                },
+                "consent_vaccine": {
+                    "type": "boolean"
+                },
+                "encrypted_blob": {
                     "type": "string"
                 },
-                "address2": {
+                "encrypted_key": {
                     "type": "string"
                 },
-                "address3": {
+                "encrypted_pk": {
                     "type": "string"
                 },
-                "audit": {
+                "exposure_notification": {
+                    "type": "boolean"
+                },
+                "public_key": {
                     "type": "string"
                 },
-                "badge_type": {
+                "re_post": {
+                    "type": "boolean"
+                },
+                "uuid": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.Account": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "address1": {
+                    "type": "string"
+                },
+                "address2": {
+                    "type": "string"
+                },
+                "address3": {
                     "type": "string"
                 },
                 "birth_date": {
@@ -7378,15 +10825,24 @@ var doc = `{
                 "city": {
                     "type": "string"
                 },
+                "default": {
+                    "type": "boolean"
+                },
                 "email": {
                     "type": "string"
                 },
+                "external_id": {
+                    "type": "string"
+                },
                 "first_name": {
                     "type": "string"
                 },
                 "gender": {
                     "type": "string"
                 },
+                "id": {
+                    "type": "string"
+                },
                 "last_name": {
                     "type": "string"
                 },
@@ -7399,186 +10855,317 @@ var doc = `{
                 "state": {
                     "type": "string"
                 },
-                "uin": {
-                    "type": "string"
-                },
                 "zip_code": {
                     "type": "string"
                 }
             }
         },
-        "createRuleRequest": {
+        "model.COVID19Config": {
             "type": "object",
-            "required": [
-                "county_id",
-                "results_statuses",
-                "test_type_id"
-            ],
             "properties": {
-                "audit": {
-                    "type": "string"
+                "broadcast_rate": {
+                    "description": "users per second, default if not set",
+                    "type": "integer"
                 },
-                "county_id": {
+                "exposure_code_lifetime": {
+                    "description": "in minutes, default if not set",
+                    "type": "integer"
+                },
+                "exposure_codes_auto_issue": {
+                    "description": "issue a code for the ctests marked as positive",
+                    "type": "boolean"
+                },
+                "exposure_codes_hourly_limit": {
+                    "description": "codes per issuer, default if not set",
+                    "type": "integer"
+                },
+                "exposure_export_window": {
+                    "description": "in hours, the exposure keys export files period. default if not set",
+                    "type": "integer"
+                },
+                "exposure_retention_days": {
+                    "description": "the exposures older than this are purged. default if not set",
+                    "type": "integer"
+                },
+                "name": {
                     "type": "string"
                 },
-                "priority": {
+                "news_update_period": {
+                    "description": "in minutes",
                     "type": "integer"
                 },
-                "results_statuses": {
+                "roster_attributes": {
+                    "description": "the extra attributes the roster members can have",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/createRuleResultsStatusesTypeRequest"
+                        "$ref": "#/definitions/RosterAttribute"
                     }
                 },
-                "test_type_id": {
+                "testing_interval": {
+                    "description": "in days, for the roster members without uin override interval. 0 - no interval",
+                    "type": "integer"
+                },
+                "testing_overdue_email_days": {
+                    "description": "days after the due date for email escalation. 0 - no escalation",
+                    "type": "integer"
+                },
+                "testing_reminder_days_before": {
+                    "description": "0 - no reminder before the due date",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.CTest": {
+            "type": "object",
+            "properties": {
+                "date_created": {
+                    "type": "string"
+                },
+                "date_updated": {
+                    "type": "string"
+                },
+                "encrypted_blob": {
+                    "type": "string"
+                },
+                "encrypted_key": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "order_number": {
+                    "type": "string"
+                },
+                "processed": {
+                    "type": "boolean"
+                },
+                "provider_id": {
+                    "type": "string"
+                },
+                "user_id": {
                     "type": "string"
                 }
             }
         },
-        "createRuleResultsStatusesTypeRequest": {
+        "model.EHistory": {
             "type": "object",
-            "required": [
-                "county_status_id",
-                "test_type_result_id"
-            ],
             "properties": {
-                "county_status_id": {
+                "date": {
                     "type": "string"
                 },
-                "test_type_result_id": {
+                "encrypted_blob": {
+                    "type": "string"
+                },
+                "encrypted_key": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "user_id": {
                     "type": "string"
                 }
             }
         },
-        "createSymptomRequest": {
+        "pendingSubAccountResponse": {
             "type": "object",
-            "required": [
-                "name",
-                "symptom_group"
-            ],
             "properties": {
-                "name": {
+                "birth_date": {
                     "type": "string"
                 },
-                "symptom_group": {
+                "first_name": {
+                    "type": "string"
+                },
+                "last_name": {
+                    "type": "string"
+                },
+                "middle_name": {
+                    "type": "string"
+                },
+                "uin": {
                     "type": "string"
                 }
             }
         },
-        "createSymptomRuleItemRequest": {
+        "previewBroadcastRequest": {
+            "type": "object",
+            "properties": {
+                "target": {
+                    "type": "object",
+                    "$ref": "#/definitions/BroadcastTarget"
+                }
+            }
+        },
+        "previewBroadcastResponse": {
+            "type": "object",
+            "properties": {
+                "audience_size": {
+                    "type": "integer"
+                }
+            }
+        },
+        "processManualTestRequest": {
             "type": "object",
             "required": [
-                "county_status_id",
-                "gr1",
-                "gr2",
-                "next_step"
+                "status"
             ],
             "properties": {
-                "county_status_id": {
+                "date": {
                     "type": "string"
                 },
-                "gr1": {
-                    "type": "boolean"
+                "encrypted_blob": {
+                    "type": "string"
                 },
-                "gr2": {
-                    "type": "boolean"
+                "encrypted_key": {
+                    "type": "string"
                 },
-                "next_step": {
+                "status": {
                     "type": "string"
                 }
             }
         },
-        "createSymptomRuleRequest": {
+        "providerCredentialResponse": {
+            "type": "object",
+            "properties": {
+                "api_key": {
+                    "type": "string"
+                },
+                "credential": {
+                    "type": "object",
+                    "$ref": "#/definitions/ProviderCredential"
+                },
+                "signing_secret": {
+                    "type": "string"
+                }
+            }
+        },
+        "publishExposureKeysRequest": {
             "type": "object",
             "required": [
-                "county_id",
-                "gr1_count",
-                "gr2_count",
-                "items"
+                "hmacKey",
+                "verificationPayload"
             ],
             "properties": {
-                "county_id": {
+                "hmacKey": {
                     "type": "string"
                 },
-                "gr1_count": {
-                    "type": "integer"
-                },
-                "gr2_count": {
-                    "type": "integer"
+                "padding": {
+                    "type": "string"
                 },
-                "items": {
+                "temporaryExposureKeys": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/createSymptomRuleItemRequest"
+                        "type": "object",
+                        "properties": {
+                            "key": {
+                                "type": "string",
+                                "required": [
+                                    "key"
+                                ]
+                            },
+                            "rollingPeriod": {
+                                "type": "integer",
+                                "required": [
+                                    "rollingPeriod"
+                                ]
+                            },
+                            "rollingStartNumber": {
+                                "type": "integer",
+                                "required": [
+                                    "rollingStartNumber"
+                                ]
+                            },
+                            "transmissionRisk": {
+                                "type": "integer",
+                                "required": [
+                                    "transmissionRisk"
+                                ]
+                            }
+                        }
                     }
+                },
+                "verificationPayload": {
+                    "type": "string"
+                }
+            }
+        },
+        "publishExposureKeysResponse": {
+            "type": "object",
+            "properties": {
+                "insertedExposures": {
+                    "type": "integer"
                 }
             }
         },
-        "createTestTypeRequest": {
+        "revokeAPIKeyRequest": {
             "type": "object",
-            "required": [
-                "name"
-            ],
             "properties": {
                 "audit": {
                     "type": "string"
-                },
-                "name": {
-                    "type": "string"
-                },
-                "priority": {
-                    "type": "integer"
                 }
             }
         },
-        "createTestTypeResultRequest": {
+        "revokeProviderCredentialRequest": {
             "type": "object",
-            "required": [
-                "name"
-            ],
             "properties": {
                 "audit": {
                     "type": "string"
-                },
-                "name": {
-                    "type": "string"
-                },
-                "test_type_id": {
+                }
+            }
+        },
+        "revokeSubAccountRequest": {
+            "type": "object",
+            "properties": {
+                "audit": {
                     "type": "string"
                 }
             }
         },
-        "createUINOverrideRequest": {
+        "roleRequest": {
             "type": "object",
             "required": [
-                "uin"
+                "name",
+                "permissions"
             ],
             "properties": {
-                "activation": {
-                    "type": "string"
-                },
                 "audit": {
                     "type": "string"
                 },
-                "category": {
-                    "type": "string"
-                },
-                "exempt": {
-                    "type": "boolean"
+                "counties": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "expiration": {
+                "description": {
                     "type": "string"
                 },
-                "interval": {
-                    "type": "integer"
+                "groups": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "uin": {
+                "name": {
                     "type": "string"
+                },
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
-        "getRosterByPhoneResponse": {
+        "rosterItemRequest": {
             "type": "object",
+            "required": [
+                "phone",
+                "uin"
+            ],
             "properties": {
                 "address1": {
                     "type": "string"
@@ -7589,6 +11176,12 @@ var doc = `{
                 "address3": {
                     "type": "string"
                 },
+                "attributes": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
                 "badge_type": {
                     "type": "string"
                 },
@@ -7627,183 +11220,103 @@ var doc = `{
                 }
             }
         },
-        "gubonResponse": {
-            "type": "object",
-            "additionalProperties": {
-                "type": "string"
-            }
-        },
-        "ilbuResponse": {
-            "type": "object",
-            "additionalProperties": {
-                "type": "array",
-                "items": {
-                    "$ref": "#/definitions/ilbuResponseItem"
-                }
-            }
-        },
-        "ilbuResponseItem": {
+        "rotateAPIKeyRequest": {
             "type": "object",
             "properties": {
-                "date_created": {
+                "audit": {
                     "type": "string"
                 },
-                "order_number": {
-                    "type": "string"
+                "grace_period_hours": {
+                    "type": "integer"
                 }
             }
         },
-        "locationOperationDayRequest": {
+        "rotateProviderCredentialRequest": {
             "type": "object",
             "properties": {
-                "close_time": {
-                    "type": "string"
-                },
-                "name": {
+                "audit": {
                     "type": "string"
                 },
-                "open_time": {
-                    "type": "string"
+                "grace_period_hours": {
+                    "type": "integer"
                 }
             }
         },
-        "loginUserRequest": {
+        "setBuildingAccessRequest": {
             "type": "object",
             "required": [
-                "consent",
-                "exposure_notification",
-                "public_key",
-                "uuid"
+                "access",
+                "date"
             ],
             "properties": {
-                "consent": {
-                    "type": "boolean"
-                },
-                "consent_vaccine": {
-                    "type": "boolean"
-                },
-                "encrypted_blob": {
-                    "type": "string"
-                },
-                "encrypted_key": {
-                    "type": "string"
-                },
-                "encrypted_pk": {
-                    "type": "string"
-                },
-                "exposure_notification": {
-                    "type": "boolean"
-                },
-                "public_key": {
+                "access": {
                     "type": "string"
                 },
-                "re_post": {
-                    "type": "boolean"
-                },
-                "uuid": {
+                "date": {
                     "type": "string"
                 }
             }
         },
-        "model.COVID19Config": {
+        "syncRostersRequest": {
             "type": "object",
+            "required": [
+                "items"
+            ],
             "properties": {
-                "name": {
+                "audit": {
                     "type": "string"
                 },
-                "news_update_period": {
-                    "description": "in minutes",
-                    "type": "integer"
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/rosterItemRequest"
+                    }
                 }
             }
         },
-        "model.CTest": {
+        "tokenMetrics": {
             "type": "object",
             "properties": {
-                "date_created": {
-                    "type": "string"
-                },
-                "date_updated": {
-                    "type": "string"
-                },
-                "encrypted_blob": {
-                    "type": "string"
-                },
-                "encrypted_key": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "order_number": {
-                    "type": "string"
-                },
-                "processed": {
-                    "type": "boolean"
-                },
-                "provider_id": {
+                "deprecated_sunset": {
                     "type": "string"
                 },
-                "user_id": {
-                    "type": "string"
+                "types": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/tokenTypeMetrics"
+                    }
                 }
             }
         },
-        "model.EHistory": {
+        "tokenTypeMetrics": {
             "type": "object",
             "properties": {
-                "date": {
-                    "type": "string"
-                },
-                "encrypted_blob": {
-                    "type": "string"
+                "accepted": {
+                    "type": "integer"
                 },
-                "encrypted_key": {
-                    "type": "string"
+                "deprecated": {
+                    "type": "boolean"
                 },
-                "id": {
-                    "type": "string"
+                "disabled": {
+                    "type": "integer"
                 },
-                "type": {
+                "last_used": {
                     "type": "string"
                 },
-                "user_id": {
-                    "type": "string"
-                }
-            }
-        },
-        "processManualTestRequest": {
-            "type": "object",
-            "required": [
-                "status"
-            ],
-            "properties": {
-                "date": {
+                "max_app_version": {
                     "type": "string"
                 },
-                "encrypted_blob": {
+                "min_app_version": {
                     "type": "string"
                 },
-                "encrypted_key": {
-                    "type": "string"
+                "rejected": {
+                    "type": "integer"
                 },
-                "status": {
-                    "type": "string"
-                }
-            }
-        },
-        "setBuildingAccessRequest": {
-            "type": "object",
-            "required": [
-                "access",
-                "date"
-            ],
-            "properties": {
-                "access": {
+                "type": {
                     "type": "string"
                 },
-                "date": {
-                    "type": "string"
+                "upgrade_required": {
+                    "type": "integer"
                 }
             }
         },
@@ -8108,6 +11621,27 @@ var doc = `{
                 }
             }
         },
+        "updateNotificationTemplateRequest": {
+            "type": "object",
+            "required": [
+                "event",
+                "variants"
+            ],
+            "properties": {
+                "audit": {
+                    "type": "string"
+                },
+                "event": {
+                    "type": "string"
+                },
+                "variants": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/NotificationTemplateVariant"
+                    }
+                }
+            }
+        },
         "updateProviderRequest": {
             "type": "object",
             "required": [
@@ -8215,6 +11749,12 @@ var doc = `{
                 "address3": {
                     "type": "string"
                 },
+                "attributes": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
                 "audit": {
                     "type": "string"
                 },
@@ -8383,6 +11923,37 @@ var doc = `{
                     "type": "integer"
                 }
             }
+        },
+        "verifyExposureCodeRequest": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string"
+                }
+            }
+        },
+        "verifyExposureCodeResponse": {
+            "type": "object",
+            "properties": {
+                "report_type": {
+                    "type": "string"
+                },
+                "symptom_onset_date": {
+                    "type": "string"
+                },
+                "test_date": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                },
+                "token_expires_at": {
+                    "type": "string"
+                }
+            }
         }
     },
     "securityDefinitions": {
@@ -18,11 +18,15 @@
 package audit
 
 import (
-	"bytes"
-	"fmt"
+	"errors"
 	"health/core"
+	"health/driven/storage"
+	"health/utils"
 	"log"
+	"sort"
 	"strconv"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -30,141 +34,315 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+const (
+	//the items waiting to be written
+	auditQueueSize = 1000
+	//how long an operation waits for its item to be written in strict mode
+	auditStrictTimeout = 30 * time.Second
+)
+
 //Adapter implements the Audit interface
 type Adapter struct {
 	db *database
+
+	//the items are written in batches by one writer in the order they are logged, so they can be chained
+	queue     chan auditItem
+	queueLock sync.RWMutex
+	closed    bool
+	stopped   chan struct{}
+
+	//the operations fail when their items cannot be written
+	strict bool
+
+	//the items are kept there while they cannot be written, they are lost if not provided
+	spillDir    string
+	spill       *spillBuffer
+	replayAfter time.Time
+
+	//the checkpoints are signed with it, no checkpoints if not provided
+	signingKey []byte
+
+	//the chain tail, used only by the writer
+	lastSequence int64
+	lastHash     string
+
+	written       int64
+	dropped       int64
+	retries       int64
+	lastError     *string
+	lastErrorAt   *time.Time
+	lastErrorLock sync.Mutex
 }
 
 //Start starts the audit
 func (a *Adapter) Start() error {
 	err := a.db.start()
-	return err
+	if err != nil {
+		return err
+	}
+
+	err = a.loadTail()
+	if err != nil {
+		return err
+	}
+
+	if len(a.spillDir) > 0 {
+		a.spill, err = newSpillBuffer(a.spillDir)
+		if err != nil {
+			return err
+		}
+		if a.spill.count() > 0 {
+			log.Printf("Audit - %d spilled items to write", a.spill.count())
+		}
+	} else {
+		log.Println("Audit - no spill directory, the items are lost while the audit database is not available")
+	}
+	go a.write()
+
+	if len(a.signingKey) > 0 {
+		go a.setupCheckpointsTimer()
+	} else {
+		log.Println("Audit - no signing key, the checkpoints are disabled")
+	}
+	return nil
 }
 
 //LogCreateEvent logs a create event item
 func (a *Adapter) LogCreateEvent(userIdentifier string, userInfo string, usedGroup string, entity string, entityID string,
-	data []core.AuditDataEntry, clientData *string) {
-	go func(userIdentifier string, userInfo string, usedGroup string, entity string, entityID string) {
-		dataFormatted := a.prepareData(data)
-		auditEntity := core.AuditEntity{UserIdentifier: userIdentifier, UserInfo: userInfo,
-			UsedGroup: usedGroup, Entity: entity, EntityID: entityID,
-			Operation: "create", Data: dataFormatted, ClientData: clientData, CreatedAt: time.Now()}
-
-		a.log(auditEntity)
-
-	}(userIdentifier, userInfo, usedGroup, entity, entityID)
+	data []core.AuditDataEntry, clientData *string) error {
+	after := a.prepareSnapshot(data)
+	return a.enqueue(core.AuditEntity{UserIdentifier: userIdentifier, UserInfo: userInfo,
+		UsedGroup: usedGroup, Entity: entity, EntityID: entityID,
+		Operation: "create", After: after, Changes: a.diff(nil, after), ClientData: clientData, CreatedAt: a.now()})
 }
 
 //LogUpdateEvent logs an update event item
 func (a *Adapter) LogUpdateEvent(userIdentifier string, userInfo string, usedGroup string, entity string, entityID string,
-	data []core.AuditDataEntry, clientData *string) {
-	go func(userIdentifier string, userInfo string, usedGroup string, entity string, entityID string) {
-		dataFormatted := a.prepareData(data)
-		auditEntity := core.AuditEntity{UserIdentifier: userIdentifier, UserInfo: userInfo,
-			UsedGroup: usedGroup, Entity: entity, EntityID: entityID,
-			Operation: "update", Data: dataFormatted, ClientData: clientData, CreatedAt: time.Now()}
-
-		a.log(auditEntity)
+	data []core.AuditDataEntry, clientData *string) error {
+	//the before snapshot and the changes are set when written as the previous item could be still in the queue
+	return a.enqueue(core.AuditEntity{UserIdentifier: userIdentifier, UserInfo: userInfo,
+		UsedGroup: usedGroup, Entity: entity, EntityID: entityID,
+		Operation: "update", After: a.prepareSnapshot(data), ClientData: clientData, CreatedAt: a.now()})
+}
 
-	}(userIdentifier, userInfo, usedGroup, entity, entityID)
+//LogDeleteEvent logs a delete event item
+func (a *Adapter) LogDeleteEvent(userIdentifier string, userInfo string, usedGroup string, entity string, entityID string) error {
+	return a.enqueue(core.AuditEntity{UserIdentifier: userIdentifier, UserInfo: userInfo,
+		UsedGroup: usedGroup, Entity: entity, EntityID: entityID,
+		Operation: "delete", CreatedAt: a.now()})
 }
 
-func (a *Adapter) prepareData(data []core.AuditDataEntry) *string {
-	if len(data) <= 0 {
+//enqueue queues the item for writing. The item is dropped if the queue is full. In strict mode it waits for a place in the queue and
+//for the item to be written, it gives an error if the item is not written - it could be still spilled and written later.
+func (a *Adapter) enqueue(entity core.AuditEntity) error {
+	item := auditItem{entity: entity}
+	if a.strict {
+		item.done = make(chan error, 1)
+	}
+
+	err := a.put(item)
+	if err != nil {
+		atomic.AddInt64(&a.dropped, 1)
+		log.Printf("error audit logging %s %s %s - %s", entity.Operation, entity.Entity, entity.EntityID, err.Error())
+		if !a.strict {
+			return nil
+		}
+		return err
+	}
+	if !a.strict {
 		return nil
 	}
 
-	var b bytes.Buffer
-	i := 0
-	count := len(data)
-	for _, current := range data {
-		res := fmt.Sprintf("%s:%s", current.Key, current.Value)
-		b.WriteString(res)
+	timer := time.NewTimer(auditStrictTimeout)
+	defer timer.Stop()
+	select {
+	case err = <-item.done:
+		return err
+	case <-timer.C:
+		return errors.New("the audit item is not written in time")
+	}
+}
 
-		if i < (count - 1) {
-			b.WriteString(", ")
+func (a *Adapter) put(item auditItem) error {
+	a.queueLock.RLock()
+	defer a.queueLock.RUnlock()
+
+	if a.closed {
+		return errors.New("the audit is stopped")
+	}
+	if !a.strict {
+		select {
+		case a.queue <- item:
+			return nil
+		default:
+			return errors.New("the audit queue is full")
 		}
-		i++
 	}
-	dataFormatted := b.String()
-	return &dataFormatted
+
+	timer := time.NewTimer(auditStrictTimeout)
+	defer timer.Stop()
+	select {
+	case a.queue <- item:
+		return nil
+	case <-timer.C:
+		return errors.New("the audit queue is full")
+	}
 }
 
-//LogDeleteEvent logs a delete event item
-func (a *Adapter) LogDeleteEvent(userIdentifier string, userInfo string, usedGroup string, entity string, entityID string) {
-	go func(userIdentifier string, userInfo string, usedGroup string, entity string, entityID string) {
-		auditEntity := core.AuditEntity{UserIdentifier: userIdentifier, UserInfo: userInfo,
-			UsedGroup: usedGroup, Entity: entity, EntityID: entityID,
-			Operation: "delete", Data: nil, CreatedAt: time.Now()}
+//Metrics gives the audit writer metrics
+func (a *Adapter) Metrics() core.AuditMetrics {
+	a.lastErrorLock.Lock()
+	defer a.lastErrorLock.Unlock()
 
-		a.log(auditEntity)
+	var spilled int64
+	if a.spill != nil {
+		spilled = a.spill.count()
+	}
+	return core.AuditMetrics{QueueDepth: len(a.queue), QueueSize: cap(a.queue), Written: atomic.LoadInt64(&a.written),
+		Dropped: atomic.LoadInt64(&a.dropped), Retries: atomic.LoadInt64(&a.retries), Spilled: spilled, SpillEnabled: a.spill != nil,
+		Strict: a.strict, LastError: a.lastError, LastErrorAt: a.lastErrorAt}
+}
 
-	}(userIdentifier, userInfo, usedGroup, entity, entityID)
+//now gives the current time as it is stored, so the items hash the same after reading them
+func (a *Adapter) now() time.Time {
+	return time.Now().UTC().Truncate(time.Millisecond)
 }
 
-func (a *Adapter) log(entity core.AuditEntity) {
-	_, err := a.db.audit.InsertOne(&entity)
-	if err != nil {
-		log.Printf("error audit logging - %s", err.Error())
+func (a *Adapter) prepareSnapshot(data []core.AuditDataEntry) map[string]string {
+	if len(data) <= 0 {
+		return nil
 	}
+
+	snapshot := make(map[string]string, len(data))
+	for _, current := range data {
+		snapshot[current.Key] = current.Value
+	}
+	return snapshot
 }
 
-//Find finds items
-func (a *Adapter) Find(userIdentifier *string, usedGroup *string, entity *string, entityID *string, operation *string,
-	clientData *string, createdAt *time.Time, sortBy *string, asc *bool, limit *int64) ([]*core.AuditEntity, error) {
-	options := options.Find()
+//findState gives the entity data merged from its logged items since it was last deleted. The items without entity id are not related,
+//so they do not have a state.
+func (a *Adapter) findState(entity string, entityID string) (map[string]string, error) {
+	if len(entityID) == 0 {
+		return nil, nil
+	}
 
-	//add sort
-	if sortBy != nil && asc != nil {
-		ascValue := -1
-		if *asc {
-			ascValue = 1
+	filter := bson.D{primitive.E{Key: "entity", Value: entity}, primitive.E{Key: "entity_id", Value: entityID}}
+	findOptions := options.Find()
+	findOptions.SetSort(bson.D{primitive.E{Key: "created_at", Value: 1}, primitive.E{Key: "sequence", Value: 1}})
+	findOptions.SetProjection(bson.D{primitive.E{Key: "operation", Value: 1}, primitive.E{Key: "after", Value: 1}})
+	var items []core.AuditEntity
+	err := a.db.audit.Find(filter, &items, findOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	var state map[string]string
+	for _, item := range items {
+		switch item.Operation {
+		case "create":
+			state = mergeSnapshot(nil, item.After)
+		case "delete":
+			state = nil
+		default:
+			state = mergeSnapshot(state, item.After)
 		}
-		options.SetSort(bson.D{primitive.E{Key: *sortBy, Value: ascValue}})
 	}
+	return state, nil
+}
 
-	//add limit
-	var limitValue int64
-	limitValue = 1000 //default is 1000
-	if limit != nil {
-		limitValue = *limit
+//mergeSnapshot gives a new snapshot with the state fields overridden by the changed fields
+func mergeSnapshot(state map[string]string, changed map[string]string) map[string]string {
+	if len(state) == 0 && len(changed) == 0 {
+		return nil
 	}
-	options.SetLimit(limitValue)
 
-	//add filter
-	filter := bson.D{}
-	if userIdentifier != nil {
-		filter = append(filter, bson.E{Key: "user_identifier", Value: *userIdentifier})
+	merged := make(map[string]string, len(state)+len(changed))
+	for field, value := range state {
+		merged[field] = value
 	}
-	if usedGroup != nil {
-		filter = append(filter, bson.E{Key: "used_group", Value: *usedGroup})
+	for field, value := range changed {
+		merged[field] = value
+	}
+	return merged
+}
+
+//pickSnapshot gives the state values of the changed fields, the fields which are not in the state are skipped
+func pickSnapshot(state map[string]string, changed map[string]string) map[string]string {
+	var picked map[string]string
+	for field := range changed {
+		value, ok := state[field]
+		if !ok {
+			continue
+		}
+		if picked == nil {
+			picked = make(map[string]string, len(changed))
+		}
+		picked[field] = value
 	}
-	if entity != nil {
-		filter = append(filter, bson.E{Key: "entity", Value: *entity})
+	return picked
+}
+
+//diff gives the changed fields ordered by name
+func (a *Adapter) diff(before map[string]string, after map[string]string) []core.AuditChange {
+	fields := make([]string, 0, len(before)+len(after))
+	for field := range before {
+		fields = append(fields, field)
 	}
-	if entityID != nil {
-		filter = append(filter, bson.E{Key: "entity_id", Value: *entityID})
+	for field := range after {
+		if _, ok := before[field]; !ok {
+			fields = append(fields, field)
+		}
 	}
-	if operation != nil {
-		filter = append(filter, bson.E{Key: "operation", Value: *operation})
+	sort.Strings(fields)
+
+	var changes []core.AuditChange
+	for _, field := range fields {
+		beforeValue, inBefore := before[field]
+		afterValue, inAfter := after[field]
+		if inBefore && inAfter && beforeValue == afterValue {
+			continue
+		}
+
+		change := core.AuditChange{Field: field}
+		if inBefore {
+			change.Before = &beforeValue
+		}
+		if inAfter {
+			change.After = &afterValue
+		}
+		changes = append(changes, change)
 	}
-	if clientData != nil {
-		filter = append(filter, bson.E{Key: "client_data", Value: bson.D{{"$regex", primitive.Regex{Pattern: *clientData, Options: "i"}}}})
+	return changes
+}
+
+//Query finds the items matching the query. The items are limited to the used group if it is provided.
+func (a *Adapter) Query(usedGroup *string, q *utils.Query) ([]*core.AuditEntity, string, error) {
+	if usedGroup != nil {
+		q.AddCondition("used_group", utils.QueryOperatorEq, *usedGroup)
 	}
-	if createdAt != nil {
-		filter = append(filter, bson.E{Key: "created_at", Value: bson.M{"$gte": *createdAt}})
+
+	filter, err := storage.QueryFilter(q)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var items []bson.Raw
+	err = a.db.audit.Find(filter, &items, storage.QueryFindOptions(q))
+	if err != nil {
+		return nil, "", err
 	}
 
 	var result []*core.AuditEntity
-	err := a.db.audit.Find(filter, &result, options)
+	cursor, err := storage.DecodeQueryPage(q, items, &result)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
-	return result, nil
+	return result, cursor, nil
 }
 
 //NewAuditAdapter creates a new audit adapter instance
-func NewAuditAdapter(mongoDBAuth string, mongoDBName string, mongoTimeout string) *Adapter {
+func NewAuditAdapter(mongoDBAuth string, mongoDBName string, mongoTimeout string, signingKey string, spillDir string, strict bool) *Adapter {
 	timeout, err := strconv.Atoi(mongoTimeout)
 	if err != nil {
 		log.Println("Audit - Set default timeout - 500")
@@ -173,5 +351,6 @@ func NewAuditAdapter(mongoDBAuth string, mongoDBName string, mongoTimeout string
 	timeoutMS := time.Millisecond * time.Duration(timeout)
 
 	db := &database{mongoDBAuth: mongoDBAuth, mongoDBName: mongoDBName, mongoTimeout: timeoutMS}
-	return &Adapter{db: db}
+	return &Adapter{db: db, queue: make(chan auditItem, auditQueueSize), stopped: make(chan struct{}), strict: strict, spillDir: spillDir,
+		signingKey: []byte(signingKey)}
 }
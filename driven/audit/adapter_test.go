@@ -0,0 +1,170 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+
+package audit
+
+import (
+	"health/core"
+	"reflect"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		before   map[string]string
+		after    map[string]string
+		expected []core.AuditChange
+	}{
+		{name: "no snapshots", before: nil, after: nil, expected: nil},
+		{
+			name:   "create",
+			before: nil,
+			after:  map[string]string{"name": "Main", "county": "1"},
+			expected: []core.AuditChange{{Field: "county", After: stringPointer("1")},
+				{Field: "name", After: stringPointer("Main")}},
+		},
+		{
+			name:   "delete",
+			before: map[string]string{"name": "Main", "county": "1"},
+			after:  nil,
+			expected: []core.AuditChange{{Field: "county", Before: stringPointer("1")},
+				{Field: "name", Before: stringPointer("Main")}},
+		},
+		{
+			name:     "update",
+			before:   map[string]string{"name": "Main", "county": "1"},
+			after:    map[string]string{"name": "Second", "county": "1"},
+			expected: []core.AuditChange{{Field: "name", Before: stringPointer("Main"), After: stringPointer("Second")}},
+		},
+		{
+			name:     "no changes",
+			before:   map[string]string{"name": "Main"},
+			after:    map[string]string{"name": "Main"},
+			expected: nil,
+		},
+		{
+			name:   "added and removed fields",
+			before: map[string]string{"b": "1", "c": "2"},
+			after:  map[string]string{"a": "1", "c": "2"},
+			expected: []core.AuditChange{{Field: "a", After: stringPointer("1")},
+				{Field: "b", Before: stringPointer("1")}},
+		},
+		{
+			name:     "empty value is a value",
+			before:   map[string]string{"name": ""},
+			after:    map[string]string{},
+			expected: []core.AuditChange{{Field: "name", Before: stringPointer("")}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			adapter := Adapter{}
+			result := adapter.diff(tt.before, tt.after)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("diff() = %s, expected %s", formatChanges(result), formatChanges(tt.expected))
+			}
+		})
+	}
+}
+
+func formatChanges(changes []core.AuditChange) string {
+	value := func(v *string) string {
+		if v == nil {
+			return "nil"
+		}
+		return "\"" + *v + "\""
+	}
+	result := "["
+	for _, change := range changes {
+		result += change.Field + ":" + value(change.Before) + "->" + value(change.After) + " "
+	}
+	return result + "]"
+}
+
+func TestMergeAndPickSnapshot(t *testing.T) {
+	tests := []struct {
+		name    string
+		state   map[string]string
+		changed map[string]string
+		merged  map[string]string
+		picked  map[string]string
+	}{
+		{name: "nothing", state: nil, changed: nil, merged: nil, picked: nil},
+		{name: "no state", state: nil, changed: map[string]string{"a": "1"}, merged: map[string]string{"a": "1"}, picked: nil},
+		{name: "no changes", state: map[string]string{"a": "1"}, changed: nil, merged: map[string]string{"a": "1"}, picked: nil},
+		{
+			name:    "partial update",
+			state:   map[string]string{"a": "1", "b": "2", "c": "3"},
+			changed: map[string]string{"b": "20", "d": "4"},
+			merged:  map[string]string{"a": "1", "b": "20", "c": "3", "d": "4"},
+			picked:  map[string]string{"b": "2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := mergeSnapshot(tt.state, tt.changed); !reflect.DeepEqual(result, tt.merged) {
+				t.Errorf("mergeSnapshot() = %v, expected %v", result, tt.merged)
+			}
+			if result := pickSnapshot(tt.state, tt.changed); !reflect.DeepEqual(result, tt.picked) {
+				t.Errorf("pickSnapshot() = %v, expected %v", result, tt.picked)
+			}
+		})
+	}
+}
+
+func TestPrepareChanges(t *testing.T) {
+	entities := []core.AuditEntity{
+		{Entity: "location", EntityID: "1", Operation: "create", After: map[string]string{"name": "Main", "county": "1"}},
+		{Entity: "location", EntityID: "2", Operation: "create", After: map[string]string{"name": "Other"}},
+		{Entity: "location", EntityID: "1", Operation: "update", After: map[string]string{"name": "Second"}},
+		{Entity: "location", EntityID: "1", Operation: "update", After: map[string]string{"county": "2", "name": "Second"}},
+		{Entity: "location", EntityID: "1", Operation: "delete"},
+		{Entity: "location", Operation: "update", After: map[string]string{"name": "no id"}},
+	}
+
+	adapter := Adapter{}
+	err := adapter.prepareChanges(entities)
+	if err != nil {
+		t.Fatalf("prepareChanges() error = %v", err)
+	}
+
+	expected := []struct {
+		before  map[string]string
+		changes []core.AuditChange
+	}{
+		{before: nil, changes: nil},
+		{before: nil, changes: nil},
+		//only the logged fields are compared
+		{before: map[string]string{"name": "Main"},
+			changes: []core.AuditChange{{Field: "name", Before: stringPointer("Main"), After: stringPointer("Second")}}},
+		{before: map[string]string{"county": "1", "name": "Second"},
+			changes: []core.AuditChange{{Field: "county", Before: stringPointer("1"), After: stringPointer("2")}}},
+		//the deleted entity data is the merged state
+		{before: map[string]string{"county": "2", "name": "Second"},
+			changes: []core.AuditChange{{Field: "county", Before: stringPointer("2")}, {Field: "name", Before: stringPointer("Second")}}},
+		{before: nil, changes: nil},
+	}
+	for i, item := range expected {
+		if !reflect.DeepEqual(entities[i].Before, item.before) {
+			t.Errorf("prepareChanges() item %d before = %v, expected %v", i, entities[i].Before, item.before)
+		}
+		if !reflect.DeepEqual(entities[i].Changes, item.changes) {
+			t.Errorf("prepareChanges() item %d changes = %s, expected %s", i, formatChanges(entities[i].Changes), formatChanges(item.changes))
+		}
+	}
+}
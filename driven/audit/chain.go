@@ -0,0 +1,366 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+
+package audit
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"health/core"
+	"log"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	//how many times an item is chained again when another instance has taken its sequence
+	auditAppendAttempts = 5
+	//how often a signed checkpoint of the chain tail is created
+	auditCheckpointsPeriod = time.Hour
+	//the items read per verification page
+	auditVerifyPageSize = 1000
+	//the max reported issues per kind
+	auditVerifyMaxIssues = 100
+)
+
+type checkpoint struct {
+	Sequence  int64     `bson:"sequence"`
+	Hash      string    `bson:"hash"`
+	CreatedAt time.Time `bson:"created_at"`
+	Signature string    `bson:"signature"`
+}
+
+//auditHashData is the hashed item content, the fields are in a fixed order and the maps keys are sorted by the json encoding
+type auditHashData struct {
+	Sequence       int64              `json:"sequence"`
+	PrevHash       string             `json:"prev_hash"`
+	UserIdentifier string             `json:"user_identifier"`
+	UserInfo       string             `json:"user_info"`
+	UsedGroup      string             `json:"used_group"`
+	Entity         string             `json:"entity"`
+	EntityID       string             `json:"entity_id"`
+	Operation      string             `json:"operation"`
+	ClientData     *string            `json:"client_data"`
+	CreatedAt      string             `json:"created_at"`
+	Before         map[string]string  `json:"before"`
+	After          map[string]string  `json:"after"`
+	Changes        []core.AuditChange `json:"changes"`
+}
+
+func hashAuditEntity(entity core.AuditEntity) string {
+	data, _ := json.Marshal(auditHashData{Sequence: entity.Sequence, PrevHash: entity.PrevHash, UserIdentifier: entity.UserIdentifier,
+		UserInfo: entity.UserInfo, UsedGroup: entity.UsedGroup, Entity: entity.Entity, EntityID: entity.EntityID, Operation: entity.Operation,
+		ClientData: entity.ClientData, CreatedAt: entity.CreatedAt.UTC().Format(time.RFC3339Nano), Before: entity.Before, After: entity.After,
+		Changes: entity.Changes})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func (a *Adapter) signCheckpoint(item checkpoint) string {
+	mac := hmac.New(sha256.New, a.signingKey)
+	mac.Write([]byte(fmt.Sprintf("%d:%s:%s", item.Sequence, item.Hash, item.CreatedAt.UTC().Format(time.RFC3339Nano))))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+//findLast gives the item with the highest sequence, nil if the chain is empty
+func (a *Adapter) findLast() (*core.AuditEntity, error) {
+	filter := bson.D{primitive.E{Key: "sequence", Value: bson.M{"$exists": true}}}
+	findOptions := options.Find()
+	findOptions.SetSort(bson.D{primitive.E{Key: "sequence", Value: -1}})
+	findOptions.SetLimit(1)
+	var items []core.AuditEntity
+	err := a.db.audit.Find(filter, &items, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	if len(items) == 0 {
+		return nil, nil
+	}
+	return &items[0], nil
+}
+
+func (a *Adapter) loadTail() error {
+	last, err := a.findLast()
+	if err != nil {
+		return err
+	}
+	if last == nil {
+		a.lastSequence = 0
+		a.lastHash = ""
+		return nil
+	}
+	a.lastSequence = last.Sequence
+	a.lastHash = last.Hash
+	return nil
+}
+
+//appendBatch chains the items to the tail and inserts them in order, it gives how many items are inserted. The tail is reloaded and the rest
+//of the items are chained again if another instance has appended to the chain.
+func (a *Adapter) appendBatch(entities []core.AuditEntity) (int, error) {
+	inserted := 0
+	for attempt := 0; attempt < auditAppendAttempts; attempt++ {
+		pending := entities[inserted:]
+		documents := make([]interface{}, len(pending))
+		prevHash := a.lastHash
+		for i := range pending {
+			pending[i].Sequence = a.lastSequence + int64(i) + 1
+			pending[i].PrevHash = prevHash
+			pending[i].Hash = hashAuditEntity(pending[i])
+			prevHash = pending[i].Hash
+			documents[i] = pending[i]
+		}
+
+		_, err := a.db.audit.InsertMany(documents, options.InsertMany().SetOrdered(true))
+		if err == nil {
+			a.setTail(pending[len(pending)-1])
+			return len(entities), nil
+		}
+
+		//the insert is ordered, so the items before the failed one are inserted
+		count, findErr := a.countInserted(pending)
+		if findErr != nil {
+			return inserted, err
+		}
+		if count > 0 {
+			a.setTail(pending[count-1])
+			inserted += count
+		}
+		if !mongo.IsDuplicateKeyError(err) {
+			return inserted, err
+		}
+
+		err = a.loadTail()
+		if err != nil {
+			return inserted, err
+		}
+	}
+	return inserted, errors.New("cannot chain the audit items, the sequences are taken")
+}
+
+func (a *Adapter) setTail(entity core.AuditEntity) {
+	a.lastSequence = entity.Sequence
+	a.lastHash = entity.Hash
+}
+
+//countInserted gives how many of the chained items are in the chain
+func (a *Adapter) countInserted(entities []core.AuditEntity) (int, error) {
+	filter := bson.D{primitive.E{Key: "sequence", Value: bson.M{"$gte": entities[0].Sequence, "$lte": entities[len(entities)-1].Sequence}}}
+	var items []core.AuditEntity
+	err := a.db.audit.Find(filter, &items, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	hashes := make(map[int64]string, len(items))
+	for _, item := range items {
+		hashes[item.Sequence] = item.Hash
+	}
+	count := 0
+	for _, entity := range entities {
+		if hashes[entity.Sequence] != entity.Hash {
+			break
+		}
+		count++
+	}
+	return count, nil
+}
+
+func (a *Adapter) setupCheckpointsTimer() {
+	a.checkpoint()
+
+	ticker := time.NewTicker(auditCheckpointsPeriod)
+	for range ticker.C {
+		a.checkpoint()
+	}
+}
+
+func (a *Adapter) checkpoint() {
+	err := a.createCheckpoint()
+	if err != nil {
+		log.Printf("error creating audit checkpoint - %s", err.Error())
+	}
+}
+
+//createCheckpoint signs the chain tail if it has changed since the last checkpoint
+func (a *Adapter) createCheckpoint() error {
+	last, err := a.findLast()
+	if err != nil {
+		return err
+	}
+	if last == nil {
+		return nil
+	}
+
+	findOptions := options.Find()
+	findOptions.SetSort(bson.D{primitive.E{Key: "sequence", Value: -1}})
+	findOptions.SetLimit(1)
+	var checkpoints []checkpoint
+	err = a.db.checkpoints.Find(bson.D{}, &checkpoints, findOptions)
+	if err != nil {
+		return err
+	}
+	if len(checkpoints) > 0 && checkpoints[0].Sequence >= last.Sequence {
+		return nil
+	}
+
+	item := checkpoint{Sequence: last.Sequence, Hash: last.Hash, CreatedAt: a.now()}
+	item.Signature = a.signCheckpoint(item)
+	_, err = a.db.checkpoints.InsertOne(&item)
+	if err != nil {
+		return err
+	}
+	log.Printf("Audit - checkpoint for sequence %d", item.Sequence)
+	return nil
+}
+
+//Verify checks the chain items and checkpoints in the sequence range, 0 for no bound
+func (a *Adapter) Verify(fromSequence int64, toSequence int64) (*core.AuditVerification, error) {
+	if fromSequence < 1 {
+		fromSequence = 1
+	}
+	verification := newChainVerification(fromSequence)
+
+	//the first item is linked to the one before the range
+	if fromSequence > 1 {
+		var items []core.AuditEntity
+		err := a.db.audit.Find(bson.D{primitive.E{Key: "sequence", Value: fromSequence - 1}}, &items, nil)
+		if err != nil {
+			return nil, err
+		}
+		if len(items) > 0 {
+			verification.prevHash = &items[0].Hash
+		}
+	}
+
+	for {
+		sequenceFilter := bson.M{"$gte": verification.expected}
+		if toSequence > 0 {
+			sequenceFilter["$lte"] = toSequence
+		}
+		findOptions := options.Find()
+		findOptions.SetSort(bson.D{primitive.E{Key: "sequence", Value: 1}})
+		findOptions.SetLimit(auditVerifyPageSize)
+		var items []core.AuditEntity
+		err := a.db.audit.Find(bson.D{primitive.E{Key: "sequence", Value: sequenceFilter}}, &items, findOptions)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range items {
+			verification.checkItem(item)
+		}
+		if len(items) < auditVerifyPageSize {
+			break
+		}
+	}
+
+	//the checkpoints show if items were removed from the chain tail
+	checkpointsFilter := bson.M{"$gte": fromSequence}
+	if toSequence > 0 {
+		checkpointsFilter["$lte"] = toSequence
+	}
+	var checkpoints []checkpoint
+	err := a.db.checkpoints.Find(bson.D{primitive.E{Key: "sequence", Value: checkpointsFilter}}, &checkpoints, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range checkpoints {
+		//the item is needed only if the checkpoint signature can be checked
+		var chained *core.AuditEntity
+		if len(a.signingKey) > 0 {
+			var items []core.AuditEntity
+			err := a.db.audit.Find(bson.D{primitive.E{Key: "sequence", Value: item.Sequence}}, &items, nil)
+			if err != nil {
+				return nil, err
+			}
+			if len(items) > 0 {
+				chained = &items[0]
+			}
+		}
+		a.checkCheckpoint(&verification.result, item, chained)
+	}
+
+	return verification.finish(), nil
+}
+
+//chainVerification collects the issues of the chain items which are checked in the sequence order
+type chainVerification struct {
+	result   core.AuditVerification
+	expected int64
+	prevHash *string //the hash of the previous item, nil if not known
+}
+
+func newChainVerification(fromSequence int64) *chainVerification {
+	result := core.AuditVerification{FromSequence: fromSequence, Gaps: []core.AuditSequenceRange{}, Modified: []int64{},
+		Unlinked: []int64{}, InvalidCheckpoints: []int64{}, UnverifiedCheckpoints: []int64{}}
+	return &chainVerification{result: result, expected: fromSequence}
+}
+
+//checkItem checks the next chain item
+func (v *chainVerification) checkItem(item core.AuditEntity) {
+	result := &v.result
+	if item.Sequence > v.expected {
+		if len(result.Gaps) < auditVerifyMaxIssues {
+			result.Gaps = append(result.Gaps, core.AuditSequenceRange{From: v.expected, To: item.Sequence - 1})
+		}
+	} else if v.prevHash != nil && item.PrevHash != *v.prevHash && len(result.Unlinked) < auditVerifyMaxIssues {
+		result.Unlinked = append(result.Unlinked, item.Sequence)
+	}
+	if hashAuditEntity(item) != item.Hash && len(result.Modified) < auditVerifyMaxIssues {
+		result.Modified = append(result.Modified, item.Sequence)
+	}
+
+	hash := item.Hash
+	v.prevHash = &hash
+	v.expected = item.Sequence + 1
+	result.Checked++
+}
+
+//finish gives the result after all the items and checkpoints are checked
+func (v *chainVerification) finish() *core.AuditVerification {
+	result := &v.result
+	result.ToSequence = v.expected - 1
+	result.Valid = len(result.Gaps) == 0 && len(result.Modified) == 0 && len(result.Unlinked) == 0 && len(result.InvalidCheckpoints) == 0 &&
+		len(result.UnverifiedCheckpoints) == 0
+	return result
+}
+
+//checkCheckpoint checks the checkpoint signature and that it matches the chain item with its sequence, nil if the item is missing
+func (a *Adapter) checkCheckpoint(result *core.AuditVerification, item checkpoint, chained *core.AuditEntity) {
+	result.Checkpoints++
+
+	//without the key anybody could have written the checkpoint
+	if len(a.signingKey) == 0 {
+		if len(result.UnverifiedCheckpoints) < auditVerifyMaxIssues {
+			result.UnverifiedCheckpoints = append(result.UnverifiedCheckpoints, item.Sequence)
+		}
+		return
+	}
+
+	valid := hmac.Equal([]byte(a.signCheckpoint(item)), []byte(item.Signature)) && chained != nil && chained.Hash == item.Hash
+	if !valid && len(result.InvalidCheckpoints) < auditVerifyMaxIssues {
+		result.InvalidCheckpoints = append(result.InvalidCheckpoints, item.Sequence)
+	}
+}
@@ -0,0 +1,201 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+
+package audit
+
+import (
+	"health/core"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func stringPointer(value string) *string {
+	return &value
+}
+
+func testAuditEntity() core.AuditEntity {
+	return core.AuditEntity{UserIdentifier: "user", UserInfo: "user@example.com", UsedGroup: "admins", Entity: "location",
+		EntityID: "1", Operation: "update", ClientData: stringPointer("ticket 1"), CreatedAt: time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC),
+		Before: map[string]string{"name": "old"}, After: map[string]string{"name": "new"},
+		Changes:  []core.AuditChange{{Field: "name", Before: stringPointer("old"), After: stringPointer("new")}},
+		Sequence: 5, PrevHash: "prev"}
+}
+
+func TestHashAuditEntity(t *testing.T) {
+	base := testAuditEntity()
+	baseHash := hashAuditEntity(base)
+
+	tests := []struct {
+		name   string
+		modify func(entity *core.AuditEntity)
+		same   bool
+	}{
+		{name: "same content", modify: func(entity *core.AuditEntity) {}, same: true},
+		{name: "other time zone", modify: func(entity *core.AuditEntity) {
+			entity.CreatedAt = entity.CreatedAt.In(time.FixedZone("CST", -6*3600))
+		}, same: true},
+		{name: "stored hash is not hashed", modify: func(entity *core.AuditEntity) { entity.Hash = "hash" }, same: true},
+		{name: "legacy data is not hashed", modify: func(entity *core.AuditEntity) { entity.Data = stringPointer("data") }, same: true},
+		{name: "sequence", modify: func(entity *core.AuditEntity) { entity.Sequence = 6 }},
+		{name: "previous hash", modify: func(entity *core.AuditEntity) { entity.PrevHash = "other" }},
+		{name: "user", modify: func(entity *core.AuditEntity) { entity.UserIdentifier = "other" }},
+		{name: "group", modify: func(entity *core.AuditEntity) { entity.UsedGroup = "other" }},
+		{name: "entity id", modify: func(entity *core.AuditEntity) { entity.EntityID = "2" }},
+		{name: "operation", modify: func(entity *core.AuditEntity) { entity.Operation = "delete" }},
+		{name: "client data", modify: func(entity *core.AuditEntity) { entity.ClientData = nil }},
+		{name: "created at", modify: func(entity *core.AuditEntity) { entity.CreatedAt = entity.CreatedAt.Add(time.Nanosecond) }},
+		{name: "before", modify: func(entity *core.AuditEntity) { entity.Before = map[string]string{"name": "older"} }},
+		{name: "after", modify: func(entity *core.AuditEntity) { entity.After = map[string]string{"name": "new", "x": "y"} }},
+		{name: "changes", modify: func(entity *core.AuditEntity) { entity.Changes = nil }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entity := testAuditEntity()
+			tt.modify(&entity)
+			hash := hashAuditEntity(entity)
+			if (hash == baseHash) != tt.same {
+				t.Errorf("hashAuditEntity() same hash %t, expected %t", hash == baseHash, tt.same)
+			}
+		})
+	}
+}
+
+//testChain gives the chained items with the provided sequences
+func testChain(sequences ...int64) []core.AuditEntity {
+	items := make([]core.AuditEntity, len(sequences))
+	prevHash := ""
+	for i, sequence := range sequences {
+		item := testAuditEntity()
+		item.Sequence = sequence
+		item.PrevHash = prevHash
+		item.Hash = hashAuditEntity(item)
+		prevHash = item.Hash
+		items[i] = item
+	}
+	return items
+}
+
+func TestChainVerification(t *testing.T) {
+	tests := []struct {
+		name     string
+		items    []core.AuditEntity
+		modify   func(items []core.AuditEntity)
+		expected core.AuditVerification
+	}{
+		{
+			name:  "valid",
+			items: testChain(1, 2, 3),
+			expected: core.AuditVerification{FromSequence: 1, ToSequence: 3, Checked: 3, Valid: true, Gaps: []core.AuditSequenceRange{},
+				Modified: []int64{}, Unlinked: []int64{}, InvalidCheckpoints: []int64{}, UnverifiedCheckpoints: []int64{}},
+		},
+		{
+			name:  "empty",
+			items: nil,
+			expected: core.AuditVerification{FromSequence: 1, ToSequence: 0, Valid: true, Gaps: []core.AuditSequenceRange{},
+				Modified: []int64{}, Unlinked: []int64{}, InvalidCheckpoints: []int64{}, UnverifiedCheckpoints: []int64{}},
+		},
+		{
+			name:  "missing items",
+			items: testChain(1, 4, 5, 7),
+			expected: core.AuditVerification{FromSequence: 1, ToSequence: 7, Checked: 4, Gaps: []core.AuditSequenceRange{{From: 2, To: 3}, {From: 6, To: 6}},
+				Modified: []int64{}, Unlinked: []int64{}, InvalidCheckpoints: []int64{}, UnverifiedCheckpoints: []int64{}},
+		},
+		{
+			name:   "modified item",
+			items:  testChain(1, 2, 3),
+			modify: func(items []core.AuditEntity) { items[1].After["name"] = "changed" },
+			expected: core.AuditVerification{FromSequence: 1, ToSequence: 3, Checked: 3, Gaps: []core.AuditSequenceRange{},
+				Modified: []int64{2}, Unlinked: []int64{}, InvalidCheckpoints: []int64{}, UnverifiedCheckpoints: []int64{}},
+		},
+		{
+			name:  "replaced item with a new hash",
+			items: testChain(1, 2, 3),
+			modify: func(items []core.AuditEntity) {
+				items[1].After = map[string]string{"name": "changed"}
+				items[1].Hash = hashAuditEntity(items[1])
+			},
+			expected: core.AuditVerification{FromSequence: 1, ToSequence: 3, Checked: 3, Gaps: []core.AuditSequenceRange{},
+				Modified: []int64{}, Unlinked: []int64{3}, InvalidCheckpoints: []int64{}, UnverifiedCheckpoints: []int64{}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.modify != nil {
+				tt.modify(tt.items)
+			}
+			verification := newChainVerification(1)
+			for _, item := range tt.items {
+				verification.checkItem(item)
+			}
+			result := verification.finish()
+			if !reflect.DeepEqual(*result, tt.expected) {
+				t.Errorf("verification = %+v, expected %+v", *result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCheckCheckpoint(t *testing.T) {
+	items := testChain(1, 2)
+	signer := Adapter{signingKey: []byte("key")}
+	signed := func(sequence int64, hash string) checkpoint {
+		item := checkpoint{Sequence: sequence, Hash: hash, CreatedAt: time.Date(2021, 6, 1, 11, 0, 0, 0, time.UTC)}
+		item.Signature = signer.signCheckpoint(item)
+		return item
+	}
+	forged := signed(2, items[1].Hash)
+	forged.Signature = "forged"
+
+	tests := []struct {
+		name       string
+		signingKey string
+		checkpoint checkpoint
+		chained    *core.AuditEntity
+		invalid    bool
+		unverified bool
+	}{
+		{name: "valid", signingKey: "key", checkpoint: signed(2, items[1].Hash), chained: &items[1]},
+		{name: "forged signature", signingKey: "key", checkpoint: forged, chained: &items[1], invalid: true},
+		{name: "other signing key", signingKey: "other", checkpoint: signed(2, items[1].Hash), chained: &items[1], invalid: true},
+		{name: "removed item", signingKey: "key", checkpoint: signed(2, items[1].Hash), chained: nil, invalid: true},
+		{name: "replaced item", signingKey: "key", checkpoint: signed(2, items[1].Hash), chained: &items[0], invalid: true},
+		{name: "no signing key", signingKey: "", checkpoint: signed(2, items[1].Hash), chained: &items[1], unverified: true},
+		{name: "no signing key and forged signature", signingKey: "", checkpoint: forged, chained: &items[1], unverified: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			adapter := Adapter{signingKey: []byte(tt.signingKey)}
+			verification := newChainVerification(1)
+			for _, item := range items {
+				verification.checkItem(item)
+			}
+			adapter.checkCheckpoint(&verification.result, tt.checkpoint, tt.chained)
+			result := verification.finish()
+
+			if result.Checkpoints != 1 {
+				t.Errorf("checkCheckpoint() checkpoints %d", result.Checkpoints)
+			}
+			if (len(result.InvalidCheckpoints) > 0) != tt.invalid || (len(result.UnverifiedCheckpoints) > 0) != tt.unverified {
+				t.Errorf("checkCheckpoint() invalid %v, unverified %v", result.InvalidCheckpoints, result.UnverifiedCheckpoints)
+			}
+			if result.Valid != (!tt.invalid && !tt.unverified) {
+				t.Errorf("checkCheckpoint() valid %t", result.Valid)
+			}
+		})
+	}
+}
@@ -221,6 +221,17 @@ func (collWrapper *collectionWrapper) AddIndex(keys interface{}, unique bool) er
 	return err
 }
 
+func (collWrapper *collectionWrapper) AddPartialIndex(keys interface{}, unique bool, partialFilter interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*15000)
+	defer cancel()
+
+	index := mongo.IndexModel{Keys: keys, Options: options.Index().SetUnique(unique).SetPartialFilterExpression(partialFilter)}
+
+	_, err := collWrapper.coll.Indexes().CreateOne(ctx, index, nil)
+
+	return err
+}
+
 func (collWrapper *collectionWrapper) DropIndex(name string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*15000)
 	defer cancel()
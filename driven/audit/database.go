@@ -19,7 +19,8 @@ type database struct {
 	db       *mongo.Database
 	dbClient *mongo.Client
 
-	audit *collectionWrapper
+	audit       *collectionWrapper
+	checkpoints *collectionWrapper
 }
 
 func (m *database) start() error {
@@ -50,11 +51,18 @@ func (m *database) start() error {
 		return err
 	}
 
+	checkpoints := &collectionWrapper{database: m, coll: db.Collection("audit_checkpoints")}
+	err = m.applyCheckpointsChecks(checkpoints)
+	if err != nil {
+		return err
+	}
+
 	//asign the db, db client and the collection
 	m.db = db
 	m.dbClient = client
 
 	m.audit = audit
+	m.checkpoints = checkpoints
 
 	return nil
 }
@@ -91,7 +99,34 @@ func (m *database) applyAuditChecks(audit *collectionWrapper) error {
 	if err != nil {
 		return err
 	}
+	//for the entity states
+	err = audit.AddIndex(bson.D{primitive.E{Key: "entity", Value: 1}, primitive.E{Key: "entity_id", Value: 1}, primitive.E{Key: "created_at", Value: -1}}, false)
+	if err != nil {
+		return err
+	}
+	err = audit.AddIndex(bson.D{primitive.E{Key: "changes.field", Value: 1}}, false)
+	if err != nil {
+		return err
+	}
+	//the items logged before the hash chain do not have sequence
+	err = audit.AddPartialIndex(bson.D{primitive.E{Key: "sequence", Value: 1}}, true, bson.D{primitive.E{Key: "sequence", Value: bson.M{"$exists": true}}})
+	if err != nil {
+		return err
+	}
 
 	log.Println("audit checks passed")
 	return nil
 }
+
+func (m *database) applyCheckpointsChecks(checkpoints *collectionWrapper) error {
+	log.Println("apply audit checkpoints checks.....")
+
+	//add indexes
+	err := checkpoints.AddIndex(bson.D{primitive.E{Key: "sequence", Value: 1}}, false)
+	if err != nil {
+		return err
+	}
+
+	log.Println("audit checkpoints checks passed")
+	return nil
+}
@@ -0,0 +1,142 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"health/core"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+)
+
+const (
+	//the max items kept on disk
+	auditSpillMaxItems = 100000
+)
+
+//spillBuffer keeps the items on disk while they cannot be written, one json item per line in the order they are logged
+type spillBuffer struct {
+	path  string
+	items int64
+}
+
+//add appends the items to the buffer
+func (s *spillBuffer) add(entities []core.AuditEntity) error {
+	if s.count()+int64(len(entities)) > auditSpillMaxItems {
+		return errors.New("the audit spill buffer is full")
+	}
+
+	file, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	for _, entity := range entities {
+		err = encoder.Encode(entity)
+		if err != nil {
+			return err
+		}
+	}
+	err = file.Sync()
+	if err != nil {
+		return err
+	}
+	atomic.AddInt64(&s.items, int64(len(entities)))
+	return nil
+}
+
+//read gives all the buffered items
+func (s *spillBuffer) read() ([]core.AuditEntity, error) {
+	file, err := os.Open(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	var entities []core.AuditEntity
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var entity core.AuditEntity
+		err = json.Unmarshal(scanner.Bytes(), &entity)
+		if err != nil {
+			return nil, err
+		}
+		entities = append(entities, entity)
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+	return entities, nil
+}
+
+//replace replaces the buffered items, the buffer is removed when there are no items
+func (s *spillBuffer) replace(entities []core.AuditEntity) error {
+	if len(entities) == 0 {
+		err := os.Remove(s.path)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		atomic.StoreInt64(&s.items, 0)
+		return nil
+	}
+
+	tmpPath := s.path + ".tmp"
+	err := os.Remove(tmpPath)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	tmp := spillBuffer{path: tmpPath}
+	err = tmp.add(entities)
+	if err != nil {
+		return err
+	}
+	err = os.Rename(tmpPath, s.path)
+	if err != nil {
+		return err
+	}
+	atomic.StoreInt64(&s.items, int64(len(entities)))
+	return nil
+}
+
+func (s *spillBuffer) count() int64 {
+	return atomic.LoadInt64(&s.items)
+}
+
+//newSpillBuffer creates a spill buffer in the directory, it keeps the items buffered before a restart
+func newSpillBuffer(dir string) (*spillBuffer, error) {
+	err := os.MkdirAll(dir, 0700)
+	if err != nil {
+		return nil, err
+	}
+
+	buffer := spillBuffer{path: filepath.Join(dir, "audit-spill.jsonl")}
+	entities, err := buffer.read()
+	if err != nil {
+		return nil, err
+	}
+	buffer.items = int64(len(entities))
+	return &buffer, nil
+}
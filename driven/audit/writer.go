@@ -0,0 +1,286 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+package audit
+
+import (
+	"errors"
+	"health/core"
+	"log"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	//the max items written at once
+	auditBatchSize = 100
+	//how long the writer waits for more items after the first one of a batch
+	auditBatchWait = 50 * time.Millisecond
+	//how many times a failed write is retried, the delay doubles after every retry
+	auditWriteRetries = 4
+	auditRetryDelay   = 500 * time.Millisecond
+	//how often the spilled items are written again while the audit database is not available
+	auditSpillReplayPeriod = 30 * time.Second
+	//how long the queued items are written on stop
+	auditStopTimeout = 20 * time.Second
+)
+
+var errAuditUnavailable = errors.New("the audit database is not available")
+
+//auditItem is a queued item
+type auditItem struct {
+	entity core.AuditEntity
+	//receives the write result in strict mode
+	done chan error
+}
+
+func (a *Adapter) write() {
+	defer close(a.stopped)
+
+	replayTicker := time.NewTicker(auditSpillReplayPeriod)
+	defer replayTicker.Stop()
+
+	for {
+		batch, open := a.nextBatch(replayTicker.C)
+		if len(batch) > 0 {
+			a.flush(batch)
+		}
+		if !open {
+			return
+		}
+	}
+}
+
+//nextBatch waits for an item and gives it with the items logged shortly after it, false when the queue is closed
+func (a *Adapter) nextBatch(replay <-chan time.Time) ([]auditItem, bool) {
+	var batch []auditItem
+	select {
+	case item, ok := <-a.queue:
+		if !ok {
+			return nil, false
+		}
+		batch = append(batch, item)
+	case <-replay:
+		err := a.replaySpill()
+		if err != nil && err != errAuditUnavailable {
+			log.Printf("error writing the spilled audit items - %s", err.Error())
+		}
+		return nil, true
+	}
+
+	timer := time.NewTimer(auditBatchWait)
+	defer timer.Stop()
+	for len(batch) < auditBatchSize {
+		select {
+		case item, ok := <-a.queue:
+			if !ok {
+				return batch, false
+			}
+			batch = append(batch, item)
+		case <-timer.C:
+			return batch, true
+		}
+	}
+	return batch, true
+}
+
+//flush writes the batch, the items which cannot be written are spilled
+func (a *Adapter) flush(batch []auditItem) {
+	entities := make([]core.AuditEntity, len(batch))
+	for i, item := range batch {
+		entities[i] = item.entity
+	}
+
+	//the spilled items are older, so the batch waits for them to keep the chain in order
+	err := a.replaySpill()
+	inserted := 0
+	if err == nil {
+		inserted, err = a.writeEntities(entities)
+	}
+	atomic.AddInt64(&a.written, int64(inserted))
+	if err != nil {
+		a.setLastError(err)
+		a.keep(entities[inserted:], err)
+	}
+
+	for i, item := range batch {
+		if item.done == nil {
+			continue
+		}
+		if i < inserted {
+			item.done <- nil
+		} else {
+			item.done <- err
+		}
+	}
+}
+
+//keep spills the items which cannot be written, they are lost if there is no spill buffer
+func (a *Adapter) keep(entities []core.AuditEntity, writeErr error) {
+	if a.spill == nil {
+		atomic.AddInt64(&a.dropped, int64(len(entities)))
+		log.Printf("error audit logging, %d items are lost - %s", len(entities), writeErr.Error())
+		return
+	}
+
+	err := a.spill.add(entities)
+	if err != nil {
+		atomic.AddInt64(&a.dropped, int64(len(entities)))
+		log.Printf("error spilling %d audit items, they are lost - %s", len(entities), err.Error())
+		return
+	}
+	if writeErr != errAuditUnavailable {
+		log.Printf("error audit logging, %d items are spilled - %s", len(entities), writeErr.Error())
+	}
+}
+
+//writeEntities prepares the changes and chains the items, the failed writes are retried. It gives how many items are inserted.
+func (a *Adapter) writeEntities(entities []core.AuditEntity) (int, error) {
+	prepared := false
+	inserted := 0
+	delay := auditRetryDelay
+	var err error
+	for attempt := 0; attempt <= auditWriteRetries; attempt++ {
+		if attempt > 0 {
+			log.Printf("error writing %d audit items, retry in %s - %s", len(entities)-inserted, delay, err.Error())
+			atomic.AddInt64(&a.retries, 1)
+			time.Sleep(delay)
+			delay *= 2
+		}
+
+		if !prepared {
+			err = a.prepareChanges(entities)
+			if err != nil {
+				continue
+			}
+			prepared = true
+		}
+
+		var count int
+		count, err = a.appendBatch(entities[inserted:])
+		inserted += count
+		if err == nil {
+			return inserted, nil
+		}
+	}
+	return inserted, err
+}
+
+//prepareChanges sets the before snapshots and the changes of the updated and deleted entities. The callers log only the fields they change,
+//so the updates are compared only by their fields with the entity state merged from its previous items. The entities in the batch use
+//the state with the previous items for the same entity as they are not written yet.
+func (a *Adapter) prepareChanges(entities []core.AuditEntity) error {
+	states := map[string]map[string]string{}
+	for i := range entities {
+		entity := &entities[i]
+		if len(entity.EntityID) == 0 {
+			continue
+		}
+
+		key := entity.Entity + "/" + entity.EntityID
+		state, ok := states[key]
+		if !ok && (entity.Operation == "update" || entity.Operation == "delete") {
+			var err error
+			state, err = a.findState(entity.Entity, entity.EntityID)
+			if err != nil {
+				return err
+			}
+		}
+
+		switch entity.Operation {
+		case "create":
+			state = mergeSnapshot(nil, entity.After)
+		case "update":
+			entity.Before = pickSnapshot(state, entity.After)
+			entity.Changes = a.diff(entity.Before, entity.After)
+			state = mergeSnapshot(state, entity.After)
+		case "delete":
+			entity.Before = state
+			entity.Changes = a.diff(state, nil)
+			state = nil
+		}
+		states[key] = state
+	}
+	return nil
+}
+
+//replaySpill writes the spilled items, it is not attempted again for a while after failing
+func (a *Adapter) replaySpill() error {
+	if a.spill == nil || a.spill.count() == 0 {
+		return nil
+	}
+	if time.Now().Before(a.replayAfter) {
+		return errAuditUnavailable
+	}
+
+	entities, err := a.spill.read()
+	if err != nil {
+		return err
+	}
+	log.Printf("Audit - writing %d spilled items", len(entities))
+
+	for len(entities) > 0 {
+		size := auditBatchSize
+		if len(entities) < size {
+			size = len(entities)
+		}
+
+		inserted, err := a.writeEntities(entities[:size])
+		atomic.AddInt64(&a.written, int64(inserted))
+		entities = entities[inserted:]
+		if err != nil {
+			a.setLastError(err)
+			a.replayAfter = time.Now().Add(auditSpillReplayPeriod)
+			replaceErr := a.spill.replace(entities)
+			if replaceErr != nil {
+				log.Printf("error updating the audit spill buffer - %s", replaceErr.Error())
+			}
+			return errAuditUnavailable
+		}
+	}
+	return a.spill.replace(nil)
+}
+
+func (a *Adapter) setLastError(err error) {
+	a.lastErrorLock.Lock()
+	defer a.lastErrorLock.Unlock()
+
+	message := err.Error()
+	now := time.Now().UTC()
+	a.lastError = &message
+	a.lastErrorAt = &now
+}
+
+//Stop stops accepting items and waits for the queued items to be written or spilled
+func (a *Adapter) Stop() {
+	a.queueLock.Lock()
+	if a.closed {
+		a.queueLock.Unlock()
+		return
+	}
+	a.closed = true
+	close(a.queue)
+	a.queueLock.Unlock()
+
+	timer := time.NewTimer(auditStopTimeout)
+	defer timer.Stop()
+	select {
+	case <-a.stopped:
+		log.Println("Audit - stopped")
+	case <-timer.C:
+		log.Printf("Audit - stopped before writing %d queued items", len(a.queue))
+	}
+}
@@ -0,0 +1,108 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+package cache
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	redisTimeout = 2 * time.Second
+	//the keys scanned per SCAN call when deleting by prefix
+	redisScanCount = 500
+)
+
+//RedisAdapter implements the users cache with a Redis compatible server, so the service replicas share it.
+//The client keeps a pool of connections which are reopened after failures.
+type RedisAdapter struct {
+	client *redis.Client
+}
+
+//Get gives the cached value, nil if the key is not cached
+func (ra *RedisAdapter) Get(key string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+
+	value, err := ra.client.Get(ctx, key).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+//Set caches the value for the ttl period
+func (ra *RedisAdapter) Set(key string, value []byte, ttl time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+
+	return ra.client.Set(ctx, key, value, ttl).Err()
+}
+
+//Delete removes the keys
+func (ra *RedisAdapter) Delete(keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+	defer cancel()
+
+	return ra.client.Del(ctx, keys...).Err()
+}
+
+//DeletePrefix removes all keys starting with the prefix
+func (ra *RedisAdapter) DeletePrefix(prefix string) error {
+	var cursor uint64
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), redisTimeout)
+		keys, next, err := ra.client.Scan(ctx, cursor, prefix+"*", redisScanCount).Result()
+		cancel()
+		if err != nil {
+			return err
+		}
+
+		err = ra.Delete(keys)
+		if err != nil {
+			return err
+		}
+
+		cursor = next
+		if cursor == 0 {
+			return nil
+		}
+	}
+}
+
+//NewRedisAdapter creates a new Redis cache adapter instance. The url format is redis://[:password@]host:port[/database], rediss:// for TLS
+func NewRedisAdapter(redisURL string) *RedisAdapter {
+	options, err := redis.ParseURL(redisURL)
+	if err != nil {
+		log.Fatalf("Not valid redis url - %s", err)
+	}
+	options.DialTimeout = redisTimeout
+	options.ReadTimeout = redisTimeout
+	options.WriteTimeout = redisTimeout
+
+	return &RedisAdapter{client: redis.NewClient(options)}
+}
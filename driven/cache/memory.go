@@ -0,0 +1,90 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+
+package cache
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+//MemoryAdapter implements the users cache in the process memory. It stands in for the Redis adapter
+//in development and tests - the instances which share it behave as replicas sharing a Redis server.
+type MemoryAdapter struct {
+	lock  *sync.Mutex
+	items map[string]memoryItem
+}
+
+type memoryItem struct {
+	value   []byte
+	expires time.Time
+}
+
+//Get gives the cached value, nil if the key is not cached
+func (ma *MemoryAdapter) Get(key string) ([]byte, error) {
+	ma.lock.Lock()
+	defer ma.lock.Unlock()
+
+	item, ok := ma.items[key]
+	if !ok {
+		return nil, nil
+	}
+	if time.Now().After(item.expires) {
+		delete(ma.items, key)
+		return nil, nil
+	}
+	return item.value, nil
+}
+
+//Set caches the value for the ttl period
+func (ma *MemoryAdapter) Set(key string, value []byte, ttl time.Duration) error {
+	ma.lock.Lock()
+	defer ma.lock.Unlock()
+
+	ma.items[key] = memoryItem{value: value, expires: time.Now().Add(ttl)}
+	return nil
+}
+
+//Delete removes the keys
+func (ma *MemoryAdapter) Delete(keys []string) error {
+	ma.lock.Lock()
+	defer ma.lock.Unlock()
+
+	for _, key := range keys {
+		delete(ma.items, key)
+	}
+	return nil
+}
+
+//DeletePrefix removes all keys starting with the prefix
+func (ma *MemoryAdapter) DeletePrefix(prefix string) error {
+	ma.lock.Lock()
+	defer ma.lock.Unlock()
+
+	for key := range ma.items {
+		if strings.HasPrefix(key, prefix) {
+			delete(ma.items, key)
+		}
+	}
+	return nil
+}
+
+//NewMemoryAdapter creates a new memory cache adapter instance
+func NewMemoryAdapter() *MemoryAdapter {
+	return &MemoryAdapter{lock: &sync.Mutex{}, items: map[string]memoryItem{}}
+}
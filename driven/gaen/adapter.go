@@ -0,0 +1,171 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+
+package gaen
+
+import (
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"health/core/model"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+)
+
+//Adapter implements the exposure notification port. It verifies the keys upload certificates issued by
+//a verification server and builds the signed exports in the format expected by the GAEN framework.
+type Adapter struct {
+	verificationKey *ecdsa.PublicKey
+	issuer          string
+	audience        string
+
+	signingKey *ecdsa.PrivateKey
+	keyID      string
+	keyVersion string
+	region     string
+}
+
+type verificationClaims struct {
+	ReportType           string `json:"reportType"`
+	SymptomOnsetInterval uint32 `json:"symptomOnsetInterval"`
+	SignedMAC            string `json:"tekmac"`
+	jwt.StandardClaims
+}
+
+//VerifyCertificate verifies the certificate and that it has been issued for the provided keys
+func (a *Adapter) VerifyCertificate(certificate string, hmacKey string, keys []model.TraceExposure) (*model.ExposureVerification, error) {
+	if a.verificationKey == nil {
+		return nil, errors.New("the exposure keys verification is not configured")
+	}
+
+	claims := verificationClaims{}
+	token, err := jwt.ParseWithClaims(certificate, &claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodECDSA); !ok {
+			return nil, fmt.Errorf("unexpected signing method %v", token.Header["alg"])
+		}
+		return a.verificationKey, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !token.Valid {
+		return nil, errors.New("the verification certificate is not valid")
+	}
+	if !claims.VerifyIssuer(a.issuer, true) {
+		return nil, errors.New("the verification certificate has a wrong issuer")
+	}
+	if !claims.VerifyAudience(a.audience, true) {
+		return nil, errors.New("the verification certificate has a wrong audience")
+	}
+	//the certificate is used once, the used ones are kept until they expire so it must expire
+	if !claims.VerifyExpiresAt(time.Now().Unix(), true) {
+		return nil, errors.New("the verification certificate has no expiration or it has expired")
+	}
+
+	//the certificate is bound to the uploaded keys through the hmac
+	secret, err := base64.StdEncoding.DecodeString(hmacKey)
+	if err != nil {
+		return nil, errors.New("the hmac key is not valid base64")
+	}
+	signedMAC, err := base64.StdEncoding.DecodeString(claims.SignedMAC)
+	if err != nil {
+		return nil, errors.New("the certificate tekmac is not valid base64")
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(keysMACMessage(keys)))
+	if !hmac.Equal(mac.Sum(nil), signedMAC) {
+		return nil, errors.New("the verification certificate is not issued for the provided keys")
+	}
+
+	var reportType string
+	switch claims.ReportType {
+	case "confirmed":
+		reportType = model.ExposureReportTypeConfirmed
+	case "likely":
+		reportType = model.ExposureReportTypeLikely
+	default:
+		return nil, fmt.Errorf("not supported report type %s", claims.ReportType)
+	}
+
+	verification := model.ExposureVerification{ReportType: reportType, ExpiresAt: time.Unix(claims.ExpiresAt, 0)}
+	if claims.SymptomOnsetInterval > 0 {
+		onset := int32(claims.SymptomOnsetInterval)
+		verification.SymptomOnsetInterval = &onset
+	}
+	return &verification, nil
+}
+
+//keysMACMessage gives the message the verification server signs - the keys sorted by the key data
+//in the format key.rollingStartNumber.rollingPeriod.transmissionRisk and joined by comma
+func keysMACMessage(keys []model.TraceExposure) string {
+	items := make([]string, len(keys))
+	for i, key := range keys {
+		items[i] = fmt.Sprintf("%s.%d.%d.%d", key.TEK, int32Value(key.RollingStartNumber),
+			int32Value(key.RollingPeriod), int32Value(key.TransmissionRisk))
+	}
+	sort.Strings(items)
+	return strings.Join(items, ",")
+}
+
+func int32Value(value *int32) int32 {
+	if value == nil {
+		return 0
+	}
+	return *value
+}
+
+//NewGAENAdapter creates a new gaen adapter instance. The verification key is the PEM public key of the verification server
+//and the signing key is the PEM private key used for signing the exports. Both are optional.
+func NewGAENAdapter(verificationKey string, issuer string, audience string,
+	signingKey string, keyID string, keyVersion string, region string) *Adapter {
+	adapter := Adapter{issuer: issuer, audience: audience, keyID: keyID, keyVersion: keyVersion, region: region}
+
+	if len(verificationKey) > 0 {
+		publicKey, err := jwt.ParseECPublicKeyFromPEM([]byte(verificationKey))
+		if err != nil {
+			log.Fatalf("error parsing the gaen verification key: %v\n", err)
+			return nil
+		}
+		if len(issuer) == 0 || len(audience) == 0 {
+			log.Fatal(errors.New("gaen verification issuer and audience are required"))
+			return nil
+		}
+		adapter.verificationKey = publicKey
+	}
+
+	if len(signingKey) > 0 {
+		privateKey, err := jwt.ParseECPrivateKeyFromPEM([]byte(signingKey))
+		if err != nil {
+			log.Fatalf("error parsing the gaen export signing key: %v\n", err)
+			return nil
+		}
+		if len(keyID) == 0 || len(keyVersion) == 0 || len(region) == 0 {
+			log.Fatal(errors.New("gaen export key id, key version and region are required"))
+			return nil
+		}
+		adapter.signingKey = privateKey
+	}
+
+	return &adapter
+}
@@ -0,0 +1,196 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+
+package gaen
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"health/core/model"
+	"math/big"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+const (
+	exportBinName = "export.bin"
+	exportSigName = "export.sig"
+
+	//the export file starts with a fixed 16 bytes header
+	exportHeader = "EK Export v1    "
+
+	//ECDSA with SHA-256
+	signatureAlgorithm = "1.2.840.10045.4.3.2"
+
+	//report type values from the export proto
+	protoReportTypeConfirmedTest     = 1
+	protoReportTypeClinicalDiagnosis = 2
+)
+
+//CreateExport creates a zip export archive containing the export.bin and export.sig files for the provided keys
+func (a *Adapter) CreateExport(keys []model.TraceExposure, start time.Time, end time.Time, batchNum int, batchSize int) ([]byte, error) {
+	if a.signingKey == nil {
+		return nil, errors.New("the exposure keys export is not configured")
+	}
+
+	//the keys are shuffled so that the keys uploaded together cannot be linked by their position
+	shuffled, err := shuffleKeys(keys)
+	if err != nil {
+		return nil, err
+	}
+
+	exportBin, err := a.encodeExport(shuffled, start, end, batchNum, batchSize)
+	if err != nil {
+		return nil, err
+	}
+
+	digest := sha256.Sum256(exportBin)
+	signature, err := ecdsa.SignASN1(rand.Reader, a.signingKey, digest[:])
+	if err != nil {
+		return nil, err
+	}
+	exportSig := a.encodeSignatureList(signature, batchNum, batchSize)
+
+	var buffer bytes.Buffer
+	zipWriter := zip.NewWriter(&buffer)
+	files := []struct {
+		name string
+		data []byte
+	}{{exportBinName, exportBin}, {exportSigName, exportSig}}
+	for _, file := range files {
+		writer, err := zipWriter.Create(file.name)
+		if err != nil {
+			return nil, err
+		}
+		_, err = writer.Write(file.data)
+		if err != nil {
+			return nil, err
+		}
+	}
+	err = zipWriter.Close()
+	if err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+//shuffleKeys gives a copy of the keys in a random order
+func shuffleKeys(keys []model.TraceExposure) ([]model.TraceExposure, error) {
+	shuffled := make([]model.TraceExposure, len(keys))
+	copy(shuffled, keys)
+	for i := len(shuffled) - 1; i > 0; i-- {
+		j, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			return nil, err
+		}
+		shuffled[i], shuffled[j.Int64()] = shuffled[j.Int64()], shuffled[i]
+	}
+	return shuffled, nil
+}
+
+//encodeExport encodes the TemporaryExposureKeyExport message prefixed with the export header
+func (a *Adapter) encodeExport(keys []model.TraceExposure, start time.Time, end time.Time, batchNum int, batchSize int) ([]byte, error) {
+	b := []byte(exportHeader)
+	b = protowire.AppendTag(b, 1, protowire.Fixed64Type)
+	b = protowire.AppendFixed64(b, uint64(start.Unix()))
+	b = protowire.AppendTag(b, 2, protowire.Fixed64Type)
+	b = protowire.AppendFixed64(b, uint64(end.Unix()))
+	b = protowire.AppendTag(b, 3, protowire.BytesType)
+	b = protowire.AppendString(b, a.region)
+	b = protowire.AppendTag(b, 4, protowire.VarintType)
+	b = protowire.AppendVarint(b, uint64(batchNum))
+	b = protowire.AppendTag(b, 5, protowire.VarintType)
+	b = protowire.AppendVarint(b, uint64(batchSize))
+	b = protowire.AppendTag(b, 6, protowire.BytesType)
+	b = protowire.AppendBytes(b, a.encodeSignatureInfo())
+	for _, key := range keys {
+		encodedKey, err := encodeKey(key)
+		if err != nil {
+			return nil, err
+		}
+		b = protowire.AppendTag(b, 7, protowire.BytesType)
+		b = protowire.AppendBytes(b, encodedKey)
+	}
+	return b, nil
+}
+
+//encodeKey encodes the TemporaryExposureKey message
+func encodeKey(key model.TraceExposure) ([]byte, error) {
+	keyData, err := base64.StdEncoding.DecodeString(key.TEK)
+	if err != nil {
+		return nil, err
+	}
+
+	var b []byte
+	b = protowire.AppendTag(b, 1, protowire.BytesType)
+	b = protowire.AppendBytes(b, keyData)
+	b = protowire.AppendTag(b, 2, protowire.VarintType)
+	b = protowire.AppendVarint(b, uint64(int32Value(key.TransmissionRisk)))
+	b = protowire.AppendTag(b, 3, protowire.VarintType)
+	b = protowire.AppendVarint(b, uint64(int32Value(key.RollingStartNumber)))
+	b = protowire.AppendTag(b, 4, protowire.VarintType)
+	b = protowire.AppendVarint(b, uint64(int32Value(key.RollingPeriod)))
+	if key.ReportType != nil {
+		reportType := protoReportTypeConfirmedTest
+		if *key.ReportType == model.ExposureReportTypeLikely {
+			reportType = protoReportTypeClinicalDiagnosis
+		}
+		b = protowire.AppendTag(b, 5, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(reportType))
+	}
+	if key.DaysSinceOnset != nil {
+		b = protowire.AppendTag(b, 6, protowire.VarintType)
+		b = protowire.AppendVarint(b, protowire.EncodeZigZag(int64(*key.DaysSinceOnset)))
+	}
+	return b, nil
+}
+
+//encodeSignatureInfo encodes the SignatureInfo message
+func (a *Adapter) encodeSignatureInfo() []byte {
+	var b []byte
+	b = protowire.AppendTag(b, 3, protowire.BytesType)
+	b = protowire.AppendString(b, a.keyVersion)
+	b = protowire.AppendTag(b, 4, protowire.BytesType)
+	b = protowire.AppendString(b, a.keyID)
+	b = protowire.AppendTag(b, 5, protowire.BytesType)
+	b = protowire.AppendString(b, signatureAlgorithm)
+	return b
+}
+
+//encodeSignatureList encodes the TEKSignatureList message with a single TEKSignature
+func (a *Adapter) encodeSignatureList(signature []byte, batchNum int, batchSize int) []byte {
+	var s []byte
+	s = protowire.AppendTag(s, 1, protowire.BytesType)
+	s = protowire.AppendBytes(s, a.encodeSignatureInfo())
+	s = protowire.AppendTag(s, 2, protowire.VarintType)
+	s = protowire.AppendVarint(s, uint64(batchNum))
+	s = protowire.AppendTag(s, 3, protowire.VarintType)
+	s = protowire.AppendVarint(s, uint64(batchSize))
+	s = protowire.AppendTag(s, 4, protowire.BytesType)
+	s = protowire.AppendBytes(s, signature)
+
+	var b []byte
+	b = protowire.AppendTag(b, 1, protowire.BytesType)
+	b = protowire.AppendBytes(b, s)
+	return b
+}
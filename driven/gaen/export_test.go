@@ -0,0 +1,193 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+
+package gaen
+
+import (
+	"bytes"
+	"health/core/model"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func int32Pointer(value int32) *int32 {
+	return &value
+}
+
+func stringPointer(value string) *string {
+	return &value
+}
+
+//the key data is the bytes 0..15
+const testKeyData = "AAECAwQFBgcICQoLDA0ODw=="
+
+var testKeyBytes = []byte{0x0a, 0x10, 0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f}
+
+func TestEncodeKey(t *testing.T) {
+	tests := []struct {
+		name     string
+		key      model.TraceExposure
+		expected []byte
+		wantErr  bool
+	}{
+		{
+			name:     "no optional fields",
+			key:      model.TraceExposure{TEK: testKeyData},
+			expected: append(append([]byte{}, testKeyBytes...), 0x10, 0x00, 0x18, 0x00, 0x20, 0x00),
+		},
+		{
+			name: "all fields",
+			key: model.TraceExposure{TEK: testKeyData, TransmissionRisk: int32Pointer(4), RollingStartNumber: int32Pointer(2650000),
+				RollingPeriod: int32Pointer(144), ReportType: stringPointer(model.ExposureReportTypeConfirmed), DaysSinceOnset: int32Pointer(3)},
+			expected: append(append([]byte{}, testKeyBytes...), 0x10, 0x04, 0x18, 0x90, 0xdf, 0xa1, 0x01, 0x20, 0x90, 0x01, 0x28, 0x01, 0x30, 0x06),
+		},
+		{
+			name:     "likely report type and negative days since onset",
+			key:      model.TraceExposure{TEK: testKeyData, ReportType: stringPointer(model.ExposureReportTypeLikely), DaysSinceOnset: int32Pointer(-2)},
+			expected: append(append([]byte{}, testKeyBytes...), 0x10, 0x00, 0x18, 0x00, 0x20, 0x00, 0x28, 0x02, 0x30, 0x03),
+		},
+		{
+			name:    "bad key data",
+			key:     model.TraceExposure{TEK: "not base64!"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := encodeKey(tt.key)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("encodeKey() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !bytes.Equal(result, tt.expected) {
+				t.Errorf("encodeKey() = %x, expected %x", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestEncodeExport(t *testing.T) {
+	adapter := Adapter{region: "US", keyID: "310", keyVersion: "v1"}
+	start := time.Unix(1600000000, 0)
+	end := time.Unix(1600086400, 0)
+
+	tests := []struct {
+		name      string
+		keys      []model.TraceExposure
+		batchNum  int
+		batchSize int
+		wantErr   bool
+	}{
+		{name: "no keys", keys: nil, batchNum: 1, batchSize: 1},
+		{name: "two keys", keys: []model.TraceExposure{{TEK: testKeyData}, {TEK: testKeyData, TransmissionRisk: int32Pointer(2)}},
+			batchNum: 2, batchSize: 3},
+		{name: "bad key", keys: []model.TraceExposure{{TEK: testKeyData}, {TEK: "not base64!"}}, batchNum: 1, batchSize: 1, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := adapter.encodeExport(tt.keys, start, end, tt.batchNum, tt.batchSize)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("encodeExport() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !bytes.HasPrefix(result, []byte(exportHeader)) {
+				t.Fatalf("encodeExport() does not start with the header")
+			}
+
+			//read the fields after the header
+			fixed := map[protowire.Number]uint64{}
+			varints := map[protowire.Number]uint64{}
+			messages := map[protowire.Number][][]byte{}
+			b := result[len(exportHeader):]
+			for len(b) > 0 {
+				number, wireType, n := protowire.ConsumeTag(b)
+				if n < 0 {
+					t.Fatalf("bad tag - %v", protowire.ParseError(n))
+				}
+				b = b[n:]
+				switch wireType {
+				case protowire.Fixed64Type:
+					fixed[number], n = protowire.ConsumeFixed64(b)
+				case protowire.VarintType:
+					varints[number], n = protowire.ConsumeVarint(b)
+				case protowire.BytesType:
+					var value []byte
+					value, n = protowire.ConsumeBytes(b)
+					messages[number] = append(messages[number], value)
+				default:
+					t.Fatalf("unexpected wire type %d", wireType)
+				}
+				if n < 0 {
+					t.Fatalf("bad value - %v", protowire.ParseError(n))
+				}
+				b = b[n:]
+			}
+
+			if fixed[1] != uint64(start.Unix()) || fixed[2] != uint64(end.Unix()) {
+				t.Errorf("encodeExport() timestamps = %d %d", fixed[1], fixed[2])
+			}
+			if len(messages[3]) != 1 || string(messages[3][0]) != adapter.region {
+				t.Errorf("encodeExport() region = %q", messages[3])
+			}
+			if varints[4] != uint64(tt.batchNum) || varints[5] != uint64(tt.batchSize) {
+				t.Errorf("encodeExport() batch = %d/%d", varints[4], varints[5])
+			}
+			if len(messages[6]) != 1 || !bytes.Equal(messages[6][0], adapter.encodeSignatureInfo()) {
+				t.Errorf("encodeExport() signature info = %x", messages[6])
+			}
+			if len(messages[7]) != len(tt.keys) {
+				t.Fatalf("encodeExport() keys count = %d, expected %d", len(messages[7]), len(tt.keys))
+			}
+			for i, key := range tt.keys {
+				expected, _ := encodeKey(key)
+				if !bytes.Equal(messages[7][i], expected) {
+					t.Errorf("encodeExport() key %d = %x, expected %x", i, messages[7][i], expected)
+				}
+			}
+		})
+	}
+}
+
+func TestShuffleKeys(t *testing.T) {
+	keys := make([]model.TraceExposure, 50)
+	for i := range keys {
+		keys[i] = model.TraceExposure{Timestamp: int64(i)}
+	}
+
+	shuffled, err := shuffleKeys(keys)
+	if err != nil {
+		t.Fatalf("shuffleKeys() error = %v", err)
+	}
+	if len(shuffled) != len(keys) {
+		t.Fatalf("shuffleKeys() count = %d, expected %d", len(shuffled), len(keys))
+	}
+	seen := map[int64]bool{}
+	for _, key := range shuffled {
+		seen[key.Timestamp] = true
+	}
+	if len(seen) != len(keys) {
+		t.Errorf("shuffleKeys() lost keys - %d unique", len(seen))
+	}
+	for i, key := range keys {
+		if key.Timestamp != int64(i) {
+			t.Fatalf("shuffleKeys() changed the provided keys")
+		}
+	}
+}
@@ -0,0 +1,173 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+
+package messaging
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+)
+
+const (
+	apnsProductionHost  = "https://api.push.apple.com"
+	apnsDevelopmentHost = "https://api.sandbox.push.apple.com"
+
+	//Apple rejects provider tokens older than one hour and throttles too frequent refreshes
+	apnsTokenLifetime = 50 * time.Minute
+)
+
+//APNsAdapter implements messaging by sending directly to the Apple Push Notification service
+type APNsAdapter struct {
+	host      string
+	keyID     string
+	teamID    string
+	topic     string
+	key       *ecdsa.PrivateKey
+	client    *http.Client
+	tokenLock *sync.Mutex
+	token     string
+	tokenDate time.Time
+}
+
+type apnsAlert struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+type apnsAps struct {
+	Alert            apnsAlert `json:"alert"`
+	Sound            string    `json:"sound"`
+	ContentAvailable int       `json:"content-available"`
+}
+
+//SendNotificationMessage send a notification message
+func (a *APNsAdapter) SendNotificationMessage(tokens []string, title string, body string, data map[string]string) {
+	if len(tokens) <= 0 {
+		log.Println("SendNotificationMessage -> cannot send messages without tokens")
+		return
+	}
+
+	payload, err := a.preparePayload(title, body, data)
+	if err != nil {
+		log.Printf("Error preparing apns payload - %s\n", err)
+		return
+	}
+
+	for _, token := range tokens {
+		go func(token string) {
+			err := a.send(token, payload)
+			if err != nil {
+				log.Printf("Error sending apns notification message - %s\n", err)
+				return
+			}
+			log.Println("Successfully sent apns notification message")
+		}(token)
+	}
+}
+
+func (a *APNsAdapter) preparePayload(title string, body string, data map[string]string) ([]byte, error) {
+	//the custom data goes next to the aps dictionary as the mobile app expects it from firebase
+	payload := make(map[string]interface{}, len(data)+1)
+	for key, value := range data {
+		payload[key] = value
+	}
+	payload["aps"] = apnsAps{Alert: apnsAlert{Title: title, Body: body}, Sound: "default", ContentAvailable: 1}
+	return json.Marshal(payload)
+}
+
+func (a *APNsAdapter) send(deviceToken string, payload []byte) error {
+	authToken, err := a.getAuthToken()
+	if err != nil {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/3/device/%s", a.host, deviceToken)
+	req, err := http.NewRequest("POST", url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("authorization", "bearer "+authToken)
+	req.Header.Set("apns-topic", a.topic)
+	req.Header.Set("apns-push-type", "alert")
+	req.Header.Set("apns-priority", "10")
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("apns error %d - %s", resp.StatusCode, string(respBody))
+	}
+	return nil
+}
+
+func (a *APNsAdapter) getAuthToken() (string, error) {
+	a.tokenLock.Lock()
+	defer a.tokenLock.Unlock()
+
+	now := time.Now()
+	if len(a.token) > 0 && now.Sub(a.tokenDate) < apnsTokenLifetime {
+		return a.token, nil
+	}
+
+	jwtToken := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{"iss": a.teamID, "iat": now.Unix()})
+	jwtToken.Header["kid"] = a.keyID
+	signed, err := jwtToken.SignedString(a.key)
+	if err != nil {
+		return "", err
+	}
+
+	a.token = signed
+	a.tokenDate = now
+	return a.token, nil
+}
+
+//NewAPNsAdapter creates a new apns adapter instance. The key is the content of the .p8 auth key file.
+func NewAPNsAdapter(key string, keyID string, teamID string, topic string, production bool) *APNsAdapter {
+	if len(keyID) == 0 || len(teamID) == 0 || len(topic) == 0 {
+		log.Fatal(errors.New("apns key id, team id and topic are required"))
+		return nil
+	}
+
+	privateKey, err := jwt.ParseECPrivateKeyFromPEM([]byte(key))
+	if err != nil {
+		log.Fatalf("error parsing the apns auth key: %v\n", err)
+		return nil
+	}
+
+	host := apnsDevelopmentHost
+	if production {
+		host = apnsProductionHost
+	}
+
+	return &APNsAdapter{host: host, keyID: keyID, teamID: teamID, topic: topic, key: privateKey,
+		client: &http.Client{Timeout: 30 * time.Second}, tokenLock: &sync.Mutex{}}
+}
@@ -0,0 +1,143 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+
+package messaging
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+//the last recorded messages which are kept in memory
+const sinkMaxMessages = 1000
+
+//SentMessage is a message recorded by the sink adapter
+type SentMessage struct {
+	Tokens []string          `json:"tokens"`
+	Title  string            `json:"title"`
+	Body   string            `json:"body"`
+	Data   map[string]string `json:"data"`
+	SentAt time.Time         `json:"sent_at"`
+}
+
+//SinkAdapter implements messaging without delivering anything. It is meant for development and
+//integration tests - every message is appended as a json line to a file or the log and the last messages are kept in memory.
+//The test harnesses read and clear the kept messages through the sink server.
+type SinkAdapter struct {
+	filePath string
+
+	lock     *sync.RWMutex
+	messages []SentMessage
+}
+
+//SendNotificationMessage send a notification message
+func (sa *SinkAdapter) SendNotificationMessage(tokens []string, title string, body string, data map[string]string) {
+	message := SentMessage{Tokens: tokens, Title: title, Body: body, Data: data, SentAt: time.Now().UTC()}
+
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	if len(sa.messages) >= sinkMaxMessages {
+		//drop the oldest one
+		copy(sa.messages, sa.messages[1:])
+		sa.messages = sa.messages[:len(sa.messages)-1]
+	}
+	sa.messages = append(sa.messages, message)
+
+	line, err := json.Marshal(message)
+	if err != nil {
+		log.Printf("Error on marshal the sink message - %s\n", err)
+		return
+	}
+	if len(sa.filePath) == 0 {
+		log.Printf("SinkAdapter -> %s\n", line)
+		return
+	}
+
+	file, err := os.OpenFile(sa.filePath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		log.Printf("Error opening the sink file - %s\n", err)
+		return
+	}
+	defer file.Close()
+	_, err = file.Write(append(line, '\n'))
+	if err != nil {
+		log.Printf("Error writing to the sink file - %s\n", err)
+	}
+}
+
+//GetMessages gives the recorded messages, the oldest first
+func (sa *SinkAdapter) GetMessages() []SentMessage {
+	sa.lock.RLock()
+	defer sa.lock.RUnlock()
+
+	result := make([]SentMessage, len(sa.messages))
+	copy(result, sa.messages)
+	return result
+}
+
+//ClearMessages removes the recorded messages
+func (sa *SinkAdapter) ClearMessages() {
+	sa.lock.Lock()
+	defer sa.lock.Unlock()
+
+	sa.messages = nil
+}
+
+//StartServer serves the recorded messages on the address for the test harnesses - GET gives them as json and DELETE clears them.
+//It must not be exposed publicly.
+func (sa *SinkAdapter) StartServer(address string) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/messages", sa.serveMessages)
+
+	go func() {
+		log.Printf("SinkAdapter -> serving the messages on %s\n", address)
+		err := http.ListenAndServe(address, mux)
+		if err != nil {
+			log.Printf("Error serving the sink messages - %s\n", err)
+		}
+	}()
+}
+
+func (sa *SinkAdapter) serveMessages(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		data, err := json.Marshal(sa.GetMessages())
+		if err != nil {
+			log.Printf("Error on marshal the sink messages - %s\n", err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		w.Write(data)
+	case http.MethodDelete:
+		sa.ClearMessages()
+		w.WriteHeader(http.StatusOK)
+	default:
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+	}
+}
+
+//NewSinkAdapter creates a new sink adapter instance. Empty file path means the messages go to the log.
+func NewSinkAdapter(filePath string) *SinkAdapter {
+	return &SinkAdapter{filePath: filePath, lock: &sync.RWMutex{}}
+}
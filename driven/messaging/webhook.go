@@ -0,0 +1,93 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+
+package messaging
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+)
+
+//WebhookAdapter implements messaging by posting every message to a generic http endpoint
+type WebhookAdapter struct {
+	url    string
+	apiKey string
+	client *http.Client
+}
+
+type webhookMessage struct {
+	Tokens []string          `json:"tokens"`
+	Title  string            `json:"title"`
+	Body   string            `json:"body"`
+	Data   map[string]string `json:"data"`
+}
+
+//SendNotificationMessage send a notification message
+func (wa *WebhookAdapter) SendNotificationMessage(tokens []string, title string, body string, data map[string]string) {
+	if len(tokens) <= 0 {
+		log.Println("SendNotificationMessage -> cannot send messages without tokens")
+		return
+	}
+
+	go func() {
+		err := wa.post(webhookMessage{Tokens: tokens, Title: title, Body: body, Data: data})
+		if err != nil {
+			log.Printf("Error sending webhook notification message - %s\n", err)
+			return
+		}
+		log.Println("Successfully sent webhook notification message")
+	}()
+}
+
+func (wa *WebhookAdapter) post(message webhookMessage) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("POST", wa.url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if len(wa.apiKey) > 0 {
+		req.Header.Set("ROKWIRE-API-KEY", wa.apiKey)
+	}
+
+	resp, err := wa.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook responded with %d", resp.StatusCode)
+	}
+	return nil
+}
+
+//NewWebhookAdapter creates a new webhook adapter instance
+func NewWebhookAdapter(url string, apiKey string) *WebhookAdapter {
+	if len(url) == 0 {
+		log.Fatal("the webhook messaging url is required")
+		return nil
+	}
+	return &WebhookAdapter{url: url, apiKey: apiKey, client: &http.Client{Timeout: 30 * time.Second}}
+}
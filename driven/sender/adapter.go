@@ -64,6 +64,58 @@ func (a *Adapter) SendForResources(resourcesList []*model.Resource) {
 	}
 }
 
+//SendForTestingOverdue sends emails to the overdue users and a list of them to the recepients
+func (a *Adapter) SendForTestingOverdue(subjects []model.TestingSubject) {
+	log.Printf("SendForTestingOverdue() -> sending data for %d items\n", len(subjects))
+
+	//1. let the users know
+	for _, subject := range subjects {
+		if len(subject.Email) == 0 {
+			continue
+		}
+		body := a.constructTestingOverdueBody(subject)
+		go a.send("COVID-19 Testing Overdue", subject.Email, body)
+	}
+
+	//2. escalate to the recepients
+	if len(a.to) <= 0 {
+		log.Println("SendForTestingOverdue() -> there is no recepients")
+		return
+	}
+	body := a.constructTestingOverdueListBody(subjects)
+	for _, recipient := range a.to {
+		go a.send("COVID-19 Testing Overdue Users", recipient, body)
+	}
+}
+
+func (a *Adapter) constructTestingOverdueBody(subject model.TestingSubject) string {
+	buf := bytes.Buffer{}
+
+	buf.WriteString("Dear " + subject.FirstName + " " + subject.LastName + ",\n\n")
+	buf.WriteString("You are overdue for your COVID-19 test. Please get tested as soon as possible.\n\n")
+	if subject.LatestTestDate != nil {
+		buf.WriteString("Your latest test is from " + subject.LatestTestDate.Format("January 2, 2006") + ".\n")
+	}
+
+	return buf.String()
+}
+
+func (a *Adapter) constructTestingOverdueListBody(subjects []model.TestingSubject) string {
+	buf := bytes.Buffer{}
+
+	buf.WriteString(fmt.Sprintf("%d", len(subjects)) + " users became overdue\n\n\n")
+
+	for _, subject := range subjects {
+		latestTest := "never tested"
+		if subject.LatestTestDate != nil {
+			latestTest = subject.LatestTestDate.Format("2006-01-02")
+		}
+		buf.WriteString(subject.UIN + " - " + subject.FirstName + " " + subject.LastName + " - " + latestTest + "\n")
+	}
+
+	return buf.String()
+}
+
 func (a *Adapter) constructResourcesBody(resourcesList []*model.Resource) string {
 	buf := bytes.Buffer{}
 
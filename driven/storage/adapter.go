@@ -437,39 +437,7 @@ func (sa *Adapter) CreateAppUser(externalID string,
 		//add default account
 		accounts = append(accounts, model.Account{ID: userID.String(), ExternalID: externalID, Default: true, Active: true})
 
-		//check if there are added sub accounts for this user
-		rawSubAccountsFilter := bson.D{primitive.E{Key: "primary_account", Value: externalID}}
-		var rawSubAccounts []*model.RawSubAccount
-		err = sa.db.rawsubaccounts.FindWithContext(sessionContext, rawSubAccountsFilter, &rawSubAccounts, nil)
-		if err != nil {
-			abortTransaction(sessionContext)
-			return err
-		}
-		if len(rawSubAccounts) > 0 {
-			//if there are, then hook them up
-			for _, rsa := range rawSubAccounts {
-				subAccID, _ := uuid.NewUUID()
-				newSubAccount := model.Account{ID: subAccID.String(), ExternalID: rsa.UIN, Default: false, Active: true, FirstName: rsa.FirstName,
-					MiddleName: rsa.MiddleName, LastName: rsa.LastName, BirthDate: rsa.BirthDate, Gender: rsa.Gender, Address1: rsa.Address1,
-					Address2: rsa.Address2, Address3: rsa.Address3, City: rsa.City, State: rsa.State, ZipCode: rsa.ZipCode, Phone: rsa.Phone,
-					Email: rsa.Email}
-
-				accounts = append(accounts, newSubAccount)
-
-				//update the raw sub account with the inserted account id
-				rawSubAccountUpdateFilter := bson.D{primitive.E{Key: "uin", Value: rsa.UIN}}
-				rawSubAccountUpdate := bson.D{
-					primitive.E{Key: "$set", Value: bson.D{
-						primitive.E{Key: "account_id", Value: subAccID.String()},
-					}},
-				}
-				_, err := sa.db.rawsubaccounts.UpdateOneWithContext(sessionContext, rawSubAccountUpdateFilter, rawSubAccountUpdate, nil)
-				if err != nil {
-					abortTransaction(sessionContext)
-					return err
-				}
-			}
-		}
+		//the added sub accounts for this user wait to be accepted by the user
 
 		//insert the created user
 		user = &model.User{ID: userID.String(), ExternalID: externalID, UUID: userUUID,
@@ -1096,14 +1064,28 @@ func (sa *Adapter) ReadAllProviders() ([]*model.Provider, error) {
 	if err != nil {
 		return nil, err
 	}
+	return convertProviders(result), nil
+}
+
+//QueryProviders finds the providers matching the query
+func (sa *Adapter) QueryProviders(q *utils.Query) ([]*model.Provider, string, error) {
+	var result []*provider
+	cursor, err := findQueryPage(sa.db.providers, q, &result)
+	if err != nil {
+		return nil, "", err
+	}
+	return convertProviders(result), cursor, nil
+}
+
+func convertProviders(list []*provider) []*model.Provider {
 	var resultList []*model.Provider
-	if result != nil {
-		for _, current := range result {
+	if list != nil {
+		for _, current := range list {
 			item := &model.Provider{ID: current.ID, Name: current.ProviderName, ManualTest: current.ManualTest, AvailableMechanisms: current.AvailableMechanisms}
 			resultList = append(resultList, item)
 		}
 	}
-	return resultList, nil
+	return resultList
 }
 
 //CreateProvider creates a provider
@@ -1774,9 +1756,23 @@ func (sa *Adapter) FindCounties(f *utils.Filter) ([]*model.County, error) {
 	if err != nil {
 		return nil, err
 	}
+	return convertCounties(result), nil
+}
+
+//QueryCounties finds the counties matching the query
+func (sa *Adapter) QueryCounties(q *utils.Query) ([]*model.County, string, error) {
+	var result []*county
+	cursor, err := findQueryPage(sa.db.counties, q, &result)
+	if err != nil {
+		return nil, "", err
+	}
+	return convertCounties(result), cursor, nil
+}
+
+func convertCounties(list []*county) []*model.County {
 	var resultList []*model.County
-	if result != nil {
-		for _, county := range result {
+	if list != nil {
+		for _, county := range list {
 			//guidelines
 			var guidelines []model.Guideline
 			if county.Guidelines != nil {
@@ -1810,7 +1806,7 @@ func (sa *Adapter) FindCounties(f *utils.Filter) ([]*model.County, error) {
 			resultList = append(resultList, entity)
 		}
 	}
-	return resultList, nil
+	return resultList
 }
 
 //DeleteCounty deletes a county
@@ -1993,7 +1989,8 @@ func (sa *Adapter) FindGuideline(ID string) (*model.Guideline, error) {
 		gli := model.GuidelineItem{Icon: current.Icon, Description: current.Description, Type: itemType}
 		items = append(items, gli)
 	}
-	resultItem := &model.Guideline{ID: guideline.ID, Name: guideline.Name, Description: guideline.Description, Items: items}
+	resultItem := &model.Guideline{ID: guideline.ID, Name: guideline.Name, Description: guideline.Description, Items: items,
+		County: model.County{ID: county.ID}}
 
 	return resultItem, nil
 }
@@ -2219,7 +2216,7 @@ func (sa *Adapter) FindCountyStatus(ID string) (*model.CountyStatus, error) {
 
 	//3. construct the result
 	resultItem := &model.CountyStatus{ID: countyStatus.ID, Name: countyStatus.Name,
-		Description: countyStatus.Description}
+		Description: countyStatus.Description, County: model.County{ID: county.ID}}
 
 	return resultItem, nil
 }
@@ -3151,9 +3148,23 @@ func (sa *Adapter) ReadAllLocations() ([]*model.Location, error) {
 	if err != nil {
 		return nil, err
 	}
+	return convertLocations(result), nil
+}
+
+//QueryLocations finds the locations matching the query
+func (sa *Adapter) QueryLocations(q *utils.Query) ([]*model.Location, string, error) {
+	var result []*location
+	cursor, err := findQueryPage(sa.db.locations, q, &result)
+	if err != nil {
+		return nil, "", err
+	}
+	return convertLocations(result), cursor, nil
+}
+
+func convertLocations(list []*location) []*model.Location {
 	var resultList []*model.Location
-	if result != nil {
-		for _, location := range result {
+	if list != nil {
+		for _, location := range list {
 			provider := model.Provider{ID: location.ProviderID}
 			county := model.County{ID: location.CountyID}
 			var avTests []model.TestType
@@ -3172,7 +3183,7 @@ func (sa *Adapter) ReadAllLocations() ([]*model.Location, error) {
 			resultList = append(resultList, locationEntity)
 		}
 	}
-	return resultList, nil
+	return resultList
 }
 
 //CreateLocation creates a location
@@ -4052,8 +4063,8 @@ func (sa *Adapter) CreateTraceReports(items []model.TraceExposure) (int, error)
 	return insertedCount, nil
 }
 
-//ReadTraceExposures reads the exposures
-func (sa *Adapter) ReadTraceExposures(timestamp *int64, dateAdded *int64) ([]model.TraceExposure, error) {
+//ReadTraceExposures reads the exposures. The exposures with expirestamp before the provided now are not given.
+func (sa *Adapter) ReadTraceExposures(timestamp *int64, dateAdded *int64, now int64) ([]model.TraceExposure, error) {
 	filter := bson.M{}
 
 	if timestamp != nil {
@@ -4062,6 +4073,7 @@ func (sa *Adapter) ReadTraceExposures(timestamp *int64, dateAdded *int64) ([]mod
 	if dateAdded != nil {
 		filter["date_added"] = bson.M{"$gte": dateAdded}
 	}
+	filter["$or"] = []bson.M{{"expirestamp": nil}, {"expirestamp": bson.M{"$gt": now}}}
 
 	options := options.Find()
 	options.SetSort(bson.D{primitive.E{Key: "timestamp", Value: 1}}) //sort by "timestamp"
@@ -4074,6 +4086,129 @@ func (sa *Adapter) ReadTraceExposures(timestamp *int64, dateAdded *int64) ([]mod
 	return result, nil
 }
 
+//CountGAENTraceExposures counts the exposure keys published through the GAEN publish API for the provided date added period
+func (sa *Adapter) CountGAENTraceExposures(dateAddedFrom int64, dateAddedTo int64) (int64, error) {
+	filter := bson.D{primitive.E{Key: "date_added", Value: bson.M{"$gte": dateAddedFrom, "$lt": dateAddedTo}},
+		primitive.E{Key: "rolling_start_number", Value: bson.M{"$exists": true}}}
+	return sa.db.traceexposures.CountDocuments(filter)
+}
+
+//ReadGAENTraceExposures reads the exposure keys published through the GAEN publish API for the provided date added period
+func (sa *Adapter) ReadGAENTraceExposures(dateAddedFrom int64, dateAddedTo int64, offset int64, limit int64) ([]model.TraceExposure, error) {
+	filter := bson.D{primitive.E{Key: "date_added", Value: bson.M{"$gte": dateAddedFrom, "$lt": dateAddedTo}},
+		primitive.E{Key: "rolling_start_number", Value: bson.M{"$exists": true}}}
+
+	//keep the same order for the export batches
+	options := options.Find()
+	options.SetSort(bson.D{primitive.E{Key: "date_added", Value: 1}, primitive.E{Key: "tek", Value: 1}})
+	options.SetSkip(offset)
+	options.SetLimit(limit)
+
+	var result []model.TraceExposure
+	err := sa.db.traceexposures.Find(filter, &result, options)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+type traceExposureItem struct {
+	ID                  primitive.ObjectID `bson:"_id"`
+	model.TraceExposure `bson:",inline"`
+}
+
+//ReadTraceExposuresPage reads an exposures page sorted by date added. The page starts after the provided cursor or from the beginning if it is nil
+//and it has only the exposures added before the provided added before. It gives the cursor of the last item or the provided one if the page is empty.
+func (sa *Adapter) ReadTraceExposuresPage(timestamp *int64, after *model.TraceExposureCursor, addedBefore int64, now int64, limit int64) ([]model.TraceExposure, *model.TraceExposureCursor, error) {
+	filter := bson.D{primitive.E{Key: "$or", Value: []bson.M{{"expirestamp": nil}, {"expirestamp": bson.M{"$gt": now}}}},
+		primitive.E{Key: "date_added", Value: bson.M{"$lt": addedBefore}}}
+	if timestamp != nil {
+		filter = append(filter, primitive.E{Key: "timestamp", Value: bson.M{"$gte": timestamp}})
+	}
+	if after != nil {
+		afterID, err := primitive.ObjectIDFromHex(after.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+		filter = append(filter, primitive.E{Key: "$and", Value: []bson.M{{"$or": []bson.M{
+			{"date_added": bson.M{"$gt": after.DateAdded}},
+			{"date_added": after.DateAdded, "_id": bson.M{"$gt": afterID}},
+		}}}})
+	}
+
+	//the id makes the order stable for the items added at the same time
+	options := options.Find()
+	options.SetSort(bson.D{primitive.E{Key: "date_added", Value: 1}, primitive.E{Key: "_id", Value: 1}})
+	options.SetLimit(limit)
+
+	var items []traceExposureItem
+	err := sa.db.traceexposures.Find(filter, &items, options)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(items) == 0 {
+		return []model.TraceExposure{}, after, nil
+	}
+
+	result := make([]model.TraceExposure, len(items))
+	for i, item := range items {
+		result[i] = item.TraceExposure
+	}
+	last := items[len(items)-1]
+	cursor := model.TraceExposureCursor{DateAdded: last.DateAdded, ID: last.ID.Hex()}
+	return result, &cursor, nil
+}
+
+//PurgeTraceExposures deletes the exposures with expirestamp before the provided now and the exposures with timestamp before the retention start.
+//It gives the deleted counts for both cases.
+func (sa *Adapter) PurgeTraceExposures(now int64, retentionFrom int64) (int64, int64, error) {
+	expiredFilter := bson.D{primitive.E{Key: "expirestamp", Value: bson.M{"$ne": nil, "$lte": now}}}
+	expiredResult, err := sa.db.traceexposures.DeleteMany(expiredFilter, nil)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	retentionFilter := bson.D{primitive.E{Key: "timestamp", Value: bson.M{"$lt": retentionFrom}}}
+	retentionResult, err := sa.db.traceexposures.DeleteMany(retentionFilter, nil)
+	if err != nil {
+		return expiredResult.DeletedCount, 0, err
+	}
+
+	return expiredResult.DeletedCount, retentionResult.DeletedCount, nil
+}
+
+//CountTraceExposures counts the exposures. The GAEN only flag counts only the keys published through the GAEN publish API.
+func (sa *Adapter) CountTraceExposures(gaenOnly bool) (int64, error) {
+	filter := bson.D{}
+	if gaenOnly {
+		filter = append(filter, primitive.E{Key: "rolling_start_number", Value: bson.M{"$exists": true}})
+	}
+	return sa.db.traceexposures.CountDocuments(filter)
+}
+
+//CreateExposurePurge records an exposures purge
+func (sa *Adapter) CreateExposurePurge(purge model.ExposurePurge) error {
+	_, err := sa.db.exposurepurges.InsertOne(purge)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+//FindExposurePurges finds the exposures purges after the provided date, the latest first
+func (sa *Adapter) FindExposurePurges(from time.Time) ([]model.ExposurePurge, error) {
+	filter := bson.D{primitive.E{Key: "date", Value: bson.M{"$gte": from}}}
+	options := options.Find()
+	options.SetSort(bson.D{primitive.E{Key: "date", Value: -1}})
+
+	var result []model.ExposurePurge
+	err := sa.db.exposurepurges.Find(filter, &result, options)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 type manualTestUserJoin struct {
 	ID            string     `bson:"_id"`
 	HistoryID     string     `bson:"ehistory_id"`
@@ -4116,9 +4251,13 @@ type manualTestUserJoin struct {
 	} `bson:"user_accounts"`
 }
 
-//FindManualTestsByCountyIDDeep find the manual test for a county
-func (sa *Adapter) FindManualTestsByCountyIDDeep(countyID string, status *string) ([]*model.EManualTest, error) {
-	// construct the query
+//QueryManualTests finds the manual tests for a county matching the query
+func (sa *Adapter) QueryManualTests(countyIDs []string, q *utils.Query) ([]*model.EManualTest, string, error) {
+	filter, err := QueryFilter(q)
+	if err != nil {
+		return nil, "", err
+	}
+
 	pipeline := []bson.M{}
 	pipeline = append(pipeline, bson.M{"$lookup": bson.M{
 		"from":         "users",
@@ -4126,51 +4265,70 @@ func (sa *Adapter) FindManualTestsByCountyIDDeep(countyID string, status *string
 		"foreignField": "_id",
 		"as":           "user",
 	}})
-	if countyID != "all" {
-		//we need to filter by county
+	countyMatch, err := sa.constructManualTestsCountyMatch(countyIDs)
+	if err != nil {
+		return nil, "", err
+	}
+	if countyMatch != nil {
+		pipeline = append(pipeline, countyMatch)
+	}
+	pipeline = append(pipeline, bson.M{"$match": filter})
+	pipeline = append(pipeline, manualTestsUserProjection()...)
+	pipeline = append(pipeline, bson.M{"$sort": QuerySort(q)})
+	if q != nil && q.Limit > 0 {
+		pipeline = append(pipeline, bson.M{"$limit": q.Limit})
+	}
 
-		locsFilter := bson.D{primitive.E{Key: "county_id", Value: countyID}}
-		var locsResult []*location
-		err := sa.db.locations.Find(locsFilter, &locsResult, nil)
-		if err != nil {
-			return nil, err
-		}
-		if len(locsResult) == 0 {
-			return nil, errors.New("there is no any location for this county")
-		}
-		var locationIDs []string
-		for _, item := range locsResult {
-			locationIDs = append(locationIDs, item.ID)
-		}
-		pipeline = append(pipeline, bson.M{"$match": bson.M{"$or": []interface{}{bson.M{"county_id": countyID}, bson.M{"location_id": bson.M{"$in": locationIDs}}}}})
+	var items []bson.Raw
+	err = sa.db.emanualtests.Aggregate(pipeline, &items, nil)
+	if err != nil {
+		return nil, "", err
 	}
-	if status != nil {
-		pipeline = append(pipeline, bson.M{"$match": bson.M{"status": status}})
+	var result []*manualTestUserJoin
+	cursor, err := DecodeQueryPage(q, items, &result)
+	if err != nil {
+		return nil, "", err
 	}
+	return convertManualTestUserJoins(result), cursor, nil
+}
 
-	pipeline = append(pipeline, bson.M{"$unwind": "$user"},
-		bson.M{"$project": bson.M{
-			"_id": 1, "ehistory_id": 1, "location_id": 1, "county_id": 1, "encrypted_key": 1, "encrypted_blob": 1, "status": 1, "date": 1, "date_created": 1,
-			"user_id": "$user._id", "user_external_id": "$user.external_id", "user_uuid": "$user.uuid", "user_public_key": "$user.public_key",
-			"user_consent": "$user.consent", "user_consent_vaccine": "$user._consent_vaccine", "user_exposure_notification": "$user._exposure_notification",
-			"user_info": "$user.info", "user_encrypted_key": "$user.encrypted_key", "user_encrypted_blob": "$user.encrypted_blob",
-			"user_accounts": "$user.accounts",
-		}},
-		bson.M{"$sort": bson.D{primitive.E{Key: "date_created", Value: -1}}})
+func (sa *Adapter) constructManualTestsCountyMatch(countyIDs []string) (bson.M, error) {
+	if countyIDs == nil {
+		//all counties
+		return nil, nil
+	}
 
-	var result []*manualTestUserJoin
-	err := sa.db.emanualtests.Aggregate(pipeline, &result, nil)
+	//we need to filter by county
+	locsFilter := bson.D{primitive.E{Key: "county_id", Value: bson.M{"$in": countyIDs}}}
+	var locsResult []*location
+	err := sa.db.locations.Find(locsFilter, &locsResult, nil)
 	if err != nil {
 		return nil, err
-
 	}
-	if result == nil || len(result) == 0 {
-		//not found
-		return nil, nil
+	if len(locsResult) == 0 {
+		return nil, errors.New("there is no any location for this county")
 	}
+	var locationIDs []string
+	for _, item := range locsResult {
+		locationIDs = append(locationIDs, item.ID)
+	}
+	return bson.M{"$match": bson.M{"$or": []interface{}{bson.M{"county_id": bson.M{"$in": countyIDs}}, bson.M{"location_id": bson.M{"$in": locationIDs}}}}}, nil
+}
+
+func manualTestsUserProjection() []bson.M {
+	return []bson.M{{"$unwind": "$user"},
+		{"$project": bson.M{
+			"_id": 1, "ehistory_id": 1, "location_id": 1, "county_id": 1, "encrypted_key": 1, "encrypted_blob": 1, "status": 1, "date": 1, "date_created": 1,
+			"user_id": "$user._id", "user_external_id": "$user.external_id", "user_uuid": "$user.uuid", "user_public_key": "$user.public_key",
+			"user_consent": "$user.consent", "user_consent_vaccine": "$user._consent_vaccine", "user_exposure_notification": "$user._exposure_notification",
+			"user_info": "$user.info", "user_encrypted_key": "$user.encrypted_key", "user_encrypted_blob": "$user.encrypted_blob",
+			"user_accounts": "$user.accounts",
+		}}}
+}
 
+func convertManualTestUserJoins(list []*manualTestUserJoin) []*model.EManualTest {
 	var resultList []*model.EManualTest
-	for _, item := range result {
+	for _, item := range list {
 
 		accounts := make([]model.Account, len(item.UserAccounts))
 		if item.UserAccounts != nil {
@@ -4191,7 +4349,7 @@ func (sa *Adapter) FindManualTestsByCountyIDDeep(countyID string, status *string
 
 		resultList = append(resultList, &mt)
 	}
-	return resultList, nil
+	return resultList
 }
 
 //FindManualTestImage finds the manual test image
@@ -4210,6 +4368,34 @@ func (sa *Adapter) FindManualTestImage(ID string) (*string, *string, error) {
 	return &manualTest.EncryptedImageKey, &manualTest.EncryptedImageBlob, nil
 }
 
+//FindManualTestCountyID finds the county of a manual test - its own county or the county of its location
+func (sa *Adapter) FindManualTestCountyID(ID string) (*string, error) {
+	filter := bson.D{primitive.E{Key: "_id", Value: ID}}
+	var result []*eManualTest
+	err := sa.db.emanualtests.Find(filter, &result, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return nil, errors.New("there is no a manual test for the provided id")
+	}
+	manualTest := result[0]
+	if manualTest.CountyID != nil || manualTest.LocationID == nil {
+		return manualTest.CountyID, nil
+	}
+
+	locFilter := bson.D{primitive.E{Key: "_id", Value: *manualTest.LocationID}}
+	var locResult []*location
+	err = sa.db.locations.Find(locFilter, &locResult, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(locResult) == 0 {
+		return nil, nil
+	}
+	return &locResult[0].CountyID, nil
+}
+
 //ProcessManualTest processes manual test
 func (sa *Adapter) ProcessManualTest(ID string, status string, encryptedKey *string, encryptedBlob *string, date *time.Time) error {
 	// transaction
@@ -4495,6 +4681,32 @@ func (sa *Adapter) validateAccessRuleData(sessionContext mongo.SessionContext, c
 	return nil
 }
 
+//FindAccessRule finds an access rule
+func (sa *Adapter) FindAccessRule(ID string) (*model.AccessRule, error) {
+	filter := bson.D{primitive.E{Key: "_id", Value: ID}}
+	var result []*accessRule
+	err := sa.db.accessrules.Find(filter, &result, nil)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil || len(result) == 0 {
+		//not found
+		return nil, nil
+	}
+	accessRule := result[0]
+
+	var items []model.AccessRuleCountyStatus
+	if accessRule.Rules != nil {
+		for _, c := range accessRule.Rules {
+			item := model.AccessRuleCountyStatus{CountyStatusID: c.CountyStatusID, Value: c.Value}
+			items = append(items, item)
+		}
+	}
+	county := model.County{ID: accessRule.CountyID}
+	resultItem := model.AccessRule{ID: accessRule.ID, County: county, Rules: items}
+	return &resultItem, nil
+}
+
 //FindAccessRuleByCountyID finds the access rule for a specific county
 func (sa *Adapter) FindAccessRuleByCountyID(countyID string) (*model.AccessRule, error) {
 	filter := bson.D{primitive.E{Key: "county_id", Value: countyID}}
@@ -4740,6 +4952,16 @@ func (sa *Adapter) FindUINOverrides(uin *string, sort *string) ([]*model.UINOver
 	return result, nil
 }
 
+//QueryUINOverrides finds the uin overrides matching the query
+func (sa *Adapter) QueryUINOverrides(q *utils.Query) ([]*model.UINOverride, string, error) {
+	var result []*model.UINOverride
+	cursor, err := findQueryPage(sa.db.uinoverrides, q, &result)
+	if err != nil {
+		return nil, "", err
+	}
+	return result, cursor, nil
+}
+
 //CreateUINOverride creates a new uin override entity
 func (sa *Adapter) CreateUINOverride(uin string, exempt *bool, interval *int, category *string, activation *time.Time, expiration *time.Time) (*model.UINOverride, error) {
 	uinOverride := model.UINOverride{UIN: uin, Interval: interval, Exempt: exempt, Category: category, Activation: activation, Expiration: expiration}
@@ -4830,9 +5052,9 @@ func (sa *Adapter) CreateOrUpdateUINBuildingAccess(uin string, date time.Time, a
 }
 
 //ReadAllRosters reads all rosters
-func (sa *Adapter) ReadAllRosters() ([]map[string]string, error) {
+func (sa *Adapter) ReadAllRosters() ([]model.Roster, error) {
 	filter := bson.D{}
-	var result []map[string]string
+	var result []model.Roster
 	err := sa.db.rosters.Find(filter, &result, nil)
 	if err != nil {
 		return nil, err
@@ -4842,10 +5064,10 @@ func (sa *Adapter) ReadAllRosters() ([]map[string]string, error) {
 }
 
 //FindRosterByPhone finds the roster for the user with the given phone number
-func (sa *Adapter) FindRosterByPhone(phone string) (map[string]string, error) {
+func (sa *Adapter) FindRosterByPhone(phone string) (*model.Roster, error) {
 	filter := bson.D{primitive.E{Key: "phone", Value: phone}}
 
-	var result []map[string]string
+	var result []model.Roster
 	err := sa.db.rosters.Find(filter, &result, nil)
 	if err != nil {
 		return nil, err
@@ -4855,11 +5077,11 @@ func (sa *Adapter) FindRosterByPhone(phone string) (map[string]string, error) {
 		return nil, nil
 	}
 	item := result[0]
-	return item, nil
+	return &item, nil
 }
 
 //FindRosters returns the roster members matching filters, sorted, and paginated
-func (sa *Adapter) FindRosters(f *utils.Filter, sortBy string, sortOrder int, limit int, offset int) ([]map[string]interface{}, error) {
+func (sa *Adapter) FindRosters(f *utils.Filter, sortBy string, sortOrder int, limit int, offset int) ([]model.Roster, error) {
 	var filter bson.D
 	if f != nil {
 		filter = constructDataFilter(f).(bson.D)
@@ -4874,33 +5096,36 @@ func (sa *Adapter) FindRosters(f *utils.Filter, sortBy string, sortOrder int, li
 		options.SetSkip(int64(offset))
 	}
 
-	projection := bson.D{
-		bson.E{Key: "_id", Value: 0},
-	}
-	options.SetProjection(projection)
-
-	var result []map[string]interface{}
+	var result []model.Roster
 	err := sa.db.rosters.Find(filter, &result, options)
 	if err != nil {
 		log.Println("GetRoster:", err.Error())
-		return []map[string]interface{}{}, err
+		return []model.Roster{}, err
 	} else if len(result) < 1 {
 		log.Println("GetRoster: no roster data found")
-		return []map[string]interface{}{}, nil
+		return []model.Roster{}, nil
 	}
 
 	return result, nil
 }
 
-//CreateRoster creates a roster
-func (sa *Adapter) CreateRoster(phone string, uin string, firstName string, middleName string, lastName string, birthDate string, gender string,
-	address1 string, address2 string, address3 string, city string, state string, zipCode string, email string, badgeType string) error {
+//QueryRosters finds the roster members matching the query
+func (sa *Adapter) QueryRosters(q *utils.Query) ([]model.Roster, string, error) {
+	var result []model.Roster
+	cursor, err := findQueryPage(sa.db.rosters, q, &result)
+	if err != nil {
+		return nil, "", err
+	}
+	if result == nil {
+		result = []model.Roster{}
+	}
+	return result, cursor, nil
+}
 
+//CreateRoster creates a roster
+func (sa *Adapter) CreateRoster(roster model.Roster) error {
 	//insert the roster
-	item := map[string]string{"phone": phone, "uin": uin, "first_name": firstName, "middle_name": middleName,
-		"last_name": lastName, "birth_date": birthDate, "gender": gender, "address1": address1, "address2": address2,
-		"address3": address3, "city": city, "state": state, "zip_code": zipCode, "email": email, "badge_type": badgeType}
-	_, err := sa.db.rosters.InsertOne(&item)
+	_, err := sa.db.rosters.InsertOne(&roster)
 	if err != nil {
 		return err
 	}
@@ -4908,26 +5133,25 @@ func (sa *Adapter) CreateRoster(phone string, uin string, firstName string, midd
 	return nil
 }
 
-//UpdateRoster updates a roster
-func (sa *Adapter) UpdateRoster(uin string, firstName string, middleName string, lastName string, birthDate string, gender string,
-	address1 string, address2 string, address3 string, city string, state string, zipCode string, email string, badgeType string) error {
-
-	filter := bson.D{primitive.E{Key: "uin", Value: uin}}
+//UpdateRoster updates a roster, the phone is not changed
+func (sa *Adapter) UpdateRoster(roster model.Roster) error {
+	filter := bson.D{primitive.E{Key: "uin", Value: roster.UIN}}
 	update := bson.D{
 		primitive.E{Key: "$set", Value: bson.D{
-			primitive.E{Key: "first_name", Value: firstName},
-			primitive.E{Key: "middle_name", Value: middleName},
-			primitive.E{Key: "last_name", Value: lastName},
-			primitive.E{Key: "birth_date", Value: birthDate},
-			primitive.E{Key: "gender", Value: gender},
-			primitive.E{Key: "address1", Value: address1},
-			primitive.E{Key: "address2", Value: address2},
-			primitive.E{Key: "address3", Value: address3},
-			primitive.E{Key: "city", Value: city},
-			primitive.E{Key: "state", Value: state},
-			primitive.E{Key: "zip_code", Value: zipCode},
-			primitive.E{Key: "email", Value: email},
-			primitive.E{Key: "badge_type", Value: badgeType},
+			primitive.E{Key: "first_name", Value: roster.FirstName},
+			primitive.E{Key: "middle_name", Value: roster.MiddleName},
+			primitive.E{Key: "last_name", Value: roster.LastName},
+			primitive.E{Key: "birth_date", Value: roster.BirthDate},
+			primitive.E{Key: "gender", Value: roster.Gender},
+			primitive.E{Key: "address1", Value: roster.Address1},
+			primitive.E{Key: "address2", Value: roster.Address2},
+			primitive.E{Key: "address3", Value: roster.Address3},
+			primitive.E{Key: "city", Value: roster.City},
+			primitive.E{Key: "state", Value: roster.State},
+			primitive.E{Key: "zip_code", Value: roster.ZipCode},
+			primitive.E{Key: "email", Value: roster.Email},
+			primitive.E{Key: "badge_type", Value: roster.BadgeType},
+			primitive.E{Key: "attributes", Value: roster.Attributes},
 		}},
 	}
 
@@ -4944,7 +5168,7 @@ func (sa *Adapter) UpdateRoster(uin string, firstName string, middleName string,
 }
 
 //CreateRosterItems creates roster items
-func (sa *Adapter) CreateRosterItems(items []map[string]string) error {
+func (sa *Adapter) CreateRosterItems(items []model.Roster) error {
 	//insert the items
 	//need to prepare the input data
 	data := make([]interface{}, len(items))
@@ -4964,23 +5188,164 @@ func (sa *Adapter) CreateRosterItems(items []map[string]string) error {
 	return nil
 }
 
-//DeleteRosterByPhone deletes the roster for the provided phone
-func (sa *Adapter) DeleteRosterByPhone(phone string) error {
-	deleteFilter := bson.D{primitive.E{Key: "phone", Value: phone}}
-	result, err := sa.db.rosters.DeleteOne(deleteFilter, nil)
+//FindRostersByPhonesOrUINs finds the roster members with any of the provided phones or uins
+func (sa *Adapter) FindRostersByPhonesOrUINs(phones []string, uins []string) ([]model.Roster, error) {
+	filter := bson.D{primitive.E{Key: "$or", Value: []interface{}{
+		bson.D{primitive.E{Key: "phone", Value: bson.M{"$in": phones}}},
+		bson.D{primitive.E{Key: "uin", Value: bson.M{"$in": uins}}},
+	}}}
+
+	var result []model.Roster
+	err := sa.db.rosters.Find(filter, &result, nil)
 	if err != nil {
-		log.Printf("error deleting a roster - %s", err)
-		return err
-	}
-	if result == nil {
-		return errors.New("result is nil for roster with phone " + phone)
-	}
-	deletedCount := result.DeletedCount
-	if deletedCount == 0 {
-		return errors.New("there is no a roster for phone " + phone)
-	}
-	if deletedCount > 1 {
-		return errors.New("deleted more than one records for phone " + phone)
+		return nil, err
+	}
+	return result, nil
+}
+
+//CreateRosterImport saves a roster import report with the imported roster items in one transaction, so the items are not created without the report
+func (sa *Adapter) CreateRosterImport(item model.RosterImport, items []model.Roster) error {
+	// transaction
+	err := sa.db.dbClient.UseSession(context.Background(), func(sessionContext mongo.SessionContext) error {
+		err := sessionContext.StartTransaction()
+		if err != nil {
+			log.Printf("error starting a transaction - %s", err)
+			return err
+		}
+
+		//1. insert the roster items
+		if len(items) > 0 {
+			data := make([]interface{}, len(items))
+			for i, c := range items {
+				data[i] = c
+			}
+			_, err = sa.db.rosters.InsertManyWithContext(sessionContext, data, nil)
+			if err != nil {
+				abortTransaction(sessionContext)
+				log.Printf("error inserting many roster items - %s", err)
+				return err
+			}
+		}
+
+		//2. insert the report
+		_, err = sa.db.rosterimports.InsertOneWithContext(sessionContext, item)
+		if err != nil {
+			abortTransaction(sessionContext)
+			return err
+		}
+
+		err = sessionContext.CommitTransaction(sessionContext)
+		if err != nil {
+			log.Printf("error on commiting a transaction - %s", err)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+//FindRosterImport finds a roster import report
+func (sa *Adapter) FindRosterImport(ID string) (*model.RosterImport, error) {
+	filter := bson.D{primitive.E{Key: "_id", Value: ID}}
+	var result []*model.RosterImport
+	err := sa.db.rosterimports.Find(filter, &result, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		//not found
+		return nil, nil
+	}
+	return result[0], nil
+}
+
+//SyncRosters applies the roster changes in one transaction - removes the items for the removed uins, replaces the updated items and inserts the added items
+func (sa *Adapter) SyncRosters(added []model.Roster, updated []model.Roster, removedUINs []string) error {
+	// transaction
+	err := sa.db.dbClient.UseSession(context.Background(), func(sessionContext mongo.SessionContext) error {
+		err := sessionContext.StartTransaction()
+		if err != nil {
+			log.Printf("error starting a transaction - %s", err)
+			return err
+		}
+
+		//1. remove - first, so the added items can take the removed phones
+		if len(removedUINs) > 0 {
+			removeFilter := bson.D{primitive.E{Key: "uin", Value: bson.M{"$in": removedUINs}}}
+			_, err = sa.db.rosters.DeleteManyWithContext(sessionContext, removeFilter, nil)
+			if err != nil {
+				abortTransaction(sessionContext)
+				return err
+			}
+		}
+
+		//2. update - the changed phones are released first, so the items can swap their phones without breaking the unique phone index
+		for _, item := range updated {
+			releaseFilter := bson.D{primitive.E{Key: "uin", Value: item.UIN}, primitive.E{Key: "phone", Value: bson.M{"$ne": item.Phone}}}
+			releaseUpdate := bson.D{primitive.E{Key: "$set", Value: bson.D{primitive.E{Key: "phone", Value: "sync:" + item.UIN}}}}
+			_, err = sa.db.rosters.UpdateOneWithContext(sessionContext, releaseFilter, releaseUpdate, nil)
+			if err != nil {
+				abortTransaction(sessionContext)
+				return err
+			}
+		}
+		for _, item := range updated {
+			updateFilter := bson.D{primitive.E{Key: "uin", Value: item.UIN}}
+			err = sa.db.rosters.ReplaceOneWithContext(sessionContext, updateFilter, item, nil)
+			if err != nil {
+				abortTransaction(sessionContext)
+				return err
+			}
+		}
+
+		//3. add
+		if len(added) > 0 {
+			data := make([]interface{}, len(added))
+			for i, item := range added {
+				data[i] = item
+			}
+			_, err = sa.db.rosters.InsertManyWithContext(sessionContext, data, nil)
+			if err != nil {
+				abortTransaction(sessionContext)
+				return err
+			}
+		}
+
+		//commit the transaction
+		err = sessionContext.CommitTransaction(sessionContext)
+		if err != nil {
+			log.Printf("error on commiting a transaction - %s", err)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+//DeleteRosterByPhone deletes the roster for the provided phone
+func (sa *Adapter) DeleteRosterByPhone(phone string) error {
+	deleteFilter := bson.D{primitive.E{Key: "phone", Value: phone}}
+	result, err := sa.db.rosters.DeleteOne(deleteFilter, nil)
+	if err != nil {
+		log.Printf("error deleting a roster - %s", err)
+		return err
+	}
+	if result == nil {
+		return errors.New("result is nil for roster with phone " + phone)
+	}
+	deletedCount := result.DeletedCount
+	if deletedCount == 0 {
+		return errors.New("there is no a roster for phone " + phone)
+	}
+	if deletedCount > 1 {
+		return errors.New("deleted more than one records for phone " + phone)
 	}
 
 	return nil
@@ -5020,121 +5385,21 @@ func (sa *Adapter) DeleteAllRosters() error {
 	return nil
 }
 
-//CreateRawSubAccountItems creates raw sub account items
+//CreateRawSubAccountItems creates raw sub account items, they wait for the primary users to accept them
 func (sa *Adapter) CreateRawSubAccountItems(items []model.RawSubAccount) error {
-	//heavy....
-
-	// transaction
-	err := sa.db.dbClient.UseSession(context.Background(), func(sessionContext mongo.SessionContext) error {
-		err := sessionContext.StartTransaction()
-		if err != nil {
-			log.Printf("error starting a transaction - %s", err)
-			return err
-		}
-
-		//apply logic for every raw sub account
-		for _, current := range items {
-			var accountID *string
-
-			// check if there is a primary account added
-			uFilter := bson.D{primitive.E{Key: "external_id", Value: current.PrimaryAccount}}
-			var usersResult []*model.User
-			err = sa.db.users.FindWithContext(sessionContext, uFilter, &usersResult, nil)
-			if err != nil {
-				abortTransaction(sessionContext)
-				return err
-			}
-			if len(usersResult) > 0 {
-				//there is a primary user for this account
-
-				user := usersResult[0]
-				subAccount := user.GetAccountByExternalID(current.UIN)
-
-				if subAccount != nil {
-					//there is a sub account, so we need to update it
-
-					//we know the account id
-					accountID = &subAccount.ID
-
-					updateSubAccountFilter := bson.D{primitive.E{Key: "accounts.id", Value: subAccount.ID}}
-					updateSubAccount := bson.D{
-						primitive.E{Key: "$set", Value: bson.D{
-							primitive.E{Key: "accounts.$.active", Value: true},
-							primitive.E{Key: "accounts.$.first_name", Value: current.FirstName},
-							primitive.E{Key: "accounts.$.middle_name", Value: current.MiddleName},
-							primitive.E{Key: "accounts.$.last_name", Value: current.LastName},
-							primitive.E{Key: "accounts.$.birth_date", Value: current.BirthDate},
-							primitive.E{Key: "accounts.$.gender", Value: current.Gender},
-							primitive.E{Key: "accounts.$.address1", Value: current.Address1},
-							primitive.E{Key: "accounts.$.address2", Value: current.Address2},
-							primitive.E{Key: "accounts.$.address3", Value: current.Address3},
-							primitive.E{Key: "accounts.$.city", Value: current.City},
-							primitive.E{Key: "accounts.$.state", Value: current.State},
-							primitive.E{Key: "accounts.$.zip_code", Value: current.ZipCode},
-							primitive.E{Key: "accounts.$.phone", Value: current.Phone},
-							primitive.E{Key: "accounts.$.email", Value: current.Email},
-						}},
-					}
-
-					_, err := sa.db.users.UpdateOneWithContext(sessionContext, updateSubAccountFilter, updateSubAccount, nil)
-					if err != nil {
-						abortTransaction(sessionContext)
-						return err
-					}
-				} else {
-					//there is no a sub account, so we need to create it
-
-					//generate sub account id
-					genSubAccountID, err := uuid.NewUUID()
-					if err != nil {
-						abortTransaction(sessionContext)
-						return err
-					}
-					subAccID := genSubAccountID.String()
-					accountID = &subAccID
-
-					newSubAccount := model.Account{ID: subAccID, ExternalID: current.UIN, Default: false, Active: true, FirstName: current.FirstName,
-						MiddleName: current.MiddleName, LastName: current.LastName, BirthDate: current.BirthDate, Gender: current.Gender, Address1: current.Address1,
-						Address2: current.Address2, Address3: current.Address3, City: current.City, State: current.State, ZipCode: current.ZipCode, Phone: current.Phone,
-						Email: current.Email}
-
-					createSubAccountFilter := bson.D{primitive.E{Key: "_id", Value: user.ID}}
-					createSubAccount := bson.D{
-						primitive.E{Key: "$push", Value: bson.D{
-							primitive.E{Key: "accounts", Value: newSubAccount},
-						}},
-					}
-
-					_, err = sa.db.users.UpdateOneWithContext(sessionContext, createSubAccountFilter, createSubAccount, nil)
-					if err != nil {
-						abortTransaction(sessionContext)
-						return err
-					}
-				}
-			}
-
-			//save the raw sub account
-			current.AccountID = accountID
-			_, err = sa.db.rawsubaccounts.InsertOneWithContext(sessionContext, &current)
-			if err != nil {
-				abortTransaction(sessionContext)
-				return err
-			}
-
-		}
+	now := time.Now().UTC()
+	data := make([]interface{}, len(items))
+	for i, item := range items {
+		item.AccountID = nil
+		item.LinkStatus = model.SubAccountLinkPending
+		item.DateLinkUpdated = &now
+		data[i] = item
+	}
 
-		//commit the transaction
-		err = sessionContext.CommitTransaction(sessionContext)
-		if err != nil {
-			fmt.Println(err)
-			return err
-		}
-		return nil
-	})
+	_, err := sa.db.rawsubaccounts.InsertMany(data, nil)
 	if err != nil {
 		return err
 	}
-
 	return nil
 }
 
@@ -5386,6 +5651,1074 @@ func (sa *Adapter) DeleteAllSubAccounts() error {
 	return nil
 }
 
+//FindRawSubAccountsByPrimaryAccount finds the raw sub accounts of a primary account with the given link status
+func (sa *Adapter) FindRawSubAccountsByPrimaryAccount(primaryAccount string, linkStatus string) ([]model.RawSubAccount, error) {
+	filter := bson.D{primitive.E{Key: "primary_account", Value: primaryAccount}, primitive.E{Key: "link_status", Value: linkStatus}}
+	options := options.Find()
+	options.SetSort(bson.D{primitive.E{Key: "uin", Value: 1}})
+
+	var result []model.RawSubAccount
+	err := sa.db.rawsubaccounts.Find(filter, &result, options)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		result = []model.RawSubAccount{}
+	}
+	return result, nil
+}
+
+//AcceptRawSubAccount links a pending raw sub account as an account of the primary user. The user account for the same uin is activated
+//if the user has it from a previous link.
+func (sa *Adapter) AcceptRawSubAccount(userID string, uin string) (*model.Account, error) {
+	var account *model.Account
+
+	// transaction
+	err := sa.db.dbClient.UseSession(context.Background(), func(sessionContext mongo.SessionContext) error {
+		err := sessionContext.StartTransaction()
+		if err != nil {
+			log.Printf("error starting a transaction - %s", err)
+			return err
+		}
+
+		//get the user
+		uFilter := bson.D{primitive.E{Key: "_id", Value: userID}}
+		var users []*model.User
+		err = sa.db.users.FindWithContext(sessionContext, uFilter, &users, nil)
+		if err != nil {
+			abortTransaction(sessionContext)
+			return err
+		}
+		if len(users) == 0 {
+			abortTransaction(sessionContext)
+			return errors.New("there is no a user for the provided id - " + userID)
+		}
+		user := users[0]
+
+		//get the pending raw sub account for the user
+		rsaFilter := bson.D{primitive.E{Key: "uin", Value: uin}, primitive.E{Key: "primary_account", Value: user.ExternalID},
+			primitive.E{Key: "link_status", Value: model.SubAccountLinkPending}}
+		var rawSubAccounts []*model.RawSubAccount
+		err = sa.db.rawsubaccounts.FindWithContext(sessionContext, rsaFilter, &rawSubAccounts, nil)
+		if err != nil {
+			abortTransaction(sessionContext)
+			return err
+		}
+		if len(rawSubAccounts) == 0 {
+			abortTransaction(sessionContext)
+			return errors.New("there is no a pending sub account for the provided uin - " + uin)
+		}
+		rsa := rawSubAccounts[0]
+
+		//the sub accounts are active but never default
+		account = &model.Account{ExternalID: rsa.UIN, Default: false, Active: true, FirstName: rsa.FirstName,
+			MiddleName: rsa.MiddleName, LastName: rsa.LastName, BirthDate: rsa.BirthDate, Gender: rsa.Gender, Address1: rsa.Address1,
+			Address2: rsa.Address2, Address3: rsa.Address3, City: rsa.City, State: rsa.State, ZipCode: rsa.ZipCode, Phone: rsa.Phone,
+			Email: rsa.Email}
+
+		var update bson.D
+		var updateFilter bson.D
+		if existing := user.GetAccountByExternalID(rsa.UIN); existing != nil {
+			if existing.Default {
+				abortTransaction(sessionContext)
+				return errors.New("the primary account cannot be a sub account")
+			}
+			account.ID = existing.ID
+			updateFilter = bson.D{primitive.E{Key: "_id", Value: user.ID}, primitive.E{Key: "accounts.id", Value: existing.ID}}
+			update = bson.D{
+				primitive.E{Key: "$set", Value: bson.D{
+					primitive.E{Key: "accounts.$", Value: account},
+				}},
+			}
+		} else {
+			account.ID = uuid.New().String()
+			updateFilter = bson.D{primitive.E{Key: "_id", Value: user.ID}}
+			update = bson.D{
+				primitive.E{Key: "$push", Value: bson.D{
+					primitive.E{Key: "accounts", Value: account},
+				}},
+			}
+		}
+		_, err = sa.db.users.UpdateOneWithContext(sessionContext, updateFilter, update, nil)
+		if err != nil {
+			abortTransaction(sessionContext)
+			return err
+		}
+
+		//update the raw sub account with the linked account id
+		rsaUpdate := bson.D{
+			primitive.E{Key: "$set", Value: bson.D{
+				primitive.E{Key: "account_id", Value: account.ID},
+				primitive.E{Key: "link_status", Value: model.SubAccountLinkAccepted},
+				primitive.E{Key: "date_link_updated", Value: time.Now().UTC()},
+			}},
+		}
+		_, err = sa.db.rawsubaccounts.UpdateOneWithContext(sessionContext, bson.D{primitive.E{Key: "uin", Value: rsa.UIN}}, rsaUpdate, nil)
+		if err != nil {
+			abortTransaction(sessionContext)
+			return err
+		}
+
+		err = sessionContext.CommitTransaction(sessionContext)
+		if err != nil {
+			log.Printf("error on commiting a transaction - %s", err)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return account, nil
+}
+
+//DeclineRawSubAccount declines a pending raw sub account of a primary account
+func (sa *Adapter) DeclineRawSubAccount(primaryAccount string, uin string) error {
+	filter := bson.D{primitive.E{Key: "uin", Value: uin}, primitive.E{Key: "primary_account", Value: primaryAccount},
+		primitive.E{Key: "link_status", Value: model.SubAccountLinkPending}}
+	update := bson.D{
+		primitive.E{Key: "$set", Value: bson.D{
+			primitive.E{Key: "link_status", Value: model.SubAccountLinkDeclined},
+			primitive.E{Key: "date_link_updated", Value: time.Now().UTC()},
+		}},
+	}
+	result, err := sa.db.rawsubaccounts.UpdateOne(filter, update, nil)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return errors.New("there is no a pending sub account for the provided uin - " + uin)
+	}
+	return nil
+}
+
+//RevokeRawSubAccount revokes the link of an accepted raw sub account, the user account is marked as not active
+func (sa *Adapter) RevokeRawSubAccount(uin string) error {
+	// transaction
+	err := sa.db.dbClient.UseSession(context.Background(), func(sessionContext mongo.SessionContext) error {
+		err := sessionContext.StartTransaction()
+		if err != nil {
+			log.Printf("error starting a transaction - %s", err)
+			return err
+		}
+
+		//get the accepted raw sub account
+		rsaFilter := bson.D{primitive.E{Key: "uin", Value: uin}, primitive.E{Key: "link_status", Value: model.SubAccountLinkAccepted}}
+		var rawSubAccounts []*model.RawSubAccount
+		err = sa.db.rawsubaccounts.FindWithContext(sessionContext, rsaFilter, &rawSubAccounts, nil)
+		if err != nil {
+			abortTransaction(sessionContext)
+			return err
+		}
+		if len(rawSubAccounts) == 0 {
+			abortTransaction(sessionContext)
+			return errors.New("there is no an accepted sub account for the provided uin - " + uin)
+		}
+		rawSubAccount := rawSubAccounts[0]
+
+		//mark the sub account as active "false", we do not remove user sub accounts
+		if rawSubAccount.AccountID != nil {
+			updateSubAccountFilter := bson.D{primitive.E{Key: "accounts.id", Value: *rawSubAccount.AccountID}}
+			updateSubAccount := bson.D{
+				primitive.E{Key: "$set", Value: bson.D{
+					primitive.E{Key: "accounts.$.active", Value: false},
+				}},
+			}
+			_, err := sa.db.users.UpdateOneWithContext(sessionContext, updateSubAccountFilter, updateSubAccount, nil)
+			if err != nil {
+				abortTransaction(sessionContext)
+				return err
+			}
+		}
+
+		rsaUpdate := bson.D{
+			primitive.E{Key: "$set", Value: bson.D{
+				primitive.E{Key: "link_status", Value: model.SubAccountLinkRevoked},
+				primitive.E{Key: "date_link_updated", Value: time.Now().UTC()},
+			}},
+		}
+		_, err = sa.db.rawsubaccounts.UpdateOneWithContext(sessionContext, rsaFilter, rsaUpdate, nil)
+		if err != nil {
+			abortTransaction(sessionContext)
+			return err
+		}
+
+		err = sessionContext.CommitTransaction(sessionContext)
+		if err != nil {
+			log.Printf("error on commiting a transaction - %s", err)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+//ReadAllRoles reads all the roles
+func (sa *Adapter) ReadAllRoles() ([]model.Role, error) {
+	filter := bson.D{}
+	options := options.Find()
+	options.SetSort(bson.D{primitive.E{Key: "name", Value: 1}})
+
+	var result []model.Role
+	err := sa.db.roles.Find(filter, &result, options)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		result = []model.Role{}
+	}
+	return result, nil
+}
+
+//FindRole finds a role by id
+func (sa *Adapter) FindRole(ID string) (*model.Role, error) {
+	filter := bson.D{primitive.E{Key: "_id", Value: ID}}
+	var result []model.Role
+	err := sa.db.roles.Find(filter, &result, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		//not found
+		return nil, nil
+	}
+	return &result[0], nil
+}
+
+//CreateRoles creates many roles
+func (sa *Adapter) CreateRoles(roles []model.Role) error {
+	data := make([]interface{}, len(roles))
+	for i, role := range roles {
+		data[i] = role
+	}
+	_, err := sa.db.roles.InsertMany(data, nil)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+//CreateRole creates a role
+func (sa *Adapter) CreateRole(role model.Role) error {
+	_, err := sa.db.roles.InsertOne(&role)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+//UpdateRole updates a role
+func (sa *Adapter) UpdateRole(role model.Role) error {
+	filter := bson.D{primitive.E{Key: "_id", Value: role.ID}}
+	err := sa.db.roles.ReplaceOne(filter, role, nil)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+//DeleteRole deletes a role
+func (sa *Adapter) DeleteRole(ID string) error {
+	filter := bson.D{primitive.E{Key: "_id", Value: ID}}
+	result, err := sa.db.roles.DeleteOne(filter, nil)
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return errors.New("there is no a role for the provided id - " + ID)
+	}
+	return nil
+}
+
+//ReadActiveProviderCredentials reads the provider credentials which are not revoked
+func (sa *Adapter) ReadActiveProviderCredentials() ([]model.ProviderCredential, error) {
+	filter := bson.D{primitive.E{Key: "date_revoked", Value: nil}}
+	var result []model.ProviderCredential
+	err := sa.db.providercredentials.Find(filter, &result, nil)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+//FindProviderCredentials finds the credentials of a provider
+func (sa *Adapter) FindProviderCredentials(providerID string) ([]model.ProviderCredential, error) {
+	filter := bson.D{primitive.E{Key: "provider_id", Value: providerID}}
+	options := options.Find()
+	options.SetSort(bson.D{primitive.E{Key: "date_created", Value: -1}})
+
+	var result []model.ProviderCredential
+	err := sa.db.providercredentials.Find(filter, &result, options)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		result = []model.ProviderCredential{}
+	}
+	return result, nil
+}
+
+//FindProviderCredential finds a provider credential by id
+func (sa *Adapter) FindProviderCredential(ID string) (*model.ProviderCredential, error) {
+	filter := bson.D{primitive.E{Key: "_id", Value: ID}}
+	var result []model.ProviderCredential
+	err := sa.db.providercredentials.Find(filter, &result, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		//not found
+		return nil, nil
+	}
+	return &result[0], nil
+}
+
+//CreateProviderCredential creates a provider credential
+func (sa *Adapter) CreateProviderCredential(credential model.ProviderCredential) error {
+	_, err := sa.db.providercredentials.InsertOne(&credential)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+//UpdateProviderCredential updates a provider credential
+func (sa *Adapter) UpdateProviderCredential(credential model.ProviderCredential) error {
+	filter := bson.D{primitive.E{Key: "_id", Value: credential.ID}}
+	err := sa.db.providercredentials.ReplaceOne(filter, credential, nil)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+//DeleteProviderCredentials deletes the credentials of a provider
+func (sa *Adapter) DeleteProviderCredentials(providerID string) error {
+	filter := bson.D{primitive.E{Key: "provider_id", Value: providerID}}
+	_, err := sa.db.providercredentials.DeleteMany(filter, nil)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+//ReadActiveAPIKeys reads the API keys which are not revoked
+func (sa *Adapter) ReadActiveAPIKeys() ([]model.APIKey, error) {
+	filter := bson.D{primitive.E{Key: "date_revoked", Value: nil}}
+	var result []model.APIKey
+	err := sa.db.apikeys.Find(filter, &result, nil)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+//ReadAllAPIKeys reads all the API keys
+func (sa *Adapter) ReadAllAPIKeys() ([]model.APIKey, error) {
+	filter := bson.D{}
+	options := options.Find()
+	options.SetSort(bson.D{primitive.E{Key: "name", Value: 1}, primitive.E{Key: "date_created", Value: -1}})
+
+	var result []model.APIKey
+	err := sa.db.apikeys.Find(filter, &result, options)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		result = []model.APIKey{}
+	}
+	return result, nil
+}
+
+//FindAPIKey finds an API key by id
+func (sa *Adapter) FindAPIKey(ID string) (*model.APIKey, error) {
+	filter := bson.D{primitive.E{Key: "_id", Value: ID}}
+	var result []model.APIKey
+	err := sa.db.apikeys.Find(filter, &result, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		//not found
+		return nil, nil
+	}
+	return &result[0], nil
+}
+
+//CreateAPIKey creates an API key
+func (sa *Adapter) CreateAPIKey(apiKey model.APIKey) error {
+	_, err := sa.db.apikeys.InsertOne(&apiKey)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+//UpdateAPIKey updates an API key
+func (sa *Adapter) UpdateAPIKey(apiKey model.APIKey) error {
+	filter := bson.D{primitive.E{Key: "_id", Value: apiKey.ID}}
+	err := sa.db.apikeys.ReplaceOne(filter, apiKey, nil)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+//UpdateAPIKeysLastUsed sets the last usage of the API keys by their ids
+func (sa *Adapter) UpdateAPIKeysLastUsed(usage map[string]time.Time) error {
+	for ID, lastUsed := range usage {
+		filter := bson.D{primitive.E{Key: "_id", Value: ID}}
+		update := bson.D{
+			primitive.E{Key: "$max", Value: bson.D{
+				primitive.E{Key: "date_last_used", Value: lastUsed},
+			}},
+		}
+		_, err := sa.db.apikeys.UpdateOne(filter, update, nil)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//ReadAllNotificationTemplates reads all the notification templates
+func (sa *Adapter) ReadAllNotificationTemplates() ([]*model.NotificationTemplate, error) {
+	filter := bson.D{}
+	options := options.Find()
+	options.SetSort(bson.D{primitive.E{Key: "event", Value: 1}})
+
+	var result []*model.NotificationTemplate
+	err := sa.db.notificationtemplates.Find(filter, &result, options)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+//FindNotificationTemplateByEvent finds the notification template for the event. It gives nil if there is no template for it.
+func (sa *Adapter) FindNotificationTemplateByEvent(event string) (*model.NotificationTemplate, error) {
+	filter := bson.D{primitive.E{Key: "event", Value: event}}
+	var result []*model.NotificationTemplate
+	err := sa.db.notificationtemplates.Find(filter, &result, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		//not found
+		return nil, nil
+	}
+	return result[0], nil
+}
+
+//CreateNotificationTemplate creates a notification template
+func (sa *Adapter) CreateNotificationTemplate(event string, variants []model.NotificationTemplateVariant) (*model.NotificationTemplate, error) {
+	id, err := uuid.NewUUID()
+	if err != nil {
+		return nil, err
+	}
+
+	template := model.NotificationTemplate{ID: id.String(), Event: event, Variants: variants, DateCreated: time.Now()}
+	_, err = sa.db.notificationtemplates.InsertOne(&template)
+	if err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+//UpdateNotificationTemplate updates a notification template
+func (sa *Adapter) UpdateNotificationTemplate(ID string, event string, variants []model.NotificationTemplateVariant) (*model.NotificationTemplate, error) {
+	filter := bson.D{primitive.E{Key: "_id", Value: ID}}
+	var result []*model.NotificationTemplate
+	err := sa.db.notificationtemplates.Find(filter, &result, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return nil, errors.New("there is no a notification template for the provided id")
+	}
+	template := result[0]
+
+	//update the values
+	template.Event = event
+	template.Variants = variants
+	dateUpdated := time.Now()
+	template.DateUpdated = &dateUpdated
+
+	err = sa.db.notificationtemplates.ReplaceOne(filter, template, nil)
+	if err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+//DeleteNotificationTemplate deletes a notification template
+func (sa *Adapter) DeleteNotificationTemplate(ID string) error {
+	filter := bson.D{primitive.E{Key: "_id", Value: ID}}
+	result, err := sa.db.notificationtemplates.DeleteOne(filter, nil)
+	if err != nil {
+		return err
+	}
+	if result == nil {
+		return errors.New("result is nil for notification template with id " + ID)
+	}
+	if result.DeletedCount == 0 {
+		return errors.New("there is no a notification template for id " + ID)
+	}
+	return nil
+}
+
+//FindBroadcastAudience gives the uuids of the users which match the broadcast target
+func (sa *Adapter) FindBroadcastAudience(target model.BroadcastTarget) ([]string, error) {
+	conditions := bson.A{bson.M{"uuid": bson.M{"$ne": ""}}}
+
+	//county - the users with manual tests in the county
+	if target.CountyID != nil {
+		accountIDs, err := sa.db.emanualtests.Distinct("user_id", bson.D{primitive.E{Key: "county_id", Value: *target.CountyID}})
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, bson.M{"accounts.id": bson.M{"$in": accountIDs}})
+	}
+
+	//roster membership
+	if target.Roster != nil {
+		uins, err := sa.db.rosters.Distinct("uin", nil)
+		if err != nil {
+			return nil, err
+		}
+		operator := "$nin"
+		if *target.Roster {
+			operator = "$in"
+		}
+		conditions = append(conditions, bson.M{"accounts.external_id": bson.M{operator: uins}})
+	}
+
+	//raw sub account status
+	if target.RawSubAccountStatus != nil {
+		switch *target.RawSubAccountStatus {
+		case model.RawSubAccountStatusPending:
+			//the primary accounts which have sub accounts not accepted yet
+			uins, err := sa.db.rawsubaccounts.Distinct("primary_account", bson.D{primitive.E{Key: "link_status", Value: model.SubAccountLinkPending}})
+			if err != nil {
+				return nil, err
+			}
+			conditions = append(conditions, bson.M{"accounts.external_id": bson.M{"$in": uins}})
+		case model.RawSubAccountStatusCreated:
+			//the linked sub accounts
+			accountIDs, err := sa.db.rawsubaccounts.Distinct("account_id", bson.D{primitive.E{Key: "link_status", Value: model.SubAccountLinkAccepted}})
+			if err != nil {
+				return nil, err
+			}
+			conditions = append(conditions, bson.M{"accounts.id": bson.M{"$in": accountIDs}})
+		default:
+			return nil, errors.New("not supported raw sub account status - " + *target.RawSubAccountStatus)
+		}
+	}
+
+	//uin override category
+	if target.UINOverrideCategory != nil {
+		uins, err := sa.db.uinoverrides.Distinct("uin", bson.D{primitive.E{Key: "category", Value: *target.UINOverrideCategory}})
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, bson.M{"accounts.external_id": bson.M{"$in": uins}})
+	}
+
+	//app version - the accounts which status is from the version
+	if target.AppVersion != nil {
+		accountIDs, err := sa.db.estatus.Distinct("user_id", bson.D{primitive.E{Key: "app_version", Value: *target.AppVersion}})
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, bson.M{"accounts.id": bson.M{"$in": accountIDs}})
+	}
+
+	filter := bson.M{"$and": conditions}
+	uuids, err := sa.db.users.Distinct("uuid", filter)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]string, 0, len(uuids))
+	for _, item := range uuids {
+		if value, ok := item.(string); ok {
+			result = append(result, value)
+		}
+	}
+	return result, nil
+}
+
+//CreateBroadcast creates a broadcast
+func (sa *Adapter) CreateBroadcast(target model.BroadcastTarget, title string, body string, scheduledAt time.Time, createdBy string) (*model.Broadcast, error) {
+	id, err := uuid.NewUUID()
+	if err != nil {
+		return nil, err
+	}
+
+	broadcast := model.Broadcast{ID: id.String(), Target: target, Title: title, Body: body, ScheduledAt: scheduledAt,
+		Status: model.BroadcastStatusScheduled, CreatedBy: createdBy, DateCreated: time.Now()}
+	_, err = sa.db.broadcasts.InsertOne(&broadcast)
+	if err != nil {
+		return nil, err
+	}
+	return &broadcast, nil
+}
+
+//FindBroadcasts finds the broadcasts. It gives all if status is nil
+func (sa *Adapter) FindBroadcasts(status *string) ([]*model.Broadcast, error) {
+	filter := bson.D{}
+	if status != nil {
+		filter = bson.D{primitive.E{Key: "status", Value: *status}}
+	}
+	options := options.Find()
+	options.SetSort(bson.D{primitive.E{Key: "scheduled_at", Value: -1}})
+
+	var result []*model.Broadcast
+	err := sa.db.broadcasts.Find(filter, &result, options)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+//FindBroadcast finds a broadcast
+func (sa *Adapter) FindBroadcast(ID string) (*model.Broadcast, error) {
+	filter := bson.D{primitive.E{Key: "_id", Value: ID}}
+	var result []*model.Broadcast
+	err := sa.db.broadcasts.Find(filter, &result, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		//not found
+		return nil, nil
+	}
+	return result[0], nil
+}
+
+//StartDueBroadcast marks as sending the first scheduled broadcast which time has come and gives it.
+//It gives nil if there is no such broadcast. The update is atomic so the broadcast cannot be started twice.
+func (sa *Adapter) StartDueBroadcast(now time.Time) (*model.Broadcast, error) {
+	filter := bson.D{primitive.E{Key: "status", Value: model.BroadcastStatusScheduled},
+		primitive.E{Key: "scheduled_at", Value: bson.M{"$lte": now}}}
+	update := bson.D{primitive.E{Key: "$set", Value: bson.D{primitive.E{Key: "status", Value: model.BroadcastStatusSending},
+		primitive.E{Key: "date_claimed", Value: now}}}}
+
+	var result model.Broadcast
+	err := sa.db.broadcasts.FindOneAndUpdate(filter, update, &result, nil)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	result.Status = model.BroadcastStatusSending
+	result.DateClaimed = &now
+	return &result, nil
+}
+
+//RenewBroadcastClaim renews the claim of a broadcast which is being sent and saves the sent count so far
+func (sa *Adapter) RenewBroadcastClaim(ID string, sentCount int, now time.Time) error {
+	filter := bson.D{primitive.E{Key: "_id", Value: ID}, primitive.E{Key: "status", Value: model.BroadcastStatusSending}}
+	update := bson.D{primitive.E{Key: "$set", Value: bson.D{primitive.E{Key: "date_claimed", Value: now},
+		primitive.E{Key: "sent_count", Value: sentCount}}}}
+	_, err := sa.db.broadcasts.UpdateOne(filter, update, nil)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+//FailStaleBroadcasts marks as failed the broadcasts in sending which claim was not renewed after the provided date. It gives the failed count.
+func (sa *Adapter) FailStaleBroadcasts(claimedBefore time.Time) (int64, error) {
+	filter := bson.D{primitive.E{Key: "status", Value: model.BroadcastStatusSending},
+		primitive.E{Key: "$or", Value: []bson.M{{"date_claimed": nil}, {"date_claimed": bson.M{"$lt": claimedBefore}}}}}
+	update := bson.D{primitive.E{Key: "$set", Value: bson.D{primitive.E{Key: "status", Value: model.BroadcastStatusFailed}}}}
+	result, err := sa.db.broadcasts.UpdateMany(filter, update, nil)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+//UpdateBroadcastStatus updates the broadcast status and the sending data
+func (sa *Adapter) UpdateBroadcastStatus(ID string, status string, audienceCount *int, sentCount *int, dateSent *time.Time) error {
+	filter := bson.D{primitive.E{Key: "_id", Value: ID}}
+	update := bson.D{
+		primitive.E{Key: "$set", Value: bson.D{
+			primitive.E{Key: "status", Value: status},
+			primitive.E{Key: "audience_count", Value: audienceCount},
+			primitive.E{Key: "sent_count", Value: sentCount},
+			primitive.E{Key: "date_sent", Value: dateSent},
+		}},
+	}
+	_, err := sa.db.broadcasts.UpdateOne(filter, update, nil)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+//CancelBroadcast cancels a scheduled broadcast
+func (sa *Adapter) CancelBroadcast(ID string) error {
+	filter := bson.D{primitive.E{Key: "_id", Value: ID}, primitive.E{Key: "status", Value: model.BroadcastStatusScheduled}}
+	update := bson.D{primitive.E{Key: "$set", Value: bson.D{primitive.E{Key: "status", Value: model.BroadcastStatusCancelled}}}}
+	result, err := sa.db.broadcasts.UpdateOne(filter, update, nil)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return errors.New("there is no a scheduled broadcast for id " + ID)
+	}
+	return nil
+}
+
+//FindTestingSubjects gives the roster members joined with their accounts, uin overrides, latest ctests and counties
+func (sa *Adapter) FindTestingSubjects() ([]model.TestingSubject, error) {
+	//1. load the rosters
+	rosters, err := sa.ReadAllRosters()
+	if err != nil {
+		return nil, err
+	}
+	uins := make([]string, len(rosters))
+	for i, roster := range rosters {
+		uins[i] = roster.UIN
+	}
+
+	//2. load the accounts for the roster members
+	usersFilter := bson.D{primitive.E{Key: "accounts.external_id", Value: bson.M{"$in": uins}}}
+	usersOptions := options.Find()
+	usersOptions.SetProjection(bson.D{primitive.E{Key: "uuid", Value: 1}, primitive.E{Key: "accounts.id", Value: 1},
+		primitive.E{Key: "accounts.external_id", Value: 1}})
+	var users []model.User
+	err = sa.db.users.Find(usersFilter, &users, usersOptions)
+	if err != nil {
+		return nil, err
+	}
+	type accountData struct {
+		accountID string
+		userUUID  string
+	}
+	accounts := make(map[string]accountData, len(users))
+	accountIDs := make([]string, 0, len(users))
+	for _, user := range users {
+		for _, account := range user.Accounts {
+			accounts[account.ExternalID] = accountData{accountID: account.ID, userUUID: user.UUID}
+			accountIDs = append(accountIDs, account.ID)
+		}
+	}
+
+	//3. load the uin overrides
+	overridesList, err := sa.FindUINOverrides(nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	overrides := make(map[string]*model.UINOverride, len(overridesList))
+	for _, override := range overridesList {
+		overrides[override.UIN] = override
+	}
+
+	//4. find the latest ctest date for every account
+	pipeline := []bson.M{
+		{"$match": bson.M{"user_id": bson.M{"$in": accountIDs}}},
+		{"$group": bson.M{"_id": "$user_id", "latest": bson.M{"$max": "$date_created"}}},
+	}
+	var latestTests []struct {
+		AccountID string    `bson:"_id"`
+		Latest    time.Time `bson:"latest"`
+	}
+	err = sa.db.ctests.Aggregate(pipeline, &latestTests, nil)
+	if err != nil {
+		return nil, err
+	}
+	latestDates := make(map[string]time.Time, len(latestTests))
+	for _, item := range latestTests {
+		latestDates[item.AccountID] = item.Latest
+	}
+
+	//5. find the county of the latest manual test for every account - this is the only user county relation
+	countiesPipeline := []bson.M{
+		{"$match": bson.M{"user_id": bson.M{"$in": accountIDs}, "county_id": bson.M{"$ne": nil}}},
+		{"$sort": bson.M{"date_created": -1}},
+		{"$group": bson.M{"_id": "$user_id", "county_id": bson.M{"$first": "$county_id"}}},
+	}
+	var latestCounties []struct {
+		AccountID string `bson:"_id"`
+		CountyID  string `bson:"county_id"`
+	}
+	err = sa.db.emanualtests.Aggregate(countiesPipeline, &latestCounties, nil)
+	if err != nil {
+		return nil, err
+	}
+	counties := make(map[string]string, len(latestCounties))
+	for _, item := range latestCounties {
+		counties[item.AccountID] = item.CountyID
+	}
+
+	//6. join them
+	result := make([]model.TestingSubject, len(rosters))
+	for i, roster := range rosters {
+		uin := roster.UIN
+		subject := model.TestingSubject{UIN: uin, FirstName: roster.FirstName, LastName: roster.LastName,
+			Email: roster.Email, Phone: roster.Phone, Override: overrides[uin]}
+		if account, ok := accounts[uin]; ok {
+			accountID := account.accountID
+			userUUID := account.userUUID
+			subject.AccountID = &accountID
+			subject.UserUUID = &userUUID
+			if latest, ok := latestDates[accountID]; ok {
+				subject.LatestTestDate = &latest
+			}
+			if countyID, ok := counties[accountID]; ok {
+				subject.CountyID = &countyID
+			}
+		}
+		result[i] = subject
+	}
+	return result, nil
+}
+
+//CreateTestingReminder records that a testing reminder is sent. It gives false if the reminder has been already recorded.
+func (sa *Adapter) CreateTestingReminder(uin string, dueDate time.Time, reminderType string) (bool, error) {
+	item := bson.D{primitive.E{Key: "uin", Value: uin}, primitive.E{Key: "due_date", Value: dueDate},
+		primitive.E{Key: "type", Value: reminderType}, primitive.E{Key: "date_created", Value: time.Now()}}
+	_, err := sa.db.testingreminders.InsertOne(item)
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+//CreateExposureVerificationCode creates an exposure verification code. It gives false if there is already a code with the same hash.
+func (sa *Adapter) CreateExposureVerificationCode(code model.ExposureVerificationCode) (bool, error) {
+	_, err := sa.db.exposurecodes.InsertOne(code)
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+//CountExposureVerificationCodes counts the exposure verification codes issued by the provided issuer after the provided date
+func (sa *Adapter) CountExposureVerificationCodes(issuedBy string, from time.Time) (int64, error) {
+	filter := bson.D{primitive.E{Key: "issued_by", Value: issuedBy}, primitive.E{Key: "date_created", Value: bson.M{"$gte": from}}}
+	return sa.db.exposurecodes.CountDocuments(filter)
+}
+
+//FindExposureVerificationCodes finds the exposure verification codes, the newest first
+func (sa *Adapter) FindExposureVerificationCodes(issuedBy *string, status *string, limit int64) ([]model.ExposureVerificationCode, error) {
+	filter := bson.D{}
+	if issuedBy != nil {
+		filter = append(filter, primitive.E{Key: "issued_by", Value: *issuedBy})
+	}
+	if status != nil {
+		filter = append(filter, primitive.E{Key: "status", Value: *status})
+	}
+
+	options := options.Find()
+	options.SetSort(bson.D{primitive.E{Key: "date_created", Value: -1}})
+	if limit > 0 {
+		options.SetLimit(limit)
+	}
+
+	var result []model.ExposureVerificationCode
+	err := sa.db.exposurecodes.Find(filter, &result, options)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+//ClaimExposureVerificationCode exchanges a valid issued code for an upload token. It gives nil if there is no such valid code.
+func (sa *Adapter) ClaimExposureVerificationCode(codeHash string, userID string, tokenHash string, tokenExpiresAt time.Time) (*model.ExposureVerificationCode, error) {
+	now := time.Now()
+	filter := bson.D{primitive.E{Key: "code_hash", Value: codeHash},
+		primitive.E{Key: "status", Value: model.ExposureCodeStatusIssued},
+		primitive.E{Key: "expires_at", Value: bson.M{"$gt": now}},
+		primitive.E{Key: "$or", Value: []bson.M{{"user_id": nil}, {"user_id": userID}}}}
+	update := bson.D{primitive.E{Key: "$set", Value: bson.D{
+		primitive.E{Key: "status", Value: model.ExposureCodeStatusClaimed},
+		primitive.E{Key: "token_hash", Value: tokenHash},
+		primitive.E{Key: "token_expires_at", Value: tokenExpiresAt},
+		primitive.E{Key: "date_claimed", Value: now},
+	}}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var result model.ExposureVerificationCode
+	err := sa.db.exposurecodes.FindOneAndUpdate(filter, update, &result, opts)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+//CreateTraceReportsWithUploadToken marks a valid upload token as used and creates the trace reports with the token report type. It uses a transaction,
+//so the token stays valid if the reports are not created. It gives nil code if there is no such valid token.
+func (sa *Adapter) CreateTraceReportsWithUploadToken(tokenHash string, items []model.TraceExposure) (*model.ExposureVerificationCode, int, error) {
+	var code *model.ExposureVerificationCode
+	insertedCount := 0
+
+	// transaction
+	err := sa.db.dbClient.UseSession(context.Background(), func(sessionContext mongo.SessionContext) error {
+		err := sessionContext.StartTransaction()
+		if err != nil {
+			log.Printf("error starting a transaction - %s", err)
+			return err
+		}
+
+		//use the token
+		now := time.Now()
+		filter := bson.D{primitive.E{Key: "token_hash", Value: tokenHash},
+			primitive.E{Key: "status", Value: model.ExposureCodeStatusClaimed},
+			primitive.E{Key: "token_expires_at", Value: bson.M{"$gt": now}}}
+		update := bson.D{primitive.E{Key: "$set", Value: bson.D{
+			primitive.E{Key: "status", Value: model.ExposureCodeStatusUsed},
+			primitive.E{Key: "date_used", Value: now},
+		}}}
+		opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+		var result model.ExposureVerificationCode
+		err = sa.db.exposurecodes.FindOneAndUpdateWithContext(sessionContext, filter, update, &result, opts)
+		if err == mongo.ErrNoDocuments {
+			abortTransaction(sessionContext)
+			return nil
+		}
+		if err != nil {
+			abortTransaction(sessionContext)
+			return err
+		}
+
+		//insert the items
+		for i := range items {
+			items[i].ReportType = &result.ReportType
+		}
+		insertedCount, err = sa.insertNewTraceReports(sessionContext, items)
+		if err != nil {
+			abortTransaction(sessionContext)
+			return err
+		}
+
+		err = sessionContext.CommitTransaction(sessionContext)
+		if err != nil {
+			log.Printf("error on commiting a transaction - %s", err)
+			return err
+		}
+		code = &result
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return code, insertedCount, nil
+}
+
+//CreateGAENTraceReports creates the published exposure keys if the verification certificate has not been used yet. It gives false if it has been used.
+//The keys which have been already published are skipped.
+func (sa *Adapter) CreateGAENTraceReports(certificateHash string, certificateExpiresAt time.Time, items []model.TraceExposure) (bool, int, error) {
+	used := false
+	insertedCount := 0
+
+	// transaction
+	err := sa.db.dbClient.UseSession(context.Background(), func(sessionContext mongo.SessionContext) error {
+		err := sessionContext.StartTransaction()
+		if err != nil {
+			log.Printf("error starting a transaction - %s", err)
+			return err
+		}
+
+		//use the certificate
+		certificate := bson.D{primitive.E{Key: "_id", Value: certificateHash},
+			primitive.E{Key: "expires_at", Value: certificateExpiresAt},
+			primitive.E{Key: "date_created", Value: time.Now()}}
+		_, err = sa.db.exposurecertificates.InsertOneWithContext(sessionContext, certificate)
+		if mongo.IsDuplicateKeyError(err) {
+			abortTransaction(sessionContext)
+			used = true
+			return nil
+		}
+		if err != nil {
+			abortTransaction(sessionContext)
+			return err
+		}
+
+		//insert the items
+		insertedCount, err = sa.insertNewTraceReports(sessionContext, items)
+		if err != nil {
+			abortTransaction(sessionContext)
+			return err
+		}
+
+		err = sessionContext.CommitTransaction(sessionContext)
+		if err != nil {
+			log.Printf("error on commiting a transaction - %s", err)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return false, 0, err
+	}
+	return !used, insertedCount, nil
+}
+
+//insertNewTraceReports inserts the items skipping the GAEN keys which are already there or repeated in the items
+func (sa *Adapter) insertNewTraceReports(sessionContext mongo.SessionContext, items []model.TraceExposure) (int, error) {
+	teks := []string{}
+	for _, item := range items {
+		if item.RollingStartNumber != nil {
+			teks = append(teks, item.TEK)
+		}
+	}
+
+	existing := map[string]bool{}
+	if len(teks) > 0 {
+		filter := bson.D{primitive.E{Key: "tek", Value: bson.M{"$in": teks}},
+			primitive.E{Key: "rolling_start_number", Value: bson.M{"$exists": true}}}
+		var found []model.TraceExposure
+		err := sa.db.traceexposures.FindWithContext(sessionContext, filter, &found, nil)
+		if err != nil {
+			return 0, err
+		}
+		for _, item := range found {
+			existing[item.TEK] = true
+		}
+	}
+
+	//we need create []Interface{}!
+	data := []interface{}{}
+	for _, item := range items {
+		if item.RollingStartNumber != nil {
+			if existing[item.TEK] {
+				continue
+			}
+			existing[item.TEK] = true
+		}
+		data = append(data, item)
+	}
+	if len(data) == 0 {
+		return 0, nil
+	}
+
+	result, err := sa.db.traceexposures.InsertManyWithContext(sessionContext, data, nil)
+	if err != nil {
+		return 0, err
+	}
+	return len(result.InsertedIDs), nil
+}
+
 func (sa *Adapter) containsCountyStatus(ID string, list []countyStatus) bool {
 	if list == nil {
 		return false
@@ -190,6 +190,33 @@ func (collWrapper *collectionWrapper) UpdateOneWithContext(ctx context.Context,
 	return updateResult, nil
 }
 
+func (collWrapper *collectionWrapper) UpdateMany(filter interface{}, update interface{}, opts *options.UpdateOptions) (*mongo.UpdateResult, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), collWrapper.database.mongoTimeout)
+	defer cancel()
+
+	updateResult, err := collWrapper.coll.UpdateMany(ctx, filter, update, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return updateResult, nil
+}
+
+func (collWrapper *collectionWrapper) FindOneAndUpdate(filter interface{}, update interface{}, result interface{}, opts *options.FindOneAndUpdateOptions) error {
+	return collWrapper.FindOneAndUpdateWithContext(context.Background(), filter, update, result, opts)
+}
+
+func (collWrapper *collectionWrapper) FindOneAndUpdateWithContext(ctx context.Context, filter interface{}, update interface{}, result interface{}, opts *options.FindOneAndUpdateOptions) error {
+	ctx, cancel := context.WithTimeout(ctx, collWrapper.database.mongoTimeout)
+	defer cancel()
+
+	singleResult := collWrapper.coll.FindOneAndUpdate(ctx, filter, update, opts)
+	if singleResult.Err() != nil {
+		return singleResult.Err()
+	}
+	return singleResult.Decode(result)
+}
+
 func (collWrapper *collectionWrapper) CountDocuments(filter interface{}) (int64, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), collWrapper.database.mongoTimeout)
 	defer cancel()
@@ -206,6 +233,21 @@ func (collWrapper *collectionWrapper) CountDocuments(filter interface{}) (int64,
 	return count, nil
 }
 
+func (collWrapper *collectionWrapper) Distinct(fieldName string, filter interface{}) ([]interface{}, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), collWrapper.database.mongoTimeout)
+	defer cancel()
+
+	if filter == nil {
+		filter = bson.D{}
+	}
+
+	values, err := collWrapper.coll.Distinct(ctx, fieldName, filter)
+	if err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
 func (collWrapper *collectionWrapper) Watch(pipeline interface{}) error {
 	if pipeline == nil {
 		pipeline = []bson.M{}
@@ -223,9 +265,10 @@ func (collWrapper *collectionWrapper) Watch(pipeline interface{}) error {
 	}
 	defer cur.Close(ctx)
 
-	var changeDoc map[string]interface{}
 	log.Println("waiting for changes")
 	for cur.Next(ctx) {
+		//a new map for every change, so no fields are left from the previous one
+		var changeDoc map[string]interface{}
 		if e := cur.Decode(&changeDoc); e != nil {
 			log.Printf("error decoding: %s\n", e)
 		}
@@ -21,9 +21,12 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"health/core"
 	"health/core/model"
+	"health/utils"
 	"log"
+	"strconv"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -40,33 +43,45 @@ type database struct {
 	db       *mongo.Database
 	dbClient *mongo.Client
 
-	configs           *collectionWrapper
-	users             *collectionWrapper
-	providers         *collectionWrapper
-	locations         *collectionWrapper
-	ctests            *collectionWrapper
-	emanualtests      *collectionWrapper
-	resources         *collectionWrapper
-	faq               *collectionWrapper
-	news              *collectionWrapper
-	estatus           *collectionWrapper
-	ehistory          *collectionWrapper
-	counties          *collectionWrapper
-	testtypes         *collectionWrapper
-	rules             *collectionWrapper
-	symptomgroups     *collectionWrapper //old
-	symptomrules      *collectionWrapper //old
-	symptoms          *collectionWrapper
-	crules            *collectionWrapper
-	traceexposures    *collectionWrapper
-	accessrules       *collectionWrapper
-	uinoverrides      *collectionWrapper
-	uinbuildingaccess *collectionWrapper
-	appversions       *collectionWrapper
-	rosters           *collectionWrapper
-	rawsubaccounts    *collectionWrapper
+	configs               *collectionWrapper
+	users                 *collectionWrapper
+	providers             *collectionWrapper
+	locations             *collectionWrapper
+	ctests                *collectionWrapper
+	emanualtests          *collectionWrapper
+	resources             *collectionWrapper
+	faq                   *collectionWrapper
+	news                  *collectionWrapper
+	estatus               *collectionWrapper
+	ehistory              *collectionWrapper
+	counties              *collectionWrapper
+	testtypes             *collectionWrapper
+	rules                 *collectionWrapper
+	symptomgroups         *collectionWrapper //old
+	symptomrules          *collectionWrapper //old
+	symptoms              *collectionWrapper
+	crules                *collectionWrapper
+	traceexposures        *collectionWrapper
+	accessrules           *collectionWrapper
+	uinoverrides          *collectionWrapper
+	uinbuildingaccess     *collectionWrapper
+	appversions           *collectionWrapper
+	rosters               *collectionWrapper
+	rawsubaccounts        *collectionWrapper
+	notificationtemplates *collectionWrapper
+	broadcasts            *collectionWrapper
+	testingreminders      *collectionWrapper
+	exposurecodes         *collectionWrapper
+	exposurepurges        *collectionWrapper
+	exposurecertificates  *collectionWrapper
+	rosterimports         *collectionWrapper
+	roles                 *collectionWrapper
+	providercredentials   *collectionWrapper
+	apikeys               *collectionWrapper
 
 	listener core.StorageListener
+
+	lastRostersTxn string //the transaction of the last rosters change
 }
 
 func (m *database) start() error {
@@ -231,6 +246,56 @@ func (m *database) start() error {
 	if err != nil {
 		return err
 	}
+	notificationtemplates := &collectionWrapper{database: m, coll: db.Collection("notificationtemplates")}
+	err = m.applyNotificationTemplatesChecks(notificationtemplates)
+	if err != nil {
+		return err
+	}
+	broadcasts := &collectionWrapper{database: m, coll: db.Collection("broadcasts")}
+	err = m.applyBroadcastsChecks(broadcasts)
+	if err != nil {
+		return err
+	}
+	testingreminders := &collectionWrapper{database: m, coll: db.Collection("testingreminders")}
+	err = m.applyTestingRemindersChecks(testingreminders)
+	if err != nil {
+		return err
+	}
+	exposurecodes := &collectionWrapper{database: m, coll: db.Collection("exposurecodes")}
+	err = m.applyExposureCodesChecks(exposurecodes)
+	if err != nil {
+		return err
+	}
+	exposurepurges := &collectionWrapper{database: m, coll: db.Collection("exposurepurges")}
+	err = m.applyExposurePurgesChecks(exposurepurges)
+	if err != nil {
+		return err
+	}
+	exposurecertificates := &collectionWrapper{database: m, coll: db.Collection("exposurecertificates")}
+	err = m.applyExposureCertificatesChecks(exposurecertificates)
+	if err != nil {
+		return err
+	}
+	rosterimports := &collectionWrapper{database: m, coll: db.Collection("rosterimports")}
+	err = m.applyRosterImportsChecks(rosterimports)
+	if err != nil {
+		return err
+	}
+	roles := &collectionWrapper{database: m, coll: db.Collection("roles")}
+	err = m.applyRolesChecks(roles)
+	if err != nil {
+		return err
+	}
+	providercredentials := &collectionWrapper{database: m, coll: db.Collection("providercredentials")}
+	err = m.applyProviderCredentialsChecks(providercredentials)
+	if err != nil {
+		return err
+	}
+	apikeys := &collectionWrapper{database: m, coll: db.Collection("apikeys")}
+	err = m.applyAPIKeysChecks(apikeys)
+	if err != nil {
+		return err
+	}
 
 	//asign the db, db client and the collections
 	m.db = db
@@ -261,6 +326,16 @@ func (m *database) start() error {
 	m.appversions = appversions
 	m.rosters = rosters
 	m.rawsubaccounts = rawsubaccounts
+	m.notificationtemplates = notificationtemplates
+	m.broadcasts = broadcasts
+	m.testingreminders = testingreminders
+	m.exposurecodes = exposurecodes
+	m.exposurepurges = exposurepurges
+	m.exposurecertificates = exposurecertificates
+	m.rosterimports = rosterimports
+	m.roles = roles
+	m.providercredentials = providercredentials
+	m.apikeys = apikeys
 
 	//watch for config changes
 	go m.configs.Watch(nil)
@@ -274,6 +349,15 @@ func (m *database) start() error {
 	//watch for rawsubaccounts changes
 	go m.rawsubaccounts.Watch(nil)
 
+	//watch for roles changes
+	go m.roles.Watch(nil)
+
+	//watch for provider credentials changes
+	go m.providercredentials.Watch(nil)
+
+	//watch for API keys changes
+	go m.apikeys.Watch(nil)
+
 	//watch for users changes
 	go m.users.Watch(nil)
 
@@ -631,6 +715,27 @@ func (m *database) applyTraceExposuresChecks(traceExposures *collectionWrapper)
 		return err
 	}
 
+	//add index
+	err = traceExposures.AddIndex(bson.D{primitive.E{Key: "expirestamp", Value: 1}}, false)
+	if err != nil {
+		return err
+	}
+
+	//add index - for the pages
+	err = traceExposures.AddIndex(bson.D{primitive.E{Key: "date_added", Value: 1}, primitive.E{Key: "_id", Value: 1}}, false)
+	if err != nil {
+		return err
+	}
+
+	//add index - unique, the keys published through the GAEN publish API are not duplicated
+	uniqueOptions := options.Index()
+	uniqueOptions.SetUnique(true)
+	uniqueOptions.SetPartialFilterExpression(bson.D{primitive.E{Key: "rolling_start_number", Value: bson.M{"$exists": true}}})
+	err = traceExposures.AddIndexWithOptions(bson.D{primitive.E{Key: "tek", Value: 1}}, uniqueOptions)
+	if err != nil {
+		return err
+	}
+
 	log.Println("traceExposures checks passed")
 	return nil
 }
@@ -716,10 +821,88 @@ func (m *database) applyRostersChecks(rosters *collectionWrapper) error {
 		return err
 	}
 
+	err = rosters.AddIndex(bson.D{primitive.E{Key: "last_name", Value: 1}}, false)
+	if err != nil {
+		return err
+	}
+
+	err = rosters.AddIndex(bson.D{primitive.E{Key: "badge_type", Value: 1}}, false)
+	if err != nil {
+		return err
+	}
+
+	//the items created before the typed rosters could have any fields
+	err = m.migrateRosters(rosters)
+	if err != nil {
+		return err
+	}
+
 	log.Println("rosters checks passed")
 	return nil
 }
 
+//migrateRosters moves the unknown fields to the roster attributes and converts the fields values to strings
+func (m *database) migrateRosters(rosters *collectionWrapper) error {
+	knownFields := bson.A{"_id", "attributes"}
+	conditions := bson.A{}
+	for _, field := range model.RosterFields {
+		knownFields = append(knownFields, field)
+		conditions = append(conditions, bson.M{field: bson.M{"$exists": true, "$not": bson.M{"$type": "string"}}})
+	}
+	conditions = append(conditions, bson.M{"$expr": bson.M{"$gt": bson.A{bson.M{"$size": bson.M{"$setDifference": bson.A{
+		bson.M{"$map": bson.M{"input": bson.M{"$objectToArray": "$$ROOT"}, "in": "$$this.k"}}, knownFields}}}, 0}}})
+
+	var items []bson.M
+	err := rosters.Find(bson.M{"$or": conditions}, &items, nil)
+	if err != nil {
+		return err
+	}
+	if len(items) == 0 {
+		return nil
+	}
+
+	log.Printf("migrating %d rosters.....", len(items))
+	for _, item := range items {
+		set := bson.M{}
+		unset := bson.M{}
+		for key, value := range item {
+			if key == "_id" || key == "attributes" {
+				continue
+			}
+			if !model.IsRosterField(key) {
+				set["attributes."+key] = rosterValueString(value)
+				unset[key] = ""
+			} else if _, ok := value.(string); !ok {
+				set[key] = rosterValueString(value)
+			}
+		}
+
+		update := bson.M{"$set": set}
+		if len(unset) > 0 {
+			update["$unset"] = unset
+		}
+		_, err = rosters.UpdateOne(bson.M{"_id": item["_id"]}, update, nil)
+		if err != nil {
+			return err
+		}
+	}
+	log.Println("rosters migrated")
+	return nil
+}
+
+func rosterValueString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
 func (m *database) applyRawSubAccountsChecks(rawsubaccounts *collectionWrapper) error {
 	log.Println("apply rawsubaccounts checks.....")
 
@@ -739,10 +922,232 @@ func (m *database) applyRawSubAccountsChecks(rawsubaccounts *collectionWrapper)
 		return err
 	}
 
+	//the sub accounts added before the linking workflow were linked automatically
+	linkedFilter := bson.D{primitive.E{Key: "link_status", Value: bson.M{"$exists": false}}, primitive.E{Key: "account_id", Value: bson.M{"$ne": nil}}}
+	_, err = rawsubaccounts.UpdateMany(linkedFilter, bson.M{"$set": bson.M{"link_status": model.SubAccountLinkAccepted}}, nil)
+	if err != nil {
+		return err
+	}
+	notLinkedFilter := bson.D{primitive.E{Key: "link_status", Value: bson.M{"$exists": false}}}
+	_, err = rawsubaccounts.UpdateMany(notLinkedFilter, bson.M{"$set": bson.M{"link_status": model.SubAccountLinkPending}}, nil)
+	if err != nil {
+		return err
+	}
+
 	log.Println("rawsubaccounts checks passed")
 	return nil
 }
 
+func (m *database) applyNotificationTemplatesChecks(notificationtemplates *collectionWrapper) error {
+	log.Println("apply notification templates checks.....")
+
+	//add index - unique
+	err := notificationtemplates.AddIndex(bson.D{primitive.E{Key: "event", Value: 1}}, true)
+	if err != nil {
+		return err
+	}
+
+	log.Println("notification templates checks passed")
+	return nil
+}
+
+func (m *database) applyBroadcastsChecks(broadcasts *collectionWrapper) error {
+	log.Println("apply broadcasts checks.....")
+
+	//add index
+	err := broadcasts.AddIndex(bson.D{primitive.E{Key: "status", Value: 1}, primitive.E{Key: "scheduled_at", Value: 1}}, false)
+	if err != nil {
+		return err
+	}
+
+	log.Println("broadcasts checks passed")
+	return nil
+}
+
+func (m *database) applyTestingRemindersChecks(testingreminders *collectionWrapper) error {
+	log.Println("apply testing reminders checks.....")
+
+	//add index - unique, a reminder is sent only once
+	err := testingreminders.AddIndex(bson.D{primitive.E{Key: "uin", Value: 1}, primitive.E{Key: "due_date", Value: 1}, primitive.E{Key: "type", Value: 1}}, true)
+	if err != nil {
+		return err
+	}
+
+	//add index - delete the old records, they are not needed after the next test
+	options := options.Index()
+	eas := int32(60 * 60 * 24 * 180) //180 days
+	options.ExpireAfterSeconds = &eas
+	err = testingreminders.AddIndexWithOptions(bson.D{primitive.E{Key: "date_created", Value: 1}}, options)
+	if err != nil {
+		return err
+	}
+
+	log.Println("testing reminders checks passed")
+	return nil
+}
+
+func (m *database) applyExposureCodesChecks(exposurecodes *collectionWrapper) error {
+	log.Println("apply exposure codes checks.....")
+
+	//add index - unique, the codes are looked up by their hashes
+	err := exposurecodes.AddIndex(bson.D{primitive.E{Key: "code_hash", Value: 1}}, true)
+	if err != nil {
+		return err
+	}
+
+	//add index
+	err = exposurecodes.AddIndex(bson.D{primitive.E{Key: "token_hash", Value: 1}}, false)
+	if err != nil {
+		return err
+	}
+
+	//add index
+	err = exposurecodes.AddIndex(bson.D{primitive.E{Key: "issued_by", Value: 1}, primitive.E{Key: "date_created", Value: 1}}, false)
+	if err != nil {
+		return err
+	}
+
+	//add index - delete the codes a month after they expire, the issuing is kept in the audit
+	options := options.Index()
+	eas := int32(60 * 60 * 24 * 30) //30 days
+	options.ExpireAfterSeconds = &eas
+	err = exposurecodes.AddIndexWithOptions(bson.D{primitive.E{Key: "expires_at", Value: 1}}, options)
+	if err != nil {
+		return err
+	}
+
+	log.Println("exposure codes checks passed")
+	return nil
+}
+
+func (m *database) applyExposurePurgesChecks(exposurepurges *collectionWrapper) error {
+	log.Println("apply exposure purges checks.....")
+
+	//add index - keep the purges history for 90 days
+	options := options.Index()
+	eas := int32(60 * 60 * 24 * 90) //90 days
+	options.ExpireAfterSeconds = &eas
+	err := exposurepurges.AddIndexWithOptions(bson.D{primitive.E{Key: "date", Value: 1}}, options)
+	if err != nil {
+		return err
+	}
+
+	log.Println("exposure purges checks passed")
+	return nil
+}
+
+func (m *database) applyExposureCertificatesChecks(exposurecertificates *collectionWrapper) error {
+	log.Println("apply exposure certificates checks.....")
+
+	//add index - the used certificates are kept until they expire
+	options := options.Index()
+	eas := int32(0)
+	options.ExpireAfterSeconds = &eas
+	err := exposurecertificates.AddIndexWithOptions(bson.D{primitive.E{Key: "expires_at", Value: 1}}, options)
+	if err != nil {
+		return err
+	}
+
+	log.Println("exposure certificates checks passed")
+	return nil
+}
+
+func (m *database) applyRosterImportsChecks(rosterimports *collectionWrapper) error {
+	log.Println("apply roster imports checks.....")
+
+	//add index - the reports contain personal data so they are kept for 30 days only
+	options := options.Index()
+	eas := int32(60 * 60 * 24 * 30) //30 days
+	options.ExpireAfterSeconds = &eas
+	err := rosterimports.AddIndexWithOptions(bson.D{primitive.E{Key: "date_created", Value: 1}}, options)
+	if err != nil {
+		return err
+	}
+
+	log.Println("roster imports checks passed")
+	return nil
+}
+
+//getChangeTransaction gives the transaction of a change, it is empty if the change is not made in a transaction
+func getChangeTransaction(changeDoc map[string]interface{}) string {
+	txnNumber, ok := changeDoc["txnNumber"]
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("%v-%v", changeDoc["lsid"], txnNumber)
+}
+
+//isFieldsUpdate checks if the change is an update of only the provided fields
+func isFieldsUpdate(changeDoc map[string]interface{}, fields ...string) bool {
+	if changeDoc["operationType"] != "update" {
+		return false
+	}
+	updateDescription, ok := changeDoc["updateDescription"].(map[string]interface{})
+	if !ok {
+		return false
+	}
+	updatedFields, ok := updateDescription["updatedFields"].(map[string]interface{})
+	if !ok {
+		return false
+	}
+	for field := range updatedFields {
+		if !utils.Contains(fields, field) {
+			return false
+		}
+	}
+	removedFields, ok := updateDescription["removedFields"].(primitive.A)
+	return !ok || len(removedFields) == 0
+}
+
+func (m *database) applyRolesChecks(roles *collectionWrapper) error {
+	log.Println("apply roles checks.....")
+
+	//add indexes
+	err := roles.AddIndex(bson.D{primitive.E{Key: "name", Value: 1}}, true)
+	if err != nil {
+		return err
+	}
+
+	err = roles.AddIndex(bson.D{primitive.E{Key: "groups", Value: 1}}, false)
+	if err != nil {
+		return err
+	}
+
+	log.Println("roles checks passed")
+	return nil
+}
+
+func (m *database) applyProviderCredentialsChecks(credentials *collectionWrapper) error {
+	log.Println("apply provider credentials checks.....")
+
+	//add indexes
+	err := credentials.AddIndex(bson.D{primitive.E{Key: "key_hash", Value: 1}}, true)
+	if err != nil {
+		return err
+	}
+
+	err = credentials.AddIndex(bson.D{primitive.E{Key: "provider_id", Value: 1}}, false)
+	if err != nil {
+		return err
+	}
+
+	log.Println("provider credentials checks passed")
+	return nil
+}
+
+func (m *database) applyAPIKeysChecks(apiKeys *collectionWrapper) error {
+	log.Println("apply API keys checks.....")
+
+	//add indexes
+	err := apiKeys.AddIndex(bson.D{primitive.E{Key: "key_hash", Value: 1}}, true)
+	if err != nil {
+		return err
+	}
+
+	log.Println("API keys checks passed")
+	return nil
+}
+
 func (m *database) onDataChanged(changeDoc map[string]interface{}) {
 	if changeDoc == nil {
 		return
@@ -771,9 +1176,38 @@ func (m *database) onDataChanged(changeDoc map[string]interface{}) {
 	} else if "rosters" == coll {
 		log.Println("rosters collection changed")
 
+		//the changes made in one transaction are notified once
+		txn := getChangeTransaction(changeDoc)
+		if len(txn) > 0 && txn == m.lastRostersTxn {
+			return
+		}
+		m.lastRostersTxn = txn
+
 		if m.listener != nil {
 			m.listener.OnRostersChanged()
 		}
+	} else if "roles" == coll {
+		log.Println("roles collection changed")
+
+		if m.listener != nil {
+			m.listener.OnRolesChanged()
+		}
+	} else if "providercredentials" == coll {
+		log.Println("providercredentials collection changed")
+
+		if m.listener != nil {
+			m.listener.OnProviderCredentialsChanged()
+		}
+	} else if "apikeys" == coll {
+		//the last usage is saved often and it does not affect the keys checks
+		if isFieldsUpdate(changeDoc, "date_last_used") {
+			return
+		}
+		log.Println("apikeys collection changed")
+
+		if m.listener != nil {
+			m.listener.OnAPIKeysChanged()
+		}
 	} else if "rawsubaccounts" == coll {
 		log.Println("rawsubaccounts collection changed")
 
@@ -0,0 +1,238 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+
+package storage
+
+import (
+	"encoding/base64"
+	"errors"
+	"health/utils"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+//QueryFilter gives the mongo filter for the query conditions and the query cursor
+func QueryFilter(q *utils.Query) (bson.D, error) {
+	if q == nil {
+		return bson.D{}, nil
+	}
+
+	var conditions []interface{}
+	for _, condition := range q.Conditions {
+		conditions = append(conditions, constructQueryCondition(condition))
+	}
+
+	if q.Cursor != nil {
+		cursorCondition, err := constructQueryCursorCondition(q)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, cursorCondition)
+	}
+
+	if len(conditions) == 0 {
+		return bson.D{}, nil
+	}
+	return bson.D{primitive.E{Key: "$and", Value: conditions}}, nil
+}
+
+//QuerySort gives the mongo sort for the query. The id is always the last sort field, so the order is stable for the cursors.
+func QuerySort(q *utils.Query) bson.D {
+	sort := bson.D{}
+	if q == nil {
+		return sort
+	}
+	for _, item := range querySortFields(q) {
+		direction := 1
+		if item.Desc {
+			direction = -1
+		}
+		sort = append(sort, primitive.E{Key: item.Field, Value: direction})
+	}
+	return sort
+}
+
+//QueryFindOptions gives the find options - sort and limit, for the query
+func QueryFindOptions(q *utils.Query) *options.FindOptions {
+	options := options.Find()
+	if q == nil {
+		return options
+	}
+	options.SetSort(QuerySort(q))
+	if q.Limit > 0 {
+		options.SetLimit(int64(q.Limit))
+	}
+	return options
+}
+
+//DecodeQueryPage decodes the page items in the result which must be a pointer to a slice. It gives the cursor for the next page,
+//it is empty if this is the last page.
+func DecodeQueryPage(q *utils.Query, items []bson.Raw, result interface{}) (string, error) {
+	resultValue := reflect.ValueOf(result)
+	if resultValue.Kind() != reflect.Ptr || resultValue.Elem().Kind() != reflect.Slice {
+		return "", errors.New("the result must be a pointer to a slice")
+	}
+	sliceValue := resultValue.Elem()
+	elemType := sliceValue.Type().Elem()
+
+	for _, item := range items {
+		var elem reflect.Value
+		if elemType.Kind() == reflect.Ptr {
+			elem = reflect.New(elemType.Elem())
+			err := bson.Unmarshal(item, elem.Interface())
+			if err != nil {
+				return "", err
+			}
+		} else {
+			pointer := reflect.New(elemType)
+			err := bson.Unmarshal(item, pointer.Interface())
+			if err != nil {
+				return "", err
+			}
+			elem = pointer.Elem()
+		}
+		sliceValue = reflect.Append(sliceValue, elem)
+	}
+	resultValue.Elem().Set(sliceValue)
+
+	//there is a next page only if this one is full
+	if q == nil || q.Limit <= 0 || len(items) < q.Limit {
+		return "", nil
+	}
+	return encodeQueryCursor(q, items[len(items)-1])
+}
+
+func constructQueryCondition(condition utils.QueryCondition) bson.D {
+	switch condition.Operator {
+	case utils.QueryOperatorNe:
+		return bson.D{primitive.E{Key: condition.Field, Value: bson.M{"$ne": condition.Values[0]}}}
+	case utils.QueryOperatorIn:
+		return bson.D{primitive.E{Key: condition.Field, Value: bson.M{"$in": condition.Values}}}
+	case utils.QueryOperatorRange:
+		bounds := bson.M{}
+		if condition.Values[0] != nil {
+			bounds["$gte"] = condition.Values[0]
+		}
+		if condition.Values[1] != nil {
+			bounds["$lte"] = condition.Values[1]
+		}
+		return bson.D{primitive.E{Key: condition.Field, Value: bounds}}
+	case utils.QueryOperatorPrefix:
+		pattern := "^" + regexp.QuoteMeta(condition.Values[0].(string))
+		return bson.D{primitive.E{Key: condition.Field, Value: primitive.Regex{Pattern: pattern}}}
+	case utils.QueryOperatorExists:
+		if condition.Values[0].(bool) {
+			return bson.D{primitive.E{Key: condition.Field, Value: bson.M{"$ne": nil}}}
+		}
+		return bson.D{primitive.E{Key: condition.Field, Value: nil}}
+	default:
+		return bson.D{primitive.E{Key: condition.Field, Value: condition.Values[0]}}
+	}
+}
+
+func querySortFields(q *utils.Query) []utils.QuerySort {
+	fields := make([]utils.QuerySort, 0, len(q.Sort)+1)
+	for _, item := range q.Sort {
+		if item.Field == "_id" {
+			return append(fields, item)
+		}
+		fields = append(fields, item)
+	}
+	return append(fields, utils.QuerySort{Field: "_id"})
+}
+
+//constructQueryCursorCondition gives the condition for the items after the cursor in the query sort order
+func constructQueryCursorCondition(q *utils.Query) (bson.D, error) {
+	values, err := decodeQueryCursor(*q.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	sortFields := querySortFields(q)
+	if len(values) != len(sortFields) {
+		return nil, errors.New("the cursor is not for this sort")
+	}
+
+	//(s1 > v1) or (s1 = v1 and s2 > v2) or ...
+	var or []interface{}
+	for i, sortField := range sortFields {
+		operator := "$gt"
+		if sortField.Desc {
+			operator = "$lt"
+		}
+		item := bson.D{}
+		for j := 0; j < i; j++ {
+			item = append(item, primitive.E{Key: sortFields[j].Field, Value: values[j]})
+		}
+		item = append(item, primitive.E{Key: sortField.Field, Value: bson.M{operator: values[i]}})
+		or = append(or, item)
+	}
+	return bson.D{primitive.E{Key: "$or", Value: or}}, nil
+}
+
+//encodeQueryCursor encodes the sort values of the last page item. The extended json keeps the values types.
+func encodeQueryCursor(q *utils.Query, last bson.Raw) (string, error) {
+	values := bson.A{}
+	for _, sortField := range querySortFields(q) {
+		value := last.Lookup(strings.Split(sortField.Field, ".")...)
+		if value.Type == 0 {
+			values = append(values, nil)
+			continue
+		}
+		values = append(values, value)
+	}
+
+	data, err := bson.MarshalExtJSON(bson.D{primitive.E{Key: "v", Value: values}}, true, false)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodeQueryCursor(cursor string) (bson.A, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, errors.New("the cursor is not valid")
+	}
+	var decoded struct {
+		Values bson.A `bson:"v"`
+	}
+	err = bson.UnmarshalExtJSON(data, true, &decoded)
+	if err != nil {
+		return nil, errors.New("the cursor is not valid")
+	}
+	return decoded.Values, nil
+}
+
+//findQueryPage finds the query page items from the collection in the result which must be a pointer to a slice. It gives the next page cursor.
+func findQueryPage(coll *collectionWrapper, q *utils.Query, result interface{}) (string, error) {
+	filter, err := QueryFilter(q)
+	if err != nil {
+		return "", err
+	}
+
+	var items []bson.Raw
+	err = coll.Find(filter, &items, QueryFindOptions(q))
+	if err != nil {
+		return "", err
+	}
+	return DecodeQueryPage(q, items, result)
+}
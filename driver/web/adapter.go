@@ -28,7 +28,6 @@ import (
 	"log"
 	"net/http"
 
-	"github.com/casbin/casbin"
 	"github.com/gorilla/mux"
 	"gopkg.in/go-playground/validator.v9"
 
@@ -37,9 +36,8 @@ import (
 
 //Adapter entity
 type Adapter struct {
-	host          string
-	auth          *Auth
-	authorization *casbin.Enforcer
+	host string
+	auth *Auth
 
 	apisHandler      rest.ApisHandler
 	adminApisHandler rest.AdminApisHandler
@@ -107,6 +105,9 @@ func (we Adapter) Start() {
 	covid19RestSubrouter.HandleFunc("/login", we.loginUser).Methods("POST")
 	covid19RestSubrouter.HandleFunc("/user", we.getUser).Methods("GET")
 	covid19RestSubrouter.HandleFunc("/user/clear", we.userAuthWrapFunc(we.apisHandler.ClearUserData)).Methods("GET")
+	covid19RestSubrouter.HandleFunc("/user/sub-accounts", we.userAuthWrapFunc(we.apisHandler.GetPendingSubAccounts)).Methods("GET")
+	covid19RestSubrouter.HandleFunc("/user/sub-accounts/{uin}/accept", we.userAuthWrapFunc(we.apisHandler.AcceptSubAccount)).Methods("PUT")
+	covid19RestSubrouter.HandleFunc("/user/sub-accounts/{uin}/decline", we.userAuthWrapFunc(we.apisHandler.DeclineSubAccount)).Methods("PUT")
 
 	covid19RestSubrouter.HandleFunc("/ctests", we.userAccountsAuthWrapFunc(we.apisHandler.GetCTests)).Methods("GET")
 	covid19RestSubrouter.HandleFunc("/ctests/{id}", we.userAccountsAuthWrapFunc(we.apisHandler.UpdateCTest)).Methods("PUT")
@@ -134,16 +135,16 @@ func (we Adapter) Start() {
 	covid19RestSubrouter.HandleFunc("/join-external-approvements/{id}", we.userAccountsAuthWrapFunc(we.apisHandler.UpdateExtJoinExternalApproval)).Methods("PUT")
 
 	//provider auth
-	covid19RestSubrouter.HandleFunc("/users/uin/{uin}", we.providerAuthWrapFunc(we.apisHandler.GetUserByShibbolethUIN)).Methods("GET")
-	covid19RestSubrouter.HandleFunc("/users/re-post", we.providerAuthWrapFunc(we.apisHandler.GetUsersForRePost)).Methods("GET")
-	covid19RestSubrouter.HandleFunc("/ctests", we.providerAuthWrapFunc(we.apisHandler.CreateExternalCTest)).Methods("POST")
-	covid19RestSubrouter.HandleFunc("/track/uins", we.providerAuthWrapFunc(we.apisHandler.GetUINsByOrderNumbers)).Methods("GET").Queries("order-numbers", "")
-	covid19RestSubrouter.HandleFunc("/track/items", we.providerAuthWrapFunc(we.apisHandler.GetItemsListsByUINs)).Methods("GET").Queries("uins", "")
-	covid19RestSubrouter.HandleFunc("/ext/uin-overrides", we.providerAuthWrapFunc(we.apisHandler.GetExtUINOverrides)).Methods("GET")
-	covid19RestSubrouter.HandleFunc("/ext/uin-overrides", we.providerAuthWrapFunc(we.apisHandler.CreateExtUINOverrides)).Methods("POST")
-	covid19RestSubrouter.HandleFunc("/ext/uin-overrides/uin/{uin}", we.providerAuthWrapFunc(we.apisHandler.UpdateExtUINOverride)).Methods("PUT")
-	covid19RestSubrouter.HandleFunc("/ext/uin-overrides/uin/{uin}", we.providerAuthWrapFunc(we.apisHandler.DeleteExtUINOverride)).Methods("DELETE")
-	covid19RestSubrouter.HandleFunc("/ext/building-access", we.providerAuthWrapFunc(we.apisHandler.GetExtBuildingAccess)).Methods("GET").Queries("uin", "")
+	covid19RestSubrouter.HandleFunc("/users/uin/{uin}", we.providerAuthWrapFunc(model.ProviderScopeUsersRead, we.apisHandler.GetUserByShibbolethUIN)).Methods("GET")
+	covid19RestSubrouter.HandleFunc("/users/re-post", we.providerAuthWrapFunc(model.ProviderScopeUsersRead, we.apisHandler.GetUsersForRePost)).Methods("GET")
+	covid19RestSubrouter.HandleFunc("/ctests", we.providerCredentialAuthWrapFunc(we.apisHandler.CreateExternalCTest)).Methods("POST")
+	covid19RestSubrouter.HandleFunc("/track/uins", we.providerAuthWrapFunc(model.ProviderScopeTrackRead, we.apisHandler.GetUINsByOrderNumbers)).Methods("GET").Queries("order-numbers", "")
+	covid19RestSubrouter.HandleFunc("/track/items", we.providerAuthWrapFunc(model.ProviderScopeTrackRead, we.apisHandler.GetItemsListsByUINs)).Methods("GET").Queries("uins", "")
+	covid19RestSubrouter.HandleFunc("/ext/uin-overrides", we.providerAuthWrapFunc(model.ProviderScopeUINOverridesRead, we.apisHandler.GetExtUINOverrides)).Methods("GET")
+	covid19RestSubrouter.HandleFunc("/ext/uin-overrides", we.providerAuthWrapFunc(model.ProviderScopeUINOverridesWrite, we.apisHandler.CreateExtUINOverrides)).Methods("POST")
+	covid19RestSubrouter.HandleFunc("/ext/uin-overrides/uin/{uin}", we.providerAuthWrapFunc(model.ProviderScopeUINOverridesWrite, we.apisHandler.UpdateExtUINOverride)).Methods("PUT")
+	covid19RestSubrouter.HandleFunc("/ext/uin-overrides/uin/{uin}", we.providerAuthWrapFunc(model.ProviderScopeUINOverridesWrite, we.apisHandler.DeleteExtUINOverride)).Methods("DELETE")
+	covid19RestSubrouter.HandleFunc("/ext/building-access", we.providerAuthWrapFunc(model.ProviderScopeBuildingAccessRead, we.apisHandler.GetExtBuildingAccess)).Methods("GET").Queries("uin", "")
 
 	//external auth
 	covid19RestSubrouter.HandleFunc("/external/user", we.externalAuthWrapFunc(we.apisHandler.GetUserByIdentifier)).Methods("GET").Queries("identifier", "")
@@ -181,6 +182,10 @@ func (we Adapter) Start() {
 
 	covid19RestSubrouter.HandleFunc("/trace/report", we.apiKeyOrTokenWrapFunc(we.apisHandler.AddTraceReport)).Methods("POST")
 	covid19RestSubrouter.HandleFunc("/trace/exposures", we.apiKeyOrTokenWrapFunc(we.apisHandler.GetExposures)).Methods("GET")
+	covid19RestSubrouter.HandleFunc("/trace/verify", we.userAuthWrapFunc(we.apisHandler.VerifyExposureCode)).Methods("POST")
+	covid19RestSubrouter.HandleFunc("/trace/publish", we.apiKeyOrTokenWrapFunc(we.apisHandler.PublishExposureKeys)).Methods("POST")
+	covid19RestSubrouter.HandleFunc("/trace/export/index.txt", we.apiKeyOrTokenWrapFunc(we.apisHandler.GetExposureExportIndex)).Methods("GET")
+	covid19RestSubrouter.HandleFunc("/trace/export/{name}", we.apiKeyOrTokenWrapFunc(we.apisHandler.GetExposureExport)).Methods("GET")
 
 	covid19RestSubrouter.HandleFunc("/rosters/phone/{phone}", we.apiKeyOrTokenWrapFunc(we.apisHandler.GetRosterByPhone)).Methods("GET")
 
@@ -190,126 +195,167 @@ func (we Adapter) Start() {
 	adminRestSubrouter := router.PathPrefix("/health/admin").Subrouter()
 
 	//admin app id token auth
-	adminRestSubrouter.HandleFunc("/covid19-config", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.GetCovid19Config)).Methods("GET")
-	adminRestSubrouter.HandleFunc("/covid19-config", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.UpdateCovid19Config)).Methods("PUT")
-	adminRestSubrouter.HandleFunc("/covid19-configs", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.GetCovid19Configs)).Methods("GET")
+	adminRestSubrouter.HandleFunc("/covid19-config", we.adminAppIDTokenAuthWrapFunc(model.PermissionConfigRead, we.adminApisHandler.GetCovid19Config)).Methods("GET")
+	adminRestSubrouter.HandleFunc("/covid19-config", we.adminAppIDTokenAuthWrapFunc(model.PermissionConfigWrite, we.adminApisHandler.UpdateCovid19Config)).Methods("PUT")
+	adminRestSubrouter.HandleFunc("/covid19-configs", we.adminAppIDTokenAuthWrapFunc(model.PermissionConfigRead, we.adminApisHandler.GetCovid19Configs)).Methods("GET")
 
-	adminRestSubrouter.HandleFunc("/app-versions", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.GetAppVersions)).Methods("GET")
-	adminRestSubrouter.HandleFunc("/app-versions", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.CreateAppVersion)).Methods("POST")
+	adminRestSubrouter.HandleFunc("/app-versions", we.adminAppIDTokenAuthWrapFunc(model.PermissionAppVersionsRead, we.adminApisHandler.GetAppVersions)).Methods("GET")
+	adminRestSubrouter.HandleFunc("/app-versions", we.adminAppIDTokenAuthWrapFunc(model.PermissionAppVersionsWrite, we.adminApisHandler.CreateAppVersion)).Methods("POST")
 
-	adminRestSubrouter.HandleFunc("/news", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.GetNews)).Methods("GET")
-	adminRestSubrouter.HandleFunc("/news", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.CreateNews)).Methods("POST")
-	adminRestSubrouter.HandleFunc("/news/{id}", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.UpdateNews)).Methods("PUT")
-	adminRestSubrouter.HandleFunc("/news/{id}", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.DeleteNews)).Methods("DELETE")
+	adminRestSubrouter.HandleFunc("/news", we.adminAppIDTokenAuthWrapFunc(model.PermissionNewsRead, we.adminApisHandler.GetNews)).Methods("GET")
+	adminRestSubrouter.HandleFunc("/news", we.adminAppIDTokenAuthWrapFunc(model.PermissionNewsWrite, we.adminApisHandler.CreateNews)).Methods("POST")
+	adminRestSubrouter.HandleFunc("/news/{id}", we.adminAppIDTokenAuthWrapFunc(model.PermissionNewsWrite, we.adminApisHandler.UpdateNews)).Methods("PUT")
+	adminRestSubrouter.HandleFunc("/news/{id}", we.adminAppIDTokenAuthWrapFunc(model.PermissionNewsWrite, we.adminApisHandler.DeleteNews)).Methods("DELETE")
 
-	adminRestSubrouter.HandleFunc("/resources", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.GetResources)).Methods("GET")
-	adminRestSubrouter.HandleFunc("/resources", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.CreateResources)).Methods("POST")
-	adminRestSubrouter.HandleFunc("/resources/{id}", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.UpdateResource)).Methods("PUT")
-	adminRestSubrouter.HandleFunc("/resources/{id}", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.DeleteResource)).Methods("DELETE")
-	adminRestSubrouter.HandleFunc("/resources/display-order", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.UpdateDisplaOrderResources)).Methods("POST")
+	adminRestSubrouter.HandleFunc("/resources", we.adminAppIDTokenAuthWrapFunc(model.PermissionResourcesRead, we.adminApisHandler.GetResources)).Methods("GET")
+	adminRestSubrouter.HandleFunc("/resources", we.adminAppIDTokenAuthWrapFunc(model.PermissionResourcesWrite, we.adminApisHandler.CreateResources)).Methods("POST")
+	adminRestSubrouter.HandleFunc("/resources/{id}", we.adminAppIDTokenAuthWrapFunc(model.PermissionResourcesWrite, we.adminApisHandler.UpdateResource)).Methods("PUT")
+	adminRestSubrouter.HandleFunc("/resources/{id}", we.adminAppIDTokenAuthWrapFunc(model.PermissionResourcesWrite, we.adminApisHandler.DeleteResource)).Methods("DELETE")
+	adminRestSubrouter.HandleFunc("/resources/display-order", we.adminAppIDTokenAuthWrapFunc(model.PermissionResourcesWrite, we.adminApisHandler.UpdateDisplaOrderResources)).Methods("POST")
 
 	//TODO refactor
-	adminRestSubrouter.HandleFunc("/faq", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.GetFAQs)).Methods("GET")
-	adminRestSubrouter.HandleFunc("/faq", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.CreateFAQItem)).Methods("POST")
-	adminRestSubrouter.HandleFunc("/faq/{id}", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.UpdateFAQItem)).Methods("PUT")
-	adminRestSubrouter.HandleFunc("/faq/{id}", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.DeleteFAQItem)).Methods("DELETE")
-	adminRestSubrouter.HandleFunc("/faq/section/{id}", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.UpdateFAQSection)).Methods("PUT")
-	adminRestSubrouter.HandleFunc("/faq/section/{id}", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.DeleteFAQSection)).Methods("DELETE")
-
-	adminRestSubrouter.HandleFunc("/providers", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.GetProviders)).Methods("GET")
-	adminRestSubrouter.HandleFunc("/providers", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.CreateProvider)).Methods("POST")
-	adminRestSubrouter.HandleFunc("/providers/{id}", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.UpdateProvider)).Methods("PUT")
-	adminRestSubrouter.HandleFunc("/providers/{id}", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.DeleteProvider)).Methods("DELETE")
-
-	adminRestSubrouter.HandleFunc("/test-types", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.GetTestTypes)).Methods("GET")
-	adminRestSubrouter.HandleFunc("/test-types", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.CreateTestType)).Methods("POST")
-	adminRestSubrouter.HandleFunc("/test-types/{id}", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.UpdateTestType)).Methods("PUT")
-	adminRestSubrouter.HandleFunc("/test-types/{id}", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.DeleteTestType)).Methods("DELETE")
-
-	adminRestSubrouter.HandleFunc("/test-type-results", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.CreateTestTypeResult)).Methods("POST")
-	adminRestSubrouter.HandleFunc("/test-type-results/{id}", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.UpdateTestTypeResult)).Methods("PUT")
-	adminRestSubrouter.HandleFunc("/test-type-results/{id}", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.DeleteTestTypeResult)).Methods("DELETE")
-	adminRestSubrouter.HandleFunc("/test-type-results", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.GetTestTypeResultsByTestTypeID)).Methods("GET").Queries("test-type-id", "")
-
-	adminRestSubrouter.HandleFunc("/counties", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.GetCounties)).Methods("GET")
-	adminRestSubrouter.HandleFunc("/counties", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.CreateCounty)).Methods("POST")
-	adminRestSubrouter.HandleFunc("/counties/{id}", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.UpdateCounty)).Methods("PUT")
-	adminRestSubrouter.HandleFunc("/counties/{id}", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.DeleteCounty)).Methods("DELETE")
-
-	adminRestSubrouter.HandleFunc("/guidelines", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.CreateGuideline)).Methods("POST")
-	adminRestSubrouter.HandleFunc("/guidelines/{id}", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.UpdateGuideline)).Methods("PUT")
-	adminRestSubrouter.HandleFunc("/guidelines/{id}", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.DeleteGuideline)).Methods("DELETE")
-	adminRestSubrouter.HandleFunc("/guidelines", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.GetGuidelinesByCountyID)).Methods("GET").Queries("county-id", "")
-
-	adminRestSubrouter.HandleFunc("/county-statuses", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.CreateCountyStatus)).Methods("POST")
-	adminRestSubrouter.HandleFunc("/county-statuses/{id}", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.UpdateCountyStatus)).Methods("PUT")
-	adminRestSubrouter.HandleFunc("/county-statuses/{id}", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.DeleteCountyStatus)).Methods("DELETE")
-	adminRestSubrouter.HandleFunc("/county-statuses", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.GetCountyStatusesByCountyID)).Methods("GET").Queries("county-id", "")
-
-	adminRestSubrouter.HandleFunc("/rules", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.GetRules)).Methods("GET")
-	adminRestSubrouter.HandleFunc("/rules", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.CreateRule)).Methods("POST")
-	adminRestSubrouter.HandleFunc("/rules/{id}", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.UpdateRule)).Methods("PUT")
-	adminRestSubrouter.HandleFunc("/rules/{id}", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.DeleteRule)).Methods("DELETE")
-
-	adminRestSubrouter.HandleFunc("/locations", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.GetLocations)).Methods("GET")
-	adminRestSubrouter.HandleFunc("/locations", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.CreateLocation)).Methods("POST")
-	adminRestSubrouter.HandleFunc("/locations/{id}", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.UpdateLocation)).Methods("PUT")
-	adminRestSubrouter.HandleFunc("/locations/{id}", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.DeleteLocation)).Methods("DELETE")
+	adminRestSubrouter.HandleFunc("/faq", we.adminAppIDTokenAuthWrapFunc(model.PermissionFAQRead, we.adminApisHandler.GetFAQs)).Methods("GET")
+	adminRestSubrouter.HandleFunc("/faq", we.adminAppIDTokenAuthWrapFunc(model.PermissionFAQWrite, we.adminApisHandler.CreateFAQItem)).Methods("POST")
+	adminRestSubrouter.HandleFunc("/faq/{id}", we.adminAppIDTokenAuthWrapFunc(model.PermissionFAQWrite, we.adminApisHandler.UpdateFAQItem)).Methods("PUT")
+	adminRestSubrouter.HandleFunc("/faq/{id}", we.adminAppIDTokenAuthWrapFunc(model.PermissionFAQWrite, we.adminApisHandler.DeleteFAQItem)).Methods("DELETE")
+	adminRestSubrouter.HandleFunc("/faq/section/{id}", we.adminAppIDTokenAuthWrapFunc(model.PermissionFAQWrite, we.adminApisHandler.UpdateFAQSection)).Methods("PUT")
+	adminRestSubrouter.HandleFunc("/faq/section/{id}", we.adminAppIDTokenAuthWrapFunc(model.PermissionFAQWrite, we.adminApisHandler.DeleteFAQSection)).Methods("DELETE")
+
+	adminRestSubrouter.HandleFunc("/providers", we.adminAppIDTokenAuthWrapFunc(model.PermissionProvidersRead, we.adminApisHandler.GetProviders)).Methods("GET")
+	adminRestSubrouter.HandleFunc("/providers", we.adminAppIDTokenAuthWrapFunc(model.PermissionProvidersWrite, we.adminApisHandler.CreateProvider)).Methods("POST")
+	adminRestSubrouter.HandleFunc("/providers/{id}", we.adminAppIDTokenAuthWrapFunc(model.PermissionProvidersWrite, we.adminApisHandler.UpdateProvider)).Methods("PUT")
+	adminRestSubrouter.HandleFunc("/providers/{id}", we.adminAppIDTokenAuthWrapFunc(model.PermissionProvidersWrite, we.adminApisHandler.DeleteProvider)).Methods("DELETE")
+	adminRestSubrouter.HandleFunc("/providers/{id}/credentials", we.adminAppIDTokenAuthWrapFunc(model.PermissionProvidersRead, we.adminApisHandler.GetProviderCredentials)).Methods("GET")
+	adminRestSubrouter.HandleFunc("/providers/{id}/credentials", we.adminAppIDTokenAuthWrapFunc(model.PermissionProvidersWrite, we.adminApisHandler.CreateProviderCredential)).Methods("POST")
+	adminRestSubrouter.HandleFunc("/provider-credentials/{id}/rotate", we.adminAppIDTokenAuthWrapFunc(model.PermissionProvidersWrite, we.adminApisHandler.RotateProviderCredential)).Methods("PUT")
+	adminRestSubrouter.HandleFunc("/provider-credentials/{id}/revoke", we.adminAppIDTokenAuthWrapFunc(model.PermissionProvidersWrite, we.adminApisHandler.RevokeProviderCredential)).Methods("PUT")
+
+	adminRestSubrouter.HandleFunc("/api-keys", we.adminAppIDTokenAuthWrapFunc(model.PermissionAPIKeysRead, we.adminApisHandler.GetAPIKeys)).Methods("GET")
+	adminRestSubrouter.HandleFunc("/api-keys", we.adminAppIDTokenAuthWrapFunc(model.PermissionAPIKeysWrite, we.adminApisHandler.CreateAPIKey)).Methods("POST")
+	adminRestSubrouter.HandleFunc("/api-keys/{id}/rotate", we.adminAppIDTokenAuthWrapFunc(model.PermissionAPIKeysWrite, we.adminApisHandler.RotateAPIKey)).Methods("PUT")
+	adminRestSubrouter.HandleFunc("/api-keys/{id}/revoke", we.adminAppIDTokenAuthWrapFunc(model.PermissionAPIKeysWrite, we.adminApisHandler.RevokeAPIKey)).Methods("PUT")
+
+	adminRestSubrouter.HandleFunc("/users-cache-metrics", we.adminAppIDTokenAuthWrapFunc(model.PermissionUsersRead, we.adminApisHandler.GetUsersCacheMetrics)).Methods("GET")
+	adminRestSubrouter.HandleFunc("/token-metrics", we.adminAppIDTokenAuthWrapFunc(model.PermissionTokenMetricsRead, we.getTokenMetrics)).Methods("GET")
+
+	adminRestSubrouter.HandleFunc("/test-types", we.adminAppIDTokenAuthWrapFunc(model.PermissionTestTypesRead, we.adminApisHandler.GetTestTypes)).Methods("GET")
+	adminRestSubrouter.HandleFunc("/test-types", we.adminAppIDTokenAuthWrapFunc(model.PermissionTestTypesWrite, we.adminApisHandler.CreateTestType)).Methods("POST")
+	adminRestSubrouter.HandleFunc("/test-types/{id}", we.adminAppIDTokenAuthWrapFunc(model.PermissionTestTypesWrite, we.adminApisHandler.UpdateTestType)).Methods("PUT")
+	adminRestSubrouter.HandleFunc("/test-types/{id}", we.adminAppIDTokenAuthWrapFunc(model.PermissionTestTypesWrite, we.adminApisHandler.DeleteTestType)).Methods("DELETE")
+
+	adminRestSubrouter.HandleFunc("/test-type-results", we.adminAppIDTokenAuthWrapFunc(model.PermissionTestTypesWrite, we.adminApisHandler.CreateTestTypeResult)).Methods("POST")
+	adminRestSubrouter.HandleFunc("/test-type-results/{id}", we.adminAppIDTokenAuthWrapFunc(model.PermissionTestTypesWrite, we.adminApisHandler.UpdateTestTypeResult)).Methods("PUT")
+	adminRestSubrouter.HandleFunc("/test-type-results/{id}", we.adminAppIDTokenAuthWrapFunc(model.PermissionTestTypesWrite, we.adminApisHandler.DeleteTestTypeResult)).Methods("DELETE")
+	adminRestSubrouter.HandleFunc("/test-type-results", we.adminAppIDTokenAuthWrapFunc(model.PermissionTestTypesRead, we.adminApisHandler.GetTestTypeResultsByTestTypeID)).Methods("GET").Queries("test-type-id", "")
+
+	adminRestSubrouter.HandleFunc("/counties", we.adminAppIDTokenAuthWrapFunc(model.PermissionCountiesRead, we.adminApisHandler.GetCounties)).Methods("GET")
+	adminRestSubrouter.HandleFunc("/counties", we.adminAppIDTokenAuthWrapFunc(model.PermissionCountiesWrite, we.adminApisHandler.CreateCounty)).Methods("POST")
+	adminRestSubrouter.HandleFunc("/counties/{id}", we.adminAppIDTokenAuthWrapFunc(model.PermissionCountiesWrite, we.adminApisHandler.UpdateCounty)).Methods("PUT")
+	adminRestSubrouter.HandleFunc("/counties/{id}", we.adminAppIDTokenAuthWrapFunc(model.PermissionCountiesWrite, we.adminApisHandler.DeleteCounty)).Methods("DELETE")
+
+	adminRestSubrouter.HandleFunc("/guidelines", we.adminAppIDTokenAuthWrapFunc(model.PermissionGuidelinesWrite, we.adminApisHandler.CreateGuideline)).Methods("POST")
+	adminRestSubrouter.HandleFunc("/guidelines/{id}", we.adminAppIDTokenAuthWrapFunc(model.PermissionGuidelinesWrite, we.adminApisHandler.UpdateGuideline)).Methods("PUT")
+	adminRestSubrouter.HandleFunc("/guidelines/{id}", we.adminAppIDTokenAuthWrapFunc(model.PermissionGuidelinesWrite, we.adminApisHandler.DeleteGuideline)).Methods("DELETE")
+	adminRestSubrouter.HandleFunc("/guidelines", we.adminAppIDTokenAuthWrapFunc(model.PermissionGuidelinesRead, we.adminApisHandler.GetGuidelinesByCountyID)).Methods("GET").Queries("county-id", "")
+
+	adminRestSubrouter.HandleFunc("/county-statuses", we.adminAppIDTokenAuthWrapFunc(model.PermissionCountyStatusesWrite, we.adminApisHandler.CreateCountyStatus)).Methods("POST")
+	adminRestSubrouter.HandleFunc("/county-statuses/{id}", we.adminAppIDTokenAuthWrapFunc(model.PermissionCountyStatusesWrite, we.adminApisHandler.UpdateCountyStatus)).Methods("PUT")
+	adminRestSubrouter.HandleFunc("/county-statuses/{id}", we.adminAppIDTokenAuthWrapFunc(model.PermissionCountyStatusesWrite, we.adminApisHandler.DeleteCountyStatus)).Methods("DELETE")
+	adminRestSubrouter.HandleFunc("/county-statuses", we.adminAppIDTokenAuthWrapFunc(model.PermissionCountyStatusesRead, we.adminApisHandler.GetCountyStatusesByCountyID)).Methods("GET").Queries("county-id", "")
+
+	adminRestSubrouter.HandleFunc("/rules", we.adminAppIDTokenAuthWrapFunc(model.PermissionRulesRead, we.adminApisHandler.GetRules)).Methods("GET")
+	adminRestSubrouter.HandleFunc("/rules", we.adminAppIDTokenAuthWrapFunc(model.PermissionRulesWrite, we.adminApisHandler.CreateRule)).Methods("POST")
+	adminRestSubrouter.HandleFunc("/rules/{id}", we.adminAppIDTokenAuthWrapFunc(model.PermissionRulesWrite, we.adminApisHandler.UpdateRule)).Methods("PUT")
+	adminRestSubrouter.HandleFunc("/rules/{id}", we.adminAppIDTokenAuthWrapFunc(model.PermissionRulesWrite, we.adminApisHandler.DeleteRule)).Methods("DELETE")
+
+	adminRestSubrouter.HandleFunc("/locations", we.adminAppIDTokenAuthWrapFunc(model.PermissionLocationsRead, we.adminApisHandler.GetLocations)).Methods("GET")
+	adminRestSubrouter.HandleFunc("/locations", we.adminAppIDTokenAuthWrapFunc(model.PermissionLocationsWrite, we.adminApisHandler.CreateLocation)).Methods("POST")
+	adminRestSubrouter.HandleFunc("/locations/{id}", we.adminAppIDTokenAuthWrapFunc(model.PermissionLocationsWrite, we.adminApisHandler.UpdateLocation)).Methods("PUT")
+	adminRestSubrouter.HandleFunc("/locations/{id}", we.adminAppIDTokenAuthWrapFunc(model.PermissionLocationsWrite, we.adminApisHandler.DeleteLocation)).Methods("DELETE")
 
 	//deprecated
-	adminRestSubrouter.HandleFunc("/symptoms", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.CreateSymptom)).Methods("POST")
-	adminRestSubrouter.HandleFunc("/symptoms/{id}", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.UpdateSymptom)).Methods("PUT")
-	adminRestSubrouter.HandleFunc("/symptoms/{id}", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.DeleteSymptom)).Methods("DELETE")
+	adminRestSubrouter.HandleFunc("/symptoms", we.adminAppIDTokenAuthWrapFunc(model.PermissionSymptomsWrite, we.adminApisHandler.CreateSymptom)).Methods("POST")
+	adminRestSubrouter.HandleFunc("/symptoms/{id}", we.adminAppIDTokenAuthWrapFunc(model.PermissionSymptomsWrite, we.adminApisHandler.UpdateSymptom)).Methods("PUT")
+	adminRestSubrouter.HandleFunc("/symptoms/{id}", we.adminAppIDTokenAuthWrapFunc(model.PermissionSymptomsWrite, we.adminApisHandler.DeleteSymptom)).Methods("DELETE")
 
 	//deprecated
-	adminRestSubrouter.HandleFunc("/symptom-groups", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.GetSymptomGroups)).Methods("GET")
+	adminRestSubrouter.HandleFunc("/symptom-groups", we.adminAppIDTokenAuthWrapFunc(model.PermissionSymptomsRead, we.adminApisHandler.GetSymptomGroups)).Methods("GET")
 
 	//deprecated
-	adminRestSubrouter.HandleFunc("/symptom-rules", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.GetSymptomRules)).Methods("GET")
-	adminRestSubrouter.HandleFunc("/symptom-rules", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.CreateSymptomRule)).Methods("POST")
-	adminRestSubrouter.HandleFunc("/symptom-rules/{id}", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.UpdateSymptomRule)).Methods("PUT")
-	adminRestSubrouter.HandleFunc("/symptom-rules/{id}", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.DeleteSymptomRule)).Methods("DELETE")
+	adminRestSubrouter.HandleFunc("/symptom-rules", we.adminAppIDTokenAuthWrapFunc(model.PermissionSymptomsRead, we.adminApisHandler.GetSymptomRules)).Methods("GET")
+	adminRestSubrouter.HandleFunc("/symptom-rules", we.adminAppIDTokenAuthWrapFunc(model.PermissionSymptomsWrite, we.adminApisHandler.CreateSymptomRule)).Methods("POST")
+	adminRestSubrouter.HandleFunc("/symptom-rules/{id}", we.adminAppIDTokenAuthWrapFunc(model.PermissionSymptomsWrite, we.adminApisHandler.UpdateSymptomRule)).Methods("PUT")
+	adminRestSubrouter.HandleFunc("/symptom-rules/{id}", we.adminAppIDTokenAuthWrapFunc(model.PermissionSymptomsWrite, we.adminApisHandler.DeleteSymptomRule)).Methods("DELETE")
 	/////
 
-	adminRestSubrouter.HandleFunc("/manual-tests", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.GetManualTestsByCountyID)).Methods("GET").Queries("county-id", "")
-	adminRestSubrouter.HandleFunc("/manual-tests/{id}/process", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.ProcessManualTest)).Methods("PUT")
-	adminRestSubrouter.HandleFunc("/manual-tests/{id}/image", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.GetManualTestImage)).Methods("GET")
-
-	adminRestSubrouter.HandleFunc("/access-rules", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.GetAccessRules)).Methods("GET")
-	adminRestSubrouter.HandleFunc("/access-rules", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.CreateAccessRule)).Methods("POST")
-	adminRestSubrouter.HandleFunc("/access-rules/{id}", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.UpdateAccessRule)).Methods("PUT")
-	adminRestSubrouter.HandleFunc("/access-rules/{id}", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.DeleteAccessRule)).Methods("DELETE")
-
-	adminRestSubrouter.HandleFunc("/crules", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.GetCRules)).Methods("GET").Queries("county-id", "", "app-version", "")
-	adminRestSubrouter.HandleFunc("/crules", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.CreateOrUpdateCRules)).Methods("PUT")
-
-	adminRestSubrouter.HandleFunc("/symptoms", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.GetSymptoms)).Methods("GET").Queries("app-version", "")
-	adminRestSubrouter.HandleFunc("/symptoms", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.CreateOrUpdateSymptoms)).Methods("PUT")
-
-	adminRestSubrouter.HandleFunc("/uin-overrides", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.GetUINOverrides)).Methods("GET")
-	adminRestSubrouter.HandleFunc("/uin-overrides", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.CreateUINOverride)).Methods("POST")
-	adminRestSubrouter.HandleFunc("/uin-overrides/uin/{uin}", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.UpdateUINOverride)).Methods("PUT")
-	adminRestSubrouter.HandleFunc("/uin-overrides/uin/{uin}", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.DeleteUINOverride)).Methods("DELETE")
-
-	adminRestSubrouter.HandleFunc("/rosters", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.CreateRoster)).Methods("POST")
-	adminRestSubrouter.HandleFunc("/roster-items", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.CreateRosterItems)).Methods("POST")
-	adminRestSubrouter.HandleFunc("/rosters", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.GetRosters)).Methods("GET")
-	adminRestSubrouter.HandleFunc("/rosters/phone/{phone}", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.DeleteRosterByPhone)).Methods("DELETE")
-	adminRestSubrouter.HandleFunc("/rosters/uin/{uin}", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.DeleteRosterByUIN)).Methods("DELETE")
-	adminRestSubrouter.HandleFunc("/rosters", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.DeleteAllRosters)).Methods("DELETE")
-	adminRestSubrouter.HandleFunc("/rosters/uin/{uin}", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.UpdateRoster)).Methods("PUT")
-
-	adminRestSubrouter.HandleFunc("/raw-sub-account-items", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.CreateSubAccountItems)).Methods("POST")
-	adminRestSubrouter.HandleFunc("/raw-sub-accounts", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.GetSubAccounts)).Methods("GET")
-	adminRestSubrouter.HandleFunc("/raw-sub-accounts/uin/{uin}", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.UpdateSubAccount)).Methods("PUT")
-	adminRestSubrouter.HandleFunc("/raw-sub-accounts/uin/{uin}", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.DeleteSubAccountByUIN)).Methods("DELETE")
-	adminRestSubrouter.HandleFunc("/raw-sub-accounts", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.DeleteAllSubAccounts)).Methods("DELETE")
-
-	adminRestSubrouter.HandleFunc("/user", we.adminAppIDTokenAuthWrapFunc(we.adminApisHandler.GetUserByExternalID)).Methods("GET").Queries("external-id", "")
-
-	adminRestSubrouter.HandleFunc("/actions", we.adminAppIDTokenAuthWrapFunc(we.apisHandler.CreateAction)).Methods("POST")
-
-	adminRestSubrouter.HandleFunc("/audit", we.adminAppIDTokenAuthWrapFunc(we.apisHandler.GetAudit)).Methods("GET")
+	adminRestSubrouter.HandleFunc("/manual-tests", we.adminAppIDTokenAuthWrapFunc(model.PermissionManualTestsRead, we.adminApisHandler.GetManualTestsByCountyID)).Methods("GET").Queries("county-id", "")
+	adminRestSubrouter.HandleFunc("/manual-tests/{id}/process", we.adminAppIDTokenAuthWrapFunc(model.PermissionManualTestsVerify, we.adminApisHandler.ProcessManualTest)).Methods("PUT")
+	adminRestSubrouter.HandleFunc("/manual-tests/{id}/image", we.adminAppIDTokenAuthWrapFunc(model.PermissionManualTestsRead, we.adminApisHandler.GetManualTestImage)).Methods("GET")
+
+	adminRestSubrouter.HandleFunc("/access-rules", we.adminAppIDTokenAuthWrapFunc(model.PermissionAccessRulesRead, we.adminApisHandler.GetAccessRules)).Methods("GET")
+	adminRestSubrouter.HandleFunc("/access-rules", we.adminAppIDTokenAuthWrapFunc(model.PermissionAccessRulesWrite, we.adminApisHandler.CreateAccessRule)).Methods("POST")
+	adminRestSubrouter.HandleFunc("/access-rules/{id}", we.adminAppIDTokenAuthWrapFunc(model.PermissionAccessRulesWrite, we.adminApisHandler.UpdateAccessRule)).Methods("PUT")
+	adminRestSubrouter.HandleFunc("/access-rules/{id}", we.adminAppIDTokenAuthWrapFunc(model.PermissionAccessRulesWrite, we.adminApisHandler.DeleteAccessRule)).Methods("DELETE")
+
+	adminRestSubrouter.HandleFunc("/crules", we.adminAppIDTokenAuthWrapFunc(model.PermissionCRulesRead, we.adminApisHandler.GetCRules)).Methods("GET").Queries("county-id", "", "app-version", "")
+	adminRestSubrouter.HandleFunc("/crules", we.adminAppIDTokenAuthWrapFunc(model.PermissionCRulesWrite, we.adminApisHandler.CreateOrUpdateCRules)).Methods("PUT")
+
+	adminRestSubrouter.HandleFunc("/symptoms", we.adminAppIDTokenAuthWrapFunc(model.PermissionSymptomsRead, we.adminApisHandler.GetSymptoms)).Methods("GET").Queries("app-version", "")
+	adminRestSubrouter.HandleFunc("/symptoms", we.adminAppIDTokenAuthWrapFunc(model.PermissionSymptomsWrite, we.adminApisHandler.CreateOrUpdateSymptoms)).Methods("PUT")
+
+	adminRestSubrouter.HandleFunc("/uin-overrides", we.adminAppIDTokenAuthWrapFunc(model.PermissionUINOverridesRead, we.adminApisHandler.GetUINOverrides)).Methods("GET")
+	adminRestSubrouter.HandleFunc("/uin-overrides", we.adminAppIDTokenAuthWrapFunc(model.PermissionUINOverridesWrite, we.adminApisHandler.CreateUINOverride)).Methods("POST")
+	adminRestSubrouter.HandleFunc("/uin-overrides/uin/{uin}", we.adminAppIDTokenAuthWrapFunc(model.PermissionUINOverridesWrite, we.adminApisHandler.UpdateUINOverride)).Methods("PUT")
+	adminRestSubrouter.HandleFunc("/uin-overrides/uin/{uin}", we.adminAppIDTokenAuthWrapFunc(model.PermissionUINOverridesWrite, we.adminApisHandler.DeleteUINOverride)).Methods("DELETE")
+
+	adminRestSubrouter.HandleFunc("/rosters", we.adminAppIDTokenAuthWrapFunc(model.PermissionRostersWrite, we.adminApisHandler.CreateRoster)).Methods("POST")
+	adminRestSubrouter.HandleFunc("/roster-items", we.adminAppIDTokenAuthWrapFunc(model.PermissionRostersWrite, we.adminApisHandler.CreateRosterItems)).Methods("POST")
+	adminRestSubrouter.HandleFunc("/rosters", we.adminAppIDTokenAuthWrapFunc(model.PermissionRostersRead, we.adminApisHandler.GetRosters)).Methods("GET")
+	adminRestSubrouter.HandleFunc("/rosters/phone/{phone}", we.adminAppIDTokenAuthWrapFunc(model.PermissionRostersWrite, we.adminApisHandler.DeleteRosterByPhone)).Methods("DELETE")
+	adminRestSubrouter.HandleFunc("/rosters/uin/{uin}", we.adminAppIDTokenAuthWrapFunc(model.PermissionRostersWrite, we.adminApisHandler.DeleteRosterByUIN)).Methods("DELETE")
+	adminRestSubrouter.HandleFunc("/rosters", we.adminAppIDTokenAuthWrapFunc(model.PermissionRostersWrite, we.adminApisHandler.DeleteAllRosters)).Methods("DELETE")
+	adminRestSubrouter.HandleFunc("/rosters/uin/{uin}", we.adminAppIDTokenAuthWrapFunc(model.PermissionRostersWrite, we.adminApisHandler.UpdateRoster)).Methods("PUT")
+	adminRestSubrouter.HandleFunc("/rosters/sync", we.adminAppIDTokenAuthWrapFunc(model.PermissionRostersWrite, we.adminApisHandler.SyncRosters)).Methods("PUT")
+	adminRestSubrouter.HandleFunc("/rosters/import", we.adminAppIDTokenAuthWrapFunc(model.PermissionRostersWrite, we.adminApisHandler.ImportRoster)).Methods("POST")
+	adminRestSubrouter.HandleFunc("/rosters/imports/{id}/errors", we.adminAppIDTokenAuthWrapFunc(model.PermissionRostersRead, we.adminApisHandler.GetRosterImportErrors)).Methods("GET")
+
+	adminRestSubrouter.HandleFunc("/raw-sub-account-items", we.adminAppIDTokenAuthWrapFunc(model.PermissionSubAccountsWrite, we.adminApisHandler.CreateSubAccountItems)).Methods("POST")
+	adminRestSubrouter.HandleFunc("/raw-sub-accounts", we.adminAppIDTokenAuthWrapFunc(model.PermissionSubAccountsRead, we.adminApisHandler.GetSubAccounts)).Methods("GET")
+	adminRestSubrouter.HandleFunc("/raw-sub-accounts/uin/{uin}", we.adminAppIDTokenAuthWrapFunc(model.PermissionSubAccountsWrite, we.adminApisHandler.UpdateSubAccount)).Methods("PUT")
+	adminRestSubrouter.HandleFunc("/raw-sub-accounts/uin/{uin}/revoke", we.adminAppIDTokenAuthWrapFunc(model.PermissionSubAccountsWrite, we.adminApisHandler.RevokeSubAccount)).Methods("PUT")
+	adminRestSubrouter.HandleFunc("/raw-sub-accounts/uin/{uin}", we.adminAppIDTokenAuthWrapFunc(model.PermissionSubAccountsWrite, we.adminApisHandler.DeleteSubAccountByUIN)).Methods("DELETE")
+	adminRestSubrouter.HandleFunc("/raw-sub-accounts", we.adminAppIDTokenAuthWrapFunc(model.PermissionSubAccountsWrite, we.adminApisHandler.DeleteAllSubAccounts)).Methods("DELETE")
+
+	adminRestSubrouter.HandleFunc("/user", we.adminAppIDTokenAuthWrapFunc(model.PermissionUsersRead, we.adminApisHandler.GetUserByExternalID)).Methods("GET").Queries("external-id", "")
+
+	adminRestSubrouter.HandleFunc("/actions", we.adminAppIDTokenAuthWrapFunc(model.PermissionActionsCreate, we.apisHandler.CreateAction)).Methods("POST")
+
+	adminRestSubrouter.HandleFunc("/audit", we.adminAppIDTokenAuthWrapFunc(model.PermissionAuditRead, we.apisHandler.GetAudit)).Methods("GET")
+	adminRestSubrouter.HandleFunc("/audit/verify", we.adminAppIDTokenAuthWrapFunc(model.PermissionAuditReadAll, we.adminApisHandler.VerifyAudit)).Methods("GET")
+	adminRestSubrouter.HandleFunc("/audit/metrics", we.adminAppIDTokenAuthWrapFunc(model.PermissionAuditReadAll, we.adminApisHandler.GetAuditMetrics)).Methods("GET")
+
+	adminRestSubrouter.HandleFunc("/notification-templates", we.adminAppIDTokenAuthWrapFunc(model.PermissionNotificationTemplatesRead, we.adminApisHandler.GetNotificationTemplates)).Methods("GET")
+	adminRestSubrouter.HandleFunc("/notification-templates", we.adminAppIDTokenAuthWrapFunc(model.PermissionNotificationTemplatesWrite, we.adminApisHandler.CreateNotificationTemplate)).Methods("POST")
+	adminRestSubrouter.HandleFunc("/notification-templates/{id}", we.adminAppIDTokenAuthWrapFunc(model.PermissionNotificationTemplatesWrite, we.adminApisHandler.UpdateNotificationTemplate)).Methods("PUT")
+	adminRestSubrouter.HandleFunc("/notification-templates/{id}", we.adminAppIDTokenAuthWrapFunc(model.PermissionNotificationTemplatesWrite, we.adminApisHandler.DeleteNotificationTemplate)).Methods("DELETE")
+
+	adminRestSubrouter.HandleFunc("/broadcasts", we.adminAppIDTokenAuthWrapFunc(model.PermissionBroadcastsRead, we.adminApisHandler.GetBroadcasts)).Methods("GET")
+	adminRestSubrouter.HandleFunc("/broadcasts", we.adminAppIDTokenAuthWrapFunc(model.PermissionBroadcastsWrite, we.adminApisHandler.CreateBroadcast)).Methods("POST")
+	adminRestSubrouter.HandleFunc("/broadcasts/preview", we.adminAppIDTokenAuthWrapFunc(model.PermissionBroadcastsWrite, we.adminApisHandler.PreviewBroadcast)).Methods("POST")
+	adminRestSubrouter.HandleFunc("/broadcasts/{id}/cancel", we.adminAppIDTokenAuthWrapFunc(model.PermissionBroadcastsWrite, we.adminApisHandler.CancelBroadcast)).Methods("PUT")
+
+	adminRestSubrouter.HandleFunc("/compliance", we.adminAppIDTokenAuthWrapFunc(model.PermissionComplianceRead, we.adminApisHandler.GetComplianceSummary)).Methods("GET")
+	adminRestSubrouter.HandleFunc("/compliance/items", we.adminAppIDTokenAuthWrapFunc(model.PermissionComplianceRead, we.adminApisHandler.GetComplianceItems)).Methods("GET")
+
+	adminRestSubrouter.HandleFunc("/exposure-verification-codes", we.adminAppIDTokenAuthWrapFunc(model.PermissionExposureCodesRead, we.adminApisHandler.GetExposureVerificationCodes)).Methods("GET")
+	adminRestSubrouter.HandleFunc("/exposure-verification-codes", we.adminAppIDTokenAuthWrapFunc(model.PermissionExposureCodesWrite, we.adminApisHandler.IssueExposureVerificationCode)).Methods("POST")
+	adminRestSubrouter.HandleFunc("/exposure-metrics", we.adminAppIDTokenAuthWrapFunc(model.PermissionExposureMetricsRead, we.adminApisHandler.GetExposureMetrics)).Methods("GET")
+
+	adminRestSubrouter.HandleFunc("/permissions", we.adminAppIDTokenAuthWrapFunc(model.PermissionRolesRead, we.adminApisHandler.GetPermissions)).Methods("GET")
+	adminRestSubrouter.HandleFunc("/roles", we.adminAppIDTokenAuthWrapFunc(model.PermissionRolesRead, we.adminApisHandler.GetRoles)).Methods("GET")
+	adminRestSubrouter.HandleFunc("/roles", we.adminAppIDTokenAuthWrapFunc(model.PermissionRolesWrite, we.adminApisHandler.CreateRole)).Methods("POST")
+	adminRestSubrouter.HandleFunc("/roles/{id}", we.adminAppIDTokenAuthWrapFunc(model.PermissionRolesWrite, we.adminApisHandler.UpdateRole)).Methods("PUT")
+	adminRestSubrouter.HandleFunc("/roles/{id}", we.adminAppIDTokenAuthWrapFunc(model.PermissionRolesWrite, we.adminApisHandler.DeleteRole)).Methods("DELETE")
 
 	log.Fatal(http.ListenAndServe(":80", router))
 }
@@ -557,9 +603,32 @@ func (we Adapter) getUser(w http.ResponseWriter, r *http.Request) {
 	w.Write(data)
 }
 
+//the token validators are part of the auth module so their metrics are served from here
+// @Description Gives the usage of the user token types, the app versions they are enabled for and the deprecated token types sunset date.
+// @Tags Admin
+// @ID GetTokenMetrics
+// @Accept json
+// @Success 200 {object} web.tokenMetrics
+// @Security AdminUserAuth
+// @Security AdminGroupAuth
+// @Router /admin/token-metrics [get]
+func (we Adapter) getTokenMetrics(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	data, err := json.Marshal(we.auth.userAuth.tokenValidators.metrics())
+	if err != nil {
+		log.Println("Error on marshal the token metrics")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
 type adminAuthFunc = func(model.User, string, http.ResponseWriter, *http.Request)
 
-func (we Adapter) adminAppIDTokenAuthWrapFunc(handler adminAuthFunc) http.HandlerFunc {
+//adminAppIDTokenAuthWrapFunc authenticates the admin and checks if the used group has the permission required by the handler
+func (we Adapter) adminAppIDTokenAuthWrapFunc(permission string, handler adminAuthFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
 		utils.LogRequest(req)
 
@@ -581,12 +650,8 @@ func (we Adapter) adminAppIDTokenAuthWrapFunc(handler adminAuthFunc) http.Handle
 		}
 
 		//authorization
-		sub := group        // the group that wants to access a resource.
-		obj := req.URL.Path // the resource that is going to be accessed.
-		act := req.Method   // the operation that the user performs on the resource.
-		acOK := we.authorization.Enforce(sub, obj, act)
-		if !acOK {
-			log.Printf("Access control error - %s is trying to apply %s operation for %s\n", sub, act, obj)
+		if !we.app.HasPermission(group, permission) {
+			log.Printf("Access control error - %s does not have %s permission for %s %s\n", group, permission, req.Method, req.URL.Path)
 			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
 			return
 		}
@@ -595,19 +660,45 @@ func (we Adapter) adminAppIDTokenAuthWrapFunc(handler adminAuthFunc) http.Handle
 	}
 }
 
-func (we Adapter) providerAuthWrapFunc(handler http.HandlerFunc) http.HandlerFunc {
+//providerAuthWrapFunc checks that the provider credential has the scope. The shared keys are not bound to a provider,
+//they are accepted for all the providers APIs until their sunset.
+func (we Adapter) providerAuthWrapFunc(scope string, handler http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
 		utils.LogRequest(req)
 
-		authenticated := we.auth.providersCheck(w, req)
+		authenticated, credential := we.auth.providersCheck(w, req)
 		if !authenticated {
 			return
 		}
 
+		//authorization
+		if credential != nil && !credential.HasScope(scope) {
+			log.Printf("Access control error - provider %s (%s) does not have %s scope for %s %s\n", credential.ProviderID, credential.KeyPrefix,
+				scope, req.Method, req.URL.Path)
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			return
+		}
+
 		handler(w, req)
 	}
 }
 
+type providerCredentialAuthFunc = func(*model.ProviderCredential, http.ResponseWriter, *http.Request)
+
+//providerCredentialAuthWrapFunc passes the provider credential to the handler, it is nil for the shared keys which are accepted until their sunset
+func (we Adapter) providerCredentialAuthWrapFunc(handler providerCredentialAuthFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		utils.LogRequest(req)
+
+		authenticated, credential := we.auth.providersCheck(w, req)
+		if !authenticated {
+			return
+		}
+
+		handler(credential, w, req)
+	}
+}
+
 func (we Adapter) externalAuthWrapFunc(handler http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, req *http.Request) {
 		utils.LogRequest(req)
@@ -624,14 +715,15 @@ func (we Adapter) externalAuthWrapFunc(handler http.HandlerFunc) http.HandlerFun
 //NewWebAdapter creates new WebAdapter instance
 func NewWebAdapter(host string, app *core.Application, appKeys []string, oidcProvider string,
 	oidcAppClientID string, adminAppClientID string, adminWebAppClientID string, phoneAuthSecret string,
-	authKeys string, authIssuer string, providersKeys []string, externalAPIKeys []string) Adapter {
+	authKeys string, authKeysURL string, authIssuer string, authAudience string, authClockSkew string,
+	tokensAppVersions string, deprecatedTokensSunset string, providersKeys []string, providersSharedKeysSunset string, externalAPIKeys []string) Adapter {
 	auth := NewAuth(app, appKeys, oidcProvider, oidcAppClientID, adminAppClientID, adminWebAppClientID,
-		phoneAuthSecret, authKeys, authIssuer, providersKeys, externalAPIKeys)
-	authorization := casbin.NewEnforcer("driver/web/authorization_model.conf", "driver/web/authorization_policy.csv")
+		phoneAuthSecret, authKeys, authKeysURL, authIssuer, authAudience, authClockSkew, tokensAppVersions, deprecatedTokensSunset,
+		providersKeys, providersSharedKeysSunset, externalAPIKeys)
 
 	apisHandler := rest.NewApisHandler(app)
 	adminApisHandler := rest.NewAdminApisHandler(app)
-	return Adapter{host: host, auth: auth, authorization: authorization, apisHandler: apisHandler, adminApisHandler: adminApisHandler, app: app}
+	return Adapter{host: host, auth: auth, apisHandler: apisHandler, adminApisHandler: adminApisHandler, app: app}
 }
 
 //AppListener implements core.ApplicationListener interface
@@ -643,16 +735,14 @@ type AppListener struct {
 func (al *AppListener) OnUserDeleted(userID string) {
 	log.Println("AppListener -> OnUserDeleted -> " + userID)
 
-	//we cannot clear just the user as we do not have the external id, so clear all cached users
-	al.adapter.auth.userAuth.clearCacheUsers()
+	//do nothing, the application takes the user out from the cached users
 }
 
 //OnUserUpdated notifies that a user has been updated
 func (al *AppListener) OnUserUpdated(user model.User) {
 	log.Println("AppListener -> OnUserUpdated -> " + user.ID)
 
-	//take out the updated user from the cached users
-	al.adapter.auth.userAuth.deleteCacheUser(user.ExternalID)
+	//do nothing, the application takes the user out from the cached users
 }
 
 //OnUserCreated notifies that a user has been created
@@ -666,10 +756,9 @@ func (al *AppListener) OnUserCreated(user model.User) {
 func (al *AppListener) OnRostersUpdated() {
 	log.Println("AppListener -> OnRostersUpdated")
 
-	//clear the cached users and reload the rosters
+	//clear the cached roster phones, the application clears the cached users
 	go func() {
-		al.adapter.auth.userAuth.clearCacheUsers()
-		al.adapter.auth.userAuth.loadRosters()
+		al.adapter.auth.userAuth.clearRosters()
 	}()
 }
 
@@ -677,8 +766,5 @@ func (al *AppListener) OnRostersUpdated() {
 func (al *AppListener) OnSubAccountsUpdated() {
 	log.Println("AppListener -> OnSubAccountsUpdated")
 
-	//clear the cached users
-	go func() {
-		al.adapter.auth.userAuth.clearCacheUsers()
-	}()
+	//do nothing, the application clears the cached users
 }
@@ -18,30 +18,44 @@
 package web
 
 import (
+	"bytes"
 	"context"
+	"crypto/hmac"
 	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"health/core"
 	"health/core/model"
 	"health/utils"
+	"io/ioutil"
 	"log"
 	"net/http"
+	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/golang-jwt/jwt"
 	"github.com/lestrrat-go/jwx/jwk"
-	"golang.org/x/sync/syncmap"
 	"gopkg.in/ericchiang/go-oidc.v2"
 )
 
-type cacheUser struct {
-	user      *model.User
-	lastUsage time.Time
-}
+const (
+	//the max number of the roster phones kept in memory, the others are found in the storage
+	rostersCacheSize = 10000
+
+	//the providers requests per minute if the provider credential does not set its limit
+	providersDefaultRateLimit = 600
+	//how much the signed providers requests timestamps could differ from the current time
+	providersSignatureMaxAge = 5 * time.Minute
+
+	//the allowed difference with the auth service clock if not configured
+	authDefaultClockSkew = 30 * time.Second
+)
 
 //Auth handler
 type Auth struct {
@@ -54,7 +68,6 @@ type Auth struct {
 
 //Start starts the auth module
 func (auth *Auth) Start() error {
-	auth.adminAuth.start()
 	auth.userAuth.start()
 
 	return nil
@@ -88,7 +101,7 @@ func (auth *Auth) externalAuthCheck(w http.ResponseWriter, r *http.Request) bool
 	return auth.externalAuth.check(w, r)
 }
 
-func (auth *Auth) providersCheck(w http.ResponseWriter, r *http.Request) bool {
+func (auth *Auth) providersCheck(w http.ResponseWriter, r *http.Request) (bool, *model.ProviderCredential) {
 	return auth.providersAuth.check(w, r)
 }
 
@@ -111,12 +124,14 @@ func (auth *Auth) createAppUser(externalID string, uuid string, publicKey string
 //NewAuth creates new auth handler
 func NewAuth(app *core.Application, appKeys []string, oidcProvider string,
 	oidcAppClientID string, appClientID string, webAppClientID string, phoneAuthSecret string,
-	authKeys string, authIssuer string, providersAPIKeys []string, externalAPIKeys []string) *Auth {
-	apiKeysAuth := newAPIKeysAuth(appKeys)
-	userAuth2 := newUserAuth(app, oidcProvider, oidcAppClientID, phoneAuthSecret, authKeys, authIssuer)
+	authKeys string, authKeysURL string, authIssuer string, authAudience string, authClockSkew string,
+	tokensAppVersions string, deprecatedTokensSunset string, providersAPIKeys []string, providersSharedKeysSunset string, externalAPIKeys []string) *Auth {
+	apiKeysAuth := newAPIKeysAuth(app, appKeys)
+	userAuth2 := newUserAuth(app, oidcProvider, oidcAppClientID, phoneAuthSecret, authKeys, authKeysURL, authIssuer, authAudience, authClockSkew,
+		tokensAppVersions, deprecatedTokensSunset)
 	adminAuth := newAdminAuth(app, oidcProvider, appClientID, webAppClientID)
-	providersAuth := newProviderAuth(providersAPIKeys)
-	externalAuth := newExternalAuth(externalAPIKeys)
+	providersAuth := newProviderAuth(app, providersAPIKeys, providersSharedKeysSunset)
+	externalAuth := newExternalAuth(app, externalAPIKeys)
 
 	auth := Auth{apiKeysAuth: apiKeysAuth, userAuth: userAuth2, adminAuth: adminAuth, providersAuth: providersAuth, externalAuth: externalAuth}
 	return &auth
@@ -126,7 +141,8 @@ func NewAuth(app *core.Application, appKeys []string, oidcProvider string,
 
 //APIKeysAuth entity
 type APIKeysAuth struct {
-	appKeys []string
+	app     *core.Application
+	appKeys []string //the keys from the environment, the other keys are managed by the admins
 }
 
 func (auth *APIKeysAuth) check(w http.ResponseWriter, r *http.Request) (bool, *string) {
@@ -147,15 +163,8 @@ func (auth *APIKeysAuth) check(w http.ResponseWriter, r *http.Request) (bool, *s
 		return false, nil
 	}
 
-	//check if the api key is one of the listed
-	appKeys := auth.appKeys
-	exist := false
-	for _, element := range appKeys {
-		if element == apiKey {
-			exist = true
-			break
-		}
-	}
+	//check if the api key is one of the listed or a managed key for the apps
+	exist := utils.Contains(auth.appKeys, apiKey) || auth.app.FindAPIKey(apiKey, model.APIKeyScopeApp) != nil
 	if !exist {
 		//not exist, so return 401
 		log.Println(fmt.Sprintf("401 - Unauthorized for key %s", apiKey))
@@ -167,8 +176,8 @@ func (auth *APIKeysAuth) check(w http.ResponseWriter, r *http.Request) (bool, *s
 	return true, appVersion
 }
 
-func newAPIKeysAuth(appKeys []string) *APIKeysAuth {
-	auth := APIKeysAuth{appKeys}
+func newAPIKeysAuth(app *core.Application, appKeys []string) *APIKeysAuth {
+	auth := APIKeysAuth{app: app, appKeys: appKeys}
 	return &auth
 }
 
@@ -176,7 +185,8 @@ func newAPIKeysAuth(appKeys []string) *APIKeysAuth {
 
 //ExternalAuth entity
 type ExternalAuth struct {
-	appKeys []string
+	app     *core.Application
+	appKeys []string //the keys from the environment, the other keys are managed by the admins
 }
 
 func (auth *ExternalAuth) check(w http.ResponseWriter, r *http.Request) bool {
@@ -191,15 +201,8 @@ func (auth *ExternalAuth) check(w http.ResponseWriter, r *http.Request) bool {
 		return false
 	}
 
-	//check if the api key is one of the listed
-	appKeys := auth.appKeys
-	exist := false
-	for _, element := range appKeys {
-		if element == apiKey {
-			exist = true
-			break
-		}
-	}
+	//check if the api key is one of the listed or a managed key for the external systems
+	exist := utils.Contains(auth.appKeys, apiKey) || auth.app.FindAPIKey(apiKey, model.APIKeyScopeExternal) != nil
 	if !exist {
 		//not exist, so return 401
 		log.Println(fmt.Sprintf("401 - Unauthorized for key %s", apiKey))
@@ -212,8 +215,8 @@ func (auth *ExternalAuth) check(w http.ResponseWriter, r *http.Request) bool {
 }
 
 //newExternalAuth creates new internal auth
-func newExternalAuth(apiKeys []string) *ExternalAuth {
-	auth := ExternalAuth{appKeys: apiKeys}
+func newExternalAuth(app *core.Application, apiKeys []string) *ExternalAuth {
+	auth := ExternalAuth{app: app, appKeys: apiKeys}
 	return &auth
 }
 
@@ -234,62 +237,6 @@ type AdminAuth struct {
 	appClientID    string
 	webAppVerifier *oidc.IDTokenVerifier
 	webAppClientID string
-
-	cachedUsers     *syncmap.Map //cache users while active - 5 minutes timeout
-	cachedUsersLock *sync.RWMutex
-}
-
-func (auth *AdminAuth) start() {
-	go auth.cleanCacheUser()
-}
-
-//cleanChacheUser cleans all users from the cache with no activity > 5 minutes
-func (auth *AdminAuth) cleanCacheUser() {
-	log.Println("AdminAuth -> cleanCacheUser -> start")
-
-	toRemove := []string{}
-
-	//find all users to remove - more than 5 minutes period from their last usage
-	now := time.Now().Unix()
-	auth.cachedUsers.Range(func(key, value interface{}) bool {
-		cacheUser, ok := value.(*cacheUser)
-		if !ok {
-			return false //break the iteration
-		}
-		externalID, ok := key.(string)
-		if !ok {
-			return false //break the iteration
-		}
-
-		difference := now - cacheUser.lastUsage.Unix()
-		//5 minutes
-		if difference > 300 {
-			toRemove = append(toRemove, externalID)
-		}
-
-		// this will continue iterating
-		return true
-	})
-
-	//remove the selected ones
-	count := len(toRemove)
-	if count > 0 {
-		log.Printf("AdminAuth -> cleanCacheUser -> %d items to remove\n", count)
-
-		for _, key := range toRemove {
-			auth.deleteCacheUser(key)
-		}
-	} else {
-		log.Println("AdminAuth -> cleanCacheUser -> nothing to remove")
-	}
-
-	nextLoad := time.Minute * 5
-	log.Printf("AdminAuth -> cleanCacheUser() -> next exec after %s\n", nextLoad)
-	timer := time.NewTimer(nextLoad)
-	<-timer.C
-	log.Println("AdminAuth -> cleanCacheUser() -> timer expired")
-
-	auth.cleanCacheUser()
 }
 
 func (auth *AdminAuth) check(w http.ResponseWriter, r *http.Request) (bool, *model.User, string, *model.ShibbolethAuth) {
@@ -428,10 +375,7 @@ func (auth *AdminAuth) updateShiboDataIfNeeded(current model.User, userData user
 	if !isEqual {
 		log.Println("updateUserIfNeeded -> need to update user")
 
-		//1. remove it from the cache
-		auth.deleteCacheUser(current.ShibbolethAuth.Uin)
-
-		//2. update it
+		//update it, the application takes it out from the cached users
 		current.ShibbolethAuth.IsMemberOf = userData.UIuceduIsMemberOf
 		err := auth.app.UpdateUser(&current)
 		if err != nil {
@@ -442,65 +386,14 @@ func (auth *AdminAuth) updateShiboDataIfNeeded(current model.User, userData user
 	return &current, nil
 }
 
-func (auth *AdminAuth) getCachedUser(externalID string) *cacheUser {
-	auth.cachedUsersLock.RLock()
-	defer auth.cachedUsersLock.RUnlock()
-
-	var cachedUser *cacheUser //to return
-
-	item, _ := auth.cachedUsers.Load(externalID)
-	if item != nil {
-		cachedUser = item.(*cacheUser)
-	}
-
-	//keep the last get time
-	if cachedUser != nil {
-		cachedUser.lastUsage = time.Now()
-		auth.cachedUsers.Store(externalID, cachedUser)
-	}
-
-	return cachedUser
-}
-
-func (auth *AdminAuth) cacheUser(externalID string, user *model.User) {
-	auth.cachedUsersLock.RLock()
-
-	cacheUser := &cacheUser{user: user, lastUsage: time.Now()}
-	auth.cachedUsers.Store(externalID, cacheUser)
-
-	auth.cachedUsersLock.RUnlock()
-}
-
-func (auth *AdminAuth) deleteCacheUser(externalID string) {
-	auth.cachedUsersLock.RLock()
-
-	auth.cachedUsers.Delete(externalID)
-
-	auth.cachedUsersLock.RUnlock()
-}
-
 func (auth *AdminAuth) getUser(uiUceduUIN string) (*model.User, error) {
-	var err error
-
-	//1. First check if cached
-	cachedUser := auth.getCachedUser(uiUceduUIN)
-	if cachedUser != nil {
-		return cachedUser.user, nil
-	}
-
-	//2. Check if we have a such user in the application
+	//the application caches the recently used users
 	user, err := auth.app.FindUserByShibbolethID(uiUceduUIN)
 	if err != nil {
 		log.Printf("error finding an for external id - %s\n", err)
 		return nil, err
 	}
-	if user != nil {
-		//cache it
-		auth.cacheUser(uiUceduUIN, user)
-		return user, nil
-	}
-	//there is no a such user
-	return nil, nil
+	return user, nil
 }
 
 func (auth *AdminAuth) responseBadRequest(w http.ResponseWriter) {
@@ -540,12 +433,8 @@ func newAdminAuth(app *core.Application, oidcProvider string, appClientID string
 	appVerifier := provider.Verifier(&oidc.Config{ClientID: appClientID})
 	webAppVerifier := provider.Verifier(&oidc.Config{ClientID: webAppClientID})
 
-	cacheUsers := &syncmap.Map{}
-	lock := &sync.RWMutex{}
-
 	auth := AdminAuth{app: app, appVerifier: appVerifier, appClientID: appClientID,
-		webAppVerifier: webAppVerifier, webAppClientID: webAppClientID,
-		cachedUsers: cacheUsers, cachedUsersLock: lock}
+		webAppVerifier: webAppVerifier, webAppClientID: webAppClientID}
 	return &auth
 }
 
@@ -553,10 +442,22 @@ func newAdminAuth(app *core.Application, oidcProvider string, appClientID string
 
 //ProvidersAuth entity
 type ProvidersAuth struct {
-	appKeys []string
+	app     *core.Application
+	appKeys []string //the shared keys from the environment, they are not bound to a provider
+
+	sharedKeysSunset *time.Time //the shared keys and the managed API keys are rejected after it, no sunset if nil
+
+	rateLock    *sync.Mutex
+	rateWindows map[string]*providerRateWindow
 }
 
-func (auth *ProvidersAuth) check(w http.ResponseWriter, r *http.Request) bool {
+//providerRateWindow counts the requests of a provider in the current minute
+type providerRateWindow struct {
+	start time.Time
+	count int
+}
+
+func (auth *ProvidersAuth) check(w http.ResponseWriter, r *http.Request) (bool, *model.ProviderCredential) {
 	apiKey := r.Header.Get("ROKWIRE-HS-API-KEY")
 	//check if there is api key in the header
 	if len(apiKey) == 0 {
@@ -565,31 +466,128 @@ func (auth *ProvidersAuth) check(w http.ResponseWriter, r *http.Request) bool {
 
 		w.WriteHeader(http.StatusBadRequest)
 		w.Write([]byte("Bad Request"))
-		return false
+		return false, nil
 	}
 
-	//check if the api key is one of the listed
-	appKeys := auth.appKeys
-	exist := false
-	for _, element := range appKeys {
-		if element == apiKey {
-			exist = true
-			break
+	//check if the api key is a provider credential or one of the shared keys
+	var caller string
+	var rateKey string
+	rateLimit := providersDefaultRateLimit
+	credential := auth.app.FindProviderCredential(apiKey)
+	if credential != nil {
+		if credential.RequireSignature {
+			err := auth.checkSignature(r, credential.SigningSecret)
+			if err != nil {
+				log.Println(fmt.Sprintf("401 - Unauthorized for provider %s - %s", credential.ProviderID, err))
+
+				w.WriteHeader(http.StatusUnauthorized)
+				w.Write([]byte("Unauthorized"))
+				return false, nil
+			}
 		}
-	}
-	if !exist {
+
+		caller = fmt.Sprintf("provider %s (%s)", credential.ProviderID, credential.KeyPrefix)
+		rateKey = credential.ProviderID
+		if credential.RateLimit > 0 {
+			rateLimit = credential.RateLimit
+		}
+	} else if utils.Contains(auth.appKeys, apiKey) {
+		caller = fmt.Sprintf("shared key %s", utils.GetLogValue(apiKey))
+		rateKey = apiKey
+	} else if sharedKey := auth.app.FindAPIKey(apiKey, model.APIKeyScopeProviders); sharedKey != nil {
+		caller = fmt.Sprintf("shared key %s (%s)", sharedKey.Name, sharedKey.KeyPrefix)
+		rateKey = sharedKey.ID
+	} else {
 		//not exist, so return 401
-		log.Println(fmt.Sprintf("401 - Unauthorized for key %s", apiKey))
+		log.Println(fmt.Sprintf("401 - Unauthorized for key %s", utils.GetLogValue(apiKey)))
+
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte("Unauthorized"))
+		return false, nil
+	}
+
+	//the shared keys are not bound to a provider, so they are accepted only until their sunset
+	if credential == nil && auth.sharedKeysSunset != nil && time.Now().After(*auth.sharedKeysSunset) {
+		log.Println(fmt.Sprintf("401 - Unauthorized for %s after the shared keys sunset", caller))
 
 		w.WriteHeader(http.StatusUnauthorized)
 		w.Write([]byte("Unauthorized"))
+		return false, nil
+	}
+
+	//every provider has its own rate limit
+	if !auth.allowRequest(rateKey, rateLimit) {
+		log.Println(fmt.Sprintf("429 - Too Many Requests for %s", caller))
+
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte("Too Many Requests"))
+		return false, nil
+	}
+
+	log.Printf("%s - %s %s", caller, r.Method, r.URL.Path)
+	return true, credential
+}
+
+//checkSignature checks the request HMAC-SHA256 signature. It is the hex encoded HMAC of the timestamp, the method, the request uri
+//and the hex encoded SHA256 hash of the body, separated by new lines.
+func (auth *ProvidersAuth) checkSignature(r *http.Request, secret string) error {
+	signature := r.Header.Get("ROKWIRE-HS-SIGNATURE")
+	timestamp := r.Header.Get("ROKWIRE-HS-TIMESTAMP")
+	if len(signature) == 0 || len(timestamp) == 0 {
+		return errors.New("the request is not signed")
+	}
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return errors.New("invalid signature timestamp")
+	}
+	age := time.Since(time.Unix(seconds, 0))
+	if age > providersSignatureMaxAge || age < -providersSignatureMaxAge {
+		return errors.New("the signature timestamp is out of the allowed period")
+	}
+
+	//read the body and give it back to the request
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+	bodyHash := sha256.Sum256(body)
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "\n" + r.Method + "\n" + r.URL.RequestURI() + "\n" + hex.EncodeToString(bodyHash[:])))
+	expected := mac.Sum(nil)
+
+	actual, err := hex.DecodeString(signature)
+	if err != nil || !hmac.Equal(actual, expected) {
+		return errors.New("invalid signature")
+	}
+	return nil
+}
+
+func (auth *ProvidersAuth) allowRequest(key string, limit int) bool {
+	auth.rateLock.Lock()
+	defer auth.rateLock.Unlock()
+
+	now := time.Now()
+	window := auth.rateWindows[key]
+	if window == nil || now.Sub(window.start) >= time.Minute {
+		window = &providerRateWindow{start: now}
+		auth.rateWindows[key] = window
+	}
+	if window.count >= limit {
 		return false
 	}
+	window.count++
 	return true
 }
 
-func newProviderAuth(appKeys []string) *ProvidersAuth {
-	auth := ProvidersAuth{appKeys}
+func newProviderAuth(app *core.Application, appKeys []string, sharedKeysSunset string) *ProvidersAuth {
+	auth := ProvidersAuth{app: app, appKeys: appKeys, rateLock: &sync.Mutex{}, rateWindows: map[string]*providerRateWindow{}}
+	sunset, err := parseSunset(sharedKeysSunset)
+	if err != nil {
+		log.Fatalf("bad providers shared keys sunset date - %s", sharedKeysSunset)
+	}
+	auth.sharedKeysSunset = sunset
 	return &auth
 }
 
@@ -597,6 +595,13 @@ type shData struct {
 	UIuceduUIN *string `json:"uiucedu_uin"`
 }
 
+type upgradeRequiredResponse struct {
+	Code      string     `json:"code"`
+	TokenType string     `json:"token_type"`
+	Sunset    *time.Time `json:"sunset"`
+	Message   string     `json:"message"`
+}
+
 type tokenData struct {
 	UID      string
 	Name     string
@@ -613,94 +618,29 @@ type tokenData struct {
 type UserAuth struct {
 	app *core.Application
 
-	//shibboleth - keep for back compatability
-	appIDTokenVerifier *oidc.IDTokenVerifier
-
-	//phone - keep for back compatability
-	phoneAuthSecret string
+	//the shibboleth, the phone and the access tokens validators
+	tokenValidators *tokenValidatorChain
 
 	//auth service
-	Keys   *jwk.Set
-	Issuer string
+	keys      *jwksCache
+	Issuer    string
+	Audience  string        //not checked if empty
+	clockSkew time.Duration //the allowed difference with the auth service clock for the exp and nbf claims
 
-	cachedUsers     *syncmap.Map //cache users while active - 5 minutes timeout
-	cachedUsersLock *sync.RWMutex
-
-	rosters     []map[string]string //cache rosters
-	rostersLock *sync.RWMutex
+	rosters        *utils.LRUCache //phone -> uin for the recently used roster phones, empty uin for the phones which are not in the roster
+	rostersVersion int64           //increased on every roster change
 }
 
 func (auth *UserAuth) start() {
-	auth.loadRosters()
-	go auth.cleanCacheUser()
-}
-
-//loadRosters loads all rosters
-func (auth *UserAuth) loadRosters() {
-	log.Println("UserAuth -> load Rosters")
-
-	rosters, err := auth.app.LoadAllRosters()
-	if err != nil {
-		log.Println("cannot load rosters")
-	}
-
-	count := len(rosters)
-	newValues := make([]map[string]string, count)
-	if count > 0 {
-		for index, item := range rosters {
-			newValues[index] = item
-		}
-	}
-	auth.setRosters(newValues)
+	auth.keys.start()
 }
 
-//cleanChacheUser cleans all users from the cache with no activity > 5 minutes
-func (auth *UserAuth) cleanCacheUser() {
-	log.Println("UserAuth -> cleanCacheUser -> start")
-
-	toRemove := []string{}
-
-	//find all users to remove - more than 5 minutes period from their last usage
-	now := time.Now().Unix()
-	auth.cachedUsers.Range(func(key, value interface{}) bool {
-		cacheUser, ok := value.(*cacheUser)
-		if !ok {
-			return false //break the iteration
-		}
-		externalID, ok := key.(string)
-		if !ok {
-			return false //break the iteration
-		}
-
-		difference := now - cacheUser.lastUsage.Unix()
-		//5 minutes
-		if difference > 300 {
-			toRemove = append(toRemove, externalID)
-		}
-
-		// this will continue iterating
-		return true
-	})
-
-	//remove the selected ones
-	count := len(toRemove)
-	if count > 0 {
-		log.Printf("UserAuth -> cleanCacheUser -> %d items to remove\n", count)
-
-		for _, key := range toRemove {
-			auth.deleteCacheUser(key)
-		}
-	} else {
-		log.Println("UserAuth -> cleanCacheUser -> nothing to remove")
-	}
-
-	nextLoad := time.Minute * 5
-	log.Printf("UserAuth -> cleanCacheUser() -> next exec after %s\n", nextLoad)
-	timer := time.NewTimer(nextLoad)
-	<-timer.C
-	log.Println("UserAuth -> cleanCacheUser() -> timer expired")
+//clearRosters clears the cached roster phones
+func (auth *UserAuth) clearRosters() {
+	log.Println("UserAuth -> clearRosters")
 
-	auth.cleanCacheUser()
+	atomic.AddInt64(&auth.rostersVersion, 1)
+	auth.rosters.Purge()
 }
 
 func (auth *UserAuth) mainCheck(w http.ResponseWriter, r *http.Request) (bool, *model.User, *string, *string, *string) {
@@ -731,66 +671,16 @@ func (auth *UserAuth) mainCheck(w http.ResponseWriter, r *http.Request) (bool, *
 		return false, nil, nil, nil, nil
 	}
 
-	// determine the token type: 1 for shibboleth, 2 for phone, 3 for auth access token
-	// 1 & 2 are deprecated but we support them for back compatability
-	tokenType, err := auth.getTokenType(rawToken)
+	// validate the token with the registered validator of its type and extract the user identifier
+	// the shibboleth and the phone tokens are deprecated but we support them for back compatability until the sunset date
+	validator, externalID, authType, err := auth.tokenValidators.validate(rawToken, *tokenSourceType, csrfToken, appVersion)
 	if err != nil {
-		auth.responseUnauthorized(err.Error(), w)
-		return false, nil, nil, nil, nil
-	}
-	if !(*tokenType == 1 || *tokenType == 2 || *tokenType == 3) {
-		auth.responseUnauthorized("not supported token type", w)
-		return false, nil, nil, nil, nil
-	}
-
-	// process the token - validate it, extract the user identifier
-	var externalID string
-	var authType string
-
-	switch *tokenType {
-	case 1:
-		//support this for back compatability
-		uin, err := auth.processShibbolethToken(rawToken)
-		if err != nil {
-			auth.responseUnauthorized(err.Error(), w)
-			return false, nil, nil, nil, nil
-		}
-		externalID = *uin
-		authType = "shibboleth"
-	case 2:
-		//support this for back compatability
-		phone, err := auth.processPhoneToken(rawToken)
-		if err != nil {
-			auth.responseUnauthorized(err.Error(), w)
-			return false, nil, nil, nil, nil
-		}
-		externalID = *phone
-		authType = "phone"
-	case 3:
-		//mobile app sends just token, the browser sends token + csrf token
-
-		csrfCheck := false
-		if *tokenSourceType == "cookie" {
-			csrfCheck = true
-		}
-
-		tokenData, err := auth.processAccessToken(rawToken, csrfCheck, csrfToken)
-		if err != nil {
-			auth.responseUnauthorized(err.Error(), w)
-			return false, nil, nil, nil, nil
-		}
-
-		tokenAuth := tokenData.Auth
-		if tokenAuth == "oidc" {
-			externalID = tokenData.UID
-			authType = "shibboleth"
-		} else if tokenAuth == "rokwire_phone" {
-			externalID = tokenData.UID
-			authType = "phone"
-		} else {
-			auth.responseUnauthorized("not supported token auth type", w)
+		if errors.Is(err, errUpgradeRequired) {
+			auth.responseUpgradeRequired(validator.validator.tokenType(), w)
 			return false, nil, nil, nil, nil
 		}
+		auth.responseUnauthorized(err.Error(), w)
+		return false, nil, nil, nil, nil
 	}
 
 	//TODO - refactor!!!
@@ -919,10 +809,7 @@ func (auth *UserAuth) createDefaultAccountIfNeeded(current model.User) (*model.U
 
 	log.Printf("createDefaultAccountIfNeeded -> we need to create default account!")
 
-	//1. remove it from the cache
-	auth.deleteCacheUser(current.ExternalID)
-
-	//2. create default account
+	//create default account, the application takes the user out from the cached users
 	user, err := auth.app.CreateDefaultAccount(current.ID)
 	if err != nil {
 		return nil, err
@@ -1000,31 +887,28 @@ func (auth *UserAuth) validateToken(token string, tokenType string) (*tokenData,
 		return nil, err
 	}
 
-	//check issuer
-	if tokenData.ISS != auth.Issuer {
-		log.Printf("issuer does not match: - %s", tokenData.ISS)
-		return nil, errors.New("issuer does not match:" + tokenData.ISS)
-	}
-
 	//check keys
 	kid := headerMap["kid"]
 	if len(kid) == 0 {
 		log.Println("kid header is missing")
 		return nil, errors.New("kid header is missing")
 	}
-	jwkMatch := auth.Keys.LookupKeyID(kid)
-	if len(jwkMatch) == 0 {
-		log.Printf("no matching kid found")
-		return nil, errors.New("no matching kid found")
-	} else if len(jwkMatch) > 1 {
-		log.Printf("multiple matching kids found")
-		return nil, errors.New("multiple matching kids found")
+	key, err := auth.keys.lookup(kid)
+	if err != nil {
+		log.Printf("error finding the key for kid %s - %s", kid, err)
+		return nil, err
+	}
+	publicKey, ok := key.(jwk.RSAPublicKey)
+	if !ok {
+		log.Printf("the key for kid %s is not a rsa public key", kid)
+		return nil, errors.New("not supported key type")
 	}
-	publicKey := jwkMatch[0].(jwk.RSAPublicKey)
 
-	//validate
+	//validate the signature, the claims are checked below with the clock skew
 	jwk := rsa.PublicKey{}
-	parsedToken, err := jwt.ParseWithClaims(token, jwt.MapClaims{}, func(token *jwt.Token) (interface{}, error) {
+	claims := jwt.MapClaims{}
+	parser := jwt.Parser{ValidMethods: []string{"RS256", "RS384", "RS512"}, SkipClaimsValidation: true}
+	parsedToken, err := parser.ParseWithClaims(token, claims, func(token *jwt.Token) (interface{}, error) {
 		if err := publicKey.Raw(&jwk); err != nil {
 			log.Println("failed to create public key:", err)
 			return nil, err
@@ -1040,6 +924,24 @@ func (auth *UserAuth) validateToken(token string, tokenType string) (*tokenData,
 		return nil, errors.New("not valid token:" + token)
 	}
 
+	//check the claims
+	now := time.Now().Unix()
+	skew := int64(auth.clockSkew.Seconds())
+	if !claims.VerifyIssuer(auth.Issuer, true) {
+		log.Printf("issuer does not match: - %s", tokenData.ISS)
+		return nil, errors.New("issuer does not match:" + tokenData.ISS)
+	}
+	if len(auth.Audience) > 0 && !claims.VerifyAudience(auth.Audience, true) {
+		log.Printf("audience does not match: - %v", claims["aud"])
+		return nil, errors.New("audience does not match")
+	}
+	if !claims.VerifyExpiresAt(now-skew, true) {
+		return nil, errors.New("token is expired or has no exp claim")
+	}
+	if !claims.VerifyNotBefore(now+skew, false) {
+		return nil, errors.New("token is not valid yet")
+	}
+
 	//check token type
 	if tokenData.Type != tokenType {
 		log.Printf("invalid type %s", tokenData.Type)
@@ -1049,87 +951,34 @@ func (auth *UserAuth) validateToken(token string, tokenType string) (*tokenData,
 	return tokenData, nil
 }
 
-func (auth *UserAuth) processShibbolethToken(token string) (*string, error) {
-	// Validate the token
-	idToken, err := auth.appIDTokenVerifier.Verify(context.Background(), token)
-	if err != nil {
-		log.Printf("error validating token - %s\n", err)
-		return nil, err
-	}
-
-	// Get the user data from the token
-	var userData shData
-	if err := idToken.Claims(&userData); err != nil {
-		log.Printf("error getting user data from token - %s\n", err)
-		return nil, err
-	}
-	//we must have UIuceduUIN
-	if userData.UIuceduUIN == nil {
-		log.Printf("missing uiuceuin data in the token - %s\n", token)
-		return nil, errors.New("missing uiuceuin data in the token")
-	}
-	return userData.UIuceduUIN, nil
-}
-
 func (auth *UserAuth) findUINByPhone(phone string) *string {
-	rosters := auth.getRosters()
-	if len(rosters) == 0 {
-		return nil
-	}
-
-	for _, item := range rosters {
-		cPhone := item["phone"]
-		if cPhone == phone {
-			uin := item["uin"]
-			return &uin
+	if value, ok := auth.rosters.Get(phone); ok {
+		uin := value.(string)
+		if len(uin) == 0 {
+			return nil
 		}
+		return &uin
 	}
-	return nil
-}
 
-func (auth *UserAuth) processPhoneToken(token string) (*string, error) {
-	claims := jwt.MapClaims{}
-	_, err := jwt.ParseWithClaims(token, claims, func(token *jwt.Token) (interface{}, error) {
-		return []byte(auth.phoneAuthSecret), nil
-	})
+	version := atomic.LoadInt64(&auth.rostersVersion)
+	roster, err := auth.app.FindRosterByPhone(phone)
 	if err != nil {
-		return nil, err
+		log.Printf("error finding the roster for a phone - %s", err)
+		return nil
 	}
-
-	for key, val := range claims {
-		if key == "phoneNumber" {
-			phoneValue := val.(string)
-			return &phoneValue, nil
-		}
+	var uin string
+	if roster != nil {
+		uin = roster.UIN
 	}
-	return nil, errors.New("there is no phoneNumber claim in the phone token")
-}
-
-// type: 1 for shibboleth, 2 for phone, 3 for auth access token
-// 1 & 2 are deprecated but we support them for back compatability
-func (auth *UserAuth) getTokenType(token string) (*int, error) {
-	parser := new(jwt.Parser)
-	claims := jwt.MapClaims{}
-	_, _, err := parser.ParseUnverified(token, claims)
-	if err != nil {
-		return nil, err
+	//do not cache the value if the rosters have been changed while finding it
+	if atomic.LoadInt64(&auth.rostersVersion) == version {
+		auth.rosters.Put(phone, uin)
 	}
 
-	for key := range claims {
-		if key == "uiucedu_uin" {
-			tokenType := 1
-			return &tokenType, nil
-		}
-		if key == "phoneNumber" {
-			tokenType := 2
-			return &tokenType, nil
-		}
-		if key == "uid" {
-			tokenType := 3
-			return &tokenType, nil
-		}
+	if len(uin) == 0 {
+		return nil
 	}
-	return nil, errors.New("not supported token type")
+	return &uin
 }
 
 func (auth *UserAuth) createAppUser(externalID string, uuid string, publicKey string,
@@ -1146,10 +995,7 @@ func (auth *UserAuth) createAppUser(externalID string, uuid string, publicKey st
 func (auth *UserAuth) updateAppUser(user model.User, uuid string, publicKey string, consent bool, consentVaccine bool, exposureNotification bool, rePost *bool,
 	encryptedKey *string, encryptedBlob *string, encryptedPK *string) error {
 
-	//1. remove it from the cache
-	auth.deleteCacheUser(user.ExternalID)
-
-	//2. Set the new values
+	//1. Set the new values
 	user.UUID = uuid
 	user.PublicKey = publicKey
 	user.Consent = consent
@@ -1162,7 +1008,7 @@ func (auth *UserAuth) updateAppUser(user model.User, uuid string, publicKey stri
 	user.EncryptedBlob = encryptedBlob
 	user.EncryptedPK = encryptedPK
 
-	//3. Update the user
+	//2. Update the user, the application takes it out from the cached users
 	err := auth.app.UpdateUser(&user)
 	if err != nil {
 		return err
@@ -1171,90 +1017,14 @@ func (auth *UserAuth) updateAppUser(user model.User, uuid string, publicKey stri
 	return nil
 }
 
-func (auth *UserAuth) getCachedUser(externalID string) *cacheUser {
-	auth.cachedUsersLock.RLock()
-	defer auth.cachedUsersLock.RUnlock()
-
-	var cachedUser *cacheUser //to return
-
-	item, _ := auth.cachedUsers.Load(externalID)
-	if item != nil {
-		cachedUser = item.(*cacheUser)
-	}
-
-	//keep the last get time
-	if cachedUser != nil {
-		cachedUser.lastUsage = time.Now()
-		auth.cachedUsers.Store(externalID, cachedUser)
-	}
-
-	return cachedUser
-}
-
-func (auth *UserAuth) cacheUser(externalID string, user *model.User) {
-	auth.cachedUsersLock.RLock()
-
-	cacheUser := &cacheUser{user: user, lastUsage: time.Now()}
-	auth.cachedUsers.Store(externalID, cacheUser)
-
-	auth.cachedUsersLock.RUnlock()
-}
-
-func (auth *UserAuth) deleteCacheUser(externalID string) {
-	auth.cachedUsersLock.RLock()
-
-	auth.cachedUsers.Delete(externalID)
-
-	auth.cachedUsersLock.RUnlock()
-}
-
-func (auth *UserAuth) clearCacheUsers() {
-	log.Println("UserAuth -> clearCacheUsers")
-
-	auth.cachedUsersLock.RLock()
-
-	auth.cachedUsers = &syncmap.Map{}
-
-	auth.cachedUsersLock.RUnlock()
-}
-
-func (auth *UserAuth) setRosters(rosters []map[string]string) {
-	auth.rostersLock.RLock()
-
-	auth.rosters = rosters
-
-	auth.rostersLock.RUnlock()
-}
-
-func (auth *UserAuth) getRosters() []map[string]string {
-	auth.rostersLock.RLock()
-	defer auth.rostersLock.RUnlock()
-
-	return auth.rosters
-}
-
 func (auth *UserAuth) getUser(externalID string) (*model.User, error) {
-	var err error
-
-	//1. First check if cached
-	cachedUser := auth.getCachedUser(externalID)
-	if cachedUser != nil {
-		return cachedUser.user, nil
-	}
-
-	//2. Check if we have a such user in the application
+	//the application caches the recently used users
 	user, err := auth.app.FindUserByExternalID(externalID)
 	if err != nil {
 		log.Printf("error finding an user for external id - %s\n", err)
 		return nil, err
 	}
-	if user != nil {
-		//cache it
-		auth.cacheUser(externalID, user)
-		return user, nil
-	}
-	//there is no a such user
-	return nil, nil
+	return user, nil
 }
 
 func (auth *UserAuth) responseBadRequest(w http.ResponseWriter) {
@@ -1271,6 +1041,16 @@ func (auth *UserAuth) responseUnauthorized(logInfo string, w http.ResponseWriter
 	w.Write([]byte("Unauthorized"))
 }
 
+func (auth *UserAuth) responseUpgradeRequired(tokenType string, w http.ResponseWriter) {
+	log.Printf("426 - Upgrade Required - %s token type", tokenType)
+
+	data, _ := json.Marshal(upgradeRequiredResponse{Code: "upgrade-required", TokenType: tokenType, Sunset: auth.tokenValidators.deprecatedSunset,
+		Message: fmt.Sprintf("%s tokens are not supported anymore, upgrade the app", tokenType)})
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusUpgradeRequired)
+	w.Write(data)
+}
+
 func (auth *UserAuth) responseInternalServerError(w http.ResponseWriter) {
 	log.Println(fmt.Sprintf("500 - Internal Server Error"))
 
@@ -1286,7 +1066,8 @@ func (auth *UserAuth) responseForbbiden(info string, w http.ResponseWriter) {
 }
 
 func newUserAuth(app *core.Application, oidcProvider string, oidcAppClientID string,
-	phoneAuthSecret string, keys string, issuer string) *UserAuth {
+	phoneAuthSecret string, keys string, keysURL string, issuer string, audience string, clockSkew string,
+	tokensAppVersions string, deprecatedTokensSunset string) *UserAuth {
 
 	provider, err := oidc.NewProvider(context.Background(), oidcProvider)
 	if err != nil {
@@ -1294,18 +1075,33 @@ func newUserAuth(app *core.Application, oidcProvider string, oidcAppClientID str
 	}
 	appIDTokenVerifier := provider.Verifier(&oidc.Config{ClientID: oidcAppClientID})
 
-	keysSet, err := jwk.ParseString(keys)
-	if err != nil {
-		log.Fatalln(err)
+	keysCache := newJWKSCache(keysURL, keys)
+
+	clockSkewValue := authDefaultClockSkew
+	if len(clockSkew) > 0 {
+		seconds, err := strconv.Atoi(clockSkew)
+		if err != nil || seconds < 0 {
+			log.Fatalf("bad auth clock skew - %s", clockSkew)
+		}
+		clockSkewValue = time.Duration(seconds) * time.Second
 	}
 
-	cacheUsers := &syncmap.Map{}
-	lock := &sync.RWMutex{}
+	cacheRosters := utils.NewLRUCache(rostersCacheSize)
 
-	cacheRosters := []map[string]string{}
-	rostersLock := &sync.RWMutex{}
+	auth := UserAuth{app: app, keys: keysCache, Issuer: issuer, Audience: audience, clockSkew: clockSkewValue, rosters: cacheRosters}
+
+	//the validators are checked in the registration order
+	tokenValidators := &tokenValidatorChain{}
+	tokenValidators.register(&shibbolethTokenValidator{verifier: appIDTokenVerifier}, true)
+	tokenValidators.register(&phoneTokenValidator{secret: phoneAuthSecret}, true)
+	tokenValidators.register(&accessTokenValidator{auth: &auth}, false)
+	if err := tokenValidators.applyAppVersions(tokensAppVersions); err != nil {
+		log.Fatalln(err)
+	}
+	if err := tokenValidators.applyDeprecatedSunset(deprecatedTokensSunset); err != nil {
+		log.Fatalln(err)
+	}
+	auth.tokenValidators = tokenValidators
 
-	auth := UserAuth{app: app, appIDTokenVerifier: appIDTokenVerifier, phoneAuthSecret: phoneAuthSecret, Keys: keysSet, Issuer: issuer,
-		cachedUsers: cacheUsers, cachedUsersLock: lock, rosters: cacheRosters, rostersLock: rostersLock}
 	return &auth
 }
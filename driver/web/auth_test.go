@@ -0,0 +1,92 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+
+package web
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func signProviderRequest(secret string, timestamp string, method string, uri string, body string) string {
+	bodyHash := sha256.Sum256([]byte(body))
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "\n" + method + "\n" + uri + "\n" + hex.EncodeToString(bodyHash[:])))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestCheckSignature(t *testing.T) {
+	secret := "secret"
+	body := `{"uin":"123456789"}`
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	old := strconv.FormatInt(time.Now().Add(-2*providersSignatureMaxAge).Unix(), 10)
+	future := strconv.FormatInt(time.Now().Add(2*providersSignatureMaxAge).Unix(), 10)
+
+	tests := []struct {
+		name      string
+		body      string
+		timestamp string
+		signature string
+		wantErr   bool
+	}{
+		{name: "valid", body: body, timestamp: now, signature: signProviderRequest(secret, now, "POST", "/health/ext/ctest?x=1", body)},
+		{name: "valid upper case hex", body: body, timestamp: now,
+			signature: strings.ToUpper(signProviderRequest(secret, now, "POST", "/health/ext/ctest?x=1", body))},
+		{name: "no signature", body: body, timestamp: now, signature: "", wantErr: true},
+		{name: "no timestamp", body: body, timestamp: "", signature: signProviderRequest(secret, now, "POST", "/health/ext/ctest?x=1", body), wantErr: true},
+		{name: "bad timestamp", body: body, timestamp: "yesterday", signature: signProviderRequest(secret, "yesterday", "POST", "/health/ext/ctest?x=1", body), wantErr: true},
+		{name: "old timestamp", body: body, timestamp: old, signature: signProviderRequest(secret, old, "POST", "/health/ext/ctest?x=1", body), wantErr: true},
+		{name: "future timestamp", body: body, timestamp: future, signature: signProviderRequest(secret, future, "POST", "/health/ext/ctest?x=1", body), wantErr: true},
+		{name: "other secret", body: body, timestamp: now, signature: signProviderRequest("other", now, "POST", "/health/ext/ctest?x=1", body), wantErr: true},
+		{name: "other body", body: `{"uin":"987654321"}`, timestamp: now, signature: signProviderRequest(secret, now, "POST", "/health/ext/ctest?x=1", body), wantErr: true},
+		{name: "other method", body: body, timestamp: now, signature: signProviderRequest(secret, now, "PUT", "/health/ext/ctest?x=1", body), wantErr: true},
+		{name: "other query", body: body, timestamp: now, signature: signProviderRequest(secret, now, "POST", "/health/ext/ctest?x=2", body), wantErr: true},
+		{name: "not hex signature", body: body, timestamp: now, signature: "zz", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", "/health/ext/ctest?x=1", strings.NewReader(tt.body))
+			if len(tt.signature) > 0 {
+				r.Header.Set("ROKWIRE-HS-SIGNATURE", tt.signature)
+			}
+			if len(tt.timestamp) > 0 {
+				r.Header.Set("ROKWIRE-HS-TIMESTAMP", tt.timestamp)
+			}
+
+			auth := ProvidersAuth{}
+			err := auth.checkSignature(r, secret)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("checkSignature() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			//the body is given back to the handler
+			if !tt.wantErr {
+				data, _ := ioutil.ReadAll(r.Body)
+				if string(data) != tt.body {
+					t.Errorf("checkSignature() body = %s, expected %s", data, tt.body)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+
+package web
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/lestrrat-go/jwx/jwk"
+)
+
+const (
+	//how often the keys are refreshed when there are no unknown kids
+	jwksRefreshInterval = time.Hour
+	//the min period between two refreshes caused by unknown kids
+	jwksUnknownKIDInterval = 30 * time.Second
+	//the failed refreshes are retried after 5s, 10s, 20s.. up to this
+	jwksMaxBackoff   = 10 * time.Minute
+	jwksFetchTimeout = 10 * time.Second
+)
+
+//jwksCache keeps the auth service keys fetched from its JWKS url. The static keys are used when there is no url or the keys have not been fetched yet
+type jwksCache struct {
+	url        string
+	staticKeys *jwk.Set
+	client     *http.Client
+
+	keys *jwk.Set
+	lock *sync.RWMutex
+
+	//only one refresh at a time, the failed ones are delayed with backoff
+	refreshLock *sync.Mutex
+	failures    int
+	nextAttempt time.Time
+	lastAttempt time.Time
+}
+
+func (c *jwksCache) start() {
+	if len(c.url) == 0 {
+		return
+	}
+
+	err := c.refresh(false)
+	if err != nil {
+		log.Printf("error fetching the auth keys on start - %s\n", err)
+	}
+	go c.refreshTimer()
+}
+
+func (c *jwksCache) refreshTimer() {
+	ticker := time.NewTicker(jwksRefreshInterval)
+	for range ticker.C {
+		err := c.refresh(false)
+		if err != nil {
+			log.Printf("error refreshing the auth keys - %s\n", err)
+		}
+	}
+}
+
+//lookup gives the key for the kid, the keys are refreshed once if the kid is unknown
+func (c *jwksCache) lookup(kid string) (jwk.Key, error) {
+	key, err := c.find(kid)
+	if key != nil || err != nil {
+		return key, err
+	}
+	if len(c.url) == 0 {
+		return nil, errors.New("no matching kid found")
+	}
+
+	//the auth service could have rotated its keys
+	log.Printf("jwksCache -> lookup -> unknown kid %s, refreshing the keys", kid)
+	err = c.refresh(true)
+	if err != nil {
+		log.Printf("error refreshing the auth keys for unknown kid - %s\n", err)
+	}
+	key, err = c.find(kid)
+	if err != nil {
+		return nil, err
+	}
+	if key == nil {
+		return nil, errors.New("no matching kid found")
+	}
+	return key, nil
+}
+
+func (c *jwksCache) find(kid string) (jwk.Key, error) {
+	c.lock.RLock()
+	keys := c.keys
+	c.lock.RUnlock()
+
+	for _, set := range []*jwk.Set{keys, c.staticKeys} {
+		if set == nil {
+			continue
+		}
+		jwkMatch := set.LookupKeyID(kid)
+		if len(jwkMatch) > 1 {
+			return nil, errors.New("multiple matching kids found")
+		}
+		if len(jwkMatch) == 1 {
+			return jwkMatch[0], nil
+		}
+	}
+	return nil, nil
+}
+
+//refresh fetches the keys. The refreshes for unknown kids are limited as they could be caused by bad tokens
+func (c *jwksCache) refresh(unknownKID bool) error {
+	c.refreshLock.Lock()
+	defer c.refreshLock.Unlock()
+
+	now := time.Now()
+	if now.Before(c.nextAttempt) {
+		return errors.New("the keys refresh is delayed after a failure")
+	}
+	if unknownKID && now.Sub(c.lastAttempt) < jwksUnknownKIDInterval {
+		return nil
+	}
+	c.lastAttempt = now
+
+	ctx, cancel := context.WithTimeout(context.Background(), jwksFetchTimeout)
+	defer cancel()
+	keys, err := jwk.FetchHTTPWithContext(ctx, c.url, jwk.WithHTTPClient(c.client))
+	if err != nil {
+		c.failures++
+		backoff := 5 * time.Second << uint(c.failures-1)
+		if backoff > jwksMaxBackoff || backoff <= 0 {
+			backoff = jwksMaxBackoff
+		}
+		c.nextAttempt = now.Add(backoff)
+		return err
+	}
+	c.failures = 0
+	c.nextAttempt = time.Time{}
+
+	c.lock.Lock()
+	c.keys = keys
+	c.lock.Unlock()
+
+	log.Printf("jwksCache -> refresh -> %d keys fetched", keys.Len())
+	return nil
+}
+
+func newJWKSCache(url string, staticKeys string) *jwksCache {
+	var staticKeysSet *jwk.Set
+	if len(staticKeys) > 0 {
+		keysSet, err := jwk.ParseString(staticKeys)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		staticKeysSet = keysSet
+	}
+	if len(url) == 0 && staticKeysSet == nil {
+		log.Fatalln("the auth keys or the auth keys url must be provided")
+	}
+
+	client := &http.Client{Timeout: jwksFetchTimeout}
+	return &jwksCache{url: url, staticKeys: staticKeysSet, client: client, lock: &sync.RWMutex{}, refreshLock: &sync.Mutex{}}
+}
@@ -18,20 +18,28 @@
 package rest
 
 import (
+	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"health/core"
 	"health/core/model"
 	"health/utils"
 	"io/ioutil"
 	"log"
 	"net/http"
+	"path"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gorilla/mux"
 	"gopkg.in/go-playground/validator.v9"
 )
 
+const (
+	maxRosterFileSize = 10 << 20 //10 MB
+)
+
 //AdminApisHandler handles the admin rest APIs implementation
 type AdminApisHandler struct {
 	app *core.Application
@@ -921,9 +929,18 @@ func (h AdminApisHandler) DeleteFAQSection(current model.User, group string, w h
 
 //GetProviders gets the providers
 // @Description Gives the providers list
+// @Description The items can be filtered by the listed fields as field=value or field=operator:value where the operator is eq, ne, in, range, prefix or exists.
+// @Description The items are given in pages if "limit" or "cursor" is provided. The next page cursor is given in the ROKWIRE-CONTINUATION-TOKEN header.
 // @Tags Admin
 // @ID getProviders
 // @Accept  json
+// @Param id query string false "ID"
+// @Param provider_name query string false "Provider name"
+// @Param manual_test query string false "Manual test"
+// @Param date_created query string false "Date created"
+// @Param sort query string false "Sort fields - field1,-field2"
+// @Param limit query string false "Limit"
+// @Param cursor query string false "Cursor"
 // @Success 200 {array} providerResponse
 // @Failure 400 {object} string "Authentication error"
 // @Failure 404 {object} string "Not Found"
@@ -932,7 +949,14 @@ func (h AdminApisHandler) DeleteFAQSection(current model.User, group string, w h
 // @Security AdminGroupAuth
 // @Router /admin/providers [get]
 func (h AdminApisHandler) GetProviders(current model.User, group string, w http.ResponseWriter, r *http.Request) {
-	providers, err := h.app.Administration.GetProviders()
+	query, err := utils.ParseQuery(r.URL.Query(), providersQuerySpec)
+	if err != nil {
+		log.Printf("Error on parsing the query - %s\n", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	providers, cursor, err := h.app.Administration.QueryProviders(query)
 	if err != nil {
 		log.Println("Error on getting the providers items")
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
@@ -952,6 +976,7 @@ func (h AdminApisHandler) GetProviders(current model.User, group string, w http.
 		return
 	}
 
+	setQueryCursor(w, cursor)
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.WriteHeader(http.StatusOK)
 	w.Write(data)
@@ -1137,6 +1162,231 @@ func (h AdminApisHandler) DeleteProvider(current model.User, group string, w htt
 	w.Write([]byte("Successfully deleted"))
 }
 
+//GetProviderCredentials gives the credentials of a provider
+// @Description Gives the credentials of a provider. The keys and the signing secrets are not given.
+// @Tags Admin
+// @ID GetProviderCredentials
+// @Accept json
+// @Param id path string true "Provider ID"
+// @Success 200 {array} model.ProviderCredential
+// @Security AdminUserAuth
+// @Security AdminGroupAuth
+// @Router /admin/providers/{id}/credentials [get]
+func (h AdminApisHandler) GetProviderCredentials(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	providerID := mux.Vars(r)["id"]
+	if len(providerID) <= 0 {
+		log.Println("Provider id is required")
+		http.Error(w, "Provider id is required", http.StatusBadRequest)
+		return
+	}
+
+	credentials, err := h.app.Administration.GetProviderCredentials(providerID)
+	if err != nil {
+		log.Printf("Error on getting the provider credentials - %s\n", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	data, err := json.Marshal(credentials)
+	if err != nil {
+		log.Println("Error on marshal the provider credentials")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+type createProviderCredentialRequest struct {
+	Audit            *string    `json:"audit"`
+	RequireSignature bool       `json:"require_signature"`
+	RateLimit        int        `json:"rate_limit" validate:"min=0"`
+	Scopes           []string   `json:"scopes"`
+	DateExpires      *time.Time `json:"date_expires"`
+} // @name createProviderCredentialRequest
+
+type providerCredentialResponse struct {
+	Credential    model.ProviderCredential `json:"credential"`
+	APIKey        string                   `json:"api_key"`
+	SigningSecret string                   `json:"signing_secret"`
+} // @name providerCredentialResponse
+
+//CreateProviderCredential creates a credential for a provider
+// @Description Creates a credential for a provider. The API key and the signing secret are given only in this response.
+// @Description The requests made with the credential are bound to the provider and they must be signed if "require_signature" is set.
+// @Description The credential can create ctests for its provider, the other providers APIs need the scopes - users.read, track.read,
+// @Description uin-overrides.read, uin-overrides.write and building-access.read.
+// @Tags Admin
+// @ID CreateProviderCredential
+// @Accept json
+// @Produce json
+// @Param data body createProviderCredentialRequest true "body data"
+// @Param id path string true "Provider ID"
+// @Success 200 {object} providerCredentialResponse
+// @Security AdminUserAuth
+// @Security AdminGroupAuth
+// @Router /admin/providers/{id}/credentials [post]
+func (h AdminApisHandler) CreateProviderCredential(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	providerID := mux.Vars(r)["id"]
+	if len(providerID) <= 0 {
+		log.Println("Provider id is required")
+		http.Error(w, "Provider id is required", http.StatusBadRequest)
+		return
+	}
+
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		log.Printf("Error on marshal create a provider credential - %s\n", err.Error())
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	var requestData createProviderCredentialRequest
+	err = json.Unmarshal(data, &requestData)
+	if err != nil {
+		log.Printf("Error on unmarshal the create provider credential request data - %s\n", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	//validate
+	validate := validator.New()
+	err = validate.Struct(requestData)
+	if err != nil {
+		log.Printf("Error on validating create provider credential data - %s\n", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	credential, apiKey, err := h.app.Administration.CreateProviderCredential(current, group, requestData.Audit, providerID,
+		requestData.RequireSignature, requestData.RateLimit, requestData.Scopes, requestData.DateExpires)
+	if err != nil {
+		log.Printf("Error on creating a provider credential - %s\n", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	h.writeProviderCredential(w, *credential, apiKey)
+}
+
+type rotateProviderCredentialRequest struct {
+	Audit            *string `json:"audit"`
+	GracePeriodHours *int    `json:"grace_period_hours"`
+} // @name rotateProviderCredentialRequest
+
+//RotateProviderCredential rotates a provider credential
+// @Description Creates a new credential with the settings of the rotated one. The rotated credential stays valid for the grace period - 24 hours if not provided.
+// @Tags Admin
+// @ID RotateProviderCredential
+// @Accept json
+// @Produce json
+// @Param data body rotateProviderCredentialRequest false "body data"
+// @Param id path string true "Credential ID"
+// @Success 200 {object} providerCredentialResponse
+// @Security AdminUserAuth
+// @Security AdminGroupAuth
+// @Router /admin/provider-credentials/{id}/rotate [put]
+func (h AdminApisHandler) RotateProviderCredential(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	ID := mux.Vars(r)["id"]
+	if len(ID) <= 0 {
+		log.Println("Credential id is required")
+		http.Error(w, "Credential id is required", http.StatusBadRequest)
+		return
+	}
+
+	var requestData rotateProviderCredentialRequest
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		log.Printf("Error on marshal rotate a provider credential - %s\n", err.Error())
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+	if len(data) > 0 {
+		err = json.Unmarshal(data, &requestData)
+		if err != nil {
+			log.Printf("Error on unmarshal the rotate provider credential request data - %s\n", err.Error())
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	credential, apiKey, err := h.app.Administration.RotateProviderCredential(current, group, requestData.Audit, ID, requestData.GracePeriodHours)
+	if err != nil {
+		log.Printf("Error on rotating a provider credential - %s\n", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	h.writeProviderCredential(w, *credential, apiKey)
+}
+
+type revokeProviderCredentialRequest struct {
+	Audit *string `json:"audit"`
+} // @name revokeProviderCredentialRequest
+
+//RevokeProviderCredential revokes a provider credential
+// @Description Revokes a provider credential. It cannot be used anymore.
+// @Tags Admin
+// @ID RevokeProviderCredential
+// @Accept json
+// @Param data body revokeProviderCredentialRequest false "body data"
+// @Param id path string true "Credential ID"
+// @Success 200 {object} string "Successfully revoked"
+// @Security AdminUserAuth
+// @Security AdminGroupAuth
+// @Router /admin/provider-credentials/{id}/revoke [put]
+func (h AdminApisHandler) RevokeProviderCredential(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	ID := mux.Vars(r)["id"]
+	if len(ID) <= 0 {
+		log.Println("Credential id is required")
+		http.Error(w, "Credential id is required", http.StatusBadRequest)
+		return
+	}
+
+	var requestData revokeProviderCredentialRequest
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		log.Printf("Error on marshal revoke a provider credential - %s\n", err.Error())
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+	if len(data) > 0 {
+		err = json.Unmarshal(data, &requestData)
+		if err != nil {
+			log.Printf("Error on unmarshal the revoke provider credential request data - %s\n", err.Error())
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	err = h.app.Administration.RevokeProviderCredential(current, group, requestData.Audit, ID)
+	if err != nil {
+		log.Printf("Error on revoking a provider credential - %s\n", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("Successfully revoked"))
+}
+
+func (h AdminApisHandler) writeProviderCredential(w http.ResponseWriter, credential model.ProviderCredential, apiKey string) {
+	response := providerCredentialResponse{Credential: credential, APIKey: apiKey, SigningSecret: credential.SigningSecret}
+	data, err := json.Marshal(response)
+	if err != nil {
+		log.Println("Error on marshal the provider credential")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
 type createCountyRequest struct {
 	Audit         *string `json:"audit"`
 	Name          string  `json:"name" validate:"required"`
@@ -1193,6 +1443,10 @@ func (h AdminApisHandler) CreateCounty(current model.User, group string, w http.
 
 	county, err := h.app.Administration.CreateCounty(current, group, audit, name, stateProvince, country)
 	if err != nil {
+		if errors.Is(err, core.ErrCountyForbidden) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
 		log.Println(err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -1274,6 +1528,10 @@ func (h AdminApisHandler) UpdateCounty(current model.User, group string, w http.
 	county, err := h.app.Administration.UpdateCounty(current, group, audit, ID, requestData.Name,
 		requestData.StateProvince, requestData.Country)
 	if err != nil {
+		if errors.Is(err, core.ErrCountyForbidden) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
 		log.Println(err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -1312,6 +1570,10 @@ func (h AdminApisHandler) DeleteCounty(current model.User, group string, w http.
 	}
 	err := h.app.Administration.DeleteCounty(current, group, ID)
 	if err != nil {
+		if errors.Is(err, core.ErrCountyForbidden) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
 		log.Println(err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -1331,9 +1593,19 @@ type getCountiesResponse struct {
 
 //GetCounties gets the counties
 // @Description Gives the counties list
+// @Description The items can be filtered by the listed fields as field=value or field=operator:value where the operator is eq, ne, in, range, prefix or exists.
+// @Description The items are given in pages if "limit" or "cursor" is provided. The next page cursor is given in the ROKWIRE-CONTINUATION-TOKEN header.
 // @Tags Admin
 // @ID getCounties
 // @Accept  json
+// @Param id query string false "ID"
+// @Param name query string false "Name"
+// @Param state_province query string false "State province"
+// @Param country query string false "Country"
+// @Param date_created query string false "Date created"
+// @Param sort query string false "Sort fields - field1,-field2"
+// @Param limit query string false "Limit"
+// @Param cursor query string false "Cursor"
 // @Success 200 {array} getCountiesResponse
 // @Failure 400 {object} string "Authentication error"
 // @Failure 404 {object} string "Not Found"
@@ -1342,7 +1614,14 @@ type getCountiesResponse struct {
 // @Security AdminGroupAuth
 // @Router /admin/counties [get]
 func (h AdminApisHandler) GetCounties(current model.User, group string, w http.ResponseWriter, r *http.Request) {
-	counties, err := h.app.Administration.FindCounties(nil)
+	query, err := utils.ParseQuery(r.URL.Query(), countiesQuerySpec)
+	if err != nil {
+		log.Printf("Error on parsing the query - %s\n", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	counties, cursor, err := h.app.Administration.QueryCounties(group, query)
 	if err != nil {
 		log.Println(err.Error())
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
@@ -1363,6 +1642,7 @@ func (h AdminApisHandler) GetCounties(current model.User, group string, w http.R
 		return
 	}
 
+	setQueryCursor(w, cursor)
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.WriteHeader(http.StatusOK)
 	w.Write(data)
@@ -1405,6 +1685,7 @@ type createGuidelineItemsResponse struct {
 // @Success 200 {object} createGuidelineResponse
 // @Security AdminUserAuth
 // @Security AdminGroupAuth
+// @Failure 403 {object} string "The county is out of the admin counties"
 // @Router /admin/guidelines [post]
 func (h AdminApisHandler) CreateGuideline(current model.User, group string, w http.ResponseWriter, r *http.Request) {
 	data, err := ioutil.ReadAll(r.Body)
@@ -1446,6 +1727,10 @@ func (h AdminApisHandler) CreateGuideline(current model.User, group string, w ht
 
 	guideline, err := h.app.Administration.CreateGuideline(current, group, audit, countyID, name, description, items)
 	if err != nil {
+		if errors.Is(err, core.ErrCountyForbidden) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
 		log.Println(err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -1506,6 +1791,7 @@ type updateGuidelineItemsResponse struct {
 // @Success 200 {object} updateGuidelineResponse
 // @Security AdminUserAuth
 // @Security AdminGroupAuth
+// @Failure 403 {object} string "The county is out of the admin counties"
 // @Router /admin/guidelines/{id} [put]
 func (h AdminApisHandler) UpdateGuideline(current model.User, group string, w http.ResponseWriter, r *http.Request) {
 	params := mux.Vars(r)
@@ -1554,6 +1840,10 @@ func (h AdminApisHandler) UpdateGuideline(current model.User, group string, w ht
 
 	guideline, err := h.app.Administration.UpdateGuideline(current, group, audit, ID, name, description, items)
 	if err != nil {
+		if errors.Is(err, core.ErrCountyForbidden) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
 		log.Println(err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -1586,6 +1876,7 @@ func (h AdminApisHandler) UpdateGuideline(current model.User, group string, w ht
 // @Success 200 {object} string "Successfuly deleted"
 // @Security AdminUserAuth
 // @Security AdminGroupAuth
+// @Failure 403 {object} string "The county is out of the admin counties"
 // @Router /admin/guidelines/{id} [delete]
 func (h AdminApisHandler) DeleteGuideline(current model.User, group string, w http.ResponseWriter, r *http.Request) {
 	params := mux.Vars(r)
@@ -1597,6 +1888,10 @@ func (h AdminApisHandler) DeleteGuideline(current model.User, group string, w ht
 	}
 	err := h.app.Administration.DeleteGuideline(current, group, ID)
 	if err != nil {
+		if errors.Is(err, core.ErrCountyForbidden) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
 		log.Println(err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -1619,6 +1914,7 @@ func (h AdminApisHandler) DeleteGuideline(current model.User, group string, w ht
 // @Failure 500 {object} string "Internal Server error"
 // @Security AdminUserAuth
 // @Security AdminGroupAuth
+// @Failure 403 {object} string "The county is out of the admin counties"
 // @Router /admin/guidelines [get]
 func (h AdminApisHandler) GetGuidelinesByCountyID(current model.User, group string, w http.ResponseWriter, r *http.Request) {
 	keys, ok := r.URL.Query()["county-id"]
@@ -1628,8 +1924,12 @@ func (h AdminApisHandler) GetGuidelinesByCountyID(current model.User, group stri
 	}
 	countyID := keys[0]
 
-	guidelines, err := h.app.Administration.GetGuidelinesByCountyID(countyID)
+	guidelines, err := h.app.Administration.GetGuidelinesByCountyID(group, countyID)
 	if err != nil {
+		if errors.Is(err, core.ErrCountyForbidden) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
 		log.Println(err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -1682,6 +1982,7 @@ type createCountyStatusResponse struct {
 // @Success 200 {object} createCountyStatusResponse
 // @Security AdminUserAuth
 // @Security AdminGroupAuth
+// @Failure 403 {object} string "The county is out of the admin counties"
 // @Router /admin/county-statuses [post]
 func (h AdminApisHandler) CreateCountyStatus(current model.User, group string, w http.ResponseWriter, r *http.Request) {
 	data, err := ioutil.ReadAll(r.Body)
@@ -1715,6 +2016,10 @@ func (h AdminApisHandler) CreateCountyStatus(current model.User, group string, w
 
 	countyStatus, err := h.app.Administration.CreateCountyStatus(current, group, audit, countyID, name, description)
 	if err != nil {
+		if errors.Is(err, core.ErrCountyForbidden) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
 		log.Println(err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -1755,6 +2060,7 @@ type updateCountyStatusResponse struct {
 // @Success 200 {object} updateCountyStatusResponse
 // @Security AdminUserAuth
 // @Security AdminGroupAuth
+// @Failure 403 {object} string "The county is out of the admin counties"
 // @Router /admin/county-statuses/{id} [put]
 func (h AdminApisHandler) UpdateCountyStatus(current model.User, group string, w http.ResponseWriter, r *http.Request) {
 	params := mux.Vars(r)
@@ -1795,6 +2101,10 @@ func (h AdminApisHandler) UpdateCountyStatus(current model.User, group string, w
 
 	countyStatus, err := h.app.Administration.UpdateCountyStatus(current, group, audit, ID, name, description)
 	if err != nil {
+		if errors.Is(err, core.ErrCountyForbidden) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
 		log.Println(err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -1822,6 +2132,7 @@ func (h AdminApisHandler) UpdateCountyStatus(current model.User, group string, w
 // @Success 200 {object} string "Successfuly deleted"
 // @Security AdminUserAuth
 // @Security AdminGroupAuth
+// @Failure 403 {object} string "The county is out of the admin counties"
 // @Router /admin/county-statuses/{id} [delete]
 func (h AdminApisHandler) DeleteCountyStatus(current model.User, group string, w http.ResponseWriter, r *http.Request) {
 	params := mux.Vars(r)
@@ -1833,6 +2144,10 @@ func (h AdminApisHandler) DeleteCountyStatus(current model.User, group string, w
 	}
 	err := h.app.Administration.DeleteCountyStatus(current, group, ID)
 	if err != nil {
+		if errors.Is(err, core.ErrCountyForbidden) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
 		log.Println(err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -1861,6 +2176,7 @@ type getCountyStatusesResponse struct {
 // @Failure 500 {object} string "Internal Server error"
 // @Security AdminUserAuth
 // @Security AdminGroupAuth
+// @Failure 403 {object} string "The county is out of the admin counties"
 // @Router /admin/county-statuses [get]
 func (h AdminApisHandler) GetCountyStatusesByCountyID(current model.User, group string, w http.ResponseWriter, r *http.Request) {
 	keys, ok := r.URL.Query()["county-id"]
@@ -1870,8 +2186,12 @@ func (h AdminApisHandler) GetCountyStatusesByCountyID(current model.User, group
 	}
 	countyID := keys[0]
 
-	countyStatuses, err := h.app.Administration.GetCountyStatusByCountyID(countyID)
+	countyStatuses, err := h.app.Administration.GetCountyStatusByCountyID(group, countyID)
 	if err != nil {
+		if errors.Is(err, core.ErrCountyForbidden) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
 		log.Println(err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -2389,6 +2709,7 @@ type createRuleResultsStatusesTypeResponse struct {
 // @Success 200 {object} createRuleResponse
 // @Security AdminUserAuth
 // @Security AdminGroupAuth
+// @Failure 403 {object} string "The county is out of the admin counties"
 // @Router /admin/rules [post]
 func (h AdminApisHandler) CreateRule(current model.User, group string, w http.ResponseWriter, r *http.Request) {
 	data, err := ioutil.ReadAll(r.Body)
@@ -2431,6 +2752,10 @@ func (h AdminApisHandler) CreateRule(current model.User, group string, w http.Re
 
 	rule, err := h.app.Administration.CreateRule(current, group, audit, countyID, testTypeID, priority, rsItems)
 	if err != nil {
+		if errors.Is(err, core.ErrCountyForbidden) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
 		log.Println(err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -2495,6 +2820,7 @@ type updateRuleResultsStatusesTypeResponse struct {
 // @Success 200 {object} updateRuleResponse
 // @Security AdminUserAuth
 // @Security AdminGroupAuth
+// @Failure 403 {object} string "The county is out of the admin counties"
 // @Router /admin/rules/{id} [put]
 func (h AdminApisHandler) UpdateRule(current model.User, group string, w http.ResponseWriter, r *http.Request) {
 	params := mux.Vars(r)
@@ -2543,6 +2869,10 @@ func (h AdminApisHandler) UpdateRule(current model.User, group string, w http.Re
 
 	rule, err := h.app.Administration.UpdateRule(current, group, audit, ID, priority, rsItems)
 	if err != nil {
+		if errors.Is(err, core.ErrCountyForbidden) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
 		log.Printf("Error on updating the rule item - %s\n", err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -2577,6 +2907,7 @@ func (h AdminApisHandler) UpdateRule(current model.User, group string, w http.Re
 // @Success 200 {object} string "Successfuly deleted"
 // @Security AdminUserAuth
 // @Security AdminGroupAuth
+// @Failure 403 {object} string "The county is out of the admin counties"
 // @Router /admin/rules/{id} [delete]
 func (h AdminApisHandler) DeleteRule(current model.User, group string, w http.ResponseWriter, r *http.Request) {
 	params := mux.Vars(r)
@@ -2588,6 +2919,10 @@ func (h AdminApisHandler) DeleteRule(current model.User, group string, w http.Re
 	}
 	err := h.app.Administration.DeleteRule(current, group, ID)
 	if err != nil {
+		if errors.Is(err, core.ErrCountyForbidden) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
 		log.Println(err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -2623,10 +2958,15 @@ type getRulesResultsStatusesTypeResponse struct {
 // @Failure 500 {object} string "Internal Server error"
 // @Security AdminUserAuth
 // @Security AdminGroupAuth
+// @Failure 403 {object} string "The county is out of the admin counties"
 // @Router /admin/rules [get]
 func (h AdminApisHandler) GetRules(current model.User, group string, w http.ResponseWriter, r *http.Request) {
-	rules, err := h.app.Administration.GetRules()
+	rules, err := h.app.Administration.GetRules(group)
 	if err != nil {
+		if errors.Is(err, core.ErrCountyForbidden) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
 		log.Println("Error on getting the rules items")
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 		return
@@ -2697,6 +3037,7 @@ type locationOperationDayRequest struct {
 // @Success 200 {object} locationResponse
 // @Security AdminUserAuth
 // @Security AdminGroupAuth
+// @Failure 403 {object} string "The county is out of the admin counties"
 // @Router /admin/locations [post]
 func (h AdminApisHandler) CreateLocation(current model.User, group string, w http.ResponseWriter, r *http.Request) {
 	data, err := ioutil.ReadAll(r.Body)
@@ -2746,6 +3087,10 @@ func (h AdminApisHandler) CreateLocation(current model.User, group string, w htt
 	location, err := h.app.Administration.CreateLocation(current, group, audit, providerID, countyID, name, address1, address2, city,
 		state, zip, country, latitude, longitude, contact, daysOfOperation, url, notes, waitTimeColor, availableTests)
 	if err != nil {
+		if errors.Is(err, core.ErrCountyForbidden) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
 		log.Printf("Error on creating a location - %s\n", err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -2804,6 +3149,7 @@ type updateLocationRequest struct {
 // @Success 200 {object} locationResponse
 // @Security AdminUserAuth
 // @Security AdminGroupAuth
+// @Failure 403 {object} string "The county is out of the admin counties"
 // @Router /admin/locations/{id} [put]
 func (h AdminApisHandler) UpdateLocation(current model.User, group string, w http.ResponseWriter, r *http.Request) {
 	params := mux.Vars(r)
@@ -2858,6 +3204,10 @@ func (h AdminApisHandler) UpdateLocation(current model.User, group string, w htt
 	location, err := h.app.Administration.UpdateLocation(current, group, audit, ID, name, address1, address2, city,
 		state, zip, country, latitude, longitude, contact, daysOfOperation, url, notes, waitTimeColor, availableTests)
 	if err != nil {
+		if errors.Is(err, core.ErrCountyForbidden) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
 		log.Printf("Error on creating a location - %s\n", err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -2887,19 +3237,45 @@ func (h AdminApisHandler) UpdateLocation(current model.User, group string, w htt
 
 //GetLocations gets the locations
 // @Description Gives the locations list
+// @Description The items can be filtered by the listed fields as field=value or field=operator:value where the operator is eq, ne, in, range, prefix or exists.
+// @Description The items are given in pages if "limit" or "cursor" is provided. The next page cursor is given in the ROKWIRE-CONTINUATION-TOKEN header.
 // @Tags Admin
 // @ID getLocations
 // @Accept  json
+// @Param id query string false "ID"
+// @Param name query string false "Name"
+// @Param city query string false "City"
+// @Param state query string false "State"
+// @Param zip query string false "ZIP"
+// @Param country query string false "Country"
+// @Param provider_id query string false "Provider ID"
+// @Param county_id query string false "County ID"
+// @Param date_created query string false "Date created"
+// @Param sort query string false "Sort fields - field1,-field2"
+// @Param limit query string false "Limit"
+// @Param cursor query string false "Cursor"
 // @Success 200 {array} locationResponse
 // @Failure 400 {object} string "Authentication error"
 // @Failure 404 {object} string "Not Found"
 // @Failure 500 {object} string "Internal Server error"
 // @Security AdminUserAuth
 // @Security AdminGroupAuth
+// @Failure 403 {object} string "The county is out of the admin counties"
 // @Router /admin/locations [get]
 func (h AdminApisHandler) GetLocations(current model.User, group string, w http.ResponseWriter, r *http.Request) {
-	locations, err := h.app.Administration.GetLocations()
+	query, err := utils.ParseQuery(r.URL.Query(), locationsQuerySpec)
+	if err != nil {
+		log.Printf("Error on parsing the query - %s\n", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	locations, cursor, err := h.app.Administration.QueryLocations(group, query)
 	if err != nil {
+		if errors.Is(err, core.ErrCountyForbidden) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
 		log.Println("Error on getting the lcoations items")
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 		return
@@ -2928,6 +3304,7 @@ func (h AdminApisHandler) GetLocations(current model.User, group string, w http.
 		return
 	}
 
+	setQueryCursor(w, cursor)
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.WriteHeader(http.StatusOK)
 	w.Write(data)
@@ -2942,6 +3319,7 @@ func (h AdminApisHandler) GetLocations(current model.User, group string, w http.
 // @Success 200 {object} string "Successfuly deleted"
 // @Security AdminUserAuth
 // @Security AdminGroupAuth
+// @Failure 403 {object} string "The county is out of the admin counties"
 // @Router /admin/locations/{id} [delete]
 func (h AdminApisHandler) DeleteLocation(current model.User, group string, w http.ResponseWriter, r *http.Request) {
 	params := mux.Vars(r)
@@ -2953,6 +3331,10 @@ func (h AdminApisHandler) DeleteLocation(current model.User, group string, w htt
 	}
 	err := h.app.Administration.DeleteLocation(current, group, ID)
 	if err != nil {
+		if errors.Is(err, core.ErrCountyForbidden) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
 		log.Println(err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -3225,6 +3607,7 @@ type symptomRuleItemResponse struct {
 // @Success 200 {object} symptomRuleResponse
 // @Security AdminUserAuth
 // @Security AdminGroupAuth
+// @Failure 403 {object} string "The county is out of the admin counties"
 // @Router /admin/symptom-rules [post]
 func (h AdminApisHandler) CreateSymptomRule(current model.User, group string, w http.ResponseWriter, r *http.Request) {
 	data, err := ioutil.ReadAll(r.Body)
@@ -3267,6 +3650,10 @@ func (h AdminApisHandler) CreateSymptomRule(current model.User, group string, w
 
 	symptomRule, err := h.app.Administration.CreateSymptomRule(current, group, countyID, gr1Count, gr2Count, rsItems)
 	if err != nil {
+		if errors.Is(err, core.ErrCountyForbidden) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
 		log.Println(err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -3322,6 +3709,7 @@ type updateSymptomRuleItemRequest struct {
 // @Success 200 {object} symptomRuleResponse
 // @Security AdminUserAuth
 // @Security AdminGroupAuth
+// @Failure 403 {object} string "The county is out of the admin counties"
 // @Router /admin/symptom-rules/{id} [put]
 func (h AdminApisHandler) UpdateSymptomRule(current model.User, group string, w http.ResponseWriter, r *http.Request) {
 	params := mux.Vars(r)
@@ -3372,6 +3760,10 @@ func (h AdminApisHandler) UpdateSymptomRule(current model.User, group string, w
 
 	symptomRule, err := h.app.Administration.UpdateSymptomRule(current, group, ID, countyID, gr1Count, gr2Count, rsItems)
 	if err != nil {
+		if errors.Is(err, core.ErrCountyForbidden) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
 		log.Println(err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -3411,10 +3803,15 @@ func (h AdminApisHandler) UpdateSymptomRule(current model.User, group string, w
 // @Failure 500 {object} string "Internal Server error"
 // @Security AdminUserAuth
 // @Security AdminGroupAuth
+// @Failure 403 {object} string "The county is out of the admin counties"
 // @Router /admin/symptom-rules [get]
 func (h AdminApisHandler) GetSymptomRules(current model.User, group string, w http.ResponseWriter, r *http.Request) {
-	symptomRules, err := h.app.Administration.GetSymptomRules()
+	symptomRules, err := h.app.Administration.GetSymptomRules(group)
 	if err != nil {
+		if errors.Is(err, core.ErrCountyForbidden) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
 		log.Println("Error on getting the rules items")
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 		return
@@ -3460,6 +3857,7 @@ func (h AdminApisHandler) GetSymptomRules(current model.User, group string, w ht
 // @Success 200 {object} string "Successfuly deleted"
 // @Security AdminUserAuth
 // @Security AdminGroupAuth
+// @Failure 403 {object} string "The county is out of the admin counties"
 // @Router /admin/symptom-rules/{id} [delete]
 func (h AdminApisHandler) DeleteSymptomRule(current model.User, group string, w http.ResponseWriter, r *http.Request) {
 	params := mux.Vars(r)
@@ -3471,6 +3869,10 @@ func (h AdminApisHandler) DeleteSymptomRule(current model.User, group string, w
 	}
 	err := h.app.Administration.DeleteSymptomRule(current, group, ID)
 	if err != nil {
+		if errors.Is(err, core.ErrCountyForbidden) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
 		log.Println(err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -3483,15 +3885,27 @@ func (h AdminApisHandler) DeleteSymptomRule(current model.User, group string, w
 
 //GetManualTestsByCountyID gets the manual tests for a county
 // @Description Gives the manual tests for a county
+// @Description The items can be filtered by the listed fields as field=value or field=operator:value where the operator is eq, ne, in, range, prefix or exists.
+// @Description The items are given in pages if "limit" or "cursor" is provided. The next page cursor is given in the ROKWIRE-CONTINUATION-TOKEN header.
 // @Tags Admin
 // @ID getManualTestsByCountyID
 // @Accept  json
+// @Param county-id query string true "County ID or all"
+// @Param id query string false "ID"
+// @Param location_id query string false "Location ID"
+// @Param status query string false "Status"
+// @Param date query string false "Date"
+// @Param date_created query string false "Date created"
+// @Param sort query string false "Sort fields - field1,-field2"
+// @Param limit query string false "Limit"
+// @Param cursor query string false "Cursor"
 // @Success 200 {array} eManualTestResponse
 // @Failure 400 {object} string "Authentication error"
 // @Failure 404 {object} string "Not Found"
 // @Failure 500 {object} string "Internal Server error"
 // @Security AdminUserAuth
 // @Security AdminGroupAuth
+// @Failure 403 {object} string "The county is out of the admin counties"
 // @Router /admin/manual-tests [get]
 func (h AdminApisHandler) GetManualTestsByCountyID(current model.User, group string, w http.ResponseWriter, r *http.Request) {
 	//county id
@@ -3502,15 +3916,21 @@ func (h AdminApisHandler) GetManualTestsByCountyID(current model.User, group str
 	}
 	countyID := countyIDKeys[0]
 
-	//status
-	var status *string
-	statusKeys, ok := r.URL.Query()["status"]
-	if ok && len(statusKeys[0]) > 0 {
-		status = &statusKeys[0]
+	values := r.URL.Query()
+	values.Del("county-id")
+	query, err := utils.ParseQuery(values, manualTestsQuerySpec)
+	if err != nil {
+		log.Printf("Error on parsing the query - %s\n", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
 
-	manualTests, err := h.app.Administration.GetManualTestByCountyID(countyID, status)
+	manualTests, cursor, err := h.app.Administration.QueryManualTests(group, countyID, query)
 	if err != nil {
+		if errors.Is(err, core.ErrCountyForbidden) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
 		log.Println(err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -3549,6 +3969,7 @@ func (h AdminApisHandler) GetManualTestsByCountyID(current model.User, group str
 		return
 	}
 
+	setQueryCursor(w, cursor)
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.WriteHeader(http.StatusOK)
 	w.Write(data)
@@ -3572,6 +3993,7 @@ type processManualTestRequest struct {
 // @Success 200 {string} Successfully processed
 // @Security AdminUserAuth
 // @Security AdminGroupAuth
+// @Failure 403 {object} string "The county is out of the admin counties"
 // @Router /admin/manual-tests/{id}/process [put]
 func (h AdminApisHandler) ProcessManualTest(current model.User, group string, w http.ResponseWriter, r *http.Request) {
 	params := mux.Vars(r)
@@ -3613,8 +4035,12 @@ func (h AdminApisHandler) ProcessManualTest(current model.User, group string, w
 		return
 	}
 
-	err = h.app.Administration.ProcessManualTest(ID, status, encryptedKey, encryptedBlob, date)
+	err = h.app.Administration.ProcessManualTest(group, ID, status, encryptedKey, encryptedBlob, date)
 	if err != nil {
+		if errors.Is(err, core.ErrCountyForbidden) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
 		log.Println(err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -3637,6 +4063,7 @@ func (h AdminApisHandler) ProcessManualTest(current model.User, group string, w
 // @Failure 500 {object} string "Internal Server error"
 // @Security AdminUserAuth
 // @Security AdminGroupAuth
+// @Failure 403 {object} string "The county is out of the admin counties"
 // @Router /admin/manual-tests/{id}/image [get]
 func (h AdminApisHandler) GetManualTestImage(current model.User, group string, w http.ResponseWriter, r *http.Request) {
 	params := mux.Vars(r)
@@ -3647,8 +4074,12 @@ func (h AdminApisHandler) GetManualTestImage(current model.User, group string, w
 		return
 	}
 
-	encryptedImageKey, encryptedImageBlob, err := h.app.Administration.GetManualTestImage(ID)
+	encryptedImageKey, encryptedImageBlob, err := h.app.Administration.GetManualTestImage(group, ID)
 	if err != nil {
+		if errors.Is(err, core.ErrCountyForbidden) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
 		log.Printf("Error on getting the manual test image - %s", err)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -3705,6 +4136,7 @@ type accessRuleItemResponse struct {
 // @Success 200 {object} accessRuleResponse
 // @Security AdminUserAuth
 // @Security AdminGroupAuth
+// @Failure 403 {object} string "The county is out of the admin counties"
 // @Router /admin/access-rules [post]
 func (h AdminApisHandler) CreateAccessRule(current model.User, group string, w http.ResponseWriter, r *http.Request) {
 	data, err := ioutil.ReadAll(r.Body)
@@ -3745,6 +4177,10 @@ func (h AdminApisHandler) CreateAccessRule(current model.User, group string, w h
 
 	accessRule, err := h.app.Administration.CreateAccessRule(current, group, audit, countyID, arRules)
 	if err != nil {
+		if errors.Is(err, core.ErrCountyForbidden) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
 		log.Println(err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -3782,10 +4218,15 @@ func (h AdminApisHandler) CreateAccessRule(current model.User, group string, w h
 // @Failure 500 {object} string "Internal Server error"
 // @Security AdminUserAuth
 // @Security AdminGroupAuth
+// @Failure 403 {object} string "The county is out of the admin counties"
 // @Router /admin/access-rules [get]
 func (h AdminApisHandler) GetAccessRules(current model.User, group string, w http.ResponseWriter, r *http.Request) {
-	accessRules, err := h.app.Administration.GetAccessRules()
+	accessRules, err := h.app.Administration.GetAccessRules(group)
 	if err != nil {
+		if errors.Is(err, core.ErrCountyForbidden) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
 		log.Println("Error on getting the access rules items")
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -3842,6 +4283,7 @@ type updateAccessRuleItemRequest struct {
 // @Success 200 {object} accessRuleResponse
 // @Security AdminUserAuth
 // @Security AdminGroupAuth
+// @Failure 403 {object} string "The county is out of the admin counties"
 // @Router /admin/access-rules/{id} [put]
 func (h AdminApisHandler) UpdateAccessRule(current model.User, group string, w http.ResponseWriter, r *http.Request) {
 	params := mux.Vars(r)
@@ -3890,6 +4332,10 @@ func (h AdminApisHandler) UpdateAccessRule(current model.User, group string, w h
 
 	accessRule, err := h.app.Administration.UpdateAccessRule(current, group, audit, ID, countyID, arRules)
 	if err != nil {
+		if errors.Is(err, core.ErrCountyForbidden) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
 		log.Println(err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -3925,6 +4371,7 @@ func (h AdminApisHandler) UpdateAccessRule(current model.User, group string, w h
 // @Success 200 {object} string "Successfuly deleted"
 // @Security AdminUserAuth
 // @Security AdminGroupAuth
+// @Failure 403 {object} string "The county is out of the admin counties"
 // @Router /admin/access-rules/{id} [delete]
 func (h AdminApisHandler) DeleteAccessRule(current model.User, group string, w http.ResponseWriter, r *http.Request) {
 	params := mux.Vars(r)
@@ -3936,6 +4383,10 @@ func (h AdminApisHandler) DeleteAccessRule(current model.User, group string, w h
 	}
 	err := h.app.Administration.DeleteAccessRule(current, group, ID)
 	if err != nil {
+		if errors.Is(err, core.ErrCountyForbidden) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
 		log.Println(err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -3956,6 +4407,7 @@ func (h AdminApisHandler) DeleteAccessRule(current model.User, group string, w h
 // @Success 200 {object} string
 // @Security AdminUserAuth
 // @Security AdminGroupAuth
+// @Failure 403 {object} string "The county is out of the admin counties"
 // @Router /admin/crules [get]
 func (h AdminApisHandler) GetCRules(current model.User, group string, w http.ResponseWriter, r *http.Request) {
 	countyKeys, ok := r.URL.Query()["county-id"]
@@ -3971,8 +4423,12 @@ func (h AdminApisHandler) GetCRules(current model.User, group string, w http.Res
 	countyID := countyKeys[0]
 	appVersion := appVersionKeys[0]
 
-	cRules, err := h.app.Administration.GetCRules(countyID, appVersion)
+	cRules, err := h.app.Administration.GetCRules(group, countyID, appVersion)
 	if err != nil {
+		if errors.Is(err, core.ErrCountyForbidden) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
 		log.Printf("Error on getting crules - %s", err)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -4007,6 +4463,7 @@ type createOrUpdateCRulesRequest struct {
 // @Success 200 {object} string
 // @Security AdminUserAuth
 // @Security AdminGroupAuth
+// @Failure 403 {object} string "The county is out of the admin counties"
 // @Router /admin/crules [put]
 func (h AdminApisHandler) CreateOrUpdateCRules(current model.User, group string, w http.ResponseWriter, r *http.Request) {
 	bodyData, err := ioutil.ReadAll(r.Body)
@@ -4040,6 +4497,10 @@ func (h AdminApisHandler) CreateOrUpdateCRules(current model.User, group string,
 
 	err = h.app.Administration.CreateOrUpdateCRules(current, group, audit, countyID, appVersion, data)
 	if err != nil {
+		if errors.Is(err, core.ErrCountyForbidden) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
 		log.Println(err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -4147,31 +4608,33 @@ func (h AdminApisHandler) CreateOrUpdateSymptoms(current model.User, group strin
 
 //GetUINOverrides gives uin override items
 // @Description Gives uin override items
+// @Description The items can be filtered by the listed fields as field=value or field=operator:value where the operator is eq, ne, in, range, prefix or exists.
+// @Description The items are given in pages if "limit" or "cursor" is provided. The next page cursor is given in the ROKWIRE-CONTINUATION-TOKEN header.
 // @Tags Admin
 // @ID GetUINOverrides
 // @Accept json
 // @Param uin query string false "UIN"
-// @Param sort query string false "Sort by uin or category"
+// @Param exempt query string false "Exempt"
+// @Param interval query string false "Interval"
+// @Param category query string false "Category"
+// @Param activation query string false "Activation"
+// @Param expiration query string false "Expiration"
+// @Param sort query string false "Sort fields - field1,-field2"
+// @Param limit query string false "Limit"
+// @Param cursor query string false "Cursor"
 // @Success 200 {array} model.UINOverride
 // @Security AdminUserAuth
 // @Security AdminGroupAuth
 // @Router /admin/uin-overrides [get]
 func (h AdminApisHandler) GetUINOverrides(current model.User, group string, w http.ResponseWriter, r *http.Request) {
-	//uin
-	var uin *string
-	uinKeys, ok := r.URL.Query()["uin"]
-	if ok && len(uinKeys[0]) > 0 {
-		uin = &uinKeys[0]
-	}
-
-	//sort by
-	var sort *string
-	sortByKeys, ok := r.URL.Query()["sort"]
-	if ok {
-		sort = &sortByKeys[0]
+	query, err := utils.ParseQuery(r.URL.Query(), uinOverridesQuerySpec)
+	if err != nil {
+		log.Printf("Error on parsing the query - %s\n", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
 
-	uinOverrides, err := h.app.Administration.GetUINOverrides(uin, sort)
+	uinOverrides, cursor, err := h.app.Administration.QueryUINOverrides(query)
 	if err != nil {
 		log.Println("Error on getting the uin overrides items")
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -4188,6 +4651,7 @@ func (h AdminApisHandler) GetUINOverrides(current model.User, group string, w ht
 		return
 	}
 
+	setQueryCursor(w, cursor)
 	w.Header().Set("Content-Type", "application/json; charset=utf-8")
 	w.WriteHeader(http.StatusOK)
 	w.Write(data)
@@ -4450,6 +4914,8 @@ type createRosterRequest struct {
 	ZipCode    string  `json:"zip_code"`
 	Email      string  `json:"email"`
 	BadgeType  string  `json:"badge_type"`
+
+	Attributes map[string]string `json:"attributes"`
 } // @name createRosterRequest
 
 //CreateRoster creates a roster
@@ -4488,27 +4954,16 @@ func (h AdminApisHandler) CreateRoster(current model.User, group string, w http.
 		return
 	}
 	audit := requestData.Audit
-	phone := requestData.Phone
-	uin := requestData.UIN
-	firstName := requestData.FirstName
-	middleName := requestData.MiddleName
-	lastName := requestData.LastName
-	birthDate := requestData.BirthDate
-	gender := requestData.Gender
-	address1 := requestData.Address1
-	address2 := requestData.Address2
-	address3 := requestData.Address3
-	city := requestData.City
-	state := requestData.State
-	zipCode := requestData.ZipCode
-	email := requestData.Email
-	badgeType := requestData.BadgeType
+	roster := model.Roster{Phone: requestData.Phone, UIN: requestData.UIN, FirstName: requestData.FirstName,
+		MiddleName: requestData.MiddleName, LastName: requestData.LastName, BirthDate: requestData.BirthDate,
+		Gender: requestData.Gender, Address1: requestData.Address1, Address2: requestData.Address2,
+		Address3: requestData.Address3, City: requestData.City, State: requestData.State, ZipCode: requestData.ZipCode,
+		Email: requestData.Email, BadgeType: requestData.BadgeType, Attributes: requestData.Attributes}
 
-	err = h.app.Administration.CreateRoster(current, group, audit, phone, uin, firstName, middleName, lastName,
-		birthDate, gender, address1, address2, address3, city, state, zipCode, email, badgeType)
+	err = h.app.Administration.CreateRoster(current, group, audit, roster)
 	if err != nil {
 		log.Printf("Error on creating a roster - %s\n", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
@@ -4517,25 +4972,36 @@ func (h AdminApisHandler) CreateRoster(current model.User, group string, w http.
 	w.Write([]byte("Successfully created"))
 }
 
+type rosterItemRequest struct {
+	Phone      string `json:"phone" validate:"required"`
+	UIN        string `json:"uin" validate:"required"`
+	FirstName  string `json:"first_name"`
+	MiddleName string `json:"middle_name"`
+	LastName   string `json:"last_name"`
+	BirthDate  string `json:"birth_date"`
+	Gender     string `json:"gender"`
+	Address1   string `json:"address1"`
+	Address2   string `json:"address2"`
+	Address3   string `json:"address3"`
+	City       string `json:"city"`
+	State      string `json:"state"`
+	ZipCode    string `json:"zip_code"`
+	Email      string `json:"email"`
+	BadgeType  string `json:"badge_type"`
+
+	Attributes map[string]string `json:"attributes"`
+} // @name rosterItemRequest
+
+func (item rosterItemRequest) toRoster() model.Roster {
+	return model.Roster{Phone: item.Phone, UIN: item.UIN, FirstName: item.FirstName, MiddleName: item.MiddleName,
+		LastName: item.LastName, BirthDate: item.BirthDate, Gender: item.Gender, Address1: item.Address1, Address2: item.Address2,
+		Address3: item.Address3, City: item.City, State: item.State, ZipCode: item.ZipCode, Email: item.Email,
+		BadgeType: item.BadgeType, Attributes: item.Attributes}
+}
+
 type createRosterItemsRequest struct {
-	Audit *string `json:"audit"`
-	Items []struct {
-		Phone      string `json:"phone" validate:"required"`
-		UIN        string `json:"uin" validate:"required"`
-		FirstName  string `json:"first_name"`
-		MiddleName string `json:"middle_name"`
-		LastName   string `json:"last_name"`
-		BirthDate  string `json:"birth_date"`
-		Gender     string `json:"gender"`
-		Address1   string `json:"address1"`
-		Address2   string `json:"address2"`
-		Address3   string `json:"address3"`
-		City       string `json:"city"`
-		State      string `json:"state"`
-		ZipCode    string `json:"zip_code"`
-		Email      string `json:"email"`
-		BadgeType  string `json:"badge_type"`
-	} `json:"items" validate:"required,min=1"`
+	Audit *string             `json:"audit"`
+	Items []rosterItemRequest `json:"items" validate:"required,min=1"`
 } // @name createRosterItemsRequest
 
 //CreateRosterItems creates many roster items
@@ -4584,18 +5050,15 @@ func (h AdminApisHandler) CreateRosterItems(current model.User, group string, w
 	items := requestData.Items
 
 	//prepare the items
-	itemsList := make([]map[string]string, len(items))
+	itemsList := make([]model.Roster, len(items))
 	for i, current := range items {
-		item := map[string]string{"phone": current.Phone, "uin": current.UIN, "first_name": current.FirstName, "middle_name": current.MiddleName,
-			"last_name": current.LastName, "birth_date": current.BirthDate, "gender": current.Gender, "address1": current.Address1, "address2": current.Address2,
-			"address3": current.Address3, "city": current.City, "state": current.State, "zip_code": current.ZipCode, "email": current.Email, "badge_type": current.BadgeType}
-		itemsList[i] = item
+		itemsList[i] = current.toRoster()
 	}
 
 	err = h.app.Administration.CreateRosterItems(current, group, audit, itemsList)
 	if err != nil {
 		log.Printf("Error on creating roster items - %s\n", err)
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
@@ -4604,38 +5067,135 @@ func (h AdminApisHandler) CreateRosterItems(current model.User, group string, w
 	w.Write([]byte("Successfully created"))
 }
 
-//GetRosters returns the roster members matching filters, sorted, and paginated
-// @Description Gives the roster members matching filters, sorted, and paginated
+type syncRostersRequest struct {
+	Audit *string             `json:"audit"`
+	Items []rosterItemRequest `json:"items" validate:"required,min=1"`
+} // @name syncRostersRequest
+
+//SyncRosters makes the roster the same as the provided items
+// @Description Makes the roster the same as the provided items - adds the new uins, updates the changed items and removes the uins which are not provided.
+// @Description All the changes are applied in one transaction. The dry run only gives the differences.
+// @Description The phone conflicts are the items which take a phone held by another roster item - the phones are swapped or moved by the sync.
 // @Tags Admin
-// @ID GetRosters
+// @ID SyncRosters
 // @Accept json
-// @Param phone query string false "Phone"
-// @Param uin query string false "UIN"
-// @Param sortBy query string false "Sort By"
-// @Param orderBy query string false "Order By"
-// @Param limit query string false "Limit"
-// @Param offset query string false "Offset"
+// @Produce json
+// @Param data body syncRostersRequest true "body data"
+// @Param dry-run query bool false "Dry run"
+// @Success 200 {object} model.RosterSync
 // @Security AdminUserAuth
 // @Security AdminGroupAuth
-// @Router /admin/rosters [get]
-func (h AdminApisHandler) GetRosters(current model.User, group string, w http.ResponseWriter, r *http.Request) {
-	sortBy := "phone"
-	sortOrder := 1
-	limit := 20
-	offset := 0
-	var filter utils.Filter
-	for key, value := range r.URL.Query() {
-		if len(value) < 1 || len(value[0]) < 1 {
-			continue
-		}
-		switch key {
-		case "sortBy":
-			sortBy = value[0]
-		case "sortOrder":
-			if value[0] == "1" {
-				sortOrder = 1
-			} else if value[0] == "-1" {
-				sortOrder = -1
+// @Router /admin/rosters/sync [put]
+func (h AdminApisHandler) SyncRosters(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		log.Printf("Error on marshal sync rosters - %s\n", err.Error())
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	var requestData syncRostersRequest
+	err = json.Unmarshal(data, &requestData)
+	if err != nil {
+		log.Printf("Error on unmarshal the sync rosters request data - %s\n", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	//validate
+	validate := validator.New()
+	err = validate.Struct(requestData)
+	if err != nil {
+		log.Printf("Error on validating sync rosters data - %s\n", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	err = validate.Var(requestData.Items, "required,dive")
+	if err != nil {
+		log.Printf("Error on validating sync rosters items - %s\n", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	dryRun := false
+	if dryRunValue := r.URL.Query().Get("dry-run"); len(dryRunValue) > 0 {
+		dryRun, err = strconv.ParseBool(dryRunValue)
+		if err != nil {
+			http.Error(w, "bad dry-run value", http.StatusBadRequest)
+			return
+		}
+	}
+
+	items := make([]model.Roster, len(requestData.Items))
+	for i, item := range requestData.Items {
+		items[i] = item.toRoster()
+	}
+
+	sync, err := h.app.Administration.SyncRosters(current, group, requestData.Audit, items, dryRun)
+	if err != nil {
+		log.Printf("Error on syncing the rosters - %s\n", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	data, err = json.Marshal(sync)
+	if err != nil {
+		log.Println("Error on marshal the rosters sync")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+//GetRosters returns the roster members matching filters, sorted, and paginated
+// @Description Gives the roster members matching filters, sorted, and paginated
+// @Description The items can be filtered by the listed fields as field=value or field=operator:value where the operator is eq, ne, in, range, prefix or exists.
+// @Description The items are given in pages if "limit" or "cursor" is provided. The next page cursor is given in the ROKWIRE-CONTINUATION-TOKEN header.
+// @Description The "offset" param keeps the old behaviour where the filters match parts of the values.
+// @Tags Admin
+// @ID GetRosters
+// @Accept json
+// @Param phone query string false "Phone"
+// @Param uin query string false "UIN"
+// @Param first_name query string false "First name"
+// @Param last_name query string false "Last name"
+// @Param email query string false "Email"
+// @Param badge_type query string false "Badge type"
+// @Param sort query string false "Sort fields - field1,-field2"
+// @Param sortBy query string false "Sort By"
+// @Param sortOrder query string false "Sort order - 1 or -1"
+// @Param limit query string false "Limit"
+// @Param cursor query string false "Cursor"
+// @Param offset query string false "Offset"
+// @Security AdminUserAuth
+// @Security AdminGroupAuth
+// @Router /admin/rosters [get]
+func (h AdminApisHandler) GetRosters(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	if _, ok := r.URL.Query()["offset"]; !ok {
+		h.queryRosters(w, r)
+		return
+	}
+
+	sortBy := "phone"
+	sortOrder := 1
+	limit := 20
+	offset := 0
+	var filter utils.Filter
+	for key, value := range r.URL.Query() {
+		if len(value) < 1 || len(value[0]) < 1 {
+			continue
+		}
+		switch key {
+		case "sortBy":
+			sortBy = value[0]
+		case "sortOrder":
+			if value[0] == "1" {
+				sortOrder = 1
+			} else if value[0] == "-1" {
+				sortOrder = -1
 			} else {
 				log.Println("Invalid 'sortOrder' value - " + value[0])
 				http.Error(w, "Invalid 'sortOrder' value - Must be 1 or -1", http.StatusBadRequest)
@@ -4684,6 +5244,179 @@ func (h AdminApisHandler) GetRosters(current model.User, group string, w http.Re
 	w.Write([]byte(data))
 }
 
+func (h AdminApisHandler) queryRosters(w http.ResponseWriter, r *http.Request) {
+	values := r.URL.Query()
+	//sortBy and sortOrder are still supported
+	if sortBy := values.Get("sortBy"); len(sortBy) > 0 {
+		if values.Get("sortOrder") == "-1" {
+			sortBy = "-" + sortBy
+		}
+		values.Set(utils.QueryParamSort, sortBy)
+	}
+	values.Del("sortBy")
+	values.Del("sortOrder")
+	query, err := utils.ParseQuery(values, rostersQuerySpec)
+	if err != nil {
+		log.Printf("Error on parsing the query - %s\n", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	roster, cursor, err := h.app.Administration.QueryRosters(query)
+	if err != nil {
+		log.Println(err.Error())
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	data, err := json.Marshal(roster)
+	if err != nil {
+		log.Println("Error on marshal roster")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	setQueryCursor(w, cursor)
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+//ImportRoster imports roster members from a csv or xlsx file
+// @Description Imports roster members from a csv or xlsx file. The first row must be a header. The columns are mapped to the roster fields
+// @Description by the "mapping" json object - {"column header":"roster field"}, the headers are used as roster fields if there is no mapping.
+// @Description The phones must be in the E.164 format and the uins must have 9 digits. The invalid rows and the rows which phones or uins
+// @Description are already in the roster are not imported. The dry run only validates the file and gives a preview of the valid items.
+// @Description The xlsx files can have up to 16384 columns(XFD) and 5000000 cells.
+// @Tags Admin
+// @ID ImportRoster
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "The csv or xlsx file"
+// @Param mapping formData string false "Columns mapping"
+// @Param audit formData string false "Audit"
+// @Param dry-run query bool false "Dry run"
+// @Success 200 {object} model.RosterImport
+// @Security AdminUserAuth
+// @Security AdminGroupAuth
+// @Router /admin/rosters/import [post]
+func (h AdminApisHandler) ImportRoster(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxRosterFileSize)
+	err := r.ParseMultipartForm(maxRosterFileSize)
+	if err != nil {
+		log.Printf("Error on parsing the roster import form - %s\n", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	file, fileHeader, err := r.FormFile("file")
+	if err != nil {
+		log.Printf("Error on reading the roster import file - %s\n", err)
+		http.Error(w, "the file is missing", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	var rows [][]string
+	switch strings.ToLower(path.Ext(fileHeader.Filename)) {
+	case ".csv":
+		rows, err = utils.ReadCSV(file)
+	case ".xlsx":
+		rows, err = utils.ReadXLSX(file, fileHeader.Size)
+	default:
+		http.Error(w, "only csv and xlsx files are supported", http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		log.Printf("Error on reading the roster import file - %s\n", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var mapping map[string]string
+	if mappingValue := r.FormValue("mapping"); len(mappingValue) > 0 {
+		err = json.Unmarshal([]byte(mappingValue), &mapping)
+		if err != nil {
+			log.Printf("Error on unmarshal the roster import mapping - %s\n", err)
+			http.Error(w, "the mapping must be a json object", http.StatusBadRequest)
+			return
+		}
+	}
+
+	var audit *string
+	if auditValue := r.FormValue("audit"); len(auditValue) > 0 {
+		audit = &auditValue
+	}
+
+	dryRun := false
+	if dryRunValue := r.URL.Query().Get("dry-run"); len(dryRunValue) > 0 {
+		dryRun, err = strconv.ParseBool(dryRunValue)
+		if err != nil {
+			http.Error(w, "bad dry-run value", http.StatusBadRequest)
+			return
+		}
+	}
+
+	report, err := h.app.Administration.ImportRoster(current, group, audit, fileHeader.Filename, rows, mapping, dryRun)
+	if err != nil {
+		log.Printf("Error on importing the roster - %s\n", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	data, err := json.Marshal(report)
+	if err != nil {
+		log.Println("Error on marshal the roster import report")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+//GetRosterImportErrors gives the errors report of a roster import
+// @Description Gives the invalid and the duplicated rows of a roster import as a csv file. The reports are kept for 30 days.
+// @Description Only the first 10000 errors are kept, the report "errors_count" has the count of all errors.
+// @Tags Admin
+// @ID GetRosterImportErrors
+// @Produce text/csv
+// @Param id path string true "Import ID"
+// @Success 200 {string} string "The errors csv"
+// @Security AdminUserAuth
+// @Security AdminGroupAuth
+// @Router /admin/rosters/imports/{id}/errors [get]
+func (h AdminApisHandler) GetRosterImportErrors(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	ID := params["id"]
+
+	report, err := h.app.Administration.GetRosterImport(ID)
+	if err != nil {
+		log.Println(err.Error())
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	if report == nil {
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", "attachment; filename=\"roster-import-errors.csv\"")
+	w.WriteHeader(http.StatusOK)
+
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"row", "field", "value", "message"})
+	for _, item := range report.Errors {
+		writer.Write([]string{strconv.Itoa(item.Row), item.Field, item.Value, item.Message})
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		log.Printf("Error on writing the roster import errors csv - %s\n", err)
+	}
+}
+
 //DeleteRosterByPhone deletes a roster by phone
 // @Description Deletes a roster by phone
 // @Tags Admin
@@ -4783,6 +5516,8 @@ type updateRosterRequest struct {
 	ZipCode    string  `json:"zip_code"`
 	Email      string  `json:"email"`
 	BadgeType  string  `json:"badge_type"`
+
+	Attributes map[string]string `json:"attributes"`
 } // @name updateRosterRequest
 
 //UpdateRoster updates a roster
@@ -4831,24 +5566,15 @@ func (h AdminApisHandler) UpdateRoster(current model.User, group string, w http.
 	}
 
 	audit := requestData.Audit
-	firstName := requestData.FirstName
-	middleName := requestData.MiddleName
-	lastName := requestData.LastName
-	birthDate := requestData.BirthDate
-	gender := requestData.Gender
-	address1 := requestData.Address1
-	address2 := requestData.Address2
-	address3 := requestData.Address3
-	city := requestData.City
-	state := requestData.State
-	zipCode := requestData.ZipCode
-	email := requestData.Email
-	badgeType := requestData.BadgeType
-	err = h.app.Administration.UpdateRoster(current, group, audit, uin, firstName, middleName, lastName, birthDate, gender,
-		address1, address2, address3, city, state, zipCode, email, badgeType)
+	roster := model.Roster{UIN: uin, FirstName: requestData.FirstName, MiddleName: requestData.MiddleName,
+		LastName: requestData.LastName, BirthDate: requestData.BirthDate, Gender: requestData.Gender,
+		Address1: requestData.Address1, Address2: requestData.Address2, Address3: requestData.Address3,
+		City: requestData.City, State: requestData.State, ZipCode: requestData.ZipCode, Email: requestData.Email,
+		BadgeType: requestData.BadgeType, Attributes: requestData.Attributes}
+	err = h.app.Administration.UpdateRoster(current, group, audit, roster)
 	if err != nil {
 		log.Printf("Error on updating roster - %s\n", err)
-		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
@@ -5121,6 +5847,57 @@ func (h AdminApisHandler) UpdateSubAccount(current model.User, group string, w h
 	w.Write([]byte("Successfully updated"))
 }
 
+type revokeSubAccountRequest struct {
+	Audit *string `json:"audit"`
+} // @name revokeSubAccountRequest
+
+//RevokeSubAccount revokes the link of an accepted sub account
+// @Description Revokes the link of an accepted sub account. The account of the primary user becomes not active.
+// @Tags Admin
+// @ID RevokeSubAccount
+// @Accept json
+// @Param data body revokeSubAccountRequest false "body data"
+// @Param uin path string true "UIN"
+// @Success 200 {object} string "Successfully revoked"
+// @Security AdminUserAuth
+// @Security AdminGroupAuth
+// @Router /admin/raw-sub-accounts/uin/{uin}/revoke [put]
+func (h AdminApisHandler) RevokeSubAccount(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	uin := mux.Vars(r)["uin"]
+	if len(uin) <= 0 {
+		log.Println("uin is required")
+		http.Error(w, "uin is required", http.StatusBadRequest)
+		return
+	}
+
+	var requestData revokeSubAccountRequest
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		log.Printf("Error on marshal revoke a sub account - %s\n", err.Error())
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+	if len(data) > 0 {
+		err = json.Unmarshal(data, &requestData)
+		if err != nil {
+			log.Printf("Error on unmarshal the revoke sub account request data - %s\n", err.Error())
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	err = h.app.Administration.RevokeRawSubAccount(current, group, requestData.Audit, uin)
+	if err != nil {
+		log.Printf("Error on revoking a sub account - %s\n", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("Successfully revoked"))
+}
+
 //DeleteSubAccountByUIN deletes a sub account by uin
 // @Description Deletes a sub account by uin
 // @Tags Admin
@@ -5180,6 +5957,7 @@ type createActionRequest struct {
 	AccountID     string  `json:"account_id" validate:"required"`
 	EncryptedKey  string  `json:"encrypted_key" validate:"required"`
 	EncryptedBlob string  `json:"encrypted_blob" validate:"required"`
+	Positive      bool    `json:"positive"` //an exposure verification code is issued for the user only for positive results
 } // @name createActionRequest
 
 //CreateAction creates an action
@@ -5223,8 +6001,9 @@ func (h ApisHandler) CreateAction(current model.User, group string, w http.Respo
 	accountID := requestData.AccountID
 	encryptedKey := requestData.EncryptedKey
 	encryptedBlob := requestData.EncryptedBlob
+	positive := requestData.Positive
 
-	item, err := h.app.Administration.CreateAction(current, group, audit, providerID, accountID, encryptedKey, encryptedBlob)
+	item, err := h.app.Administration.CreateAction(current, group, audit, providerID, accountID, encryptedKey, encryptedBlob, positive)
 	if err != nil {
 		log.Printf("Error on creating an action - %s\n", err)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -5247,117 +6026,1265 @@ func (h ApisHandler) CreateAction(current model.User, group string, w http.Respo
 }
 
 //GetAudit gets the audilt/log history
-// @Description Gives the audilt/log history
+// @Description Gives the audilt/log history. The items have the entity data before and after the change and the changed fields.
+// @Description The items can be filtered by the listed fields as field=value or field=operator:value where the operator is eq, ne, in, range, prefix or exists.
+// @Description The items are given in pages if "limit" or "cursor" is provided. The next page cursor is given in the ROKWIRE-CONTINUATION-TOKEN header.
+// @Description The old user-identifier, entity-id, client-data, created-at and asc params are still supported.
 // @Tags Admin
 // @ID GetAudit
 // @Accept json
-// @Param user-identifier query string false "User identifier"
+// @Param user_identifier query string false "User identifier"
+// @Param used_group query string false "Used group"
 // @Param entity query string false "Entity"
-// @Param entity-id query string false "Entity ID"
+// @Param entity_id query string false "Entity ID"
 // @Param operation query string false "Operation"
-// @Param client-data query string false "Client data"
-// @Param created-at query string false "Created At"
-// @Param sort query string false "Sort By"
-// @Param asc query string false "Ascending"
+// @Param client_data query string false "Client data"
+// @Param created_at query string false "Created At"
+// @Param changed_field query string false "Changed field - the items which changed the field"
+// @Param sort query string false "Sort fields - field1,-field2"
 // @Param limit query string false "Limit"
+// @Param cursor query string false "Cursor"
 // @Success 200 {array} core.AuditEntity
 // @Security AdminUserAuth
 // @Security AdminGroupAuth
 // @Router /admin/audit [get]
 func (h ApisHandler) GetAudit(current model.User, group string, w http.ResponseWriter, r *http.Request) {
-
-	//user identifier
-	var userIdentifier *string
-	userIdentifierKeys, ok := r.URL.Query()["user-identifier"]
-	if ok {
-		userIdentifier = &userIdentifierKeys[0]
+	values := renameQueryParams(r.URL.Query(), map[string]string{"user-identifier": "user_identifier", "entity-id": "entity_id"})
+	//the old client data param is for items starting with it and the old created at param is for items created after it
+	if clientData := values.Get("client-data"); len(clientData) > 0 {
+		values.Set("client_data", utils.QueryOperatorPrefix+":"+clientData)
 	}
-
-	//entity
-	var entity *string
-	entityKeys, ok := r.URL.Query()["entity"]
-	if ok {
-		entity = &entityKeys[0]
+	if createdAt := values.Get("created-at"); len(createdAt) > 0 {
+		values.Set("created_at", utils.QueryOperatorRange+":"+createdAt+",")
 	}
-
-	//entity id
-	var entityID *string
-	entityIDKeys, ok := r.URL.Query()["entity-id"]
-	if ok {
-		entityID = &entityIDKeys[0]
+	if sort := values.Get(utils.QueryParamSort); len(sort) > 0 && values.Get("asc") == "false" {
+		values.Set(utils.QueryParamSort, "-"+sort)
 	}
-
-	//operation
-	var operation *string
-	operationKeys, ok := r.URL.Query()["operation"]
-	if ok {
-		operation = &operationKeys[0]
+	values.Del("client-data")
+	values.Del("created-at")
+	values.Del("asc")
+	query, err := utils.ParseQuery(values, auditQuerySpec)
+	if err != nil {
+		log.Printf("Error on parsing the query - %s\n", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
 
-	//client data
-	var clientData *string
-	clientDataKeys, ok := r.URL.Query()["client-data"]
-	if ok {
-		clientData = &clientDataKeys[0]
+	items, cursor, err := h.app.Administration.GetAudit(current, group, query)
+	if err != nil {
+		log.Println(err.Error())
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
 	}
-
-	//created at
-	var createdAt *time.Time
-	createdAtKeys, ok := r.URL.Query()["created-at"]
-	if ok {
-		createdAtValue := &createdAtKeys[0]
-		layout := "2006-01-02T15:04:05.000Z"
-		t, err := time.Parse(layout, *createdAtValue)
-		if err == nil {
-			createdAt = &t
-		} else {
-			log.Printf("error parsing date - %s\n", err)
-		}
+	if len(items) == 0 {
+		items = make([]*core.AuditEntity, 0)
 	}
-
-	//sort by
-	var sort *string
-	sortByKeys, ok := r.URL.Query()["sort"]
-	if ok {
-		sort = &sortByKeys[0]
+	data, err := json.Marshal(items)
+	if err != nil {
+		log.Println("Error on marshal the audit items")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
 	}
 
-	//asc
-	var asc *bool
-	ascKeys, ok := r.URL.Query()["asc"]
-	if ok {
-		ascValue, err := strconv.ParseBool(ascKeys[0])
-		if err == nil {
-			asc = &ascValue
-		} else {
-			log.Printf("error parsing asc - %s\n", err)
-		}
-	}
+	setQueryCursor(w, cursor)
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
 
-	//limit
-	var limit *int64
-	limitKeys, ok := r.URL.Query()["limit"]
-	if ok {
-		limitValue, err := strconv.ParseInt(limitKeys[0], 10, 64)
-		if err == nil {
-			limit = &limitValue
-		} else {
-			log.Printf("error parsing limit - %s\n", err)
+//VerifyAudit verifies the audit chain
+// @Description Verifies that the audit items are not modified or removed - every item matches its hash and is chained to the previous one and the signed checkpoints match their items. The checkpoints cannot be verified and the result is not valid if there is no signing key.
+// @Tags Admin
+// @ID VerifyAudit
+// @Accept json
+// @Param from query int false "From sequence - from the first item if not provided"
+// @Param to query int false "To sequence - to the last item if not provided"
+// @Success 200 {object} core.AuditVerification
+// @Security AdminUserAuth
+// @Security AdminGroupAuth
+// @Router /admin/audit/verify [get]
+func (h AdminApisHandler) VerifyAudit(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	var sequences [2]int64
+	for i, param := range []string{"from", "to"} {
+		value := r.URL.Query().Get(param)
+		if len(value) == 0 {
+			continue
+		}
+		sequence, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || sequence < 1 {
+			http.Error(w, param+" must be a positive number", http.StatusBadRequest)
+			return
 		}
+		sequences[i] = sequence
 	}
 
-	items, err := h.app.Administration.GetAudit(current, group, userIdentifier, entity, entityID, operation, clientData, createdAt, sort, asc, limit)
+	verification, err := h.app.Administration.VerifyAudit(sequences[0], sequences[1])
 	if err != nil {
-		log.Println(err.Error())
+		log.Printf("Error on verifying the audit - %s\n", err)
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 		return
 	}
-	if len(items) == 0 {
-		items = make([]*core.AuditEntity, 0)
-	}
-	data, err := json.Marshal(items)
+
+	data, err := json.Marshal(verification)
 	if err != nil {
-		log.Println("Error on marshal the audit items")
+		log.Println("Error on marshal the audit verification")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+//GetAuditMetrics gives the audit writer metrics
+// @Description Gives the audit queue depth, the written, dropped and spilled items and the last write error since the service start.
+// @Tags Admin
+// @ID GetAuditMetrics
+// @Accept json
+// @Success 200 {object} core.AuditMetrics
+// @Security AdminUserAuth
+// @Security AdminGroupAuth
+// @Router /admin/audit/metrics [get]
+func (h AdminApisHandler) GetAuditMetrics(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	metrics := h.app.Administration.GetAuditMetrics()
+
+	data, err := json.Marshal(metrics)
+	if err != nil {
+		log.Println("Error on marshal the audit metrics")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+//GetNotificationTemplates gets the notification templates
+// @Description Gives all the notification templates
+// @Tags Admin
+// @ID GetNotificationTemplates
+// @Accept json
+// @Success 200 {array} model.NotificationTemplate
+// @Security AdminUserAuth
+// @Security AdminGroupAuth
+// @Router /admin/notification-templates [get]
+func (h AdminApisHandler) GetNotificationTemplates(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	templates, err := h.app.Administration.GetNotificationTemplates()
+	if err != nil {
+		log.Printf("Error on getting the notification templates - %s\n", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	if len(templates) == 0 {
+		templates = make([]*model.NotificationTemplate, 0)
+	}
+	data, err := json.Marshal(templates)
+	if err != nil {
+		log.Println("Error on marshal the notification templates")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+type createNotificationTemplateRequest struct {
+	Audit    *string                             `json:"audit"`
+	Event    string                              `json:"event" validate:"required"`
+	Variants []model.NotificationTemplateVariant `json:"variants" validate:"required,min=1,dive"`
+} // @name createNotificationTemplateRequest
+
+//CreateNotificationTemplate creates a notification template
+// @Description Creates a notification template. The title and the body of the variants may contain {{name}} placeholders.
+// @Tags Admin
+// @ID CreateNotificationTemplate
+// @Accept json
+// @Produce json
+// @Param data body createNotificationTemplateRequest true "body data"
+// @Success 200 {object} model.NotificationTemplate
+// @Security AdminUserAuth
+// @Security AdminGroupAuth
+// @Router /admin/notification-templates [post]
+func (h AdminApisHandler) CreateNotificationTemplate(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		log.Printf("Error on marshal create notification template - %s\n", err.Error())
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	var requestData createNotificationTemplateRequest
+	err = json.Unmarshal(data, &requestData)
+	if err != nil {
+		log.Printf("Error on unmarshal the create notification template request data - %s\n", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	//validate
+	validate := validator.New()
+	err = validate.Struct(requestData)
+	if err != nil {
+		log.Printf("Error on validating create notification template data - %s\n", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	template, err := h.app.Administration.CreateNotificationTemplate(current, group, requestData.Audit, requestData.Event, requestData.Variants)
+	if err != nil {
+		log.Println(err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	data, err = json.Marshal(template)
+	if err != nil {
+		log.Println("Error on marshal a notification template")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+type updateNotificationTemplateRequest struct {
+	Audit    *string                             `json:"audit"`
+	Event    string                              `json:"event" validate:"required"`
+	Variants []model.NotificationTemplateVariant `json:"variants" validate:"required,min=1,dive"`
+} // @name updateNotificationTemplateRequest
+
+//UpdateNotificationTemplate updates a notification template
+// @Description Updates a notification template.
+// @Tags Admin
+// @ID UpdateNotificationTemplate
+// @Accept json
+// @Produce json
+// @Param data body updateNotificationTemplateRequest true "body data"
+// @Param id path string true "ID"
+// @Success 200 {object} model.NotificationTemplate
+// @Security AdminUserAuth
+// @Security AdminGroupAuth
+// @Router /admin/notification-templates/{id} [put]
+func (h AdminApisHandler) UpdateNotificationTemplate(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	ID := params["id"]
+	if len(ID) <= 0 {
+		log.Println("Notification template id is required")
+		http.Error(w, "Notification template id is required", http.StatusBadRequest)
+		return
+	}
+
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		log.Printf("Error on marshal the update notification template item - %s\n", err.Error())
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	var requestData updateNotificationTemplateRequest
+	err = json.Unmarshal(data, &requestData)
+	if err != nil {
+		log.Printf("Error on unmarshal the update notification template request data - %s\n", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	//validate
+	validate := validator.New()
+	err = validate.Struct(requestData)
+	if err != nil {
+		log.Printf("Error on validating update notification template data - %s\n", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	template, err := h.app.Administration.UpdateNotificationTemplate(current, group, requestData.Audit, ID, requestData.Event, requestData.Variants)
+	if err != nil {
+		log.Println(err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	data, err = json.Marshal(template)
+	if err != nil {
+		log.Println("Error on marshal a notification template")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+//DeleteNotificationTemplate deletes a notification template
+// @Description Deletes a notification template
+// @Tags Admin
+// @ID DeleteNotificationTemplate
+// @Accept plain
+// @Param id path string true "ID"
+// @Success 200 {object} string "Successfuly deleted"
+// @Security AdminUserAuth
+// @Security AdminGroupAuth
+// @Router /admin/notification-templates/{id} [delete]
+func (h AdminApisHandler) DeleteNotificationTemplate(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	ID := params["id"]
+	if len(ID) <= 0 {
+		log.Println("Notification template id is required")
+		http.Error(w, "Notification template id is required", http.StatusBadRequest)
+		return
+	}
+	err := h.app.Administration.DeleteNotificationTemplate(current, group, ID)
+	if err != nil {
+		log.Println(err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("Successfully deleted"))
+}
+
+//GetBroadcasts gets the broadcasts
+// @Description Gives the broadcasts sorted by the scheduled time, the latest first
+// @Tags Admin
+// @ID GetBroadcasts
+// @Accept json
+// @Param status query string false "scheduled, sending, sent, cancelled or failed"
+// @Success 200 {array} model.Broadcast
+// @Security AdminUserAuth
+// @Security AdminGroupAuth
+// @Router /admin/broadcasts [get]
+func (h AdminApisHandler) GetBroadcasts(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	var status *string
+	statusKeys, ok := r.URL.Query()["status"]
+	if ok && len(statusKeys[0]) > 0 {
+		status = &statusKeys[0]
+	}
+
+	broadcasts, err := h.app.Administration.GetBroadcasts(group, status)
+	if err != nil {
+		log.Printf("Error on getting the broadcasts - %s\n", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	if len(broadcasts) == 0 {
+		broadcasts = make([]*model.Broadcast, 0)
+	}
+	data, err := json.Marshal(broadcasts)
+	if err != nil {
+		log.Println("Error on marshal the broadcasts")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+type previewBroadcastRequest struct {
+	Target model.BroadcastTarget `json:"target"`
+} // @name previewBroadcastRequest
+
+type previewBroadcastResponse struct {
+	AudienceSize int `json:"audience_size"`
+} // @name previewBroadcastResponse
+
+//PreviewBroadcast gives the audience size for a broadcast target
+// @Description Gives how many users a broadcast with the provided target will reach.
+// @Tags Admin
+// @ID PreviewBroadcast
+// @Accept json
+// @Produce json
+// @Param data body previewBroadcastRequest true "body data"
+// @Success 200 {object} previewBroadcastResponse
+// @Security AdminUserAuth
+// @Security AdminGroupAuth
+// @Router /admin/broadcasts/preview [post]
+func (h AdminApisHandler) PreviewBroadcast(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		log.Printf("Error on marshal preview broadcast - %s\n", err.Error())
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	var requestData previewBroadcastRequest
+	err = json.Unmarshal(data, &requestData)
+	if err != nil {
+		log.Printf("Error on unmarshal the preview broadcast request data - %s\n", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	audienceSize, err := h.app.Administration.GetBroadcastAudienceSize(group, requestData.Target)
+	if err != nil {
+		if errors.Is(err, core.ErrCountyForbidden) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		log.Println(err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	data, err = json.Marshal(previewBroadcastResponse{AudienceSize: audienceSize})
+	if err != nil {
+		log.Println("Error on marshal the broadcast preview")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+type createBroadcastRequest struct {
+	Audit       *string               `json:"audit"`
+	Target      model.BroadcastTarget `json:"target"`
+	Title       string                `json:"title" validate:"required"`
+	Body        string                `json:"body" validate:"required"`
+	ScheduledAt *time.Time            `json:"scheduled_at"`
+} // @name createBroadcastRequest
+
+//CreateBroadcast creates a broadcast
+// @Description Creates a broadcast. It is sent at the scheduled time or immediately if there is no scheduled time. An empty target means all users.
+// @Tags Admin
+// @ID CreateBroadcast
+// @Accept json
+// @Produce json
+// @Param data body createBroadcastRequest true "body data"
+// @Success 200 {object} model.Broadcast
+// @Security AdminUserAuth
+// @Security AdminGroupAuth
+// @Router /admin/broadcasts [post]
+func (h AdminApisHandler) CreateBroadcast(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		log.Printf("Error on marshal create broadcast - %s\n", err.Error())
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	var requestData createBroadcastRequest
+	err = json.Unmarshal(data, &requestData)
+	if err != nil {
+		log.Printf("Error on unmarshal the create broadcast request data - %s\n", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	//validate
+	validate := validator.New()
+	err = validate.Struct(requestData)
+	if err != nil {
+		log.Printf("Error on validating create broadcast data - %s\n", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	broadcast, err := h.app.Administration.CreateBroadcast(current, group, requestData.Audit, requestData.Target,
+		requestData.Title, requestData.Body, requestData.ScheduledAt)
+	if err != nil {
+		if errors.Is(err, core.ErrCountyForbidden) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		log.Println(err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	data, err = json.Marshal(broadcast)
+	if err != nil {
+		log.Println("Error on marshal a broadcast")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+//CancelBroadcast cancels a scheduled broadcast
+// @Description Cancels a broadcast which has not been sent yet
+// @Tags Admin
+// @ID CancelBroadcast
+// @Accept plain
+// @Param id path string true "ID"
+// @Success 200 {object} string "Successfully cancelled"
+// @Security AdminUserAuth
+// @Security AdminGroupAuth
+// @Router /admin/broadcasts/{id}/cancel [put]
+func (h AdminApisHandler) CancelBroadcast(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	ID := params["id"]
+	if len(ID) <= 0 {
+		log.Println("Broadcast id is required")
+		http.Error(w, "Broadcast id is required", http.StatusBadRequest)
+		return
+	}
+	err := h.app.Administration.CancelBroadcast(current, group, ID)
+	if err != nil {
+		if errors.Is(err, core.ErrCountyForbidden) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		log.Println(err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("Successfully cancelled"))
+}
+
+//GetComplianceSummary gives the testing compliance dashboard data
+// @Description Gives the roster members testing compliance counts - total, per uin override category and per county.
+// @Tags Admin
+// @ID GetComplianceSummary
+// @Accept json
+// @Success 200 {object} model.ComplianceSummary
+// @Security AdminUserAuth
+// @Security AdminGroupAuth
+// @Router /admin/compliance [get]
+func (h AdminApisHandler) GetComplianceSummary(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	summary, err := h.app.Administration.GetComplianceSummary(group)
+	if err != nil {
+		log.Printf("Error on getting the compliance summary - %s\n", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	data, err := json.Marshal(summary)
+	if err != nil {
+		log.Println("Error on marshal the compliance summary")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+type getComplianceItemsResponse struct {
+	Total int                    `json:"total"`
+	Items []model.ComplianceItem `json:"items"`
+} // @name getComplianceItemsResponse
+
+//GetComplianceItems gives the roster members testing compliance
+// @Description Gives the roster members testing compliance matching the filters and paginated. The csv format gives all the matching items if there is no limit.
+// @Tags Admin
+// @ID GetComplianceItems
+// @Accept json
+// @Produce json,text/csv
+// @Param status query string false "compliant, due-soon, overdue or exempt"
+// @Param category query string false "UIN override category"
+// @Param county-id query string false "County ID"
+// @Param limit query string false "Limit"
+// @Param offset query string false "Offset"
+// @Param format query string false "json or csv"
+// @Success 200 {object} getComplianceItemsResponse
+// @Security AdminUserAuth
+// @Security AdminGroupAuth
+// @Router /admin/compliance/items [get]
+func (h AdminApisHandler) GetComplianceItems(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	var status *string
+	var category *string
+	var countyID *string
+	var limit *int
+	offset := 0
+	csvFormat := false
+	for key, value := range r.URL.Query() {
+		if len(value) < 1 || len(value[0]) < 1 {
+			continue
+		}
+		switch key {
+		case "status":
+			status = &value[0]
+		case "category":
+			category = &value[0]
+		case "county-id":
+			countyID = &value[0]
+		case "limit":
+			limitValue, err := strconv.Atoi(value[0])
+			if err != nil || limitValue < 1 || limitValue > 100 {
+				log.Println("Invalid 'limit' value - " + value[0])
+				http.Error(w, "Invalid 'limit' value - Must be an integer between 1 and 100", http.StatusBadRequest)
+				return
+			}
+			limit = &limitValue
+		case "offset":
+			offsetValue, err := strconv.Atoi(value[0])
+			if err != nil || offsetValue < 0 {
+				log.Println("Invalid 'offset' value - " + value[0])
+				http.Error(w, "Invalid 'offset' value - Must be a positive integer", http.StatusBadRequest)
+				return
+			}
+			offset = offsetValue
+		case "format":
+			csvFormat = value[0] == "csv"
+		}
+	}
+
+	//json is paginated by default, csv is an export
+	pageSize := 20
+	if csvFormat {
+		pageSize = 0
+	}
+	if limit != nil {
+		pageSize = *limit
+	}
+
+	items, total, err := h.app.Administration.GetComplianceItems(group, status, category, countyID, pageSize, offset)
+	if err != nil {
+		if errors.Is(err, core.ErrCountyForbidden) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		log.Println(err.Error())
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	if csvFormat {
+		h.writeComplianceCSV(w, items)
+		return
+	}
+
+	data, err := json.Marshal(getComplianceItemsResponse{Total: total, Items: items})
+	if err != nil {
+		log.Println("Error on marshal the compliance items")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+func (h AdminApisHandler) writeComplianceCSV(w http.ResponseWriter, items []model.ComplianceItem) {
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", "attachment; filename=\"compliance.csv\"")
+	w.WriteHeader(http.StatusOK)
+
+	formatDate := func(date *time.Time) string {
+		if date == nil {
+			return ""
+		}
+		return date.Format("2006-01-02")
+	}
+
+	writer := csv.NewWriter(w)
+	writer.Write([]string{"uin", "first_name", "last_name", "category", "county_id", "latest_test_date", "due_date", "status"})
+	for _, item := range items {
+		writer.Write([]string{item.UIN, item.FirstName, item.LastName, utils.GetString(item.Category), utils.GetString(item.CountyID),
+			formatDate(item.LatestTestDate), formatDate(item.DueDate), item.Status})
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		log.Printf("Error on writing the compliance csv - %s\n", err)
+	}
+}
+
+//GetExposureVerificationCodes gives the exposure verification codes
+// @Description Gives the exposure verification codes, the newest first. The codes themselves are not stored so they are not given.
+// @Tags Admin
+// @ID GetExposureVerificationCodes
+// @Accept json
+// @Param issued-by query string false "Issued by - user identifier or provider:{provider id}"
+// @Param status query string false "Status - issued, claimed or used"
+// @Param limit query int false "Limit - 100 if not provided"
+// @Success 200 {array} model.ExposureVerificationCode
+// @Security AdminUserAuth
+// @Security AdminGroupAuth
+// @Router /admin/exposure-verification-codes [get]
+func (h AdminApisHandler) GetExposureVerificationCodes(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	var issuedBy *string
+	issuedByKeys, ok := r.URL.Query()["issued-by"]
+	if ok && len(issuedByKeys[0]) > 0 {
+		issuedBy = &issuedByKeys[0]
+	}
+
+	var status *string
+	statusKeys, ok := r.URL.Query()["status"]
+	if ok && len(statusKeys[0]) > 0 {
+		status = &statusKeys[0]
+	}
+
+	limit := int64(100)
+	limitKeys, ok := r.URL.Query()["limit"]
+	if ok && len(limitKeys[0]) > 0 {
+		value, err := strconv.ParseInt(limitKeys[0], 10, 64)
+		if err != nil {
+			http.Error(w, "limit must be a number", http.StatusBadRequest)
+			return
+		}
+		limit = value
+	}
+
+	codes, err := h.app.Administration.GetExposureVerificationCodes(issuedBy, status, limit)
+	if err != nil {
+		log.Printf("Error on getting the exposure verification codes - %s\n", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	if len(codes) == 0 {
+		codes = make([]model.ExposureVerificationCode, 0)
+	}
+	data, err := json.Marshal(codes)
+	if err != nil {
+		log.Println("Error on marshal the exposure verification codes")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+type issueExposureVerificationCodeRequest struct {
+	Audit            *string    `json:"audit"`
+	ReportType       string     `json:"report_type" validate:"required,oneof=confirmed likely"`
+	TestDate         *time.Time `json:"test_date" validate:"required"`
+	SymptomOnsetDate *time.Time `json:"symptom_onset_date"`
+} // @name issueExposureVerificationCodeRequest
+
+type issueExposureVerificationCodeResponse struct {
+	Code string `json:"code"`
+	model.ExposureVerificationCode
+} // @name issueExposureVerificationCodeResponse
+
+//IssueExposureVerificationCode issues an exposure verification code
+// @Description Issues a one-time exposure verification code for a positive test. The user enters the code in the app in order to upload the exposure keys. The code is given only in this response.
+// @Tags Admin
+// @ID IssueExposureVerificationCode
+// @Accept json
+// @Produce json
+// @Param data body issueExposureVerificationCodeRequest true "body data"
+// @Success 200 {object} issueExposureVerificationCodeResponse
+// @Security AdminUserAuth
+// @Security AdminGroupAuth
+// @Router /admin/exposure-verification-codes [post]
+func (h AdminApisHandler) IssueExposureVerificationCode(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		log.Printf("Error on marshal issue exposure verification code - %s\n", err.Error())
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	var requestData issueExposureVerificationCodeRequest
+	err = json.Unmarshal(data, &requestData)
+	if err != nil {
+		log.Printf("Error on unmarshal the issue exposure verification code request data - %s\n", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	//validate
+	validate := validator.New()
+	err = validate.Struct(requestData)
+	if err != nil {
+		log.Printf("Error on validating issue exposure verification code data - %s\n", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	item, code, err := h.app.Administration.IssueExposureVerificationCode(current, group, requestData.Audit,
+		requestData.ReportType, *requestData.TestDate, requestData.SymptomOnsetDate)
+	if err != nil {
+		log.Println(err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	data, err = json.Marshal(issueExposureVerificationCodeResponse{Code: code, ExposureVerificationCode: *item})
+	if err != nil {
+		log.Println("Error on marshal an exposure verification code")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+//GetExposureMetrics gives the trace exposures metrics
+// @Description Gives the trace exposures counts, the retention period and the purges for the last days.
+// @Tags Admin
+// @ID GetExposureMetrics
+// @Accept json
+// @Param days query int false "Days - the purges period, 30 if not provided"
+// @Success 200 {object} model.ExposureMetrics
+// @Security AdminUserAuth
+// @Security AdminGroupAuth
+// @Router /admin/exposure-metrics [get]
+func (h AdminApisHandler) GetExposureMetrics(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	days := 30
+	daysKeys, ok := r.URL.Query()["days"]
+	if ok && len(daysKeys[0]) > 0 {
+		value, err := strconv.Atoi(daysKeys[0])
+		if err != nil || value < 0 {
+			http.Error(w, "days must be a positive number", http.StatusBadRequest)
+			return
+		}
+		days = value
+	}
+
+	metrics, err := h.app.Administration.GetExposureMetrics(days)
+	if err != nil {
+		log.Printf("Error on getting the exposure metrics - %s\n", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	data, err := json.Marshal(metrics)
+	if err != nil {
+		log.Println("Error on marshal the exposure metrics")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+//GetUsersCacheMetrics gives the users cache metrics
+// @Description Gives the users cache size, limits and hits, misses and invalidations since the service start.
+// @Tags Admin
+// @ID GetUsersCacheMetrics
+// @Accept json
+// @Success 200 {object} model.UsersCacheMetrics
+// @Security AdminUserAuth
+// @Security AdminGroupAuth
+// @Router /admin/users-cache-metrics [get]
+func (h AdminApisHandler) GetUsersCacheMetrics(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	metrics := h.app.Administration.GetUsersCacheMetrics()
+
+	data, err := json.Marshal(metrics)
+	if err != nil {
+		log.Println("Error on marshal the users cache metrics")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+//GetPermissions gives the permissions which the roles can have
+// @Description Gives the permissions which the roles can have.
+// @Tags Admin
+// @ID GetPermissions
+// @Accept json
+// @Success 200 {array} string
+// @Security AdminUserAuth
+// @Security AdminGroupAuth
+// @Router /admin/permissions [get]
+func (h AdminApisHandler) GetPermissions(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	data, err := json.Marshal(model.Permissions)
+	if err != nil {
+		log.Println("Error on marshal the permissions")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+//GetRoles gets the roles
+// @Description Gives the roles with their groups and permissions.
+// @Tags Admin
+// @ID GetRoles
+// @Accept json
+// @Success 200 {array} model.Role
+// @Security AdminUserAuth
+// @Security AdminGroupAuth
+// @Router /admin/roles [get]
+func (h AdminApisHandler) GetRoles(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	roles, err := h.app.Administration.GetRoles()
+	if err != nil {
+		log.Printf("Error on getting the roles - %s\n", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	data, err := json.Marshal(roles)
+	if err != nil {
+		log.Println("Error on marshal the roles")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+type roleRequest struct {
+	Audit       *string  `json:"audit"`
+	Name        string   `json:"name" validate:"required"`
+	Description string   `json:"description"`
+	Groups      []string `json:"groups"`
+	Permissions []string `json:"permissions" validate:"required"`
+	Counties    []string `json:"counties"`
+} // @name roleRequest
+
+//CreateRole creates a role
+// @Description Creates a role. The members of the role groups get the role permissions. The permissions for the county bound entities are limited to the role counties when they are provided.
+// @Tags Admin
+// @ID CreateRole
+// @Accept json
+// @Produce json
+// @Param data body roleRequest true "body data"
+// @Success 200 {object} model.Role
+// @Security AdminUserAuth
+// @Security AdminGroupAuth
+// @Router /admin/roles [post]
+func (h AdminApisHandler) CreateRole(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	requestData, err := readRoleRequest(r)
+	if err != nil {
+		log.Printf("Error on reading the create role request - %s\n", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	role, err := h.app.Administration.CreateRole(current, group, requestData.Audit, requestData.Name, requestData.Description,
+		requestData.Groups, requestData.Permissions, requestData.Counties)
+	if err != nil {
+		log.Printf("Error on creating a role - %s\n", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	data, err := json.Marshal(role)
+	if err != nil {
+		log.Println("Error on marshal the role")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+//UpdateRole updates a role
+// @Description Updates a role. At least one role with groups must keep the roles.write permission.
+// @Tags Admin
+// @ID UpdateRole
+// @Accept json
+// @Produce json
+// @Param data body roleRequest true "body data"
+// @Param id path string true "ID"
+// @Success 200 {object} model.Role
+// @Security AdminUserAuth
+// @Security AdminGroupAuth
+// @Router /admin/roles/{id} [put]
+func (h AdminApisHandler) UpdateRole(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	ID := mux.Vars(r)["id"]
+	if len(ID) <= 0 {
+		log.Println("Role id is required")
+		http.Error(w, "Role id is required", http.StatusBadRequest)
+		return
+	}
+
+	requestData, err := readRoleRequest(r)
+	if err != nil {
+		log.Printf("Error on reading the update role request - %s\n", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	role, err := h.app.Administration.UpdateRole(current, group, requestData.Audit, ID, requestData.Name, requestData.Description,
+		requestData.Groups, requestData.Permissions, requestData.Counties)
+	if err != nil {
+		log.Printf("Error on updating a role - %s\n", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	data, err := json.Marshal(role)
+	if err != nil {
+		log.Println("Error on marshal the role")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+//DeleteRole deletes a role
+// @Description Deletes a role. At least one role with groups must keep the roles.write permission.
+// @Tags Admin
+// @ID DeleteRole
+// @Accept plain
+// @Param id path string true "ID"
+// @Success 200 {object} string "Successfully deleted"
+// @Security AdminUserAuth
+// @Security AdminGroupAuth
+// @Router /admin/roles/{id} [delete]
+func (h AdminApisHandler) DeleteRole(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	ID := mux.Vars(r)["id"]
+	if len(ID) <= 0 {
+		log.Println("Role id is required")
+		http.Error(w, "Role id is required", http.StatusBadRequest)
+		return
+	}
+
+	err := h.app.Administration.DeleteRole(current, group, ID)
+	if err != nil {
+		log.Printf("Error on deleting a role - %s\n", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("Successfully deleted"))
+}
+
+func readRoleRequest(r *http.Request) (*roleRequest, error) {
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var requestData roleRequest
+	err = json.Unmarshal(data, &requestData)
+	if err != nil {
+		return nil, err
+	}
+
+	validate := validator.New()
+	err = validate.Struct(requestData)
+	if err != nil {
+		return nil, err
+	}
+	return &requestData, nil
+}
+
+//GetAPIKeys gives the managed API keys
+// @Description Gives the API keys managed by the admins. The keys values are not given.
+// @Tags Admin
+// @ID GetAPIKeys
+// @Accept json
+// @Success 200 {array} model.APIKey
+// @Security AdminUserAuth
+// @Security AdminGroupAuth
+// @Router /admin/api-keys [get]
+func (h AdminApisHandler) GetAPIKeys(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	apiKeys, err := h.app.Administration.GetAPIKeys()
+	if err != nil {
+		log.Printf("Error on getting the API keys - %s\n", err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	data, err := json.Marshal(apiKeys)
+	if err != nil {
+		log.Println("Error on marshal the API keys")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+type createAPIKeyRequest struct {
+	Audit       *string    `json:"audit"`
+	Name        string     `json:"name" validate:"required"`
+	Owner       string     `json:"owner"`
+	Scopes      []string   `json:"scopes" validate:"required"`
+	DateExpires *time.Time `json:"date_expires"`
+} // @name createAPIKeyRequest
+
+type apiKeyResponse struct {
+	APIKey model.APIKey `json:"api_key"`
+	Key    string       `json:"key"`
+} // @name apiKeyResponse
+
+//CreateAPIKey creates an API key
+// @Description Creates an API key. The key value is given only in this response. The scopes are "app", "external" and "providers".
+// @Tags Admin
+// @ID CreateAPIKey
+// @Accept json
+// @Produce json
+// @Param data body createAPIKeyRequest true "body data"
+// @Success 200 {object} apiKeyResponse
+// @Security AdminUserAuth
+// @Security AdminGroupAuth
+// @Router /admin/api-keys [post]
+func (h AdminApisHandler) CreateAPIKey(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		log.Printf("Error on marshal create an API key - %s\n", err.Error())
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	var requestData createAPIKeyRequest
+	err = json.Unmarshal(data, &requestData)
+	if err != nil {
+		log.Printf("Error on unmarshal the create API key request data - %s\n", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	//validate
+	validate := validator.New()
+	err = validate.Struct(requestData)
+	if err != nil {
+		log.Printf("Error on validating create API key data - %s\n", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	apiKey, key, err := h.app.Administration.CreateAPIKey(current, group, requestData.Audit, requestData.Name, requestData.Owner,
+		requestData.Scopes, requestData.DateExpires)
+	if err != nil {
+		log.Printf("Error on creating an API key - %s\n", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	h.writeAPIKey(w, *apiKey, key)
+}
+
+type rotateAPIKeyRequest struct {
+	Audit            *string `json:"audit"`
+	GracePeriodHours *int    `json:"grace_period_hours"`
+} // @name rotateAPIKeyRequest
+
+//RotateAPIKey rotates an API key
+// @Description Creates a new API key with the name, the owner and the scopes of the rotated one. The rotated key stays valid for the grace period - 24 hours if not provided.
+// @Tags Admin
+// @ID RotateAPIKey
+// @Accept json
+// @Produce json
+// @Param data body rotateAPIKeyRequest false "body data"
+// @Param id path string true "ID"
+// @Success 200 {object} apiKeyResponse
+// @Security AdminUserAuth
+// @Security AdminGroupAuth
+// @Router /admin/api-keys/{id}/rotate [put]
+func (h AdminApisHandler) RotateAPIKey(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	ID := mux.Vars(r)["id"]
+	if len(ID) <= 0 {
+		log.Println("API key id is required")
+		http.Error(w, "API key id is required", http.StatusBadRequest)
+		return
+	}
+
+	var requestData rotateAPIKeyRequest
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		log.Printf("Error on marshal rotate an API key - %s\n", err.Error())
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+	if len(data) > 0 {
+		err = json.Unmarshal(data, &requestData)
+		if err != nil {
+			log.Printf("Error on unmarshal the rotate API key request data - %s\n", err.Error())
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	apiKey, key, err := h.app.Administration.RotateAPIKey(current, group, requestData.Audit, ID, requestData.GracePeriodHours)
+	if err != nil {
+		log.Printf("Error on rotating an API key - %s\n", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	h.writeAPIKey(w, *apiKey, key)
+}
+
+type revokeAPIKeyRequest struct {
+	Audit *string `json:"audit"`
+} // @name revokeAPIKeyRequest
+
+//RevokeAPIKey revokes an API key
+// @Description Revokes an API key. It cannot be used anymore.
+// @Tags Admin
+// @ID RevokeAPIKey
+// @Accept json
+// @Param data body revokeAPIKeyRequest false "body data"
+// @Param id path string true "ID"
+// @Success 200 {object} string "Successfully revoked"
+// @Security AdminUserAuth
+// @Security AdminGroupAuth
+// @Router /admin/api-keys/{id}/revoke [put]
+func (h AdminApisHandler) RevokeAPIKey(current model.User, group string, w http.ResponseWriter, r *http.Request) {
+	ID := mux.Vars(r)["id"]
+	if len(ID) <= 0 {
+		log.Println("API key id is required")
+		http.Error(w, "API key id is required", http.StatusBadRequest)
+		return
+	}
+
+	var requestData revokeAPIKeyRequest
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		log.Printf("Error on marshal revoke an API key - %s\n", err.Error())
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+	if len(data) > 0 {
+		err = json.Unmarshal(data, &requestData)
+		if err != nil {
+			log.Printf("Error on unmarshal the revoke API key request data - %s\n", err.Error())
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	err = h.app.Administration.RevokeAPIKey(current, group, requestData.Audit, ID)
+	if err != nil {
+		log.Printf("Error on revoking an API key - %s\n", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("Successfully revoked"))
+}
+
+func (h AdminApisHandler) writeAPIKey(w http.ResponseWriter, apiKey model.APIKey, key string) {
+	data, err := json.Marshal(apiKeyResponse{APIKey: apiKey, Key: key})
+	if err != nil {
+		log.Println("Error on marshal the API key")
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 		return
 	}
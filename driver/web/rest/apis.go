@@ -18,6 +18,11 @@
 package rest
 
 import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -165,19 +170,21 @@ type createCTestRequest struct {
 	EncryptedKey  string  `json:"encrypted_key" validate:"required"`
 	EncryptedBlob string  `json:"encrypted_blob" validate:"required"`
 	OrderNumber   *string `json:"order_number"`
+	Positive      bool    `json:"positive"` //an exposure verification code is issued for the user only for positive results
 } // @name createCTestRequest
 
 //CreateExternalCTest creates CTest
-// @Description Creates CTest.
+// @Description Creates CTest. The provider credentials create ctests only for their provider, the shared keys are accepted until their sunset.
 // @Tags Providers
 // @ID createCTest
 // @Accept json
 // @Produce json
 // @Param data body createCTestRequest true "body data"
 // @Success 200 {object} string "Successfully created"
+// @Failure 403 {object} string "The provider credential does not allow this provider"
 // @Security ProvidersAuth
 // @Router /covid19/ctests [post]
-func (h ApisHandler) CreateExternalCTest(w http.ResponseWriter, r *http.Request) {
+func (h ApisHandler) CreateExternalCTest(credential *model.ProviderCredential, w http.ResponseWriter, r *http.Request) {
 	data, err := ioutil.ReadAll(r.Body)
 	if err != nil {
 		log.Printf("Error on marshal create a ctest - %s\n", err.Error())
@@ -207,10 +214,15 @@ func (h ApisHandler) CreateExternalCTest(w http.ResponseWriter, r *http.Request)
 	encryptedKey := requestData.EncryptedKey
 	encryptedBlob := requestData.EncryptedBlob
 	orderNumber := requestData.OrderNumber
+	positive := requestData.Positive
 
-	err = h.app.Services.CreateExternalCTest(providerID, uin, encryptedKey, encryptedBlob, orderNumber)
+	err = h.app.Services.CreateExternalCTest(credential, providerID, uin, encryptedKey, encryptedBlob, orderNumber, positive)
 	if err != nil {
 		log.Printf("Error on creating a ctest - %s\n", err)
+		if errors.Is(err, core.ErrProviderForbidden) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -1705,6 +1717,112 @@ func (h ApisHandler) SetUINBuildingAccess(current model.User, account model.Acco
 	w.Write([]byte("Successfully processed"))
 }
 
+type pendingSubAccountResponse struct {
+	UIN        string `json:"uin"`
+	FirstName  string `json:"first_name"`
+	MiddleName string `json:"middle_name"`
+	LastName   string `json:"last_name"`
+	BirthDate  string `json:"birth_date"`
+} // @name pendingSubAccountResponse
+
+//GetPendingSubAccounts gives the sub accounts which wait for the user to accept or decline them
+// @Description Gives the sub accounts which have been added for the user and wait to be accepted or declined.
+// @Tags Covid19
+// @ID GetPendingSubAccounts
+// @Accept json
+// @Success 200 {array} pendingSubAccountResponse
+// @Security AppUserAuth
+// @Router /covid19/user/sub-accounts [get]
+func (h ApisHandler) GetPendingSubAccounts(current model.User, w http.ResponseWriter, r *http.Request) {
+	items, err := h.app.Services.GetPendingSubAccounts(current)
+	if err != nil {
+		log.Printf("error getting the pending sub accounts - %s", err.Error())
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	result := make([]pendingSubAccountResponse, len(items))
+	for i, c := range items {
+		result[i] = pendingSubAccountResponse{UIN: c.UIN, FirstName: c.FirstName, MiddleName: c.MiddleName, LastName: c.LastName, BirthDate: c.BirthDate}
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		log.Println("Error on marshal the pending sub accounts")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+//AcceptSubAccount accepts a pending sub account
+// @Description Accepts a pending sub account. It becomes an active account of the user.
+// @Tags Covid19
+// @ID AcceptSubAccount
+// @Accept json
+// @Param uin path string true "UIN"
+// @Success 200 {object} model.Account
+// @Security AppUserAuth
+// @Router /covid19/user/sub-accounts/{uin}/accept [put]
+func (h ApisHandler) AcceptSubAccount(current model.User, w http.ResponseWriter, r *http.Request) {
+	uin := mux.Vars(r)["uin"]
+	if len(uin) <= 0 {
+		log.Println("uin is required")
+		http.Error(w, "uin is required", http.StatusBadRequest)
+		return
+	}
+
+	account, err := h.app.Services.AcceptSubAccount(current, uin)
+	if err != nil {
+		log.Printf("error accepting a sub account - %s", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	data, err := json.Marshal(account)
+	if err != nil {
+		log.Println("Error on marshal the accepted sub account")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+//DeclineSubAccount declines a pending sub account
+// @Description Declines a pending sub account.
+// @Tags Covid19
+// @ID DeclineSubAccount
+// @Accept plain
+// @Param uin path string true "UIN"
+// @Success 200 {object} string "Successfully declined"
+// @Security AppUserAuth
+// @Router /covid19/user/sub-accounts/{uin}/decline [put]
+func (h ApisHandler) DeclineSubAccount(current model.User, w http.ResponseWriter, r *http.Request) {
+	uin := mux.Vars(r)["uin"]
+	if len(uin) <= 0 {
+		log.Println("uin is required")
+		http.Error(w, "uin is required", http.StatusBadRequest)
+		return
+	}
+
+	err := h.app.Services.DeclineSubAccount(current, uin)
+	if err != nil {
+		log.Printf("error declining a sub account - %s", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("Successfully declined"))
+}
+
 //GetExtJoinExternalApproval gets the join external approvals for approving
 // @Description Gives the join groups external approvals for approving
 // @Tags Covid19
@@ -2598,16 +2716,24 @@ type addTraceReportRequest []struct {
 } // @name addTraceReportRequest
 
 //AddTraceReport adds a trace report
-// @Description Adds contact tracing report. "timestamp" - Unix time, the number of milliseconds elapsed since January 1, 1970 UTC
+// @Description Adds contact tracing report. "timestamp" - Unix time, the number of milliseconds elapsed since January 1, 1970 UTC. The upload must be authorized by an upload token given by the /covid19/trace/verify API.
 // @Tags Covid19
 // @ID AddTraceReport
 // @Produce plain
 // @Accept json
+// @Param ROKWIRE-UPLOAD-TOKEN header string true "the upload token"
 // @Param data body addTraceReportRequest true "body data"
 // @Success 200 {object} string "Successfully added [n] items"
 // @Security RokwireAuth
 // @Router /covid19/trace/report [post]
 func (h ApisHandler) AddTraceReport(appVersion *string, w http.ResponseWriter, r *http.Request) {
+	uploadToken := r.Header.Get("ROKWIRE-UPLOAD-TOKEN")
+	if len(uploadToken) == 0 {
+		log.Println("Missing upload token for a trace report")
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+		return
+	}
+
 	data, err := ioutil.ReadAll(r.Body)
 	if err != nil {
 		log.Printf("Error on marshal create a trace report - %s\n", err.Error())
@@ -2641,10 +2767,15 @@ func (h ApisHandler) AddTraceReport(appVersion *string, w http.ResponseWriter, r
 	}
 
 	//add it
-	insertedCount, err := h.app.Services.AddTraceReport(traceExposures)
+	//the upload token is checked by the core
+	insertedCount, err := h.app.Services.AddTraceReport(traceExposures, uploadToken)
 	if err != nil {
 		log.Printf("Error on adding a trace report - %s", err.Error())
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		if errors.Is(err, core.ErrUploadTokenNotValid) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
 		return
 	}
 
@@ -2654,12 +2785,20 @@ func (h ApisHandler) AddTraceReport(appVersion *string, w http.ResponseWriter, r
 }
 
 //GetExposures gets the exposures items
-// @Description Gives the exposures records. "timestamp" and "date-added" params are optional. It is the time in milliseconds.
+// @Description Gives the exposures records. "timestamp" and "date-added" params are optional. It is the time in milliseconds. The expired exposures and the exposures older than the retention period are not given.
+// @Description
+// @Description The records are given in pages sorted by the date added if "limit" or "cursor" is provided. The next page cursor is given in the ROKWIRE-CONTINUATION-TOKEN header.
+// @Description The cursor of the last page gives only the exposures added after it, so the clients download only the new exposures incrementally. The pages have an ETag and If-None-Match is supported.
+// @Description The pages give the exposures added more than 2 minutes ago, so that the cursor does not move after the exposures which are still being added. The cursor keeps the first page "timestamp", it cannot be used with "timestamp".
+// @Description The "binary" format gives for every record - timestamp (int64), expirestamp (int64, 0 if not set), tek length (uint16) and tek bytes, big endian. The response is gzip encoded if the client accepts it.
 // @Tags Covid19
 // @ID GetExposures
 // @Accept json
 // @Param timestamp query int false "timestamp"
 // @Param date-added query string false "date-added"
+// @Param limit query int false "page size - 1000 if not provided, 5000 max"
+// @Param cursor query string false "the cursor given by the previous page"
+// @Param format query string false "json or binary - json if not provided, only for pages"
 // @Success 200 {array} model.TraceExposure
 // @Security RokwireAuth
 // @Router /covid19/trace/exposures [get]
@@ -2690,6 +2829,13 @@ func (h ApisHandler) GetExposures(appVersion *string, w http.ResponseWriter, r *
 		dateAdded = &da
 	}
 
+	_, hasLimit := r.URL.Query()["limit"]
+	_, hasCursor := r.URL.Query()["cursor"]
+	if hasLimit || hasCursor {
+		h.getExposuresPage(timestamp, w, r)
+		return
+	}
+
 	items, err := h.app.Services.GetExposures(timestamp, dateAdded)
 	if err != nil {
 		log.Printf("Error on getting the trace exposures items - %s", err.Error())
@@ -2712,6 +2858,305 @@ func (h ApisHandler) GetExposures(appVersion *string, w http.ResponseWriter, r *
 	w.Write(data)
 }
 
+func (h ApisHandler) getExposuresPage(timestamp *int64, w http.ResponseWriter, r *http.Request) {
+	limit := 1000
+	limitKeys, ok := r.URL.Query()["limit"]
+	if ok && len(limitKeys[0]) > 0 {
+		value, err := strconv.Atoi(limitKeys[0])
+		if err != nil || value <= 0 || value > 5000 {
+			log.Println("bad limit value")
+			http.Error(w, "limit must be between 1 and 5000", http.StatusBadRequest)
+			return
+		}
+		limit = value
+	}
+
+	var cursor *string
+	cursorKeys, ok := r.URL.Query()["cursor"]
+	if ok && len(cursorKeys[0]) > 0 {
+		cursor = &cursorKeys[0]
+	}
+
+	format := r.URL.Query().Get("format")
+	if len(format) > 0 && format != "json" && format != "binary" {
+		log.Println("bad format value")
+		http.Error(w, "format must be json or binary", http.StatusBadRequest)
+		return
+	}
+
+	items, nextCursor, err := h.app.Services.GetExposuresPage(timestamp, cursor, limit)
+	if err != nil {
+		log.Printf("Error on getting the trace exposures page - %s", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var data []byte
+	contentType := "application/json; charset=utf-8"
+	if format == "binary" {
+		data = encodeExposuresBinary(items)
+		contentType = "application/octet-stream"
+	} else {
+		data, err = json.Marshal(items)
+		if err != nil {
+			log.Println("Error on marshal the trace exposures page")
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	//the same page gives the same etag
+	hash := sha256.Sum256(data)
+	etag := "\"" + hex.EncodeToString(hash[:16]) + "\""
+	w.Header().Set("ETag", etag)
+	w.Header().Set("ROKWIRE-CONTINUATION-TOKEN", nextCursor)
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	if strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+		var buffer bytes.Buffer
+		gzipWriter := gzip.NewWriter(&buffer)
+		_, err = gzipWriter.Write(data)
+		if err == nil {
+			err = gzipWriter.Close()
+		}
+		if err != nil {
+			log.Printf("Error on compressing the trace exposures page - %s", err.Error())
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		data = buffer.Bytes()
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Header().Set("Vary", "Accept-Encoding")
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+//encodeExposuresBinary encodes every exposure as timestamp, expirestamp (0 if not set), tek length and tek - big endian
+func encodeExposuresBinary(items []model.TraceExposure) []byte {
+	var buffer bytes.Buffer
+	for _, item := range items {
+		var expirestamp int64
+		if item.Expirestamp != nil {
+			expirestamp = *item.Expirestamp
+		}
+		binary.Write(&buffer, binary.BigEndian, item.Timestamp)
+		binary.Write(&buffer, binary.BigEndian, expirestamp)
+		binary.Write(&buffer, binary.BigEndian, uint16(len(item.TEK)))
+		buffer.WriteString(item.TEK)
+	}
+	return buffer.Bytes()
+}
+
+type publishExposureKeysRequest struct {
+	TemporaryExposureKeys []struct {
+		Key                string `json:"key" validate:"required"`
+		RollingStartNumber *int32 `json:"rollingStartNumber" validate:"required"`
+		RollingPeriod      *int32 `json:"rollingPeriod" validate:"required"`
+		TransmissionRisk   *int32 `json:"transmissionRisk" validate:"required"`
+	} `json:"temporaryExposureKeys" validate:"required,min=1,dive"`
+	VerificationPayload string `json:"verificationPayload" validate:"required"`
+	HMACKey             string `json:"hmacKey" validate:"required"`
+	Padding             string `json:"padding"`
+} // @name publishExposureKeysRequest
+
+type publishExposureKeysResponse struct {
+	InsertedExposures int `json:"insertedExposures"`
+} // @name publishExposureKeysResponse
+
+//PublishExposureKeys publishes exposure notification keys
+// @Description Publishes exposure notification (GAEN) temporary exposure keys. The upload must be verified by a verification certificate issued for the keys - "verificationPayload" and "hmacKey". Each certificate can be used once, the keys which have been already published are skipped.
+// @Tags Covid19
+// @ID PublishExposureKeys
+// @Accept json
+// @Produce json
+// @Param data body publishExposureKeysRequest true "body data"
+// @Success 200 {object} publishExposureKeysResponse
+// @Security RokwireAuth
+// @Router /covid19/trace/publish [post]
+func (h ApisHandler) PublishExposureKeys(appVersion *string, w http.ResponseWriter, r *http.Request) {
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		log.Printf("Error on marshal publish exposure keys - %s\n", err.Error())
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	var requestData publishExposureKeysRequest
+	err = json.Unmarshal(data, &requestData)
+	if err != nil {
+		log.Printf("Error on unmarshal the publish exposure keys request data - %s\n", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	//validate
+	validate := validator.New()
+	err = validate.Struct(requestData)
+	if err != nil {
+		log.Printf("Error on validating publish exposure keys data - %s\n", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	keys := make([]model.TraceExposure, len(requestData.TemporaryExposureKeys))
+	for i, item := range requestData.TemporaryExposureKeys {
+		keys[i] = model.TraceExposure{TEK: item.Key, RollingStartNumber: item.RollingStartNumber,
+			RollingPeriod: item.RollingPeriod, TransmissionRisk: item.TransmissionRisk}
+	}
+
+	//the keys and the certificate are verified by the core, so all errors are treated as a bad request
+	insertedCount, err := h.app.Services.PublishExposureKeys(keys, requestData.VerificationPayload, requestData.HMACKey)
+	if err != nil {
+		log.Printf("Error on publishing exposure keys - %s", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	data, err = json.Marshal(publishExposureKeysResponse{InsertedExposures: insertedCount})
+	if err != nil {
+		log.Println("Error on marshal the publish exposure keys response")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+//GetExposureExportIndex gives the exposure keys export files index
+// @Description Gives the exposure keys export files names, one per line. The files are available for the last 14 days and are given by the /covid19/trace/export/{name} API.
+// @Tags Covid19
+// @ID GetExposureExportIndex
+// @Produce plain
+// @Success 200 {string} string "the files names"
+// @Security RokwireAuth
+// @Router /covid19/trace/export/index.txt [get]
+func (h ApisHandler) GetExposureExportIndex(appVersion *string, w http.ResponseWriter, r *http.Request) {
+	names, err := h.app.Services.GetExposureExportIndex()
+	if err != nil {
+		log.Printf("Error on getting the exposure export index - %s", err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(strings.Join(names, "\n")))
+}
+
+//GetExposureExport gives an exposure keys export file
+// @Description Gives a signed exposure keys export file in the GAEN format. The file names are given by the /covid19/trace/export/index.txt API.
+// @Tags Covid19
+// @ID GetExposureExport
+// @Produce application/zip
+// @Param name path string true "the file name - {start}-{end}-{batch}.zip"
+// @Success 200 {file} file "the export archive"
+// @Security RokwireAuth
+// @Router /covid19/trace/export/{name} [get]
+func (h ApisHandler) GetExposureExport(appVersion *string, w http.ResponseWriter, r *http.Request) {
+	params := mux.Vars(r)
+	name := params["name"]
+
+	var start, end int64
+	var batchNum int
+	_, err := fmt.Sscanf(name, "%d-%d-%d.zip", &start, &end, &batchNum)
+	if err != nil {
+		log.Printf("Bad exposure export name %s", name)
+		http.Error(w, "bad export name", http.StatusBadRequest)
+		return
+	}
+
+	export, err := h.app.Services.GetExposureExport(start, end, batchNum)
+	if err != nil {
+		log.Printf("Error on getting the exposure export %s - %s", name, err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if export == nil {
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", "attachment; filename="+name)
+	w.WriteHeader(http.StatusOK)
+	w.Write(export)
+}
+
+type verifyExposureCodeRequest struct {
+	Code string `json:"code" validate:"required"`
+} // @name verifyExposureCodeRequest
+
+type verifyExposureCodeResponse struct {
+	Token            string     `json:"token"`
+	TokenExpiresAt   *time.Time `json:"token_expires_at"`
+	ReportType       string     `json:"report_type"`
+	TestDate         time.Time  `json:"test_date"`
+	SymptomOnsetDate *time.Time `json:"symptom_onset_date"`
+} // @name verifyExposureCodeResponse
+
+//VerifyExposureCode exchanges an exposure verification code for an upload token
+// @Description Exchanges a one-time exposure verification code issued by the public health for an upload token. The token authorizes one /covid19/trace/report upload.
+// @Tags Covid19
+// @ID VerifyExposureCode
+// @Accept json
+// @Produce json
+// @Param data body verifyExposureCodeRequest true "body data"
+// @Success 200 {object} verifyExposureCodeResponse
+// @Security AppUserAuth
+// @Router /covid19/trace/verify [post]
+func (h ApisHandler) VerifyExposureCode(current model.User, w http.ResponseWriter, r *http.Request) {
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		log.Printf("Error on marshal verify exposure code - %s\n", err.Error())
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	var requestData verifyExposureCodeRequest
+	err = json.Unmarshal(data, &requestData)
+	if err != nil {
+		log.Printf("Error on unmarshal the verify exposure code request data - %s\n", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	//validate
+	validate := validator.New()
+	err = validate.Struct(requestData)
+	if err != nil {
+		log.Printf("Error on validating verify exposure code data - %s\n", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	item, token, err := h.app.Services.VerifyExposureCode(current, requestData.Code)
+	if err != nil {
+		log.Printf("Error on verifying an exposure code - %s", err.Error())
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	resp := verifyExposureCodeResponse{Token: token, TokenExpiresAt: item.TokenExpiresAt, ReportType: item.ReportType,
+		TestDate: item.TestDate, SymptomOnsetDate: item.SymptomOnsetDate}
+	data, err = json.Marshal(resp)
+	if err != nil {
+		log.Println("Error on marshal the verify exposure code response")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
 type getRosterByPhoneResponse struct {
 	UIN        string `json:"uin"`
 	FirstName  string `json:"first_name"`
@@ -2756,21 +3201,21 @@ func (h ApisHandler) GetRosterByPhone(appVersion *string, w http.ResponseWriter,
 
 	var response *getRosterByPhoneResponse
 	if roster != nil {
-		uin := roster["uin"]
-		firstName := roster["first_name"]
-		middleName := roster["middle_name"]
-		lastName := roster["last_name"]
-		address1 := roster["address1"]
-		address2 := roster["address2"]
-		address3 := roster["address3"]
-		badgeType := roster["badge_type"]
-		birthDate := roster["birth_date"]
-		city := roster["city"]
-		email := roster["email"]
-		gender := roster["gender"]
-		phone := roster["phone"]
-		state := roster["state"]
-		zipCode := roster["zip_code"]
+		uin := roster.UIN
+		firstName := roster.FirstName
+		middleName := roster.MiddleName
+		lastName := roster.LastName
+		address1 := roster.Address1
+		address2 := roster.Address2
+		address3 := roster.Address3
+		badgeType := roster.BadgeType
+		birthDate := roster.BirthDate
+		city := roster.City
+		email := roster.Email
+		gender := roster.Gender
+		phone := roster.Phone
+		state := roster.State
+		zipCode := roster.ZipCode
 
 		response = &getRosterByPhoneResponse{UIN: uin, FirstName: firstName, MiddleName: middleName, LastName: lastName,
 			Address1: address1, Address2: address2, Address3: address3, BadgeType: badgeType, BirthDate: birthDate, City: city,
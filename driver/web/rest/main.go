@@ -19,6 +19,9 @@ package rest
 
 import (
 	"health/core/model"
+	"health/utils"
+	"net/http"
+	"net/url"
 	"time"
 )
 
@@ -237,3 +240,114 @@ func convertFromDaysOfOperations(list []model.OperationDay) []locationOperationD
 	}
 	return doo
 }
+
+//the fields which the admin list apis allow for filtering and sorting
+var (
+	countiesQuerySpec = utils.QuerySpec{
+		Fields: map[string]utils.QueryField{
+			"id":             {Type: utils.QueryFieldString, Path: "_id"},
+			"name":           {Type: utils.QueryFieldString},
+			"state_province": {Type: utils.QueryFieldString},
+			"country":        {Type: utils.QueryFieldString},
+			"date_created":   {Type: utils.QueryFieldTime},
+		},
+		MaxLimit: 1000}
+
+	locationsQuerySpec = utils.QuerySpec{
+		Fields: map[string]utils.QueryField{
+			"id":           {Type: utils.QueryFieldString, Path: "_id"},
+			"name":         {Type: utils.QueryFieldString},
+			"city":         {Type: utils.QueryFieldString},
+			"state":        {Type: utils.QueryFieldString},
+			"zip":          {Type: utils.QueryFieldString},
+			"country":      {Type: utils.QueryFieldString},
+			"provider_id":  {Type: utils.QueryFieldString},
+			"county_id":    {Type: utils.QueryFieldString},
+			"date_created": {Type: utils.QueryFieldTime},
+		},
+		MaxLimit: 1000}
+
+	providersQuerySpec = utils.QuerySpec{
+		Fields: map[string]utils.QueryField{
+			"id":            {Type: utils.QueryFieldString, Path: "_id"},
+			"provider_name": {Type: utils.QueryFieldString},
+			"manual_test":   {Type: utils.QueryFieldBool},
+			"date_created":  {Type: utils.QueryFieldTime},
+		},
+		MaxLimit: 1000}
+
+	uinOverridesQuerySpec = utils.QuerySpec{
+		Fields: map[string]utils.QueryField{
+			"uin":        {Type: utils.QueryFieldString},
+			"exempt":     {Type: utils.QueryFieldBool},
+			"interval":   {Type: utils.QueryFieldInt},
+			"category":   {Type: utils.QueryFieldString},
+			"activation": {Type: utils.QueryFieldTime},
+			"expiration": {Type: utils.QueryFieldTime},
+		},
+		MaxLimit: 1000}
+
+	manualTestsQuerySpec = utils.QuerySpec{
+		Fields: map[string]utils.QueryField{
+			"id":           {Type: utils.QueryFieldString, Path: "_id"},
+			"location_id":  {Type: utils.QueryFieldString},
+			"status":       {Type: utils.QueryFieldString},
+			"date":         {Type: utils.QueryFieldTime},
+			"date_created": {Type: utils.QueryFieldTime},
+		},
+		DefaultSort: []utils.QuerySort{{Field: "date_created", Desc: true}},
+		MaxLimit:    1000}
+
+	rostersQuerySpec = utils.QuerySpec{
+		Fields: map[string]utils.QueryField{
+			"phone":       {Type: utils.QueryFieldString},
+			"uin":         {Type: utils.QueryFieldString},
+			"first_name":  {Type: utils.QueryFieldString},
+			"middle_name": {Type: utils.QueryFieldString},
+			"last_name":   {Type: utils.QueryFieldString},
+			"birth_date":  {Type: utils.QueryFieldString},
+			"gender":      {Type: utils.QueryFieldString},
+			"city":        {Type: utils.QueryFieldString},
+			"state":       {Type: utils.QueryFieldString},
+			"zip_code":    {Type: utils.QueryFieldString},
+			"email":       {Type: utils.QueryFieldString},
+			"badge_type":  {Type: utils.QueryFieldString},
+		},
+		DefaultSort:  []utils.QuerySort{{Field: "phone"}},
+		DefaultLimit: 20,
+		MaxLimit:     50}
+
+	auditQuerySpec = utils.QuerySpec{
+		Fields: map[string]utils.QueryField{
+			"user_identifier": {Type: utils.QueryFieldString},
+			"used_group":      {Type: utils.QueryFieldString},
+			"entity":          {Type: utils.QueryFieldString},
+			"entity_id":       {Type: utils.QueryFieldString},
+			"operation":       {Type: utils.QueryFieldString},
+			"client_data":     {Type: utils.QueryFieldString},
+			"created_at":      {Type: utils.QueryFieldTime},
+			"changed_field":   {Type: utils.QueryFieldString, Path: "changes.field"},
+		},
+		DefaultSort:  []utils.QuerySort{{Field: "created_at"}},
+		DefaultLimit: 1000,
+		MaxLimit:     10000}
+)
+
+//renameQueryParams gives the params with the old params names replaced by the query fields names
+func renameQueryParams(values url.Values, names map[string]string) url.Values {
+	result := url.Values{}
+	for key, value := range values {
+		if name, ok := names[key]; ok {
+			key = name
+		}
+		result[key] = append(result[key], value...)
+	}
+	return result
+}
+
+//setQueryCursor gives the next page cursor to the client if there is a next page
+func setQueryCursor(w http.ResponseWriter, cursor string) {
+	if len(cursor) > 0 {
+		w.Header().Set("ROKWIRE-CONTINUATION-TOKEN", cursor)
+	}
+}
@@ -0,0 +1,324 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+
+package web
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"health/utils"
+	"log"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"gopkg.in/ericchiang/go-oidc.v2"
+)
+
+const (
+	tokenTypeShibboleth = "shibboleth"
+	tokenTypePhone      = "phone"
+	tokenTypeAccess     = "access"
+)
+
+//errUpgradeRequired is given when a deprecated token type is used after its sunset date
+var errUpgradeRequired = errors.New("the token type is not supported anymore")
+
+//tokenValidator validates one type of the users tokens
+type tokenValidator interface {
+	//tokenType gives the type of the tokens the validator handles
+	tokenType() string
+	//matches checks if the token with the unverified claims is of the validator type
+	matches(claims jwt.MapClaims) bool
+	//validate validates the token and gives the user external identifier and the auth type
+	validate(token string, tokenSourceType string, csrfToken *string) (string, string, error)
+}
+
+type registeredTokenValidator struct {
+	validator  tokenValidator
+	deprecated bool
+
+	//the app versions range the token type is enabled for, nil for no bound
+	minAppVersion *string
+	maxAppVersion *string
+
+	accepted        int64
+	rejected        int64
+	disabled        int64
+	upgradeRequired int64
+	lastUsed        int64 //unix time
+}
+
+//enabledFor checks if the token type is enabled for the app version. The requests which do not send a version are not restricted
+func (rv *registeredTokenValidator) enabledFor(appVersion *string) bool {
+	if appVersion == nil {
+		return true
+	}
+	if rv.minAppVersion != nil && utils.IsVersionLess(*appVersion, *rv.minAppVersion) {
+		return false
+	}
+	if rv.maxAppVersion != nil && utils.IsVersionLess(*rv.maxAppVersion, *appVersion) {
+		return false
+	}
+	return true
+}
+
+type tokenTypeMetrics struct {
+	Type            string     `json:"type"`
+	Deprecated      bool       `json:"deprecated"`
+	MinAppVersion   *string    `json:"min_app_version"`
+	MaxAppVersion   *string    `json:"max_app_version"`
+	Accepted        int64      `json:"accepted"`
+	Rejected        int64      `json:"rejected"`
+	Disabled        int64      `json:"disabled"`
+	UpgradeRequired int64      `json:"upgrade_required"`
+	LastUsed        *time.Time `json:"last_used"`
+} // @name tokenTypeMetrics
+
+type tokenMetrics struct {
+	DeprecatedSunset *time.Time         `json:"deprecated_sunset"`
+	Types            []tokenTypeMetrics `json:"types"`
+} // @name tokenMetrics
+
+//tokenValidatorChain keeps the registered token validators in the order they are checked
+type tokenValidatorChain struct {
+	validators []*registeredTokenValidator
+
+	//the deprecated token types are rejected with upgrade required after this date, nil for no sunset
+	deprecatedSunset *time.Time
+}
+
+func (c *tokenValidatorChain) register(validator tokenValidator, deprecated bool) {
+	c.validators = append(c.validators, &registeredTokenValidator{validator: validator, deprecated: deprecated})
+}
+
+//find gives the registered validator for the token
+func (c *tokenValidatorChain) find(token string) (*registeredTokenValidator, error) {
+	parser := new(jwt.Parser)
+	claims := jwt.MapClaims{}
+	_, _, err := parser.ParseUnverified(token, claims)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, rv := range c.validators {
+		if rv.validator.matches(claims) {
+			return rv, nil
+		}
+	}
+	return nil, errors.New("not supported token type")
+}
+
+//validate validates the token with the validator of its type and records the usage
+func (c *tokenValidatorChain) validate(token string, tokenSourceType string, csrfToken *string, appVersion *string) (*registeredTokenValidator, string, string, error) {
+	rv, err := c.find(token)
+	if err != nil {
+		return nil, "", "", err
+	}
+	atomic.StoreInt64(&rv.lastUsed, time.Now().Unix())
+
+	if rv.deprecated && c.deprecatedSunset != nil && time.Now().After(*c.deprecatedSunset) {
+		atomic.AddInt64(&rv.upgradeRequired, 1)
+		return rv, "", "", errUpgradeRequired
+	}
+	if !rv.enabledFor(appVersion) {
+		atomic.AddInt64(&rv.disabled, 1)
+		return rv, "", "", fmt.Errorf("%s token type is not enabled for app version %s", rv.validator.tokenType(), *appVersion)
+	}
+
+	externalID, authType, err := rv.validator.validate(token, tokenSourceType, csrfToken)
+	if err != nil {
+		atomic.AddInt64(&rv.rejected, 1)
+		return rv, "", "", err
+	}
+	atomic.AddInt64(&rv.accepted, 1)
+	return rv, externalID, authType, nil
+}
+
+func (c *tokenValidatorChain) metrics() tokenMetrics {
+	types := make([]tokenTypeMetrics, len(c.validators))
+	for i, rv := range c.validators {
+		var lastUsed *time.Time
+		if value := atomic.LoadInt64(&rv.lastUsed); value > 0 {
+			t := time.Unix(value, 0).UTC()
+			lastUsed = &t
+		}
+		types[i] = tokenTypeMetrics{Type: rv.validator.tokenType(), Deprecated: rv.deprecated, MinAppVersion: rv.minAppVersion,
+			MaxAppVersion: rv.maxAppVersion, Accepted: atomic.LoadInt64(&rv.accepted), Rejected: atomic.LoadInt64(&rv.rejected),
+			Disabled: atomic.LoadInt64(&rv.disabled), UpgradeRequired: atomic.LoadInt64(&rv.upgradeRequired), LastUsed: lastUsed}
+	}
+	return tokenMetrics{DeprecatedSunset: c.deprecatedSunset, Types: types}
+}
+
+//applyAppVersions sets the app versions ranges from the "type:min-max,type:min-max" format, every bound is optional
+func (c *tokenValidatorChain) applyAppVersions(value string) error {
+	if len(value) == 0 {
+		return nil
+	}
+	for _, item := range strings.Split(value, ",") {
+		typeAndRange := strings.SplitN(strings.TrimSpace(item), ":", 2)
+		if len(typeAndRange) != 2 {
+			return fmt.Errorf("bad token type app versions - %s", item)
+		}
+		var rv *registeredTokenValidator
+		for _, current := range c.validators {
+			if current.validator.tokenType() == typeAndRange[0] {
+				rv = current
+			}
+		}
+		if rv == nil {
+			return fmt.Errorf("not supported token type - %s", typeAndRange[0])
+		}
+		bounds := strings.SplitN(typeAndRange[1], "-", 2)
+		if len(bounds) != 2 {
+			return fmt.Errorf("bad token type app versions range - %s", typeAndRange[1])
+		}
+		if len(bounds[0]) > 0 {
+			rv.minAppVersion = &bounds[0]
+		}
+		if len(bounds[1]) > 0 {
+			rv.maxAppVersion = &bounds[1]
+		}
+	}
+	return nil
+}
+
+//applyDeprecatedSunset sets the sunset date
+func (c *tokenValidatorChain) applyDeprecatedSunset(value string) error {
+	sunset, err := parseSunset(value)
+	if err != nil {
+		return fmt.Errorf("bad deprecated tokens sunset date - %s", value)
+	}
+	c.deprecatedSunset = sunset
+	return nil
+}
+
+//parseSunset parses a sunset date, both RFC3339 and 2006-01-02 formats are accepted. It gives nil for an empty value.
+func parseSunset(value string) (*time.Time, error) {
+	if len(value) == 0 {
+		return nil, nil
+	}
+	sunset, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		sunset, err = time.Parse("2006-01-02", value)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &sunset, nil
+}
+
+//shibbolethTokenValidator validates the Shibboleth ID tokens - deprecated, kept for back compatability
+type shibbolethTokenValidator struct {
+	verifier *oidc.IDTokenVerifier
+}
+
+func (v *shibbolethTokenValidator) tokenType() string {
+	return tokenTypeShibboleth
+}
+
+func (v *shibbolethTokenValidator) matches(claims jwt.MapClaims) bool {
+	_, ok := claims["uiucedu_uin"]
+	return ok
+}
+
+func (v *shibbolethTokenValidator) validate(token string, tokenSourceType string, csrfToken *string) (string, string, error) {
+	// Validate the token
+	idToken, err := v.verifier.Verify(context.Background(), token)
+	if err != nil {
+		log.Printf("error validating token - %s\n", err)
+		return "", "", err
+	}
+
+	// Get the user data from the token
+	var userData shData
+	if err := idToken.Claims(&userData); err != nil {
+		log.Printf("error getting user data from token - %s\n", err)
+		return "", "", err
+	}
+	//we must have UIuceduUIN
+	if userData.UIuceduUIN == nil {
+		log.Printf("missing uiuceuin data in the token - %s\n", token)
+		return "", "", errors.New("missing uiuceuin data in the token")
+	}
+	return *userData.UIuceduUIN, "shibboleth", nil
+}
+
+//phoneTokenValidator validates the phone tokens signed with the phone secret - deprecated, kept for back compatability
+type phoneTokenValidator struct {
+	secret string
+}
+
+func (v *phoneTokenValidator) tokenType() string {
+	return tokenTypePhone
+}
+
+func (v *phoneTokenValidator) matches(claims jwt.MapClaims) bool {
+	_, ok := claims["phoneNumber"]
+	return ok
+}
+
+func (v *phoneTokenValidator) validate(token string, tokenSourceType string, csrfToken *string) (string, string, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(token *jwt.Token) (interface{}, error) {
+		return []byte(v.secret), nil
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	phoneValue, ok := claims["phoneNumber"].(string)
+	if !ok {
+		return "", "", errors.New("there is no phoneNumber claim in the phone token")
+	}
+	return phoneValue, "phone", nil
+}
+
+//accessTokenValidator validates the auth service access tokens
+type accessTokenValidator struct {
+	auth *UserAuth
+}
+
+func (v *accessTokenValidator) tokenType() string {
+	return tokenTypeAccess
+}
+
+func (v *accessTokenValidator) matches(claims jwt.MapClaims) bool {
+	_, ok := claims["uid"]
+	return ok
+}
+
+func (v *accessTokenValidator) validate(token string, tokenSourceType string, csrfToken *string) (string, string, error) {
+	//mobile app sends just token, the browser sends token + csrf token
+	csrfCheck := tokenSourceType == "cookie"
+
+	tokenData, err := v.auth.processAccessToken(token, csrfCheck, csrfToken)
+	if err != nil {
+		return "", "", err
+	}
+
+	switch tokenData.Auth {
+	case "oidc":
+		return tokenData.UID, "shibboleth", nil
+	case "rokwire_phone":
+		return tokenData.UID, "phone", nil
+	default:
+		return "", "", errors.New("not supported token auth type")
+	}
+}
@@ -0,0 +1,160 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+
+package web
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+)
+
+//testTokenValidator accepts the tokens which "typ" claim is its type
+type testTokenValidator struct {
+	typ string
+}
+
+func (v testTokenValidator) tokenType() string {
+	return v.typ
+}
+
+func (v testTokenValidator) matches(claims jwt.MapClaims) bool {
+	return claims["typ"] == v.typ
+}
+
+func (v testTokenValidator) validate(token string, tokenSourceType string, csrfToken *string) (string, string, error) {
+	return "external-id", v.typ, nil
+}
+
+func testToken(t *testing.T, typ string) string {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"typ": typ}).SignedString([]byte("key"))
+	if err != nil {
+		t.Fatalf("cannot create a token - %s", err)
+	}
+	return token
+}
+
+func TestTokenValidatorChainSunset(t *testing.T) {
+	past := time.Now().Add(-time.Hour)
+	future := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name            string
+		sunset          *time.Time
+		tokenType       string
+		wantErr         error
+		upgradeRequired int64
+		accepted        int64
+	}{
+		{name: "deprecated without sunset", sunset: nil, tokenType: tokenTypeShibboleth, accepted: 1},
+		{name: "deprecated before sunset", sunset: &future, tokenType: tokenTypeShibboleth, accepted: 1},
+		{name: "deprecated after sunset", sunset: &past, tokenType: tokenTypeShibboleth, wantErr: errUpgradeRequired, upgradeRequired: 1},
+		{name: "current after sunset", sunset: &past, tokenType: tokenTypeAccess, accepted: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chain := tokenValidatorChain{deprecatedSunset: tt.sunset}
+			chain.register(testTokenValidator{typ: tokenTypeAccess}, false)
+			chain.register(testTokenValidator{typ: tokenTypeShibboleth}, true)
+
+			rv, externalID, _, err := chain.validate(testToken(t, tt.tokenType), "", nil, nil)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("validate() error = %v, expected %v", err, tt.wantErr)
+			}
+			if rv == nil || rv.validator.tokenType() != tt.tokenType {
+				t.Fatalf("validate() gives a wrong validator")
+			}
+			if tt.wantErr == nil && externalID != "external-id" {
+				t.Errorf("validate() external id = %s", externalID)
+			}
+			if rv.upgradeRequired != tt.upgradeRequired || rv.accepted != tt.accepted {
+				t.Errorf("validate() metrics - upgrade required %d, accepted %d", rv.upgradeRequired, rv.accepted)
+			}
+		})
+	}
+}
+
+func TestTokenValidatorChainAppVersions(t *testing.T) {
+	tests := []struct {
+		name       string
+		versions   string
+		appVersion *string
+		wantErr    bool
+	}{
+		{name: "no ranges", versions: "", appVersion: stringPointer("2.0")},
+		{name: "no app version", versions: "access:2.0-3.0", appVersion: nil},
+		{name: "in range", versions: "access:2.0-3.0", appVersion: stringPointer("2.5")},
+		{name: "below range", versions: "access:2.0-3.0", appVersion: stringPointer("1.9"), wantErr: true},
+		{name: "above range", versions: "access:2.0-3.0", appVersion: stringPointer("3.1"), wantErr: true},
+		{name: "open max", versions: "access:2.0-", appVersion: stringPointer("10.0")},
+		{name: "other type range", versions: "shibboleth:1.0-1.5", appVersion: stringPointer("2.0")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chain := tokenValidatorChain{}
+			chain.register(testTokenValidator{typ: tokenTypeAccess}, false)
+			chain.register(testTokenValidator{typ: tokenTypeShibboleth}, true)
+			err := chain.applyAppVersions(tt.versions)
+			if err != nil {
+				t.Fatalf("applyAppVersions() error = %v", err)
+			}
+
+			rv, _, _, err := chain.validate(testToken(t, tokenTypeAccess), "", nil, tt.appVersion)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr && rv.disabled != 1 {
+				t.Errorf("validate() disabled = %d", rv.disabled)
+			}
+		})
+	}
+}
+
+func TestParseSunset(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected *time.Time
+		wantErr  bool
+	}{
+		{name: "empty", value: ""},
+		{name: "date", value: "2021-06-30", expected: timePointer(time.Date(2021, 6, 30, 0, 0, 0, 0, time.UTC))},
+		{name: "date and time", value: "2021-06-30T12:30:00Z", expected: timePointer(time.Date(2021, 6, 30, 12, 30, 0, 0, time.UTC))},
+		{name: "bad value", value: "30/06/2021", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := parseSunset(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseSunset() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if (result == nil) != (tt.expected == nil) || (result != nil && !result.Equal(*tt.expected)) {
+				t.Errorf("parseSunset() = %v, expected %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func stringPointer(value string) *string {
+	return &value
+}
+
+func timePointer(value time.Time) *time.Time {
+	return &value
+}
@@ -20,7 +20,9 @@ package main
 import (
 	"health/core"
 	audit "health/driven/audit"
+	cache "health/driven/cache"
 	dataprovider "health/driven/dataprovider"
+	gaen "health/driven/gaen"
 	messaging "health/driven/messaging"
 	profilebb "health/driven/profilebb"
 	rokmetro "health/driven/rokmetro"
@@ -29,7 +31,11 @@ import (
 	driver "health/driver/web"
 	"log"
 	"os"
+	"os/signal"
+	"strconv"
 	"strings"
+	"syscall"
+	"time"
 )
 
 var (
@@ -55,11 +61,15 @@ func main() {
 	}
 
 	//audit adapter
-	auditAdapter := audit.NewAuditAdapter(mongoDBAuth, mongoDBName, mongoTimeout)
+	auditSigningKey := getEnvKey("HEALTH_AUDIT_SIGNING_KEY", false)
+	auditSpillDir := getEnvKey("HEALTH_AUDIT_SPILL_DIR", false)
+	auditStrict := getAuditStrict()
+	auditAdapter := audit.NewAuditAdapter(mongoDBAuth, mongoDBName, mongoTimeout, auditSigningKey, auditSpillDir, auditStrict)
 	err = auditAdapter.Start()
 	if err != nil {
 		log.Fatal("Cannot start the audit adapter - " + err.Error())
 	}
+	stopOnSignal(auditAdapter)
 
 	//data provider adapter
 	newsRSSURL := getEnvKey("HEALTH_NEWS_RSS_URL", true)
@@ -75,9 +85,8 @@ func main() {
 	to := getEmailsRecepients()
 	sender := sender.NewSenderAdapter(smtpHost, smtpPort, user, password, from, to)
 
-	firebaseAuth := getEnvKey("HEALTH_FIREBASE_AUTH", true)
-	firebaseProjectID := getEnvKey("HEALTH_FIREBASE_PROJECT_ID", true)
-	messaging := messaging.NewFirebaseAdapter(firebaseAuth, firebaseProjectID)
+	//messaging adapter
+	messaging := getMessagingAdapter()
 
 	//profile bb adapter
 	profileHost := getEnvKey("HEALTH_PROFILE_HOST", true)
@@ -89,8 +98,23 @@ func main() {
 	rokmetroGroupsAPIKey := getEnvKey("HEALTH_ROKMETRO_GROUPS_API_KEY", true)
 	rokmetroAdapter := rokmetro.NewRokmetroAdapter(rokmetroGroupsHost, rokmetroGroupsAPIKey)
 
+	//gaen adapter
+	gaenVerificationKey := getEnvKey("HEALTH_GAEN_VERIFICATION_KEY", false)
+	gaenIssuer := getEnvKey("HEALTH_GAEN_VERIFICATION_ISSUER", false)
+	gaenAudience := getEnvKey("HEALTH_GAEN_VERIFICATION_AUDIENCE", false)
+	gaenSigningKey := getEnvKey("HEALTH_GAEN_EXPORT_SIGNING_KEY", false)
+	gaenKeyID := getEnvKey("HEALTH_GAEN_EXPORT_KEY_ID", false)
+	gaenKeyVersion := getEnvKey("HEALTH_GAEN_EXPORT_KEY_VERSION", false)
+	gaenRegion := getEnvKey("HEALTH_GAEN_REGION", false)
+	gaenAdapter := gaen.NewGAENAdapter(gaenVerificationKey, gaenIssuer, gaenAudience, gaenSigningKey, gaenKeyID, gaenKeyVersion, gaenRegion)
+
+	//users cache adapter
+	usersCache := getUsersCacheAdapter()
+	usersCacheSize, usersCacheTTL := getUsersCacheLimits()
+
 	//application
-	application := core.NewApplication(Version, Build, dataProvider, sender, messaging, profileBBAdapter, rokmetroAdapter, storageAdapter, auditAdapter)
+	application := core.NewApplication(Version, Build, dataProvider, sender, messaging, profileBBAdapter, rokmetroAdapter, gaenAdapter, storageAdapter, auditAdapter,
+		usersCache, usersCacheSize, usersCacheTTL)
 	application.Start()
 
 	//web adapter
@@ -103,15 +127,119 @@ func main() {
 	adminWebAppClientID := getEnvKey("HEALTH_OIDC_ADMIN_WEB_CLIENT_ID", true)
 	phoneSecret := getEnvKey("HEALTH_PHONE_SECRET", true)
 	providersKeys := getHSAPIKeys()
-	authKeys := getEnvKey("HEALTH_AUTH_KEYS", true)
+	authKeys := getEnvKey("HEALTH_AUTH_KEYS", false)
+	authKeysURL := getEnvKey("HEALTH_AUTH_KEYS_URL", false)
 	authIssuer := getEnvKey("HEALTH_AUTH_ISSUER", true)
+	authAudience := getEnvKey("HEALTH_AUTH_AUDIENCE", false)
+	authClockSkew := getEnvKey("HEALTH_AUTH_CLOCK_SKEW", false)
+	tokensAppVersions := getEnvKey("HEALTH_TOKEN_TYPES_APP_VERSIONS", false)
+	deprecatedTokensSunset := getEnvKey("HEALTH_DEPRECATED_TOKENS_SUNSET", false)
+	providersSharedKeysSunset := getEnvKey("HEALTH_PROVIDERS_KEY_SUNSET", false)
 
 	webAdapter := driver.NewWebAdapter(host, application, apiKeys, oidcProvider, oidcAppClientID, adminAppClientID, adminWebAppClientID,
-		phoneSecret, authKeys, authIssuer, providersKeys, externalAPIKeys)
+		phoneSecret, authKeys, authKeysURL, authIssuer, authAudience, authClockSkew, tokensAppVersions, deprecatedTokensSunset,
+		providersKeys, providersSharedKeysSunset, externalAPIKeys)
 
 	webAdapter.Start()
 }
 
+//stopOnSignal writes the queued audit items before exit
+func stopOnSignal(auditAdapter *audit.Adapter) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		received := <-signals
+		log.Printf("Stopping on %s", received)
+		auditAdapter.Stop()
+		os.Exit(0)
+	}()
+}
+
+func getAuditStrict() bool {
+	strictValue := getEnvKey("HEALTH_AUDIT_STRICT", false)
+	if len(strictValue) == 0 {
+		return false
+	}
+	strict, err := strconv.ParseBool(strictValue)
+	if err != nil {
+		log.Fatal("Not valid audit strict value - " + strictValue)
+	}
+	return strict
+}
+
+func getMessagingAdapter() core.Messaging {
+	//firebase is the default one
+	messagingType := getEnvKey("HEALTH_MESSAGING_TYPE", false)
+	switch messagingType {
+	case "", "firebase":
+		firebaseAuth := getEnvKey("HEALTH_FIREBASE_AUTH", true)
+		firebaseProjectID := getEnvKey("HEALTH_FIREBASE_PROJECT_ID", true)
+		return messaging.NewFirebaseAdapter(firebaseAuth, firebaseProjectID)
+	case "apns":
+		apnsKey := getEnvKey("HEALTH_APNS_AUTH_KEY", true)
+		apnsKeyID := getEnvKey("HEALTH_APNS_KEY_ID", true)
+		apnsTeamID := getEnvKey("HEALTH_APNS_TEAM_ID", true)
+		apnsTopic := getEnvKey("HEALTH_APNS_TOPIC", true)
+		apnsProduction := getEnvKey("HEALTH_APNS_PRODUCTION", false) == "true"
+		return messaging.NewAPNsAdapter(apnsKey, apnsKeyID, apnsTeamID, apnsTopic, apnsProduction)
+	case "webhook":
+		webhookURL := getEnvKey("HEALTH_MESSAGING_WEBHOOK_URL", true)
+		webhookAPIKey := getEnvKey("HEALTH_MESSAGING_WEBHOOK_API_KEY", false)
+		return messaging.NewWebhookAdapter(webhookURL, webhookAPIKey)
+	case "sink":
+		sinkFile := getEnvKey("HEALTH_MESSAGING_SINK_FILE", false)
+		sinkAdapter := messaging.NewSinkAdapter(sinkFile)
+		sinkAddress := getEnvKey("HEALTH_MESSAGING_SINK_ADDRESS", false)
+		if len(sinkAddress) > 0 {
+			sinkAdapter.StartServer(sinkAddress)
+		}
+		return sinkAdapter
+	default:
+		log.Fatal("Not supported messaging type - " + messagingType)
+		return nil
+	}
+}
+
+func getUsersCacheAdapter() core.UsersCache {
+	//the users are cached only in the process memory by default
+	usersCacheType := getEnvKey("HEALTH_USERS_CACHE_TYPE", false)
+	switch usersCacheType {
+	case "":
+		return nil
+	case "redis":
+		redisURL := getEnvKey("HEALTH_USERS_CACHE_REDIS_URL", true)
+		return cache.NewRedisAdapter(redisURL)
+	case "memory":
+		return cache.NewMemoryAdapter()
+	default:
+		log.Fatal("Not supported users cache type - " + usersCacheType)
+		return nil
+	}
+}
+
+func getUsersCacheLimits() (int, time.Duration) {
+	size := 10000
+	sizeValue := getEnvKey("HEALTH_USERS_CACHE_SIZE", false)
+	if len(sizeValue) > 0 {
+		value, err := strconv.Atoi(sizeValue)
+		if err != nil || value <= 0 {
+			log.Fatal("Not valid users cache size - " + sizeValue)
+		}
+		size = value
+	}
+
+	ttl := 5 * time.Minute
+	ttlValue := getEnvKey("HEALTH_USERS_CACHE_TTL", false)
+	if len(ttlValue) > 0 {
+		value, err := strconv.Atoi(ttlValue)
+		if err != nil || value <= 0 {
+			log.Fatal("Not valid users cache ttl - " + ttlValue)
+		}
+		ttl = time.Duration(value) * time.Second
+	}
+	return size, ttl
+}
+
 func getEmailsRecepients() []string {
 	//get from the environment
 	emails, exist := os.LookupEnv("HEALTH_EMAIL_TO")
@@ -159,15 +287,14 @@ func getExternalAPIKeys() []string {
 }
 
 func getHSAPIKeys() []string {
-	//get from the environment
-	providersAPIKeys := getEnvKey("HEALTH_PROVIDERS_KEY", true)
+	//get from the environment, the shared keys are optional as the providers could have their own credentials
+	providersAPIKeys := getEnvKey("HEALTH_PROVIDERS_KEY", false)
+	if len(providersAPIKeys) == 0 {
+		return []string{}
+	}
 
 	//it is comma separated format
 	providersAPIKeysList := strings.Split(providersAPIKeys, ",")
-	if len(providersAPIKeysList) <= 0 {
-		log.Fatal("For some reasons the providers apis keys list is empty")
-	}
-
 	return providersAPIKeysList
 }
 
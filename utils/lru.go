@@ -0,0 +1,104 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+
+package utils
+
+import (
+	"container/list"
+	"sync"
+)
+
+//LRUCache is a thread safe cache which keeps up to a fixed number of items and evicts the least recently used ones
+type LRUCache struct {
+	capacity int
+	items    map[string]*list.Element
+	order    *list.List //the front is the most recently used item
+	lock     *sync.Mutex
+}
+
+type lruEntry struct {
+	key   string
+	value interface{}
+}
+
+//Get gives the cached value for the key
+func (c *LRUCache) Get(key string) (interface{}, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	element, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(element)
+	return element.Value.(*lruEntry).value, true
+}
+
+//Put caches the value for the key, the least recently used item is evicted if the cache is full
+func (c *LRUCache) Put(key string, value interface{}) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if element, ok := c.items[key]; ok {
+		element.Value.(*lruEntry).value = value
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.items[key] = c.order.PushFront(&lruEntry{key: key, value: value})
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*lruEntry).key)
+	}
+}
+
+//Remove removes the key from the cache
+func (c *LRUCache) Remove(key string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if element, ok := c.items[key]; ok {
+		c.order.Remove(element)
+		delete(c.items, key)
+	}
+}
+
+//Purge removes all items from the cache
+func (c *LRUCache) Purge() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.items = map[string]*list.Element{}
+	c.order.Init()
+}
+
+//Len gives the number of the cached items
+func (c *LRUCache) Len() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.order.Len()
+}
+
+//NewLRUCache creates new LRU cache instance
+func NewLRUCache(capacity int) *LRUCache {
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &LRUCache{capacity: capacity, items: map[string]*list.Element{}, order: list.New(), lock: &sync.Mutex{}}
+}
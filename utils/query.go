@@ -0,0 +1,232 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+
+package utils
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	//QueryOperatorEq is for equal values, it is used when no operator is provided - field=value or field=eq:value
+	QueryOperatorEq string = "eq"
+	//QueryOperatorNe is for not equal values - field=ne:value
+	QueryOperatorNe string = "ne"
+	//QueryOperatorIn is for values from a list - field=in:value1,value2
+	QueryOperatorIn string = "in"
+	//QueryOperatorRange is for values between two values including them, any of them could be empty - field=range:from,to
+	QueryOperatorRange string = "range"
+	//QueryOperatorPrefix is for string values starting with a value - field=prefix:value
+	QueryOperatorPrefix string = "prefix"
+	//QueryOperatorExists is for set or not set values - field=exists:true
+	QueryOperatorExists string = "exists"
+
+	//QueryFieldString is a string field
+	QueryFieldString string = "string"
+	//QueryFieldInt is an integer field
+	QueryFieldInt string = "int"
+	//QueryFieldBool is a boolean field
+	QueryFieldBool string = "bool"
+	//QueryFieldTime is a RFC3339 time field
+	QueryFieldTime string = "time"
+
+	//QueryParamSort is the sort param - sort=field1,-field2 where - is for descending order
+	QueryParamSort string = "sort"
+	//QueryParamLimit is the page size param
+	QueryParamLimit string = "limit"
+	//QueryParamCursor is the param for the cursor given by the previous page
+	QueryParamCursor string = "cursor"
+)
+
+//QuerySpec represents what an entity query allows
+type QuerySpec struct {
+	Fields       map[string]QueryField //the fields which can be used for filtering and sorting by their params names
+	DefaultSort  []QuerySort
+	DefaultLimit int //0 - no limit
+	MaxLimit     int
+}
+
+//QueryField represents a field which can be used in a query
+type QueryField struct {
+	Type string
+	Path string //the stored field path if it differs from the param name
+}
+
+func (f QueryField) path(name string) string {
+	if len(f.Path) > 0 {
+		return f.Path
+	}
+	return name
+}
+
+//QueryCondition represents a query condition. The range condition has two values where any of them could be nil.
+type QueryCondition struct {
+	Field    string
+	Operator string
+	Values   []interface{}
+}
+
+//QuerySort represents a query sort field
+type QuerySort struct {
+	Field string
+	Desc  bool
+}
+
+//Query represents a typed entities query with sort and cursor pagination
+type Query struct {
+	Conditions []QueryCondition
+	Sort       []QuerySort
+	Limit      int
+	Cursor     *string
+}
+
+//AddCondition adds a condition to the query
+func (q *Query) AddCondition(field string, operator string, values ...interface{}) {
+	q.Conditions = append(q.Conditions, QueryCondition{Field: field, Operator: operator, Values: values})
+}
+
+//ParseQuery constructs a query from the request params. All the params which are not sort, limit or cursor are conditions
+//and they must be allowed by the spec.
+func ParseQuery(values url.Values, spec QuerySpec) (*Query, error) {
+	query := Query{Sort: spec.DefaultSort, Limit: spec.DefaultLimit}
+	for key, value := range values {
+		if len(value) == 0 || len(value[0]) == 0 {
+			continue
+		}
+
+		switch key {
+		case QueryParamSort:
+			sort, err := parseQuerySort(value[0], spec)
+			if err != nil {
+				return nil, err
+			}
+			query.Sort = sort
+		case QueryParamLimit:
+			limit, err := strconv.Atoi(value[0])
+			if err != nil || limit < 1 || (spec.MaxLimit > 0 && limit > spec.MaxLimit) {
+				return nil, fmt.Errorf("the limit must be between 1 and %d", spec.MaxLimit)
+			}
+			query.Limit = limit
+		case QueryParamCursor:
+			cursor := value[0]
+			query.Cursor = &cursor
+		default:
+			for _, item := range value {
+				condition, err := parseQueryCondition(key, item, spec)
+				if err != nil {
+					return nil, err
+				}
+				query.Conditions = append(query.Conditions, *condition)
+			}
+		}
+	}
+	return &query, nil
+}
+
+func parseQuerySort(value string, spec QuerySpec) ([]QuerySort, error) {
+	var result []QuerySort
+	for _, item := range strings.Split(value, ",") {
+		name := strings.TrimPrefix(item, "-")
+		field, ok := spec.Fields[name]
+		if !ok {
+			return nil, fmt.Errorf("not allowed sort field %s", name)
+		}
+		result = append(result, QuerySort{Field: field.path(name), Desc: strings.HasPrefix(item, "-")})
+	}
+	return result, nil
+}
+
+func parseQueryCondition(field string, value string, spec QuerySpec) (*QueryCondition, error) {
+	queryField, ok := spec.Fields[field]
+	if !ok {
+		return nil, fmt.Errorf("not allowed filter field %s", field)
+	}
+	fieldType := queryField.Type
+
+	//the operator is optional, the values without a known operator are for equality
+	operator := QueryOperatorEq
+	if index := strings.Index(value, ":"); index > 0 {
+		switch value[:index] {
+		case QueryOperatorEq, QueryOperatorNe, QueryOperatorIn, QueryOperatorRange, QueryOperatorPrefix, QueryOperatorExists:
+			operator = value[:index]
+			value = value[index+1:]
+		}
+	}
+
+	condition := QueryCondition{Field: queryField.path(field), Operator: operator}
+	switch operator {
+	case QueryOperatorEq, QueryOperatorNe:
+		typed, err := parseQueryValue(value, fieldType)
+		if err != nil {
+			return nil, fmt.Errorf("bad %s value - %s", field, err)
+		}
+		condition.Values = []interface{}{typed}
+	case QueryOperatorIn:
+		for _, item := range strings.Split(value, ",") {
+			typed, err := parseQueryValue(item, fieldType)
+			if err != nil {
+				return nil, fmt.Errorf("bad %s value - %s", field, err)
+			}
+			condition.Values = append(condition.Values, typed)
+		}
+	case QueryOperatorRange:
+		bounds := strings.Split(value, ",")
+		if len(bounds) != 2 || (len(bounds[0]) == 0 && len(bounds[1]) == 0) {
+			return nil, fmt.Errorf("bad %s range - from,to is expected", field)
+		}
+		condition.Values = make([]interface{}, 2)
+		for i, bound := range bounds {
+			if len(bound) == 0 {
+				continue
+			}
+			typed, err := parseQueryValue(bound, fieldType)
+			if err != nil {
+				return nil, fmt.Errorf("bad %s value - %s", field, err)
+			}
+			condition.Values[i] = typed
+		}
+	case QueryOperatorPrefix:
+		if fieldType != QueryFieldString {
+			return nil, fmt.Errorf("prefix is supported only for string fields - %s", field)
+		}
+		condition.Values = []interface{}{value}
+	case QueryOperatorExists:
+		exists, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("bad %s exists value - %s", field, value)
+		}
+		condition.Values = []interface{}{exists}
+	}
+	return &condition, nil
+}
+
+func parseQueryValue(value string, fieldType string) (interface{}, error) {
+	switch fieldType {
+	case QueryFieldInt:
+		return strconv.ParseInt(value, 10, 64)
+	case QueryFieldBool:
+		return strconv.ParseBool(value)
+	case QueryFieldTime:
+		return time.Parse(time.RFC3339, value)
+	default:
+		return value, nil
+	}
+}
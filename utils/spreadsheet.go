@@ -0,0 +1,234 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+
+package utils
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+const (
+	//the last xlsx column is XFD
+	xlsxMaxColumns = 16384
+	//the cells of all the rows including the empty ones before the last row cell
+	xlsxMaxCells = 5000000
+	//the uncompressed size of a xlsx part, it prevents the zip bombs
+	xlsxMaxPartSize = 100 << 20 //100 MB
+)
+
+//ReadCSV reads all the records from a csv file. The records may have different number of fields.
+func ReadCSV(r io.Reader) ([][]string, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	return reader.ReadAll()
+}
+
+//ReadXLSX reads all the rows from the first worksheet of a xlsx file. The empty cells are given as empty strings.
+func ReadXLSX(r io.ReaderAt, size int64) ([][]string, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, errors.New("the file is not a xlsx file")
+	}
+	files := map[string]*zip.File{}
+	for _, file := range archive.File {
+		files[file.Name] = file
+	}
+
+	sheetPath, err := xlsxFirstSheetPath(files)
+	if err != nil {
+		return nil, err
+	}
+	sharedStrings, err := xlsxSharedStrings(files)
+	if err != nil {
+		return nil, err
+	}
+
+	var sheet struct {
+		Rows []struct {
+			Cells []struct {
+				Ref       string   `xml:"r,attr"`
+				Type      string   `xml:"t,attr"`
+				Value     string   `xml:"v"`
+				InlineStr xlsxText `xml:"is"`
+			} `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+	err = xlsxDecode(files, sheetPath, &sheet)
+	if err != nil {
+		return nil, err
+	}
+
+	cellsCount := 0
+	rows := make([][]string, len(sheet.Rows))
+	for i, row := range sheet.Rows {
+		var values []string
+		for _, cell := range row.Cells {
+			column := xlsxColumnIndex(cell.Ref)
+			if column < 0 {
+				column = len(values)
+			}
+			if column >= xlsxMaxColumns {
+				return nil, fmt.Errorf("bad cell reference %s", cell.Ref)
+			}
+			if column >= len(values) {
+				cellsCount += column + 1 - len(values)
+				if cellsCount > xlsxMaxCells {
+					return nil, errors.New("the xlsx file has too many cells")
+				}
+			}
+			for len(values) <= column {
+				values = append(values, "")
+			}
+
+			switch cell.Type {
+			case "s":
+				index, err := strconv.Atoi(cell.Value)
+				if err != nil || index < 0 || index >= len(sharedStrings) {
+					return nil, fmt.Errorf("bad shared string in cell %s", cell.Ref)
+				}
+				values[column] = sharedStrings[index]
+			case "inlineStr":
+				values[column] = cell.InlineStr.String()
+			default:
+				values[column] = cell.Value
+			}
+		}
+		rows[i] = values
+	}
+	return rows, nil
+}
+
+//xlsxText is a string item which could be plain or rich text
+type xlsxText struct {
+	Text string `xml:"t"`
+	Runs []struct {
+		Text string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xlsxText) String() string {
+	if len(t.Runs) == 0 {
+		return t.Text
+	}
+	var builder strings.Builder
+	for _, run := range t.Runs {
+		builder.WriteString(run.Text)
+	}
+	return builder.String()
+}
+
+func xlsxDecode(files map[string]*zip.File, name string, v interface{}) error {
+	file, ok := files[name]
+	if !ok {
+		return fmt.Errorf("%s is missing in the xlsx file", name)
+	}
+	if file.UncompressedSize64 > xlsxMaxPartSize {
+		return fmt.Errorf("%s is too large", name)
+	}
+	reader, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	//the size in the header could be wrong, so the decompressed data is limited too
+	limited := &io.LimitedReader{R: reader, N: xlsxMaxPartSize + 1}
+	err = xml.NewDecoder(limited).Decode(v)
+	if limited.N <= 0 {
+		return fmt.Errorf("%s is too large", name)
+	}
+	return err
+}
+
+func xlsxFirstSheetPath(files map[string]*zip.File) (string, error) {
+	var workbook struct {
+		Sheets []struct {
+			RelationID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	err := xlsxDecode(files, "xl/workbook.xml", &workbook)
+	if err != nil {
+		return "", err
+	}
+	if len(workbook.Sheets) == 0 {
+		return "", errors.New("there is no worksheet in the xlsx file")
+	}
+
+	var relationships struct {
+		Items []struct {
+			ID     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}
+	err = xlsxDecode(files, "xl/_rels/workbook.xml.rels", &relationships)
+	if err != nil {
+		return "", err
+	}
+	for _, item := range relationships.Items {
+		if item.ID == workbook.Sheets[0].RelationID {
+			if strings.HasPrefix(item.Target, "/") {
+				return strings.TrimPrefix(item.Target, "/"), nil
+			}
+			return path.Join("xl", item.Target), nil
+		}
+	}
+	return "", errors.New("the first worksheet is missing in the xlsx file")
+}
+
+func xlsxSharedStrings(files map[string]*zip.File) ([]string, error) {
+	if _, ok := files["xl/sharedStrings.xml"]; !ok {
+		//there are no string cells
+		return nil, nil
+	}
+
+	var sst struct {
+		Items []xlsxText `xml:"si"`
+	}
+	err := xlsxDecode(files, "xl/sharedStrings.xml", &sst)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]string, len(sst.Items))
+	for i, item := range sst.Items {
+		result[i] = item.String()
+	}
+	return result, nil
+}
+
+//xlsxColumnIndex gives the zero based column index for a cell reference like AB12. It gives xlsxMaxColumns for the references after XFD.
+func xlsxColumnIndex(ref string) int {
+	index := 0
+	for _, c := range ref {
+		if c < 'A' || c > 'Z' {
+			break
+		}
+		index = index*26 + int(c-'A'+1)
+		if index > xlsxMaxColumns {
+			return xlsxMaxColumns
+		}
+	}
+	return index - 1
+}
@@ -0,0 +1,193 @@
+/*
+ *   Copyright (c) 2020 Board of Trustees of the University of Illinois.
+ *   All rights reserved.
+
+ *   Licensed under the Apache License, Version 2.0 (the "License");
+ *   you may not use this file except in compliance with the License.
+ *   You may obtain a copy of the License at
+
+ *   http://www.apache.org/licenses/LICENSE-2.0
+
+ *   Unless required by applicable law or agreed to in writing, software
+ *   distributed under the License is distributed on an "AS IS" BASIS,
+ *   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ *   See the License for the specific language governing permissions and
+ *   limitations under the License.
+ */
+
+package utils
+
+import (
+	"archive/zip"
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const (
+	testWorkbook = `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+		`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+		`<sheets><sheet name="Sheet1" sheetId="1" r:id="rId1"/></sheets></workbook>`
+	testWorkbookRels = `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+		`</Relationships>`
+	testSharedStrings = `<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+		`<si><t>uin</t></si><si><t>phone</t></si><si><r><t>John </t></r><r><t>Doe</t></r></si></sst>`
+)
+
+func testSheet(rows string) string {
+	return `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>` + rows + `</sheetData></worksheet>`
+}
+
+//testXLSX creates a xlsx file with the provided parts, the parts with empty content are not added
+func testXLSX(t *testing.T, parts map[string]string) []byte {
+	var buffer bytes.Buffer
+	writer := zip.NewWriter(&buffer)
+	for name, content := range parts {
+		if len(content) == 0 {
+			continue
+		}
+		file, err := writer.Create(name)
+		if err != nil {
+			t.Fatalf("cannot create %s - %s", name, err)
+		}
+		_, err = file.Write([]byte(content))
+		if err != nil {
+			t.Fatalf("cannot write %s - %s", name, err)
+		}
+	}
+	err := writer.Close()
+	if err != nil {
+		t.Fatalf("cannot close the xlsx file - %s", err)
+	}
+	return buffer.Bytes()
+}
+
+func TestReadXLSX(t *testing.T) {
+	tests := []struct {
+		name          string
+		sheet         string
+		sharedStrings string
+		workbook      string
+		expected      [][]string
+		wantErr       string
+	}{
+		{
+			name: "shared, inline and number cells",
+			sheet: testSheet(`<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c><c r="C1" t="s"><v>2</v></c></row>` +
+				`<row r="2"><c r="A2"><v>123456789</v></c><c r="B2" t="inlineStr"><is><t>+12175551234</t></is></c></row>`),
+			sharedStrings: testSharedStrings,
+			expected:      [][]string{{"uin", "phone", "John Doe"}, {"123456789", "+12175551234"}},
+		},
+		{
+			name:     "empty cells before the referenced cell",
+			sheet:    testSheet(`<row r="1"><c r="C1"><v>3</v></c><c r="E1"><v>5</v></c></row>`),
+			expected: [][]string{{"", "", "3", "", "5"}},
+		},
+		{
+			name:     "cells without reference",
+			sheet:    testSheet(`<row><c><v>1</v></c><c><v>2</v></c></row>`),
+			expected: [][]string{{"1", "2"}},
+		},
+		{
+			name:     "last column",
+			sheet:    testSheet(`<row r="1"><c r="XFD1"><v>1</v></c></row>`),
+			expected: [][]string{append(make([]string, xlsxMaxColumns-1), "1")},
+		},
+		{
+			name:    "column after the last one",
+			sheet:   testSheet(`<row r="1"><c r="XFE1"><v>1</v></c></row>`),
+			wantErr: "bad cell reference",
+		},
+		{
+			name:    "very long column reference",
+			sheet:   testSheet(`<row r="1"><c r="ZZZZZZZZZZZZZZZZ1"><v>1</v></c></row>`),
+			wantErr: "bad cell reference",
+		},
+		{
+			name:    "too many cells",
+			sheet:   testSheet(strings.Repeat(`<row><c r="XFD1"><v>1</v></c></row>`, xlsxMaxCells/xlsxMaxColumns+1)),
+			wantErr: "too many cells",
+		},
+		{
+			name:          "bad shared string index",
+			sheet:         testSheet(`<row r="1"><c r="A1" t="s"><v>3</v></c></row>`),
+			sharedStrings: testSharedStrings,
+			wantErr:       "bad shared string",
+		},
+		{
+			name:     "no worksheet",
+			workbook: `<workbook><sheets></sheets></workbook>`,
+			wantErr:  "no worksheet",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workbook := tt.workbook
+			if len(workbook) == 0 {
+				workbook = testWorkbook
+			}
+			data := testXLSX(t, map[string]string{"xl/workbook.xml": workbook, "xl/_rels/workbook.xml.rels": testWorkbookRels,
+				"xl/worksheets/sheet1.xml": tt.sheet, "xl/sharedStrings.xml": tt.sharedStrings})
+
+			rows, err := ReadXLSX(bytes.NewReader(data), int64(len(data)))
+			if len(tt.wantErr) > 0 {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ReadXLSX() error = %v, expected %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ReadXLSX() error = %v", err)
+			}
+			if !reflect.DeepEqual(rows, tt.expected) {
+				t.Errorf("ReadXLSX() = %v, expected %v", rows, tt.expected)
+			}
+		})
+	}
+}
+
+func TestReadXLSXNotZip(t *testing.T) {
+	data := []byte("uin,phone\n123456789,+12175551234\n")
+	_, err := ReadXLSX(bytes.NewReader(data), int64(len(data)))
+	if err == nil {
+		t.Fatalf("ReadXLSX() gives no error for a csv file")
+	}
+}
+
+func TestReadXLSXLargePart(t *testing.T) {
+	//a highly compressible part larger than the limit
+	sheet := testSheet(strings.Repeat(" ", xlsxMaxPartSize))
+	data := testXLSX(t, map[string]string{"xl/workbook.xml": testWorkbook, "xl/_rels/workbook.xml.rels": testWorkbookRels,
+		"xl/worksheets/sheet1.xml": sheet})
+
+	_, err := ReadXLSX(bytes.NewReader(data), int64(len(data)))
+	if err == nil || !strings.Contains(err.Error(), "too large") {
+		t.Fatalf("ReadXLSX() error = %v, expected too large", err)
+	}
+}
+
+func TestXLSXColumnIndex(t *testing.T) {
+	tests := []struct {
+		ref      string
+		expected int
+	}{
+		{ref: "A1", expected: 0},
+		{ref: "Z10", expected: 25},
+		{ref: "AA3", expected: 26},
+		{ref: "AB12", expected: 27},
+		{ref: "XFD1", expected: xlsxMaxColumns - 1},
+		{ref: "XFE1", expected: xlsxMaxColumns},
+		{ref: "ZZZZZZZZZZZZZZZZ1", expected: xlsxMaxColumns},
+		{ref: "", expected: -1},
+		{ref: "12", expected: -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			if result := xlsxColumnIndex(tt.ref); result != tt.expected {
+				t.Errorf("xlsxColumnIndex(%s) = %d, expected %d", tt.ref, result, tt.expected)
+			}
+		})
+	}
+}
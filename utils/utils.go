@@ -195,6 +195,16 @@ func EqualPointers(a, b *[]string) bool {
 	return Equal(*a, *b)
 }
 
+//Contains checks if the slice contains the value
+func Contains(list []string, value string) bool {
+	for _, current := range list {
+		if current == value {
+			return true
+		}
+	}
+	return false
+}
+
 //GetInt gives the value which this pointer points. Gives 0 if the pointer is nil
 func GetInt(v *int) int {
 	if v == nil {